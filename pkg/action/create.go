@@ -30,6 +30,8 @@ import (
 	"cuelang.org/go/cue/cuecontext"
 	cuejson "cuelang.org/go/encoding/json"
 	"github.com/leaanthony/debme"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,8 +44,10 @@ import (
 	"k8s.io/client-go/kubernetes"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/yaml"
 
 	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
 )
 
 var (
@@ -101,9 +105,37 @@ type CreateOptions struct {
 	// Quiet minimize unnecessary output
 	Quiet bool
 
+	// outputFlag is the output flag added by AddDryRunFlags, the structured result is
+	// printed instead of CustomOutPut if it is specified explicitly
+	outputFlag *pflag.Flag
+
 	genericiooptions.IOStreams
 }
 
+// CreateResult is the structured result of a create-based command, automation can chain
+// the commands with it instead of parsing the human-readable messages.
+type CreateResult struct {
+	Kind           string `json:"kind"`
+	Name           string `json:"name"`
+	Namespace      string `json:"namespace"`
+	OpsRequestName string `json:"opsRequestName,omitempty"`
+	Status         string `json:"status"`
+}
+
+// AddDryRunFlags adds the dry-run and output flags, with which the create-based commands
+// can preview or emit the manifest that would be submitted instead of creating it. If the
+// output flag is specified without dry-run, a CreateResult is printed after creating.
+func (o *CreateOptions) AddDryRunFlags(cmd *cobra.Command, persistent bool) {
+	fs := cmd.Flags()
+	if persistent {
+		fs = cmd.PersistentFlags()
+	}
+	fs.StringVar(&o.DryRun, "dry-run", "none", `Must be "client", or "server". If with client strategy, only print the object that would be sent, and no data is actually sent. If with server strategy, submit the server-side request, but no data is persistent.`)
+	fs.Lookup("dry-run").NoOptDefVal = "unchanged"
+	printer.AddOutputFlagForCreate(cmd, &o.Format, persistent)
+	o.outputFlag = fs.Lookup("output")
+}
+
 func (o *CreateOptions) Complete() error {
 	var err error
 	if o.Namespace, _, err = o.Factory.ToRawKubeConfigLoader().Namespace(); err != nil {
@@ -146,7 +178,7 @@ func (o *CreateOptions) Complete() error {
 
 // Run execute command. the options of parameter contain the command flags and args.
 func (o *CreateOptions) Run() error {
-	resObj, err := o.buildResourceObj()
+	resObj, err := o.BuildResourceObj()
 	if err != nil {
 		return err
 	}
@@ -169,6 +201,12 @@ func (o *CreateOptions) Run() error {
 		return err
 	}
 
+	// write the manifest to the export directory instead of creating it, the commands with
+	// dependencies should reject the export directory as the dependencies are not exported
+	if ExportEnabled() && dryRunStrategy == DryRunNone {
+		return ExportObject(resObj, o.Out)
+	}
+
 	if dryRunStrategy != DryRunClient {
 		createOptions := metav1.CreateOptions{}
 
@@ -202,7 +240,11 @@ func (o *CreateOptions) Run() error {
 			if o.Quiet {
 				return nil
 			}
-			if o.CustomOutPut != nil {
+			if o.outputFlag != nil && o.outputFlag.Changed {
+				return o.printResult(resObj)
+			}
+			// the custom outputs are the hints of the next steps, which are decorative
+			if o.CustomOutPut != nil && !printer.Quiet() {
 				o.CustomOutPut(o)
 			} else {
 				fmt.Fprintf(o.Out, "%s %s created\n", resObj.GetKind(), resObj.GetName())
@@ -217,6 +259,38 @@ func (o *CreateOptions) Run() error {
 	return p.PrintObj(resObj, o.Out)
 }
 
+// printResult prints the structured result of the created resource in the output format
+func (o *CreateOptions) printResult(obj *unstructured.Unstructured) error {
+	result := CreateResult{
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Status:    "Created",
+	}
+	if obj.GetKind() == types.KindOps {
+		result.OpsRequestName = obj.GetName()
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+		result.Status = phase
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if o.Format == printer.JSON {
+		data, err = json.MarshalIndent(result, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(result)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
 func (o *CreateOptions) CleanUp() error {
 	if o.CreateDependencies == nil {
 		return nil
@@ -228,7 +302,8 @@ func (o *CreateOptions) CleanUp() error {
 	return nil
 }
 
-func (o *CreateOptions) buildResourceObj() (*unstructured.Unstructured, error) {
+// BuildResourceObj renders the resource object from the cue template and the options without creating it
+func (o *CreateOptions) BuildResourceObj() (*unstructured.Unstructured, error) {
 	var (
 		cueValue    cue.Value
 		err         error
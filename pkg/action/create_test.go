@@ -20,11 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action
 
 import (
+	"bytes"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
@@ -141,5 +143,47 @@ var _ = Describe("Create", func() {
 				}
 			}
 		})
+
+		It("test dry-run flags", func() {
+			cmd := &cobra.Command{Use: "test"}
+			options.AddDryRunFlags(cmd, false)
+			Expect(cmd.Flags().Lookup("dry-run").NoOptDefVal).Should(Equal("unchanged"))
+			Expect(cmd.Flags().Parse([]string{"--dry-run", "-o", "json"})).Should(Succeed())
+			Expect(options.DryRun).Should(Equal("unchanged"))
+			Expect(options.Format).Should(Equal(printer.JSON))
+
+			Expect(options.Complete()).Should(Succeed())
+			s, err := options.GetDryRunStrategy()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s).Should(Equal(DryRunClient))
+			Expect(options.Run()).Should(Succeed())
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring(`"kind": "Cluster"`))
+		})
+
+		It("test structured result", func() {
+			options.CustomOutPut = func(o *CreateOptions) {
+				fmt.Fprintf(o.Out, "%s created successfully\n", o.Name)
+			}
+			cmd := &cobra.Command{Use: "test"}
+			options.AddDryRunFlags(cmd, false)
+			Expect(options.Complete()).Should(Succeed())
+
+			By("custom output is used if the output flag is not specified")
+			Expect(options.Run()).Should(Succeed())
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(Equal("test created successfully\n"))
+
+			By("structured result is printed if the output flag is specified")
+			streams.Out.(*bytes.Buffer).Reset()
+			Expect(cmd.Flags().Parse([]string{"-o", "json"})).Should(Succeed())
+			options.Name = "test-json"
+			Expect(options.Run()).Should(Succeed())
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(Equal(fmt.Sprintf(`{
+  "kind": "Cluster",
+  "name": "test-json",
+  "namespace": "%s",
+  "status": "Created"
+}
+`, testing.Namespace)))
+		})
 	})
 })
@@ -20,13 +20,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/resource"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -38,6 +43,15 @@ import (
 
 type DeleteHook func(options *DeleteOptions, object runtime.Object) error
 
+const (
+	CascadeBackground = "background"
+	CascadeForeground = "foreground"
+	CascadeOrphan     = "orphan"
+)
+
+// deleteWaitInterval is the interval to check whether the deleted objects are removed
+var deleteWaitInterval = 2 * time.Second
+
 type DeleteOptions struct {
 	Factory       cmdutil.Factory
 	Namespace     string
@@ -48,6 +62,13 @@ type DeleteOptions struct {
 	Now           bool
 	AutoApprove   bool
 
+	// Wait waits until the deleted objects are actually removed or Timeout is reached
+	Wait    bool
+	Timeout time.Duration
+	// Cascade is the deletion cascading strategy of the dependents, supported values
+	// are background, foreground and orphan, empty means using the server default.
+	Cascade string
+
 	// Names are the resource names
 	Names []string
 	// ConfirmedNames used to double-check the resource names to delete, sometimes Names are used to build
@@ -67,6 +88,7 @@ func NewDeleteOptions(f cmdutil.Factory, streams genericiooptions.IOStreams, gvr
 		Factory:   f,
 		IOStreams: streams,
 		GVR:       gvr,
+		Timeout:   300 * time.Second,
 	}
 }
 
@@ -84,6 +106,10 @@ func (o *DeleteOptions) Run() error {
 }
 
 func (o *DeleteOptions) validate() error {
+	if err := CheckExportUnsupported("delete"); err != nil {
+		return err
+	}
+
 	switch {
 	case o.GracePeriod == 0 && o.Force:
 		fmt.Fprintf(o.ErrOut, "warning: Immediate deletion does not wait for confirmation that the running resource has been terminated.\n")
@@ -104,6 +130,12 @@ func (o *DeleteOptions) validate() error {
 		o.GracePeriod = 0
 	}
 
+	switch o.Cascade {
+	case "", CascadeBackground, CascadeForeground, CascadeOrphan:
+	default:
+		return fmt.Errorf(`invalid cascade value (%s). Must be "background", "foreground", or "orphan"`, o.Cascade)
+	}
+
 	if len(o.Names) > 0 && len(o.LabelSelector) > 0 {
 		return fmt.Errorf("name cannot be provided when a selector is specified")
 	}
@@ -169,6 +201,10 @@ func (o *DeleteOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.Now, "now", false, "If true, resources are signaled for immediate shutdown (same as --grace-period=1).")
 	cmd.Flags().IntVar(&o.GracePeriod, "grace-period", -1, "Period of time in seconds given to the resource to terminate gracefully. Ignored if negative. Set to 1 for immediate shutdown. Can only be set to 0 when --force is true (force deletion).")
 	cmd.Flags().BoolVar(&o.AutoApprove, "auto-approve", false, "Skip interactive approval before deleting")
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "If true, wait for resources to be gone before returning, the pending finalizers will be reported while waiting.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "Time to wait for resources to be gone when --wait is true, such as --timeout=5m")
+	cmd.Flags().StringVar(&o.Cascade, "cascade", CascadeBackground, `Must be "background", "orphan", or "foreground". Selects the deletion cascading strategy for the dependents.`)
+	cmd.Flags().Lookup("cascade").NoOptDefVal = CascadeBackground
 }
 
 func (o *DeleteOptions) deleteResult(r *resource.Result) error {
@@ -185,6 +221,10 @@ func (o *DeleteOptions) deleteResult(r *resource.Result) error {
 		if o.GracePeriod >= 0 {
 			options = metav1.NewDeleteOptions(int64(o.GracePeriod))
 		}
+		if o.Cascade != "" {
+			policy := cascadeToPropagationPolicy(o.Cascade)
+			options.PropagationPolicy = &policy
+		}
 		if err = o.preDeleteResource(info); err != nil {
 			return err
 		}
@@ -202,11 +242,70 @@ func (o *DeleteOptions) deleteResult(r *resource.Result) error {
 	}
 	if found == 0 {
 		fmt.Fprintf(o.Out, "No %s found\n", o.GVR.Resource)
+		return nil
 	}
 
+	if o.Wait {
+		return o.waitForDeletion(deleteInfos)
+	}
 	return nil
 }
 
+// waitForDeletion waits until all the deleted objects are removed, and reports the
+// finalizers that are still pending when they are changed.
+func (o *DeleteOptions) waitForDeletion(infos []*resource.Info) error {
+	for _, info := range infos {
+		var lastFinalizers string
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		err := wait.PollUntilContextTimeout(context.Background(), deleteWaitInterval, o.Timeout, true,
+			func(_ context.Context) (bool, error) {
+				obj, err := helper.Get(info.Namespace, info.Name)
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				if err != nil {
+					return false, err
+				}
+				accessor, err := meta.Accessor(obj)
+				if err != nil {
+					return false, err
+				}
+				// the object is removed and recreated with the same name
+				if info.Object != nil {
+					if old, err := meta.Accessor(info.Object); err == nil && old.GetUID() != "" && old.GetUID() != accessor.GetUID() {
+						return true, nil
+					}
+				}
+				if finalizers := strings.Join(accessor.GetFinalizers(), ", "); finalizers != lastFinalizers {
+					lastFinalizers = finalizers
+					fmt.Fprintf(o.Out, "Waiting for %s %s to be deleted, pending finalizers: [%s]\n",
+						info.Mapping.GroupVersionKind.Kind, info.Name, finalizers)
+				}
+				return false, nil
+			})
+		if err != nil {
+			if wait.Interrupted(err) {
+				return fmt.Errorf("timed out waiting for %s %s to be deleted, pending finalizers: [%s]",
+					info.Mapping.GroupVersionKind.Kind, info.Name, lastFinalizers)
+			}
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s %s is gone\n", info.Mapping.GroupVersionKind.Kind, info.Name)
+	}
+	return nil
+}
+
+func cascadeToPropagationPolicy(cascade string) metav1.DeletionPropagation {
+	switch cascade {
+	case CascadeForeground:
+		return metav1.DeletePropagationForeground
+	case CascadeOrphan:
+		return metav1.DeletePropagationOrphan
+	default:
+		return metav1.DeletePropagationBackground
+	}
+}
+
 func (o *DeleteOptions) deleteResource(info *resource.Info, deleteOptions *metav1.DeleteOptions) (runtime.Object, error) {
 	response, err := resource.
 		NewHelper(info.Client, info.Mapping).
@@ -21,12 +21,17 @@ package action
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -200,4 +205,65 @@ var _ = Describe("Delete", func() {
 		_, _ = in.Write([]byte(clusterName + "\n"))
 		Expect(cmd.RunE(cmd, []string{clusterName})).Should(HaveOccurred())
 	})
+
+	It("delete with cascade and wait", func() {
+		interval := deleteWaitInterval
+		defer func() { deleteWaitInterval = interval }()
+		deleteWaitInterval = 10 * time.Millisecond
+		codec := scheme.Codecs.LegacyCodec(scheme.Scheme.PrioritizedVersionsAllGroups()...)
+		cluster := testing.FakeCluster(clusterName, namespace)
+		cluster.Finalizers = []string{"cluster.kubeblocks.io/finalizer"}
+		getCount := 0
+		removed := true
+		var propagationPolicy metav1.DeletionPropagation
+		tf.UnstructuredClient = &clientfake.RESTClient{
+			GroupVersion:         schema.GroupVersion{Group: types.AppsAPIGroup, Version: types.AppsAPIVersion},
+			NegotiatedSerializer: resource.UnstructuredPlusDefaultContentConfig().NegotiatedSerializer,
+			Client: clientfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodDelete {
+					options := &metav1.DeleteOptions{}
+					body, _ := io.ReadAll(req.Body)
+					_ = json.Unmarshal(body, options)
+					if options.PropagationPolicy != nil {
+						propagationPolicy = *options.PropagationPolicy
+					}
+				}
+				if req.Method == http.MethodGet {
+					getCount++
+					if removed && getCount > 1 {
+						status := apierrors.NewNotFound(schema.GroupResource{Group: types.AppsAPIGroup, Resource: "clusters"}, clusterName).ErrStatus
+						return &http.Response{StatusCode: http.StatusNotFound, Header: cmdtesting.DefaultHeader(), Body: cmdtesting.ObjBody(codec, &status)}, nil
+					}
+				}
+				return &http.Response{StatusCode: http.StatusOK, Header: cmdtesting.DefaultHeader(), Body: cmdtesting.ObjBody(codec, cluster)}, nil
+			}),
+		}
+		cmd := &cobra.Command{
+			Use: "test-delete",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				o.Names = args
+				return o.Run()
+			},
+		}
+		o.AddFlags(cmd)
+		o.AutoApprove = true
+
+		By("invalid cascade")
+		Expect(cmd.Flags().Set("cascade", "invalid")).Should(Succeed())
+		Expect(cmd.RunE(cmd, []string{clusterName})).Should(MatchError(ContainSubstring("invalid cascade value")))
+
+		By("wait until the cluster is gone")
+		out := streams.Out.(*bytes.Buffer)
+		Expect(cmd.Flags().Set("cascade", CascadeForeground)).Should(Succeed())
+		Expect(cmd.Flags().Set("wait", "true")).Should(Succeed())
+		Expect(cmd.RunE(cmd, []string{clusterName})).Should(Succeed())
+		Expect(propagationPolicy).Should(Equal(metav1.DeletePropagationForeground))
+		Expect(out.String()).Should(ContainSubstring("pending finalizers: [cluster.kubeblocks.io/finalizer]"))
+		Expect(out.String()).Should(ContainSubstring("is gone"))
+
+		By("timed out waiting for the cluster")
+		removed = false
+		Expect(cmd.Flags().Set("timeout", "50ms")).Should(Succeed())
+		Expect(cmd.RunE(cmd, []string{clusterName})).Should(MatchError(ContainSubstring("timed out waiting for")))
+	})
 })
@@ -20,7 +20,9 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
@@ -32,6 +34,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 	cmdexec "k8s.io/kubectl/pkg/cmd/exec"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/cmd/util/podcmd"
@@ -53,6 +56,16 @@ type ExecOptions struct {
 	Command []string
 }
 
+// ExecResult is the structured result of the command executed in scripted mode
+type ExecResult struct {
+	Pod       string   `json:"pod"`
+	Container string   `json:"container"`
+	Command   []string `json:"command"`
+	Stdout    string   `json:"stdout"`
+	Stderr    string   `json:"stderr"`
+	ExitCode  int      `json:"exitCode"`
+}
+
 func NewExecOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *ExecOptions {
 	return &ExecOptions{
 		Factory: f,
@@ -128,6 +141,33 @@ func (o *ExecOptions) Run() error {
 	return o.RunWithRedirect(o.Out, o.ErrOut)
 }
 
+// RunCaptured executes the command in scripted mode without TTY and stdin, the stdout and
+// stderr of the remote command are captured into the result instead of being streamed to
+// the terminal. A non-zero exit code of the remote command is reported by the result rather
+// than the error, so the caller can output the result in a structured format.
+func (o *ExecOptions) RunCaptured() (*ExecResult, error) {
+	var stdout, stderr bytes.Buffer
+	o.TTY = false
+	o.Stdin = false
+	err := o.RunWithRedirect(&stdout, &stderr)
+	result := &ExecResult{
+		Container: o.ContainerName,
+		Command:   o.Command,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+	}
+	if o.Pod != nil {
+		result.Pod = o.Pod.Name
+	}
+
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		result.ExitCode = exitErr.ExitStatus()
+		return result, nil
+	}
+	return result, err
+}
+
 func (o *ExecOptions) RunWithRedirect(outWriter io.Writer, errWriter io.Writer) error {
 	if err := o.validate(); err != nil {
 		return err
@@ -166,7 +206,11 @@ func (o *ExecOptions) RunWithRedirect(outWriter io.Writer, errWriter io.Writer)
 			TTY:       t.Raw,
 		}, scheme.ParameterCodec)
 
-		return o.Executor.Execute("POST", req.URL(), o.Config, o.In, outWriter, errWriter, t.Raw, sizeQueue)
+		var in io.Reader
+		if o.Stdin {
+			in = o.In
+		}
+		return o.Executor.Execute("POST", req.URL(), o.Config, in, outWriter, errWriter, t.Raw, sizeQueue)
 	}
 
 	if err := t.Safe(fn); err != nil {
@@ -22,7 +22,9 @@ package action
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -33,6 +35,8 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 )
 
@@ -77,8 +81,53 @@ var _ = Describe("Exec", func() {
 		testOptions.PodName = ""
 		Expect(testOptions.ExecOptions.validate()).Should(MatchError("failed to get the pod to execute"))
 	})
+
+	It("run in scripted mode", func() {
+		executor := &fakeRemoteExecutor{stdout: "foo\n", stderr: "bar\n"}
+		o := NewExecOptions(nil, genericiooptions.NewTestIOStreamsDiscard())
+		o.Executor = executor
+		o.Config = &restclient.Config{APIPath: "/api", ContentConfig: restclient.ContentConfig{NegotiatedSerializer: scheme.Codecs, GroupVersion: &schema.GroupVersion{Version: "v1"}}}
+		o.Pod = execPod()
+		o.Command = []string{"echo", "foo"}
+
+		By("captures the output")
+		result, err := o.RunCaptured()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(o.TTY).Should(BeFalse())
+		Expect(executor.stdin).Should(BeNil())
+		Expect(result.Pod).Should(Equal("foo"))
+		Expect(result.Container).Should(Equal("bar"))
+		Expect(result.Stdout).Should(Equal("foo\n"))
+		Expect(result.Stderr).Should(Equal("bar\n"))
+		Expect(result.ExitCode).Should(Equal(0))
+
+		By("reports the exit code")
+		executor.err = utilexec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 2"), Code: 2}
+		result, err = o.RunCaptured()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.ExitCode).Should(Equal(2))
+
+		By("returns other errors")
+		executor.err = fmt.Errorf("connection refused")
+		_, err = o.RunCaptured()
+		Expect(err).Should(MatchError("connection refused"))
+	})
 })
 
+type fakeRemoteExecutor struct {
+	stdout string
+	stderr string
+	stdin  io.Reader
+	err    error
+}
+
+func (f *fakeRemoteExecutor) Execute(_ string, _ *url.URL, _ *restclient.Config, stdin io.Reader, stdout, stderr io.Writer, _ bool, _ remotecommand.TerminalSizeQueue) error {
+	f.stdin = stdin
+	_, _ = io.WriteString(stdout, f.stdout)
+	_, _ = io.WriteString(stderr, f.stderr)
+	return f.err
+}
+
 type testExecOptions struct {
 	name string
 	*ExecOptions
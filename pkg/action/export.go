@@ -0,0 +1,136 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/yaml"
+)
+
+const kustomizationFile = "kustomization.yaml"
+
+// exportDir is the directory to export the manifests to, the commands write the manifests
+// they would apply into it instead of submitting them to the API server if it's set.
+var exportDir string
+
+// SetExportDir sets the directory to export the manifests to
+func SetExportDir(dir string) {
+	exportDir = dir
+}
+
+// ExportEnabled returns true if the manifests should be exported instead of applied
+func ExportEnabled() bool {
+	return exportDir != ""
+}
+
+// CheckExportUnsupported returns an error if the manifests should be exported, it's called by the
+// commands changing the resources without manifests to export, such as delete.
+func CheckExportUnsupported(operation string) error {
+	if ExportEnabled() {
+		return fmt.Errorf("--export-to-dir is not supported by %s which changes the resources on the server directly", operation)
+	}
+	return nil
+}
+
+// kustomization is the subset of the kustomize Kustomization used by the exported directory
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// ExportObject writes the object to <dir>/<namespace>/<kind>-<name>.yaml and adds it to the
+// kustomization files, so the directory can be applied by "kubectl apply -k" or synced by the
+// GitOps tools. The server-populated fields are removed and the generated name is resolved.
+func ExportObject(obj *unstructured.Unstructured, out io.Writer) error {
+	obj = obj.DeepCopy()
+	if obj.GetName() == "" && obj.GetGenerateName() != "" {
+		obj.SetName(obj.GetGenerateName() + utilrand.String(5))
+		obj.SetGenerateName("")
+	}
+	if obj.GetName() == "" {
+		return fmt.Errorf("failed to export %s without name", obj.GetKind())
+	}
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "cluster-scoped"
+	}
+	dir := filepath.Join(exportDir, namespace)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+	fileName := fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())
+	if err = os.WriteFile(filepath.Join(dir, fileName), data, 0644); err != nil {
+		return err
+	}
+	if err = addKustomizationResource(dir, fileName); err != nil {
+		return err
+	}
+	if err = addKustomizationResource(exportDir, namespace); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s %s exported to %s\n", obj.GetKind(), obj.GetName(), filepath.Join(dir, fileName))
+	return nil
+}
+
+// addKustomizationResource adds the resource to the kustomization file in the directory, the
+// kustomization file is created if it does not exist.
+func addKustomizationResource(dir string, resource string) error {
+	k := &kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+	}
+	path := filepath.Join(dir, kustomizationFile)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err = yaml.Unmarshal(data, k); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+	for _, r := range k.Resources {
+		if r == resource {
+			return nil
+		}
+	}
+	k.Resources = append(k.Resources, resource)
+	if data, err = yaml.Marshal(k); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package action
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("Export", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "kbcli-export-")
+		Expect(err).Should(Succeed())
+		SetExportDir(dir)
+	})
+
+	AfterEach(func() {
+		SetExportDir("")
+		Expect(os.RemoveAll(dir)).Should(Succeed())
+	})
+
+	readKustomization := func(path string) *kustomization {
+		data, err := os.ReadFile(path)
+		Expect(err).Should(Succeed())
+		k := &kustomization{}
+		Expect(yaml.Unmarshal(data, k)).Should(Succeed())
+		return k
+	}
+
+	It("export objects", func() {
+		Expect(ExportEnabled()).Should(BeTrue())
+		out := &bytes.Buffer{}
+
+		cluster := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps.kubeblocks.io/v1alpha1",
+			"kind":       "Cluster",
+			"metadata": map[string]interface{}{
+				"name":            testing.ClusterName,
+				"namespace":       testing.Namespace,
+				"resourceVersion": "1",
+				"uid":             "uid",
+			},
+			"spec":   map[string]interface{}{"clusterDefinitionRef": "apecloud-mysql"},
+			"status": map[string]interface{}{"phase": "Running"},
+		}}
+		Expect(ExportObject(cluster, out)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("exported to"))
+
+		data, err := os.ReadFile(filepath.Join(dir, testing.Namespace, "cluster-"+testing.ClusterName+".yaml"))
+		Expect(err).Should(Succeed())
+		Expect(string(data)).ShouldNot(ContainSubstring("resourceVersion"))
+		Expect(string(data)).ShouldNot(ContainSubstring("status"))
+		Expect(string(data)).Should(ContainSubstring("clusterDefinitionRef: apecloud-mysql"))
+
+		ops := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps.kubeblocks.io/v1alpha1",
+			"kind":       "OpsRequest",
+			"metadata": map[string]interface{}{
+				"generateName": "test-expose-",
+				"namespace":    testing.Namespace,
+			},
+		}}
+		Expect(ExportObject(ops, out)).Should(Succeed())
+		// export the cluster again should not duplicate the resource
+		Expect(ExportObject(cluster, out)).Should(Succeed())
+
+		k := readKustomization(filepath.Join(dir, testing.Namespace, kustomizationFile))
+		Expect(k.Kind).Should(Equal("Kustomization"))
+		Expect(k.Resources).Should(HaveLen(2))
+		Expect(k.Resources[0]).Should(Equal("cluster-" + testing.ClusterName + ".yaml"))
+		Expect(k.Resources[1]).Should(HavePrefix("opsrequest-test-expose-"))
+		Expect(readKustomization(filepath.Join(dir, kustomizationFile)).Resources).Should(Equal([]string{testing.Namespace}))
+
+		Expect(ExportObject(&unstructured.Unstructured{Object: map[string]interface{}{"kind": "Cluster"}}, out)).Should(HaveOccurred())
+	})
+
+	It("reject the operations can not be exported", func() {
+		Expect(CheckExportUnsupported("delete")).Should(MatchError(ContainSubstring("delete")))
+		Expect((&DeleteOptions{Names: []string{"test"}}).validate()).Should(HaveOccurred())
+
+		SetExportDir("")
+		Expect(CheckExportUnsupported("delete")).Should(Succeed())
+	})
+})
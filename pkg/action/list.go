@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,18 +28,22 @@ import (
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	cmdget "k8s.io/kubectl/pkg/cmd/get"
@@ -66,6 +71,15 @@ type ListOptions struct {
 	// only return the result to caller.
 	Print  bool
 	SortBy string
+
+	// Watch watches the changes of the listed objects after the list is printed,
+	// it takes effect only if Print is true.
+	Watch bool
+	// ChunkSize is the page size of the list request, 0 disables the pagination.
+	ChunkSize int64
+	// TableOptions customizes the table output, the commands with custom printers should
+	// add its flags and apply it to their table printers.
+	TableOptions printer.TableOptions
 	genericiooptions.IOStreams
 }
 
@@ -77,6 +91,7 @@ func NewListOptions(f cmdutil.Factory, streams genericiooptions.IOStreams,
 		GVR:       gvr,
 		Print:     true,
 		SortBy:    ".metadata.name",
+		ChunkSize: cmdutil.DefaultChunkSize,
 	}
 }
 
@@ -85,8 +100,11 @@ func (o *ListOptions) AddFlags(cmd *cobra.Command, isClusterScope ...bool) {
 		cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
 	}
 	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Matching objects must satisfy all of the specified label constraints.")
+	cmd.Flags().StringVar(&o.FieldSelector, "field-selector", o.FieldSelector, "Selector (field query) to filter on, supports '=', '==', and '!='.(e.g. --field-selector key1=value1,key2=value2). The server only supports a limited number of field queries per type.")
 	cmd.Flags().BoolVar(&o.ShowLabels, "show-labels", false, "When printing, show all labels as the last column (default hide labels column)")
-	// Todo: --sortBy supports custom field sorting, now `list` is to sort using the `.metadata.name` field in default
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After listing the requested object(s), watch for changes.")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", o.ChunkSize, "Return large lists in chunks rather than all at once. Pass 0 to disable.")
+	o.TableOptions.AddSortFlag(cmd)
 	printer.AddOutputFlag(cmd, &o.Format)
 }
 
@@ -113,6 +131,7 @@ func (o *ListOptions) Complete() error {
 			p = printers.NewTablePrinter(printers.PrintOptions{
 				Kind:          kind,
 				Wide:          false,
+				NoHeaders:     o.TableOptions.NoHeaders,
 				WithNamespace: o.AllNamespaces,
 				ShowLabels:    o.ShowLabels,
 			})
@@ -120,9 +139,16 @@ func (o *ListOptions) Complete() error {
 			p = printers.NewTablePrinter(printers.PrintOptions{
 				Kind:          kind,
 				Wide:          true,
+				NoHeaders:     o.TableOptions.NoHeaders,
 				WithNamespace: o.AllNamespaces,
 				ShowLabels:    o.ShowLabels,
 			})
+		case printer.CSV, printer.Markdown:
+			p = &printer.ResourcePrinter{
+				Format:        o.Format,
+				NoHeaders:     o.TableOptions.NoHeaders,
+				WithNamespace: o.AllNamespaces,
+			}
 		default:
 			return nil, genericclioptions.NoCompatiblePrinterError{AllowedFormats: printer.Formats()}
 		}
@@ -132,8 +158,13 @@ func (o *ListOptions) Complete() error {
 			return nil, err
 		}
 
-		if o.Format.IsHumanReadable() {
-			p = &cmdget.SortingPrinter{Delegate: p, SortField: o.SortBy}
+		if o.Format.IsTabular() {
+			// sort by the columns if specified, otherwise sort by the default field
+			if len(o.TableOptions.SortBy) > 0 {
+				p = &printer.TableSorter{Delegate: p, SortBy: o.TableOptions.SortBy}
+			} else {
+				p = &cmdget.SortingPrinter{Delegate: p, SortField: o.SortBy}
+			}
 			p = &cmdget.TablePrinter{Delegate: p}
 		}
 		return p.PrintObj, nil
@@ -147,6 +178,15 @@ func (o *ListOptions) Run() (*resource.Result, error) {
 		return nil, err
 	}
 
+	// get the resource version before listing, so no change will be missed when watching
+	var resourceVersion string
+	if o.Watch && o.Print {
+		var err error
+		if resourceVersion, err = o.latestResourceVersion(); err != nil {
+			return nil, err
+		}
+	}
+
 	r := o.Factory.NewBuilder().
 		Unstructured().
 		NamespaceParam(o.Namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
@@ -156,6 +196,7 @@ func (o *ListOptions) Run() (*resource.Result, error) {
 		ContinueOnError().
 		Latest().
 		Flatten().
+		RequestChunksOf(o.ChunkSize).
 		TransformRequests(o.transformRequests).
 		Do()
 
@@ -165,15 +206,135 @@ func (o *ListOptions) Run() (*resource.Result, error) {
 
 	// if Print is true, use default printer to print the result, otherwise, only return the result,
 	// the caller needs to implement its own printer function to output the result.
-	if o.Print {
-		return r, o.printResult(r)
-	} else {
+	if !o.Print {
 		return r, nil
 	}
+	if err := o.printResult(r); err != nil || !o.Watch {
+		return r, err
+	}
+	return r, o.watch(resourceVersion)
+}
+
+// resourceInterface returns the dynamic resource interface of the listed resource, the
+// namespace is ignored if the resource is cluster scoped or all namespaces are listed.
+func (o *ListOptions) resourceInterface() (dynamic.ResourceInterface, error) {
+	client, err := o.Factory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := o.Factory.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := mapper.KindFor(o.GVR)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot || o.AllNamespaces {
+		return client.Resource(o.GVR), nil
+	}
+	return client.Resource(o.GVR).Namespace(o.Namespace), nil
+}
+
+func (o *ListOptions) listOptions() metav1.ListOptions {
+	fieldSelector := o.FieldSelector
+	if len(o.Names) == 1 {
+		nameSelector := fields.OneTermEqualSelector("metadata.name", o.Names[0]).String()
+		if fieldSelector == "" {
+			fieldSelector = nameSelector
+		} else {
+			fieldSelector = fieldSelector + "," + nameSelector
+		}
+	}
+	return metav1.ListOptions{
+		LabelSelector: o.LabelSelector,
+		FieldSelector: fieldSelector,
+	}
+}
+
+func (o *ListOptions) latestResourceVersion() (string, error) {
+	ri, err := o.resourceInterface()
+	if err != nil {
+		return "", err
+	}
+	listOpts := o.listOptions()
+	listOpts.Limit = 1
+	list, err := ri.List(context.TODO(), listOpts)
+	if err != nil {
+		return "", err
+	}
+	return list.GetResourceVersion(), nil
+}
+
+// watch watches the changes of the listed objects from the resource version until
+// the watch is closed by the server or the command is interrupted.
+func (o *ListOptions) watch(resourceVersion string) error {
+	ri, err := o.resourceInterface()
+	if err != nil {
+		return err
+	}
+	listOpts := o.listOptions()
+	listOpts.ResourceVersion = resourceVersion
+	w, err := ri.Watch(context.TODO(), listOpts)
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	return o.printWatchEvents(w)
+}
+
+// printWatchEvents prints the objects received from the watcher, human-readable formats
+// print a row for each event, and JSON or YAML formats print the whole object.
+func (o *ListOptions) printWatchEvents(w watch.Interface) error {
+	var (
+		p     printers.ResourcePrinterFunc
+		names = sets.NewString(o.Names...)
+		tw    = printers.GetNewTabWriter(o.Out)
+	)
+	if !o.Format.IsHumanReadable() {
+		var err error
+		if p, err = o.ToPrinter(nil, false); err != nil {
+			return err
+		}
+	} else if o.AllNamespaces {
+		fmt.Fprintln(tw, "EVENT\tNAMESPACE\tNAME\tCREATED-TIME")
+	} else {
+		fmt.Fprintln(tw, "EVENT\tNAME\tCREATED-TIME")
+	}
+
+	for event := range w.ResultChan() {
+		if event.Type == watch.Error {
+			return apierrors.FromObject(event.Object)
+		}
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok || (names.Len() > 1 && !names.Has(obj.GetName())) {
+			continue
+		}
+		if p != nil {
+			if err := p.PrintObj(obj, o.Out); err != nil {
+				return err
+			}
+			continue
+		}
+		createdTime := obj.GetCreationTimestamp()
+		if o.AllNamespaces {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", event.Type, obj.GetNamespace(), obj.GetName(), util.TimeFormat(&createdTime))
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", event.Type, obj.GetName(), util.TimeFormat(&createdTime))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
 }
 
 func (o *ListOptions) transformRequests(req *rest.Request) {
-	if !o.Format.IsHumanReadable() || !o.Print {
+	if !o.Format.IsTabular() || !o.Print {
 		return
 	}
 
@@ -188,7 +349,7 @@ func (o *ListOptions) transformRequests(req *rest.Request) {
 }
 
 func (o *ListOptions) printResult(r *resource.Result) error {
-	if !o.Format.IsHumanReadable() {
+	if !o.Format.IsTabular() {
 		return o.printGeneric(r)
 	}
 
@@ -27,8 +27,10 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -36,6 +38,7 @@ import (
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
+	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
 )
@@ -118,6 +121,28 @@ bar    <unknown>
 			Expect(buf.String()).To(Equal(expected))
 		})
 
+		It("With -o csv flag", func() {
+			expected := `NAMESPACE,NAME
+test,foo
+test,bar
+`
+			_ = cmd.Flags().Set("all-namespaces", "true")
+			_ = cmd.Flags().Set("output", "csv")
+			cmd.Run(cmd, []string{})
+			Expect(buf.String()).To(Equal(expected))
+		})
+
+		It("With -o markdown flag", func() {
+			expected := `| NAME |
+| --- |
+| foo |
+| bar |
+`
+			_ = cmd.Flags().Set("output", "markdown")
+			cmd.Run(cmd, []string{})
+			Expect(buf.String()).To(Equal(expected))
+		})
+
 		It("With -o yaml flag", func() {
 			expected := `apiVersion: v1
 items:
@@ -224,5 +249,66 @@ metadata:
 			Expect(buf.String()).To(Equal(""))
 			Expect(errbuf.String()).To(Equal("No pods found in test namespace.\n"))
 		})
+
+		It("With --field-selector and --chunk-size flags", func() {
+			o := NewListOptions(nil, streams, types.PodGVR())
+			c := &cobra.Command{Use: "ls-test"}
+			o.AddFlags(c)
+			Expect(c.Flags().Parse([]string{"--field-selector", "status.phase=Running", "--chunk-size", "10", "-w"})).Should(Succeed())
+			Expect(o.FieldSelector).Should(Equal("status.phase=Running"))
+			Expect(o.ChunkSize).Should(Equal(int64(10)))
+			Expect(o.Watch).Should(BeTrue())
+
+			o.Names = []string{"foo"}
+			Expect(o.listOptions().FieldSelector).Should(Equal("status.phase=Running,metadata.name=foo"))
+		})
+	})
+
+	Context("Watch Objects", func() {
+		newPod := func(name string) *unstructured.Unstructured {
+			pod := &unstructured.Unstructured{}
+			pod.SetAPIVersion("v1")
+			pod.SetKind("Pod")
+			pod.SetNamespace("test")
+			pod.SetName(name)
+			return pod
+		}
+
+		It("print watch events", func() {
+			tf := mockClient(&corev1.PodList{})
+			streams, _, buf, _ := genericiooptions.NewTestIOStreams()
+			o := NewListOptions(tf, streams, types.PodGVR())
+			o.Names = []string{"foo", "bar"}
+			o.Format = printer.Table
+			Expect(o.Complete()).Should(Succeed())
+
+			w := watch.NewFake()
+			go func() {
+				w.Add(newPod("foo"))
+				w.Modify(newPod("baz"))
+				w.Delete(newPod("bar"))
+				w.Stop()
+			}()
+			Expect(o.printWatchEvents(w)).Should(Succeed())
+			Expect(buf.String()).Should(ContainSubstring("ADDED"))
+			Expect(buf.String()).Should(ContainSubstring("DELETED"))
+			Expect(buf.String()).ShouldNot(ContainSubstring("baz"))
+		})
+
+		It("print watch events in yaml", func() {
+			tf := mockClient(&corev1.PodList{})
+			streams, _, buf, _ := genericiooptions.NewTestIOStreams()
+			o := NewListOptions(tf, streams, types.PodGVR())
+			o.Format = printer.YAML
+			Expect(o.Complete()).Should(Succeed())
+
+			w := watch.NewFake()
+			go func() {
+				w.Add(newPod("foo"))
+				w.Stop()
+			}()
+			Expect(o.printWatchEvents(w)).Should(Succeed())
+			Expect(buf.String()).Should(ContainSubstring("name: foo"))
+		})
 	})
 })
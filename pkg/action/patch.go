@@ -153,7 +153,7 @@ func (o *PatchOptions) Run(cmd *cobra.Command) error {
 		}
 		count++
 		name, namespace := info.Name, info.Namespace
-		if o.dryRunStrategy != cmdutil.DryRunClient {
+		if o.dryRunStrategy != cmdutil.DryRunClient && !ExportEnabled() {
 			mapping := info.ResourceMapping()
 			client, err := o.unstructuredClientForMapping(mapping)
 			if err != nil {
@@ -221,6 +221,11 @@ func (o *PatchOptions) Run(cmd *cobra.Command) error {
 			return err
 		}
 
+		// write the patched object to the export directory instead of patching it
+		if ExportEnabled() && o.dryRunStrategy == cmdutil.DryRunNone {
+			return ExportObject(targetObj.(*unstructured.Unstructured), o.Out)
+		}
+
 		didPatch := !reflect.DeepEqual(info.Object, targetObj)
 		printer, err := o.ToPrinter(o.OutputOperation(didPatch))
 		if err != nil {
@@ -40,6 +40,7 @@ options: {
 	cfgTemplateName: string
 	cfgFile:         string
 	forceRestart:    bool
+	configurations: [...{...}]
 	services: [
 		...{
 			name:        string
@@ -122,24 +123,29 @@ content: {
 		if options.type == "Reconfiguring" {
 			reconfigure: {
 				componentName: options.componentNames[0]
-				configurations: [ {
-					name: options.cfgTemplateName
-					if options.forceRestart {
-						policy: "simple"
-					}
-					keys: [{
-						key: options.cfgFile
-						if options.fileContent != "" {
-							fileContent: options.fileContent
-						}
-						if options.hasPatch {
-							parameters: [ for k, v in options.keyValues {
-								key:   k
-								value: v
-							}]
+				if len(options.configurations) > 0 {
+					configurations: options.configurations
+				}
+				if len(options.configurations) == 0 {
+					configurations: [ {
+						name: options.cfgTemplateName
+						if options.forceRestart {
+							policy: "simple"
 						}
+						keys: [{
+							key: options.cfgFile
+							if options.fileContent != "" {
+								fileContent: options.fileContent
+							}
+							if options.hasPatch {
+								parameters: [ for k, v in options.keyValues {
+									key:   k
+									value: v
+								}]
+							}
+						}]
 					}]
-				}]
+				}
 			}
 		}
 		if options.type == "Expose" {
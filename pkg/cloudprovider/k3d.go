@@ -97,7 +97,7 @@ func (p *localCloudProvider) Name() string {
 func (p *localCloudProvider) CreateK8sCluster(clusterInfo *K8sClusterInfo) error {
 	var err error
 
-	if p.cfg, err = buildClusterRunConfig(clusterInfo.ClusterName); err != nil {
+	if p.cfg, err = buildClusterRunConfig(clusterInfo.ClusterName, clusterInfo.Workers); err != nil {
 		return err
 	}
 
@@ -208,10 +208,10 @@ func (p *localCloudProvider) GetClusterInfo() (*K8sClusterInfo, error) {
 	}, nil
 }
 
-// buildClusterRunConfig returns the run-config for the k3d cluster
-func buildClusterRunConfig(clusterName string) (config.ClusterConfig, error) {
+// buildClusterRunConfig returns the run-config for the k3d cluster with the specified number of worker nodes
+func buildClusterRunConfig(clusterName string, workers int) (config.ClusterConfig, error) {
 	createOpts := buildClusterCreateOpts()
-	cluster, err := buildClusterConfig(clusterName, createOpts)
+	cluster, err := buildClusterConfig(clusterName, workers, createOpts)
 	if err != nil {
 		return config.ClusterConfig{}, err
 	}
@@ -239,7 +239,7 @@ func buildClusterCreateOpts() k3d.ClusterCreateOpts {
 	return clusterCreateOpts
 }
 
-func buildClusterConfig(clusterName string, opts k3d.ClusterCreateOpts) (k3d.Cluster, error) {
+func buildClusterConfig(clusterName string, workers int, opts k3d.ClusterCreateOpts) (k3d.Cluster, error) {
 	var network = k3d.ClusterNetwork{
 		Name:     CliDockerNetwork,
 		External: false,
@@ -287,6 +287,15 @@ func buildClusterConfig(clusterName string, opts k3d.ClusterCreateOpts) (k3d.Clu
 
 	nodes = append(nodes, &serverNode)
 
+	// build k3d agent nodes as the worker nodes
+	for i := 0; i < workers; i++ {
+		nodes = append(nodes, &k3d.Node{
+			Name:  k3dClient.GenerateNodeName(clusterConfig.Name, k3d.AgentRole, i),
+			Role:  k3d.AgentRole,
+			Image: K3sImage,
+		})
+	}
+
 	clusterConfig.Nodes = nodes
 	clusterConfig.ServerLoadBalancer.Config.Ports[fmt.Sprintf("%s.tcp", k3d.DefaultAPIPort)] =
 		append(clusterConfig.ServerLoadBalancer.Config.Ports[fmt.Sprintf("%s.tcp", k3d.DefaultAPIPort)], serverNode.Name)
@@ -34,9 +34,16 @@ var _ = Describe("playground", func() {
 	)
 
 	It("k3d util function", func() {
-		config, err := buildClusterRunConfig("test")
+		config, err := buildClusterRunConfig("test", 0)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config.Name).Should(ContainSubstring("test"))
+		// a load balancer node and a server node
+		Expect(config.Nodes).Should(HaveLen(2))
+
+		config, err = buildClusterRunConfig("test", 2)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config.Nodes).Should(HaveLen(4))
+		Expect(config.Nodes[3].Name).Should(Equal("k3d-test-agent-1"))
 		Expect(setUpK3d(context.Background(), nil)).Should(HaveOccurred())
 		Expect(provider.DeleteK8sCluster(&K8sClusterInfo{ClusterName: clusterName})).Should(HaveOccurred())
 	})
@@ -50,6 +50,15 @@ const (
 )
 
 var (
+	// k8sServiceCloudProviderMap maps the managed kubernetes service name to the cloud provider,
+	// so users can specify the cloud provider by the service name such as eks
+	k8sServiceCloudProviderMap = map[string]string{
+		"eks": AWS,
+		"ack": AliCloud,
+		"gke": GCP,
+		"tke": TencentCloud,
+	}
+
 	cloudProviderK8sServiceMap = map[string]string{
 		Local:        "k3s",
 		AWS:          "eks",
@@ -68,6 +77,15 @@ func K8sService(provider string) string {
 	return cloudProviderK8sServiceMap[provider]
 }
 
+// NormalizeCloudProvider returns the cloud provider name, the managed kubernetes
+// service name such as eks, ack and gke will be converted to its cloud provider
+func NormalizeCloudProvider(provider string) string {
+	if p, ok := k8sServiceCloudProviderMap[strings.ToLower(provider)]; ok {
+		return p
+	}
+	return provider
+}
+
 // K8sClusterInfo is the kubernetes cluster information for playground that will
 // be serialized to a state file
 type K8sClusterInfo struct {
@@ -76,6 +94,8 @@ type K8sClusterInfo struct {
 	Region        string `json:"region,omitempty"`
 	KubeConfig    string `json:"kube_config,omitempty"`
 	KbcliVersion  string `json:"kbcli_version,omitempty"`
+	// Workers is the number of the worker nodes, only used by the local k3d cluster
+	Workers int `json:"workers,omitempty"`
 }
 
 // IsValid checks if kubernetes cluster info is valid
@@ -93,6 +113,9 @@ func (c *K8sClusterInfo) String() string {
 	if c.CloudProvider != Local {
 		fields = append(fields, "region: "+c.Region)
 	}
+	if c.Workers > 0 {
+		fields = append(fields, fmt.Sprintf("workers: %d", c.Workers))
+	}
 	return strings.Join(fields, "\n  ")
 }
 
@@ -0,0 +1,234 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+const (
+	// restartThreshold is the restart count from which the container is considered unstable
+	restartThreshold = 3
+
+	defaultStorageClassAnnotationKey = "storageclass.kubernetes.io/is-default-class"
+)
+
+// ProbableCause is a probable cause of the problems correlated from the warning events,
+// pod restarts and failed OpsRequests, with the suggestion to fix it.
+type ProbableCause struct {
+	// Object is the object in the format of kind/name where the problem is found
+	Object     string
+	Cause      string
+	Suggestion string
+}
+
+// DiagnoseObjects are the objects to be diagnosed, the storage classes are used to check
+// the pending PVCs, nil means they are unknown and the check is skipped.
+type DiagnoseObjects struct {
+	Events         []corev1.Event
+	Pods           []corev1.Pod
+	PVCs           []corev1.PersistentVolumeClaim
+	StorageClasses []storagev1.StorageClass
+	OpsRequests    []appsv1alpha1.OpsRequest
+	Backups        []dpv1alpha1.Backup
+}
+
+// eventRule suggests how to fix the problem reported by the warning events with the reason,
+// the keyword is matched in the event message case-insensitively and empty matches all.
+type eventRule struct {
+	reason     string
+	keyword    string
+	suggestion string
+}
+
+const (
+	suggestionPullImage = "Check the image name, the image pull secrets and the network access to the image registry."
+	suggestionCheckLogs = "Check the logs of the instance by `kbcli cluster logs`, use --previous for the restarted container."
+)
+
+var eventRules = []eventRule{
+	{reason: "FailedScheduling", keyword: "insufficient", suggestion: "Add more nodes or decrease the resources of the cluster by `kbcli cluster vscale`."},
+	{reason: "FailedScheduling", keyword: "persistentvolumeclaim", suggestion: "Check the PersistentVolumeClaims used by the pod are bound."},
+	{reason: "FailedScheduling", suggestion: "Check the labels and taints of the nodes, and the affinity and tolerations of the cluster."},
+	{reason: "ProvisioningFailed", suggestion: "Check the provisioner of the storage class and the storage quota."},
+	{reason: "FailedAttachVolume", suggestion: "Check the volume attachments and the CSI driver of the storage class."},
+	{reason: "FailedMount", suggestion: "Check the volumes, secrets and configmaps mounted by the pod exist."},
+	{reason: "BackOff", keyword: "pulling image", suggestion: suggestionPullImage},
+	{reason: "Failed", keyword: "image", suggestion: suggestionPullImage},
+	{reason: "BackOff", suggestion: suggestionCheckLogs},
+	{reason: "Unhealthy", suggestion: "Check the probes and the health of the database in the instance."},
+	{reason: "Evicted", suggestion: "Check the resource pressure of the node and the resources of the cluster."},
+}
+
+// Diagnose correlates the objects into the probable causes, the causes found by the object
+// status come first, and the causes of the same object and suggestion are reported once.
+func Diagnose(objs *DiagnoseObjects) []ProbableCause {
+	if objs == nil {
+		return nil
+	}
+	d := &diagnoser{objs: objs, seen: map[string]bool{}}
+	d.diagnosePods()
+	d.diagnosePVCs()
+	d.diagnoseOpsRequests()
+	d.diagnoseBackups()
+	d.diagnoseEvents()
+	return d.causes
+}
+
+type diagnoser struct {
+	objs   *DiagnoseObjects
+	causes []ProbableCause
+	seen   map[string]bool
+}
+
+func (d *diagnoser) add(object, cause, suggestion string) {
+	key := object + "/" + suggestion
+	if d.seen[key] {
+		return
+	}
+	d.seen[key] = true
+	d.causes = append(d.causes, ProbableCause{Object: object, Cause: cause, Suggestion: suggestion})
+}
+
+func (d *diagnoser) diagnosePods() {
+	for _, pod := range d.objs.Pods {
+		object := "Pod/" + pod.Name
+		for _, status := range pod.Status.ContainerStatuses {
+			if waiting := status.State.Waiting; waiting != nil {
+				switch waiting.Reason {
+				case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+					d.add(object, fmt.Sprintf("container %s failed to pull image %s: %s", status.Name, status.Image, waiting.Reason), suggestionPullImage)
+					continue
+				}
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+				d.add(object, fmt.Sprintf("container %s was OOMKilled and restarted %d times", status.Name, status.RestartCount),
+					"Increase the memory of the cluster by `kbcli cluster vscale`.")
+				continue
+			}
+			if status.RestartCount >= restartThreshold ||
+				(status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff") {
+				d.add(object, fmt.Sprintf("container %s restarted %d times", status.Name, status.RestartCount), suggestionCheckLogs)
+			}
+		}
+	}
+}
+
+func (d *diagnoser) diagnosePVCs() {
+	var defaultClass string
+	classes := map[string]bool{}
+	for _, sc := range d.objs.StorageClasses {
+		classes[sc.Name] = true
+		if sc.Annotations[defaultStorageClassAnnotationKey] == "true" {
+			defaultClass = sc.Name
+		}
+	}
+	for _, pvc := range d.objs.PVCs {
+		if pvc.Status.Phase != corev1.ClaimPending || d.objs.StorageClasses == nil {
+			continue
+		}
+		object := "PersistentVolumeClaim/" + pvc.Name
+		switch className := pvc.Spec.StorageClassName; {
+		case className == nil && defaultClass == "":
+			d.add(object, "PVC is pending and there is no default storage class",
+				"Mark a storage class as the default or specify one by --storage-class when creating the cluster.")
+		case className != nil && *className != "" && !classes[*className]:
+			d.add(object, fmt.Sprintf("PVC is pending and storage class %s is not found", *className),
+				"Create the storage class or specify an existing one by --storage-class when creating the cluster.")
+		}
+	}
+}
+
+func (d *diagnoser) diagnoseOpsRequests() {
+	for _, ops := range d.objs.OpsRequests {
+		if ops.Status.Phase != appsv1alpha1.OpsFailedPhase {
+			continue
+		}
+		cause := fmt.Sprintf("%s OpsRequest failed", ops.Spec.Type)
+		if cond := meta.FindStatusCondition(ops.Status.Conditions, appsv1alpha1.ConditionTypeFailed); cond != nil && cond.Message != "" {
+			cause = fmt.Sprintf("%s: %s", cause, cond.Message)
+		}
+		d.add("OpsRequest/"+ops.Name, cause,
+			fmt.Sprintf("Check the details by `kbcli cluster describe-ops %s -n %s`.", ops.Name, ops.Namespace))
+	}
+}
+
+func (d *diagnoser) diagnoseBackups() {
+	for _, backup := range d.objs.Backups {
+		if backup.Status.Phase != dpv1alpha1.BackupPhaseFailed {
+			continue
+		}
+		cause := "backup failed"
+		if backup.Status.FailureReason != "" {
+			cause = fmt.Sprintf("%s: %s", cause, backup.Status.FailureReason)
+		}
+		suggestion := "Check the logs of the backup job pods."
+		if strings.Contains(strings.ToLower(backup.Status.FailureReason), "backuprepo") {
+			suggestion = "Check the backup repo is ready by `kbcli backuprepo describe`."
+		}
+		d.add("Backup/"+backup.Name, cause, suggestion)
+	}
+}
+
+func (d *diagnoser) diagnoseEvents() {
+	pods := map[string]*corev1.Pod{}
+	for i := range d.objs.Pods {
+		pods[d.objs.Pods[i].Name] = &d.objs.Pods[i]
+	}
+	pvcs := map[string]*corev1.PersistentVolumeClaim{}
+	for i := range d.objs.PVCs {
+		pvcs[d.objs.PVCs[i].Name] = &d.objs.PVCs[i]
+	}
+	// the problems reported by the events may have been resolved
+	resolved := func(e *corev1.Event) bool {
+		switch e.InvolvedObject.Kind {
+		case "Pod":
+			pod := pods[e.InvolvedObject.Name]
+			return pod != nil && e.Reason == "FailedScheduling" && pod.Spec.NodeName != ""
+		case "PersistentVolumeClaim":
+			pvc := pvcs[e.InvolvedObject.Name]
+			return pvc != nil && pvc.Status.Phase == corev1.ClaimBound
+		}
+		return false
+	}
+
+	for i := range d.objs.Events {
+		e := &d.objs.Events[i]
+		if e.Type != corev1.EventTypeWarning || resolved(e) {
+			continue
+		}
+		for _, rule := range eventRules {
+			if rule.reason != e.Reason || !strings.Contains(strings.ToLower(e.Message), rule.keyword) {
+				continue
+			}
+			d.add(fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name),
+				fmt.Sprintf("%s: %s", e.Reason, strings.TrimSpace(e.Message)), rule.suggestion)
+			break
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+var _ = Describe("diagnose", func() {
+	objects := func(causes []ProbableCause) []string {
+		var result []string
+		for _, c := range causes {
+			result = append(result, c.Object)
+		}
+		return result
+	}
+	warning := func(kind, name, reason, message string) corev1.Event {
+		return corev1.Event{
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        message,
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+		}
+	}
+
+	It("nothing to diagnose", func() {
+		Expect(Diagnose(nil)).Should(BeEmpty())
+		Expect(Diagnose(&DiagnoseObjects{})).Should(BeEmpty())
+	})
+
+	It("diagnose pods", func() {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod"}}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name:                 "oom",
+				RestartCount:         1,
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+			},
+			{
+				Name:  "image",
+				Image: "not-exist",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			},
+			{Name: "crash", RestartCount: 5},
+			{Name: "stable", RestartCount: 1},
+		}
+		causes := Diagnose(&DiagnoseObjects{
+			Pods: []corev1.Pod{pod},
+			// the same cause found by the image pull back-off event is reported once
+			Events: []corev1.Event{warning("Pod", "pod", "BackOff", "Back-off pulling image not-exist")},
+		})
+		Expect(causes).Should(HaveLen(3))
+		Expect(causes[0].Cause).Should(ContainSubstring("OOMKilled"))
+		Expect(causes[1].Cause).Should(ContainSubstring("not-exist"))
+		Expect(causes[2].Cause).Should(ContainSubstring("restarted 5 times"))
+	})
+
+	It("diagnose pending PVCs", func() {
+		missing := "missing"
+		pending := func(name string, className *string) corev1.PersistentVolumeClaim {
+			pvc := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			pvc.Spec.StorageClassName = className
+			pvc.Status.Phase = corev1.ClaimPending
+			return pvc
+		}
+		pvcs := []corev1.PersistentVolumeClaim{pending("pvc1", &missing), pending("pvc2", nil)}
+
+		By("the storage classes are unknown")
+		Expect(Diagnose(&DiagnoseObjects{PVCs: pvcs})).Should(BeEmpty())
+
+		By("the storage class is missing and there is no default storage class")
+		classes := []storagev1.StorageClass{{ObjectMeta: metav1.ObjectMeta{Name: "standard"}}}
+		causes := Diagnose(&DiagnoseObjects{PVCs: pvcs, StorageClasses: classes})
+		Expect(objects(causes)).Should(Equal([]string{"PersistentVolumeClaim/pvc1", "PersistentVolumeClaim/pvc2"}))
+		Expect(causes[0].Cause).Should(ContainSubstring("storage class missing is not found"))
+		Expect(causes[1].Cause).Should(ContainSubstring("no default storage class"))
+
+		By("the default storage class exists")
+		classes[0].Annotations = map[string]string{defaultStorageClassAnnotationKey: "true"}
+		causes = Diagnose(&DiagnoseObjects{PVCs: pvcs, StorageClasses: classes})
+		Expect(objects(causes)).Should(Equal([]string{"PersistentVolumeClaim/pvc1"}))
+	})
+
+	It("diagnose failed ops and backups", func() {
+		ops := appsv1alpha1.OpsRequest{ObjectMeta: metav1.ObjectMeta{Name: "ops", Namespace: "default"}}
+		ops.Spec.Type = appsv1alpha1.RestartType
+		ops.Status.Phase = appsv1alpha1.OpsFailedPhase
+		ops.Status.Conditions = []metav1.Condition{{Type: appsv1alpha1.ConditionTypeFailed, Message: "timeout"}}
+		backup := dpv1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "backup"}}
+		backup.Status.Phase = dpv1alpha1.BackupPhaseFailed
+		backup.Status.FailureReason = "BackupRepo is not ready"
+		causes := Diagnose(&DiagnoseObjects{
+			OpsRequests: []appsv1alpha1.OpsRequest{ops},
+			Backups:     []dpv1alpha1.Backup{backup},
+		})
+		Expect(objects(causes)).Should(Equal([]string{"OpsRequest/ops", "Backup/backup"}))
+		Expect(causes[0].Cause).Should(Equal("Restart OpsRequest failed: timeout"))
+		Expect(causes[0].Suggestion).Should(ContainSubstring("kbcli cluster describe-ops ops -n default"))
+		Expect(causes[1].Suggestion).Should(ContainSubstring("kbcli backuprepo describe"))
+	})
+
+	It("diagnose warning events", func() {
+		scheduled := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "scheduled"}, Spec: corev1.PodSpec{NodeName: "node"}}
+		bound := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "bound"}}
+		bound.Status.Phase = corev1.ClaimBound
+		causes := Diagnose(&DiagnoseObjects{
+			Pods: []corev1.Pod{scheduled},
+			PVCs: []corev1.PersistentVolumeClaim{bound},
+			Events: []corev1.Event{
+				warning("Pod", "pending", "FailedScheduling", "0/3 nodes are available: 3 Insufficient cpu."),
+				warning("Pod", "scheduled", "FailedScheduling", "0/3 nodes are available: 3 Insufficient cpu."),
+				warning("PersistentVolumeClaim", "bound", "ProvisioningFailed", "failed to provision volume"),
+				warning("PersistentVolumeClaim", "pending", "ProvisioningFailed", "failed to provision volume"),
+				warning("Pod", "pending", "Unknown", "unknown warning"),
+				{Type: corev1.EventTypeNormal, Reason: "BackOff", InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "normal"}},
+			},
+		})
+		Expect(objects(causes)).Should(Equal([]string{"Pod/pending", "PersistentVolumeClaim/pending"}))
+		Expect(causes[0].Suggestion).Should(ContainSubstring("kbcli cluster vscale"))
+	})
+})
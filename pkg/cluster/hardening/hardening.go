@@ -0,0 +1,133 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package hardening
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/leaanthony/debme"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/gotemplate"
+)
+
+const (
+	networkPolicyTemplate = "network_policy.yaml.tpl"
+	rbacTemplate          = "rbac.yaml.tpl"
+)
+
+var (
+	//go:embed templates/*
+	hardeningTemplate embed.FS
+)
+
+// Port is a port of the component services allowed to be accessed by the peers
+type Port struct {
+	// Port is the number or the name of the container port
+	Port     string
+	Protocol string
+}
+
+// Component is a component of the cluster to restrict the ingress
+type Component struct {
+	Name  string
+	Ports []Port
+}
+
+// Options are the values to render the hardening templates
+type Options struct {
+	ClusterName string
+	Namespace   string
+	Components  []Component
+
+	// AllowNamespaces are the namespaces allowed to access the service ports, default to the namespace of the cluster
+	AllowNamespaces []string
+	// AllowPodLabels are the labels of the pods allowed to access the service ports
+	AllowPodLabels map[string]string
+	// KubeBlocksNamespace is the namespace of KubeBlocks, it's allowed to access all ports of the pods
+	KubeBlocksNamespace string
+}
+
+// ServiceAccountName returns the name of the dedicated ServiceAccount of the cluster
+func ServiceAccountName(clusterName string) string {
+	return fmt.Sprintf("kb-%s-hardened", clusterName)
+}
+
+// BuildNetworkPolicies builds the NetworkPolicies restricting the ingress of the components
+func BuildNetworkPolicies(o *Options) ([]*unstructured.Unstructured, error) {
+	return build(o, networkPolicyTemplate)
+}
+
+// BuildRBAC builds the ServiceAccount with the minimal Role required by the pods of the cluster
+func BuildRBAC(o *Options) ([]*unstructured.Unstructured, error) {
+	return build(o, rbacTemplate)
+}
+
+func build(o *Options, templateName string) ([]*unstructured.Unstructured, error) {
+	tmplFs, _ := debme.FS(hardeningTemplate, "templates")
+	tpl, err := tmplFs.ReadFile(templateName)
+	if err != nil {
+		return nil, err
+	}
+	values := gotemplate.TplValues{
+		"ClusterName":         o.ClusterName,
+		"Namespace":           o.Namespace,
+		"Components":          o.Components,
+		"AllowNamespaces":     o.AllowNamespaces,
+		"AllowPodLabels":      o.AllowPodLabels,
+		"KubeBlocksNamespace": o.KubeBlocksNamespace,
+		"ServiceAccountName":  ServiceAccountName(o.ClusterName),
+		"Labels": map[string]string{
+			constant.AppInstanceLabelKey:  o.ClusterName,
+			constant.AppManagedByLabelKey: "kbcli",
+		},
+	}
+	engine := gotemplate.NewTplEngine(&values, nil, templateName, nil, nil)
+	rendered, err := engine.Render(string(tpl))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %v", templateName, err)
+	}
+	return decodeObjects(rendered)
+}
+
+// decodeObjects decodes the multi-document YAML to objects, the empty documents are skipped
+func decodeObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewBufferString(manifest), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
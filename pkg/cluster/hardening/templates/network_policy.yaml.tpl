@@ -0,0 +1,63 @@
+{{- range .Components }}
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: {{ $.ClusterName }}-{{ .Name }}-ingress
+  namespace: {{ $.Namespace }}
+  labels:
+    {{- range $k, $v := $.Labels }}
+    {{ $k }}: {{ $v | quote }}
+    {{- end }}
+    apps.kubeblocks.io/component-name: {{ .Name | quote }}
+spec:
+  podSelector:
+    matchLabels:
+      app.kubernetes.io/instance: {{ $.ClusterName | quote }}
+      apps.kubeblocks.io/component-name: {{ .Name | quote }}
+  policyTypes:
+  - Ingress
+  ingress:
+  # the traffic between the pods of the cluster, such as the replication, is not restricted
+  - from:
+    - podSelector:
+        matchLabels:
+          app.kubernetes.io/instance: {{ $.ClusterName | quote }}
+  {{- with $.KubeBlocksNamespace }}
+  # KubeBlocks accesses the pods to probe the roles and do the switchover
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: {{ . | quote }}
+  {{- end }}
+  {{- if .Ports }}
+  - from:
+    {{- range $.AllowNamespaces }}
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: {{ . | quote }}
+      {{- with $.AllowPodLabels }}
+      podSelector:
+        matchLabels:
+          {{- range $k, $v := . }}
+          {{ $k }}: {{ $v | quote }}
+          {{- end }}
+      {{- end }}
+    {{- else }}
+    {{- with $.AllowPodLabels }}
+    - podSelector:
+        matchLabels:
+          {{- range $k, $v := . }}
+          {{ $k }}: {{ $v | quote }}
+          {{- end }}
+    {{- else }}
+    - podSelector: {}
+    {{- end }}
+    {{- end }}
+    ports:
+    {{- range .Ports }}
+    - port: {{ .Port }}
+      protocol: {{ .Protocol }}
+    {{- end }}
+  {{- end }}
+{{- end }}
@@ -0,0 +1,79 @@
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .ServiceAccountName }}
+  namespace: {{ .Namespace }}
+  labels:
+    {{- range $k, $v := .Labels }}
+    {{ $k }}: {{ $v | quote }}
+    {{- end }}
+---
+# the permissions required by lorry, the sidecar of KubeBlocks to probe the roles and manage the database
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: {{ .ServiceAccountName }}
+  namespace: {{ .Namespace }}
+  labels:
+    {{- range $k, $v := .Labels }}
+    {{ $k }}: {{ $v | quote }}
+    {{- end }}
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+- apiGroups:
+  - ""
+  resources:
+  - configmaps
+  verbs:
+  - create
+  - get
+  - list
+  - patch
+  - update
+  - delete
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - apps.kubeblocks.io
+  resources:
+  - clusters
+  resourceNames:
+  - {{ .ClusterName | quote }}
+  verbs:
+  - get
+- apiGroups:
+  - apps.kubeblocks.io
+  resources:
+  - clusters/status
+  resourceNames:
+  - {{ .ClusterName | quote }}
+  verbs:
+  - get
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: {{ .ServiceAccountName }}
+  namespace: {{ .Namespace }}
+  labels:
+    {{- range $k, $v := .Labels }}
+    {{ $k }}: {{ $v | quote }}
+    {{- end }}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: {{ .ServiceAccountName }}
+subjects:
+- kind: ServiceAccount
+  name: {{ .ServiceAccountName }}
+  namespace: {{ .Namespace }}
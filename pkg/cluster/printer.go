@@ -42,6 +42,8 @@ const (
 
 type PrinterOptions struct {
 	ShowLabels bool
+	// Format is the format to render the table, such as CSV or Markdown
+	Format printer.Format
 }
 
 type tblInfo struct {
@@ -118,6 +120,7 @@ func NewPrinter(out io.Writer, printType PrintType, opt *PrinterOptions) *Printe
 		p.tblInfo.header = append(p.tblInfo.header, "LABELS")
 	}
 
+	p.tbl.SetFormat(opt.Format)
 	p.tbl.SetHeader(p.tblInfo.header...)
 	return p
 }
@@ -126,6 +129,11 @@ func (p *Printer) AddRow(objs *ClusterObjects) {
 	p.addRow(p.tbl, objs, p.opt)
 }
 
+// ApplyTableOptions applies the options to customize the table output
+func (p *Printer) ApplyTableOptions(opts *printer.TableOptions) error {
+	return p.tbl.ApplyOptions(opts)
+}
+
 func (p *Printer) Print() {
 	p.tbl.Print()
 }
@@ -21,6 +21,7 @@ package accounts
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -29,12 +30,16 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
 
+	lorryclient "github.com/apecloud/kubeblocks/pkg/lorry/client"
 	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	clusterutil "github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 type AccountBaseOptions struct {
@@ -46,16 +51,17 @@ type AccountBaseOptions struct {
 	Verbose       bool
 	AccountOp     lorryutil.OperationKind
 	RequestMeta   map[string]interface{}
+	Format        printer.Format
 	*action.ExecOptions
 }
 
 var (
-	errClusterNameNum        = fmt.Errorf("please specify ONE cluster-name at a time")
-	errMissingUserName       = fmt.Errorf("please specify username")
-	errMissingRoleName       = fmt.Errorf("please specify at least ONE role name")
-	errInvalidRoleName       = fmt.Errorf("invalid role name, should be one of [SUPERUSER, READWRITE, READONLY] ")
-	errCompNameOrInstName    = fmt.Errorf("please specify either --component or --instance, they are exclusive")
-	errClusterNameorInstName = fmt.Errorf("specify either cluster name or --instance")
+	errClusterNameNum        = clierrors.NewValidation("please specify ONE cluster-name at a time")
+	errMissingUserName       = clierrors.NewValidation("please specify username")
+	errMissingRoleName       = clierrors.NewValidation("please specify at least ONE role name")
+	errInvalidRoleName       = clierrors.NewValidation("invalid role name, should be one of [SUPERUSER, READWRITE, READONLY] ")
+	errCompNameOrInstName    = clierrors.NewValidation("please specify either --component or --instance, they are exclusive")
+	errClusterNameorInstName = clierrors.NewValidation("specify either cluster name or --instance")
 )
 
 func NewAccountBaseOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *AccountBaseOptions {
@@ -66,7 +72,9 @@ func NewAccountBaseOptions(f cmdutil.Factory, streams genericiooptions.IOStreams
 
 func (o *AccountBaseOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.ComponentName, "component", "", "Specify the name of component to be connected. If not specified, pick the first one.")
+	util.RegisterFlagCompletionFunc(cmd, "component", util.ClusterComponentCompletionFunc(o.Factory))
 	cmd.Flags().StringVarP(&o.PodName, "instance", "i", "", "Specify the name of instance to be connected.")
+	printer.AddOutputFlag(cmd, &o.Format)
 }
 
 func (o *AccountBaseOptions) Validate(args []string) error {
@@ -135,14 +143,39 @@ func (o *AccountBaseOptions) Complete(f cmdutil.Factory) error {
 	o.ExecOptions.Pod = o.Pod
 	o.ExecOptions.Namespace = o.Namespace
 	o.ExecOptions.Quiet = true
-	o.ExecOptions.TTY = true
-	o.ExecOptions.Stdin = true
+	// only attach the terminal when the output is human-readable, otherwise run in scripted mode
+	// so the output can be consumed by scripts and CI.
+	o.ExecOptions.TTY = !o.scripted()
+	o.ExecOptions.Stdin = !o.scripted()
 
 	o.Verbose = klog.V(1).Enabled()
 
 	return nil
 }
 
+// newLorryClient creates the lorry client which sends requests to lorry by exec into the pod,
+// the exec is done by the executor of the options so it can be replaced in tests.
+func (o *AccountBaseOptions) newLorryClient() (lorryclient.Client, error) {
+	cli, err := lorryclient.NewK8sExecClientWithPod(o.Pod)
+	if err != nil {
+		return nil, err
+	}
+	if cli == nil {
+		return nil, fmt.Errorf("lorry container not found in instance %s", o.Pod.Name)
+	}
+	if o.Executor != nil {
+		cli.Executor = o.Executor
+	}
+	// the lorry client attaches the terminal by default, follow the exec options so that
+	// the commands in scripted mode run without TTY and stdin
+	cli.TTY = o.TTY
+	cli.Stdin = o.Stdin
+	if !cli.Stdin {
+		cli.In = nil
+	}
+	return cli, nil
+}
+
 func (o *AccountBaseOptions) newTblPrinterWithStyle(title string, header []interface{}) *printer.TablePrinter {
 	tblPrinter := printer.NewTablePrinter(o.Out)
 	tblPrinter.SetStyle(printer.TerminalStyle)
@@ -151,13 +184,41 @@ func (o *AccountBaseOptions) newTblPrinterWithStyle(title string, header []inter
 	return tblPrinter
 }
 
+// scripted returns true if the output is in a structured format which is consumed by scripts
+func (o *AccountBaseOptions) scripted() bool {
+	return o.Format == printer.JSON || o.Format == printer.YAML
+}
+
+// printStructured prints the object in JSON or YAML format, it returns false if the
+// output format is human-readable and nothing is printed.
+func (o *AccountBaseOptions) printStructured(obj interface{}) bool {
+	if !o.scripted() {
+		return false
+	}
+	var data []byte
+	if o.Format == printer.YAML {
+		data, _ = yaml.Marshal(obj)
+	} else {
+		data, _ = json.MarshalIndent(obj, "", "  ")
+		data = append(data, '\n')
+	}
+	fmt.Fprint(o.Out, string(data))
+	return true
+}
+
 func (o *AccountBaseOptions) printGeneralInfo(event, message string) {
+	if o.printStructured(map[string]string{"result": event, "message": message}) {
+		return
+	}
 	tblPrinter := o.newTblPrinterWithStyle("QUERY RESULT", []interface{}{"RESULT", "MESSAGE"})
 	tblPrinter.AddRow(event, message)
 	tblPrinter.Print()
 }
 
 func (o *AccountBaseOptions) printUserInfo(users []map[string]any) {
+	if o.printStructured(users) {
+		return
+	}
 	// render user info with username and password expired boolean
 	tblPrinter := o.newTblPrinterWithStyle("USER INFO", []interface{}{"USERNAME", "EXPIRED"})
 	for _, user := range users {
@@ -168,6 +229,9 @@ func (o *AccountBaseOptions) printUserInfo(users []map[string]any) {
 }
 
 func (o *AccountBaseOptions) printRoleInfo(users []map[string]any) {
+	if o.printStructured(users) {
+		return
+	}
 	tblPrinter := o.newTblPrinterWithStyle("USER INFO", []interface{}{"USERNAME", "ROLE"})
 	for _, user := range users {
 		tblPrinter.AddRow(user["userName"], user["roleName"])
@@ -20,11 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package accounts
 
 import (
+	"bytes"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -33,6 +35,9 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	lorryclient "github.com/apecloud/kubeblocks/pkg/lorry/client"
+
+	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -125,7 +130,61 @@ var _ = Describe("Base Account Options", func() {
 			Expect(o.Dynamic).ShouldNot(BeNil())
 			Expect(o.Namespace).Should(Equal(namespace))
 			Expect(o.Pod.Name).Should(Equal(pods.Items[0].Name))
+			Expect(o.TTY).Should(BeTrue())
 
+			By("complete in scripted mode")
+			o.Format = printer.JSON
+			Expect(o.Complete(tf)).Should(Succeed())
+			Expect(o.TTY).Should(BeFalse())
+			Expect(o.Stdin).Should(BeFalse())
+		})
+
+		It("print structured result", func() {
+			o := NewAccountBaseOptions(tf, streams)
+			o.printGeneralInfo("success", "")
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring("RESULT"))
+
+			streams.Out.(*bytes.Buffer).Reset()
+			o.Format = printer.JSON
+			o.printUserInfo([]map[string]any{{"userName": "foo", "expired": false}})
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring(`"userName": "foo"`))
+
+			streams.Out.(*bytes.Buffer).Reset()
+			o.Format = printer.YAML
+			o.printGeneralInfo("fail", "bar")
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(Equal("message: bar\nresult: fail\n"))
+		})
+
+		It("new lorry client", func() {
+			o := NewAccountBaseOptions(tf, streams)
+			withFakeLorry(o, &pods.Items[0], testing.NewFakeLorryExecutor())
+			Expect(o.newLorryClient()).ShouldNot(BeNil())
+
+			By("the lorry client runs without TTY and stdin in scripted mode")
+			o.TTY, o.Stdin = false, false
+			cli, err := o.newLorryClient()
+			Expect(err).Should(Succeed())
+			execCli := cli.(*lorryclient.K8sExecClient)
+			Expect(execCli.TTY).Should(BeFalse())
+			Expect(execCli.Stdin).Should(BeFalse())
+			Expect(execCli.In).Should(BeNil())
+
+			By("the instance without lorry container")
+			o.Pod = &pods.Items[0]
+			_, err = o.newLorryClient()
+			Expect(err).Should(MatchError(ContainSubstring("lorry container not found")))
 		})
 	})
 })
+
+// withFakeLorry sets the pod with lorry container and the fake lorry executor to the options,
+// the lorry exec client loads the fake kubeconfig from the KUBECONFIG env.
+func withFakeLorry(o *AccountBaseOptions, pod *corev1.Pod, executor *testing.FakeLorryExecutor) {
+	kubeconfig, err := testing.WriteFakeKubeConfig(GinkgoT().TempDir())
+	Expect(err).Should(Succeed())
+	GinkgoT().Setenv("KUBECONFIG", kubeconfig)
+	o.Pod = pod.DeepCopy()
+	o.Pod.Spec.Containers = append(o.Pod.Spec.Containers, testing.FakeLorryContainer())
+	o.PodName = pod.Name
+	o.Executor = executor
+}
@@ -28,14 +28,13 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
-
-	"github.com/apecloud/kubeblocks/pkg/lorry/client"
 )
 
 type CreateUserOptions struct {
 	*AccountBaseOptions
 	userName string
 	password string
+	secret   secretSyncOptions
 }
 
 func NewCreateUserOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *CreateUserOptions {
@@ -48,6 +47,7 @@ func (o *CreateUserOptions) AddFlags(cmd *cobra.Command) {
 	o.AccountBaseOptions.AddFlags(cmd)
 	cmd.Flags().StringVar(&o.userName, "name", "", "Required. Specify the name of user, which must be unique.")
 	cmd.Flags().StringVarP(&o.password, "password", "p", "", "Optional. Specify the password of user. The default value is empty, which means a random password will be generated.")
+	o.secret.addFlags(cmd)
 	_ = cmd.MarkFlagRequired("name")
 	// TODO:@shanshan add expire flag if needed
 	// cmd.Flags().DurationVar(&o.info.ExpireAt, "expire", 0, "Optional. Specify the expired time of password. The default value is 0, which means the user will never expire.")
@@ -77,7 +77,7 @@ func (o *CreateUserOptions) Complete(f cmdutil.Factory) error {
 
 func (o *CreateUserOptions) Run(cmd *cobra.Command, f cmdutil.Factory, streams genericiooptions.IOStreams) error {
 	klog.V(1).Info(fmt.Sprintf("connect to cluster %s, component %s, instance %s\n", o.ClusterName, o.ComponentName, o.PodName))
-	lorryClient, err := client.NewK8sExecClientWithPod(o.Pod)
+	lorryClient, err := o.newLorryClient()
 	if err != nil {
 		return err
 	}
@@ -88,5 +88,5 @@ func (o *CreateUserOptions) Run(cmd *cobra.Command, f cmdutil.Factory, streams g
 		return err
 	}
 	o.printGeneralInfo("success", "")
-	return nil
+	return o.syncSecretIfNeeded(&o.secret, o.userName, o.password)
 }
@@ -21,10 +21,12 @@ package accounts
 
 import (
 	"net/http"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -33,6 +35,9 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
+	clusterutil "github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -121,5 +126,34 @@ var _ = Describe("Create Account Options", func() {
 			Expect(o.Complete(tf)).Should(Succeed())
 			Expect(o.password).ShouldNot(BeEmpty())
 		})
+
+		It("run", func() {
+			o := NewCreateUserOptions(tf, streams)
+			executor := testing.NewFakeLorryExecutor()
+			withFakeLorry(o.AccountBaseOptions, &pods.Items[0], executor)
+			o.userName = "alice"
+			o.password = "it's-a-secret"
+			Expect(o.Run(nil, tf, streams)).Should(Succeed())
+			requests := executor.Requests()
+			Expect(requests).Should(HaveLen(1))
+			Expect(requests[0].Operation).Should(Equal(strings.ToLower(string(lorryutil.CreateUserOp))))
+			Expect(requests[0].Method).Should(Equal("POST"))
+			Expect(requests[0].Parameters).Should(Equal(map[string]any{"userName": "alice", "password": "it's-a-secret"}))
+
+			By("synchronize the credentials to the secret")
+			o.ClusterName = clusterName
+			o.ComponentName = "mysql"
+			o.Namespace = namespace
+			o.Dynamic = tf.FakeDynamicClient
+			o.secret = secretSyncOptions{sync: true, nameTemplate: defaultSecretNameTemplate}
+			Expect(o.Run(nil, tf, streams)).Should(Succeed())
+			secret := &corev1.Secret{}
+			Expect(clusterutil.GetK8SClientObject(tf.FakeDynamicClient, secret, types.SecretGVR(), namespace, "apple-mysql-account-alice")).Should(Succeed())
+			Expect(secret.StringData).Should(HaveKeyWithValue(accountSecretPasswordKey, "it's-a-secret"))
+
+			By("the user already exists")
+			executor.WithError(string(lorryutil.CreateUserOp), "user alice already exists")
+			Expect(o.Run(nil, tf, streams)).Should(MatchError(ContainSubstring("already exists")))
+		})
 	})
 })
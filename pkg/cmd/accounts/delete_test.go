@@ -22,6 +22,7 @@ package accounts
 import (
 	"bytes"
 	"net/http"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -34,6 +35,8 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -118,5 +121,14 @@ var _ = Describe("Delete Account Options", func() {
 
 			Expect(o.Complete(tf)).Should(Succeed())
 		})
+
+		It("run", func() {
+			o := NewDeleteUserOptions(tf, streams)
+			executor := testing.NewFakeLorryExecutor()
+			withFakeLorry(o.AccountBaseOptions, &pods.Items[0], executor)
+			o.userName = "alice"
+			Expect(o.Run(nil, tf, streams)).Should(Succeed())
+			Expect(executor.Requests()[0].Operation).Should(Equal(strings.ToLower(string(lorryutil.DeleteUserOp))))
+		})
 	})
 })
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package accounts
 
 import (
+	"bytes"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -33,6 +34,8 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -111,5 +114,17 @@ var _ = Describe("Describe Account Options", func() {
 
 			Expect(o.Complete(tf)).Should(Succeed())
 		})
+
+		It("run", func() {
+			o := NewDescribeUserOptions(tf, streams)
+			executor := testing.NewFakeLorryExecutor().WithResponse(string(lorryutil.DescribeUserOp), map[string]any{
+				"user": map[string]any{"userName": "alice", "roleName": "readwrite"},
+			})
+			withFakeLorry(o.AccountBaseOptions, &pods.Items[0], executor)
+			o.userName = "alice"
+			Expect(o.Run(nil, tf, streams)).Should(Succeed())
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring("readwrite"))
+			Expect(executor.Requests()[0].Parameters).Should(HaveKeyWithValue("userName", "alice"))
+		})
 	})
 })
@@ -30,7 +30,6 @@ import (
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
-	"github.com/apecloud/kubeblocks/pkg/lorry/client"
 	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
 )
 
@@ -88,7 +87,7 @@ func (o *GrantOptions) Complete(f cmdutil.Factory) error {
 
 func (o *GrantOptions) Run(cmd *cobra.Command, f cmdutil.Factory, streams genericiooptions.IOStreams) error {
 	klog.V(1).Info(fmt.Sprintf("connect to cluster %s, component %s, instance %s\n", o.ClusterName, o.ComponentName, o.PodName))
-	lorryClient, err := client.NewK8sExecClientWithPod(o.Pod)
+	lorryClient, err := o.newLorryClient()
 	if err != nil {
 		return err
 	}
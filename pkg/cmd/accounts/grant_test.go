@@ -122,5 +122,15 @@ var _ = Describe("Grant Account Options", func() {
 			Expect(o.Pod).ShouldNot(BeNil())
 			Expect(o.Pod.Name).Should(Equal(o.PodName))
 		})
+
+		It("run", func() {
+			o := NewGrantOptions(tf, streams)
+			executor := testing.NewFakeLorryExecutor()
+			withFakeLorry(o.AccountBaseOptions, &pods.Items[0], executor)
+			o.userName = "alice"
+			o.roleName = "readonly"
+			Expect(o.Run(nil, tf, streams)).Should(Succeed())
+			Expect(executor.Requests()[0].Parameters).Should(Equal(map[string]any{"userName": "alice", "roleName": "readonly"}))
+		})
 	})
 })
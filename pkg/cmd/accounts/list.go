@@ -28,8 +28,6 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/spf13/cobra"
-
-	"github.com/apecloud/kubeblocks/pkg/lorry/client"
 )
 
 type ListUserOptions struct {
@@ -51,7 +49,7 @@ func (o *ListUserOptions) Complete(f cmdutil.Factory) error {
 
 func (o *ListUserOptions) Run(cmd *cobra.Command, f cmdutil.Factory, streams genericiooptions.IOStreams) error {
 	klog.V(1).Info(fmt.Sprintf("connect to cluster %s, component %s, instance %s\n", o.ClusterName, o.ComponentName, o.PodName))
-	lorryClient, err := client.NewK8sExecClientWithPod(o.Pod)
+	lorryClient, err := o.newLorryClient()
 	if err != nil {
 		return err
 	}
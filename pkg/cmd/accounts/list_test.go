@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package accounts
 
 import (
+	"bytes"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -33,6 +34,8 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -125,5 +128,19 @@ var _ = Describe("List Account Options", func() {
 			Expect(o.Namespace).Should(Equal(namespace))
 			Expect(o.Pod.Name).Should(Equal(o.PodName))
 		})
+
+		It("run", func() {
+			o := NewListUserOptions(tf, streams)
+			executor := testing.NewFakeLorryExecutor().WithResponse(string(lorryutil.ListUsersOp), testing.FakeLorryUsers("alice", "bob"))
+			withFakeLorry(o.AccountBaseOptions, &pods.Items[0], executor)
+			Expect(o.Run(nil, tf, streams)).Should(Succeed())
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring("alice"))
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring("bob"))
+			Expect(executor.Requests()).Should(HaveLen(1))
+
+			By("list users failed")
+			executor.WithError(string(lorryutil.ListUsersOp), "connection refused")
+			Expect(o.Run(nil, tf, streams)).Should(MatchError(ContainSubstring("connection refused")))
+		})
 	})
 })
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package accounts
 
 import (
+	"bytes"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -32,6 +33,8 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -121,5 +124,15 @@ var _ = Describe("Revoke Account Options", func() {
 			Expect(o.Pod).ShouldNot(BeNil())
 			Expect(o.Pod.Name).Should(Equal(o.PodName))
 		})
+
+		It("run", func() {
+			o := NewRevokeOptions(tf, streams)
+			executor := testing.NewFakeLorryExecutor().WithError(string(lorryutil.RevokeUserRoleOp), "role readonly is not granted")
+			withFakeLorry(o.AccountBaseOptions, &pods.Items[0], executor)
+			o.userName = "alice"
+			o.roleName = "readonly"
+			Expect(o.Run(nil, tf, streams)).Should(MatchError(ContainSubstring("not granted")))
+			Expect(streams.Out.(*bytes.Buffer).String()).Should(ContainSubstring("fail"))
+		})
 	})
 })
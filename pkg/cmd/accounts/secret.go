@@ -0,0 +1,235 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	clusterutil "github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	// AccountSecretLabelKey is the label of the Secrets synchronized with the credentials of the accounts
+	AccountSecretLabelKey = "apps.kubeblocks.io/account-secret"
+	// accountNameAnnotationKey is the annotation of the account name of the synchronized Secret, the
+	// account name may be not a valid label value.
+	accountNameAnnotationKey = "apps.kubeblocks.io/account-name"
+
+	defaultSecretNameTemplate = "{{.Cluster}}-{{.Component}}-account-{{.Account}}"
+
+	accountSecretUsernameKey = "username"
+	accountSecretPasswordKey = "password"
+)
+
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// secretSyncOptions are the options to write the credentials of the account to a Secret, so the
+// applications can mount the credentials without creating the Secret manually.
+type secretSyncOptions struct {
+	sync         bool
+	nameTemplate string
+}
+
+func (s *secretSyncOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.sync, "sync-secret", false, "Write the credentials of the account to a Secret in the namespace of the cluster, so the applications can mount it")
+	cmd.Flags().StringVar(&s.nameTemplate, "secret-name", defaultSecretNameTemplate, "The name template of the Secret written by --sync-secret, {{.Cluster}}, {{.Component}} and {{.Account}} can be used")
+}
+
+// secretName renders the name of the Secret of the account, the characters not allowed in the
+// Secret name are replaced with "-".
+func (s *secretSyncOptions) secretName(clusterName, compName, account string) (string, error) {
+	tmpl, err := template.New("secret-name").Option("missingkey=error").Parse(s.nameTemplate)
+	if err != nil {
+		return "", clierrors.NewValidation("invalid --secret-name %s: %v", s.nameTemplate, err)
+	}
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, map[string]string{"Cluster": clusterName, "Component": compName, "Account": account}); err != nil {
+		return "", clierrors.NewValidation("invalid --secret-name %s: %v", s.nameTemplate, err)
+	}
+	name := strings.Trim(invalidSecretNameChars.ReplaceAllString(strings.ToLower(buf.String()), "-"), "-.")
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", clierrors.NewValidation("invalid Secret name %q rendered by --secret-name: %s", name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// syncAccountSecret creates or updates the Secret with the credentials of the account, the Secret
+// is owned by the cluster so it's deleted with the cluster. The existing Secret not synchronized
+// by kbcli is never overwritten.
+func syncAccountSecret(dynamic dynamic.Interface, namespace, name, clusterName, compName, account, password string) error {
+	c, err := clusterutil.GetClusterByName(dynamic, clusterName, namespace)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    clusterName,
+				constant.KBAppComponentLabelKey: compName,
+				constant.AppManagedByLabelKey:   "kbcli",
+				AccountSecretLabelKey:           "true",
+			},
+			Annotations: map[string]string{accountNameAnnotationKey: account},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: types.ClusterGVR().GroupVersion().String(),
+				Kind:       types.KindCluster,
+				Name:       c.Name,
+				UID:        c.UID,
+			}},
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			accountSecretUsernameKey: account,
+			accountSecretPasswordKey: password,
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return err
+	}
+	client := dynamic.Resource(types.SecretGVR()).Namespace(namespace)
+	existing, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(context.TODO(), &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+	if existing.GetLabels()[AccountSecretLabelKey] != "true" {
+		return clierrors.NewConflict("Secret %s already exists and is not synchronized by kbcli, please specify another --secret-name", name)
+	}
+	// replace the data of the Secret, the stale keys are removed
+	existing.Object["stringData"] = obj["stringData"]
+	delete(existing.Object, "data")
+	existing.SetLabels(secret.Labels)
+	existing.SetAnnotations(secret.Annotations)
+	_, err = client.Update(context.TODO(), existing, metav1.UpdateOptions{})
+	return err
+}
+
+// syncSecretIfNeeded synchronizes the Secret of the account if --sync-secret is specified
+func (o *AccountBaseOptions) syncSecretIfNeeded(s *secretSyncOptions, account, password string) error {
+	if !s.sync {
+		return nil
+	}
+	name, err := s.secretName(o.ClusterName, o.ComponentName, account)
+	if err != nil {
+		return err
+	}
+	if err = syncAccountSecret(o.Dynamic, o.Namespace, name, o.ClusterName, o.ComponentName, account, password); err != nil {
+		return fmt.Errorf("failed to synchronize the credentials of account %s to Secret %s: %v", account, name, err)
+	}
+	if !o.scripted() {
+		fmt.Fprintf(o.Out, "Secret %s is synchronized with the credentials of account %s\n", name, account)
+	}
+	return nil
+}
+
+type ListSecretsOptions struct {
+	clusterName   string
+	namespace     string
+	allNamespaces bool
+	dynamic       dynamic.Interface
+	genericiooptions.IOStreams
+}
+
+func NewListSecretsOptions(streams genericiooptions.IOStreams) *ListSecretsOptions {
+	return &ListSecretsOptions{IOStreams: streams}
+}
+
+func (o *ListSecretsOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "List the Secrets of the accounts across all namespaces")
+}
+
+func (o *ListSecretsOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) > 1 {
+		return errClusterNameNum
+	}
+	if len(args) == 1 {
+		o.clusterName = args[0]
+	}
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.allNamespaces {
+		o.namespace = metav1.NamespaceAll
+	}
+	o.dynamic, err = f.DynamicClient()
+	return err
+}
+
+func (o *ListSecretsOptions) Run() error {
+	selector := fmt.Sprintf("%s=true", AccountSecretLabelKey)
+	if o.clusterName != "" {
+		selector = util.BuildLabelSelectorByNames(selector, []string{o.clusterName})
+	}
+	objs, err := o.dynamic.Resource(types.SecretGVR()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	if len(objs.Items) == 0 {
+		fmt.Fprintln(o.Out, "No account Secrets found")
+		return nil
+	}
+	sort.Slice(objs.Items, func(i, j int) bool {
+		a, b := objs.Items[i], objs.Items[j]
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("NAMESPACE", "NAME", "CLUSTER", "COMPONENT", "ACCOUNT", "CREATED-TIME")
+	for _, obj := range objs.Items {
+		created := obj.GetCreationTimestamp()
+		tbl.AddRow(obj.GetNamespace(), obj.GetName(), obj.GetLabels()[constant.AppInstanceLabelKey],
+			obj.GetLabels()[constant.KBAppComponentLabelKey], obj.GetAnnotations()[accountNameAnnotationKey], util.TimeFormat(&created))
+	}
+	tbl.Print()
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package accounts
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	clusterutil "github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("account secrets", func() {
+	const (
+		namespace   = "test"
+		clusterName = "apple"
+		compName    = "mysql"
+	)
+
+	It("render the secret name", func() {
+		s := &secretSyncOptions{nameTemplate: defaultSecretNameTemplate}
+		Expect(s.secretName(clusterName, compName, "Bob_Admin")).Should(Equal("apple-mysql-account-bob-admin"))
+
+		s.nameTemplate = "{{.Cluster}}-{{.Unknown}}"
+		_, err := s.secretName(clusterName, compName, "bob")
+		Expect(err).Should(MatchError(ContainSubstring("invalid --secret-name")))
+		s.nameTemplate = "{{.Cluster"
+		_, err = s.secretName(clusterName, compName, "bob")
+		Expect(err).Should(MatchError(ContainSubstring("invalid --secret-name")))
+		s.nameTemplate = "___"
+		_, err = s.secretName(clusterName, compName, "bob")
+		Expect(err).Should(MatchError(ContainSubstring("invalid Secret name")))
+	})
+
+	It("synchronize and list the secrets", func() {
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: namespace}}
+		dynamic := testing.FakeDynamicClient(testing.FakeCluster(clusterName, namespace), other)
+		Expect(syncAccountSecret(dynamic, namespace, "apple-mysql-account-bob", clusterName, compName, "bob", "pwd1")).Should(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(clusterutil.GetK8SClientObject(dynamic, secret, types.SecretGVR(), namespace, "apple-mysql-account-bob")).Should(Succeed())
+		Expect(secret.StringData).Should(Equal(map[string]string{accountSecretUsernameKey: "bob", accountSecretPasswordKey: "pwd1"}))
+		Expect(secret.Labels).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, clusterName))
+		Expect(secret.Labels).Should(HaveKeyWithValue(AccountSecretLabelKey, "true"))
+		Expect(secret.OwnerReferences).Should(HaveLen(1))
+		Expect(secret.OwnerReferences[0].Kind).Should(Equal(types.KindCluster))
+
+		By("update the password of the synchronized secret")
+		Expect(syncAccountSecret(dynamic, namespace, "apple-mysql-account-bob", clusterName, compName, "bob", "pwd2")).Should(Succeed())
+		Expect(clusterutil.GetK8SClientObject(dynamic, secret, types.SecretGVR(), namespace, "apple-mysql-account-bob")).Should(Succeed())
+		Expect(secret.StringData[accountSecretPasswordKey]).Should(Equal("pwd2"))
+
+		By("never overwrite the secret not synchronized by kbcli")
+		Expect(syncAccountSecret(dynamic, namespace, "other", clusterName, compName, "bob", "pwd")).Should(MatchError(ContainSubstring("not synchronized by kbcli")))
+
+		By("list the secrets")
+		out := &bytes.Buffer{}
+		o := &ListSecretsOptions{namespace: namespace, dynamic: dynamic, IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out}}
+		Expect(o.Run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("apple-mysql-account-bob"))
+		Expect(out.String()).ShouldNot(ContainSubstring("other"))
+
+		out.Reset()
+		o.clusterName = "banana"
+		Expect(o.Run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("No account Secrets found"))
+
+		Expect(dynamic.Resource(types.SecretGVR()).Namespace(namespace).Delete(context.TODO(), "apple-mysql-account-bob", metav1.DeleteOptions{})).Should(Succeed())
+	})
+})
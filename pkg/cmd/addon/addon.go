@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"path"
 	"sort"
@@ -38,7 +39,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
-	discoverycli "k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -64,6 +64,8 @@ type addonEnableFlags struct {
 	TolerationsSet   []string
 	SetValues        []string
 	Force            bool
+	All              bool
+	Filters          []string
 }
 
 func (r *addonEnableFlags) useDefault() bool {
@@ -105,6 +107,8 @@ func NewAddonCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.C
 		newInstallCmd(f, streams),
 		newUninstallCmd(f, streams),
 		newUpgradeCmd(f, streams),
+		newGetValuesCmd(f, streams),
+		newConfigCmd(f, streams),
 	)
 	return cmd
 }
@@ -173,9 +177,14 @@ func newEnableCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 	// # [--dry-run] # TODO
 
 	cmd := &cobra.Command{
-		Use:               "enable ADDON_NAME",
-		Short:             "Enable an addon.",
-		Args:              cobra.ExactArgs(1),
+		Use:   "enable ADDON_NAME",
+		Short: "Enable an addon.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if o.addonEnableFlags.All {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.AddonGVR()),
 		Example: templates.Examples(`
     	# Enabled "prometheus" addon
@@ -198,13 +207,13 @@ func newEnableCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 
 		# Force enabled "csi-s3" addon
 		kbcli addon enable csi-s3 --force
+
+		# Enabled all addons labeled with "addon.kubeblocks.io/class=database" and their dependencies
+		kbcli addon enable --all --filter class=database
 `),
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.init(args))
-			util.CheckErr(o.fetchAddonObj())
-			util.CheckErr(o.validate())
-			util.CheckErr(o.complete(o, cmd, args))
-			util.CheckErr(o.Run(cmd))
+			util.CheckErr(o.runEnable(cmd, args))
 		},
 	}
 	cmd.Flags().StringArrayVar(&o.addonEnableFlags.MemorySets, "memory", []string{},
@@ -223,11 +232,15 @@ re-enable the addon (More info on how-to resize a PVC: https://kubernetes.io/doc
 		"Sets addon component replica count (--replicas [extraName:]<number>) (can specify multiple if has extra items))")
 	cmd.Flags().StringArrayVar(&o.addonEnableFlags.StorageClassSets, "storage-class", []string{},
 		"Sets addon storage class name (--storage-class [extraName:]<storage class name>) (can specify multiple if has extra items))")
+	util.RegisterFlagCompletionFunc(cmd, "storage-class", util.ResourceNameCompletionFunc(f, types.StorageClassGVR()))
 	cmd.Flags().StringArrayVar(&o.addonEnableFlags.TolerationsSet, "tolerations", []string{},
 		"Sets addon pod tolerations (--tolerations [extraName:]<toleration JSON list items>) (can specify multiple if has extra items))")
 	cmd.Flags().StringArrayVar(&o.addonEnableFlags.SetValues, "set", []string{},
 		"set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2), it's only being processed if addon's type is helm.")
 	cmd.Flags().BoolVar(&o.addonEnableFlags.Force, "force", false, "ignoring the installable restrictions and forcefully enabling.")
+	cmd.Flags().BoolVar(&o.addonEnableFlags.All, "all", false, "enable all addons matching the --filter, the addons that have been enabled are skipped.")
+	cmd.Flags().StringArrayVar(&o.addonEnableFlags.Filters, "filter", []string{},
+		"filter the addons to enable with --all by labels (--filter key=value), the key without a prefix is treated as an addon.kubeblocks.io label (can specify multiple)")
 
 	o.PatchOptions.AddFlags(cmd)
 	return cmd
@@ -275,8 +288,7 @@ func (o *addonCmdOpts) init(args []string) error {
 
 	// setup _KUBE_SERVER_INFO
 	if viper.Get(constant.CfgKeyServerInfo) == nil {
-		cfg, _ := o.Factory.ToRESTConfig()
-		cli, err := discoverycli.NewDiscoveryClientForConfig(cfg)
+		cli, err := o.Factory.ToDiscoveryClient()
 		if err != nil {
 			return err
 		}
@@ -290,6 +302,88 @@ func (o *addonCmdOpts) init(args []string) error {
 	return nil
 }
 
+// runEnable resolves the addons to enable and their dependencies, prints the plan and
+// enables them one by one, the dependencies are enabled with the default install values.
+func (o *addonCmdOpts) runEnable(cmd *cobra.Command, args []string) error {
+	var (
+		names []string
+		err   error
+	)
+	flags := o.addonEnableFlags
+	if flags.All {
+		if !flags.useDefault() || len(flags.SetValues) > 0 {
+			return fmt.Errorf("addon install settings can not be specified when enabling addons with --all")
+		}
+		if names, err = filterAddons(o.dynamic, flags.Filters); err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(o.Out, "No addon found")
+			return nil
+		}
+	} else {
+		names = args
+	}
+
+	plan, err := buildEnablePlan(o.dynamic, names)
+	if err != nil {
+		return err
+	}
+	if len(plan) > 1 {
+		printEnablePlan(o.Out, plan)
+	}
+
+	// the dependencies do not use the settings specified by user
+	depFlags := &addonEnableFlags{Force: flags.Force}
+	for _, item := range plan {
+		if item.enabled && (item.dependency || flags.All) {
+			continue
+		}
+		o.addonEnableFlags = flags
+		if item.dependency {
+			o.addonEnableFlags = depFlags
+		}
+		o.Names = []string{item.name}
+		if err = o.fetchAddonObj(); err != nil {
+			return err
+		}
+		if err = o.validate(); err != nil {
+			// skip the addons which can not be installed in current environment when batch enabling
+			if flags.All && !item.dependency {
+				fmt.Fprintf(o.Out, "%s skip addon %s: %v\n", printer.BoldYellow("Warning:"), item.name, err)
+				continue
+			}
+			return err
+		}
+		if err = o.complete(o, cmd, o.Names); err != nil {
+			return err
+		}
+		if err = o.Run(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printEnablePlan(out io.Writer, plan []enablePlanItem) {
+	fmt.Fprintln(out, "Addons will be enabled in the following order:")
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("ORDER", "ADDON", "REASON", "ACTION")
+	for i, item := range plan {
+		reason := "requested"
+		if item.dependency {
+			reason = "dependency"
+		}
+		action := "enable"
+		if item.enabled {
+			action = "skip (already enabled)"
+		}
+		tbl.AddRow(i+1, item.name, reason, action)
+	}
+	tbl.Print()
+	fmt.Fprintln(out)
+}
+
 func (o *addonCmdOpts) fetchAddonObj() error {
 	ctx := context.TODO()
 	obj, err := o.dynamic.Resource(o.GVR).Get(ctx, o.Names[0], metav1.GetOptions{})
@@ -386,7 +480,7 @@ func addonDescribeHandler(o *addonCmdOpts, cmd *cobra.Command, args []string) er
 	if len(o.addon.GetExtraNames()) > 0 {
 		printer.PrintPairStringToLine("Extras", strings.Join(o.addon.GetExtraNames(), ","), 0)
 	}
-	printer.PrintPairStringToLine("Status", string(o.addon.Status.Phase), 0)
+	printer.PrintPairStringToLine("Status", printer.ColorStatus(string(o.addon.Status.Phase)), 0)
 	var autoInstall bool
 	if o.addon.Spec.Installable != nil {
 		autoInstall = o.addon.Spec.Installable.AutoInstall
@@ -769,8 +863,8 @@ func (o *addonCmdOpts) buildPatch(flags []*pflag.Flag) error {
 }
 
 func addonListRun(o *action.ListOptions) error {
-	// if format is JSON or YAML, use default printer to output the result.
-	if o.Format == printer.JSON || o.Format == printer.YAML {
+	// if format is JSON or YAML, or watch is enabled, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML || o.Watch {
 		_, err := o.Run()
 		return err
 	}
@@ -855,12 +949,12 @@ func addonListRun(o *action.ListOptions) error {
 	}
 
 	if o.Format == printer.Wide {
-		if err = printer.PrintTable(o.Out, nil, printRows,
+		if err = printer.PrintTableWithOptions(o.Out, o.Format, &o.TableOptions, printRows,
 			"NAME", "TYPE", "PROVIDER", "STATUS", "AUTO-INSTALL", "AUTO-INSTALLABLE-SELECTOR", "EXTRAS"); err != nil {
 			return err
 		}
 	} else {
-		if err = printer.PrintTable(o.Out, nil, printRows,
+		if err = printer.PrintTableWithOptions(o.Out, o.Format, &o.TableOptions, printRows,
 			"NAME", "TYPE", "PROVIDER", "STATUS", "AUTO-INSTALL"); err != nil {
 			return err
 		}
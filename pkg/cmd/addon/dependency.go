@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// enablePlanItem is an addon to be enabled, the dependency is enabled with the default
+// install values because it's not specified by user explicitly.
+type enablePlanItem struct {
+	name       string
+	dependency bool
+	// enabled is true if the dependency has already been enabled and can be skipped
+	enabled bool
+}
+
+// getAddonDependencies returns the addon names the addon depends on, which are declared by annotation
+func getAddonDependencies(addon *extensionsv1alpha1.Addon) []string {
+	if addon.Annotations == nil {
+		return nil
+	}
+	var deps []string
+	for _, d := range strings.Split(addon.Annotations[types.AddonDependenciesAnnotationKey], ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+func addonEnabled(addon *extensionsv1alpha1.Addon) bool {
+	return addon.Status.Phase == extensionsv1alpha1.AddonEnabled || addon.Spec.InstallSpec.GetEnabled()
+}
+
+func getAddon(dynamic dynamic.Interface, name string) (*extensionsv1alpha1.Addon, error) {
+	obj, err := dynamic.Resource(types.AddonGVR()).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addon := &extensionsv1alpha1.Addon{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, addon); err != nil {
+		return nil, err
+	}
+	return addon, nil
+}
+
+// buildEnablePlan resolves the dependencies of the addons recursively and returns the addons
+// in the order they should be enabled, the dependencies always come before the dependents.
+func buildEnablePlan(dynamic dynamic.Interface, names []string) ([]enablePlanItem, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		plan      []enablePlanItem
+		states    = map[string]int{}
+		requested = map[string]bool{}
+		visit     func(name string, path []string) error
+	)
+	for _, name := range names {
+		requested[name] = true
+	}
+
+	visit = func(name string, path []string) error {
+		switch states[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular addon dependency detected: %s", strings.Join(append(path, name), " -> "))
+		}
+		states[name] = visiting
+		addon, err := getAddon(dynamic, name)
+		if err != nil {
+			if len(path) > 0 {
+				return fmt.Errorf("failed to get addon %s required by %s: %v", name, path[len(path)-1], err)
+			}
+			return err
+		}
+		for _, dep := range getAddonDependencies(addon) {
+			if err = visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		states[name] = visited
+		plan = append(plan, enablePlanItem{
+			name:       name,
+			dependency: !requested[name],
+			enabled:    addonEnabled(addon),
+		})
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// filterAddons returns the names of the addons whose labels match all the filters, the filter
+// is in the format of key=value, and the key without a prefix is treated as an addon label
+// such as class=database matching label addon.kubeblocks.io/class=database.
+func filterAddons(dynamic dynamic.Interface, filters []string) ([]string, error) {
+	selector := map[string]string{}
+	for _, f := range filters {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid filter %s, should be in the format of key=value", f)
+		}
+		key := kv[0]
+		if !strings.Contains(key, "/") {
+			key = fmt.Sprintf("%s/%s", types.AddonLabelPrefix, key)
+		}
+		selector[key] = kv[1]
+	}
+
+	objs, err := dynamic.Resource(types.AddonGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, obj := range objs.Items {
+		labels := obj.GetLabels()
+		matched := true
+		for k, v := range selector {
+			if labels[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			names = append(names, obj.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("addon dependency test", func() {
+	newAddon := func(name string, deps string, labels map[string]string, enabled bool) *extensionsv1alpha1.Addon {
+		addon := testing.FakeAddon(name)
+		addon.Annotations = map[string]string{types.AddonDependenciesAnnotationKey: deps}
+		addon.Labels = labels
+		if enabled {
+			addon.Status.Phase = extensionsv1alpha1.AddonEnabled
+		}
+		return addon
+	}
+
+	It("build enable plan", func() {
+		dynamic := fake.NewSimpleDynamicClient(scheme.Scheme,
+			newAddon("a", "b, c", nil, false),
+			newAddon("b", "c", nil, false),
+			newAddon("c", "", nil, true),
+			newAddon("d", "a", nil, false))
+		plan, err := buildEnablePlan(dynamic, []string{"a"})
+		Expect(err).Should(Succeed())
+		Expect(plan).Should(Equal([]enablePlanItem{
+			{name: "c", dependency: true, enabled: true},
+			{name: "b", dependency: true},
+			{name: "a"},
+		}))
+
+		plan, err = buildEnablePlan(dynamic, []string{"d", "b"})
+		Expect(err).Should(Succeed())
+		Expect(plan).Should(HaveLen(4))
+		Expect(plan[3]).Should(Equal(enablePlanItem{name: "d"}))
+
+		By("missing dependency")
+		dynamic = fake.NewSimpleDynamicClient(scheme.Scheme, newAddon("a", "not-existed", nil, false))
+		_, err = buildEnablePlan(dynamic, []string{"a"})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("detect circular dependency", func() {
+		dynamic := fake.NewSimpleDynamicClient(scheme.Scheme,
+			newAddon("a", "b", nil, false),
+			newAddon("b", "a", nil, false))
+		_, err := buildEnablePlan(dynamic, []string{"a"})
+		Expect(err).Should(MatchError(ContainSubstring("a -> b -> a")))
+	})
+
+	It("filter addons", func() {
+		objs := []runtime.Object{
+			newAddon("mysql", "", map[string]string{"addon.kubeblocks.io/class": "database"}, false),
+			newAddon("redis", "", map[string]string{"addon.kubeblocks.io/class": "database", "kubeblocks.io/provider": "community"}, false),
+			newAddon("grafana", "", map[string]string{"addon.kubeblocks.io/class": "monitoring"}, false),
+		}
+		dynamic := fake.NewSimpleDynamicClient(scheme.Scheme, objs...)
+		names, err := filterAddons(dynamic, []string{"class=database"})
+		Expect(err).Should(Succeed())
+		Expect(names).Should(Equal([]string{"mysql", "redis"}))
+
+		names, err = filterAddons(dynamic, []string{"class=database", "kubeblocks.io/provider=community"})
+		Expect(err).Should(Succeed())
+		Expect(names).Should(Equal([]string{"redis"}))
+
+		names, err = filterAddons(dynamic, nil)
+		Expect(err).Should(Succeed())
+		Expect(names).Should(HaveLen(3))
+
+		_, err = filterAddons(dynamic, []string{"class"})
+		Expect(err).Should(HaveOccurred())
+	})
+})
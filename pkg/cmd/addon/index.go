@@ -92,7 +92,8 @@ func newIndexCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Short: "Manage custom addon indexes",
 		Long:  "Manage which repositories are used to discover and install addon from.",
 		Args:  cobra.NoArgs,
-		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(util.RunParentPersistentPreRun(cmd, args))
 			util.CheckErr(addDefaultIndex())
 		},
 	}
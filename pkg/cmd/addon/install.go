@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
@@ -56,6 +57,12 @@ var addonInstallExample = templates.Examples(`
 
 	# install an addon with a specified version default index
 	kbcli addon install apecloud-mysql --version 0.7.0
+
+	# install a community addon from an OCI registry, the version is required
+	kbcli addon install my-addon --source oci://registry-1.docker.io/my-org/my-addon --version 0.1.0
+
+	# install a community addon from a Helm chart repository
+	kbcli addon install my-addon --source https://my-org.github.io/helm-charts --version 0.1.0
 `)
 
 type baseOption struct {
@@ -91,6 +98,9 @@ type installOption struct {
 	version string
 	// the index name, if not specified use `kubeblocks` default
 	index string
+	// source is an external Helm chart repository or OCI registry, the addon will be
+	// installed from it directly instead of from the index if specified
+	source string
 
 	addon *extensionsv1alpha1.Addon
 }
@@ -126,6 +136,7 @@ func newInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().BoolVar(&o.force, "force", false, "force install the addon and ignore the version check")
 	cmd.Flags().StringVar(&o.version, "version", "", "specify the addon version")
 	cmd.Flags().StringVar(&o.index, "index", types.DefaultIndexName, "specify the addon index index, use 'kubeblocks' by default")
+	cmd.Flags().StringVar(&o.source, "source", "", "install the addon from an external Helm chart repository (http/https) or OCI registry (oci://) instead of the index")
 
 	return cmd
 }
@@ -142,6 +153,11 @@ func (o *installOption) Complete() error {
 		return fmt.Errorf("the version %s does not comply with the standards", o.version)
 	}
 
+	if o.source != "" {
+		o.addon, err = buildAddonFromSource(o.name, o.source, o.version)
+		return err
+	}
+
 	dir, err := util.GetCliAddonDir()
 	if err != nil {
 		return err
@@ -231,6 +247,57 @@ func (o *installOption) Run() error {
 	return nil
 }
 
+// buildAddonFromSource builds a helm type addon whose chart is located in an external
+// Helm chart repository or OCI registry, the version must be specified to pin the chart.
+func buildAddonFromSource(name, source, version string) (*extensionsv1alpha1.Addon, error) {
+	if version == "" {
+		return nil, fmt.Errorf("--version must be specified when installing addon from source %s", source)
+	}
+	helmSpec := &extensionsv1alpha1.HelmTypeInstallSpec{}
+	source = strings.TrimSuffix(source, "/")
+	switch {
+	case strings.HasPrefix(source, "oci://"):
+		// helm pulls the chart from the OCI registry with the --version option
+		helmSpec.ChartLocationURL = source
+		if !strings.HasSuffix(source, "/"+name) {
+			helmSpec.ChartLocationURL = fmt.Sprintf("%s/%s", source, name)
+		}
+		helmSpec.InstallOptions = extensionsv1alpha1.HelmInstallOptions{"version": version}
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		// use the chart archive URL in the Helm chart repository
+		helmSpec.ChartLocationURL = fmt.Sprintf("%s/%s-%s.tgz", source, name, version)
+	default:
+		return nil, fmt.Errorf("unsupported addon source %s, only oci://, http:// and https:// are supported", source)
+	}
+
+	addon := &extensionsv1alpha1.Addon{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: types.ExtensionsAPIGroup + "/" + types.ExtensionsAPIVersion,
+			Kind:       "Addon",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				constant.AppVersionLabelKey: version,
+				types.AddonProviderLabelKey: types.AddonCommunityProvider,
+			},
+			Annotations: map[string]string{
+				types.AddonSourceAnnotationKey: source,
+			},
+		},
+		Spec: extensionsv1alpha1.AddonSpec{
+			Description: fmt.Sprintf("%s addon installed from %s", name, source),
+			Type:        extensionsv1alpha1.HelmType,
+			Helm:        helmSpec,
+			DefaultInstallValues: []extensionsv1alpha1.AddonDefaultInstallSpecItem{
+				{AddonInstallSpec: extensionsv1alpha1.AddonInstallSpec{Enabled: true}},
+			},
+			InstallSpec: &extensionsv1alpha1.AddonInstallSpec{Enabled: true},
+		},
+	}
+	return addon, nil
+}
+
 // validateVersion will check if the kbVersion meets the version constraint defined by annotations
 func validateVersion(annotations, kbVersion string) (bool, error) {
 	constraint, err := semver.NewConstraint(annotations)
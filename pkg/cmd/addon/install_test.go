@@ -140,4 +140,23 @@ var _ = Describe("index test", func() {
 		option.force = true
 		Expect(option.Validate()).Should(Succeed())
 	})
+	It("test install from source", func() {
+		option := newInstallOption(tf, streams)
+		option.name = "my-addon"
+		option.source = "oci://registry-1.docker.io/my-org"
+		Expect(option.Complete()).Should(HaveOccurred())
+
+		option.version = "0.1.0"
+		Expect(option.Complete()).Should(Succeed())
+		Expect(option.addon.Spec.Helm.ChartLocationURL).Should(Equal("oci://registry-1.docker.io/my-org/my-addon"))
+		Expect(option.addon.Spec.Helm.InstallOptions["version"]).Should(Equal("0.1.0"))
+		Expect(option.addon.Labels[types.AddonProviderLabelKey]).Should(Equal(types.AddonCommunityProvider))
+
+		option.source = "https://my-org.github.io/helm-charts/"
+		Expect(option.Complete()).Should(Succeed())
+		Expect(option.addon.Spec.Helm.ChartLocationURL).Should(Equal("https://my-org.github.io/helm-charts/my-addon-0.1.0.tgz"))
+
+		option.source = "ftp://my-org.github.io/helm-charts"
+		Expect(option.Complete()).Should(HaveOccurred())
+	})
 })
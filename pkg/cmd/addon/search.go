@@ -50,7 +50,8 @@ func newSearchCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "search",
 		Short: "search the addon from index",
 		Args:  cobra.ExactArgs(1),
-		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(util.RunParentPersistentPreRun(cmd, args))
 			util.CheckErr(util.EnableLogToFile(cmd.Flags()))
 		},
 		Run: func(_ *cobra.Command, args []string) {
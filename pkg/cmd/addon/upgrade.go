@@ -60,6 +60,9 @@ var addonUpgradeExample = templates.Examples(`
 
 	# non-inplace upgrade an addon with a specified addon name
 	kbcli addon upgrade apecloud-mysql --inplace=false --name apecloud-mysql-0.7.0
+
+	# upgrade a community addon installed from an external source to a specified version
+	kbcli addon upgrade my-addon --source oci://registry-1.docker.io/my-org/my-addon --version 0.2.0
 `)
 
 // upgradeOption storage the info to upgrade an addon
@@ -103,6 +106,7 @@ func newUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().BoolVar(&o.force, "force", false, "force upgrade the addon and ignore the version check")
 	cmd.Flags().StringVar(&o.version, "version", "", "specify the addon version")
 	cmd.Flags().StringVar(&o.index, "index", types.DefaultIndexName, "specify the addon index index, use 'kubeblocks' by default")
+	cmd.Flags().StringVar(&o.source, "source", "", "upgrade the addon from an external Helm chart repository (http/https) or OCI registry (oci://) instead of the index")
 	cmd.Flags().BoolVar(&o.inplace, "inplace", true, "when inplace is false, it will retain the existing addon and reinstall the new version of the addon, otherwise the upgrade will be in-place. The default is true.")
 	cmd.Flags().StringVar(&o.rename, "name", "", "name is the new version addon name need to set by user when inplace is false, it also will be used as resourceNamePrefix of an addon with multiple version.")
 	return cmd
@@ -118,6 +122,11 @@ func (o *upgradeOption) Complete() error {
 		return fmt.Errorf("addon %s not found. please use 'kbcli addon install %s' first", o.name, o.name)
 	}
 	o.currentVersion = addon.Labels[constant.AppVersionLabelKey]
+	// the addon built from --source is labeled as a community addon, keep the provider of the
+	// installed addon such as the addons installed from the index
+	if provider, ok := addon.Labels[types.AddonProviderLabelKey]; ok && o.source != "" {
+		o.addon.Labels[types.AddonProviderLabelKey] = provider
+	}
 	return nil
 }
 
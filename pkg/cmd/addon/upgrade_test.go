@@ -32,6 +32,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 
 	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
 )
 
 var _ = Describe("test addon upgrade", func() {
@@ -70,6 +71,18 @@ var _ = Describe("test addon upgrade", func() {
 		Expect(option.Complete()).Should(Succeed())
 	})
 
+	It("keep the provider of the installed addon when upgrade from the source", func() {
+		installed := testing.FakeAddon("apecloud-mysql")
+		installed.Labels = map[string]string{types.AddonProviderLabelKey: "apecloud"}
+		tf.FakeDynamicClient = testing.FakeDynamicClient(installed)
+		option := newUpgradeOption(tf, streams)
+		option.name = "apecloud-mysql"
+		option.source = "oci://registry-1.docker.io/my-org/apecloud-mysql"
+		option.version = "0.8.0"
+		Expect(option.Complete()).Should(Succeed())
+		Expect(option.addon.Labels).Should(HaveKeyWithValue(types.AddonProviderLabelKey, "apecloud"))
+	})
+
 	It("test upgrade validate", func() {
 		option := newUpgradeOption(tf, streams)
 		option.addon = &extensionsv1alpha1.Addon{
@@ -0,0 +1,429 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/strvals"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+var (
+	addonGetValuesExample = templates.Examples(`
+	# get the values set by user of the prometheus addon
+	kbcli addon get-values prometheus
+
+	# get all the computed values of the prometheus addon in JSON
+	kbcli addon get-values prometheus --all -o json`)
+
+	addonConfigExample = templates.Examples(`
+	# set the retention of the prometheus addon, a diff of the values is shown before applying
+	kbcli addon config prometheus --set server.retention=7d
+
+	# set the admin password of the grafana addon without approval
+	kbcli addon config grafana --set adminPassword=mypassword --auto-approve
+
+	# remove the value set by user, the default value of the chart is used
+	kbcli addon config prometheus --unset server.retention
+
+	# preview the changes of the values without applying them
+	kbcli addon config prometheus --set alertmanager.replicaCount=2 --dry-run`)
+)
+
+// getAddonRelease gets the helm release of the addon, which is installed by KubeBlocks
+// in the KubeBlocks namespace
+var getAddonRelease = func(o *addonValuesOptions) (*release.Release, error) {
+	namespace, err := util.GetKubeBlocksNamespace(o.client)
+	if err != nil {
+		return nil, err
+	}
+	actionConfig, err := helm.NewActionConfig(helm.NewConfig(namespace, o.kubeConfig, o.kubeContext, klog.V(1).Enabled()))
+	if err != nil {
+		return nil, err
+	}
+	return action.NewGet(actionConfig).Run(addonReleaseName(o.name))
+}
+
+type addonValuesOptions struct {
+	genericiooptions.IOStreams
+	Factory cmdutil.Factory
+	dynamic dynamic.Interface
+	client  kubernetes.Interface
+
+	name        string
+	kubeConfig  string
+	kubeContext string
+	addon       *extensionsv1alpha1.Addon
+
+	// flags of get-values
+	all    bool
+	output string
+
+	// flags of config
+	setValues   []string
+	unsetKeys   []string
+	dryRun      bool
+	autoApprove bool
+}
+
+func newGetValuesCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &addonValuesOptions{Factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "get-values ADDON_NAME",
+		Short:             "Get the helm values of an addon.",
+		Args:              cobra.ExactArgs(1),
+		Example:           addonGetValuesExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.AddonGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(cmd, args))
+			util.CheckErr(o.runGetValues())
+		},
+	}
+	cmd.Flags().BoolVar(&o.all, "all", false, "Get all the computed values of the installed addon, including the defaults of the chart")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "yaml", "Output format, one of yaml|json")
+	util.RegisterFlagCompletionFunc(cmd, "output", util.ValuesCompletionFunc("yaml", "json"))
+	return cmd
+}
+
+func newConfigCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &addonValuesOptions{Factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "config ADDON_NAME",
+		Short:             "Update the helm values of an addon.",
+		Args:              cobra.ExactArgs(1),
+		Example:           addonConfigExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.AddonGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(cmd, args))
+			util.CheckErr(o.runConfig())
+		},
+	}
+	cmd.Flags().StringArrayVar(&o.setValues, "set", []string{}, "Set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	cmd.Flags().StringArrayVar(&o.unsetKeys, "unset", []string{}, "Remove the values set by user with the keys (can specify multiple)")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only show the diff of the values without applying them")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before updating the values")
+	return cmd
+}
+
+func (o *addonValuesOptions) complete(cmd *cobra.Command, args []string) error {
+	var err error
+	o.name = args[0]
+	if o.dynamic == nil {
+		if o.dynamic, err = o.Factory.DynamicClient(); err != nil {
+			return err
+		}
+	}
+	if o.client == nil {
+		if o.client, err = o.Factory.KubernetesClientSet(); err != nil {
+			return err
+		}
+	}
+	if cmd != nil {
+		o.kubeConfig, _ = cmd.Flags().GetString("kubeconfig")
+		o.kubeContext, _ = cmd.Flags().GetString("context")
+	}
+	o.addon = &extensionsv1alpha1.Addon{}
+	if err = cluster.GetK8SClientObject(o.dynamic, o.addon, types.AddonGVR(), "", o.name); err != nil {
+		return err
+	}
+	if o.addon.Spec.Type != extensionsv1alpha1.HelmType || o.addon.Spec.Helm == nil {
+		return fmt.Errorf("addon %s is not a helm type addon", o.name)
+	}
+	return nil
+}
+
+func (o *addonValuesOptions) runGetValues() error {
+	var (
+		values map[string]interface{}
+		err    error
+	)
+	if o.all {
+		rel, err := getAddonRelease(o)
+		if err != nil {
+			return fmt.Errorf("failed to get the helm release of addon %s, make sure it is enabled: %v", o.name, err)
+		}
+		if values, err = chartutil.CoalesceValues(rel.Chart, rel.Config); err != nil {
+			return err
+		}
+	} else if values, err = parseAddonValues(o.addon.Spec.Helm.InstallValues); err != nil {
+		return err
+	}
+
+	var data []byte
+	switch o.output {
+	case "yaml":
+		data, err = yaml.Marshal(values)
+	case "json":
+		data, err = json.MarshalIndent(values, "", "  ")
+		data = append(data, '\n')
+	default:
+		return fmt.Errorf("invalid output format %s, should be one of yaml|json", o.output)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+func (o *addonValuesOptions) runConfig() error {
+	if len(o.setValues) == 0 && len(o.unsetKeys) == 0 {
+		return fmt.Errorf("at least one of --set and --unset should be specified")
+	}
+	installValues := o.addon.Spec.Helm.InstallValues
+	oldValues, err := parseAddonValues(installValues)
+	if err != nil {
+		return err
+	}
+	installValues.SetValues = mergeSetValues(installValues.SetValues, o.setValues, o.unsetKeys)
+	newValues, err := parseAddonValues(installValues)
+	if err != nil {
+		return err
+	}
+
+	// validate the values against the chart of the installed addon
+	rel, err := getAddonRelease(o)
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "%s failed to get the helm release of addon %s, the values are not validated: %v\n",
+			printer.BoldYellow("Warning:"), o.name, err)
+	} else {
+		warnings, err := validateAddonValues(rel.Chart, newValues)
+		if err != nil {
+			return fmt.Errorf("invalid values of addon %s: %v", o.name, err)
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(o.ErrOut, "%s %s\n", printer.BoldYellow("Warning:"), w)
+		}
+	}
+
+	// show the diff of the values set by user
+	oldData, err := yaml.Marshal(oldValues)
+	if err != nil {
+		return err
+	}
+	newData, err := yaml.Marshal(newValues)
+	if err != nil {
+		return err
+	}
+	diff, err := util.GetUnifiedDiffString(string(oldData), string(newData), "current", "new", 3)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintf(o.Out, "The values of addon %s are not changed\n", o.name)
+		return nil
+	}
+	util.DisplayDiffWithColor(o.Out, diff)
+	if o.dryRun {
+		return nil
+	}
+	if !o.autoApprove {
+		if err = prompt.Confirm(nil, o.In, "", "Please type \"yes\" to update the values:"); err != nil {
+			return err
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"helm": map[string]interface{}{
+				"installValues": map[string]interface{}{
+					"setValues": installValues.SetValues,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = o.dynamic.Resource(types.AddonGVR()).Patch(context.TODO(), o.name, ktypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Addon %s values updated, they will be applied by KubeBlocks soon\n", o.name)
+	return nil
+}
+
+// addonReleaseName returns the helm release name of the addon installed by KubeBlocks
+func addonReleaseName(name string) string {
+	return fmt.Sprintf("kb-addon-%s", name)
+}
+
+// parseAddonValues parses the values set by user in the install values of the addon
+func parseAddonValues(installValues extensionsv1alpha1.HelmInstallValues) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, v := range installValues.SetValues {
+		if err := strvals.ParseInto(v, values); err != nil {
+			return nil, fmt.Errorf("failed to parse value %s: %v", v, err)
+		}
+	}
+	for _, v := range installValues.SetJSONValues {
+		if err := strvals.ParseJSON(v, values); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON value %s: %v", v, err)
+		}
+	}
+	return values, nil
+}
+
+// mergeSetValues merges the new set values into the existing ones, the existing value with the same key
+// is replaced in place, and the values with the unset keys are removed.
+func mergeSetValues(existing, set, unset []string) []string {
+	var (
+		keys   []string
+		values = map[string]string{}
+	)
+	add := func(entries []string) {
+		for _, entry := range entries {
+			for _, kv := range splitSetValue(entry) {
+				key := strings.SplitN(kv, "=", 2)[0]
+				if _, ok := values[key]; !ok {
+					keys = append(keys, key)
+				}
+				values[key] = kv
+			}
+		}
+	}
+	add(existing)
+	add(set)
+	for _, key := range unset {
+		delete(values, key)
+	}
+	var result []string
+	for _, key := range keys {
+		if kv, ok := values[key]; ok {
+			result = append(result, kv)
+		}
+	}
+	return result
+}
+
+// splitSetValue splits the set value into key=value pairs by the commas, the escaped commas
+// and the commas in the list values such as key={a,b} are not split.
+func splitSetValue(s string) []string {
+	var (
+		res   []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(res, s[start:])
+}
+
+// validateAddonValues validates the values set by user against the chart, the values are validated by
+// the JSON schema if the chart has one, otherwise the types of the values are checked against the defaults
+// of the chart, and the keys not found in the defaults are returned as warnings.
+func validateAddonValues(chrt *chart.Chart, values map[string]interface{}) ([]string, error) {
+	if len(chrt.Schema) > 0 {
+		coalesced, err := chartutil.CoalesceValues(chrt, values)
+		if err != nil {
+			return nil, err
+		}
+		return nil, chartutil.ValidateAgainstSchema(chrt, coalesced)
+	}
+	var warnings []string
+	if err := validateValueTypes(chrt.Values, values, "", &warnings); err != nil {
+		return nil, err
+	}
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
+func validateValueTypes(defaults, values map[string]interface{}, prefix string, warnings *[]string) error {
+	for key, value := range values {
+		path := prefix + key
+		defaultValue, ok := defaults[key]
+		if !ok {
+			*warnings = append(*warnings, fmt.Sprintf("%s is not found in the default values of the chart", path))
+			continue
+		}
+		if value == nil || defaultValue == nil {
+			continue
+		}
+		if m, ok := value.(map[string]interface{}); ok {
+			dm, ok := defaultValue.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s should be a %s, but got a map", path, valueKind(defaultValue))
+			}
+			if err := validateValueTypes(dm, m, path+".", warnings); err != nil {
+				return err
+			}
+			continue
+		}
+		// the scalar values are rendered as strings in the templates
+		if kind := valueKind(defaultValue); kind != "string" && kind != valueKind(value) {
+			return fmt.Errorf("%s should be a %s, but got %v", path, kind, value)
+		}
+	}
+	return nil
+}
+
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	case bool:
+		return "bool"
+	case int, int32, int64, float32, float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("addon values", func() {
+	const addonName = "prometheus"
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+		o       *addonValuesOptions
+		rel     *release.Release
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = cmdtesting.NewTestFactory().WithNamespace(testNamespace)
+		addon := testing.FakeAddon(addonName)
+		addon.Spec.Type = extensionsv1alpha1.HelmType
+		addon.Spec.Helm = &extensionsv1alpha1.HelmTypeInstallSpec{
+			ChartLocationURL: "file:///prometheus.tgz",
+			InstallValues: extensionsv1alpha1.HelmInstallValues{
+				SetValues: []string{"server.retention=15d,alertmanager.enabled=true"},
+			},
+		}
+		tf.FakeDynamicClient = testing.FakeDynamicClient(addon)
+		o = &addonValuesOptions{
+			Factory:     tf,
+			IOStreams:   streams,
+			dynamic:     tf.FakeDynamicClient,
+			client:      testing.FakeClientSet(),
+			output:      "yaml",
+			autoApprove: true,
+		}
+		rel = &release.Release{
+			Chart: &chart.Chart{
+				Metadata: &chart.Metadata{Name: addonName},
+				Values: map[string]interface{}{
+					"server":       map[string]interface{}{"retention": "15d", "replicaCount": 1},
+					"alertmanager": map[string]interface{}{"enabled": false},
+				},
+			},
+			Config: map[string]interface{}{"server": map[string]interface{}{"retention": "15d"}},
+		}
+		getAddonRelease = func(o *addonValuesOptions) (*release.Release, error) {
+			return rel, nil
+		}
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("new commands", func() {
+		Expect(newGetValuesCmd(tf, streams)).ShouldNot(BeNil())
+		Expect(newConfigCmd(tf, streams)).ShouldNot(BeNil())
+	})
+
+	It("get values", func() {
+		Expect(o.complete(nil, []string{addonName})).Should(Succeed())
+		Expect(o.runGetValues()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("retention: 15d"))
+		Expect(out.String()).ShouldNot(ContainSubstring("replicaCount"))
+
+		out.Reset()
+		o.all = true
+		o.output = "json"
+		Expect(o.runGetValues()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring(`"replicaCount": 1`))
+	})
+
+	It("config values", func() {
+		Expect(o.complete(nil, []string{addonName})).Should(Succeed())
+		Expect(o.runConfig()).Should(HaveOccurred())
+
+		By("set values with diff")
+		o.setValues = []string{"server.retention=7d", "server.replicaCount=2"}
+		o.unsetKeys = []string{"alertmanager.enabled"}
+		Expect(o.runConfig()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("-  retention: 15d"))
+		Expect(out.String()).Should(ContainSubstring("+  retention: 7d"))
+		addon := &extensionsv1alpha1.Addon{}
+		Expect(cluster.GetK8SClientObject(o.dynamic, addon, types.AddonGVR(), "", addonName)).Should(Succeed())
+		Expect(addon.Spec.Helm.InstallValues.SetValues).Should(Equal([]string{"server.retention=7d", "server.replicaCount=2"}))
+
+		By("no change")
+		Expect(o.complete(nil, []string{addonName})).Should(Succeed())
+		out.Reset()
+		Expect(o.runConfig()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("not changed"))
+
+		By("invalid value type")
+		o.setValues = []string{"server.replicaCount=abc"}
+		Expect(o.runConfig()).Should(MatchError(ContainSubstring("server.replicaCount should be a number")))
+
+		By("dry run does not update the addon")
+		o.setValues = []string{"server.retention=1d"}
+		o.dryRun = true
+		Expect(o.runConfig()).Should(Succeed())
+		obj, err := o.dynamic.Resource(types.AddonGVR()).Get(context.TODO(), addonName, metav1.GetOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fmt.Sprint(obj.Object["spec"])).Should(ContainSubstring("server.retention=7d"))
+	})
+
+	It("validate values by schema", func() {
+		rel.Chart.Schema = []byte(`{"properties": {"server": {"properties": {"replicaCount": {"type": "integer", "minimum": 1}}}}}`)
+		_, err := validateAddonValues(rel.Chart, map[string]interface{}{"server": map[string]interface{}{"replicaCount": int64(0)}})
+		Expect(err).Should(HaveOccurred())
+		warnings, err := validateAddonValues(&chart.Chart{Values: map[string]interface{}{}}, map[string]interface{}{"unknown": "v"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(warnings).Should(HaveLen(1))
+	})
+
+	It("merge set values", func() {
+		Expect(splitSetValue(`a=1,b={x,y},c=d\,e`)).Should(Equal([]string{"a=1", "b={x,y}", `c=d\,e`}))
+		Expect(mergeSetValues([]string{"a=1,b=2"}, []string{"a=3", "c=4"}, []string{"b"})).Should(Equal([]string{"a=3", "c=4"}))
+	})
+})
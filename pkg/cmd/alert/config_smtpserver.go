@@ -25,6 +25,8 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -51,9 +53,9 @@ func newConfigSMTPServerCmd(f cmdutil.Factory, streams genericiooptions.IOStream
 		Short:   "Set smtp server config",
 		Example: configSMTPServerExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(f))
-			cmdutil.CheckErr(o.validate())
-			cmdutil.CheckErr(o.run())
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
 		},
 	}
 
@@ -480,11 +480,10 @@ func (o *createOptions) run() error {
 }
 
 func registerFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
-	util.CheckErr(cmd.RegisterFlagCompletionFunc(
-		providerFlagName,
+	util.RegisterFlagCompletionFunc(cmd, providerFlagName,
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.CompGetResource(f, util.GVRToString(types.StorageProviderGVR()), toComplete), cobra.ShellCompDirectiveNoFileComp
-		}))
+		})
 	util.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"access-method",
 		cobra.FixedCompletions(allowedAccessMethods, cobra.ShellCompDirectiveNoFileComp)))
@@ -52,8 +52,8 @@ func newDeleteCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 		Example:           deleteExample,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupRepoGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(completeForDeleteBackupRepo(o, args))
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(completeForDeleteBackupRepo(o, args))
+			util.CheckErr(o.Run())
 		},
 	}
 	return cmd
@@ -76,8 +76,8 @@ func newDescribeCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupRepoGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.run())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
 		},
 	}
 	return cmd
@@ -148,7 +148,7 @@ func (o *describeBackupRepoOptions) printBackupRepo(backupRepo *dpv1alpha1.Backu
 	}
 
 	printer.PrintLine("\nStatus:")
-	printer.PrintPairStringToLine("Phase", string(backupRepo.Status.Phase))
+	printer.PrintPairStringToLine("Phase", printer.ColorStatus(string(backupRepo.Status.Phase)))
 	printer.PrintPairStringToLine("BackupPVCName", backupRepo.Status.BackupPVCName)
 	printer.PrintPairStringToLine("ObservedGeneration", fmt.Sprintf("%d", backupRepo.Status.ObservedGeneration))
 
@@ -41,10 +41,8 @@ var _ = Describe("backuprepo describe command", func() {
 		tf = cmdtesting.NewTestFactory().WithNamespace(testing.Namespace)
 		tf.Client = &clientfake.RESTClient{}
 		repoObj := testing.FakeBackupRepo("test-backuprepo", false)
-		backupObj := testing.FakeBackup("backup1")
+		backupObj := testing.FakeBackupWithPhase("backup1", dpv1alpha1.BackupPhaseCompleted)
 		backupObj.Labels = map[string]string{associatedBackupRepoKey: "test-backuprepo"}
-		backupObj.Status.Phase = dpv1alpha1.BackupPhaseCompleted
-		backupObj.Status.TotalSize = "123456"
 		tf.FakeDynamicClient = testing.FakeDynamicClient(repoObj, backupObj)
 	})
 
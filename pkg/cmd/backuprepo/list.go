@@ -64,8 +64,8 @@ func newListCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupRepoGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Names = args
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(printBackupRepoList(o))
+			util.CheckErr(o.Complete())
+			util.CheckErr(printBackupRepoList(o))
 		},
 	}
 	o.AddFlags(cmd)
@@ -82,8 +82,8 @@ func (o *listBackupRepoOptions) Complete() error {
 }
 
 func printBackupRepoList(o *listBackupRepoOptions) error {
-	// if format is JSON or YAML, use default printer to output the result.
-	if o.Format == printer.JSON || o.Format == printer.YAML {
+	// if format is JSON or YAML, or watch is enabled, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML || o.Watch {
 		_, err := o.Run()
 		return err
 	}
@@ -140,7 +140,7 @@ func printBackupRepoList(o *listBackupRepoOptions) error {
 		return nil
 	}
 
-	if err = printer.PrintTable(o.Out, nil, printRows,
+	if err = printer.PrintTableWithOptions(o.Out, o.Format, &o.TableOptions, printRows,
 		"NAME", "STATUS", "STORAGE-PROVIDER", "ACCESS-METHOD", "DEFAULT", "BACKUPS", "TOTAL-SIZE"); err != nil {
 		return err
 	}
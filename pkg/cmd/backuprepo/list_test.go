@@ -40,10 +40,8 @@ var _ = Describe("backuprepo list command", func() {
 		tf = cmdtesting.NewTestFactory().WithNamespace(testing.Namespace)
 		repoObj1 := testing.FakeBackupRepo("test-backuprepo", false)
 		repoObj2 := testing.FakeBackupRepo("default-backuprepo", true)
-		backupObj := testing.FakeBackup("backup1")
+		backupObj := testing.FakeBackupWithPhase("backup1", dpv1alpha1.BackupPhaseCompleted)
 		backupObj.Labels = map[string]string{associatedBackupRepoKey: "default-backuprepo"}
-		backupObj.Status.Phase = dpv1alpha1.BackupPhaseCompleted
-		backupObj.Status.TotalSize = "123456"
 		tf.FakeDynamicClient = testing.FakeDynamicClient(repoObj1, repoObj2, backupObj)
 	})
 
@@ -0,0 +1,347 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backuprepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	storagev1alpha1 "github.com/apecloud/kubeblocks/apis/storage/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	// accessKeyIDField and secretAccessKeyField are the credential fields of the S3-compatible storage providers
+	accessKeyIDField     = "accessKeyId"
+	secretAccessKeyField = "secretAccessKey"
+
+	// backupRepoDigestAnnotationKey is the digest of the parameters of the backup repo checked by the
+	// controller, the repo is checked again if the digest is removed.
+	backupRepoDigestAnnotationKey = "dataprotection.kubeblocks.io/backup-repo-digest"
+	// conditionTypePreCheckPassed is the condition of the backup repo whether it passed the check
+	conditionTypePreCheckPassed = "PreCheckPassed"
+)
+
+var (
+	rotateCredentialsExample = templates.Examples(`
+	# Rotate the credential of a S3-compatible backuprepo, and wait for the backuprepo to be checked
+	kbcli backuprepo rotate-credentials my-backuprepo --access-key <NEW ACCESS KEY> --secret-key <NEW SECRET KEY>
+
+	# Rotate the credential without waiting for the backuprepo to be checked
+	kbcli backuprepo rotate-credentials my-backuprepo --access-key <NEW ACCESS KEY> --secret-key <NEW SECRET KEY> --wait=false
+	`)
+)
+
+type rotateCredentialsOptions struct {
+	genericiooptions.IOStreams
+	dynamic dynamic.Interface
+	client  kubernetes.Interface
+
+	repoName  string
+	accessKey string
+	secretKey string
+	wait      bool
+	timeout   time.Duration
+
+	repo *dpv1alpha1.BackupRepo
+}
+
+// affectedBackupPolicy is a backup policy storing its backups in the backup repo
+type affectedBackupPolicy struct {
+	policy    *dpv1alpha1.BackupPolicy
+	schedules []*dpv1alpha1.BackupSchedule
+}
+
+func newRotateCredentialsCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &rotateCredentialsOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "rotate-credentials BACKUP_REPO_NAME",
+		Short:             "Rotate the credential of a S3-compatible backup repository.",
+		Example:           rotateCredentialsExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupRepoGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.accessKey, "access-key", "", "The new access key of the backup repository")
+	cmd.Flags().StringVar(&o.secretKey, "secret-key", "", "The new secret key of the backup repository")
+	cmd.Flags().BoolVar(&o.wait, "wait", true, "Wait for the backup repository to be checked with the new credential")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 5*time.Minute, "Time to wait for the backup repository to be checked")
+	_ = cmd.MarkFlagRequired("access-key")
+	_ = cmd.MarkFlagRequired("secret-key")
+	return cmd
+}
+
+func (o *rotateCredentialsOptions) complete(f cmdutil.Factory, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("please specify the name of the backup repository")
+	}
+	o.repoName = args[0]
+	var err error
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *rotateCredentialsOptions) validate() error {
+	if o.accessKey == "" || o.secretKey == "" {
+		return fmt.Errorf("the access key and secret key can not be empty")
+	}
+	repo := &dpv1alpha1.BackupRepo{}
+	if err := util.GetResourceObjectFromGVR(types.BackupRepoGVR(), client.ObjectKey{Name: o.repoName}, o.dynamic, repo); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("backup repository \"%s\" is not found", o.repoName)
+		}
+		return err
+	}
+	if repo.Spec.Credential == nil {
+		return fmt.Errorf("backup repository \"%s\" has no credential to rotate", o.repoName)
+	}
+	provider := &storagev1alpha1.StorageProvider{}
+	if err := util.GetResourceObjectFromGVR(types.StorageProviderGVR(), client.ObjectKey{Name: repo.Spec.StorageProviderRef}, o.dynamic, provider); err != nil {
+		return err
+	}
+	if provider.Spec.ParametersSchema == nil ||
+		!slices.Contains(provider.Spec.ParametersSchema.CredentialFields, accessKeyIDField) ||
+		!slices.Contains(provider.Spec.ParametersSchema.CredentialFields, secretAccessKeyField) {
+		return fmt.Errorf("the storage provider \"%s\" of backup repository \"%s\" is not S3-compatible", provider.Name, o.repoName)
+	}
+	o.repo = repo
+	return nil
+}
+
+// updateCredentialSecret updates the access key and secret key in the credential secret of the backup repo
+func (o *rotateCredentialsOptions) updateCredentialSecret() error {
+	ref := o.repo.Spec.Credential
+	secret, err := o.client.CoreV1().Secrets(ref.Namespace).Get(context.Background(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	original := secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[accessKeyIDField] = []byte(o.accessKey)
+	secret.Data[secretAccessKeyField] = []byte(o.secretKey)
+	patchData, err := createPatchData(original, secret)
+	if err != nil {
+		return err
+	}
+	_, err = o.client.CoreV1().Secrets(ref.Namespace).Patch(context.Background(), ref.Name, k8stypes.MergePatchType, patchData, metav1.PatchOptions{})
+	return err
+}
+
+// triggerRecheck removes the digest of the backup repo, so that the controller checks the backup repo
+// again even if the credential is not changed.
+func (o *rotateCredentialsOptions) triggerRecheck() error {
+	patchData, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{backupRepoDigestAnnotationKey: nil},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = o.dynamic.Resource(types.BackupRepoGVR()).Patch(context.Background(), o.repoName, k8stypes.MergePatchType, patchData, metav1.PatchOptions{})
+	return err
+}
+
+// waitForCheck waits for the backup repo to be checked with the new credential, and returns the error
+// if the check is failed.
+func (o *rotateCredentialsOptions) waitForCheck() error {
+	var checkErr error
+	if err := wait.PollUntilContextTimeout(context.Background(), 2*time.Second, o.timeout, true, func(ctx context.Context) (bool, error) {
+		repo := &dpv1alpha1.BackupRepo{}
+		if err := util.GetResourceObjectFromGVR(types.BackupRepoGVR(), client.ObjectKey{Name: o.repoName}, o.dynamic, repo); err != nil {
+			return false, err
+		}
+		// the digest is set again after the backup repo is checked
+		if repo.Annotations[backupRepoDigestAnnotationKey] == "" {
+			return false, nil
+		}
+		cond := meta.FindStatusCondition(repo.Status.Conditions, conditionTypePreCheckPassed)
+		switch {
+		case cond != nil && cond.Status == metav1.ConditionTrue:
+			return true, nil
+		case cond != nil && cond.Status == metav1.ConditionFalse:
+			checkErr = fmt.Errorf("backup repository \"%s\" failed to pass the check with the new credential: %s", o.repoName, cond.Message)
+			return true, nil
+		case repo.Status.Phase == dpv1alpha1.BackupRepoFailed:
+			checkErr = fmt.Errorf("backup repository \"%s\" is failed with the new credential", o.repoName)
+			return true, nil
+		}
+		return false, nil
+	}); err != nil {
+		return fmt.Errorf("failed to wait for backup repository \"%s\" to be checked: %v", o.repoName, err)
+	}
+	return checkErr
+}
+
+// getAffectedBackupPolicies returns the backup policies storing the backups in the backup repo, whose
+// backup repo is the backup repo or not specified and the backup repo is the default one.
+func (o *rotateCredentialsOptions) getAffectedBackupPolicies() ([]*affectedBackupPolicy, error) {
+	policyList, err := o.dynamic.Resource(types.BackupPolicyGVR()).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	policies, err := util.ConvertUnstructuredList[*dpv1alpha1.BackupPolicy](policyList.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	scheduleList, err := o.dynamic.Resource(types.BackupScheduleGVR()).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := util.ConvertUnstructuredList[*dpv1alpha1.BackupSchedule](scheduleList.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	isDefault := o.repo.Annotations[dptypes.DefaultBackupRepoAnnotationKey] == trueVal
+	var affected []*affectedBackupPolicy
+	for _, policy := range policies {
+		repoName := ""
+		if policy.Spec.BackupRepoName != nil {
+			repoName = *policy.Spec.BackupRepoName
+		}
+		if repoName != o.repoName && (repoName != "" || !isDefault) {
+			continue
+		}
+		a := &affectedBackupPolicy{policy: policy}
+		for _, schedule := range schedules {
+			if schedule.Namespace == policy.Namespace && schedule.Spec.BackupPolicyName == policy.Name {
+				a.schedules = append(a.schedules, schedule)
+			}
+		}
+		affected = append(affected, a)
+	}
+	slices.SortFunc(affected, func(a, b *affectedBackupPolicy) bool {
+		if a.policy.Namespace != b.policy.Namespace {
+			return a.policy.Namespace < b.policy.Namespace
+		}
+		return a.policy.Name < b.policy.Name
+	})
+	return affected, nil
+}
+
+// printReport prints the backup policies affected by the credential rotation, and whether their
+// scheduled backups can still work with the new credential.
+func (o *rotateCredentialsOptions) printReport(affected []*affectedBackupPolicy, checkErr error) {
+	if len(affected) == 0 {
+		fmt.Fprintf(o.Out, "No backup policy stores the backups in backup repository \"%s\".\n", o.repoName)
+		return
+	}
+	fmt.Fprintf(o.Out, "\nAffected backup policies:\n")
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("NAMESPACE", "BACKUP-POLICY", "SCHEDULED-METHODS", "LAST-SCHEDULE", "LAST-SUCCESSFUL", "STATUS")
+	for _, a := range affected {
+		var (
+			methods                      []string
+			lastSchedule, lastSuccessful *metav1.Time
+		)
+		for _, schedule := range a.schedules {
+			for _, s := range schedule.Spec.Schedules {
+				if !boolptr.IsSetToTrue(s.Enabled) {
+					continue
+				}
+				methods = append(methods, s.BackupMethod)
+				status := schedule.Status.Schedules[s.BackupMethod]
+				if status.LastScheduleTime != nil && (lastSchedule == nil || lastSchedule.Before(status.LastScheduleTime)) {
+					lastSchedule = status.LastScheduleTime
+				}
+				if status.LastSuccessfulTime != nil && (lastSuccessful == nil || lastSuccessful.Before(status.LastSuccessfulTime)) {
+					lastSuccessful = status.LastSuccessfulTime
+				}
+			}
+		}
+		tbl.AddRow(a.policy.Namespace, a.policy.Name, strings.Join(methods, ","), util.TimeFormat(lastSchedule),
+			util.TimeFormat(lastSuccessful), o.scheduledBackupStatus(a, methods, checkErr))
+	}
+	tbl.Print()
+}
+
+// scheduledBackupStatus returns whether the scheduled backups of the backup policy still work
+func (o *rotateCredentialsOptions) scheduledBackupStatus(a *affectedBackupPolicy, methods []string, checkErr error) string {
+	switch {
+	case len(methods) == 0:
+		return "NotScheduled"
+	case checkErr != nil:
+		return "Failed"
+	case a.policy.Status.Phase != dpv1alpha1.AvailablePhase:
+		return fmt.Sprintf("BackupPolicy%s", a.policy.Status.Phase)
+	case !o.wait:
+		return "Unverified"
+	}
+	return "OK"
+}
+
+func (o *rotateCredentialsOptions) run() error {
+	if err := action.CheckExportUnsupported("rotate-credentials"); err != nil {
+		return err
+	}
+	if err := o.updateCredentialSecret(); err != nil {
+		return err
+	}
+	if err := o.triggerRecheck(); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "The credential of backup repository \"%s\" is rotated.\n", o.repoName)
+
+	var checkErr error
+	if o.wait {
+		if checkErr = o.waitForCheck(); checkErr == nil {
+			fmt.Fprintf(o.Out, "Backup repository \"%s\" passed the check with the new credential.\n", o.repoName)
+		}
+	}
+	affected, err := o.getAffectedBackupPolicies()
+	if err != nil {
+		return err
+	}
+	o.printReport(affected, checkErr)
+	return checkErr
+}
@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backuprepo
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/pointer"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	storagev1alpha1 "github.com/apecloud/kubeblocks/apis/storage/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/scheme"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("backuprepo rotate-credentials command", func() {
+	var (
+		out     *bytes.Buffer
+		repo    *dpv1alpha1.BackupRepo
+		secret  *corev1.Secret
+		options *rotateCredentialsOptions
+	)
+
+	newOptions := func(objects ...runtime.Object) *rotateCredentialsOptions {
+		return &rotateCredentialsOptions{
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+			dynamic:   fake.NewSimpleDynamicClient(scheme.Scheme, objects...),
+			client:    testing.FakeClientSet(secret),
+			repoName:  repo.Name,
+			accessKey: "new-access-key",
+			secretKey: "new-secret-key",
+			timeout:   time.Second,
+		}
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credential", Namespace: testing.Namespace},
+			Data:       map[string][]byte{accessKeyIDField: []byte("old-access-key"), secretAccessKeyField: []byte("old-secret-key")},
+		}
+		repo = testing.FakeBackupRepo("test-backuprepo", true)
+		repo.Spec.Credential = &corev1.SecretReference{Name: secret.Name, Namespace: secret.Namespace}
+		repo.Annotations[backupRepoDigestAnnotationKey] = "digest"
+
+		// the policy using the default backup repo with a scheduled backup, and the policy of another repo
+		policy := testing.FakeBackupPolicy("policy", testing.ClusterName)
+		otherPolicy := testing.FakeBackupPolicy("other-policy", testing.ClusterName)
+		otherPolicy.Spec.BackupRepoName = pointer.String("other-backuprepo")
+		options = newOptions(testing.FakeStorageProvider("fake-storage-provider", nil), repo, policy, otherPolicy,
+			testing.FakeBackupSchedule("schedule", policy.Name))
+	})
+
+	It("validate", func() {
+		Expect(options.validate()).Should(Succeed())
+
+		By("the backup repo without credential")
+		repo.Spec.Credential = nil
+		Expect(newOptions(testing.FakeStorageProvider("fake-storage-provider", nil), repo).validate()).Should(
+			MatchError(ContainSubstring("has no credential to rotate")))
+
+		By("the storage provider is not S3-compatible")
+		repo.Spec.Credential = &corev1.SecretReference{Name: secret.Name, Namespace: secret.Namespace}
+		provider := testing.FakeStorageProvider("fake-storage-provider", func(obj *storagev1alpha1.StorageProvider) {
+			obj.Spec.ParametersSchema.CredentialFields = []string{"token"}
+		})
+		Expect(newOptions(provider, repo).validate()).Should(MatchError(ContainSubstring("is not S3-compatible")))
+	})
+
+	It("rotate the credential and report the affected backup policies", func() {
+		Expect(options.validate()).Should(Succeed())
+		Expect(options.run()).Should(Succeed())
+
+		newSecret, err := options.client.CoreV1().Secrets(testing.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(string(newSecret.Data[accessKeyIDField])).Should(Equal("new-access-key"))
+		Expect(string(newSecret.Data[secretAccessKeyField])).Should(Equal("new-secret-key"))
+
+		// the digest is removed to trigger the check
+		obj, err := options.dynamic.Resource(types.BackupRepoGVR()).Get(context.TODO(), repo.Name, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(obj.GetAnnotations()).ShouldNot(HaveKey(backupRepoDigestAnnotationKey))
+
+		Expect(out.String()).Should(ContainSubstring("policy"))
+		Expect(out.String()).Should(ContainSubstring(testing.BackupMethodName))
+		Expect(out.String()).Should(ContainSubstring("Unverified"))
+		Expect(out.String()).ShouldNot(ContainSubstring("other-policy"))
+	})
+
+	It("wait for the backup repo to be checked", func() {
+		repo.Status.Conditions = []metav1.Condition{{Type: conditionTypePreCheckPassed, Status: metav1.ConditionTrue}}
+		options = newOptions(repo)
+		Expect(options.waitForCheck()).Should(Succeed())
+
+		By("the check is failed with the new credential")
+		repo.Status.Conditions[0].Status = metav1.ConditionFalse
+		repo.Status.Conditions[0].Message = "access denied"
+		options = newOptions(repo)
+		Expect(options.waitForCheck()).Should(MatchError(ContainSubstring("access denied")))
+
+		By("the check is not finished")
+		delete(repo.Annotations, backupRepoDigestAnnotationKey)
+		options = newOptions(repo)
+		Expect(options.waitForCheck()).Should(MatchError(ContainSubstring("failed to wait")))
+	})
+})
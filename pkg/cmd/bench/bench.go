@@ -167,6 +167,9 @@ func NewBenchCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.C
 		newListCmd(f, streams),
 		newDeleteCmd(f, streams),
 		newDescribeCmd(f, streams),
+		newLogsCmd(f, streams),
+		newSummaryCmd(f, streams),
+		newCompareCmd(f, streams),
 	)
 
 	return cmd
@@ -212,7 +215,7 @@ func newListCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 		Args:    cobra.NoArgs,
 		Example: benchListExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.run())
+			util.CheckErr(o.run())
 		},
 	}
 
@@ -236,8 +239,8 @@ func newDeleteCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 			return registerBenchmarkCompletionFunc(cmd, f, args, toComplete)
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete())
-			cmdutil.CheckErr(o.run(args))
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run(args))
 		},
 	}
 
@@ -258,8 +261,8 @@ func newDescribeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 			return registerBenchmarkCompletionFunc(cmd, f, args, toComplete)
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.run())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
 		},
 	}
 
@@ -486,6 +489,20 @@ func validateBenchmarkExist(factory cmdutil.Factory, streams genericiooptions.IO
 	return nil
 }
 
+// completeClusterFromArgs takes the benchmark name parsed from the arguments as the cluster to
+// run the benchmark against if --cluster is not specified and the cluster exists, such as
+// `kbcli bench sysbench mycluster`, then the benchmark name is generated with the cluster name.
+func (o *BenchBaseOptions) completeClusterFromArgs(args []string, namePrefix string) {
+	if o.ClusterName != "" || len(args) == 0 || args[len(args)-1] != o.name {
+		return
+	}
+	if _, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(o.namespace).Get(context.TODO(), o.name, metav1.GetOptions{}); err != nil {
+		return
+	}
+	o.ClusterName = o.name
+	o.name = fmt.Sprintf("%s-%s-%s", namePrefix, o.ClusterName, util.RandRFC1123String(6))
+}
+
 // parseStepAndName parses the step and name from the given arguments and name prefix.
 // If no arguments are provided, it sets the step to "all" and generates a random name with the given prefix.
 // If the first argument is "all", "cleanup", "prepare", or "run", it sets the step to the argument value.
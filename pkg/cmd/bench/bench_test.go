@@ -20,17 +20,23 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package bench
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"os"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
@@ -180,6 +186,81 @@ var _ = Describe("bench", func() {
 		Expect(port).Should(Equal(3306))
 	})
 
+	It("take the benchmark name as cluster", func() {
+		o := &BenchBaseOptions{
+			dynamic:   testing.FakeDynamicClient(cluster),
+			namespace: namespace,
+		}
+		o.Step, o.name = parseStepAndName([]string{"run", "not-exist"}, "sysbench")
+		o.completeClusterFromArgs([]string{"run", "not-exist"}, "sysbench")
+		Expect(o.ClusterName).Should(BeEmpty())
+		Expect(o.name).Should(Equal("not-exist"))
+
+		o.Step, o.name = parseStepAndName([]string{clusterName}, "sysbench")
+		o.completeClusterFromArgs([]string{clusterName}, "sysbench")
+		Expect(o.ClusterName).Should(Equal(clusterName))
+		Expect(o.name).Should(HavePrefix("sysbench-" + clusterName + "-"))
+	})
+
+	It("print logs and summarize the results", func() {
+		bench := &unstructured.Unstructured{}
+		bench.SetKind("Sysbench")
+		bench.SetName("mybench")
+		bench.SetNamespace(namespace)
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:            "mybench-run",
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Sysbench", Name: "mybench"}},
+		}}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "mybench-run-xxx",
+			Namespace: namespace,
+			Labels:    map[string]string{benchJobNameLabelKey: job.Name},
+		}}
+		out := &bytes.Buffer{}
+		o := &benchResultOptions{client: kubefakeclient.NewSimpleClientset(job, pod), IOStreams: streams}
+		Expect(o.printLogs(bench, out)).Should(Succeed())
+		Expect(out.String()).Should(Equal("fake logs"))
+
+		By("parse the metrics from the logs")
+		logs := `
+SQL statistics:
+    transactions:                        1000   (100.50 per sec.)
+    queries:                             20000  (2010.00 per sec.)
+Latency (ms):
+         min:                                    1.00
+         avg:                                   39.80
+         95th percentile:                       52.89
+SQL statistics:
+    transactions:                        1500   (150.00 per sec.)
+`
+		metrics := parseBenchMetrics("Sysbench", logs)
+		Expect(metrics).Should(HaveLen(4))
+		Expect(metrics[0].Values).Should(Equal([]float64{100.5, 150}))
+		Expect(metrics[2].Values).Should(Equal([]float64{39.8}))
+		Expect(parseBenchMetrics("Tpch", logs)).Should(BeEmpty())
+
+		By("save and compare the summaries")
+		home, err := os.MkdirTemp("", "kbcli-bench")
+		Expect(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(home)
+		Expect(os.Setenv(types.CliHomeEnv, home)).Should(Succeed())
+		defer os.Unsetenv(types.CliHomeEnv)
+		for _, name := range []string{"bench1", "bench2"} {
+			Expect(saveBenchSummary(&benchSummary{Name: name, Namespace: namespace, Kind: "Sysbench", Metrics: metrics})).Should(Succeed())
+		}
+		_, err = loadBenchSummary(namespace, "not-exist")
+		Expect(err).Should(HaveOccurred())
+		summary, err := loadBenchSummary(namespace, "bench2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(summary.Metrics).Should(Equal(metrics))
+
+		out.Reset()
+		o = &benchResultOptions{namespace: namespace, names: []string{"bench1", "bench2"}, IOStreams: genericiooptions.IOStreams{Out: out}}
+		Expect(o.runCompare()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("100.50, 150.00 /s"))
+	})
+
 	It("parse tolerations", func() {
 		o := &BenchBaseOptions{
 			TolerationsRaw: []string{"dev=true:NoSchedule,large:NoSchedule"},
@@ -35,6 +35,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 const (
@@ -87,13 +88,13 @@ func NewPgBenchCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	}
 
 	cmd := &cobra.Command{
-		Use:     "pgbench [Step] [BenchmarkName]",
+		Use:     "pgbench [Step] [BenchmarkName | ClusterName]",
 		Short:   "Run pgbench against a PostgreSQL cluster",
 		Example: pgbenchExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -133,6 +134,7 @@ func (o *PgBenchOptions) Complete(args []string) error {
 		return err
 	}
 
+	o.completeClusterFromArgs(args, "pgbench")
 	if o.ClusterName != "" {
 		clusterGetter := cluster.ObjectsGetter{
 			Client:    o.client,
@@ -0,0 +1,447 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var (
+	benchLogsExample = templates.Examples(`
+		# print the logs of a benchmark
+		kbcli bench logs mybench
+
+		# stream the logs of a running benchmark
+		kbcli bench logs mybench --follow
+	`)
+
+	benchSummaryExample = templates.Examples(`
+		# parse the results from the logs of a completed benchmark and store the summary for comparison
+		kbcli bench summary mybench
+	`)
+
+	benchCompareExample = templates.Examples(`
+		# compare the stored summaries of benchmarks
+		kbcli bench compare mybench1 mybench2
+	`)
+)
+
+const (
+	// benchContainerName is the container running the benchmark in the kubebench job pods
+	benchContainerName = "kubebench"
+	// benchJobNameLabelKey is the label key of the job pods set by the job controller
+	benchJobNameLabelKey = "job-name"
+)
+
+// benchMetricPattern parses the metric from the benchmark logs, if last is true, only the
+// last match is kept, because the metric is also printed periodically while running.
+type benchMetricPattern struct {
+	name    string
+	unit    string
+	pattern *regexp.Regexp
+	last    bool
+}
+
+// benchMetricPatterns are the patterns of the metrics by the benchmark kind
+var benchMetricPatterns = map[string][]benchMetricPattern{
+	"Sysbench": {
+		{name: "TPS", unit: "/s", pattern: regexp.MustCompile(`transactions:\s+\d+\s+\(([\d.]+) per sec\.\)`)},
+		{name: "QPS", unit: "/s", pattern: regexp.MustCompile(`queries:\s+\d+\s+\(([\d.]+) per sec\.\)`)},
+		{name: "Latency Avg", unit: "ms", pattern: regexp.MustCompile(`\savg:\s+([\d.]+)`)},
+		{name: "Latency P95", unit: "ms", pattern: regexp.MustCompile(`95th percentile:\s+([\d.]+)`)},
+	},
+	"Pgbench": {
+		{name: "TPS", unit: "/s", pattern: regexp.MustCompile(`tps = ([\d.]+)`)},
+		{name: "Latency Avg", unit: "ms", pattern: regexp.MustCompile(`latency average = ([\d.]+) ms`)},
+	},
+	"Ycsb": {
+		{name: "OPS", unit: "/s", pattern: regexp.MustCompile(`TOTAL\s+- Takes\(s\): [\d.]+, Count: \d+, OPS: ([\d.]+)`), last: true},
+		{name: "Latency Avg", unit: "us", pattern: regexp.MustCompile(`TOTAL\s+- .*Avg\(us\): ([\d.]+)`), last: true},
+	},
+	"Tpcc": {
+		{name: "tpmC", unit: "/min", pattern: regexp.MustCompile(`Measured tpmC \(NewOrders\) = ([\d.]+)`)},
+		{name: "tpmTOTAL", unit: "/min", pattern: regexp.MustCompile(`Measured tpmTOTAL = ([\d.]+)`)},
+	},
+}
+
+type benchMetric struct {
+	Name   string    `json:"name"`
+	Unit   string    `json:"unit"`
+	Values []float64 `json:"values"`
+}
+
+// benchSummary is the summary of the benchmark results stored under the kbcli home dir,
+// the metric has multiple values if the benchmark runs multiple times, such as with
+// multiple thread counts.
+type benchSummary struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Kind      string        `json:"kind"`
+	Target    string        `json:"target,omitempty"`
+	Created   time.Time     `json:"created"`
+	Metrics   []benchMetric `json:"metrics"`
+}
+
+type benchResultOptions struct {
+	factory   cmdutil.Factory
+	client    clientset.Interface
+	dynamic   dynamic.Interface
+	namespace string
+	names     []string
+	follow    bool
+
+	genericiooptions.IOStreams
+}
+
+func newLogsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &benchResultOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "logs BenchmarkName",
+		Short:   "Print the logs of a benchmark.",
+		Example: benchLogsExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return registerBenchmarkCompletionFunc(cmd, f, args, toComplete)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.runLogs())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.follow, "follow", "f", false, "Specify if the logs should be streamed")
+	return cmd
+}
+
+func newSummaryCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &benchResultOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "summary BenchmarkName",
+		Short:   "Summarize the results of a benchmark and store the summary for comparison.",
+		Example: benchSummaryExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return registerBenchmarkCompletionFunc(cmd, f, args, toComplete)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.runSummary())
+		},
+	}
+	return cmd
+}
+
+func newCompareCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &benchResultOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "compare BenchmarkName BenchmarkName...",
+		Short:   "Compare the stored summaries of benchmarks.",
+		Example: benchCompareExample,
+		Args:    cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.runCompare())
+		},
+	}
+	return cmd
+}
+
+func (o *benchResultOptions) complete(args []string) error {
+	var err error
+	o.names = args
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *benchResultOptions) runLogs() error {
+	bench, err := getBenchmark(o.dynamic, o.namespace, o.names[0])
+	if err != nil {
+		return err
+	}
+	return o.printLogs(bench, o.Out)
+}
+
+func (o *benchResultOptions) runSummary() error {
+	bench, err := getBenchmark(o.dynamic, o.namespace, o.names[0])
+	if err != nil {
+		return err
+	}
+	logs := &bytes.Buffer{}
+	if err = o.printLogs(bench, logs); err != nil {
+		return err
+	}
+	summary := &benchSummary{
+		Name:      bench.GetName(),
+		Namespace: bench.GetNamespace(),
+		Kind:      bench.GetKind(),
+		Created:   bench.GetCreationTimestamp().Time,
+		Metrics:   parseBenchMetrics(bench.GetKind(), logs.String()),
+	}
+	summary.Target, _, _ = unstructured.NestedString(bench.Object, "spec", "target", "host")
+	if len(summary.Metrics) == 0 {
+		return fmt.Errorf("no results found in the logs of benchmark %s, please check it's completed", bench.GetName())
+	}
+	if err = saveBenchSummary(summary); err != nil {
+		return err
+	}
+
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("METRIC", "VALUES")
+	for _, m := range summary.Metrics {
+		tbl.AddRow(m.Name, formatBenchMetric(&m))
+	}
+	tbl.Print()
+	return nil
+}
+
+func (o *benchResultOptions) runCompare() error {
+	var summaries []*benchSummary
+	for _, name := range o.names {
+		summary, err := loadBenchSummary(o.namespace, name)
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, summary)
+	}
+	return printBenchComparison(summaries, o.Out)
+}
+
+// printLogs prints the logs of the benchmark job pods in the order of the job creation
+func (o *benchResultOptions) printLogs(bench *unstructured.Unstructured, out io.Writer) error {
+	pods, err := getBenchmarkPods(o.client, bench)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found for benchmark %s", bench.GetName())
+	}
+	for _, pod := range pods {
+		if len(pods) > 1 {
+			fmt.Fprintf(out, "==> %s <==\n", pod.Name)
+		}
+		req := o.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container: benchContainerName,
+			Follow:    o.follow,
+		})
+		stream, err := req.Stream(context.TODO())
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, stream)
+		stream.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getBenchmark(dynamic dynamic.Interface, namespace, name string) (*unstructured.Unstructured, error) {
+	for _, gvr := range benchGVRList {
+		obj, err := dynamic.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			return obj, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("benchmark %s not found", name)
+}
+
+// getBenchmarkPods returns the pods of the jobs owned by the benchmark
+func getBenchmarkPods(client clientset.Interface, bench *unstructured.Unstructured) ([]corev1.Pod, error) {
+	jobs, err := client.BatchV1().Jobs(bench.GetNamespace()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	items := jobs.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+	})
+
+	var pods []corev1.Pod
+	for _, job := range items {
+		owned := false
+		for _, ref := range job.OwnerReferences {
+			if ref.Kind == bench.GetKind() && ref.Name == bench.GetName() {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			continue
+		}
+		podList, err := client.CoreV1().Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", benchJobNameLabelKey, job.Name),
+		})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, podList.Items...)
+	}
+	return pods, nil
+}
+
+// parseBenchMetrics parses the metrics from the logs by the patterns of the benchmark kind
+func parseBenchMetrics(kind string, logs string) []benchMetric {
+	var metrics []benchMetric
+	for _, p := range benchMetricPatterns[kind] {
+		var values []float64
+		for _, match := range p.pattern.FindAllStringSubmatch(logs, -1) {
+			v, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				continue
+			}
+			values = append(values, v)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if p.last {
+			values = values[len(values)-1:]
+		}
+		metrics = append(metrics, benchMetric{Name: p.name, Unit: p.unit, Values: values})
+	}
+	return metrics
+}
+
+func formatBenchMetric(m *benchMetric) string {
+	values := make([]string, len(m.Values))
+	for i, v := range m.Values {
+		values[i] = strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	return fmt.Sprintf("%s %s", strings.Join(values, ", "), m.Unit)
+}
+
+func printBenchComparison(summaries []*benchSummary, out io.Writer) error {
+	header := []interface{}{""}
+	kinds := []interface{}{"KIND"}
+	targets := []interface{}{"TARGET"}
+	var names []string
+	metrics := make([]map[string]*benchMetric, len(summaries))
+	for i, s := range summaries {
+		header = append(header, s.Name)
+		kinds = append(kinds, s.Kind)
+		targets = append(targets, util.CheckEmpty(s.Target))
+		metrics[i] = map[string]*benchMetric{}
+		for j := range s.Metrics {
+			m := &s.Metrics[j]
+			if !slices.Contains(names, m.Name) {
+				names = append(names, m.Name)
+			}
+			metrics[i][m.Name] = m
+		}
+	}
+
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader(header...)
+	tbl.AddRow(kinds...)
+	tbl.AddRow(targets...)
+	for _, name := range names {
+		row := []interface{}{name}
+		for i := range summaries {
+			if m, ok := metrics[i][name]; ok {
+				row = append(row, formatBenchMetric(m))
+			} else {
+				row = append(row, printer.NoneString)
+			}
+		}
+		tbl.AddRow(row...)
+	}
+	tbl.Print()
+	return nil
+}
+
+func benchSummaryPath(namespace, name string) (string, error) {
+	home, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, types.CliBenchSummaryDir, fmt.Sprintf("%s_%s.json", namespace, name)), nil
+}
+
+func saveBenchSummary(summary *benchSummary) error {
+	path, err := benchSummaryPath(summary.Namespace, summary.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func loadBenchSummary(namespace, name string) (*benchSummary, error) {
+	path, err := benchSummaryPath(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("summary of benchmark %s not found, use `kbcli bench summary %s` to create it first", name, name)
+		}
+		return nil, err
+	}
+	summary := &benchSummary{}
+	if err = json.Unmarshal(data, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
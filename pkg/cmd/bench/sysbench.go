@@ -36,6 +36,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -50,6 +51,9 @@ var sysbenchExample = templates.Examples(`
 		# sysbench on a cluster, that will exec for all steps, cleanup, prepare and run
 		kbcli bench sysbench mytest --cluster mycluster --user xxx --password xxx --database mydb
 
+		# sysbench on a cluster specified by the argument, the benchmark name is generated
+		kbcli bench sysbench mycluster --user xxx --password xxx --database mydb --threads 16 --duration 300
+
 		# sysbench run on a cluster with cleanup, only cleanup by deleting the testdata
 		kbcli bench sysbench cleanup mytest --cluster mycluster --user xxx --password xxx --database mydb
 
@@ -93,13 +97,13 @@ func NewSysBenchCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 	}
 
 	cmd := &cobra.Command{
-		Use:     "sysbench [Step] [BenchmarkName]",
+		Use:     "sysbench [Step] [BenchmarkName | ClusterName]",
 		Short:   "run a SysBench benchmark",
 		Example: sysbenchExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -140,6 +144,7 @@ func (o *SysBenchOptions) Complete(args []string) error {
 		return err
 	}
 
+	o.completeClusterFromArgs(args, "sysbench")
 	if o.ClusterName != "" {
 		clusterGetter := cluster.ObjectsGetter{
 			Client:    o.client,
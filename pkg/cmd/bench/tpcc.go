@@ -36,6 +36,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -95,13 +96,13 @@ func NewTpccCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 		},
 	}
 	cmd := &cobra.Command{
-		Use:     "tpcc [Step] [BenchmarkName]",
+		Use:     "tpcc [Step] [BenchmarkName | ClusterName]",
 		Short:   "Run tpcc benchmark",
 		Example: tpccExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -145,6 +146,7 @@ func (o *TpccOptions) Complete(args []string) error {
 		return err
 	}
 
+	o.completeClusterFromArgs(args, "tpcc")
 	if o.ClusterName != "" {
 		clusterGetter := cluster.ObjectsGetter{
 			Client:    o.client,
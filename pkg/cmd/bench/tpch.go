@@ -36,6 +36,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -65,13 +66,13 @@ func NewTpchCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 		},
 	}
 	cmd := &cobra.Command{
-		Use:     "tpch [Step] [BenchmarkName]",
+		Use:     "tpch [Step] [BenchmarkName | ClusterName]",
 		Short:   "Run tpch benchmark",
 		Example: tpchExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -105,6 +106,7 @@ func (o *TpchOptions) Complete(args []string) error {
 		return err
 	}
 
+	o.completeClusterFromArgs(args, "tpch")
 	if o.ClusterName != "" {
 		clusterGetter := cluster.ObjectsGetter{
 			Client:    o.client,
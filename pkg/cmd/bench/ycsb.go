@@ -36,6 +36,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -93,13 +94,13 @@ func NewYcsbCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 	}
 
 	cmd := &cobra.Command{
-		Use:     "ycsb [Step] [BenchmarkName]",
+		Use:     "ycsb [Step] [BenchmarkName | ClusterName]",
 		Short:   "Run YCSB benchmark on a cluster",
 		Example: ycsbExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -140,6 +141,7 @@ func (o *YcsbOptions) Complete(args []string) error {
 	}
 
 	o.Step, o.name = parseStepAndName(args, "ycsb")
+	o.completeClusterFromArgs(args, "ycsb")
 	if o.ClusterName != "" {
 		clusterGetter := cluster.ObjectsGetter{
 			Client:    o.client,
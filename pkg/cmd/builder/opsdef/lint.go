@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opsdef
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// lintOpsDef returns the issues of the OpsDefinitions in the yaml documents
+func lintOpsDef(data []byte) []string {
+	var issues []string
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return append(issues, err.Error())
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		opsDef := &OpsDefinition{}
+		if err = yaml.UnmarshalStrict(doc, opsDef); err != nil {
+			issues = append(issues, err.Error())
+			continue
+		}
+		issues = append(issues, lintOpsDefObject(opsDef)...)
+	}
+	return issues
+}
+
+func lintOpsDefObject(opsDef *OpsDefinition) []string {
+	var issues []string
+	addIssue := func(format string, a ...interface{}) {
+		issues = append(issues, fmt.Sprintf("OpsDefinition %s: ", opsDef.Name)+fmt.Sprintf(format, a...))
+	}
+
+	if opsDef.APIVersion != opsDefAPIVersion || opsDef.Kind != opsDefKind {
+		addIssue("apiVersion and kind must be %s and %s", opsDefAPIVersion, opsDefKind)
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(opsDef.Name) {
+		addIssue("invalid name: %s", msg)
+	}
+
+	spec := opsDef.Spec
+	if len(spec.ComponentDefinitionRefs) == 0 {
+		addIssue("spec.componentDefinitionRefs can not be empty")
+	}
+	for i, ref := range spec.ComponentDefinitionRefs {
+		if ref.Name == "" {
+			addIssue("spec.componentDefinitionRefs[%d].name can not be empty", i)
+		}
+	}
+
+	for i, cond := range spec.PreConditions {
+		if cond.Rule == nil {
+			addIssue("spec.preConditions[%d].rule can not be empty", i)
+			continue
+		}
+		if cond.Rule.Expression == "" {
+			addIssue("spec.preConditions[%d].rule.expression can not be empty", i)
+		} else if _, err := template.New("expression").Parse(cond.Rule.Expression); err != nil {
+			addIssue("spec.preConditions[%d].rule.expression is not a valid go template: %v", i, err)
+		}
+		if cond.Rule.Message == "" {
+			addIssue("spec.preConditions[%d].rule.message can not be empty", i)
+		}
+	}
+
+	if spec.ParametersSchema != nil && spec.ParametersSchema.OpenAPIV3Schema != nil {
+		schema := spec.ParametersSchema.OpenAPIV3Schema
+		if schema.Type != "object" {
+			addIssue("the type of spec.parametersSchema.openAPIV3Schema must be object")
+		}
+		for name, prop := range schema.Properties {
+			if prop.Type == "" {
+				addIssue("the type of parameter %s can not be empty", name)
+			}
+		}
+		for _, name := range schema.Required {
+			if _, ok := schema.Properties[name]; !ok {
+				addIssue("the required parameter %s is not defined in the properties", name)
+			}
+		}
+	}
+
+	podSpec := spec.JobSpec.Template.Spec
+	if podSpec.RestartPolicy != corev1.RestartPolicyNever && podSpec.RestartPolicy != corev1.RestartPolicyOnFailure {
+		addIssue("spec.jobSpec.template.spec.restartPolicy must be Never or OnFailure")
+	}
+	if len(podSpec.Containers) == 0 {
+		addIssue("spec.jobSpec.template.spec.containers can not be empty")
+	}
+	for i, c := range podSpec.Containers {
+		if c.Name == "" {
+			addIssue("spec.jobSpec.template.spec.containers[%d].name can not be empty", i)
+		}
+		if c.Image == "" {
+			addIssue("spec.jobSpec.template.spec.containers[%d].image can not be empty", i)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,236 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opsdef
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	cfgutil "github.com/apecloud/kubeblocks/pkg/configuration/util"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var (
+	initExamples = templates.Examples(`
+	# scaffold an OpsDefinition named mysql-kill-query to mysql-kill-query.yaml
+	kbcli builder opsdef init mysql-kill-query --component-def mysql
+
+	# scaffold an OpsDefinition with the job image to the specified directory
+	kbcli builder opsdef init mysql-kill-query --component-def mysql --image mysql:8.0.30 --output-dir deploy/apecloud-mysql/templates`)
+
+	lintExamples = templates.Examples(`
+	# lint the OpsDefinitions
+	kbcli builder opsdef lint mysql-kill-query.yaml deploy/apecloud-mysql/templates/opsdef-*.yaml`)
+)
+
+// opsDefTemplate is the scaffold of an OpsDefinition, it contains a parameter, a precondition and
+// a job which passes the parameter as env.
+var opsDefTemplate = template.Must(template.New("opsdef").Parse(`apiVersion: {{ .APIVersion }}
+kind: {{ .Kind }}
+metadata:
+  name: {{ .Name }}
+spec:
+  # the component definitions supported by the ops
+  componentDefinitionRefs:
+{{- range .ComponentDefs }}
+    - name: {{ . }}
+{{- end }}
+  # the rules must be satisfied before running the ops, the expression is a go template
+  # rendered with the component and evaluated to "true" if satisfied
+  preConditions:
+    - rule:
+        expression: '{{ "{{" }} eq .component.status.phase "Running" {{ "}}" }}'
+        message: "Component is not in Running status."
+  # the schema of the parameters passed by the OpsRequest
+  parametersSchema:
+    openAPIV3Schema:
+      type: object
+      properties:
+        timeout:
+          type: integer
+          description: "the timeout seconds of the ops."
+          default: 60
+          minimum: 1
+      required:
+        - timeout
+  # the job to run the ops, the parameters are passed to the containers as env
+  jobSpec:
+    backoffLimit: 2
+    template:
+      spec:
+        restartPolicy: Never
+        containers:
+          - name: {{ .Name }}
+            image: {{ .Image }}
+            imagePullPolicy: IfNotPresent
+            command:
+              - sh
+              - -c
+              - echo "run ops {{ .Name }} with timeout ${timeout}"
+`))
+
+type initOptions struct {
+	genericiooptions.IOStreams
+
+	name          string
+	componentDefs []string
+	image         string
+	outputDir     string
+	overwrite     bool
+}
+
+func (o *initOptions) complete(args []string) error {
+	if len(args) != 1 {
+		return cfgcore.MakeError("the name of the OpsDefinition is required")
+	}
+	o.name = args[0]
+	if len(o.componentDefs) == 0 {
+		return cfgcore.MakeError("the component definition is required, specify it by --component-def")
+	}
+	return nil
+}
+
+func (o *initOptions) run() error {
+	buf := &bytes.Buffer{}
+	if err := opsDefTemplate.Execute(buf, map[string]interface{}{
+		"APIVersion":    opsDefAPIVersion,
+		"Kind":          opsDefKind,
+		"Name":          o.name,
+		"ComponentDefs": o.componentDefs,
+		"Image":         o.image,
+	}); err != nil {
+		return err
+	}
+	// the scaffold should always pass the lint
+	if issues := lintOpsDef(buf.Bytes()); len(issues) != 0 {
+		return cfgcore.MakeError("invalid OpsDefinition scaffold: %v", issues)
+	}
+
+	if o.outputDir != "" {
+		if err := os.MkdirAll(o.outputDir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	file := filepath.Join(o.outputDir, o.name+".yaml")
+	if ok, _ := cfgutil.CheckPathExists(file); ok && !o.overwrite {
+		return cfgcore.MakeError("file %s already exists, use --force to overwrite it", file)
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "OpsDefinition %s is created to %s\n", o.name, file)
+	return nil
+}
+
+type lintOptions struct {
+	genericiooptions.IOStreams
+
+	files []string
+}
+
+func (o *lintOptions) complete(args []string) error {
+	if len(args) == 0 {
+		return cfgcore.MakeError("the OpsDefinition files are required")
+	}
+	o.files = args
+	return nil
+}
+
+func (o *lintOptions) run() error {
+	total := 0
+	for _, file := range o.files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		issues := lintOpsDef(data)
+		printIssues(o.Out, file, issues)
+		total += len(issues)
+	}
+	if total != 0 {
+		return cfgcore.MakeError("found %d issue(s) in the OpsDefinitions", total)
+	}
+	fmt.Fprintf(o.Out, "%d OpsDefinition file(s) linted, no issues found\n", len(o.files))
+	return nil
+}
+
+func printIssues(out io.Writer, file string, issues []string) {
+	for _, issue := range issues {
+		fmt.Fprintf(out, "%s: %s\n", printer.BoldRed(file), issue)
+	}
+}
+
+func newInitCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &initOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "init NAME",
+		Short:   "Scaffold an OpsDefinition with the parameters schema, job template and preconditions.",
+		Example: initExamples,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringArrayVar(&o.componentDefs, "component-def", nil, "specify the component definitions supported by the ops")
+	cmd.Flags().StringVar(&o.image, "image", "busybox:latest", "specify the image of the job running the ops")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "specify the output directory, the file is named by the OpsDefinition name")
+	cmd.Flags().BoolVar(&o.overwrite, "force", false, "whether overwrite the exists file")
+	return cmd
+}
+
+func newLintCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &lintOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "lint FILE...",
+		Short:   "Lint the OpsDefinitions for the custom ops.",
+		Example: lintExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
+		},
+	}
+	return cmd
+}
+
+// NewOpsDefCmd for the OpsDefinition developer tools
+func NewOpsDefCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "opsdef",
+		Short: "opsdef - a developer tool to scaffold and lint the OpsDefinitions for the custom ops.",
+	}
+	cmd.AddCommand(
+		newInitCmd(streams),
+		newLintCmd(streams),
+	)
+	return cmd
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opsdef
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+var _ = Describe("opsdef", func() {
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+		tmpDir  string
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = cmdtesting.NewTestFactory()
+		tmpDir = GinkgoT().TempDir()
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("command should succeed", func() {
+		Expect(NewOpsDefCmd(tf, streams)).ShouldNot(BeNil())
+	})
+
+	It("init and lint the OpsDefinition", func() {
+		o := &initOptions{IOStreams: streams, outputDir: tmpDir, image: "busybox:latest"}
+		Expect(o.complete([]string{"mysql-kill-query"})).Should(HaveOccurred())
+		o.componentDefs = []string{"mysql"}
+		Expect(o.complete([]string{"mysql-kill-query"})).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		file := filepath.Join(tmpDir, "mysql-kill-query.yaml")
+		Expect(file).Should(BeAnExistingFile())
+
+		By("the file exists")
+		Expect(o.run()).Should(MatchError(ContainSubstring("already exists")))
+		o.overwrite = true
+		Expect(o.run()).Should(Succeed())
+
+		By("lint the scaffold")
+		lint := &lintOptions{IOStreams: streams}
+		Expect(lint.complete([]string{file})).Should(Succeed())
+		Expect(lint.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("no issues found"))
+	})
+
+	It("lint the invalid OpsDefinition", func() {
+		file := filepath.Join(tmpDir, "invalid.yaml")
+		Expect(os.WriteFile(file, []byte(`apiVersion: apps.kubeblocks.io/v1alpha1
+kind: OpsDefinition
+metadata:
+  name: Invalid_Name
+spec:
+  preConditions:
+    - rule:
+        expression: '{{ eq .component.status.phase "Running" '
+  parametersSchema:
+    openAPIV3Schema:
+      type: object
+      properties:
+        timeout: {}
+      required:
+        - sql
+  jobSpec:
+    template:
+      spec:
+        containers:
+          - name: kill-query
+`), 0644)).Should(Succeed())
+		lint := &lintOptions{IOStreams: streams, files: []string{file}}
+		Expect(lint.run()).Should(MatchError(ContainSubstring("found 8 issue(s)")))
+		for _, issue := range []string{
+			"invalid name",
+			"spec.componentDefinitionRefs can not be empty",
+			"is not a valid go template",
+			"rule.message can not be empty",
+			"the type of parameter timeout can not be empty",
+			"the required parameter sql is not defined",
+			"restartPolicy must be Never or OnFailure",
+			"containers[0].image can not be empty",
+		} {
+			Expect(out.String()).Should(ContainSubstring(issue))
+		}
+
+		By("lint the unknown fields")
+		Expect(lintOpsDef([]byte("apiVersion: v1\nkind: OpsDefinition\nunknown: true\n"))).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package opsdef
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	opsDefAPIVersion = "apps.kubeblocks.io/v1alpha1"
+	opsDefKind       = "OpsDefinition"
+)
+
+// OpsDefinition is the custom ops defined by the addon developers. The KubeBlocks API in use does
+// not contain it yet, so the fields used by the scaffold and the lint are declared here.
+type OpsDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OpsDefinitionSpec `json:"spec"`
+}
+
+type OpsDefinitionSpec struct {
+	// ComponentDefinitionRefs are the component definitions supported by the ops
+	ComponentDefinitionRefs []ComponentDefinitionRef `json:"componentDefinitionRefs,omitempty"`
+
+	// PreConditions are the rules must be satisfied before running the ops
+	PreConditions []PreCondition `json:"preConditions,omitempty"`
+
+	// ParametersSchema is the schema of the parameters passed by the OpsRequest
+	ParametersSchema *ParametersSchema `json:"parametersSchema,omitempty"`
+
+	// JobSpec is the job to run the ops, the parameters are passed to the containers as env
+	JobSpec batchv1.JobSpec `json:"jobSpec"`
+}
+
+type ComponentDefinitionRef struct {
+	Name string `json:"name"`
+}
+
+type PreCondition struct {
+	Rule *Rule `json:"rule,omitempty"`
+}
+
+type Rule struct {
+	// Expression is a go template rendered with the component, evaluated to "true" if satisfied
+	Expression string `json:"expression"`
+	Message    string `json:"message"`
+}
+
+type ParametersSchema struct {
+	OpenAPIV3Schema *apiextensionsv1.JSONSchemaProps `json:"openAPIV3Schema,omitempty"`
+}
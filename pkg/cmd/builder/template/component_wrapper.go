@@ -50,6 +50,7 @@ import (
 type templateRenderWorkflow struct {
 	renderedOpts RenderedOptions
 	clusterYaml  string
+	clusterObj   *appsv1alpha1.Cluster
 	localObjects []client.Object
 
 	clusterDefObj     *appsv1alpha1.ClusterDefinition
@@ -142,7 +143,18 @@ func (w *templateRenderWorkflow) getComponentWithConfigSpec(name string) *appsv1
 	return nil
 }
 
+// WithClusterObjects renders the templates against the cluster object instead of a mocked one,
+// the objects such as the components of the cluster are added to the mock client.
+func (w *templateRenderWorkflow) WithClusterObjects(cluster *appsv1alpha1.Cluster, objects ...client.Object) {
+	w.clusterObj = cluster
+	w.localObjects = append(w.localObjects, cluster)
+	w.localObjects = append(w.localObjects, objects...)
+}
+
 func (w *templateRenderWorkflow) createClusterObject() (*appsv1alpha1.Cluster, error) {
+	if w.clusterObj != nil {
+		return w.clusterObj, nil
+	}
 	if w.clusterYaml != "" {
 		return CustomizedObjFromYaml(w.clusterYaml, generics.ClusterSignature)
 	}
@@ -0,0 +1,279 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	cfgutil "github.com/apecloud/kubeblocks/pkg/configuration/util"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var templateTestExamples = templates.Examples(`
+    # render the templates against every cluster yaml in the fixtures directory and compare with the golden outputs
+    kbcli builder template test --helm deploy/redis --fixtures deploy/redis/tests/fixtures
+
+    # update the golden outputs after the templates are changed as expected
+    kbcli builder template test --helm deploy/redis --fixtures deploy/redis/tests/fixtures --update-golden
+`)
+
+type goldenTestOptions struct {
+	genericiooptions.IOStreams
+
+	helmTemplateDir string
+	helmOutputDir   string
+	fixturesDir     string
+	goldenDir       string
+	clusterDef      string
+	clusterVersion  string
+	updateGolden    bool
+}
+
+func (o *goldenTestOptions) complete() error {
+	if o.helmTemplateDir == "" && o.helmOutputDir == "" {
+		return cfgcore.MakeError("helm template dir is required, specify it by --helm or --helm-output")
+	}
+	if o.fixturesDir == "" {
+		return cfgcore.MakeError("fixtures dir is required, specify it by --fixtures")
+	}
+	if o.goldenDir == "" {
+		o.goldenDir = filepath.Join(o.fixturesDir, "golden")
+	}
+	return nil
+}
+
+func (o *goldenTestOptions) run() error {
+	viper.SetDefault(constant.KubernetesClusterDomainEnv, constant.DefaultDNSDomain)
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "golden-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	helmOutput := o.helmOutputDir
+	if helmOutput == "" {
+		helmOutput = filepath.Join(tmpDir, "helm-output")
+		if err = HelmTemplate(o.helmTemplateDir, helmOutput); err != nil {
+			return err
+		}
+	}
+	fixtures, err := listFixtures(o.fixturesDir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return cfgcore.MakeError("no cluster yaml found in the fixtures dir[%s]", o.fixturesDir)
+	}
+
+	var failed []string
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), filepath.Ext(fixture))
+		rendered := filepath.Join(tmpDir, "rendered", name)
+		if err = o.render(helmOutput, fixture, rendered); err != nil {
+			return fmt.Errorf("failed to render the fixture %s: %v", fixture, err)
+		}
+		golden := filepath.Join(o.goldenDir, name)
+		if o.updateGolden {
+			if err = updateGoldenDir(rendered, golden); err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "%s %s\n", printer.BoldYellow("UPDATED"), name)
+			continue
+		}
+		diffs, err := compareGoldenDir(rendered, golden)
+		if err != nil {
+			return err
+		}
+		if len(diffs) == 0 {
+			fmt.Fprintf(o.Out, "%s %s\n", printer.BoldGreen("PASS"), name)
+			continue
+		}
+		failed = append(failed, name)
+		fmt.Fprintf(o.Out, "%s %s\n", printer.BoldRed("FAIL"), name)
+		for _, diff := range diffs {
+			util.DisplayDiffWithColor(o.Out, diff)
+		}
+	}
+	if len(failed) != 0 {
+		return cfgcore.MakeError("the rendered outputs of fixtures %s mismatch the golden outputs, rerun with --update-golden if the changes are expected", strings.Join(failed, ","))
+	}
+	return nil
+}
+
+// render renders all templates of the addon against the cluster in the fixture
+func (o *goldenTestOptions) render(helmOutput, fixture, outputDir string) error {
+	cluster, err := CustomizedObjFromYaml(fixture, generics.ClusterSignature)
+	if err != nil {
+		return err
+	}
+	clusterDef, clusterVersion := o.clusterDef, o.clusterVersion
+	if clusterDef == "" {
+		clusterDef = cluster.Spec.ClusterDefRef
+	}
+	if clusterVersion == "" {
+		clusterVersion = cluster.Spec.ClusterVersionRef
+	}
+	workflow, err := NewWorkflowTemplateRender(helmOutput, RenderedOptions{}, clusterDef, clusterVersion)
+	if err != nil {
+		return err
+	}
+	workflow.WithClusterObjects(cluster)
+	return workflow.Do(outputDir)
+}
+
+// listFixtures returns the sorted cluster yaml files in the fixtures dir
+func listFixtures(dir string) ([]string, error) {
+	var fixtures []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, files...)
+	}
+	sort.Strings(fixtures)
+	return fixtures, nil
+}
+
+// listFiles returns the relative paths of the files in the dir, returns empty if the dir does not exist
+func listFiles(dir string) (sets.Set[string], error) {
+	files := sets.New[string]()
+	if ok, _ := cfgutil.CheckPathExists(dir); !ok {
+		return files, nil
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files.Insert(rel)
+		return nil
+	})
+	return files, err
+}
+
+// compareGoldenDir compares the rendered files with the golden files, returns the unified diffs of the mismatched files
+func compareGoldenDir(renderedDir, goldenDir string) ([]string, error) {
+	renderedFiles, err := listFiles(renderedDir)
+	if err != nil {
+		return nil, err
+	}
+	goldenFiles, err := listFiles(goldenDir)
+	if err != nil {
+		return nil, err
+	}
+
+	readFile := func(dir, file string, exists bool) (string, error) {
+		if !exists {
+			return "", nil
+		}
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		return string(b), err
+	}
+	var diffs []string
+	for _, file := range sets.List(renderedFiles.Union(goldenFiles)) {
+		rendered, err := readFile(renderedDir, file, renderedFiles.Has(file))
+		if err != nil {
+			return nil, err
+		}
+		golden, err := readFile(goldenDir, file, goldenFiles.Has(file))
+		if err != nil {
+			return nil, err
+		}
+		if rendered == golden && renderedFiles.Has(file) == goldenFiles.Has(file) {
+			continue
+		}
+		diff, err := util.GetUnifiedDiffString(golden, rendered, filepath.Join("golden", file), filepath.Join("rendered", file), 3)
+		if err != nil {
+			return nil, err
+		}
+		if diff == "" {
+			// the empty file is created or deleted
+			diff = fmt.Sprintf("--- golden/%s\n+++ rendered/%s\n", file, file)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// updateGoldenDir replaces the golden files with the rendered files
+func updateGoldenDir(renderedDir, goldenDir string) error {
+	if err := os.RemoveAll(goldenDir); err != nil {
+		return err
+	}
+	files, err := listFiles(renderedDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range sets.List(files) {
+		b, err := os.ReadFile(filepath.Join(renderedDir, file))
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(goldenDir, file)
+		if err = os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(dst, b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTemplateTestCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &goldenTestOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "test",
+		Short:   "Render all templates of an addon against the fixtures and compare with the golden outputs.",
+		Example: templateTestExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.helmTemplateDir, "helm", "", "specify the helm template dir")
+	cmd.Flags().StringVar(&o.helmOutputDir, "helm-output", "", "specify the helm template output dir, the helm template is skipped if specified")
+	cmd.Flags().StringVar(&o.fixturesDir, "fixtures", "", "specify the dir of the cluster yaml files to render the templates against")
+	cmd.Flags().StringVar(&o.goldenDir, "golden-dir", "", "specify the dir of the golden outputs, default is the golden dir in the fixtures dir")
+	cmd.Flags().StringVar(&o.clusterDef, "cluster-definition", "", "specify the cluster definition name, default is the cluster definition of the fixture")
+	cmd.Flags().StringVar(&o.clusterVersion, "cluster-version", "", "specify the cluster version name, default is the cluster version of the fixture")
+	cmd.Flags().BoolVar(&o.updateGolden, "update-golden", false, "update the golden outputs with the rendered outputs")
+	return cmd
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package template
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+var _ = Describe("golden", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+	})
+
+	writeFiles := func(dir string, files map[string]string) {
+		for name, content := range files {
+			file := filepath.Join(dir, name)
+			Expect(os.MkdirAll(filepath.Dir(file), 0755)).Should(Succeed())
+			Expect(os.WriteFile(file, []byte(content), 0644)).Should(Succeed())
+		}
+	}
+
+	It("complete the options", func() {
+		streams, _, _, _ := genericiooptions.NewTestIOStreams()
+		o := &goldenTestOptions{IOStreams: streams}
+		Expect(o.complete()).Should(HaveOccurred())
+		o.helmTemplateDir = "deploy/redis"
+		Expect(o.complete()).Should(HaveOccurred())
+		o.fixturesDir = "fixtures"
+		Expect(o.complete()).Should(Succeed())
+		Expect(o.goldenDir).Should(Equal(filepath.Join("fixtures", "golden")))
+		Expect(newTemplateTestCmd(streams)).ShouldNot(BeNil())
+	})
+
+	It("list the fixtures", func() {
+		writeFiles(tmpDir, map[string]string{"b.yaml": "", "a.yml": "", "c.txt": "", "golden/d.yaml": ""})
+		fixtures, err := listFixtures(tmpDir)
+		Expect(err).Should(Succeed())
+		Expect(fixtures).Should(Equal([]string{filepath.Join(tmpDir, "a.yml"), filepath.Join(tmpDir, "b.yaml")}))
+	})
+
+	It("compare and update the golden outputs", func() {
+		rendered := filepath.Join(tmpDir, "rendered")
+		golden := filepath.Join(tmpDir, "golden")
+		writeFiles(rendered, map[string]string{
+			"redis-config/redis.conf": "port 6379\nmaxmemory 1gb\n",
+			"redis-scripts/start.sh":  "redis-server\n",
+		})
+
+		By("compare with the missing golden outputs")
+		diffs, err := compareGoldenDir(rendered, golden)
+		Expect(err).Should(Succeed())
+		Expect(diffs).Should(HaveLen(2))
+
+		By("update the golden outputs")
+		writeFiles(golden, map[string]string{"stale.conf": "stale"})
+		Expect(updateGoldenDir(rendered, golden)).Should(Succeed())
+		Expect(filepath.Join(golden, "stale.conf")).ShouldNot(BeAnExistingFile())
+		diffs, err = compareGoldenDir(rendered, golden)
+		Expect(err).Should(Succeed())
+		Expect(diffs).Should(BeEmpty())
+
+		By("compare with the changed outputs")
+		writeFiles(rendered, map[string]string{"redis-config/redis.conf": "port 6379\nmaxmemory 2gb\n"})
+		diffs, err = compareGoldenDir(rendered, golden)
+		Expect(err).Should(Succeed())
+		Expect(diffs).Should(HaveLen(1))
+		Expect(diffs[0]).Should(ContainSubstring("-maxmemory 1gb"))
+		Expect(diffs[0]).Should(ContainSubstring("+maxmemory 2gb"))
+	})
+})
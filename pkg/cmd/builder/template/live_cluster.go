@@ -0,0 +1,66 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package template
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// getLiveClusterObjects gets the cluster and its components from the live cluster, which are used to
+// seed the mock client instead of the hand-written fixtures.
+func getLiveClusterObjects(dynamic dynamic.Interface, namespace, name string) (*appsv1alpha1.Cluster, []client.Object, error) {
+	clusterObj, err := cluster.GetClusterByName(dynamic, name, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the Component CRD may be not installed by the older KubeBlocks
+	list, err := dynamic.Resource(types.ComponentGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, name),
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return clusterObj, nil, nil
+		}
+		return nil, nil, err
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for _, item := range list.Items {
+		comp := &appsv1alpha1.Component{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, comp); err != nil {
+			return nil, nil, err
+		}
+		objects = append(objects, comp)
+	}
+	return clusterObj, objects, nil
+}
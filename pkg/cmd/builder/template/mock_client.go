@@ -21,18 +21,35 @@ package template
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
+	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
 )
 
+var mockScheme = func() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	return scheme
+}()
+
 type ResourceMatcher = func(obj runtime.Object) bool
 type Handler = func(obj runtime.Object) error
 
@@ -41,9 +58,18 @@ type ResourceHandler struct {
 	Handler Handler
 }
 
+// Mutation is an update or patch of an object, the Object is the resulting object after the mutation
+type Mutation struct {
+	Verb string
+	// SubResource is the mutated subresource, such as status, empty if the object is mutated
+	SubResource string
+	Object      client.Object
+}
+
 type mockClient struct {
 	objects        map[client.ObjectKey]client.Object
 	kindObjectList map[string][]runtime.Object
+	mutations      []Mutation
 
 	hander *ResourceHandler
 }
@@ -77,6 +103,67 @@ func (m *mockClient) AppendMockObjects(obj client.Object) {
 	m.objects[objKey] = obj
 }
 
+// Mutations returns the updates and patches of the objects in order
+func (m *mockClient) Mutations() []Mutation {
+	return m.mutations
+}
+
+// storeObject saves a copy of the object to the object store, replaces the existing one
+func (m *mockClient) storeObject(obj client.Object) client.Object {
+	stored := obj.DeepCopyObject().(client.Object)
+	objKey := client.ObjectKeyFromObject(obj)
+	m.objects[objKey] = stored
+
+	kind := stored.GetObjectKind().GroupVersionKind().Kind
+	for i, o := range m.kindObjectList[kind] {
+		if client.ObjectKeyFromObject(o.(client.Object)) == objKey {
+			m.kindObjectList[kind][i] = stored
+			return stored
+		}
+	}
+	m.kindObjectList[kind] = append(m.kindObjectList[kind], stored)
+	return stored
+}
+
+func (m *mockClient) recordMutation(verb, subResource string, obj client.Object) {
+	m.mutations = append(m.mutations, Mutation{Verb: verb, SubResource: subResource, Object: obj})
+}
+
+// patchObject applies the patch to the stored object and saves the result to obj, the obj is stored as it is
+// if not found in the object store.
+func (m *mockClient) patchObject(obj client.Object, patch client.Patch) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	stored, ok := m.objects[client.ObjectKeyFromObject(obj)]
+	if !ok {
+		return nil
+	}
+	original, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	var patched []byte
+	switch patch.Type() {
+	case k8stypes.MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, data)
+	case k8stypes.JSONPatchType:
+		var p jsonpatch.Patch
+		if p, err = jsonpatch.DecodePatch(data); err == nil {
+			patched, err = p.Apply(original)
+		}
+	case k8stypes.StrategicMergePatchType:
+		patched, err = strategicpatch.StrategicMergePatch(original, data, obj)
+	default:
+		return cfgcore.MakeError("not support patch type %s", patch.Type())
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(patched, obj)
+}
+
 func splitRuntimeObject(objects []client.Object) map[string][]runtime.Object {
 	r := make(map[string][]runtime.Object)
 	for _, object := range objects {
@@ -104,14 +191,51 @@ func (m *mockClient) Get(ctx context.Context, key client.ObjectKey, obj client.O
 }
 
 func (m *mockClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
-	r := m.kindObjectList[list.GetObjectKind().GroupVersionKind().Kind]
-	if r != nil {
-		return SetListReturnedObjects(list, r)
+	kind := list.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		gvk, err := m.GroupVersionKindFor(list)
+		if err != nil {
+			return err
+		}
+		kind = gvk.Kind
 	}
-	return nil
+	r, ok := m.kindObjectList[kind]
+	if !ok {
+		r = m.kindObjectList[strings.TrimSuffix(kind, "List")]
+	}
+	if r == nil {
+		return nil
+	}
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	var objects []runtime.Object
+	for _, o := range r {
+		obj := o.(client.Object)
+		if listOpts.Namespace != "" && obj.GetNamespace() != listOpts.Namespace {
+			continue
+		}
+		if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		if listOpts.FieldSelector != nil && !listOpts.FieldSelector.Matches(fields.Set{
+			"metadata.name":      obj.GetName(),
+			"metadata.namespace": obj.GetNamespace(),
+		}) {
+			continue
+		}
+		if listOpts.Limit > 0 && int64(len(objects)) >= listOpts.Limit {
+			break
+		}
+		objects = append(objects, obj)
+	}
+	return SetListReturnedObjects(list, objects)
 }
 
 func (m *mockClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := m.objects[client.ObjectKeyFromObject(obj)]; !ok {
+		m.storeObject(obj)
+	}
 	if m.hander == nil || len(m.hander.Matcher) == 0 {
 		return nil
 	}
@@ -129,10 +253,15 @@ func (m *mockClient) Delete(ctx context.Context, obj client.Object, opts ...clie
 }
 
 func (m *mockClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	m.recordMutation("update", "", m.storeObject(obj))
 	return nil
 }
 
 func (m *mockClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := m.patchObject(obj, patch); err != nil {
+		return err
+	}
+	m.recordMutation("patch", "", m.storeObject(obj))
 	return nil
 }
 
@@ -141,15 +270,15 @@ func (m *mockClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ..
 }
 
 func (m *mockClient) Status() client.SubResourceWriter {
-	panic("implement me")
+	return m.SubResource("status")
 }
 
 func (m *mockClient) SubResource(subResource string) client.SubResourceClient {
-	panic("implement me")
+	return &mockSubResourceClient{client: m, subResource: subResource}
 }
 
 func (m *mockClient) Scheme() *runtime.Scheme {
-	panic("implement me")
+	return mockScheme
 }
 
 func (m *mockClient) RESTMapper() meta.RESTMapper {
@@ -157,13 +286,74 @@ func (m *mockClient) RESTMapper() meta.RESTMapper {
 }
 
 func (m *mockClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
-	panic("implement me")
+	return apiutil.GVKForObject(obj, m.Scheme())
 }
 
 func (m *mockClient) IsObjectNamespaced(obj runtime.Object) (bool, error) {
 	panic("implement me")
 }
 
+// mockSubResourceClient mutates the subresource of the objects in the object store of the mock client,
+// the status subresource is the status field of the object, other subresources are only recorded.
+type mockSubResourceClient struct {
+	client      *mockClient
+	subResource string
+}
+
+func (c *mockSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	if c.subResource != "status" {
+		return cfgcore.MakeError("not support subresource %s", c.subResource)
+	}
+	return c.client.Get(ctx, client.ObjectKeyFromObject(obj), subResource)
+}
+
+func (c *mockSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	c.client.recordMutation("create", c.subResource, subResource)
+	return nil
+}
+
+func (c *mockSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return c.update("update", obj)
+}
+
+func (c *mockSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := c.client.patchObject(obj, patch); err != nil {
+		return err
+	}
+	return c.update("patch", obj)
+}
+
+// update saves the status of obj to the stored object, other fields of the stored object are kept
+func (c *mockSubResourceClient) update(verb string, obj client.Object) error {
+	stored, ok := c.client.objects[client.ObjectKeyFromObject(obj)]
+	if !ok {
+		return apierrors.NewNotFound(corev1.SchemeGroupVersion.WithResource("mock_resource").GroupResource(), client.ObjectKeyFromObject(obj).String())
+	}
+	if c.subResource == "status" {
+		status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		updated, err := runtime.DefaultUnstructuredConverter.ToUnstructured(stored)
+		if err != nil {
+			return err
+		}
+		if v, ok := status["status"]; ok {
+			updated["status"] = v
+		} else {
+			delete(updated, "status")
+		}
+		result := stored.DeepCopyObject().(client.Object)
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(updated, result); err != nil {
+			return err
+		}
+		SetGetReturnedObject(obj, result)
+		stored = c.client.storeObject(result)
+	}
+	c.client.recordMutation(verb, c.subResource, stored)
+	return nil
+}
+
 func SetGetReturnedObject(out client.Object, expectedObj client.Object) {
 	outVal := reflect.ValueOf(out)
 	objVal := reflect.ValueOf(expectedObj)
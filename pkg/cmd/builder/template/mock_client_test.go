@@ -0,0 +1,153 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package template
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("mock client", func() {
+	var (
+		ctx     = context.Background()
+		cli     *mockClient
+		cluster *appsv1alpha1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = testing.FakeCluster(testing.ClusterName, testing.Namespace)
+		cli = newMockClient([]client.Object{cluster})
+	})
+
+	It("scheme", func() {
+		Expect(cli.Scheme().IsGroupRegistered(appsv1alpha1.GroupVersion.Group)).Should(BeTrue())
+		gvk, err := cli.GroupVersionKindFor(&corev1.ConfigMap{})
+		Expect(err).Should(Succeed())
+		Expect(gvk.Kind).Should(Equal("ConfigMap"))
+	})
+
+	It("update and patch the object", func() {
+		obj := cluster.DeepCopy()
+		obj.Spec.TerminationPolicy = appsv1alpha1.WipeOut
+		Expect(cli.Update(ctx, obj)).Should(Succeed())
+
+		patched := &appsv1alpha1.Cluster{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), patched)).Should(Succeed())
+		Expect(patched.Spec.TerminationPolicy).Should(Equal(appsv1alpha1.WipeOut))
+		base := patched.DeepCopy()
+		patched.Labels = map[string]string{"env": "test"}
+		Expect(cli.Patch(ctx, patched, client.MergeFrom(base))).Should(Succeed())
+
+		result := &appsv1alpha1.Cluster{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), result)).Should(Succeed())
+		Expect(result.Labels).Should(HaveKeyWithValue("env", "test"))
+		Expect(result.Spec.TerminationPolicy).Should(Equal(appsv1alpha1.WipeOut))
+
+		mutations := cli.Mutations()
+		Expect(mutations).Should(HaveLen(2))
+		Expect(mutations[0].Verb).Should(Equal("update"))
+		Expect(mutations[1].Verb).Should(Equal("patch"))
+		Expect(mutations[1].Object.GetLabels()).Should(HaveKeyWithValue("env", "test"))
+	})
+
+	It("update the status", func() {
+		obj := cluster.DeepCopy()
+		obj.Spec.TerminationPolicy = appsv1alpha1.WipeOut
+		obj.Status.Phase = appsv1alpha1.RunningClusterPhase
+		Expect(cli.Status().Update(ctx, obj)).Should(Succeed())
+
+		result := &appsv1alpha1.Cluster{}
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), result)).Should(Succeed())
+		Expect(result.Status.Phase).Should(Equal(appsv1alpha1.RunningClusterPhase))
+		// only the status is updated
+		Expect(result.Spec.TerminationPolicy).Should(Equal(cluster.Spec.TerminationPolicy))
+
+		base := result.DeepCopy()
+		result.Status.Phase = appsv1alpha1.StoppedClusterPhase
+		Expect(cli.Status().Patch(ctx, result, client.MergeFrom(base))).Should(Succeed())
+		status := &appsv1alpha1.Cluster{}
+		Expect(cli.SubResource("status").Get(ctx, cluster, status)).Should(Succeed())
+		Expect(status.Status.Phase).Should(Equal(appsv1alpha1.StoppedClusterPhase))
+
+		mutations := cli.Mutations()
+		Expect(mutations).Should(HaveLen(2))
+		Expect(mutations[0].SubResource).Should(Equal("status"))
+
+		By("update the status of the object not found")
+		notFound := testing.FakeCluster("not-found", testing.Namespace)
+		Expect(apierrors.IsNotFound(cli.Status().Update(ctx, notFound))).Should(BeTrue())
+	})
+
+	It("list the objects with options", func() {
+		newConfigMap := func(name, namespace string, labels map[string]string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			}
+		}
+		cli = newMockClient([]client.Object{
+			newConfigMap("mysql-config", testing.Namespace, map[string]string{"app": "mysql"}),
+			newConfigMap("mysql-scripts", testing.Namespace, map[string]string{"app": "mysql", "type": "scripts"}),
+			newConfigMap("redis-config", testing.Namespace, map[string]string{"app": "redis"}),
+			newConfigMap("mysql-config", "other", map[string]string{"app": "mysql"}),
+			cluster,
+		})
+		names := func(opts ...client.ListOption) []string {
+			list := &corev1.ConfigMapList{}
+			Expect(cli.List(ctx, list, opts...)).Should(Succeed())
+			var r []string
+			for _, cm := range list.Items {
+				r = append(r, cm.Namespace+"/"+cm.Name)
+			}
+			return r
+		}
+
+		Expect(names()).Should(HaveLen(4))
+		Expect(names(client.InNamespace("other"))).Should(Equal([]string{"other/mysql-config"}))
+		Expect(names(client.InNamespace(testing.Namespace), client.MatchingLabels{"app": "mysql"})).Should(Equal([]string{
+			testing.Namespace + "/mysql-config", testing.Namespace + "/mysql-scripts"}))
+		Expect(names(client.HasLabels{"type"})).Should(Equal([]string{testing.Namespace + "/mysql-scripts"}))
+		Expect(names(client.MatchingFields{"metadata.name": "redis-config"})).Should(Equal([]string{testing.Namespace + "/redis-config"}))
+		Expect(names(client.MatchingLabels{"app": "mysql"}, client.Limit(2))).Should(HaveLen(2))
+		Expect(names(client.MatchingLabels{"app": "postgresql"})).Should(BeEmpty())
+
+		clusters := &appsv1alpha1.ClusterList{}
+		Expect(cli.List(ctx, clusters)).Should(Succeed())
+		Expect(clusters.Items).Should(HaveLen(1))
+	})
+
+	It("create the subresource", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: testing.Namespace}}
+		Expect(cli.SubResource("eviction").Create(ctx, pod, &corev1.Pod{})).Should(Succeed())
+		Expect(cli.Mutations()).Should(HaveLen(1))
+		Expect(cli.SubResource("eviction").Get(ctx, pod, &corev1.Pod{})).Should(HaveOccurred())
+	})
+})
@@ -27,7 +27,9 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -42,6 +44,7 @@ type renderTPLCmdOpts struct {
 	// dynamic dynamic.Interface
 
 	clusterYaml    string
+	fromCluster    string
 	clusterDef     string
 	clusterVersion string
 
@@ -51,9 +54,19 @@ type renderTPLCmdOpts struct {
 	helmTemplateDir string
 
 	opts RenderedOptions
+
+	// the cluster and its components from the live cluster
+	clusterObj     *appsv1alpha1.Cluster
+	clusterObjects []client.Object
 }
 
 func (o *renderTPLCmdOpts) complete() error {
+	if o.fromCluster != "" && o.clusterYaml != "" {
+		return cfgcore.MakeError("--cluster and --from-cluster can not be specified at the same time")
+	}
+	if err := o.completeFromCluster(); err != nil {
+		return err
+	}
 	if err := o.checkAndHelmTemplate(); err != nil {
 		return err
 	}
@@ -71,12 +84,41 @@ func (o *renderTPLCmdOpts) complete() error {
 	return nil
 }
 
+// completeFromCluster gets the cluster and its components from the live cluster, the cluster definition
+// and cluster version of the cluster are used if not specified
+func (o *renderTPLCmdOpts) completeFromCluster() error {
+	if o.fromCluster == "" {
+		return nil
+	}
+	namespace, _, err := o.Factory.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	dynamic, err := o.Factory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	if o.clusterObj, o.clusterObjects, err = getLiveClusterObjects(dynamic, namespace, o.fromCluster); err != nil {
+		return err
+	}
+	if o.clusterDef == "" {
+		o.clusterDef = o.clusterObj.Spec.ClusterDefRef
+	}
+	if o.clusterVersion == "" {
+		o.clusterVersion = o.clusterObj.Spec.ClusterVersionRef
+	}
+	return nil
+}
+
 func (o *renderTPLCmdOpts) run() error {
 	viper.SetDefault(constant.KubernetesClusterDomainEnv, constant.DefaultDNSDomain)
 	workflow, err := NewWorkflowTemplateRender(o.helmOutputDir, o.opts, o.clusterDef, o.clusterVersion)
 	if err != nil {
 		return err
 	}
+	if o.clusterObj != nil {
+		workflow.WithClusterObjects(o.clusterObj, o.clusterObjects...)
+	}
 	return workflow.Do(o.outputDir)
 }
 
@@ -86,11 +128,15 @@ var templateExamples = templates.Examples(`
 
     # build all configspec
     kbcli builder template --helm deploy/redis -a
+
+    # render the templates against the live cluster mycluster and its components
+    kbcli builder template --helm deploy/redis --from-cluster mycluster
 `)
 
 // buildReconfigureCommonFlags build common flags for reconfigure command
 func (o *renderTPLCmdOpts) buildTemplateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.clusterYaml, "cluster", "", "the cluster yaml file")
+	cmd.Flags().StringVar(&o.fromCluster, "from-cluster", "", "render the templates against the cluster and its components in the current namespace of the live cluster")
 	cmd.Flags().StringVar(&o.clusterVersion, "cluster-version", "", "specify the cluster version name")
 	cmd.Flags().StringVar(&o.clusterDef, "cluster-definition", "", "specify the cluster definition name")
 	cmd.Flags().StringVarP(&o.outputDir, "output-dir", "o", "", "specify the output directory")
@@ -141,5 +187,6 @@ func NewComponentTemplateRenderCmd(f cmdutil.Factory, streams genericiooptions.I
 		},
 	}
 	o.buildTemplateFlags(cmd)
+	cmd.AddCommand(newTemplateTestCmd(streams))
 	return cmd
 }
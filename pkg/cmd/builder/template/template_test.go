@@ -26,9 +26,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/test/testdata"
 
 	"github.com/apecloud/kbcli/pkg/testing"
@@ -66,6 +69,34 @@ var _ = Describe("template", func() {
 		cmd.Run(cmd, []string{})
 	}
 
+	It("complete from the live cluster", func() {
+		comp := &appsv1alpha1.Component{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps.kubeblocks.io/v1alpha1", Kind: "Component"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testing.ClusterName + "-" + testing.ComponentName,
+				Namespace: testing.Namespace,
+				Labels:    map[string]string{constant.AppInstanceLabelKey: testing.ClusterName},
+			},
+		}
+		tf.Cleanup()
+		tf = testing.NewTestFactory(testing.Namespace)
+		tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeCluster(testing.ClusterName, testing.Namespace), comp)
+		o := &renderTPLCmdOpts{Factory: tf, IOStreams: streams, fromCluster: testing.ClusterName, clusterYaml: "cluster.yaml"}
+		Expect(o.complete()).Should(HaveOccurred())
+
+		o.clusterYaml = ""
+		Expect(o.completeFromCluster()).Should(Succeed())
+		Expect(o.clusterObj.Name).Should(Equal(testing.ClusterName))
+		Expect(o.clusterDef).Should(Equal(testing.ClusterDefName))
+		Expect(o.clusterVersion).Should(Equal(testing.ClusterVersionName))
+		Expect(o.clusterObjects).Should(HaveLen(1))
+
+		w := &templateRenderWorkflow{}
+		w.WithClusterObjects(o.clusterObj, o.clusterObjects...)
+		Expect(w.createClusterObject()).Should(Equal(o.clusterObj))
+		Expect(w.localObjects).Should(HaveLen(2))
+	})
+
 	It("should succeed", func() {
 		Skip("helm charts in ../../deploy are moved to other repos")
 		componentRootPath := testdata.SubTestDataPath("../../deploy")
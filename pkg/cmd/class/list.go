@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package class
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 
 	"github.com/apecloud/kubeblocks/pkg/class"
 
@@ -41,13 +43,27 @@ import (
 type ListOptions struct {
 	ClusterDefRef string
 	Factory       cmdutil.Factory
+	Format        printer.Format
+	TableOptions  printer.TableOptions
 	dynamic       dynamic.Interface
 	genericiooptions.IOStreams
 }
 
+// classInfo is the class printed in JSON or YAML format
+type classInfo struct {
+	Component       string `json:"component"`
+	Class           string `json:"class"`
+	CPU             string `json:"cpu"`
+	Memory          string `json:"memory"`
+	ClassDefinition string `json:"classDefinition,omitempty"`
+}
+
 var listClassExamples = templates.Examples(`
     # List all components classes in cluster definition apecloud-mysql
     kbcli class list --cluster-definition apecloud-mysql
+
+    # List the classes with their class definitions
+    kbcli class list --cluster-definition apecloud-mysql -o wide
 `)
 
 func NewListCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -63,6 +79,9 @@ func NewListCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 	}
 	flags.AddClusterDefinitionFlag(f, cmd, &o.ClusterDefRef)
 	util.CheckErr(cmd.MarkFlagRequired("cluster-definition"))
+	printer.AddOutputFlag(cmd, &o.Format)
+	o.TableOptions.AddFlags(cmd)
+	o.TableOptions.AddSortFlag(cmd)
 	return cmd
 }
 
@@ -77,20 +96,76 @@ func (o *ListOptions) run() error {
 	if err != nil {
 		return err
 	}
+	if !o.Format.IsTabular() {
+		return o.printStructured(clsMgr.GetClasses())
+	}
 	for compName, classes := range clsMgr.GetClasses() {
-		o.printClass(compName, classes)
+		if err = o.printClass(compName, classes); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (o *ListOptions) printClass(compName string, classes []*class.ComponentClassWithRef) {
+func (o *ListOptions) printClass(compName string, classes []*class.ComponentClassWithRef) error {
 	tbl := printer.NewTablePrinter(o.Out)
-	tbl.SetHeader("COMPONENT", "CLASS", "CPU", "MEMORY")
+	tbl.SetFormat(o.Format)
+	header := []interface{}{"COMPONENT", "CLASS", "CPU", "MEMORY"}
+	if o.Format == printer.Wide {
+		header = append(header, "CLASS-DEFINITION")
+	}
+	tbl.SetHeader(header...)
+	if err := tbl.ApplyOptions(&o.TableOptions); err != nil {
+		return err
+	}
 	sort.Sort(class.ByClassResource(classes))
 	for _, cls := range classes {
-		tbl.AddRow(compName, cls.Name, cls.CPU.String(), normalizeMemory(cls.Memory))
+		row := []interface{}{compName, cls.Name, cls.CPU.String(), normalizeMemory(cls.Memory)}
+		if o.Format == printer.Wide {
+			row = append(row, cls.ClassDefRef.Name)
+		}
+		tbl.AddRow(row...)
 	}
 	tbl.Print()
+	return nil
+}
+
+func (o *ListOptions) printStructured(compClasses map[string][]*class.ComponentClassWithRef) error {
+	var compNames []string
+	for compName := range compClasses {
+		compNames = append(compNames, compName)
+	}
+	sort.Strings(compNames)
+	infos := make([]classInfo, 0)
+	for _, compName := range compNames {
+		classes := compClasses[compName]
+		sort.Sort(class.ByClassResource(classes))
+		for _, cls := range classes {
+			infos = append(infos, classInfo{
+				Component:       compName,
+				Class:           cls.Name,
+				CPU:             cls.CPU.String(),
+				Memory:          normalizeMemory(cls.Memory),
+				ClassDefinition: cls.ClassDefRef.Name,
+			})
+		}
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if o.Format == printer.JSON {
+		data, err = json.MarshalIndent(infos, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(infos)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
 }
 
 func normalizeMemory(mem resource.Quantity) string {
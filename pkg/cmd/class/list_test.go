@@ -61,6 +61,23 @@ var _ = Describe("list", func() {
 		Expect(out.String()).To(ContainSubstring("mysql"))
 	})
 
+	It("should print with output options", func() {
+		cmd := NewListCommand(tf, streams)
+		_ = cmd.Flags().Set("cluster-definition", "apecloud-mysql")
+		_ = cmd.Flags().Set("output", "wide")
+		_ = cmd.Flags().Set("columns", "CLASS,CLASS-DEFINITION")
+		_ = cmd.Flags().Set("no-headers", "true")
+		cmd.Run(cmd, []string{})
+		Expect(out.String()).To(ContainSubstring("general-1c1g"))
+		Expect(out.String()).To(ContainSubstring(classDef.Name))
+		Expect(out.String()).ShouldNot(ContainSubstring("CLASS"))
+
+		out.Reset()
+		_ = cmd.Flags().Set("output", "json")
+		cmd.Run(cmd, []string{})
+		Expect(out.String()).To(ContainSubstring(`"class": "general-1c1g"`))
+	})
+
 	It("memory should be normalized", func() {
 		cases := []struct {
 			memory     string
@@ -26,7 +26,9 @@ import (
 
 	"github.com/spf13/cobra"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/discovery"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 	kccmd "k8s.io/kubectl/pkg/cmd"
@@ -37,6 +39,7 @@ import (
 
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 
+	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/cmd/addon"
 	"github.com/apecloud/kbcli/pkg/cmd/alert"
 	"github.com/apecloud/kbcli/pkg/cmd/auth"
@@ -47,21 +50,30 @@ import (
 	"github.com/apecloud/kbcli/pkg/cmd/cluster"
 	"github.com/apecloud/kbcli/pkg/cmd/clusterdefinition"
 	"github.com/apecloud/kbcli/pkg/cmd/clusterversion"
+	"github.com/apecloud/kbcli/pkg/cmd/completion"
+	"github.com/apecloud/kbcli/pkg/cmd/config"
 	"github.com/apecloud/kbcli/pkg/cmd/context"
 	"github.com/apecloud/kbcli/pkg/cmd/dashboard"
 	"github.com/apecloud/kbcli/pkg/cmd/dataprotection"
+	"github.com/apecloud/kbcli/pkg/cmd/doctor"
 	"github.com/apecloud/kbcli/pkg/cmd/fault"
+	"github.com/apecloud/kbcli/pkg/cmd/history"
 	infras "github.com/apecloud/kbcli/pkg/cmd/infrastructure"
 	"github.com/apecloud/kbcli/pkg/cmd/kubeblocks"
 	"github.com/apecloud/kbcli/pkg/cmd/migration"
+	"github.com/apecloud/kbcli/pkg/cmd/ops"
 	"github.com/apecloud/kbcli/pkg/cmd/options"
 	"github.com/apecloud/kbcli/pkg/cmd/organization"
 	"github.com/apecloud/kbcli/pkg/cmd/playground"
 	"github.com/apecloud/kbcli/pkg/cmd/plugin"
+	"github.com/apecloud/kbcli/pkg/cmd/rbac"
 	"github.com/apecloud/kbcli/pkg/cmd/report"
 	"github.com/apecloud/kbcli/pkg/cmd/version"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/multicluster"
 )
 
 const (
@@ -70,9 +82,8 @@ const (
 
 // TODO: add more commands
 var cloudCmds = map[string]bool{
-	"org":     true,
-	"logout":  true,
-	"context": true,
+	"org":    true,
+	"logout": true,
 }
 
 func init() {
@@ -88,6 +99,10 @@ func init() {
 		fmt.Println("Failed to add kbcli bin dir to PATH:", err)
 	}
 
+	// record the failure to the audit log before exiting
+	util.FatalHandlers = append(util.FatalHandlers, history.FatalHandler)
+	cmdutil.BehaviorOnFatal(util.Fatal)
+
 	// when the kubernetes cluster is not ready, the runtime will output the error
 	// message like "couldn't get resource list for", we ignore it
 	utilruntime.ErrorHandlers[0] = func(err error) {
@@ -132,6 +147,9 @@ func NewDefaultCliCmd() *cobra.Command {
 }
 
 func NewCliCmd() *cobra.Command {
+	// add kubernetes flags like kubectl
+	kubeConfigFlags := util.NewConfigFlagNoWarnings()
+
 	cmd := &cobra.Command{
 		Use:   cliName,
 		Short: "KubeBlocks CLI.",
@@ -153,11 +171,64 @@ A Command Line Interface for KubeBlocks`,
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
 		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			history.FinishAudit("")
+		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if cmd.Name() == cobra.ShellCompRequestCmd {
 				kcplugin.SetupPluginCompletion(cmd, args)
+				// do not hang the shell if the API server is unreachable
+				if kubeConfigFlags.Timeout != nil && (*kubeConfigFlags.Timeout == "" || *kubeConfigFlags.Timeout == "0") {
+					*kubeConfigFlags.Timeout = util.CompletionRequestTimeout
+				}
+			}
+
+			// honor the current kbcli context if kubeconfig or context is not specified explicitly,
+			// it takes precedence over the flag defaults from the kbcli config file
+			if err := context.ApplyCurrentContext(kubeConfigFlags, cmd.Flags()); err != nil {
+				return err
+			}
+
+			// resolve the flag defaults from the kbcli config file
+			config.ApplyDefaults(cmd.Flags())
+
+			// attribute the requests to the subcommand and the impersonated user in the audit logs
+			util.SetUserAgentCommand(cmd.CommandPath())
+			util.SetImpersonation(kubeConfigFlags)
+
+			// apply the color theme shared by all outputs
+			colorMode, _ := cmd.Flags().GetString("color")
+			if err := printer.SetColorMode(colorMode); err != nil {
+				return err
+			}
+			if err := printer.SetStatusColors(viper.GetString(types.CfgKeyStatusColors)); err != nil {
+				return err
+			}
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			printer.SetQuiet(quiet)
+
+			// fan out the read command to multiple kube contexts
+			if contexts, _ := cmd.Flags().GetStringSlice(multicluster.ContextsFlag); len(contexts) > 0 {
+				return setupMultiClusterRun(cmd, contexts, kubeConfigFlags)
 			}
 
+			// export the manifests instead of applying them if the export directory is specified
+			exportDir, _ := cmd.Flags().GetString("export-to-dir")
+			action.SetExportDir(exportDir)
+
+			// select the served versions of the KubeBlocks APIs on the connected cluster lazily
+			types.SetAPIDiscovery(func() (discovery.DiscoveryInterface, error) {
+				return kubeConfigFlags.ToDiscoveryClient()
+			})
+
+			// detect the namespace by the cluster name in the auto-namespace mode
+			if err := cluster.ApplyAutoNamespace(cmd, args, kubeConfigFlags, genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}); err != nil {
+				return err
+			}
+
+			// record the mutate commands to the audit log if it's enabled
+			history.StartAudit(cmd, args, kubeConfigFlags)
+
 			commandPath := cmd.CommandPath()
 			parts := strings.Split(commandPath, " ")
 			if len(parts) < 2 {
@@ -175,17 +246,36 @@ A Command Line Interface for KubeBlocks`,
 	// when adding them with hyphen instead of the original name.
 	cmd.SetGlobalNormalizationFunc(cliflag.WarnWordSepNormalizeFunc)
 
+	// the invalid flags exit with the validation exit code
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return clierrors.NewValidation("%v", err)
+	})
+
 	flags := cmd.PersistentFlags()
 
-	// add kubernetes flags like kubectl
-	kubeConfigFlags := util.NewConfigFlagNoWarnings()
 	kubeConfigFlags.AddFlags(flags)
-	matchVersionKubeConfigFlags := cmdutil.NewMatchVersionFlags(kubeConfigFlags)
+	matchVersionKubeConfigFlags := cmdutil.NewMatchVersionFlags(util.NewCachedConfigFlags(kubeConfigFlags))
 	matchVersionKubeConfigFlags.AddFlags(flags)
 
+	// add the rate limit and retry flags of the REST clients
+	util.AddClientFlags(flags)
+
+	// add the timezone and format flags of the time outputs
+	util.AddTimeFlags(flags)
+
 	// add klog flags
 	util.AddKlogFlags(flags)
 
+	flags.String("color", string(printer.ColorAuto), fmt.Sprintf("When to color the outputs, one of %s. The auto mode colors the outputs if the stdout is a terminal and NO_COLOR is not set", strings.Join(printer.ColorModes(), "|")))
+
+	flags.Bool("quiet", false, "Suppress the decorative outputs such as the notes, hints, spinners and progress, only the results and errors are printed")
+
+	flags.String("export-to-dir", "", "Write the manifests that the create, update, expose, configure and OpsRequest commands would apply to the directory in a kustomize layout instead of applying them, the commands changing the resources on the server directly reject it")
+
+	flags.StringSlice(multicluster.ContextsFlag, nil, "Run the read commands such as list and describe in the comma separated kube contexts concurrently, and merge the tables with a CONTEXT column")
+
+	flags.Bool(types.CfgKeyAutoNamespace, false, "Search the clusters across namespaces if they are not found in the current namespace, the mutating commands require to confirm the namespace found")
+
 	f := cmdutil.NewFactory(matchVersionKubeConfigFlags)
 	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 
@@ -195,11 +285,14 @@ A Command Line Interface for KubeBlocks`,
 		auth.NewLogout(ioStreams),
 		organization.NewOrganizationCmd(ioStreams),
 		context.NewContextCmd(ioStreams),
+		config.NewConfigCmd(ioStreams),
+		history.NewHistoryCmd(ioStreams),
 		playground.NewPlaygroundCmd(ioStreams),
 		kubeblocks.NewKubeBlocksCmd(f, ioStreams),
 		bench.NewBenchCmd(f, ioStreams),
 		options.NewCmdOptions(ioStreams.Out),
 		version.NewVersionCmd(f),
+		completion.NewCompletionCmd(ioStreams),
 		dashboard.NewDashboardCmd(f, ioStreams),
 		clusterversion.NewClusterVersionCmd(f, ioStreams),
 		clusterdefinition.NewClusterDefinitionCmd(f, ioStreams),
@@ -214,6 +307,9 @@ A Command Line Interface for KubeBlocks`,
 		infras.NewInfraCmd(ioStreams),
 		backuprepo.NewBackupRepoCmd(f, ioStreams),
 		dataprotection.NewDataProtectionCmd(f, ioStreams),
+		doctor.NewDoctorCmd(f, ioStreams),
+		rbac.NewRBACCmd(f, ioStreams),
+		ops.NewOpsCmd(f, ioStreams),
 	)
 
 	filters := []string{"options"}
@@ -235,10 +331,32 @@ A Command Line Interface for KubeBlocks`,
 	return cmd
 }
 
+// setupMultiClusterRun replaces the run function of the command to run it in the contexts by the multicluster runner
+func setupMultiClusterRun(cmd *cobra.Command, contexts []string, kubeConfigFlags *genericclioptions.ConfigFlags) error {
+	if !multicluster.IsReadCommand(cmd) {
+		return fmt.Errorf("--%s only supports the read commands such as list and describe", multicluster.ContextsFlag)
+	}
+	// the contexts are looked up in the kubeconfig resolved by the parent, such as the one of the kbcli context
+	exec, err := multicluster.NewExecFunc(os.Args[1:], kubeConfigFlags.ToRawKubeConfigLoader().ConfigAccess().GetExplicitFile())
+	if err != nil {
+		return err
+	}
+	runner := multicluster.NewRunner(contexts, exec, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	runner.MergeTable = multicluster.IsTableOutput(cmd)
+	cmd.RunE = nil
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		util.CheckErr(runner.Run())
+	}
+	return nil
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
+	if cliHome, err := util.GetCliHomeDir(); err == nil {
+		viper.AddConfigPath(cliHome)
+	}
 	viper.AddConfigPath(fmt.Sprintf("/etc/%s/", cliName))
 	viper.AddConfigPath(fmt.Sprintf("$HOME/.%s/", cliName))
 	viper.AutomaticEnv() // read in environment variables that match
@@ -252,31 +370,35 @@ func initConfig() {
 	viper.SetDefault(types.CfgKeyHelmRepoURL, "")
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		klog.V(1).Infof("Using config file: %s", viper.ConfigFileUsed())
 	}
 }
 
 func registerCompletionFuncForGlobalFlags(cmd *cobra.Command, f cmdutil.Factory) {
-	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"namespace",
+	util.RegisterFlagCompletionFunc(cmd, "namespace",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.CompGetResource(f, "namespace", toComplete), cobra.ShellCompDirectiveNoFileComp
-		}))
-	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
+		})
+	util.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"context",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.ListContextsInConfig(toComplete), cobra.ShellCompDirectiveNoFileComp
 		}))
-	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
+	util.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"cluster",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.ListClustersInConfig(toComplete), cobra.ShellCompDirectiveNoFileComp
 		}))
-	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
+	util.CheckErr(cmd.RegisterFlagCompletionFunc(
 		"user",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.ListUsersInConfig(toComplete), cobra.ShellCompDirectiveNoFileComp
 		}))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc(
+		"color",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return printer.ColorModes(), cobra.ShellCompDirectiveNoFileComp
+		}))
 }
 
 func registerUsageAndHelpFuncForSubCommand(cmd *cobra.Command, helpFunc func(*cobra.Command, []string), usageFunc func(command *cobra.Command) error) {
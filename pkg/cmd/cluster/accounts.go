@@ -40,6 +40,10 @@ var (
 		kbcli cluster create-account CLUSTERNAME --name USERNAME
 		# create account for instance
 		kbcli cluster create-account  --instance INSTANCE --name USERNAME
+		# create account and write the credentials to the Secret mycluster-mysql-account-username
+		kbcli cluster create-account CLUSTERNAME --component mysql --name username --sync-secret
+		# create account and write the credentials to the Secret with the specified name
+		kbcli cluster create-account CLUSTERNAME --name USERNAME --sync-secret --secret-name "{{.Cluster}}-{{.Account}}-credential"
  `)
 
 	deleteUserExamples = templates.Examples(`
@@ -68,6 +72,14 @@ var (
 		# list all users from instance
 		kbcli cluster list-accounts --instance INSTANCE
 	`)
+	listAccountSecretsExample = templates.Examples(`
+		# list the Secrets synchronized with the credentials of the accounts in the current namespace
+		kbcli cluster list-account-secrets
+		# list the Secrets of the accounts of the cluster
+		kbcli cluster list-account-secrets CLUSTERNAME
+		# list the Secrets of the accounts in all namespaces
+		kbcli cluster list-account-secrets -A
+	`)
 	grantRoleExamples = templates.Examples(`
 		# grant role to user
 		kbcli cluster grant-role CLUSTERNAME --component COMPNAME --name USERNAME --role ROLENAME
@@ -94,9 +106,9 @@ func NewCreateAccountCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 		Example:           createUserExamples,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Validate(args))
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Run(cmd, f, streams))
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Complete(f))
+			util.CheckErr(o.Run(cmd, f, streams))
 		},
 	}
 	o.AddFlags(cmd)
@@ -111,9 +123,9 @@ func NewDeleteAccountCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 		Example:           deleteUserExamples,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Validate(args))
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Run(cmd, f, streams))
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Complete(f))
+			util.CheckErr(o.Run(cmd, f, streams))
 		},
 	}
 	o.AddFlags(cmd)
@@ -129,9 +141,9 @@ func NewDescAccountCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *c
 		Example:           descUserExamples,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Validate(args))
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Run(cmd, f, streams))
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Complete(f))
+			util.CheckErr(o.Run(cmd, f, streams))
 		},
 	}
 	o.AddFlags(cmd)
@@ -148,9 +160,26 @@ func NewListAccountsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *
 		Example:           listUsersExample,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Validate(args))
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Run(cmd, f, streams))
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Complete(f))
+			util.CheckErr(o.Run(cmd, f, streams))
+		},
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func NewListAccountSecretsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := accounts.NewListSecretsOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:               "list-account-secrets [CLUSTERNAME]",
+		Short:             "List the Secrets synchronized with the credentials of the accounts",
+		Example:           listAccountSecretsExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, args))
+			util.CheckErr(o.Run())
 		},
 	}
 	o.AddFlags(cmd)
@@ -167,9 +196,9 @@ func NewGrantOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *cob
 		Example:           grantRoleExamples,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Validate(args))
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Run(cmd, f, streams))
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Complete(f))
+			util.CheckErr(o.Run(cmd, f, streams))
 		},
 	}
 	o.AddFlags(cmd)
@@ -186,9 +215,9 @@ func NewRevokeOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 		Example:           revokeRoleExamples,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Validate(args))
-			cmdutil.CheckErr(o.Complete(f))
-			cmdutil.CheckErr(o.Run(cmd, f, streams))
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Complete(f))
+			util.CheckErr(o.Run(cmd, f, streams))
 		},
 	}
 	o.AddFlags(cmd)
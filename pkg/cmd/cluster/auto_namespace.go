@@ -0,0 +1,259 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+// clusterNameArgAnnotation marks the commands whose arguments are the names of existing clusters,
+// the value is clusterNameArgRead or clusterNameArgMutate.
+const clusterNameArgAnnotation = "kbcli.apecloud.com/cluster-name-arg"
+
+const (
+	clusterNameArgRead   = "read"
+	clusterNameArgMutate = "mutate"
+)
+
+// clusterNameArgCmds are the cluster subcommands taking the names of existing clusters as the
+// arguments, the namespace of which can be detected in the auto-namespace mode.
+var clusterNameArgCmds = []string{
+	"connect", "describe", "list-instances", "list-components", "list-events", "label", "delete",
+	"update", "stop", "start", "restart", "upgrade", "volume-expand", "vscale", "hscale", "promote",
+	"list-ops", "delete-ops", "expose", "configure", "edit-config", "describe-config", "explain-config",
+	"list-backup-policy", "describe-backup-policy", "backup", "list-backups", "delete-backup",
+	"logs", "list-logs", "metrics", "create-account", "delete-account", "describe-account", "list-accounts", "get-credentials",
+	"grant-role", "revoke-role",
+}
+
+// clusterNameArgReadCmds are the commands in clusterNameArgCmds not changing the cluster, the
+// namespace is switched without confirmation for them.
+var clusterNameArgReadCmds = []string{
+	"connect", "describe", "list-instances", "list-components", "list-events", "list-ops",
+	"describe-config", "explain-config", "list-backup-policy", "describe-backup-policy", "list-backups",
+	"logs", "list-logs", "metrics", "describe-account", "list-accounts", "get-credentials",
+}
+
+// isTerminalInput returns true if the input is a terminal, the user is never prompted otherwise
+var isTerminalInput = func(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+func markClusterNameArgCmds(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		if !slices.Contains(clusterNameArgCmds, c.Name()) {
+			continue
+		}
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[clusterNameArgAnnotation] = clusterNameArgMutate
+		if slices.Contains(clusterNameArgReadCmds, c.Name()) {
+			c.Annotations[clusterNameArgAnnotation] = clusterNameArgRead
+		}
+	}
+}
+
+// getClusterNamesToResolve returns the cluster name specified by the --cluster flag or
+// the arguments of the commands marked with clusterNameArgAnnotation.
+func getClusterNamesToResolve(cmd *cobra.Command, args []string) []string {
+	if f := cmd.Flags().Lookup("cluster"); f != nil && f.Changed {
+		return []string{f.Value.String()}
+	}
+	if cmd.Annotations[clusterNameArgAnnotation] != "" {
+		return args
+	}
+	return nil
+}
+
+// ApplyAutoNamespace switches the namespace of the config flags to the namespace of the clusters
+// specified by the command if the auto-namespace mode is enabled and the clusters are not found in
+// the current namespace. The namespace specified explicitly by the --namespace flag is honored.
+// The resolved namespace is printed to the error output, and the mutating commands require the
+// user to confirm it.
+func ApplyAutoNamespace(cmd *cobra.Command, args []string, configFlags *genericclioptions.ConfigFlags, streams genericiooptions.IOStreams) error {
+	if enabled, _ := cmd.Flags().GetBool(types.CfgKeyAutoNamespace); !enabled {
+		return nil
+	}
+	if f := cmd.Flags().Lookup("namespace"); f == nil || f.Changed || configFlags.Namespace == nil {
+		return nil
+	}
+	names := getClusterNamesToResolve(cmd, args)
+	if len(names) == 0 {
+		return nil
+	}
+
+	// use a non-persistent copy of the config flags, otherwise the client config cached by
+	// the factory will keep the current namespace even if it's switched
+	flags := copyConfigFlags(configFlags)
+	namespace, _, err := flags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	restConfig, err := flags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveClustersNamespace(client, namespace, names, streams)
+	if err != nil || resolved == namespace {
+		return err
+	}
+
+	fmt.Fprintf(streams.ErrOut, "Cluster %s is found in namespace %s\n", strings.Join(names, ", "), resolved)
+	if cmd.Annotations[clusterNameArgAnnotation] == clusterNameArgMutate {
+		if !isTerminalInput(streams.In) {
+			return fmt.Errorf("cluster %s is not found in namespace %s, specify the namespace %s by --namespace to %s it",
+				strings.Join(names, ", "), namespace, resolved, cmd.Name())
+		}
+		if err = prompt.Confirm([]string{resolved}, streams.In, "", "Please type the namespace to confirm:"); err != nil {
+			return err
+		}
+	}
+	*configFlags.Namespace = resolved
+	return nil
+}
+
+// resolveClustersNamespace returns the namespace of all the clusters, an error is returned if
+// the clusters are in different namespaces.
+func resolveClustersNamespace(client dynamic.Interface, namespace string, names []string, streams genericiooptions.IOStreams) (string, error) {
+	resolved := map[string][]string{}
+	for _, name := range names {
+		ns, err := detectClusterNamespace(client, namespace, name, streams.In, streams.ErrOut)
+		if err != nil {
+			return "", err
+		}
+		resolved[ns] = append(resolved[ns], name)
+	}
+	if len(resolved) == 1 {
+		return maps.Keys(resolved)[0], nil
+	}
+	var found []string
+	for ns, clusters := range resolved {
+		found = append(found, fmt.Sprintf("%s in %s", strings.Join(clusters, ", "), ns))
+	}
+	sort.Strings(found)
+	return "", fmt.Errorf("the clusters are in different namespaces: %s, specify the namespace by --namespace", strings.Join(found, "; "))
+}
+
+// detectClusterNamespace returns the namespace of the cluster, the current namespace is returned
+// if the cluster exists in it, or the clusters in all namespaces can not be listed. The user is
+// prompted to choose one if the cluster is found in multiple namespaces and the input is a terminal.
+func detectClusterNamespace(client dynamic.Interface, namespace string, name string, in io.Reader, out io.Writer) (string, error) {
+	_, err := client.Resource(types.ClusterGVR()).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err == nil {
+		return namespace, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		klog.V(1).Infof("failed to get cluster %s in namespace %s: %v", name, namespace, err)
+		return namespace, nil
+	}
+
+	objs, err := client.Resource(types.ClusterGVR()).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "metadata.name=" + name,
+	})
+	if err != nil {
+		// the user may not be allowed to list the clusters across namespaces
+		klog.V(1).Infof("failed to list clusters in all namespaces: %v", err)
+		return namespace, nil
+	}
+	var namespaces []string
+	for _, obj := range objs.Items {
+		if obj.GetName() == name {
+			namespaces = append(namespaces, obj.GetNamespace())
+		}
+	}
+	sort.Strings(namespaces)
+
+	switch len(namespaces) {
+	case 0:
+		return namespace, nil
+	case 1:
+		return namespaces[0], nil
+	}
+	if !isTerminalInput(in) {
+		return "", fmt.Errorf("cluster %s is found in multiple namespaces: %s, specify the namespace by --namespace",
+			name, strings.Join(namespaces, ", "))
+	}
+	fmt.Fprintf(out, "Cluster %s is found in multiple namespaces: %s\n", name, strings.Join(namespaces, ", "))
+	selected, err := prompt.NewPrompt("Please type the namespace to use:", func(entered string) error {
+		if !slices.Contains(namespaces, strings.TrimSpace(entered)) {
+			return fmt.Errorf("namespace should be one of %s", strings.Join(namespaces, ", "))
+		}
+		return nil
+	}, in).Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(selected), nil
+}
+
+// copyConfigFlags copies the config flags to a non-persistent one sharing the flag values
+func copyConfigFlags(f *genericclioptions.ConfigFlags) *genericclioptions.ConfigFlags {
+	c := genericclioptions.NewConfigFlags(false)
+	c.CacheDir = f.CacheDir
+	c.KubeConfig = f.KubeConfig
+	c.ClusterName = f.ClusterName
+	c.AuthInfoName = f.AuthInfoName
+	c.Context = f.Context
+	c.Namespace = f.Namespace
+	c.APIServer = f.APIServer
+	c.TLSServerName = f.TLSServerName
+	c.Insecure = f.Insecure
+	c.CertFile = f.CertFile
+	c.KeyFile = f.KeyFile
+	c.CAFile = f.CAFile
+	c.BearerToken = f.BearerToken
+	c.Impersonate = f.Impersonate
+	c.ImpersonateUID = f.ImpersonateUID
+	c.ImpersonateGroup = f.ImpersonateGroup
+	c.Username = f.Username
+	c.Password = f.Password
+	c.Timeout = f.Timeout
+	c.DisableCompression = f.DisableCompression
+	c.WrapConfigFn = f.WrapConfigFn
+	return c
+}
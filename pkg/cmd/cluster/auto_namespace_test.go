@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("auto namespace", func() {
+	const name = "test-cluster"
+
+	It("mark the commands taking cluster name", func() {
+		cmd := NewClusterCmd(clitesting.NewTestFactory("default"), genericiooptions.NewTestIOStreamsDiscard())
+		for _, c := range cmd.Commands() {
+			mark, marked := c.Annotations[clusterNameArgAnnotation]
+			switch c.Name() {
+			case "describe", "logs":
+				Expect(mark).Should(Equal(clusterNameArgRead), c.Name())
+			case "delete", "stop", "restart", "backup":
+				Expect(mark).Should(Equal(clusterNameArgMutate), c.Name())
+			case "create", "restore", "describe-ops", "describe-backup":
+				Expect(marked).Should(BeFalse(), c.Name())
+			}
+		}
+	})
+
+	It("get the cluster name to resolve", func() {
+		cmd := &cobra.Command{Use: "test"}
+		Expect(getClusterNamesToResolve(cmd, []string{name})).Should(BeEmpty())
+
+		cmd.Annotations = map[string]string{clusterNameArgAnnotation: clusterNameArgMutate}
+		Expect(getClusterNamesToResolve(cmd, nil)).Should(BeEmpty())
+		Expect(getClusterNamesToResolve(cmd, []string{name, "other"})).Should(Equal([]string{name, "other"}))
+
+		cmd.Flags().String("cluster", "", "")
+		Expect(cmd.Flags().Set("cluster", "other")).Should(Succeed())
+		Expect(getClusterNamesToResolve(cmd, []string{name})).Should(Equal([]string{"other"}))
+	})
+
+	It("detect the cluster namespace", func() {
+		out := &bytes.Buffer{}
+		client := clitesting.FakeDynamicClient(clitesting.FakeCluster(name, "ns1"))
+		By("the cluster exists in the current namespace")
+		Expect(detectClusterNamespace(client, "ns1", name, nil, out)).Should(Equal("ns1"))
+
+		By("the cluster is found in another namespace")
+		Expect(detectClusterNamespace(client, "default", name, nil, out)).Should(Equal("ns1"))
+
+		By("the cluster is not found")
+		Expect(detectClusterNamespace(client, "default", "not-exist", nil, out)).Should(Equal("default"))
+
+		By("the cluster is found in multiple namespaces without terminal")
+		client = clitesting.FakeDynamicClient(clitesting.FakeCluster(name, "ns1"), clitesting.FakeCluster(name, "ns2"))
+		in := &bytes.Buffer{}
+		_, _ = in.Write([]byte("ns2\n"))
+		_, err := detectClusterNamespace(client, "default", name, in, out)
+		Expect(err).Should(MatchError(ContainSubstring("--namespace")))
+
+		By("the cluster is found in multiple namespaces")
+		isTerminal := isTerminalInput
+		defer func() { isTerminalInput = isTerminal }()
+		isTerminalInput = func(io.Reader) bool { return true }
+		Expect(detectClusterNamespace(client, "default", name, in, out)).Should(Equal("ns2"))
+		Expect(out.String()).Should(ContainSubstring("ns1, ns2"))
+	})
+
+	It("resolve the namespace of the clusters", func() {
+		streams, _, _, _ := genericiooptions.NewTestIOStreams()
+		client := clitesting.FakeDynamicClient(clitesting.FakeCluster(name, "ns1"), clitesting.FakeCluster("other", "ns1"),
+			clitesting.FakeCluster("another", "ns2"))
+		Expect(resolveClustersNamespace(client, "default", []string{name, "other"}, streams)).Should(Equal("ns1"))
+
+		_, err := resolveClustersNamespace(client, "default", []string{name, "another"}, streams)
+		Expect(err).Should(MatchError(ContainSubstring("different namespaces")))
+	})
+})
@@ -0,0 +1,579 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	backupSchedulerName = "kbcli-backup-scheduler"
+
+	// backupBlackoutLabelKey is the label key of the CronJobs which suspend and resume the backup
+	// schedules, the value is start or end.
+	backupBlackoutLabelKey = "dataprotection.kubeblocks.io/blackout"
+	// blackoutWindowsAnnotationKey records the blackout windows of the backup schedule
+	blackoutWindowsAnnotationKey = "dataprotection.kubeblocks.io/blackout-windows"
+	// blackoutMethodsAnnotationKey records the backup methods suspended in the blackout windows
+	blackoutMethodsAnnotationKey = "dataprotection.kubeblocks.io/blackout-methods"
+	// blackoutSuspendedAnnotationKey marks the backup schedule is suspended by a blackout window
+	blackoutSuspendedAnnotationKey = "dataprotection.kubeblocks.io/blackout-suspended"
+)
+
+var editBackupScheduleExample = templates.Examples(`
+	# Suspend the scheduled backups of cluster mycluster from Saturday 22:00 to Sunday 02:00 in UTC
+	kbcli cluster edit-backup-schedule mycluster --blackout "Sat 22:00-02:00"
+
+	# Suspend the scheduled backups from 01:00 to 03:00 every day, and from 12:00 to 14:00 on weekdays
+	kbcli cluster edit-backup-schedule mycluster --blackout "01:00-03:00" --blackout "Mon,Tue,Wed,Thu,Fri 12:00-14:00"
+
+	# Remove the blackout windows and resume the suspended backup schedules
+	kbcli cluster edit-backup-schedule mycluster --clear-blackout`)
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// blackoutWindow is a period in UTC during which the scheduled backups are suspended, it is
+// formatted as "[DAYS ]HH:MM-HH:MM", and ends in the next day if the end is not after the start.
+type blackoutWindow struct {
+	// days are the weekdays the window starts, empty means every day
+	days  []time.Weekday
+	start time.Duration
+	end   time.Duration
+}
+
+func parseBlackoutWindow(s string) (*blackoutWindow, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf(`invalid blackout window %q, %s, it should be formatted as "[DAYS ]HH:MM-HH:MM" such as "Sat,Sun 22:00-02:00"`, s, reason)
+	}
+	w := &blackoutWindow{}
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+	case 2:
+		for _, day := range strings.Split(fields[0], ",") {
+			i := slices.Index(weekdayNames, strings.ToLower(day))
+			if i < 0 {
+				return nil, invalid(fmt.Sprintf("unknown weekday %s", day))
+			}
+			if !slices.Contains(w.days, time.Weekday(i)) {
+				w.days = append(w.days, time.Weekday(i))
+			}
+		}
+	default:
+		return nil, invalid("unexpected fields")
+	}
+
+	period := strings.Split(fields[len(fields)-1], "-")
+	if len(period) != 2 {
+		return nil, invalid("missing the end time")
+	}
+	var err error
+	if w.start, err = parseClockTime(period[0]); err != nil {
+		return nil, invalid(err.Error())
+	}
+	if w.end, err = parseClockTime(period[1]); err != nil {
+		return nil, invalid(err.Error())
+	}
+	if w.start == w.end {
+		return nil, invalid("the start and end time are the same")
+	}
+	return w, nil
+}
+
+// parseClockTime parses the time of the day formatted as HH:MM
+func parseClockTime(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// cronDays returns the weekdays field of the cron expression, the days are shifted to the next
+// day if the window ends in the next day.
+func (w *blackoutWindow) cronDays(nextDay bool) string {
+	if len(w.days) == 0 {
+		return "*"
+	}
+	var days []string
+	for _, d := range w.days {
+		if nextDay {
+			d = (d + 1) % 7
+		}
+		days = append(days, strconv.Itoa(int(d)))
+	}
+	return strings.Join(days, ",")
+}
+
+func (w *blackoutWindow) startSchedule() string {
+	return fmt.Sprintf("%d %d * * %s", int(w.start.Minutes())%60, int(w.start.Hours()), w.cronDays(false))
+}
+
+func (w *blackoutWindow) endSchedule() string {
+	return fmt.Sprintf("%d %d * * %s", int(w.end.Minutes())%60, int(w.end.Hours()), w.cronDays(w.end < w.start))
+}
+
+// contains checks whether the time is in the window, which may start in the previous day
+func (w *blackoutWindow) contains(t time.Time) bool {
+	t = t.UTC()
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	for _, day := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		if len(w.days) > 0 && !slices.Contains(w.days, day.Weekday()) {
+			continue
+		}
+		start, end := day.Add(w.start), day.Add(w.end)
+		if w.end < w.start {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+type editBackupScheduleOptions struct {
+	clusterName    string
+	namespace      string
+	blackouts      []string
+	clearBlackouts bool
+	image          string
+
+	windows []*blackoutWindow
+
+	dynamic dynamic.Interface
+	client  kubernetes.Interface
+	genericiooptions.IOStreams
+}
+
+func NewEditBackupScheduleCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &editBackupScheduleOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "edit-backup-schedule NAME",
+		Short:             "Edit the backup schedule of a cluster, such as the blackout windows during which the scheduled backups are suspended.",
+		Example:           editBackupScheduleExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringArrayVar(&o.blackouts, "blackout", nil, `The blackout window in UTC during which the scheduled backups are suspended, formatted as "[DAYS ]HH:MM-HH:MM" such as "Sat 22:00-02:00", the window ends in the next day if the end is not after the start. It replaces the existing blackout windows and can be specified multiple times`)
+	cmd.Flags().BoolVar(&o.clearBlackouts, "clear-blackout", false, "Remove the blackout windows and resume the suspended backup schedules")
+	cmd.Flags().StringVar(&o.image, "scheduler-image", "", schedulerImageUsage("The kubectl image used by the CronJobs to suspend and resume the backup schedules"))
+	return cmd
+}
+
+func (o *editBackupScheduleOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to edit the backup schedule of one cluster")
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *editBackupScheduleOptions) validate() error {
+	if len(o.blackouts) == 0 && !o.clearBlackouts {
+		return clierrors.NewValidation("missing --blackout or --clear-blackout")
+	}
+	if len(o.blackouts) > 0 && o.clearBlackouts {
+		return clierrors.NewValidation("--blackout and --clear-blackout can not be specified at the same time")
+	}
+	o.windows = nil
+	for _, s := range o.blackouts {
+		w, err := parseBlackoutWindow(s)
+		if err != nil {
+			return err
+		}
+		o.windows = append(o.windows, w)
+	}
+	return nil
+}
+
+// getBackupSchedules returns the backup schedules of the cluster
+func (o *editBackupScheduleOptions) getBackupSchedules() ([]dpv1alpha1.BackupSchedule, error) {
+	list, err := o.dynamic.Resource(types.BackupScheduleGVR()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.clusterName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no backup schedule found in cluster %s", o.clusterName)
+	}
+	return util.ConvertUnstructuredList[dpv1alpha1.BackupSchedule](list.Items, nil)
+}
+
+// blackoutMethods returns the backup methods of the schedule managed by the blackout windows, they
+// are the recorded methods if the schedule is suspended now, or the enabled methods and the methods
+// suspended by the maintenance mode otherwise.
+func blackoutMethods(schedule *dpv1alpha1.BackupSchedule) []string {
+	if schedule.Annotations[blackoutSuspendedAnnotationKey] == "true" {
+		return blackoutSuspendedMethods(schedule)
+	}
+	methods := maintenanceMethods(schedule)
+	for _, p := range schedule.Spec.Schedules {
+		if p.Enabled != nil && *p.Enabled && !slices.Contains(methods, p.BackupMethod) {
+			methods = append(methods, p.BackupMethod)
+		}
+	}
+	return methods
+}
+
+// blackoutSuspendedMethods returns the backup methods suspended by a blackout window now
+func blackoutSuspendedMethods(schedule *dpv1alpha1.BackupSchedule) []string {
+	if schedule.Annotations[blackoutSuspendedAnnotationKey] != "true" {
+		return nil
+	}
+	if methods := schedule.Annotations[blackoutMethodsAnnotationKey]; methods != "" {
+		return strings.Split(methods, ",")
+	}
+	return nil
+}
+
+// excludeMethods returns the methods not in the excluded ones, it's used to keep the methods suspended
+// by the maintenance mode or a blackout window when the other one resumes the backups.
+func excludeMethods(methods []string, excluded []string) []string {
+	var res []string
+	for _, m := range methods {
+		if !slices.Contains(excluded, m) {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// buildResumeCommands builds the commands to resume the methods of the backup schedule one by one, the
+// method is skipped at runtime if the annotations of the schedule printed by the jsonpath match the
+// pattern, so the CronJobs do not resume the backups suspended by the others after they are created.
+func buildResumeCommands(s *dpv1alpha1.BackupSchedule, methods []string, jsonPath, pattern string) ([]string, error) {
+	var cmds []string
+	for _, m := range methods {
+		patch, err := buildEnablePatch(s, []string{m}, true)
+		if err != nil {
+			return nil, err
+		}
+		if patch == "" {
+			continue
+		}
+		cmds = append(cmds, fmt.Sprintf("(kubectl get backupschedule %s -n %s -o jsonpath='%s' | grep -Eq '%s' || kubectl patch backupschedule %s -n %s --type=json -p '%s')",
+			s.Name, s.Namespace, jsonPath, fmt.Sprintf(pattern, regexp.QuoteMeta(m)), s.Name, s.Namespace, patch))
+	}
+	return cmds, nil
+}
+
+// annotationJSONPath returns the jsonpath of the annotation
+func annotationJSONPath(key string) string {
+	return fmt.Sprintf("{.metadata.annotations.%s}", strings.ReplaceAll(key, ".", "\\."))
+}
+
+// buildEnablePatch builds the JSON patch to enable or disable the schedule policies of the methods,
+// the policies are located by the index and tested by the method, so the patch fails instead of
+// changing other policies if the schedule is reordered.
+func buildEnablePatch(schedule *dpv1alpha1.BackupSchedule, methods []string, enabled bool) (string, error) {
+	var ops []map[string]interface{}
+	for i, p := range schedule.Spec.Schedules {
+		if !slices.Contains(methods, p.BackupMethod) {
+			continue
+		}
+		path := fmt.Sprintf("/spec/schedules/%d", i)
+		ops = append(ops,
+			map[string]interface{}{"op": "test", "path": path + "/backupMethod", "value": p.BackupMethod},
+			map[string]interface{}{"op": "add", "path": path + "/enabled", "value": enabled})
+	}
+	if len(ops) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(ops)
+	return string(data), err
+}
+
+// buildBlackoutScript builds the script of the CronJob to suspend or resume the backup schedules, the
+// methods suspended by the maintenance mode are kept suspended when the blackout window ends.
+func buildBlackoutScript(schedules []dpv1alpha1.BackupSchedule, suspend bool) (string, error) {
+	var cmds []string
+	for i := range schedules {
+		s := &schedules[i]
+		methods := blackoutMethods(s)
+		if len(methods) == 0 {
+			continue
+		}
+		if suspend {
+			patch, err := buildEnablePatch(s, methods, false)
+			if err != nil {
+				return "", err
+			}
+			if patch == "" {
+				continue
+			}
+			cmds = append(cmds,
+				fmt.Sprintf("kubectl patch backupschedule %s -n %s --type=json -p '%s'", s.Name, s.Namespace, patch),
+				fmt.Sprintf("kubectl annotate backupschedule %s -n %s %s=true --overwrite", s.Name, s.Namespace, blackoutSuspendedAnnotationKey))
+			continue
+		}
+		resumeCmds, err := buildResumeCommands(s, methods, annotationJSONPath(maintenanceMethodsAnnotationKey), "(^|,)%s(,|$)")
+		if err != nil {
+			return "", err
+		}
+		if len(resumeCmds) == 0 {
+			continue
+		}
+		cmds = append(cmds, resumeCmds...)
+		cmds = append(cmds, fmt.Sprintf("kubectl annotate backupschedule %s -n %s %s-", s.Name, s.Namespace, blackoutSuspendedAnnotationKey))
+	}
+	return strings.Join(cmds, " && "), nil
+}
+
+// backupSchedulesRule returns the rule allowing to get and patch the backup schedules, it returns nil if
+// there is no backup schedule as the rule without resource names allows all of them.
+func backupSchedulesRule(schedules []dpv1alpha1.BackupSchedule) *rbacv1.PolicyRule {
+	if len(schedules) == 0 {
+		return nil
+	}
+	rule := &rbacv1.PolicyRule{
+		APIGroups: []string{types.DPAPIGroup},
+		Resources: []string{types.ResourceBackupSchedules},
+		Verbs:     []string{"get", "patch"},
+	}
+	for _, s := range schedules {
+		rule.ResourceNames = append(rule.ResourceNames, s.Name)
+	}
+	return rule
+}
+
+// buildBlackoutCronJob builds the CronJob to suspend the backup schedules at the start of the
+// blackout window, or resume them at the end.
+func (o *editBackupScheduleOptions) buildBlackoutCronJob(c *appsv1alpha1.Cluster, name, schedule, phase, script, image string) *batchv1.CronJob {
+	utc := "Etc/UTC"
+	var backoffLimit int32 = 3
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:  c.Name,
+				constant.AppManagedByLabelKey: "kbcli",
+				backupBlackoutLabelKey:        phase,
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          schedule,
+			TimeZone:          &utc,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: backupSchedulerName,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{
+								{
+									Name:    phase + "-blackout",
+									Image:   image,
+									Command: []string{"sh", "-c", script},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if c.UID != "" {
+		// the CronJobs are deleted with the cluster
+		cronJob.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: fmt.Sprintf("%s/%s", types.AppsAPIGroup, types.AppsAPIVersion),
+				Kind:       types.KindCluster,
+				Name:       c.Name,
+				UID:        c.UID,
+			},
+		}
+	}
+	return cronJob
+}
+
+// updateBackupSchedule updates the blackout annotations of the backup schedule, and suspends or
+// resumes the schedule policies of the methods.
+func (o *editBackupScheduleOptions) updateBackupSchedule(schedule *dpv1alpha1.BackupSchedule, methods []string, suspend bool) error {
+	if schedule.Annotations == nil {
+		schedule.Annotations = map[string]string{}
+	}
+	if len(o.blackouts) > 0 {
+		schedule.Annotations[blackoutWindowsAnnotationKey] = strings.Join(o.blackouts, ";")
+		schedule.Annotations[blackoutMethodsAnnotationKey] = strings.Join(methods, ",")
+	} else {
+		delete(schedule.Annotations, blackoutWindowsAnnotationKey)
+		delete(schedule.Annotations, blackoutMethodsAnnotationKey)
+	}
+	if suspend {
+		schedule.Annotations[blackoutSuspendedAnnotationKey] = "true"
+	} else {
+		delete(schedule.Annotations, blackoutSuspendedAnnotationKey)
+		// the methods suspended by the maintenance mode are resumed when it's disabled
+		methods = excludeMethods(methods, maintenanceMethods(schedule))
+	}
+	for i := range schedule.Spec.Schedules {
+		p := &schedule.Spec.Schedules[i]
+		if slices.Contains(methods, p.BackupMethod) {
+			enabled := !suspend
+			p.Enabled = &enabled
+		}
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(schedule)
+	if err != nil {
+		return err
+	}
+	_, err = o.dynamic.Resource(types.BackupScheduleGVR()).Namespace(schedule.Namespace).Update(context.TODO(),
+		&unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+	return err
+}
+
+// inBlackout checks whether the time is in any blackout window
+func (o *editBackupScheduleOptions) inBlackout(t time.Time) bool {
+	for _, w := range o.windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *editBackupScheduleOptions) run() error {
+	if err := action.CheckExportUnsupported("edit-backup-schedule"); err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	schedules, err := o.getBackupSchedules()
+	if err != nil {
+		return err
+	}
+
+	// the existing blackout windows are replaced
+	cronJobs, err := o.client.BatchV1().CronJobs(o.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", constant.AppInstanceLabelKey, c.Name, backupBlackoutLabelKey),
+	})
+	if err != nil {
+		return err
+	}
+	for _, cronJob := range cronJobs.Items {
+		if err = o.client.BatchV1().CronJobs(o.namespace).Delete(ctx, cronJob.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if len(o.windows) > 0 {
+		suspendScript, err := buildBlackoutScript(schedules, true)
+		if err != nil {
+			return err
+		}
+		if suspendScript == "" {
+			return fmt.Errorf("no enabled backup schedule found in cluster %s", c.Name)
+		}
+		resumeScript, err := buildBlackoutScript(schedules, false)
+		if err != nil {
+			return err
+		}
+		image, err := schedulerImage(o.client, o.image)
+		if err != nil {
+			return err
+		}
+		if err = createSchedulerRBAC(ctx, o.client, o.namespace, backupSchedulerName, []rbacv1.PolicyRule{*backupSchedulesRule(schedules)}, nil); err != nil {
+			return err
+		}
+		for i, w := range o.windows {
+			for _, cronJob := range []*batchv1.CronJob{
+				o.buildBlackoutCronJob(c, fmt.Sprintf("%s-blackout-%d-start", c.Name, i), w.startSchedule(), "start", suspendScript, image),
+				o.buildBlackoutCronJob(c, fmt.Sprintf("%s-blackout-%d-end", c.Name, i), w.endSchedule(), "end", resumeScript, image),
+			} {
+				if _, err = o.client.BatchV1().CronJobs(o.namespace).Create(ctx, cronJob, metav1.CreateOptions{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// the CronJobs take effect from the next window, the schedules are suspended now if it's in a window
+	suspend := o.inBlackout(time.Now())
+	for i := range schedules {
+		s := &schedules[i]
+		if err = o.updateBackupSchedule(s, blackoutMethods(s), suspend); err != nil {
+			return err
+		}
+	}
+	if len(o.windows) == 0 {
+		fmt.Fprintf(o.Out, "The blackout windows of cluster %s are removed\n", c.Name)
+		return nil
+	}
+	fmt.Fprintf(o.Out, "The scheduled backups of cluster %s are suspended in the blackout windows (UTC): %s\n", c.Name, strings.Join(o.blackouts, "; "))
+	if suspend {
+		fmt.Fprintln(o.Out, "The backup schedules are suspended now as it's in a blackout window")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("backup blackout windows", func() {
+	const scheduleName = "mycluster-backup-schedule"
+
+	var (
+		out *bytes.Buffer
+		o   *editBackupScheduleOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		o = &editBackupScheduleOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			dynamic: clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace),
+				clitesting.FakeBackupSchedule(scheduleName, "policy")),
+			client:    fake.NewSimpleClientset(clitesting.FakeKBDeploy("")),
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	getSchedule := func() *dpv1alpha1.BackupSchedule {
+		schedule := &dpv1alpha1.BackupSchedule{}
+		Expect(cluster.GetK8SClientObject(o.dynamic, schedule, types.BackupScheduleGVR(), clitesting.Namespace, scheduleName)).Should(Succeed())
+		return schedule
+	}
+
+	It("parse the blackout windows", func() {
+		for _, s := range []string{"22:00", "Sat", "Fri 22:00-22:00", "Someday 22:00-02:00", "Sat 25:00-02:00", "Sat Sun 22:00-02:00"} {
+			_, err := parseBlackoutWindow(s)
+			Expect(err).Should(HaveOccurred(), s)
+		}
+
+		w, err := parseBlackoutWindow("Sat 22:00-02:30")
+		Expect(err).Should(Succeed())
+		Expect(w.startSchedule()).Should(Equal("0 22 * * 6"))
+		Expect(w.endSchedule()).Should(Equal("30 2 * * 0"))
+		Expect(w.contains(time.Date(2024, 5, 4, 23, 0, 0, 0, time.UTC))).Should(BeTrue())
+		Expect(w.contains(time.Date(2024, 5, 5, 2, 0, 0, 0, time.UTC))).Should(BeTrue())
+		Expect(w.contains(time.Date(2024, 5, 5, 3, 0, 0, 0, time.UTC))).Should(BeFalse())
+		Expect(w.contains(time.Date(2024, 5, 3, 23, 0, 0, 0, time.UTC))).Should(BeFalse())
+
+		w, err = parseBlackoutWindow("01:00-03:00")
+		Expect(err).Should(Succeed())
+		Expect(w.startSchedule()).Should(Equal("0 1 * * *"))
+		Expect(w.endSchedule()).Should(Equal("0 3 * * *"))
+		Expect(w.contains(time.Date(2024, 5, 3, 1, 30, 0, 0, time.UTC))).Should(BeTrue())
+	})
+
+	It("validate", func() {
+		Expect(o.validate()).Should(MatchError(ContainSubstring("missing --blackout")))
+		o.blackouts = []string{"Sat 22:00-02:00"}
+		o.clearBlackouts = true
+		Expect(o.validate()).Should(MatchError(ContainSubstring("at the same time")))
+	})
+
+	It("set and clear the blackout windows", func() {
+		By("the blackout window is not now")
+		now := time.Now().UTC()
+		o.blackouts = []string{now.Add(time.Hour).Format("15:04") + "-" + now.Add(2*time.Hour).Format("15:04")}
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		cronJobs, err := o.client.BatchV1().CronJobs(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(HaveLen(2))
+		script := cronJobs.Items[0].Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command[2]
+		Expect(script).Should(ContainSubstring("kubectl patch backupschedule " + scheduleName))
+		Expect(script).Should(ContainSubstring(`"path":"/spec/schedules/0/backupMethod"`))
+		_, err = o.client.RbacV1().Roles(clitesting.Namespace).Get(context.TODO(), backupSchedulerName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		schedule := getSchedule()
+		Expect(schedule.Annotations).Should(HaveKeyWithValue(blackoutWindowsAnnotationKey, o.blackouts[0]))
+		Expect(schedule.Annotations).Should(HaveKeyWithValue(blackoutMethodsAnnotationKey, clitesting.BackupMethodName))
+		Expect(schedule.Annotations).ShouldNot(HaveKey(blackoutSuspendedAnnotationKey))
+		Expect(*schedule.Spec.Schedules[0].Enabled).Should(BeTrue())
+
+		By("replace the blackout window with the one including now")
+		o.blackouts = []string{now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")}
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("suspended now"))
+		cronJobs, err = o.client.BatchV1().CronJobs(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(HaveLen(2))
+		schedule = getSchedule()
+		Expect(schedule.Annotations).Should(HaveKeyWithValue(blackoutSuspendedAnnotationKey, "true"))
+		Expect(*schedule.Spec.Schedules[0].Enabled).Should(BeFalse())
+
+		By("clear the blackout windows")
+		o.blackouts = nil
+		o.clearBlackouts = true
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		cronJobs, err = o.client.BatchV1().CronJobs(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(BeEmpty())
+		schedule = getSchedule()
+		Expect(schedule.Annotations).ShouldNot(HaveKey(blackoutWindowsAnnotationKey))
+		Expect(schedule.Annotations).ShouldNot(HaveKey(blackoutSuspendedAnnotationKey))
+		Expect(*schedule.Spec.Schedules[0].Enabled).Should(BeTrue())
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// backupProtectedAnnotationKey marks the backup is protected from deletion, the protected backups
+// are refused to delete by names and skipped to delete by the selector unless --delete-protected is specified.
+const backupProtectedAnnotationKey = "dataprotection.kubeblocks.io/protected"
+
+var protectBackupExample = templates.Examples(`
+	# protect the backup mybackup from deletion
+	kbcli cluster protect-backup mybackup
+
+	# remove the protection of the backup mybackup
+	kbcli cluster protect-backup mybackup --unprotect`)
+
+type protectBackupOptions struct {
+	factory   cmdutil.Factory
+	dynamic   dynamic.Interface
+	namespace string
+
+	names     []string
+	unprotect bool
+
+	genericiooptions.IOStreams
+}
+
+func NewProtectBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &protectBackupOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "protect-backup NAME...",
+		Short:             "Protect the backups from deletion.",
+		Example:           protectBackupExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.names = args
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.unprotect, "unprotect", false, "Remove the protection of the backups")
+	return cmd
+}
+
+func (o *protectBackupOptions) complete() error {
+	if len(o.names) == 0 {
+		return clierrors.NewValidation("missing backup name")
+	}
+	var err error
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.dynamic, err = o.factory.DynamicClient()
+	return err
+}
+
+func (o *protectBackupOptions) run() error {
+	if err := action.CheckExportUnsupported("protect-backup"); err != nil {
+		return err
+	}
+	var value interface{} = "true"
+	if o.unprotect {
+		// remove the annotation by the merge patch
+		value = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{backupProtectedAnnotationKey: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range o.names {
+		if _, err = o.dynamic.Resource(types.BackupGVR()).Namespace(o.namespace).Patch(context.TODO(), name,
+			k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		if o.unprotect {
+			fmt.Fprintf(o.Out, "Backup %s is unprotected\n", name)
+		} else {
+			fmt.Fprintf(o.Out, "Backup %s is protected\n", name)
+		}
+	}
+	return nil
+}
+
+// IsBackupProtected checks if the backup is protected from deletion
+func IsBackupProtected(obj metav1.Object) bool {
+	return obj.GetAnnotations()[backupProtectedAnnotationKey] == "true"
+}
+
+// CheckProtectedBackups returns an error if any of the backups is protected, the backups not
+// found are ignored.
+func CheckProtectedBackups(dynamic dynamic.Interface, namespace string, names []string) error {
+	var protected []string
+	for _, name := range names {
+		obj, err := dynamic.Resource(types.BackupGVR()).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if IsBackupProtected(obj) {
+			protected = append(protected, name)
+		}
+	}
+	if len(protected) > 0 {
+		return clierrors.NewValidation("backup %s is protected, remove the protection by `kbcli cluster protect-backup %s --unprotect` or delete it with --delete-protected",
+			strings.Join(protected, ","), strings.Join(protected, " "))
+	}
+	return nil
+}
+
+// AddDeleteProtectedFlag adds the flag to delete the protected backups to the delete-backup commands
+func AddDeleteProtectedFlag(cmd *cobra.Command, deleteProtected *bool) {
+	cmd.Flags().BoolVar(deleteProtected, "delete-protected", false, "Delete the protected backups, they are refused to delete by names and skipped to delete by the cluster or selector by default")
+}
+
+// CheckProtectedBackupsToDelete refuses to delete the protected backups by names, and skips the protected
+// backups to delete by the label selector, unless deleteProtected is true.
+func CheckProtectedBackupsToDelete(o *action.DeleteOptions, deleteProtected bool) error {
+	if deleteProtected || (len(o.Names) == 0 && len(o.LabelSelector) == 0) {
+		return nil
+	}
+	namespace, _, err := o.Factory.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	dynamic, err := o.Factory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	if len(o.Names) > 0 {
+		return CheckProtectedBackups(dynamic, namespace, o.Names)
+	}
+	return skipProtectedBackups(o, dynamic, namespace)
+}
+
+// skipProtectedBackups resolves the backups selected by the label selector to the names without the protected
+// ones, so the protected backups are not deleted by the bulk deletion.
+func skipProtectedBackups(o *action.DeleteOptions, dynamic dynamic.Interface, namespace string) error {
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	objs, err := dynamic.Resource(types.BackupGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: o.LabelSelector})
+	if err != nil {
+		return err
+	}
+	var names, protected []string
+	for i := range objs.Items {
+		if IsBackupProtected(&objs.Items[i]) {
+			protected = append(protected, objs.Items[i].GetName())
+			continue
+		}
+		names = append(names, objs.Items[i].GetName())
+	}
+	if len(protected) == 0 {
+		return nil
+	}
+	if o.AllNamespaces {
+		return clierrors.NewValidation("backup %s is protected and can not be skipped across all namespaces, delete the backups in the namespace or delete them with --delete-protected",
+			strings.Join(protected, ","))
+	}
+	printer.Warning(o.ErrOut, "backup %s is protected and skipped, delete it with --delete-protected\n", strings.Join(protected, ","))
+	if len(names) == 0 {
+		return clierrors.NewValidation("all the backups to delete are protected")
+	}
+	o.LabelSelector = ""
+	o.ConfirmedNames = nil
+	o.Names = names
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var _ = Describe("backup protection", func() {
+	var (
+		out     *bytes.Buffer
+		streams genericiooptions.IOStreams
+		tf      *cmdtesting.TestFactory
+		o       *protectBackupOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		streams = genericiooptions.IOStreams{Out: out, ErrOut: out}
+		tf = clitesting.NewTestFactory(clitesting.Namespace)
+		golden, daily := clitesting.FakeBackupWithPhase("golden", dpv1alpha1.BackupPhaseCompleted), clitesting.FakeBackupWithPhase("daily", dpv1alpha1.BackupPhaseCompleted)
+		tf.FakeDynamicClient = clitesting.FakeDynamicClient(golden, daily)
+		o = &protectBackupOptions{factory: tf, IOStreams: streams, names: []string{"golden"}}
+		Expect(o.complete()).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	isProtected := func(name string) bool {
+		obj, err := o.dynamic.Resource(types.BackupGVR()).Namespace(clitesting.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		return IsBackupProtected(obj)
+	}
+
+	It("protect and unprotect the backup", func() {
+		Expect(o.run()).Should(Succeed())
+		Expect(isProtected("golden")).Should(BeTrue())
+		Expect(isProtected("daily")).Should(BeFalse())
+		Expect(out.String()).Should(ContainSubstring("Backup golden is protected"))
+
+		o.unprotect = true
+		Expect(o.run()).Should(Succeed())
+		Expect(isProtected("golden")).Should(BeFalse())
+	})
+
+	It("refuse to export the manifests", func() {
+		action.SetExportDir(GinkgoT().TempDir())
+		defer action.SetExportDir("")
+		Expect(o.run()).Should(MatchError(ContainSubstring("--export-to-dir")))
+		Expect(isProtected("golden")).Should(BeFalse())
+	})
+
+	It("refuse to delete the protected backup unless --delete-protected", func() {
+		Expect(o.run()).Should(Succeed())
+		deleteOptions := action.NewDeleteOptions(tf, streams, types.BackupGVR())
+		deleteOptions.Names = []string{"daily", "golden", "not-found"}
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, false)).Should(MatchError(ContainSubstring("backup golden is protected")))
+
+		By("--force does not delete the protected backup")
+		deleteOptions.Force = true
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, false)).Should(HaveOccurred())
+
+		deleteOptions.Names = []string{"daily"}
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, false)).Should(Succeed())
+
+		deleteOptions.Names = []string{"golden"}
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, true)).Should(Succeed())
+	})
+
+	It("skip the protected backups to delete by the selector", func() {
+		Expect(o.run()).Should(Succeed())
+		deleteOptions := action.NewDeleteOptions(tf, streams, types.BackupGVR())
+		deleteOptions.Force = true
+		deleteOptions.LabelSelector = util.BuildLabelSelectorByNames("", []string{clitesting.ClusterName})
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, false)).Should(Succeed())
+		Expect(deleteOptions.LabelSelector).Should(BeEmpty())
+		Expect(deleteOptions.Names).Should(Equal([]string{"daily"}))
+		Expect(out.String()).Should(ContainSubstring("backup golden is protected and skipped"))
+
+		By("all the backups are protected")
+		o.names = []string{"daily"}
+		Expect(o.run()).Should(Succeed())
+		deleteOptions.Names = nil
+		deleteOptions.LabelSelector = util.BuildLabelSelectorByNames("", []string{clitesting.ClusterName})
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, false)).Should(MatchError(ContainSubstring("all the backups to delete are protected")))
+
+		By("delete the protected backups with --delete-protected")
+		Expect(CheckProtectedBackupsToDelete(deleteOptions, true)).Should(Succeed())
+		Expect(deleteOptions.LabelSelector).ShouldNot(BeEmpty())
+	})
+
+	It("list the backups with the protected column", func() {
+		Expect(o.run()).Should(Succeed())
+		listOptions := ListBackupOptions{ListOptions: action.NewListOptions(tf, streams, types.BackupGVR())}
+		Expect(listOptions.Complete()).Should(Succeed())
+		out.Reset()
+		Expect(PrintBackupList(listOptions)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("PROTECTED"))
+		Expect(out.String()).Should(MatchRegexp(`golden .*true`))
+	})
+})
@@ -0,0 +1,536 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var capacityForecastExample = templates.Examples(`
+	# Forecast when the volumes of cluster mycluster will be full and how its backup size will trend
+	kbcli cluster capacity-forecast mycluster
+
+	# Forecast with the volume usage of the last 30 days, and warn about the volumes full within 14 days
+	kbcli cluster capacity-forecast mycluster --range 720h --horizon 336h
+
+	# Record a snapshot of the volume usage when prometheus is not available, run it periodically
+	# such as by cron to build the usage history used by the forecast
+	kbcli cluster capacity-forecast mycluster --record`)
+
+const (
+	// forecastPoints is the number of volume usage samples queried from prometheus
+	forecastPoints = 60
+	// forecastFreeRatio is the ratio of the volume kept free after the suggested expansion
+	forecastFreeRatio = 0.2
+	// backupGrowthWarnRatio is the growth ratio of the backup size within the horizon to warn about
+	backupGrowthWarnRatio = 0.5
+	// maxCapacitySnapshots is the max number of the volume usage snapshots kept for a cluster
+	maxCapacitySnapshots = 1000
+)
+
+// forecastSample is a sample of the volume usage or the backup size
+type forecastSample struct {
+	time  time.Time
+	value float64
+}
+
+// capacitySnapshot is the volume usage of a cluster recorded by the CLI, the usage is keyed by
+// the PVC name.
+type capacitySnapshot struct {
+	Time    time.Time        `json:"time"`
+	Volumes map[string]int64 `json:"volumes"`
+}
+
+// kubeletStatsSummary is the part of the kubelet stats summary API response with the volume usage
+type kubeletStatsSummary struct {
+	Pods []struct {
+		Volumes []struct {
+			UsedBytes *int64 `json:"usedBytes"`
+			PVCRef    *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+type CapacityForecastOptions struct {
+	clusterName   string
+	componentName string
+	namespace     string
+	timeRange     time.Duration
+	horizon       time.Duration
+	prometheusURL string
+	record        bool
+
+	client     kubernetes.Interface
+	dynamic    dynamic.Interface
+	queryRange promQueryRangeFunc
+	// statsSummary returns the kubelet stats summary of the node
+	statsSummary func(node string) ([]byte, error)
+	now          func() time.Time
+	genericiooptions.IOStreams
+}
+
+func NewCapacityForecastCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &CapacityForecastOptions{IOStreams: streams, now: time.Now}
+	cmd := &cobra.Command{
+		Use:               "capacity-forecast NAME",
+		Short:             "Forecast when the volumes of the cluster will be full and how the backup size will trend.",
+		Example:           capacityForecastExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentFlag(f, cmd, &o.componentName, "Only forecast the volumes and backups of the specified component")
+	cmd.Flags().DurationVar(&o.timeRange, "range", 7*24*time.Hour, "The time range of the usage history used to forecast, such as 72h and 720h")
+	cmd.Flags().DurationVar(&o.horizon, "horizon", 30*24*time.Hour, "Warn about the volumes full and the backups growing within the horizon")
+	cmd.Flags().StringVar(&o.prometheusURL, "prometheus-url", "", "The URL of the prometheus server, if not specified, the prometheus addon server is queried, and the snapshots recorded by --record are used if it's not found")
+	cmd.Flags().BoolVar(&o.record, "record", false, "Record a snapshot of the current volume usage collected from kubelet before forecasting")
+	return cmd
+}
+
+func (o *CapacityForecastOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return clierrors.NewValidation("only support to forecast the capacity of one cluster")
+	}
+	if o.timeRange <= 0 || o.horizon <= 0 {
+		return clierrors.NewValidation("--range and --horizon must be greater than 0")
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.client, err = f.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	o.statsSummary = func(node string) ([]byte, error) {
+		return o.client.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes", node, "proxy", "stats", "summary").DoRaw(context.TODO())
+	}
+	if o.prometheusURL != "" {
+		o.queryRange = newHTTPPromQuerier(o.prometheusURL)
+		return nil
+	}
+	if o.queryRange, err = newProxyPromQuerier(o.client); err != nil {
+		// fall back to the snapshots recorded by the CLI
+		klog.V(1).Infof("failed to find the prometheus server: %v", err)
+		o.queryRange = nil
+	}
+	return nil
+}
+
+func (o *CapacityForecastOptions) run() error {
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	if o.componentName != "" && c.Spec.GetComponentByName(o.componentName) == nil {
+		return clierrors.NewNotFoundf("component %s is not found in cluster %s", o.componentName, o.clusterName)
+	}
+	pvcs, err := o.getPVCs()
+	if err != nil {
+		return err
+	}
+	if o.record {
+		if err = o.recordSnapshot(pvcs); err != nil {
+			return err
+		}
+	}
+	history, source, err := o.getUsageHistory(pvcs)
+	if err != nil {
+		return err
+	}
+
+	now := o.now()
+	var warnings []string
+	fmt.Fprintf(o.Out, "Volumes (forecast with the usage of the last %s from %s):\n", duration.HumanDuration(o.timeRange), source)
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("COMPONENT", "PVC", "USED", "CAPACITY", "GROWTH/DAY", "FULL-IN")
+	for _, pvc := range pvcs {
+		compName := pvc.Labels[constant.KBAppComponentLabelKey]
+		capacity := pvcCapacity(&pvc)
+		samples := history[pvc.Name]
+		if len(samples) == 0 {
+			tbl.AddRow(compName, pvc.Name, printer.NoneString, humanize.IBytes(uint64(capacity)), printer.NoneString, printer.NoneString)
+			continue
+		}
+		used := samples[len(samples)-1].value
+		slope, _, ok := linearFit(samples, now)
+		growth, fullIn := printer.NoneString, printer.NoneString
+		if ok {
+			growth = formatBytesGrowth(slope)
+			fullIn = "Not growing"
+		}
+		switch {
+		case used >= float64(capacity):
+			fullIn = "Full"
+			warnings = append(warnings, o.volumeExpansionWarning(&pvc, capacity, used, math.Max(slope, 0), 0))
+		case ok && slope > 0:
+			left := time.Duration((float64(capacity) - used) / slope * float64(time.Second))
+			fullIn = duration.HumanDuration(left)
+			if left <= o.horizon {
+				warnings = append(warnings, o.volumeExpansionWarning(&pvc, capacity, used, slope, left))
+			}
+		}
+		tbl.AddRow(compName, pvc.Name, humanize.IBytes(uint64(used)), humanize.IBytes(uint64(capacity)), growth, fullIn)
+	}
+	tbl.Print()
+
+	backupWarnings, err := o.printBackupTrend(now)
+	if err != nil {
+		return err
+	}
+	warnings = append(warnings, backupWarnings...)
+	if len(warnings) > 0 {
+		fmt.Fprintln(o.Out)
+	}
+	for _, w := range warnings {
+		printer.Warning(o.Out, "%s\n", w)
+	}
+	return nil
+}
+
+// getPVCs returns the PVCs of the cluster sorted by the name
+func (o *CapacityForecastOptions) getPVCs() ([]corev1.PersistentVolumeClaim, error) {
+	selector := util.BuildLabelSelectorByNames("", []string{o.clusterName})
+	if o.componentName != "" {
+		selector = fmt.Sprintf("%s,%s=%s", selector, constant.KBAppComponentLabelKey, o.componentName)
+	}
+	pvcs, err := o.client.CoreV1().PersistentVolumeClaims(o.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pvcs.Items, func(i, j int) bool {
+		return pvcs.Items[i].Name < pvcs.Items[j].Name
+	})
+	return pvcs.Items, nil
+}
+
+// getUsageHistory returns the usage samples of the PVCs in the time range from prometheus, or the
+// snapshots recorded by the CLI if prometheus is not available.
+func (o *CapacityForecastOptions) getUsageHistory(pvcs []corev1.PersistentVolumeClaim) (map[string][]forecastSample, string, error) {
+	end := o.now()
+	start := end.Add(-o.timeRange)
+	history := map[string][]forecastSample{}
+	if o.queryRange == nil {
+		snapshots, err := loadCapacitySnapshots(o.namespace, o.clusterName)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, s := range snapshots {
+			if s.Time.Before(start) || s.Time.After(end) {
+				continue
+			}
+			for name, used := range s.Volumes {
+				history[name] = append(history[name], forecastSample{time: s.Time, value: float64(used)})
+			}
+		}
+		if len(history) == 0 {
+			printer.Warning(o.ErrOut, "prometheus is not found and no usage snapshot is recorded in the last %s, "+
+				"please enable prometheus by \"kbcli addon enable prometheus\" or run \"kbcli cluster capacity-forecast %s --record\" periodically\n",
+				duration.HumanDuration(o.timeRange), o.clusterName)
+		}
+		return history, "recorded snapshots", nil
+	}
+
+	step := o.timeRange / forecastPoints
+	if step < time.Second {
+		step = time.Second
+	}
+	for _, pvc := range pvcs {
+		// the missing samples are filled with NaN, so the values are aligned with the steps
+		query := fmt.Sprintf(`max(kubelet_volume_stats_used_bytes{namespace="%s",persistentvolumeclaim="%s"}) or vector(NaN)`,
+			o.namespace, pvc.Name)
+		values, err := o.queryRange(query, start, end, step)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to query the usage of volume %s: %v", pvc.Name, err)
+		}
+		for i, v := range values {
+			if math.IsNaN(v) {
+				continue
+			}
+			history[pvc.Name] = append(history[pvc.Name], forecastSample{time: start.Add(time.Duration(i) * step), value: v})
+		}
+	}
+	return history, "prometheus", nil
+}
+
+// recordSnapshot records the current usage of the PVCs collected from the kubelet stats summary of
+// the nodes where the pods of the cluster run.
+func (o *CapacityForecastOptions) recordSnapshot(pvcs []corev1.PersistentVolumeClaim) error {
+	pods, err := o.client.CoreV1().Pods(o.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: util.BuildLabelSelectorByNames("", []string{o.clusterName}),
+	})
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, pvc := range pvcs {
+		names[pvc.Name] = true
+	}
+	nodes := map[string]bool{}
+	snapshot := capacitySnapshot{Time: o.now(), Volumes: map[string]int64{}}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || nodes[pod.Spec.NodeName] {
+			continue
+		}
+		nodes[pod.Spec.NodeName] = true
+		data, err := o.statsSummary(pod.Spec.NodeName)
+		if err != nil {
+			return fmt.Errorf("failed to get the stats summary of node %s: %v", pod.Spec.NodeName, err)
+		}
+		summary := &kubeletStatsSummary{}
+		if err = json.Unmarshal(data, summary); err != nil {
+			return fmt.Errorf("failed to parse the stats summary of node %s: %v", pod.Spec.NodeName, err)
+		}
+		for _, p := range summary.Pods {
+			for _, v := range p.Volumes {
+				if v.PVCRef == nil || v.UsedBytes == nil || v.PVCRef.Namespace != o.namespace || !names[v.PVCRef.Name] {
+					continue
+				}
+				snapshot.Volumes[v.PVCRef.Name] = *v.UsedBytes
+			}
+		}
+	}
+	if len(snapshot.Volumes) == 0 {
+		return fmt.Errorf("no volume usage of cluster %s is found in the kubelet stats", o.clusterName)
+	}
+	snapshots, err := loadCapacitySnapshots(o.namespace, o.clusterName)
+	if err != nil {
+		return err
+	}
+	snapshots = append(snapshots, snapshot)
+	if len(snapshots) > maxCapacitySnapshots {
+		snapshots = snapshots[len(snapshots)-maxCapacitySnapshots:]
+	}
+	if err = saveCapacitySnapshots(o.namespace, o.clusterName, snapshots); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Recorded the usage of %d volumes of cluster %s\n\n", len(snapshot.Volumes), o.clusterName)
+	return nil
+}
+
+// printBackupTrend prints the size trend of the completed backups of the cluster grouped by the
+// backup method, and returns the warnings of the backups growing fast within the horizon.
+func (o *CapacityForecastOptions) printBackupTrend(now time.Time) ([]string, error) {
+	selector := util.BuildLabelSelectorByNames("", []string{o.clusterName})
+	if o.componentName != "" {
+		selector = fmt.Sprintf("%s,%s=%s", selector, constant.KBAppComponentLabelKey, o.componentName)
+	}
+	objs, err := o.dynamic.Resource(types.BackupGVR()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	backups, err := util.ConvertUnstructuredList[*dpv1alpha1.Backup](objs.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	samples := map[string][]forecastSample{}
+	var methods []string
+	for _, b := range backups {
+		if b.Status.Phase != dpv1alpha1.BackupPhaseCompleted || b.Status.CompletionTimestamp == nil {
+			continue
+		}
+		size, err := humanize.ParseBytes(b.Status.TotalSize)
+		if err != nil {
+			continue
+		}
+		if _, ok := samples[b.Spec.BackupMethod]; !ok {
+			methods = append(methods, b.Spec.BackupMethod)
+		}
+		samples[b.Spec.BackupMethod] = append(samples[b.Spec.BackupMethod], forecastSample{time: b.Status.CompletionTimestamp.Time, value: float64(size)})
+	}
+	fmt.Fprintln(o.Out, "\nBackups:")
+	if len(methods) == 0 {
+		fmt.Fprintf(o.Out, "No completed backup of cluster %s is found\n", o.clusterName)
+		return nil, nil
+	}
+	sort.Strings(methods)
+
+	var warnings []string
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("METHOD", "BACKUPS", "TOTAL-SIZE", "LATEST-SIZE", "GROWTH/DAY", "FORECAST-SIZE")
+	for _, method := range methods {
+		s := samples[method]
+		sort.Slice(s, func(i, j int) bool { return s[i].time.Before(s[j].time) })
+		var total float64
+		for _, v := range s {
+			total += v.value
+		}
+		latest := s[len(s)-1].value
+		growth, forecast := printer.NoneString, printer.NoneString
+		if slope, value, ok := linearFit(s, now.Add(o.horizon)); ok {
+			value = math.Max(value, 0)
+			growth = formatBytesGrowth(slope)
+			forecast = fmt.Sprintf("%s in %s", humanize.IBytes(uint64(value)), duration.HumanDuration(o.horizon))
+			if latest > 0 && value >= latest*(1+backupGrowthWarnRatio) {
+				warnings = append(warnings, fmt.Sprintf("the %s backups are forecast to grow from %s to %s in %s, "+
+					"consider shortening the retention period to keep the backup storage stable: kbcli cluster update %s --backup-retention-period=<period> --namespace=%s",
+					method, humanize.IBytes(uint64(latest)), humanize.IBytes(uint64(value)), duration.HumanDuration(o.horizon), o.clusterName, o.namespace))
+			}
+		}
+		tbl.AddRow(method, len(s), humanize.IBytes(uint64(total)), humanize.IBytes(uint64(latest)), growth, forecast)
+	}
+	tbl.Print()
+	return warnings, nil
+}
+
+// volumeExpansionWarning returns the warning of the volume to be full with the suggested storage,
+// which keeps the volume free by forecastFreeRatio at the end of the horizon.
+func (o *CapacityForecastOptions) volumeExpansionWarning(pvc *corev1.PersistentVolumeClaim, capacity int64, used, slope float64, left time.Duration) string {
+	projected := used + slope*o.horizon.Seconds()
+	storage := int64(math.Ceil(projected / (1 - forecastFreeRatio) / (1 << 30)))
+	if minimum := capacity>>30 + 1; storage < minimum {
+		storage = minimum
+	}
+	when := "is full"
+	if left > 0 {
+		when = "is forecast to be full in " + duration.HumanDuration(left)
+	}
+	vctName := pvc.Labels[constant.VolumeClaimTemplateNameLabelKey]
+	if vctName == "" {
+		vctName = "<volume-claim-template>"
+	}
+	return fmt.Sprintf("volume %s %s, consider expanding it: kbcli cluster volume-expand %s --components=%s --volume-claim-template-names=%s --storage=%dGi --namespace=%s",
+		pvc.Name, when, o.clusterName, pvc.Labels[constant.KBAppComponentLabelKey], vctName, storage, o.namespace)
+}
+
+// linearFit fits the samples to a line by the least squares, it returns the slope in units per
+// second and the value of the line at the time, ok is false if the samples are not enough.
+func linearFit(samples []forecastSample, at time.Time) (slope, value float64, ok bool) {
+	if len(samples) < 2 {
+		return 0, 0, false
+	}
+	base := samples[0].time
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.time.Sub(base).Seconds()
+		sumX += x
+		sumY += s.value
+		sumXY += x * s.value
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0, false
+	}
+	slope = (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	return slope, intercept + slope*at.Sub(base).Seconds(), true
+}
+
+// formatBytesGrowth formats the growth in bytes per second as the growth per day
+func formatBytesGrowth(slope float64) string {
+	perDay := slope * (24 * time.Hour).Seconds()
+	if perDay < 0 {
+		return "-" + humanize.IBytes(uint64(-perDay))
+	}
+	return "+" + humanize.IBytes(uint64(perDay))
+}
+
+// pvcCapacity returns the capacity of the PVC, or the requested storage if it's not bound
+func pvcCapacity(pvc *corev1.PersistentVolumeClaim) int64 {
+	if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return q.Value()
+	}
+	q := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	return q.Value()
+}
+
+func capacitySnapshotsPath(namespace, name string) (string, error) {
+	home, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, types.CliCapacityHistoryDir, fmt.Sprintf("%s_%s.json", namespace, name)), nil
+}
+
+func loadCapacitySnapshots(namespace, name string) ([]capacitySnapshot, error) {
+	path, err := capacitySnapshotsPath(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snapshots []capacitySnapshot
+	if err = json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse the usage snapshots %s: %v", path, err)
+	}
+	return snapshots, nil
+}
+
+func saveCapacitySnapshots(namespace, name string, snapshots []capacitySnapshot) error {
+	path, err := capacitySnapshotsPath(namespace, name)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster capacity-forecast", func() {
+	const gi = float64(1 << 30)
+	var (
+		now time.Time
+		out *bytes.Buffer
+	)
+
+	newOptions := func(objs ...runtime.Object) *CapacityForecastOptions {
+		pvc := clitesting.FakePVCs().Items[0]
+		pvc.Namespace = clitesting.Namespace
+		pvc.Labels[constant.VolumeClaimTemplateNameLabelKey] = "data"
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-0", Namespace: clitesting.Namespace,
+				Labels: map[string]string{constant.AppInstanceLabelKey: clitesting.ClusterName}},
+			Spec: corev1.PodSpec{NodeName: "node-0"},
+		}
+		return &CapacityForecastOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			timeRange:   24 * time.Hour,
+			horizon:     30 * 24 * time.Hour,
+			client:      clitesting.FakeClientSet(&pvc, pod),
+			dynamic:     clitesting.FakeDynamicClient(append(objs, clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace))...),
+			now:         func() time.Time { return now },
+			IOStreams:   genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Unix(1700000000, 0)
+		out = &bytes.Buffer{}
+	})
+
+	It("fit the samples", func() {
+		_, _, ok := linearFit([]forecastSample{{time: now, value: 1}}, now)
+		Expect(ok).Should(BeFalse())
+		_, _, ok = linearFit([]forecastSample{{time: now, value: 1}, {time: now, value: 2}}, now)
+		Expect(ok).Should(BeFalse())
+
+		samples := []forecastSample{
+			{time: now, value: 10},
+			{time: now.Add(time.Hour), value: 20},
+			{time: now.Add(2 * time.Hour), value: 30},
+		}
+		slope, value, ok := linearFit(samples, now.Add(3*time.Hour))
+		Expect(ok).Should(BeTrue())
+		Expect(slope * 3600).Should(BeNumerically("~", 10, 1e-9))
+		Expect(value).Should(BeNumerically("~", 40, 1e-9))
+		Expect(formatBytesGrowth(1)).Should(Equal("+84 KiB"))
+		Expect(formatBytesGrowth(-1)).Should(Equal("-84 KiB"))
+	})
+
+	It("forecast with prometheus", func() {
+		// the volume grows 1Gi per 6 hours from 5Gi and the backup grows 1Gi per day
+		var backups []runtime.Object
+		for i := 0; i < 3; i++ {
+			backup := clitesting.FakeBackupWithPhase(fmt.Sprintf("backup-%d", i), dpv1alpha1.BackupPhaseCompleted)
+			completion := metav1.NewTime(now.Add(time.Duration(i-2) * 24 * time.Hour))
+			backup.Status.CompletionTimestamp = &completion
+			backup.Status.TotalSize = fmt.Sprintf("%d", (i+1)<<30)
+			backups = append(backups, backup)
+		}
+		o := newOptions(backups...)
+		var queries []string
+		o.queryRange = func(query string, start, end time.Time, step time.Duration) ([]float64, error) {
+			Expect(start).Should(Equal(now.Add(-24 * time.Hour)))
+			Expect(step).Should(Equal(24 * time.Minute))
+			queries = append(queries, query)
+			values := make([]float64, forecastPoints+1)
+			for i := range values {
+				values[i] = 5*gi + float64(i)*gi/15
+			}
+			values[1] = math.NaN()
+			return values, nil
+		}
+		Expect(o.run()).Should(Succeed())
+		Expect(queries).Should(Equal([]string{fmt.Sprintf(`max(kubelet_volume_stats_used_bytes{namespace="%s",persistentvolumeclaim="%s"}) or vector(NaN)`,
+			clitesting.Namespace, clitesting.PVCName)}))
+		Expect(out.String()).Should(ContainSubstring("from prometheus"))
+		Expect(out.String()).Should(ContainSubstring("+4.0 GiB"))
+		Expect(out.String()).Should(ContainSubstring("volume fake-pvc is forecast to be full in 6h"))
+		// 9Gi used + 4Gi per day for 30 days, with 20% free
+		Expect(out.String()).Should(ContainSubstring("--volume-claim-template-names=data --storage=162Gi"))
+		Expect(out.String()).Should(ContainSubstring(clitesting.BackupMethodName))
+		Expect(out.String()).Should(ContainSubstring("6.0 GiB"))
+		Expect(out.String()).Should(ContainSubstring("the fake-backup-method backups are forecast to grow from 3.0 GiB to 33 GiB"))
+
+		By("the component is not found")
+		o.componentName = "not-exist"
+		Expect(o.run()).Should(MatchError(ContainSubstring("component not-exist is not found")))
+	})
+
+	It("record snapshots and forecast with them", func() {
+		home, err := os.MkdirTemp("", "kbcli-capacity")
+		Expect(err).Should(Succeed())
+		defer os.RemoveAll(home)
+		Expect(os.Setenv(types.CliHomeEnv, home)).Should(Succeed())
+		defer os.Unsetenv(types.CliHomeEnv)
+
+		o := newOptions()
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("no usage snapshot is recorded"))
+		Expect(out.String()).Should(ContainSubstring("No completed backup"))
+
+		o.record = true
+		for i := 0; i < 3; i++ {
+			used := int64(i+1) << 30
+			o.statsSummary = func(node string) ([]byte, error) {
+				Expect(node).Should(Equal("node-0"))
+				return []byte(fmt.Sprintf(`{"pods":[{"volume":[{"name":"data","usedBytes":%d,"pvcRef":{"name":"%s","namespace":"%s"}},{"name":"tmp","usedBytes":1}]}]}`,
+					used, clitesting.PVCName, clitesting.Namespace)), nil
+			}
+			Expect(o.run()).Should(Succeed())
+			now = now.Add(time.Hour)
+		}
+		snapshots, err := loadCapacitySnapshots(clitesting.Namespace, clitesting.ClusterName)
+		Expect(err).Should(Succeed())
+		Expect(snapshots).Should(HaveLen(3))
+
+		out.Reset()
+		o.record = false
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("from recorded snapshots"))
+		Expect(out.String()).Should(ContainSubstring("+24 GiB"))
+		Expect(out.String()).Should(ContainSubstring("is forecast to be full in 7h"))
+
+		By("no volume usage is found")
+		o.record = true
+		o.statsSummary = func(node string) ([]byte, error) { return []byte(`{"pods":[]}`), nil }
+		Expect(o.run()).Should(MatchError(ContainSubstring("no volume usage of cluster")))
+	})
+})
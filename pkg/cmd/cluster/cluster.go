@@ -57,6 +57,7 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewLabelCmd(f, streams),
 				NewDeleteCmd(f, streams),
 				newRegisterCmd(f, streams),
+				NewExplainCmd(f, streams),
 			},
 		},
 		{
@@ -67,15 +68,21 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewStartCmd(f, streams),
 				NewRestartCmd(f, streams),
 				NewUpgradeCmd(f, streams),
+				NewUpgradeAdvisorCmd(f, streams),
 				NewVolumeExpansionCmd(f, streams),
 				NewVerticalScalingCmd(f, streams),
 				NewHorizontalScalingCmd(f, streams),
 				NewPromoteCmd(f, streams),
+				NewSwitchoverCmd(f, streams),
 				NewDescribeOpsCmd(f, streams),
 				NewListOpsCmd(f, streams),
 				NewDeleteOpsCmd(f, streams),
 				NewExposeCmd(f, streams),
 				NewCancelCmd(f, streams),
+				NewTLSCmd(f, streams),
+				NewHardenCmd(f, streams),
+				NewMaintenanceCmd(f, streams),
+				NewExportCmd(f, streams),
 			},
 		},
 		{
@@ -86,6 +93,7 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewDescribeReconfigureCmd(f, streams),
 				NewExplainReconfigureCmd(f, streams),
 				NewDiffConfigureCmd(f, streams),
+				NewRollbackConfigureCmd(f, streams),
 			},
 		},
 		{
@@ -94,11 +102,16 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewListBackupPolicyCmd(f, streams),
 				NewEditBackupPolicyCmd(f, streams),
 				NewDescribeBackupPolicyCmd(f, streams),
+				NewEditBackupScheduleCmd(f, streams),
 				NewCreateBackupCmd(f, streams),
 				NewListBackupCmd(f, streams),
 				NewDeleteBackupCmd(f, streams),
+				NewProtectBackupCmd(f, streams),
 				NewCreateRestoreCmd(f, streams),
 				NewDescribeBackupCmd(f, streams),
+				NewSnapshotCmd(f, streams),
+				NewListSnapshotsCmd(f, streams),
+				NewDeleteSnapshotCmd(f, streams),
 			},
 		},
 		{
@@ -106,6 +119,9 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 			Commands: []*cobra.Command{
 				NewLogsCmd(f, streams),
 				NewListLogsCmd(f, streams),
+				NewMetricsCmd(f, streams),
+				NewTopCmd(f, streams),
+				NewCapacityForecastCmd(f, streams),
 			},
 		},
 
@@ -116,6 +132,8 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewDeleteAccountCmd(f, streams),
 				NewDescAccountCmd(f, streams),
 				NewListAccountsCmd(f, streams),
+				NewListAccountSecretsCmd(f, streams),
+				NewGetCredentialsCmd(f, streams),
 				NewGrantOptions(f, streams),
 				NewRevokeOptions(f, streams),
 			},
@@ -124,6 +142,7 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 
 	// add subcommands
 	groups.Add(cmd)
+	markClusterNameArgCmds(cmd)
 	templates.ActsAsRootCommand(cmd, nil, groups...)
 
 	return cmd
@@ -20,15 +20,22 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"os"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
@@ -177,6 +184,13 @@ var _ = Describe("Cluster", func() {
 			Expect(o.Validate()).Should(HaveOccurred())
 		})
 
+		It("validate rbac-enabled should not be exported", func() {
+			o.RBACEnabled = true
+			action.SetExportDir(os.TempDir())
+			defer action.SetExportDir("")
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("--export-to-dir")))
+		})
+
 		It("should succeed if component with valid class", func() {
 			o.Values = []string{fmt.Sprintf("type=%s,class=%s", testing.ComponentDefName, testapps.Class1c1gName)}
 			Expect(o.Complete()).Should(Succeed())
@@ -283,6 +297,58 @@ var _ = Describe("Cluster", func() {
 			Expect(o.Complete()).Should(Succeed())
 			Expect(o.Validate()).Should(Succeed())
 		})
+
+		It("create cluster with monitoring enabled", func() {
+			By("validate the Prometheus operator is installed")
+			o.MonitoringEnabled = true
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("the Prometheus operator is not installed")))
+			crd := &unstructured.Unstructured{}
+			crd.SetAPIVersion(types.CustomResourceDefinitionAPIGroup + "/" + types.CustomResourceDefinitionAPIVersion)
+			crd.SetKind("CustomResourceDefinition")
+			crd.SetName(podMonitorCRDName)
+			_, err := tf.FakeDynamicClient.Resource(types.CustomResourceDefinitionGVR()).Create(context.TODO(), crd, metav1.CreateOptions{})
+			Expect(err).Should(Succeed())
+			Expect(o.Validate()).Should(Succeed())
+
+			By("only the components with an exporter are monitored")
+			Expect(o.Complete()).Should(Succeed())
+			Expect(o.monitorTargets).Should(BeEmpty())
+			clusterDef := testing.FakeClusterDef()
+			clusterDef.Spec.ComponentDefs[0].Monitor = &appsv1alpha1.MonitorConfig{
+				Exporter: &appsv1alpha1.ExporterConfig{ScrapePort: intstr.FromInt(9104), ScrapePath: "/metrics"},
+			}
+			cdObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(clusterDef)
+			Expect(err).Should(Succeed())
+			_, err = tf.FakeDynamicClient.Resource(types.ClusterDefGVR()).Update(context.TODO(), &unstructured.Unstructured{Object: cdObj}, metav1.UpdateOptions{})
+			Expect(err).Should(Succeed())
+			Expect(o.Complete()).Should(Succeed())
+			Expect(o.monitorTargets).Should(HaveLen(1))
+			Expect(o.ComponentSpecs[0][monitorKey]).Should(BeTrue())
+			Expect(o.ComponentSpecs[1]).ShouldNot(HaveKey(monitorKey))
+
+			By("enable or disable the exporters of the components")
+			o.Exporters = map[string]string{testing.ExtraComponentDefName: "true"}
+			Expect(o.Complete()).Should(MatchError(ContainSubstring("does not provide a metrics exporter")))
+			o.Exporters = map[string]string{"unknown": "true"}
+			Expect(o.Complete()).Should(MatchError(ContainSubstring("are not found in the cluster")))
+			o.Exporters = map[string]string{testing.ComponentDefName: "false"}
+			Expect(o.Complete()).Should(Succeed())
+			Expect(o.monitorTargets).Should(BeEmpty())
+			Expect(o.ComponentSpecs[0]).ShouldNot(HaveKey(monitorKey))
+
+			By("create the cluster and the PodMonitor")
+			o.Exporters = nil
+			o.MonitoringInterval = 30
+			Expect(o.Complete()).Should(Succeed())
+			Run()
+			podMonitor, err := tf.FakeDynamicClient.Resource(types.PodMonitorGVR()).Namespace(namespace).
+				Get(context.TODO(), fmt.Sprintf("%s-%s", clusterName, testing.ComponentDefName), metav1.GetOptions{})
+			Expect(err).Should(Succeed())
+			Expect(podMonitor.GetOwnerReferences()).Should(HaveLen(1))
+			Expect(podMonitor.GetOwnerReferences()[0].Name).Should(Equal(clusterName))
+			endpoints, _, _ := unstructured.NestedSlice(podMonitor.Object, "spec", "podMetricsEndpoints")
+			Expect(endpoints).Should(ConsistOf(map[string]interface{}{"targetPort": int64(9104), "path": "/metrics", "interval": "30s"}))
+		})
 	})
 
 	Context("create validate", func() {
@@ -406,22 +472,44 @@ var _ = Describe("Cluster", func() {
 			})
 
 			It("can specify the StorageClass and the StorageClass must exist", func() {
-				Expect(validateStorageClass(o.Dynamic, o.ComponentSpecs)).Should(Succeed())
+				Expect(validateStorageClass(o.Dynamic, o.ComponentSpecs, false)).Should(Succeed())
 				fakeNotDefaultStorageClass := testing.FakeStorageClass(testing.StorageClassName+"-other", testing.IsNotDefault)
 				FakeDynamicClientWithNotDefaultSC := testing.FakeDynamicClient(testing.FakeClusterDef(), fakeNotDefaultStorageClass, testing.FakeClusterVersion(), testing.FakeConfigMap("kubeblocks-manager-config", types.DefaultNamespace, fakeConfigData), testing.FakeSecret(types.DefaultNamespace, clusterName))
-				Expect(validateStorageClass(FakeDynamicClientWithNotDefaultSC, o.ComponentSpecs)).Should(HaveOccurred())
+				Expect(validateStorageClass(FakeDynamicClientWithNotDefaultSC, o.ComponentSpecs, false)).Should(HaveOccurred())
 			})
 
 			It("can get valiate the default StorageClasses", func() {
 				vct := o.ComponentSpecs[0]["volumeClaimTemplates"].([]interface{})
 				spec := vct[0].(map[string]interface{})["spec"]
 				delete(spec.(map[string]interface{}), "storageClassName")
-				Expect(validateStorageClass(o.Dynamic, o.ComponentSpecs)).Should(Succeed())
+				Expect(validateStorageClass(o.Dynamic, o.ComponentSpecs, false)).Should(Succeed())
 				FakeDynamicClientWithNotDefaultSC := testing.FakeDynamicClient(testing.FakeClusterDef(), testing.FakeStorageClass(testing.StorageClassName+"-other", testing.IsNotDefault), testing.FakeClusterVersion(), testing.FakeConfigMap("kubeblocks-manager-config", types.DefaultNamespace, fakeConfigData), testing.FakeSecret(types.DefaultNamespace, clusterName))
-				Expect(validateStorageClass(FakeDynamicClientWithNotDefaultSC, o.ComponentSpecs)).Should(HaveOccurred())
+				Expect(validateStorageClass(FakeDynamicClientWithNotDefaultSC, o.ComponentSpecs, false)).Should(HaveOccurred())
 				// It can validate 'DEFAULT_STORAGE_CLASS' in ConfigMap for cloud K8S
 				FakeDynamicClientWithConfigDefaultSC := testing.FakeDynamicClient(testing.FakeClusterDef(), testing.FakeStorageClass(testing.StorageClassName+"-other", testing.IsNotDefault), testing.FakeClusterVersion(), testing.FakeConfigMap("kubeblocks-manager-config", types.DefaultNamespace, fakeConfigDataWithDefaultSC), testing.FakeSecret(types.DefaultNamespace, clusterName))
-				Expect(validateStorageClass(FakeDynamicClientWithConfigDefaultSC, o.ComponentSpecs)).Should(Succeed())
+				Expect(validateStorageClass(FakeDynamicClientWithConfigDefaultSC, o.ComponentSpecs, false)).Should(Succeed())
+			})
+
+			It("can validate the access modes and volume snapshot of the StorageClass", func() {
+				vct := o.ComponentSpecs[0]["volumeClaimTemplates"].([]interface{})
+				spec := vct[0].(map[string]interface{})["spec"].(map[string]interface{})
+				spec["accessModes"] = []interface{}{"ReadWriteMany"}
+				sc := testing.FakeStorageClass(testing.StorageClassName, testing.IsDefault)
+				sc.Provisioner = "ebs.csi.aws.com"
+				newDynamic := func(driver string) *dynamicfakeclient.FakeDynamicClient {
+					vsc := &unstructured.Unstructured{Object: map[string]interface{}{
+						"apiVersion": "snapshot.storage.k8s.io/v1",
+						"kind":       "VolumeSnapshotClass",
+						"metadata":   map[string]interface{}{"name": "csi-snapclass"},
+						"driver":     driver,
+					}}
+					return testing.FakeDynamicClient(sc, vsc, testing.FakeConfigMap("kubeblocks-manager-config", types.DefaultNamespace, fakeConfigData))
+				}
+				Expect(validateStorageClass(newDynamic(sc.Provisioner), o.ComponentSpecs, false)).Should(MatchError(ContainSubstring("does not support the access mode ReadWriteMany")))
+				spec["accessModes"] = []interface{}{"ReadWriteOnce"}
+				Expect(validateStorageClass(newDynamic(sc.Provisioner), o.ComponentSpecs, false)).Should(Succeed())
+				Expect(validateStorageClass(newDynamic("hostpath.csi.k8s.io"), o.ComponentSpecs, true)).Should(MatchError(ContainSubstring("does not support volume snapshot")))
+				Expect(validateStorageClass(newDynamic(sc.Provisioner), o.ComponentSpecs, true)).Should(Succeed())
 			})
 
 			It("validateDefaultSCInConfig test", func() {
@@ -0,0 +1,262 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// configParamsFile is the content of the parameters file of the configure command, such as:
+//
+//	components:
+//	- componentName: mysql
+//	  parameters:
+//	    max_connections: 2000
+//	    general_log: "OFF"
+//	- componentName: proxy
+//	  configSpec: proxy-config
+//	  configFile: proxy.cnf
+//	  parameters:
+//	    thread_pool_size: 8
+type configParamsFile struct {
+	Components []configParamsItem `json:"components"`
+}
+
+type configParamsItem struct {
+	ComponentName string `json:"componentName"`
+	// ConfigSpec and ConfigFile are auto filled as the --config-spec and --config-file if not specified
+	ConfigSpec string `json:"configSpec,omitempty"`
+	ConfigFile string `json:"configFile,omitempty"`
+	// Parameters are the parameters to update, the parameter is removed if the value is null
+	Parameters map[string]any `json:"parameters"`
+}
+
+// configBatchItem is the parameters to update of a config file
+type configBatchItem struct {
+	wrapper   *configWrapper
+	keyValues map[string]*string
+	restart   bool
+}
+
+// completeBatch loads the parameters file and fills the config spec and config file of the components
+func (o *configOpsOptions) completeBatch() error {
+	if len(o.Parameters) > 0 || o.LocalFilePath != "" || o.ComponentName != "" || o.CfgTemplateName != "" || o.CfgFile != "" {
+		return fmt.Errorf("--file can not be used with --set, --local-file, --component, --config-spec and --config-file")
+	}
+	data, err := os.ReadFile(o.paramsFile)
+	if err != nil {
+		return err
+	}
+	paramsFile := configParamsFile{}
+	if err = yaml.UnmarshalStrict(data, &paramsFile); err != nil {
+		return fmt.Errorf("failed to parse the parameters file %s: %v", o.paramsFile, err)
+	}
+	if len(paramsFile.Components) == 0 {
+		return fmt.Errorf("no component is specified in the parameters file %s", o.paramsFile)
+	}
+
+	// nil cannot be set to a map struct in CueLang, so init the map of KeyValues.
+	o.KeyValues = map[string]*string{}
+	var errs []error
+	for i, item := range paramsFile.Components {
+		if item.ComponentName == "" {
+			errs = append(errs, fmt.Errorf("the componentName of the item %d is empty", i))
+			continue
+		}
+		if len(item.Parameters) == 0 {
+			errs = append(errs, fmt.Errorf("component %s: no parameter is specified", item.ComponentName))
+			continue
+		}
+		keyValues := make(map[string]*string, len(item.Parameters))
+		for k, v := range item.Parameters {
+			keyValues[k] = formatParamValue(v)
+		}
+		wrapper, err := newConfigWrapper(o.CreateOptions, o.Name, item.ComponentName, item.ConfigSpec, item.ConfigFile, keyValues)
+		if err == nil {
+			err = wrapper.AutoFillRequiredParam()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %v", item.ComponentName, err))
+			continue
+		}
+		o.batch = append(o.batch, &configBatchItem{wrapper: wrapper, keyValues: keyValues})
+	}
+	return errors.NewAggregate(errs)
+}
+
+// validateBatch validates all parameters against the ConfigConstraints and reports all invalid parameters at once,
+// then shows the parameters to update and whether the components are restarted.
+func (o *configOpsOptions) validateBatch() error {
+	var errs []error
+	restart := false
+	for _, item := range o.batch {
+		w := item.wrapper
+		err := w.ValidateRequiredParam(false)
+		if err == nil {
+			err = func() error {
+				cc, data, err := validateConfigData(o.Dynamic, w.ConfigTemplateSpec(), w.ConfigFile(), "", item.keyValues)
+				if err != nil {
+					return err
+				}
+				if err = util.ValidateParametersModified2(sets.KeySet(item.keyValues), *cc); err != nil {
+					return err
+				}
+				item.restart, err = isRestartRequired(cc, data, w.ConfigTemplateSpec(), false)
+				return err
+			}()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("component %s, config file %s: %v", w.ComponentName(), w.ConfigFile(), err))
+			continue
+		}
+		item.restart = item.restart || o.ForceRestart
+		restart = restart || item.restart
+	}
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+
+	o.printBatch()
+	if restart {
+		return o.confirmReconfigureWithRestart()
+	}
+	return nil
+}
+
+func (o *configOpsOptions) printBatch() {
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("COMPONENT", "CONFIG-SPEC", "CONFIG-FILE", "PARAMETERS", "RESTART")
+	for _, item := range o.batch {
+		params := make([]string, 0, len(item.keyValues))
+		for k, v := range item.keyValues {
+			value := "null"
+			if v != nil {
+				value = *v
+			}
+			params = append(params, fmt.Sprintf("%s=%s", k, value))
+		}
+		sort.Strings(params)
+		restart := "No"
+		if item.restart {
+			restart = printer.BoldYellow("Yes")
+		}
+		tbl.AddRow(item.wrapper.ComponentName(), item.wrapper.ConfigSpecName(), item.wrapper.ConfigFile(), strings.Join(params, ","), restart)
+	}
+	tbl.Print()
+}
+
+// buildBatchConfigurations groups the parameters by the components and the config specs, since an
+// OpsRequest can only reconfigure one component, the components are returned in order.
+func (o *configOpsOptions) buildBatchConfigurations() ([]string, map[string][]appsv1alpha1.ConfigurationItem) {
+	var components []string
+	configurations := map[string][]appsv1alpha1.ConfigurationItem{}
+	for _, item := range o.batch {
+		compName := item.wrapper.ComponentName()
+		if _, ok := configurations[compName]; !ok {
+			components = append(components, compName)
+		}
+		items := configurations[compName]
+		index := -1
+		for i := range items {
+			if items[i].Name == item.wrapper.ConfigSpecName() {
+				index = i
+			}
+		}
+		if index < 0 {
+			configItem := appsv1alpha1.ConfigurationItem{Name: item.wrapper.ConfigSpecName()}
+			if o.ForceRestart {
+				policy := appsv1alpha1.NormalPolicy
+				configItem.Policy = &policy
+			}
+			items = append(items, configItem)
+			index = len(items) - 1
+		}
+
+		keyIndex := -1
+		for i := range items[index].Keys {
+			if items[index].Keys[i].Key == item.wrapper.ConfigFile() {
+				keyIndex = i
+			}
+		}
+		if keyIndex < 0 {
+			items[index].Keys = append(items[index].Keys, appsv1alpha1.ParameterConfig{Key: item.wrapper.ConfigFile()})
+			keyIndex = len(items[index].Keys) - 1
+		}
+		keys := make([]string, 0, len(item.keyValues))
+		for k := range item.keyValues {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			items[index].Keys[keyIndex].Parameters = append(items[index].Keys[keyIndex].Parameters, appsv1alpha1.ParameterPair{Key: k, Value: item.keyValues[k]})
+		}
+		configurations[compName] = items
+	}
+	return components, configurations
+}
+
+// runBatch creates a Reconfiguring OpsRequest for each component in the parameters file
+func (o *configOpsOptions) runBatch() error {
+	clusterName, opsRequestName := o.Name, o.OpsRequestName
+	components, configurations := o.buildBatchConfigurations()
+	for _, compName := range components {
+		// the name is set to the created OpsRequest after running
+		o.Name = clusterName
+		o.ComponentNames = []string{compName}
+		o.Configurations = configurations[compName]
+		if opsRequestName != "" && len(components) > 1 {
+			o.OpsRequestName = fmt.Sprintf("%s-%s", opsRequestName, compName)
+		}
+		if err := o.OperationsOptions.Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatParamValue formats the parameter value in the parameters file as the string, the numbers
+// are formatted without the exponent.
+func formatParamValue(v any) *string {
+	var s string
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case string:
+		s = value
+	case float64:
+		s = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		s = fmt.Sprint(value)
+	}
+	return &s
+}
@@ -238,10 +238,10 @@ func NewEditConfigureCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.CreateOptions.Complete())
 			util.CheckErr(o.Complete())
 			util.CheckErr(o.Validate())
-			util.CheckErr(o.Run(o.CreateOptions.Run))
+			util.CheckErr(o.Run(o.OperationsOptions.Run))
 		},
 	}
 	o.buildReconfigureCommonFlags(cmd, f)
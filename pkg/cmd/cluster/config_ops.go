@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,6 +53,10 @@ type configOpsOptions struct {
 	// config file replace
 	replaceFile bool
 
+	// paramsFile is the file of the parameters of multiple components
+	paramsFile string
+	batch      []*configBatchItem
+
 	// Reconfiguring options
 	ComponentName string
 	LocalFilePath string   `json:"localFilePath"`
@@ -66,6 +71,9 @@ var (
 		# if only one component, and one config spec, and one config file, simplify the searching process of configure. e.g:
 		# update mysql max_connections, cluster name is mycluster
 		kbcli cluster configure mycluster --set max_connections=2000
+
+		# update the parameters of multiple components in the file, all parameters are validated before updating
+		kbcli cluster configure mycluster -f params.yaml
 	`)
 )
 
@@ -73,6 +81,9 @@ func (o *configOpsOptions) Complete() error {
 	if o.Name == "" {
 		return makeMissingClusterNameErr()
 	}
+	if o.paramsFile != "" {
+		return o.completeBatch()
+	}
 
 	if !o.editMode {
 		if err := o.validateReconfigureOptions(); err != nil {
@@ -111,6 +122,9 @@ func (o *configOpsOptions) validateReconfigureOptions() error {
 
 // Validate command flags or args is legal
 func (o *configOpsOptions) Validate() error {
+	if o.paramsFile != "" {
+		return o.validateBatch()
+	}
 	if err := o.wrapper.ValidateRequiredParam(o.replaceFile); err != nil {
 		return err
 	}
@@ -132,33 +146,61 @@ func (o *configOpsOptions) Validate() error {
 	return nil
 }
 
+// Run creates the Reconfiguring OpsRequests
+func (o *configOpsOptions) Run() error {
+	if o.paramsFile != "" {
+		return o.runBatch()
+	}
+	return o.OperationsOptions.Run()
+}
+
 func (o *configOpsOptions) validateConfigParams(tpl *appsv1alpha1.ComponentConfigSpec) error {
+	cc, newConfigData, err := validateConfigData(o.Dynamic, tpl, o.CfgFile, o.FileContent, o.KeyValues)
+	if err != nil {
+		return err
+	}
+	return o.checkChangedParamsAndDoubleConfirm(cc, newConfigData, tpl)
+}
+
+// validateConfigData validates the updated parameters or the file content against the ConfigConstraint
+// of the config template, and returns the ConfigConstraint and the new config data.
+func validateConfigData(cli dynamic.Interface, tpl *appsv1alpha1.ComponentConfigSpec, cfgFile, fileContent string, keyValues map[string]*string) (*appsv1alpha1.ConfigConstraintSpec, map[string]string, error) {
 	configConstraintKey := client.ObjectKey{
 		Namespace: "",
 		Name:      tpl.ConfigConstraintRef,
 	}
 	configConstraint := appsv1alpha1.ConfigConstraint{}
-	if err := util.GetResourceObjectFromGVR(types.ConfigConstraintGVR(), configConstraintKey, o.Dynamic, &configConstraint); err != nil {
-		return err
+	if err := util.GetResourceObjectFromGVR(types.ConfigConstraintGVR(), configConstraintKey, cli, &configConstraint); err != nil {
+		return nil, nil, err
 	}
 
 	var err error
 	var newConfigData map[string]string
-	if o.FileContent != "" {
-		newConfigData = map[string]string{o.CfgFile: o.FileContent}
+	if fileContent != "" {
+		newConfigData = map[string]string{cfgFile: fileContent}
 	} else {
-		newConfigData, err = controllerutil.MergeAndValidateConfigs(configConstraint.Spec, map[string]string{o.CfgFile: ""}, tpl.Keys, []core.ParamPairs{{
-			Key:           o.CfgFile,
-			UpdatedParams: core.FromStringMap(o.KeyValues),
+		newConfigData, err = controllerutil.MergeAndValidateConfigs(configConstraint.Spec, map[string]string{cfgFile: ""}, tpl.Keys, []core.ParamPairs{{
+			Key:           cfgFile,
+			UpdatedParams: core.FromStringMap(keyValues),
 		}})
 	}
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	return o.checkChangedParamsAndDoubleConfirm(&configConstraint.Spec, newConfigData, tpl)
+	return &configConstraint.Spec, newConfigData, nil
 }
 
 func (o *configOpsOptions) checkChangedParamsAndDoubleConfirm(cc *appsv1alpha1.ConfigConstraintSpec, data map[string]string, tpl *appsv1alpha1.ComponentConfigSpec) error {
+	restart, err := isRestartRequired(cc, data, tpl, o.FileContent != "")
+	if err != nil || !restart {
+		return err
+	}
+	return o.confirmReconfigureWithRestart()
+}
+
+// isRestartRequired checks whether the component is restarted to apply the new config data, the
+// component is not restarted only if the changed parameters are all dynamic and can be reloaded.
+func isRestartRequired(cc *appsv1alpha1.ConfigConstraintSpec, data map[string]string, tpl *appsv1alpha1.ComponentConfigSpec, replaceFile bool) (bool, error) {
 	mockEmptyData := func(m map[string]string) map[string]string {
 		r := make(map[string]string, len(data))
 		for key := range m {
@@ -168,25 +210,22 @@ func (o *configOpsOptions) checkChangedParamsAndDoubleConfirm(cc *appsv1alpha1.C
 	}
 
 	if !cfgcm.IsSupportReload(cc.ReloadOptions) {
-		return o.confirmReconfigureWithRestart()
+		return true, nil
 	}
 
-	configPatch, restart, err := core.CreateConfigPatch(mockEmptyData(data), data, cc.FormatterConfig.Format, tpl.Keys, o.FileContent != "")
+	configPatch, restart, err := core.CreateConfigPatch(mockEmptyData(data), data, cc.FormatterConfig.Format, tpl.Keys, replaceFile)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if restart {
-		return o.confirmReconfigureWithRestart()
+		return true, nil
 	}
 
 	dynamicUpdated, err := core.IsUpdateDynamicParameters(cc, configPatch)
 	if err != nil {
-		return nil
+		return false, nil
 	}
-	if dynamicUpdated {
-		return nil
-	}
-	return o.confirmReconfigureWithRestart()
+	return !dynamicUpdated, nil
 }
 
 func (o *configOpsOptions) confirmReconfigureWithRestart() error {
@@ -254,21 +293,22 @@ func NewReconfigureCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *c
 		OperationsOptions: newBaseOperationsOptions(f, streams, appsv1alpha1.ReconfiguringType, false),
 	}
 	cmd := &cobra.Command{
-		Use:               "configure NAME --set key=value[,key=value] [--component=component-name] [--config-spec=config-spec-name] [--config-file=config-file]",
+		Use:               "configure NAME {--set key=value[,key=value] [--component=component-name] [--config-spec=config-spec-name] [--config-file=config-file] | -f params.yaml}",
 		Short:             "Configure parameters with the specified components in the cluster.",
 		Example:           createReconfigureExample,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.CreateOptions.Complete())
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
 	o.buildReconfigureCommonFlags(cmd, f)
+	cmd.Flags().StringVarP(&o.paramsFile, "file", "f", "", "Specify the YAML file of the parameters to be updated of multiple components")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before reconfiguring the cluster")
 	return cmd
 }
@@ -22,6 +22,8 @@ package cluster
 import (
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -37,7 +39,9 @@ import (
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 
+	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
 )
 
 var _ = Describe("reconfigure test", func() {
@@ -134,4 +138,95 @@ var _ = Describe("reconfigure test", func() {
 		Expect(o.Validate()).Should(Succeed())
 	})
 
+	It("configure parameters of multiple components from file", func() {
+		const (
+			ns                  = "default"
+			clusterDefName      = "test-clusterdef"
+			clusterName         = "test-cluster"
+			statefulCompDefName = "replicasets"
+			statefulCompName    = "mysql"
+			configSpecName      = "mysql-config-tpl"
+			configVolumeName    = "mysql-config"
+		)
+
+		configmap := testapps.NewCustomizedObj("resources/mysql-config-template.yaml", &corev1.ConfigMap{}, testapps.WithNamespace(ns))
+		constraint := testapps.NewCustomizedObj("resources/mysql-config-constraint.yaml",
+			&appsv1alpha1.ConfigConstraint{})
+		clusterDefObj := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.StatefulMySQLComponent, statefulCompDefName).
+			AddConfigTemplate(configSpecName, configmap.Name, constraint.Name, ns, configVolumeName).
+			GetObject()
+		clusterObj := testapps.NewClusterFactory(ns, clusterName, clusterDefObj.Name, "").
+			AddComponent(statefulCompName, statefulCompDefName).
+			AddComponent(statefulCompName+"-1", statefulCompDefName).GetObject()
+		objs := []runtime.Object{configmap, constraint, clusterDefObj, clusterObj,
+			testapps.NewConfigMap(ns, cfgcore.GetComponentCfgName(clusterName, statefulCompName, configSpecName), testapps.SetConfigMapData("my.cnf", "")),
+			testapps.NewConfigMap(ns, cfgcore.GetComponentCfgName(clusterName, statefulCompName+"-1", configSpecName), testapps.SetConfigMapData("my.cnf", "")),
+		}
+		ttf, ops := NewFakeOperationsOptions(ns, clusterObj.Name, appsv1alpha1.ReconfiguringType, objs...)
+		defer ttf.Cleanup()
+		o := &configOpsOptions{OperationsOptions: newBaseOperationsOptions(tf, streams, appsv1alpha1.ReconfiguringType, false)}
+		o.Dynamic = ops.Dynamic
+		o.Name = clusterName
+		o.Namespace = ns
+		o.DryRun = "none"
+		writeParams := func(content string) {
+			o.paramsFile = filepath.Join(GinkgoT().TempDir(), "params.yaml")
+			Expect(os.WriteFile(o.paramsFile, []byte(content), 0600)).Should(Succeed())
+			o.batch = nil
+		}
+
+		By("report all invalid parameters at once")
+		writeParams(`
+components:
+- componentName: mysql
+  parameters:
+    automatic_sp_privileges: "invalid"
+- componentName: mysql-1
+  parameters:
+    auto_increment_increment: 0
+`)
+		Expect(o.Complete()).Should(Succeed())
+		Expect(o.batch).Should(HaveLen(2))
+		err := o.Validate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("component mysql,"))
+		Expect(err.Error()).Should(ContainSubstring("component mysql-1,"))
+
+		By("show the restart impact and confirm")
+		writeParams(`
+components:
+- componentName: mysql
+  parameters:
+    automatic_sp_privileges: "OFF"
+    innodb_autoinc_lock_mode: 1
+- componentName: mysql-1
+  parameters:
+    innodb_autoinc_lock_mode: 0
+`)
+		Expect(o.Complete()).Should(Succeed())
+		in.Write([]byte("yes\n"))
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.batch[0].restart).Should(BeTrue())
+		Expect(o.batch[1].restart).Should(BeFalse())
+
+		By("create an OpsRequest for each component")
+		o.OpsRequestName = "reconfigure"
+		Expect(o.Run()).Should(Succeed())
+		components := map[string]appsv1alpha1.Reconfigure{}
+		for _, compName := range []string{statefulCompName, statefulCompName + "-1"} {
+			opsRequest := appsv1alpha1.OpsRequest{}
+			Expect(cluster.GetK8SClientObject(o.Dynamic, &opsRequest, types.OpsGVR(), ns, "reconfigure-"+compName)).Should(Succeed())
+			components[opsRequest.Spec.Reconfigure.ComponentName] = *opsRequest.Spec.Reconfigure
+		}
+		Expect(components[statefulCompName].Configurations[0].Name).Should(Equal(configSpecName))
+		Expect(components[statefulCompName].Configurations[0].Keys[0].Key).Should(Equal("my.cnf"))
+		Expect(components[statefulCompName].Configurations[0].Keys[0].Parameters).Should(HaveLen(2))
+		Expect(*components[statefulCompName+"-1"].Configurations[0].Keys[0].Parameters[0].Value).Should(Equal("0"))
+
+		By("file can not be used with --set")
+		o.Parameters = []string{"max_connections=100"}
+		Expect(o.Complete()).Should(MatchError(ContainSubstring("--file can not be used")))
+	})
+
 })
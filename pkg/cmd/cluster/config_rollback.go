@@ -0,0 +1,238 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+// configVersion is a version of the config file in the configuration history, the version 0 is the
+// config file before the first reconfiguring, and the version N is the config file after the Nth
+// succeeded reconfiguring.
+type configVersion struct {
+	version int
+	// ops is the reconfiguring OpsRequest which updates the config file from this version
+	ops     *appsv1alpha1.OpsRequest
+	content string
+}
+
+type configRollbackOptions struct {
+	configOpsOptions
+
+	toVersion int
+}
+
+var rollbackConfigExample = templates.Examples(`
+		# list the versions of the configuration of the component
+		kbcli cluster rollback-config mycluster --component=mysql
+
+		# rollback the configuration of the component to the version 1
+		kbcli cluster rollback-config mycluster --component=mysql --to-version 1
+	`)
+
+// listConfigVersions lists the versions of the config file from the succeeded reconfiguring OpsRequests, the
+// last applied configuration of each OpsRequest is the config file before it is applied.
+func (o *configRollbackOptions) listConfigVersions() ([]configVersion, error) {
+	opsList, err := o.Dynamic.Resource(types.OpsGVR()).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(unstructuredList(opsList.Items))
+	var versions []configVersion
+	for _, obj := range opsList.Items {
+		ops := &appsv1alpha1.OpsRequest{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ops); err != nil {
+			return nil, err
+		}
+		if ops.Spec.Type != appsv1alpha1.ReconfiguringType || ops.Spec.Reconfigure == nil ||
+			ops.Spec.Reconfigure.ComponentName != o.wrapper.ComponentName() || ops.Status.Phase != appsv1alpha1.OpsSucceedPhase {
+			continue
+		}
+		status := findTemplateStatusByName(ops.Status.ReconfiguringStatus, o.CfgTemplateName)
+		if status == nil {
+			continue
+		}
+		content, ok := status.LastAppliedConfiguration[o.CfgFile]
+		if !ok {
+			continue
+		}
+		versions = append(versions, configVersion{version: len(versions), ops: ops, content: content})
+	}
+	return versions, nil
+}
+
+func (o *configRollbackOptions) printConfigVersions(versions []configVersion) {
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("VERSION", "UPDATED-BY", "CREATED-TIME", "VALID-UPDATED")
+	for _, v := range versions {
+		tbl.AddRow(v.version, v.ops.Name, util.TimeFormat(&v.ops.CreationTimestamp), getValidUpdatedParams(v.ops.Status))
+	}
+	tbl.Print()
+	fmt.Fprintf(o.Out, "\nThe current configuration is the version %d.\n", len(versions))
+}
+
+func (o *configRollbackOptions) Run() error {
+	versions, err := o.listConfigVersions()
+	if err != nil {
+		return err
+	}
+	if o.toVersion < 0 {
+		o.printConfigVersions(versions)
+		return nil
+	}
+	switch {
+	case len(versions) == 0:
+		return fmt.Errorf("no configuration history of the config file %s is found", o.CfgFile)
+	case o.toVersion == len(versions):
+		return fmt.Errorf("the version %d is the current configuration", o.toVersion)
+	case o.toVersion > len(versions):
+		return fmt.Errorf("the version %d is not found in the configuration history of the config file %s, the available versions are 0 to %d", o.toVersion, o.CfgFile, len(versions)-1)
+	}
+
+	cmObj := corev1.ConfigMap{}
+	cmKey := client.ObjectKey{
+		Name:      core.GetComponentCfgName(o.Name, o.wrapper.ComponentName(), o.CfgTemplateName),
+		Namespace: o.Namespace,
+	}
+	if err = util.GetResourceObjectFromGVR(types.ConfigmapGVR(), cmKey, o.Dynamic, &cmObj); err != nil {
+		return err
+	}
+	current, target := cmObj.Data[o.CfgFile], versions[o.toVersion].content
+	if current == target {
+		fmt.Fprintf(o.Out, "The config file %s is the same as the version %d, no changes made.\n", o.CfgFile, o.toVersion)
+		return nil
+	}
+
+	configSpec := o.wrapper.ConfigTemplateSpec()
+	o.KeyValues = map[string]*string{}
+	confirmPrompt := fmt.Sprintf(fullRestartConfirmPrompt, printer.BoldRed(o.CfgFile))
+	if configSpec.ConfigConstraintRef == "" {
+		o.HasPatch = false
+		o.FileContent = target
+	} else {
+		configConstraint := appsv1alpha1.ConfigConstraint{}
+		if err = util.GetResourceObjectFromGVR(types.ConfigConstraintGVR(), client.ObjectKey{Name: configSpec.ConfigConstraintRef}, o.Dynamic, &configConstraint); err != nil {
+			return err
+		}
+		formatterConfig := configConstraint.Spec.FormatterConfig
+		if formatterConfig == nil {
+			return core.MakeError("config spec[%s] not support reconfiguring!", configSpec.Name)
+		}
+		configPatch, fileUpdated, err := core.CreateConfigPatch(map[string]string{o.CfgFile: current}, map[string]string{o.CfgFile: target}, formatterConfig.Format, configSpec.Keys, true)
+		if err != nil {
+			return err
+		}
+		if !fileUpdated && !configPatch.IsModify {
+			fmt.Fprintf(o.Out, "The parameters of the config file %s are the same as the version %d, no changes made.\n", o.CfgFile, o.toVersion)
+			return nil
+		}
+		params := core.GenerateVisualizedParamsList(configPatch, formatterConfig, nil)
+		o.KeyValues = fromKeyValuesToMap(params, o.CfgFile)
+		if err = util.ValidateParametersModified2(sets.KeySet(o.KeyValues), configConstraint.Spec); err != nil {
+			return err
+		}
+		o.HasPatch = true
+		if confirmPrompt, err = generateReconfiguringPrompt(fileUpdated, configPatch, &configConstraint.Spec, o.CfgFile); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(o.Out, "Rollback the config file %s to the version %d:\n", o.CfgFile, o.toVersion)
+		tbl := printer.NewTablePrinter(o.Out)
+		tbl.SetHeader("PARAMETER", "VALUE")
+		keys := make([]string, 0, len(o.KeyValues))
+		for k := range o.KeyValues {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value := "null"
+			if v := o.KeyValues[k]; v != nil {
+				value = *v
+			}
+			tbl.AddRow(k, value)
+		}
+		tbl.Print()
+	}
+
+	if !o.autoApprove && o.DryRun == "none" {
+		printer.Warning(o.Out, confirmPrompt)
+		if err = prompt.Confirm([]string{o.Name}, o.In, "", ""); err != nil {
+			return err
+		}
+	}
+	return o.OperationsOptions.Run()
+}
+
+// NewRollbackConfigureCmd creates a command to rollback the configuration to a version in the history
+func NewRollbackConfigureCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &configRollbackOptions{
+		configOpsOptions: configOpsOptions{
+			editMode:          true,
+			OperationsOptions: newBaseOperationsOptions(f, streams, appsv1alpha1.ReconfiguringType, false),
+		},
+	}
+	cmd := &cobra.Command{
+		Use:               "rollback-config NAME [--component=component-name] [--config-spec=config-spec-name] [--config-file=config-file] [--to-version=version]",
+		Short:             "Rollback the configuration of the component to a version in the configuration history.",
+		Example:           rollbackConfigExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Args = args
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			util.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+	o.addCommonFlags(cmd, f)
+	cmd.Flags().StringVar(&o.CfgTemplateName, "config-spec", "", "Specify the name of the configuration template to rollback. "+
+		"For available templates and configs, refer to: 'kbcli cluster describe-config'.")
+	cmd.Flags().StringVar(&o.CfgFile, "config-file", "", "Specify the name of the configuration file to rollback. "+
+		"For available templates and configs, refer to: 'kbcli cluster describe-config'.")
+	flags.AddComponentFlag(f, cmd, &o.ComponentName, "Specify the name of Component to rollback. If the cluster has only one component, unset the parameter.")
+	cmd.Flags().IntVar(&o.toVersion, "to-version", -1, "Specify the version to rollback, list the versions of the configuration if not specified")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before rolling back the configuration")
+	return cmd
+}
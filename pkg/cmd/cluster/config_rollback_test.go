@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("rollback config test", func() {
+	const (
+		ns                  = "default"
+		clusterDefName      = "test-clusterdef"
+		clusterName         = "test-cluster"
+		statefulCompDefName = "replicasets"
+		statefulCompName    = "mysql"
+		configSpecName      = "mysql-config-tpl"
+		configVolumeName    = "mysql-config"
+	)
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = cmdtesting.NewTestFactory().WithNamespace(ns)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	mockReconfigureOps := func(name string, created time.Time, lastApplied string) *appsv1alpha1.OpsRequest {
+		return &appsv1alpha1.OpsRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         ns,
+				Labels:            map[string]string{constant.AppInstanceLabelKey: clusterName},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: appsv1alpha1.OpsRequestSpec{
+				ClusterRef: clusterName,
+				Type:       appsv1alpha1.ReconfiguringType,
+				Reconfigure: &appsv1alpha1.Reconfigure{
+					ComponentOps:   appsv1alpha1.ComponentOps{ComponentName: statefulCompName},
+					Configurations: []appsv1alpha1.ConfigurationItem{{Name: configSpecName}},
+				},
+			},
+			Status: appsv1alpha1.OpsRequestStatus{
+				Phase: appsv1alpha1.OpsSucceedPhase,
+				ReconfiguringStatus: &appsv1alpha1.ReconfiguringStatus{
+					ConfigurationStatus: []appsv1alpha1.ConfigurationItemStatus{
+						{Name: configSpecName, LastAppliedConfiguration: map[string]string{"my.cnf": lastApplied}},
+					},
+				},
+			},
+		}
+	}
+
+	It("rollback the configuration to the version in history", func() {
+		configmap := testapps.NewCustomizedObj("resources/mysql-config-template.yaml", &corev1.ConfigMap{}, testapps.WithNamespace(ns))
+		constraint := testapps.NewCustomizedObj("resources/mysql-config-constraint.yaml",
+			&appsv1alpha1.ConfigConstraint{})
+		clusterDefObj := testapps.NewClusterDefFactory(clusterDefName).
+			AddComponentDef(testapps.StatefulMySQLComponent, statefulCompDefName).
+			AddConfigTemplate(configSpecName, configmap.Name, constraint.Name, ns, configVolumeName).
+			GetObject()
+		clusterObj := testapps.NewClusterFactory(ns, clusterName, clusterDefObj.Name, "").
+			AddComponent(statefulCompName, statefulCompDefName).GetObject()
+		componentConfig := testapps.NewConfigMap(ns, cfgcore.GetComponentCfgName(clusterName, statefulCompName, configSpecName),
+			testapps.SetConfigMapData("my.cnf", "[mysqld]\ninnodb_autoinc_lock_mode=0\n"))
+		now := time.Now()
+		objs := []runtime.Object{configmap, constraint, clusterDefObj, clusterObj, componentConfig,
+			mockReconfigureOps("reconfigure-2", now.Add(-time.Hour), "[mysqld]\ninnodb_autoinc_lock_mode=1\n"),
+			mockReconfigureOps("reconfigure-1", now.Add(-2*time.Hour), "[mysqld]\ninnodb_autoinc_lock_mode=2\n"),
+		}
+		ttf, ops := NewFakeOperationsOptions(ns, clusterName, appsv1alpha1.ReconfiguringType, objs...)
+		defer ttf.Cleanup()
+
+		o := &configRollbackOptions{
+			configOpsOptions: configOpsOptions{
+				editMode:          true,
+				OperationsOptions: newBaseOperationsOptions(tf, streams, appsv1alpha1.ReconfiguringType, false),
+			},
+			toVersion: -1,
+		}
+		o.Dynamic = ops.Dynamic
+		o.Name = clusterName
+		o.Namespace = ns
+		o.DryRun = "none"
+		Expect(o.Complete()).Should(Succeed())
+		Expect(o.Validate()).Should(Succeed())
+
+		By("list the versions of the configuration")
+		versions, err := o.listConfigVersions()
+		Expect(err).Should(Succeed())
+		Expect(versions).Should(HaveLen(2))
+		Expect(versions[0].ops.Name).Should(Equal("reconfigure-1"))
+		Expect(o.Run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("current configuration is the version 2"))
+
+		By("rollback to the current or unknown version")
+		o.toVersion = 2
+		Expect(o.Run()).Should(MatchError(ContainSubstring("is the current configuration")))
+		o.toVersion = 3
+		Expect(o.Run()).Should(MatchError(ContainSubstring("available versions are 0 to 1")))
+
+		By("rollback to the version 0")
+		o.toVersion = 0
+		o.autoApprove = true
+		o.OpsRequestName = "rollback"
+		Expect(o.Run()).Should(Succeed())
+		Expect(o.KeyValues).Should(HaveKey("innodb_autoinc_lock_mode"))
+		Expect(*o.KeyValues["innodb_autoinc_lock_mode"]).Should(Equal("2"))
+		opsRequest := &appsv1alpha1.OpsRequest{}
+		Expect(cluster.GetK8SClientObject(o.Dynamic, opsRequest, types.OpsGVR(), ns, "rollback")).Should(Succeed())
+		Expect(opsRequest.Spec.Reconfigure.ComponentName).Should(Equal(statefulCompName))
+		Expect(opsRequest.Spec.Reconfigure.Configurations[0].Keys[0].Parameters).Should(HaveLen(1))
+	})
+})
@@ -28,6 +28,7 @@ import (
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/types"
@@ -46,6 +47,11 @@ func NewFakeOperationsOptions(ns, cName string, opsType appsv1alpha1.OpsType, ob
 	if err != nil {
 		panic(err)
 	}
+	// register the typed backups and restores before the list kinds are registered as unstructured,
+	// otherwise the later registration of the typed lists panics
+	if err = dpv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
 
 	// TODO using GroupVersionResource of FakeKubeObjectHelper
 	listMapping := map[schema.GroupVersionResource]string{
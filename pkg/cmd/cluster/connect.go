@@ -60,6 +60,9 @@ var connectExample = templates.Examples(`
 		# connect to a specified component
 		kbcli cluster connect mycluster --component mycomponent
 
+		# connect to cluster with a read-only session to protect the data while debugging
+		kbcli cluster connect mycluster --read-only
+
 		# show cli connection example with password mask
 		kbcli cluster connect mycluster --show-example --client=cli
 
@@ -74,6 +77,24 @@ var connectExample = templates.Examples(`
 
 const passwordMask = "******"
 
+// readOnlySessionScripts add the client options or the session variables to the connect script
+// of the engines to open the session in read-only mode, the other engines don't support it.
+var readOnlySessionScripts = map[models.EngineType]func(script string) string{
+	models.MySQL:              mysqlReadOnlyScript,
+	models.WeSQL:              mysqlReadOnlyScript,
+	models.PostgreSQL:         postgresReadOnlyScript,
+	models.OfficialPostgreSQL: postgresReadOnlyScript,
+	models.ApecloudPostgreSQL: postgresReadOnlyScript,
+}
+
+func mysqlReadOnlyScript(script string) string {
+	return script + " --init-command='SET SESSION TRANSACTION READ ONLY'"
+}
+
+func postgresReadOnlyScript(script string) string {
+	return "PGOPTIONS='-c default_transaction_read_only=on' " + script
+}
+
 type ConnectOptions struct {
 	clusterName   string
 	componentName string
@@ -93,6 +114,7 @@ type ConnectOptions struct {
 
 	userName   string
 	userPasswd string
+	readOnly   bool
 
 	*action.ExecOptions
 }
@@ -123,6 +145,7 @@ func NewConnectCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().StringVar(&o.clientType, "client", "", "Which client connection example should be output, only valid if --show-example is true.")
 
 	cmd.Flags().StringVar(&o.userName, "as-user", "", "Connect to cluster as user")
+	cmd.Flags().BoolVar(&o.readOnly, "read-only", false, "Open the session in read-only mode, only supported by MySQL and PostgreSQL")
 
 	util.CheckErr(cmd.RegisterFlagCompletionFunc("client", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		var types []string
@@ -176,6 +199,10 @@ func (o *ConnectOptions) validate(args []string) error {
 		return fmt.Errorf("either cluster name or instance name should be specified")
 	}
 
+	if o.readOnly && o.showExample {
+		return fmt.Errorf("--read-only can't be used with --show-example")
+	}
+
 	// set custer name
 	if len(args) > 0 {
 		o.clusterName = args[0]
@@ -272,12 +299,27 @@ func (o *ConnectOptions) connect() error {
 
 	o.ExecOptions.ContainerName = o.engine.Container()
 	o.ExecOptions.Command = o.engine.ConnectCommand(authInfo)
+	if o.readOnly {
+		if o.ExecOptions.Command, err = buildReadOnlyConnectCommand(o.componentDef.CharacterType, o.ExecOptions.Command); err != nil {
+			return err
+		}
+	}
 	if klog.V(1).Enabled() {
 		fmt.Fprintf(o.Out, "connect with cmd: %s", o.ExecOptions.Command)
 	}
 	return o.ExecOptions.Run()
 }
 
+// buildReadOnlyConnectCommand builds the connect command opening the session in read-only mode,
+// the connect command is a shell script run by "sh -c".
+func buildReadOnlyConnectCommand(characterType string, command []string) ([]string, error) {
+	readOnlyScript, ok := readOnlySessionScripts[models.EngineType(characterType)]
+	if !ok || len(command) != 3 {
+		return nil, fmt.Errorf("--read-only is not supported by the engine %s", characterType)
+	}
+	return []string{command[0], command[1], readOnlyScript(command[2])}, nil
+}
+
 func (o *ConnectOptions) getAuthInfo() (*engines.AuthInfo, error) {
 	getter := cluster.ObjectsGetter{
 		Client:    o.Client,
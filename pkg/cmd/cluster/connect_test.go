@@ -110,6 +110,24 @@ var _ = Describe("connection", func() {
 		// unset component name
 		o.componentName = ""
 		Expect(o.validate([]string{clusterName})).Should(Succeed())
+
+		By("read-only with show-example")
+		o.readOnly = true
+		o.showExample = true
+		Expect(o.validate([]string{clusterName})).Should(MatchError(ContainSubstring("--read-only can't be used with --show-example")))
+	})
+
+	It("build read-only connect command", func() {
+		command, err := buildReadOnlyConnectCommand("mysql", []string{"sh", "-c", "mysql -u'root' -p'pwd'"})
+		Expect(err).Should(Succeed())
+		Expect(command).Should(Equal([]string{"sh", "-c", "mysql -u'root' -p'pwd' --init-command='SET SESSION TRANSACTION READ ONLY'"}))
+
+		command, err = buildReadOnlyConnectCommand("postgresql", []string{"sh", "-c", "PGUSER='postgres' psql"})
+		Expect(err).Should(Succeed())
+		Expect(command[2]).Should(Equal("PGOPTIONS='-c default_transaction_read_only=on' PGUSER='postgres' psql"))
+
+		_, err = buildReadOnlyConnectCommand("redis", []string{"sh", "-c", "redis-cli"})
+		Expect(err).Should(MatchError(ContainSubstring("--read-only is not supported by the engine redis")))
 	})
 
 	It("complete by cluster name", func() {
@@ -35,7 +35,9 @@ import (
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -48,7 +50,6 @@ import (
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	utilcomp "k8s.io/kubectl/pkg/util/completion"
-	"k8s.io/kubectl/pkg/util/storage"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -105,6 +106,12 @@ var clusterCreateExample = templates.Examples(`
 	# the default storage class will be used
 	kbcli cluster create mycluster --cluster-definition apecloud-mysql --set storageClass=csi-hostpath-sc
 
+	# Create a cluster and set the storage class of all volumes to csi-hostpath-sc
+	kbcli cluster create mycluster --cluster-definition apecloud-mysql --storage-class csi-hostpath-sc
+
+	# List the storage classes with their access modes and volume snapshot support
+	kbcli cluster create --list-storage-classes
+
 	# Create a cluster with replicationSet workloadType and set switchPolicy to Noop
 	kbcli cluster create mycluster --cluster-definition postgresql --set switchPolicy=Noop
 
@@ -141,6 +148,9 @@ var clusterCreateExample = templates.Examples(`
     # Create a cluster with time to restore from point in time
     kbcli cluster create --restore-to-time "Jun 16,2023 18:58:53 UTC+0800" --source-cluster mycluster
 
+	# Create a staging copy of the cluster mycluster in the namespace prod, with 1 replica of each component
+	kbcli cluster create mycluster-stg -n staging --from-cluster prod/mycluster --set replicas=1
+
 	# Create a cluster with auto backup
 	kbcli cluster create --cluster-definition apecloud-mysql --backup-enabled
 
@@ -152,6 +162,12 @@ var clusterCreateExample = templates.Examples(`
 
 	# Create a cluster with using a service reference to another KubeBlocks cluster
 	kbcli cluster create --cluster-definition pulsar --service-reference name=pulsarZookeeper,cluster=zookeeper,namespace=default
+
+	# Create a cluster with monitoring enabled, the metrics of the components are scraped by the Prometheus operator
+	kbcli cluster create --cluster-definition apecloud-mysql --monitoring-enabled
+
+	# Create a cluster with the exporter of the component mysql enabled and the exporter of the component proxy disabled
+	kbcli cluster create --cluster-definition apecloud-mysql --exporter mysql=true,proxy=false
 `)
 
 const (
@@ -259,6 +275,16 @@ type CreateOptions struct {
 	LabelStrs           []string `json:"-"`
 	CPUOversellRatio    float64  `json:"-"`
 	MemoryOversellRatio float64  `json:"-"`
+	StorageClass        string   `json:"-"`
+	ListStorageClasses  bool     `json:"-"`
+
+	// monitoring with the Prometheus operator
+	MonitoringEnabled bool              `json:"-"`
+	Exporters         map[string]string `json:"-"`
+	monitorTargets    []monitorTarget
+
+	// FromCluster is the source cluster in the format of NAMESPACE/NAME to copy the spec from
+	FromCluster string `json:"-"`
 
 	// backup name to restore in creation
 	Backup              string `json:"backup,omitempty"`
@@ -282,10 +308,14 @@ func NewCreateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 		Example: clusterCreateExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.CreateOptions.Complete())
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CreateOptions.Complete())
+			if o.ListStorageClasses {
+				util.CheckErr(o.printStorageClasses())
+				return
+			}
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -299,20 +329,23 @@ func NewCreateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 	cmd.Flags().StringArrayVar(&o.LabelStrs, "label", []string{}, "Set labels for cluster resources")
 	cmd.Flags().Float64Var(&o.CPUOversellRatio, "cpu-oversell-ratio", 1, "Set oversell ratio of CPU, set to 10 means 10 times oversell")
 	cmd.Flags().Float64Var(&o.MemoryOversellRatio, "memory-oversell-ratio", 1, "Set oversell ratio of memory, set to 10 means 10 times oversell")
+	cmd.Flags().StringVar(&o.StorageClass, "storage-class", "", "Set the storage class of the volumes without a storage class specified by --set or --pvc, use the default storage class if not specified")
+	cmd.Flags().BoolVar(&o.ListStorageClasses, "list-storage-classes", false, "List the storage classes with their access modes and volume snapshot support, then exit")
+	cmd.Flags().BoolVar(&o.MonitoringEnabled, "monitoring-enabled", false, "Enable the exporters of the components and create PodMonitors to scrape the metrics, the Prometheus operator is required")
+	cmd.Flags().StringToStringVar(&o.Exporters, "exporter", nil, "Enable or disable the exporter of the component, it overrides --monitoring-enabled (e.g. --exporter mysql=true,proxy=false)")
 
 	cmd.Flags().StringVar(&o.Backup, "backup", "", "Set a source backup to restore data")
+	cmd.Flags().StringVar(&o.FromCluster, "from-cluster", "", "Copy the spec of an existing cluster in the format of NAMESPACE/NAME, the flags such as --set, --pvc and --termination-policy override the copied spec")
 	cmd.Flags().StringVar(&o.RestoreTime, "restore-to-time", "", "Set a time for point in time recovery")
 	cmd.Flags().StringVar(&o.VolumeRestorePolicy, "volume-restore-policy", "Parallel", "the volume claim restore policy, supported values: [Serial, Parallel]")
 	cmd.Flags().BoolVar(&o.RBACEnabled, "rbac-enabled", false, "Specify whether rbac resources will be created by kbcli, otherwise KubeBlocks server will try to create rbac resources")
 	cmd.PersistentFlags().BoolVar(&o.EditBeforeCreate, "edit", o.EditBeforeCreate, "Edit the API resource before creating")
-	cmd.PersistentFlags().StringVar(&o.DryRun, "dry-run", "none", `Must be "client", or "server". If with client strategy, only print the object that would be sent, and no data is actually sent. If with server strategy, submit the server-side request, but no data is persistent.`)
-	cmd.PersistentFlags().Lookup("dry-run").NoOptDefVal = "unchanged"
 
 	// add updatable flags
 	o.UpdatableFlags.addFlags(cmd)
 
-	// add print flags
-	printer.AddOutputFlagForCreate(cmd, &o.Format, true)
+	// add dry-run and print flags
+	o.AddDryRunFlags(cmd, true)
 
 	// register flag completion func
 	registerFlagCompletionFunc(cmd, f)
@@ -447,9 +480,27 @@ func (o *CreateOptions) Validate() error {
 		return fmt.Errorf("cluster name should be less than 16 characters")
 	}
 
+	// the service account, role and binding created by --rbac-enabled are not exported
+	if o.RBACEnabled && action.ExportEnabled() {
+		if dryRun, err := o.GetDryRunStrategy(); err == nil && dryRun == action.DryRunNone {
+			return fmt.Errorf("--rbac-enabled is not supported with --export-to-dir, the service account, role and binding of the cluster can not be exported")
+		}
+	}
+
+	if o.monitoringRequested() {
+		return validatePrometheusOperator(o.Dynamic)
+	}
 	return nil
 }
 
+// Run creates the cluster and the PodMonitors of the monitored components
+func (o *CreateOptions) Run() error {
+	if err := o.CreateOptions.Run(); err != nil {
+		return err
+	}
+	return o.createPodMonitors()
+}
+
 func (o *CreateOptions) Complete() error {
 	var (
 		compByte         []byte
@@ -478,6 +529,9 @@ func (o *CreateOptions) Complete() error {
 	if err = fillClusterInfoFromBackup(o, &cls); err != nil {
 		return err
 	}
+	if err = fillClusterInfoFromCluster(o, &cls); err != nil {
+		return err
+	}
 	if nil != cls && cls.Spec.ComponentSpecs != nil {
 		clusterCompSpecs = cls.Spec.ComponentSpecs
 	}
@@ -532,6 +586,9 @@ func (o *CreateOptions) Complete() error {
 	if err = setBackup(o, components); err != nil {
 		return err
 	}
+	if err = o.buildMonitor(components); err != nil {
+		return err
+	}
 	o.ComponentSpecs = components
 
 	// TolerationsRaw looks like `["key=engineType,value=mongo,operator=Equal,effect=NoSchedule"]` after parsing by cmd
@@ -544,7 +601,51 @@ func (o *CreateOptions) Complete() error {
 	}
 
 	// validate default storageClassName
-	return validateStorageClass(o.Dynamic, o.ComponentSpecs)
+	requireSnapshot, err := o.requireVolumeSnapshot()
+	if err != nil {
+		return err
+	}
+	return validateStorageClass(o.Dynamic, o.ComponentSpecs, requireSnapshot)
+}
+
+// requireVolumeSnapshot returns true if the automated backup is enabled with a backup method taking volume snapshots
+func (o *CreateOptions) requireVolumeSnapshot() (bool, error) {
+	if o.BackupConfig == nil || !boolptr.IsSetToTrue(o.BackupConfig.Enabled) {
+		return false, nil
+	}
+	defaultBackupMethod, backupMethodsMap, err := getBackupMethodsFromBackupPolicyTemplates(o.Dynamic, o.ClusterDefRef)
+	if err != nil {
+		return false, err
+	}
+	if o.BackupConfig.Method == "" {
+		return backupMethodsMap[defaultBackupMethod], nil
+	}
+	return backupMethodsMap[o.BackupConfig.Method], nil
+}
+
+// printStorageClasses prints the storage classes and their capabilities
+func (o *CreateOptions) printStorageClasses() error {
+	storageClasses, _, err := getStorageClasses(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	drivers, _, err := util.GetVolumeSnapshotClassDrivers(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("NAME", "PROVISIONER", "DEFAULT", "ACCESS-MODES", "VOLUME-SNAPSHOT", "VOLUME-EXPANSION")
+	for _, sc := range storageClasses {
+		var modes []string
+		for _, m := range util.StorageClassAccessModes(sc) {
+			modes = append(modes, string(m))
+		}
+		tbl.AddRow(sc.Name, sc.Provisioner, util.IsDefaultStorageClass(sc.Annotations), strings.Join(modes, ","),
+			drivers[sc.Provisioner], sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion)
+	}
+	tbl.SortBy(1)
+	tbl.Print()
+	return nil
 }
 
 func (o *CreateOptions) CleanUp() error {
@@ -636,6 +737,17 @@ func (o *CreateOptions) buildComponents(clusterCompSpecs []appsv1alpha1.ClusterC
 		compSpecs = rebuildCompStorage(storages, compSpecs)
 	}
 
+	// set the storage class of the volumes without a storage class
+	if o.StorageClass != "" {
+		for _, compSpec := range compSpecs {
+			for i := range compSpec.VolumeClaimTemplates {
+				if compSpec.VolumeClaimTemplates[i].Spec.StorageClassName == nil {
+					compSpec.VolumeClaimTemplates[i].Spec.StorageClassName = &o.StorageClass
+				}
+			}
+		}
+	}
+
 	// build service reference if --service-reference not empty
 	if len(o.ServiceRef) != 0 {
 		compSpecs, err = buildServiceRefs(o.ServiceRef, cd, compSpecs)
@@ -871,13 +983,12 @@ func MultipleSourceComponents(fileName string, in io.Reader) ([]byte, error) {
 }
 
 func registerFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
-	util.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"cluster-definition",
+	util.RegisterFlagCompletionFunc(cmd, "cluster-definition",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.CompGetResource(f, util.GVRToString(types.ClusterDefGVR()), toComplete), cobra.ShellCompDirectiveNoFileComp
-		}))
-	util.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"cluster-version",
+		})
+	util.RegisterFlagCompletionFunc(cmd, "storage-class", util.ResourceNameCompletionFunc(f, types.StorageClassGVR()))
+	util.RegisterFlagCompletionFunc(cmd, "cluster-version",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			var clusterVersion []string
 			clusterDefinition, err := cmd.Flags().GetString("cluster-definition")
@@ -888,7 +999,7 @@ func registerFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
 				clusterVersion = util.CompGetResourceWithLabels(f, cmd, util.GVRToString(types.ClusterVersionGVR()), []string{label}, toComplete)
 			}
 			return clusterVersion, cobra.ShellCompDirectiveNoFileComp
-		}))
+		})
 
 	var formatsWithDesc = map[string]string{
 		"JSON": "Output result in JSON format",
@@ -1326,12 +1437,30 @@ func (f *UpdatableFlags) addFlags(cmd *cobra.Command) {
 }
 
 // validateStorageClass checks the existence of declared StorageClasses in volume claim templates,
-// if not set, check the existence of the default StorageClasses
-func validateStorageClass(dynamic dynamic.Interface, components []map[string]interface{}) error {
+// if not set, check the existence of the default StorageClasses. The StorageClasses must support the
+// access modes of the volume claim templates, and support volume snapshot if requireSnapshot is true.
+func validateStorageClass(dynamic dynamic.Interface, components []map[string]interface{}, requireSnapshot bool) error {
 	existedStorageClasses, existedDefault, err := getStorageClasses(dynamic)
 	if err != nil {
 		return err
 	}
+	var defaultStorageClass *storagev1.StorageClass
+	for _, sc := range existedStorageClasses {
+		if util.IsDefaultStorageClass(sc.Annotations) {
+			defaultStorageClass = sc
+			break
+		}
+	}
+	var snapshotDrivers map[string]bool
+	if requireSnapshot {
+		var installed bool
+		if snapshotDrivers, installed, err = util.GetVolumeSnapshotClassDrivers(dynamic); err != nil {
+			return err
+		}
+		if !installed {
+			return fmt.Errorf("the volume snapshot CRDs are not installed for the volume snapshot backup, %s", util.VolumeSnapshotInstallHint)
+		}
+	}
 	for _, comp := range components {
 		compObj := appsv1alpha1.ClusterComponentSpec{}
 		err = runtime.DefaultUnstructuredConverter.FromUnstructured(comp, &compObj)
@@ -1339,15 +1468,35 @@ func validateStorageClass(dynamic dynamic.Interface, components []map[string]int
 			return err
 		}
 		for _, vct := range compObj.VolumeClaimTemplates {
+			var sc *storagev1.StorageClass
 			name := vct.Spec.StorageClassName
 			if name != nil {
 				// validate the specified StorageClass whether exist
-				if _, ok := existedStorageClasses[*name]; !ok {
+				var ok bool
+				if sc, ok = existedStorageClasses[*name]; !ok {
 					return fmt.Errorf("failed to find the specified storageClass \"%s\"", *name)
 				}
 			} else if !existedDefault {
 				// validate the default StorageClass
 				return fmt.Errorf("failed to find the default storageClass, use '--set storageClass=NAME' to set it")
+			} else {
+				// the default StorageClass may be only set in the kubeblocks-manager-config for cloud K8S,
+				// its capabilities can not be checked
+				sc = defaultStorageClass
+			}
+			if sc == nil {
+				continue
+			}
+			supportedModes := util.StorageClassAccessModes(sc)
+			for _, mode := range vct.Spec.AccessModes {
+				if !slices.Contains(supportedModes, mode) {
+					return fmt.Errorf("storageClass \"%s\" of component %s does not support the access mode %s, "+
+						"run \"kbcli cluster create --list-storage-classes\" to show the capabilities of the storage classes", sc.Name, compObj.Name, mode)
+				}
+			}
+			if requireSnapshot && !snapshotDrivers[sc.Provisioner] {
+				return fmt.Errorf("storageClass \"%s\" of component %s does not support volume snapshot required by the backup method, "+
+					"create a VolumeSnapshotClass with the driver %s, or use a backup method without volume snapshot by --backup-method", sc.Name, compObj.Name, sc.Provisioner)
 			}
 		}
 	}
@@ -1355,18 +1504,21 @@ func validateStorageClass(dynamic dynamic.Interface, components []map[string]int
 }
 
 // getStorageClasses returns all StorageClasses in K8S and return true if the cluster have a default StorageClasses
-func getStorageClasses(dynamic dynamic.Interface) (map[string]struct{}, bool, error) {
+func getStorageClasses(dynamic dynamic.Interface) (map[string]*storagev1.StorageClass, bool, error) {
 	gvr := types.StorageClassGVR()
-	allStorageClasses := make(map[string]struct{})
+	allStorageClasses := make(map[string]*storagev1.StorageClass)
 	existedDefault := false
 	list, err := dynamic.Resource(gvr).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, false, err
 	}
 	for _, item := range list.Items {
-		allStorageClasses[item.GetName()] = struct{}{}
-		annotations := item.GetAnnotations()
-		if !existedDefault && annotations != nil && (annotations[storage.IsDefaultStorageClassAnnotation] == TrueValue || annotations[storage.BetaIsDefaultStorageClassAnnotation] == TrueValue) {
+		sc := &storagev1.StorageClass{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, sc); err != nil {
+			return nil, false, err
+		}
+		allStorageClasses[sc.Name] = sc
+		if !existedDefault && util.IsDefaultStorageClass(sc.Annotations) {
 			existedDefault = true
 		}
 	}
@@ -1525,7 +1677,7 @@ func (o *CreateOptions) buildBackupConfig(cls *appsv1alpha1.Cluster) error {
 
 // get backup methods from backup policy template
 // if method's snapshotVolumes is true, use the method as default method
-func getBackupMethodsFromBackupPolicyTemplates(dynamic dynamic.Interface, clusterDefRef string) (string, map[string]struct{}, error) {
+func getBackupMethodsFromBackupPolicyTemplates(dynamic dynamic.Interface, clusterDefRef string) (string, map[string]bool, error) {
 	var backupPolicyTemplates []appsv1alpha1.BackupPolicyTemplate
 	var defaultBackupPolicyTemplate appsv1alpha1.BackupPolicyTemplate
 
@@ -1559,13 +1711,14 @@ func getBackupMethodsFromBackupPolicyTemplates(dynamic dynamic.Interface, cluste
 	}
 
 	var defaultBackupMethod string
-	var backupMethodsMap = make(map[string]struct{})
+	// the value is true if the backup method takes volume snapshots
+	var backupMethodsMap = make(map[string]bool)
 	for _, policy := range defaultBackupPolicyTemplate.Spec.BackupPolicies {
 		for _, method := range policy.BackupMethods {
 			if boolptr.IsSetToTrue(method.SnapshotVolumes) {
 				defaultBackupMethod = method.Name
 			}
-			backupMethodsMap[method.Name] = struct{}{}
+			backupMethodsMap[method.Name] = boolptr.IsSetToTrue(method.SnapshotVolumes)
 		}
 	}
 	if defaultBackupMethod == "" {
@@ -0,0 +1,142 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// parseSourceCluster parses the source cluster of --from-cluster in the format of NAMESPACE/NAME,
+// the namespace of the new cluster is used if the namespace is omitted.
+func parseSourceCluster(value, namespace string) (string, string, error) {
+	parts := strings.Split(value, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return namespace, parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	}
+	return "", "", clierrors.NewValidation("invalid --from-cluster %s, should be NAMESPACE/NAME or NAME", value)
+}
+
+// fillClusterInfoFromCluster copies the spec of the source cluster specified by --from-cluster,
+// the components, termination policy, affinity and tolerations are copied unless they are
+// overridden by the flags.
+func fillClusterInfoFromCluster(o *CreateOptions, cls **appsv1alpha1.Cluster) error {
+	if o.FromCluster == "" {
+		return nil
+	}
+	if o.Backup != "" || o.SetFile != "" {
+		return clierrors.NewValidation("--from-cluster can not be specified with --backup or --set-file")
+	}
+	namespace, name, err := parseSourceCluster(o.FromCluster, o.Namespace)
+	if err != nil {
+		return err
+	}
+	source := &appsv1alpha1.Cluster{}
+	if err = cluster.GetK8SClientObject(o.Dynamic, source, types.ClusterGVR(), namespace, name); err != nil {
+		return err
+	}
+	if o.ClusterDefRef != "" && o.ClusterDefRef != source.Spec.ClusterDefRef {
+		return clierrors.NewValidation("specified cluster definition does not match the source cluster(expect: %s, actual: %s)",
+			source.Spec.ClusterDefRef, o.ClusterDefRef)
+	}
+
+	copied := copyClusterSpec(source, o.Namespace)
+	o.ClusterDefRef = copied.Spec.ClusterDefRef
+	if !o.flagChanged("termination-policy") && copied.Spec.TerminationPolicy != "" {
+		o.TerminationPolicy = string(copied.Spec.TerminationPolicy)
+	}
+	if affinity := copied.Spec.Affinity; affinity != nil {
+		if !o.flagChanged("pod-anti-affinity") && affinity.PodAntiAffinity != "" {
+			o.PodAntiAffinity = string(affinity.PodAntiAffinity)
+		}
+		if !o.flagChanged("topology-keys") {
+			o.TopologyKeys = affinity.TopologyKeys
+		}
+		if !o.flagChanged("node-labels") {
+			o.NodeLabels = affinity.NodeLabels
+		}
+		if !o.flagChanged("tenancy") && affinity.Tenancy != "" {
+			o.Tenancy = string(affinity.Tenancy)
+		}
+	}
+	for _, toleration := range copied.Spec.Tolerations {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&toleration)
+		if err != nil {
+			return err
+		}
+		o.Tolerations = append(o.Tolerations, obj)
+	}
+	*cls = copied
+	return nil
+}
+
+// copyClusterSpec copies the spec and labels of the source cluster without the status and the
+// fields identifying the source cluster, the service references without a namespace still refer
+// to the services in the namespace of the source cluster.
+func copyClusterSpec(source *appsv1alpha1.Cluster, namespace string) *appsv1alpha1.Cluster {
+	c := &appsv1alpha1.Cluster{Spec: *source.Spec.DeepCopy()}
+	for k, v := range source.Labels {
+		// the labels set by KubeBlocks and kbcli belong to the source cluster
+		if strings.HasPrefix(k, "app.kubernetes.io/") || strings.Contains(k, "kubeblocks.io/") {
+			continue
+		}
+		if c.Labels == nil {
+			c.Labels = map[string]string{}
+		}
+		c.Labels[k] = v
+	}
+	for i := range c.Spec.ComponentSpecs {
+		comp := &c.Spec.ComponentSpecs[i]
+		// the service account is created for the new cluster
+		comp.ServiceAccountName = ""
+		if source.Namespace == namespace {
+			continue
+		}
+		for j := range comp.ServiceRefs {
+			if comp.ServiceRefs[j].Namespace == "" {
+				comp.ServiceRefs[j].Namespace = source.Namespace
+			}
+		}
+		// the user provided certificates are in the namespace of the source cluster
+		if comp.Issuer != nil && comp.Issuer.Name == appsv1alpha1.IssuerUserProvided {
+			comp.Issuer = &appsv1alpha1.Issuer{Name: appsv1alpha1.IssuerKubeBlocks}
+		}
+	}
+	return c
+}
+
+// flagChanged returns true if the flag is specified by the user
+func (o *CreateOptions) flagChanged(name string) bool {
+	if o.Cmd == nil {
+		return false
+	}
+	flag := o.Cmd.Flags().Lookup(name)
+	return flag != nil && flag.Changed
+}
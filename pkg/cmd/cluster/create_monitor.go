@@ -0,0 +1,196 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// podMonitorCRDName is the CRD installed by the Prometheus operator to scrape the metrics of pods
+var podMonitorCRDName = types.ResourcePodMonitors + "." + types.MonitoringAPIGroup
+
+// monitorTarget is a component whose exporter is scraped by a PodMonitor
+type monitorTarget struct {
+	component string
+	exporter  *appsv1alpha1.ExporterConfig
+}
+
+// monitoringRequested returns true if the monitoring is enabled by --monitoring-enabled or --exporter
+func (o *CreateOptions) monitoringRequested() bool {
+	return o.MonitoringEnabled || len(o.Exporters) > 0
+}
+
+// buildMonitor sets the monitor field of the components and collects the components to create the PodMonitors.
+// The component is monitored if it's enabled by --exporter, otherwise it follows the --monitoring-enabled, the
+// components without an exporter are skipped unless they are enabled explicitly.
+func (o *CreateOptions) buildMonitor(components []map[string]interface{}) error {
+	o.monitorTargets = nil
+	if !o.monitoringRequested() {
+		return nil
+	}
+
+	exporters := map[string]bool{}
+	for name, val := range o.Exporters {
+		enabled, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of the exporter of component %s, should be true or false", val, name)
+		}
+		exporters[name] = enabled
+	}
+
+	cd, err := cluster.GetClusterDefByName(o.Dynamic, o.ClusterDefRef)
+	if err != nil {
+		return err
+	}
+	for _, comp := range components {
+		name, _ := comp["name"].(string)
+		compDefRef, _ := comp["componentDefRef"].(string)
+		enabled, explicit := exporters[name]
+		delete(exporters, name)
+		if !explicit {
+			if !o.MonitoringEnabled {
+				continue
+			}
+			enabled = true
+		}
+		if !enabled {
+			delete(comp, monitorKey)
+			continue
+		}
+
+		compDef := cd.GetComponentDefByName(compDefRef)
+		if compDef == nil || compDef.Monitor == nil || compDef.Monitor.Exporter == nil {
+			if explicit {
+				return fmt.Errorf("component %s does not provide a metrics exporter", name)
+			}
+			continue
+		}
+		comp[monitorKey] = true
+		o.monitorTargets = append(o.monitorTargets, monitorTarget{component: name, exporter: compDef.Monitor.Exporter})
+	}
+
+	if len(exporters) > 0 {
+		var names []string
+		for name := range exporters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("the components %v specified by --exporter are not found in the cluster", names)
+	}
+	return nil
+}
+
+// validatePrometheusOperator checks the Prometheus operator is installed, the PodMonitors can not be created without it
+func validatePrometheusOperator(dynamic dynamic.Interface) error {
+	_, err := dynamic.Resource(types.CustomResourceDefinitionGVR()).Get(context.TODO(), podMonitorCRDName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("the Prometheus operator is not installed, CRD %s is not found, install it or disable the monitoring", podMonitorCRDName)
+	}
+	return err
+}
+
+// buildPodMonitor builds a PodMonitor to scrape the metrics of the exporter of the component
+func (o *CreateOptions) buildPodMonitor(target monitorTarget, owner *unstructured.Unstructured) *unstructured.Unstructured {
+	labels := buildResourceLabels(o.Name)
+	labels[constant.KBAppComponentLabelKey] = target.component
+
+	endpoint := map[string]interface{}{}
+	if target.exporter.ScrapePort.Type == intstr.String {
+		endpoint["port"] = target.exporter.ScrapePort.StrVal
+	} else {
+		endpoint["targetPort"] = int64(target.exporter.ScrapePort.IntVal)
+	}
+	if target.exporter.ScrapePath != "" {
+		endpoint["path"] = target.exporter.ScrapePath
+	}
+	if o.MonitoringInterval != 0 {
+		endpoint["interval"] = fmt.Sprintf("%ds", o.MonitoringInterval)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": types.MonitoringAPIGroup + "/" + types.MonitoringAPIVersion,
+		"kind":       types.KindPodMonitor,
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					constant.AppInstanceLabelKey:    o.Name,
+					constant.KBAppComponentLabelKey: target.component,
+				},
+			},
+			"podMetricsEndpoints": []interface{}{endpoint},
+		},
+	}}
+	obj.SetName(fmt.Sprintf("%s-%s", o.Name, target.component))
+	obj.SetNamespace(o.Namespace)
+	obj.SetLabels(labels)
+	// the PodMonitors are deleted with the cluster
+	obj.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: owner.GetAPIVersion(),
+		Kind:       owner.GetKind(),
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}})
+	return obj
+}
+
+// createPodMonitors creates the PodMonitors of the monitored components after the cluster is created
+func (o *CreateOptions) createPodMonitors() error {
+	if len(o.monitorTargets) == 0 || action.ExportEnabled() {
+		return nil
+	}
+	dryRun, err := o.GetDryRunStrategy()
+	if err != nil || dryRun != action.DryRunNone {
+		return err
+	}
+
+	ctx := context.TODO()
+	owner, err := o.Dynamic.Resource(types.ClusterGVR()).Namespace(o.Namespace).Get(ctx, o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	for _, target := range o.monitorTargets {
+		podMonitor := o.buildPodMonitor(target, owner)
+		klog.V(1).Infof("create PodMonitor %s for component %s", podMonitor.GetName(), target.component)
+		if _, err = o.Dynamic.Resource(types.PodMonitorGVR()).Namespace(o.Namespace).Create(ctx, podMonitor, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create PodMonitor of component %s: %v", target.component, err)
+		}
+	}
+	return nil
+}
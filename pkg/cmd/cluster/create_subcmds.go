@@ -28,7 +28,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/cluster"
@@ -85,10 +84,10 @@ func buildCreateSubCmds(createOptions *action.CreateOptions) []*cobra.Command {
 			Example: buildCreateSubCmdsExamples(t),
 			Run: func(cmd *cobra.Command, args []string) {
 				o.Args = args
-				cmdutil.CheckErr(o.CreateOptions.Complete())
-				cmdutil.CheckErr(o.complete(cmd))
-				cmdutil.CheckErr(o.validate())
-				cmdutil.CheckErr(o.Run())
+				util.CheckErr(o.CreateOptions.Complete())
+				util.CheckErr(o.complete(cmd))
+				util.CheckErr(o.validate())
+				util.CheckErr(o.Run())
 			},
 		}
 
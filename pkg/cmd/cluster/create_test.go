@@ -37,6 +37,7 @@ import (
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/class"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 
@@ -446,6 +447,48 @@ var _ = Describe("create", func() {
 		Expect(fillClusterInfoFromBackup(o, &cluster)).Should(HaveOccurred())
 	})
 
+	It("fill cluster from another cluster", func() {
+		source := testing.FakeCluster(testing.ClusterName, "prod")
+		source.Labels = map[string]string{"team": "dba", constant.AppInstanceLabelKey: source.Name}
+		source.Spec.TerminationPolicy = appsv1alpha1.DoNotTerminate
+		source.Spec.Tolerations = []corev1.Toleration{{Key: "engineType", Value: "mysql", Effect: corev1.TaintEffectNoSchedule}}
+		source.Spec.ComponentSpecs[0].ServiceAccountName = "kb-" + source.Name
+		source.Spec.ComponentSpecs[0].ServiceRefs = []appsv1alpha1.ServiceRef{{Name: "zookeeper", Cluster: "zk"}}
+
+		tf := testing.NewTestFactory(testing.Namespace)
+		defer tf.Cleanup()
+		cmd := NewCreateCmd(tf, genericiooptions.NewTestIOStreamsDiscard())
+		o := &CreateOptions{Cmd: cmd}
+		o.Dynamic = testing.FakeDynamicClient(source)
+		o.Namespace = testing.Namespace
+		o.FromCluster = "prod/" + source.Name
+		Expect(cmd.Flags().Set("tenancy", "DedicatedNode")).Should(Succeed())
+
+		var cls *appsv1alpha1.Cluster
+		Expect(fillClusterInfoFromCluster(o, &cls)).Should(Succeed())
+		Expect(o.ClusterDefRef).Should(Equal(testing.ClusterDefName))
+		Expect(o.TerminationPolicy).Should(Equal(string(appsv1alpha1.DoNotTerminate)))
+		Expect(o.Tolerations).Should(HaveLen(1))
+		Expect(cls.Labels).Should(Equal(map[string]string{"team": "dba"}))
+		Expect(cls.Name).Should(BeEmpty())
+		Expect(cls.Spec.ComponentSpecs[0].ServiceAccountName).Should(BeEmpty())
+		Expect(cls.Spec.ComponentSpecs[0].ServiceRefs[0].Namespace).Should(Equal("prod"))
+
+		By("the source cluster in the same namespace")
+		_, _, err := parseSourceCluster("mycluster", testing.Namespace)
+		Expect(err).Should(Succeed())
+		_, _, err = parseSourceCluster("prod/", testing.Namespace)
+		Expect(err).Should(HaveOccurred())
+
+		By("the source cluster is not found")
+		o.FromCluster = source.Name
+		Expect(fillClusterInfoFromCluster(o, &cls)).Should(HaveOccurred())
+
+		By("can not be specified with --backup")
+		o.Backup = "backup"
+		Expect(fillClusterInfoFromCluster(o, &cls)).Should(MatchError(ContainSubstring("--from-cluster can not be specified")))
+	})
+
 	It("test build backup config", func() {
 		backupPolicyTemplate := testing.FakeBackupPolicyTemplate("backupPolicyTemplate-test", testing.ClusterDefName)
 		backupPolicy := appsv1alpha1.BackupPolicy{
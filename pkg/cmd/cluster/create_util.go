@@ -128,6 +128,7 @@ func registerFlagCompFunc(cmd *cobra.Command, f cmdutil.Factory, c *cluster.Char
 			}
 			return versions, cobra.ShellCompDirectiveNoFileComp
 		})
+	util.RegisterFlagCompletionFunc(cmd, "storage-class", util.ResourceNameCompletionFunc(f, types.StorageClassGVR()))
 }
 
 // buildCreateSubCmdsExamples builds the creation examples for the specified clusterType type.
@@ -23,41 +23,51 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/cmd/get"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/cmd/util/editor"
 	"k8s.io/kubectl/pkg/util/templates"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/printer/progress"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
 )
@@ -79,6 +89,9 @@ var (
 
 	    # using short cmd to edit backup policy
         kbcli cluster edit-bp <backup-policy-name>
+
+		# edit the full spec of the backup policy in YAML
+		kbcli cluster edit-backup-policy <backup-policy-name> --mode yaml
 	`)
 	createBackupExample = templates.Examples(`
 		# Create a backup for the cluster, use the default backup policy and volume snapshot backup method
@@ -92,6 +105,15 @@ var (
 
 		# create a backup from a parent backup
 		kbcli cluster backup mycluster --parent-backup parent-backup-name
+
+		# create an incremental backup from the latest completed backup of the same method
+		kbcli cluster backup mycluster --method xtrabackup-inc --incremental
+
+		# create a backup and wait for it to be completed
+		kbcli cluster backup mycluster --wait --timeout 30m
+
+		# create a backup whose workloads are limited to 1 CPU, 1Gi memory and 50Mi bytes per second
+		kbcli cluster backup mycluster --cpu-limit 1 --memory-limit 1Gi --bandwidth 50Mi
 	`)
 	listBackupExample = templates.Examples(`
 		# list all backups
@@ -104,10 +126,25 @@ var (
 	createRestoreExample = templates.Examples(`
 		# restore a new cluster from a backup
 		kbcli cluster restore new-cluster-name --backup backup-name
+
+		# restore a new cluster from a backup and wait for the cluster to be running
+		kbcli cluster restore new-cluster-name --backup backup-name --wait
+
+		# restore a new cluster from a backup with 2 CPU, 4Gi memory and 3 replicas of component mysql, and the volumes in storage class gp3
+		kbcli cluster restore new-cluster-name --backup backup-name --components mysql --cpu 2 --memory 4Gi --replicas 3 --storage-class gp3
+
+		# restore a new cluster from a backup with the restore workloads limited to 1 CPU and 50Mi bytes per second
+		kbcli cluster restore new-cluster-name --backup backup-name --cpu-limit 1 --bandwidth 50Mi
 	`)
 	describeBackupExample = templates.Examples(`
 		# describe a backup
 		kbcli cluster describe-backup backup-default-mycluster-20230616190023
+
+		# describe multiple backups
+		kbcli cluster describe-backup backup-default-mycluster-20230616190023 mybackup2
+
+		# describe the backups of the cluster mycluster
+		kbcli cluster describe-backup -l app.kubernetes.io/instance=mycluster
 	`)
 	describeBackupPolicyExample = templates.Examples(`
 		# describe the default backup policy of the cluster
@@ -120,12 +157,26 @@ var (
 
 const TrueValue = "true"
 
+// defaultWaitTimeout is the default time to wait for the backup to be completed or the cluster to be restored
+const defaultWaitTimeout = 30 * time.Minute
+
 type CreateBackupOptions struct {
 	BackupSpec     appsv1alpha1.BackupSpec `json:"backupSpec"`
 	ClusterRef     string                  `json:"clusterRef"`
 	OpsType        string                  `json:"opsType"`
 	OpsRequestName string                  `json:"opsRequestName"`
 
+	// Wait waits for the backup to be completed after it is created
+	Wait    bool          `json:"-"`
+	Timeout time.Duration `json:"-"`
+
+	// Incremental uses the latest completed backup of the same method as the parent backup
+	Incremental bool `json:"-"`
+
+	// Limits are the resource limits and bandwidth of the backup workloads, the backup is created
+	// with a backup policy derived from the backup policy with the limits.
+	Limits workloadLimits `json:"-"`
+
 	action.CreateOptions `json:"-"`
 }
 
@@ -144,16 +195,29 @@ type DescribeBackupOptions struct {
 	Gvr   schema.GroupVersionResource
 	names []string
 
+	// LabelSelector selects the backups to describe if no names are specified
+	LabelSelector string
+	// Parallelism is the number of backups fetched concurrently
+	Parallelism int
+
 	genericiooptions.IOStreams
 }
 
+// backupDescribeInfo is the information of a backup fetched for describing.
+type backupDescribeInfo struct {
+	backup        *dpv1alpha1.Backup
+	failureReason string
+	events        *corev1.EventList
+	causes        []cluster.ProbableCause
+}
+
 func (o *CreateBackupOptions) CompleteBackup() error {
 	if err := o.Complete(); err != nil {
 		return err
 	}
 	// generate backupName
 	if len(o.BackupSpec.BackupName) == 0 {
-		o.BackupSpec.BackupName = strings.Join([]string{"backup", o.Namespace, o.Name, time.Now().Format("20060102150405")}, "-")
+		o.BackupSpec.BackupName = GenerateBackupName(o.Namespace, o.Name)
 	}
 
 	// set ops type, ops request name and clusterRef
@@ -164,9 +228,63 @@ func (o *CreateBackupOptions) CompleteBackup() error {
 	return o.CreateOptions.Complete()
 }
 
+// GenerateBackupName generates the default name of the backup for the cluster
+func GenerateBackupName(namespace, clusterName string) string {
+	return strings.Join([]string{"backup", namespace, clusterName, time.Now().Format("20060102150405")}, "-")
+}
+
+// AddWaitFlags adds the flags to wait for the backup to be completed
+func (o *CreateBackupOptions) AddWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the backup to be completed. It will wait for a --timeout period")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", defaultWaitTimeout, "Time to wait for the backup to be completed when --wait is true, such as --timeout=10m")
+}
+
+// Run creates the backup, and waits for it to be completed if --wait is true
+func (o *CreateBackupOptions) Run() error {
+	if o.Limits.enabled() {
+		if err := o.applyThrottledBackupPolicy(); err != nil {
+			return err
+		}
+	}
+	if err := o.CreateOptions.Run(); err != nil {
+		return err
+	}
+	if dryRun, _ := o.GetDryRunStrategy(); !o.Wait || dryRun != action.DryRunNone {
+		return nil
+	}
+	return waitBackupCompleted(o.Dynamic, o.Namespace, o.BackupSpec.BackupName, o.Timeout, o.Out)
+}
+
+// waitBackupCompleted waits for the backup to be completed, the backup is created by the
+// OpsRequest asynchronously, so it may not exist at the beginning.
+func waitBackupCompleted(dynamic dynamic.Interface, namespace, name string, timeout time.Duration, out io.Writer) error {
+	s := progress.NewSpinner(out, "Wait for backup %s to be completed", name)
+	defer s.Fail()
+	if err := wait.PollUntilContextTimeout(context.Background(), 5*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		backup := &dpv1alpha1.Backup{}
+		if err := util.GetResourceObjectFromGVR(types.BackupGVR(), client.ObjectKey{Namespace: namespace, Name: name}, dynamic, backup); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		switch backup.Status.Phase {
+		case dpv1alpha1.BackupPhaseCompleted:
+			return true, nil
+		case dpv1alpha1.BackupPhaseFailed:
+			return false, fmt.Errorf("backup %s failed: %s", name, backup.Status.FailureReason)
+		}
+		return false, nil
+	}); err != nil {
+		return err
+	}
+	s.Success()
+	return nil
+}
+
 func (o *CreateBackupOptions) Validate() error {
 	if o.Name == "" {
-		return fmt.Errorf("missing cluster name")
+		return clierrors.NewValidation("missing cluster name")
 	}
 
 	// if backup policy is not specified, use the default backup policy
@@ -187,16 +305,46 @@ func (o *CreateBackupOptions) Validate() error {
 	}
 
 	if o.BackupSpec.BackupMethod == "" {
-		return fmt.Errorf("backup method can not be empty, you can specify it by --method")
+		return clierrors.NewValidation("backup method can not be empty, you can specify it by --method")
+	}
+	var backupMethod *dpv1alpha1.BackupMethod
+	for i, m := range backupPolicy.Spec.BackupMethods {
+		if m.Name == o.BackupSpec.BackupMethod {
+			backupMethod = &backupPolicy.Spec.BackupMethods[i]
+			break
+		}
+	}
+	if backupMethod == nil {
+		return clierrors.NewValidation("backup method %s is not found in backup policy %s", o.BackupSpec.BackupMethod, backupPolicy.Name)
+	}
+	if boolptr.IsSetToTrue(backupMethod.SnapshotVolumes) {
+		if err = o.validateVolumeSnapshot(backupPolicy); err != nil {
+			return err
+		}
 	}
-	// TODO: check if pvc exists
 
 	// valid retention period
 	if o.BackupSpec.RetentionPeriod != "" {
 		_, err := dpv1alpha1.RetentionPeriod(o.BackupSpec.RetentionPeriod).ToDuration()
 		if err != nil {
-			return fmt.Errorf("invalid retention period, please refer to examples [1y, 1m, 1d, 1h, 1m] or combine them [1y1m1d1h1m]")
+			return clierrors.NewValidation("invalid retention period, please refer to examples [1y, 1m, 1d, 1h, 1m] or combine them [1y1m1d1h1m]")
+		}
+	}
+
+	if err = o.Limits.validate(); err != nil {
+		return err
+	}
+
+	if o.Incremental {
+		if o.BackupSpec.ParentBackupName != "" {
+			return clierrors.NewValidation("--incremental and --parent-backup can not be specified at the same time")
+		}
+		parent, err := o.findParentBackup()
+		if err != nil {
+			return err
 		}
+		o.BackupSpec.ParentBackupName = parent.Name
+		fmt.Fprintf(o.ErrOut, "Use backup %s completed at %s as the parent backup\n", parent.Name, util.TimeFormat(parent.Status.CompletionTimestamp))
 	}
 
 	// check if parent backup exists
@@ -210,10 +358,114 @@ func (o *CreateBackupOptions) Validate() error {
 			return err
 		}
 		if parentBackup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
-			return fmt.Errorf("parent backup %s is not completed", o.BackupSpec.ParentBackupName)
+			return clierrors.NewConflict("parent backup %s is not completed", o.BackupSpec.ParentBackupName)
 		}
 		if parentBackup.Labels[constant.AppInstanceLabelKey] != o.Name {
-			return fmt.Errorf("parent backup %s is not belong to cluster %s", o.BackupSpec.ParentBackupName, o.Name)
+			return clierrors.NewValidation("parent backup %s is not belong to cluster %s", o.BackupSpec.ParentBackupName, o.Name)
+		}
+	}
+	return nil
+}
+
+// findParentBackup finds the latest completed backup of the same backup policy and method in the
+// cluster as the parent of the incremental backup, the backups of a deleted cluster with the same
+// name are skipped.
+func (o *CreateBackupOptions) findParentBackup() (*dpv1alpha1.Backup, error) {
+	c, err := cluster.GetClusterByName(o.Dynamic, o.Name, o.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	backupList, err := o.Dynamic.Resource(types.BackupGVR()).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	backups, err := util.ConvertUnstructuredList[dpv1alpha1.Backup](backupList.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	var parent *dpv1alpha1.Backup
+	for i := range backups {
+		b := &backups[i]
+		if b.Status.Phase != dpv1alpha1.BackupPhaseCompleted || b.DeletionTimestamp != nil ||
+			b.Spec.BackupPolicyName != o.BackupSpec.BackupPolicyName || b.Spec.BackupMethod != o.BackupSpec.BackupMethod {
+			continue
+		}
+		if uid, ok := b.Labels[dptypes.ClusterUIDLabelKey]; ok && c.UID != "" && uid != string(c.UID) {
+			continue
+		}
+		if parent == nil || completionTime(b).After(completionTime(parent)) {
+			parent = b
+		}
+	}
+	if parent == nil {
+		return nil, clierrors.NewValidation("no completed backup of method %s in backup policy %s found as the parent of the incremental backup, "+
+			"create a full backup first by \"kbcli cluster backup %s --method %s --policy %s\"",
+			o.BackupSpec.BackupMethod, o.BackupSpec.BackupPolicyName, o.Name, o.BackupSpec.BackupMethod, o.BackupSpec.BackupPolicyName)
+	}
+	return parent, nil
+}
+
+// completionTime returns the completion time of the backup, or the creation time if it's not recorded
+func completionTime(backup *dpv1alpha1.Backup) time.Time {
+	if backup.Status.CompletionTimestamp != nil {
+		return backup.Status.CompletionTimestamp.Time
+	}
+	return backup.CreationTimestamp.Time
+}
+
+// validateVolumeSnapshot checks the volume snapshot CRDs are installed and there is a VolumeSnapshotClass for
+// the CSI driver of every PVC to back up, so the backup fails fast instead of being stuck.
+func (o *CreateBackupOptions) validateVolumeSnapshot(backupPolicy *dpv1alpha1.BackupPolicy) error {
+	drivers, installed, err := util.GetVolumeSnapshotClassDrivers(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return clierrors.NewValidation("the volume snapshot CRDs are not installed, %s", util.VolumeSnapshotInstallHint)
+	}
+	if o.Client == nil {
+		if o.Client, err = o.Factory.KubernetesClientSet(); err != nil {
+			return err
+		}
+	}
+
+	// the PVCs share the instance and component labels with the target pods
+	labels := map[string]string{constant.AppInstanceLabelKey: o.Name}
+	if backupPolicy.Spec.Target != nil && backupPolicy.Spec.Target.PodSelector != nil && backupPolicy.Spec.Target.PodSelector.LabelSelector != nil {
+		if comp, ok := backupPolicy.Spec.Target.PodSelector.MatchLabels[constant.KBAppComponentLabelKey]; ok {
+			labels[constant.KBAppComponentLabelKey] = comp
+		}
+	}
+	pvcs, err := o.Client.CoreV1().PersistentVolumeClaims(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: k8slabels.SelectorFromSet(labels).String(),
+	})
+	if err != nil {
+		return err
+	}
+	var defaultStorageClass *storagev1.StorageClass
+	for _, pvc := range pvcs.Items {
+		var sc *storagev1.StorageClass
+		if pvc.Spec.StorageClassName == nil {
+			if defaultStorageClass == nil {
+				if defaultStorageClass, err = util.GetDefaultStorageClass(o.Client); err != nil {
+					return err
+				}
+			}
+			sc = defaultStorageClass
+		} else if sc, err = o.Client.StorageV1().StorageClasses().Get(context.TODO(), *pvc.Spec.StorageClassName, metav1.GetOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return clierrors.NewValidation("storage class %s of PVC %s is not found", *pvc.Spec.StorageClassName, pvc.Name)
+			}
+			return err
+		}
+		if sc == nil {
+			return clierrors.NewValidation("PVC %s has no storage class and there is no default storage class", pvc.Name)
+		}
+		if !drivers[sc.Provisioner] {
+			return clierrors.NewValidation("no VolumeSnapshotClass found for the CSI driver %s of storage class %s used by PVC %s, "+
+				"create a VolumeSnapshotClass with the driver %s, or use a backup method without volume snapshot", sc.Provisioner, sc.Name, pvc.Name, sc.Provisioner)
 		}
 	}
 	return nil
@@ -247,7 +499,7 @@ func (o *CreateBackupOptions) getDefaultBackupPolicy() (string, error) {
 		return "", err
 	}
 	if len(objs.Items) == 0 {
-		return "", fmt.Errorf(`not found any backup policy for cluster "%s"`, o.Name)
+		return "", clierrors.NewNotFoundf(`not found any backup policy for cluster "%s"`, o.Name)
 	}
 	var defaultBackupPolicies []unstructured.Unstructured
 	for _, obj := range objs.Items {
@@ -256,10 +508,10 @@ func (o *CreateBackupOptions) getDefaultBackupPolicy() (string, error) {
 		}
 	}
 	if len(defaultBackupPolicies) == 0 {
-		return "", fmt.Errorf(`not found any default backup policy for cluster "%s"`, o.Name)
+		return "", clierrors.NewNotFoundf(`not found any default backup policy for cluster "%s"`, o.Name)
 	}
 	if len(defaultBackupPolicies) > 1 {
-		return "", fmt.Errorf(`cluster "%s" has multiple default backup policies`, o.Name)
+		return "", clierrors.NewConflict(`cluster "%s" has multiple default backup policies`, o.Name)
 	}
 	return defaultBackupPolicies[0].GetName(), nil
 }
@@ -291,9 +543,9 @@ func NewCreateBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.CompleteBackup())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CompleteBackup())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -303,52 +555,99 @@ func NewCreateBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *
 	cmd.Flags().StringVar(&o.BackupSpec.DeletionPolicy, "deletion-policy", "Delete", "Deletion policy for backup, determine whether the backup content in backup repo will be deleted after the backup is deleted, supported values: [Delete, Retain]")
 	cmd.Flags().StringVar(&o.BackupSpec.RetentionPeriod, "retention-period", "", "Retention period for backup, supported values: [1y, 1mo, 1d, 1h, 1m] or combine them [1y1mo1d1h1m], if not specified, the backup will not be automatically deleted, you need to manually delete it.")
 	cmd.Flags().StringVar(&o.BackupSpec.ParentBackupName, "parent-backup", "", "Parent backup name, used for incremental backup")
+	cmd.Flags().BoolVar(&o.Incremental, "incremental", false, "Create an incremental backup whose parent is the latest completed backup of the same backup policy and method")
+	o.Limits.addFlags(cmd, "backup")
+	o.AddDryRunFlags(cmd, false)
+	o.AddWaitFlags(cmd)
 	// register backup flag completion func
 	o.RegisterBackupFlagCompletionFunc(cmd, f)
 	return cmd
 }
-func (o *CreateBackupOptions) RegisterBackupFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
-	getClusterName := func(cmd *cobra.Command, args []string) string {
-		clusterName, _ := cmd.Flags().GetString("cluster")
-		if clusterName != "" {
-			return clusterName
-		}
-		if len(args) > 0 {
-			return args[0]
-		}
-		return ""
+
+// BackupCluster creates a backup of the cluster with its default backup policy and returns
+// the backup name, it is used by the callers that are not a command, such as the dashboard TUI.
+func BackupCluster(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) (string, error) {
+	o := &CreateBackupOptions{
+		BackupSpec: appsv1alpha1.BackupSpec{DeletionPolicy: "Delete"},
+		CreateOptions: action.CreateOptions{
+			IOStreams:       streams,
+			Factory:         f,
+			GVR:             types.OpsGVR(),
+			CueTemplateName: "opsrequest_template.cue",
+			Quiet:           true,
+		},
 	}
-	util.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"deletion-policy",
-		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			return []string{string(dpv1alpha1.BackupDeletionPolicyRetain), string(dpv1alpha1.BackupDeletionPolicyDelete)}, cobra.ShellCompDirectiveNoFileComp
-		}))
+	o.CreateOptions.Options = o
+	o.Args = []string{name}
+	if err := o.CreateOptions.Complete(); err != nil {
+		return "", err
+	}
+	o.Namespace = namespace
+	o.BackupSpec.BackupName = strings.Join([]string{"backup", o.Namespace, o.Name, time.Now().Format("20060102150405")}, "-")
+	o.OpsType = string(appsv1alpha1.BackupType)
+	o.OpsRequestName = o.BackupSpec.BackupName
+	o.ClusterRef = o.Name
+	if err := o.completeDefaultBackupPolicy(); err != nil {
+		return "", err
+	}
+	if err := o.completeDefaultBackupMethod(); err != nil {
+		return "", err
+	}
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	if err := o.Run(); err != nil {
+		return "", err
+	}
+	return o.BackupSpec.BackupName, nil
+}
 
-	util.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"policy",
-		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			label := fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, getClusterName(cmd, args))
-			return util.CompGetResourceWithLabels(f, cmd, util.GVRToString(types.BackupPolicyGVR()), []string{label}, toComplete), cobra.ShellCompDirectiveNoFileComp
-		}))
+// completeDefaultBackupMethod uses the only backup method of the backup policy, or the
+// method taking volume snapshots if there are multiple backup methods.
+func (o *CreateBackupOptions) completeDefaultBackupMethod() error {
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	if err := cluster.GetK8SClientObject(o.Dynamic, backupPolicy, types.BackupPolicyGVR(), o.Namespace, o.BackupSpec.BackupPolicyName); err != nil {
+		return err
+	}
+	methods := backupPolicy.Spec.BackupMethods
+	if len(methods) == 1 {
+		o.BackupSpec.BackupMethod = methods[0].Name
+		return nil
+	}
+	for _, m := range methods {
+		if boolptr.IsSetToTrue(m.SnapshotVolumes) {
+			o.BackupSpec.BackupMethod = m.Name
+			return nil
+		}
+	}
+	return nil
+}
 
-	util.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"method",
+func (o *CreateBackupOptions) RegisterBackupFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
+	util.RegisterFlagCompletionFunc(cmd, "deletion-policy",
+		util.ValuesCompletionFunc(string(dpv1alpha1.BackupDeletionPolicyRetain), string(dpv1alpha1.BackupDeletionPolicyDelete)))
+	util.RegisterFlagCompletionFunc(cmd, "policy", util.ClusterResourceCompletionFunc(f, types.BackupPolicyGVR()))
+	util.RegisterFlagCompletionFunc(cmd, "parent-backup", util.ClusterResourceCompletionFunc(f, types.BackupGVR()))
+	util.RegisterFlagCompletionFunc(cmd, "method",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			namespace, _ := cmd.Flags().GetString("namespace")
-			if namespace == "" {
-				namespace, _, _ = f.ToRawKubeConfigLoader().Namespace()
-			}
+			namespace, _, _ := f.ToRawKubeConfigLoader().Namespace()
 			var (
 				labelSelector string
-				clusterName   = getClusterName(cmd, args)
+				clusterName   = util.GetClusterNameFromArgsOrFlag(cmd, args)
 			)
 			if clusterName != "" {
 				labelSelector = fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, clusterName)
 			}
-			dynamicClient, _ := f.DynamicClient()
-			objs, _ := dynamicClient.Resource(types.BackupPolicyGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
+			dynamicClient, err := f.DynamicClient()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			objs, err := dynamicClient.Resource(types.BackupPolicyGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
 				LabelSelector: labelSelector,
 			})
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
 			methodMap := map[string]struct{}{}
 			for _, v := range objs.Items {
 				backupPolicy := &dpv1alpha1.BackupPolicy{}
@@ -358,17 +657,12 @@ func (o *CreateBackupOptions) RegisterBackupFlagCompletionFunc(cmd *cobra.Comman
 				}
 			}
 			return maps.Keys(methodMap), cobra.ShellCompDirectiveNoFileComp
-		}))
+		})
 }
 
 func PrintBackupList(o ListBackupOptions) error {
-	var backupNameMap = make(map[string]bool)
-	for _, name := range o.Names {
-		backupNameMap[name] = true
-	}
-
-	// if format is JSON or YAML, use default printer to output the result.
-	if o.Format == printer.JSON || o.Format == printer.YAML {
+	// if format is JSON or YAML, or watch is enabled, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML || o.Watch {
 		if o.BackupName != "" {
 			o.Names = []string{o.BackupName}
 		}
@@ -394,16 +688,26 @@ func PrintBackupList(o ListBackupOptions) error {
 		o.PrintNotFoundResources()
 		return nil
 	}
+	// sort the backups by the creation timestamp in positive order, the CREATE-TIME column
+	// is only accurate to the minute
+	sort.Stable(unstructuredList(backupList.Items))
+	backups, err := util.ConvertUnstructuredList[dpv1alpha1.Backup](backupList.Items, util.NameFilter(o.Names...))
+	if err != nil {
+		return err
+	}
 
-	// sort the unstructured objects with the creationTimestamp in positive order
-	sort.Sort(unstructuredList(backupList.Items))
 	tbl := printer.NewTablePrinter(o.Out)
-	tbl.SetHeader("NAME", "NAMESPACE", "SOURCE-CLUSTER", "METHOD", "STATUS", "TOTAL-SIZE", "DURATION", "CREATE-TIME", "COMPLETION-TIME", "EXPIRATION")
-	for _, obj := range backupList.Items {
-		backup := &dpv1alpha1.Backup{}
-		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, backup); err != nil {
-			return err
-		}
+	tbl.SetFormat(o.Format)
+	header := []interface{}{"NAME", "NAMESPACE", "SOURCE-CLUSTER", "METHOD", "STATUS", "TOTAL-SIZE", "DURATION", "CREATE-TIME", "COMPLETION-TIME", "EXPIRATION", "PROTECTED"}
+	if o.Format == printer.Wide {
+		header = append(header, "BACKUP-POLICY", "BACKUP-REPO", "PATH")
+	}
+	tbl.SetHeader(header...)
+	if err = tbl.ApplyOptions(&o.TableOptions); err != nil {
+		return err
+	}
+	for i := range backups {
+		backup := &backups[i]
 		// TODO(ldm): find cluster from backup policy target spec.
 		sourceCluster := backup.Labels[constant.AppInstanceLabelKey]
 		durationStr := ""
@@ -411,9 +715,6 @@ func PrintBackupList(o ListBackupOptions) error {
 			durationStr = duration.HumanDuration(backup.Status.Duration.Duration)
 		}
 		statusString := string(backup.Status.Phase)
-		if len(o.Names) > 0 && !backupNameMap[backup.Name] {
-			continue
-		}
 		var availableReplicas *int32
 		for _, v := range backup.Status.Actions {
 			if v.ActionType == dpv1alpha1.ActionTypeStatefulSet {
@@ -424,9 +725,13 @@ func PrintBackupList(o ListBackupOptions) error {
 		if availableReplicas != nil {
 			statusString = fmt.Sprintf("%s(AvailablePods: %d)", statusString, availableReplicas)
 		}
-		tbl.AddRow(backup.Name, backup.Namespace, sourceCluster, backup.Spec.BackupMethod, statusString, backup.Status.TotalSize,
+		row := []interface{}{backup.Name, backup.Namespace, sourceCluster, backup.Spec.BackupMethod, statusString, backup.Status.TotalSize,
 			durationStr, util.TimeFormat(&backup.CreationTimestamp), util.TimeFormat(backup.Status.CompletionTimestamp),
-			util.TimeFormat(backup.Status.Expiration))
+			util.TimeFormat(backup.Status.Expiration), IsBackupProtected(backup)}
+		if o.Format == printer.Wide {
+			row = append(row, backup.Spec.BackupPolicyName, backup.Status.BackupRepoName, backup.Status.Path)
+		}
+		tbl.AddRow(row...)
 	}
 	tbl.Print()
 	return nil
@@ -451,7 +756,9 @@ func NewListBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 		},
 	}
 	o.AddFlags(cmd)
+	o.TableOptions.AddFlags(cmd)
 	cmd.Flags().StringVar(&o.BackupName, "name", "", "The backup name to get the details.")
+	util.RegisterFlagCompletionFunc(cmd, "name", util.ClusterResourceCompletionFunc(f, types.BackupGVR()))
 	return cmd
 }
 
@@ -462,8 +769,8 @@ func NewDescribeBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 		Gvr:       types.BackupGVR(),
 	}
 	cmd := &cobra.Command{
-		Use:               "describe-backup BACKUP-NAME",
-		Short:             "Describe a backup.",
+		Use:               "describe-backup BACKUP-NAME...",
+		Short:             "Describe backups.",
 		Aliases:           []string{"desc-backup"},
 		Example:           describeBackupExample,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupGVR()),
@@ -473,11 +780,28 @@ func NewDescribeBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 			util.CheckErr(o.Run())
 		},
 	}
+	o.AddFlags(cmd)
 	return cmd
 }
 
+// DescribeBackup describes the backup in the specified namespace, it is used by
+// the callers that are not a command, such as the dashboard TUI.
+func DescribeBackup(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) error {
+	o := &DescribeBackupOptions{
+		Factory:   f,
+		IOStreams: streams,
+		Gvr:       types.BackupGVR(),
+	}
+	if err := o.Complete([]string{name}); err != nil {
+		return err
+	}
+	o.namespace = namespace
+	return o.Run()
+}
+
 func NewDeleteBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
 	o := action.NewDeleteOptions(f, streams, types.BackupGVR())
+	deleteProtected := false
 	cmd := &cobra.Command{
 		Use:               "delete-backup",
 		Short:             "Delete a backup.",
@@ -485,25 +809,27 @@ func NewDeleteBackupCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			util.CheckErr(completeForDeleteBackup(o, args))
+			util.CheckErr(completeForDeleteBackup(o, args, deleteProtected))
 			util.CheckErr(o.Run())
 		},
 	}
 	cmd.Flags().StringSliceVar(&o.Names, "name", []string{}, "Backup names")
+	util.RegisterFlagCompletionFunc(cmd, "name", util.ClusterResourceCompletionFunc(f, types.BackupGVR()))
 	o.AddFlags(cmd)
+	AddDeleteProtectedFlag(cmd, &deleteProtected)
 	return cmd
 }
 
 // completeForDeleteBackup completes cmd for delete backup
-func completeForDeleteBackup(o *action.DeleteOptions, args []string) error {
+func completeForDeleteBackup(o *action.DeleteOptions, args []string, deleteProtected bool) error {
 	if len(args) == 0 {
-		return errors.New("Missing cluster name")
+		return clierrors.NewValidation("Missing cluster name")
 	}
 	if len(args) > 1 {
-		return errors.New("Only supported delete the Backup of one cluster")
+		return clierrors.NewValidation("Only supported delete the Backup of one cluster")
 	}
 	if !o.Force && len(o.Names) == 0 {
-		return errors.New("Missing --name as backup name.")
+		return clierrors.NewValidation("Missing --name as backup name.")
 	}
 	if o.Force && len(o.Names) == 0 {
 		// do force action, for --force and --name unset, delete all backups of the cluster
@@ -512,7 +838,7 @@ func completeForDeleteBackup(o *action.DeleteOptions, args []string) error {
 		o.ConfirmedNames = args
 	}
 	o.ConfirmedNames = o.Names
-	return nil
+	return CheckProtectedBackupsToDelete(o, deleteProtected)
 }
 
 type CreateRestoreOptions struct {
@@ -521,12 +847,91 @@ type CreateRestoreOptions struct {
 	OpsType        string                   `json:"opsType"`
 	OpsRequestName string                   `json:"opsRequestName"`
 
+	// Wait waits for the restored cluster to be running after the restore is created
+	Wait    bool          `json:"-"`
+	Timeout time.Duration `json:"-"`
+
+	// overrides are the resources of the restored cluster different from the source cluster,
+	// the cluster is created directly instead of by the Restore OpsRequest if any is specified.
+	overrides       restoreOverrides
+	restoredCluster *appsv1alpha1.Cluster
+
+	// limits are the resource limits and bandwidth of the restore workloads, which are set to the
+	// Restores created by the cluster controller while waiting for the cluster to be restored.
+	limits workloadLimits
+
 	action.CreateOptions `json:"-"`
 }
 
+// AddWaitFlags adds the flags to wait for the restored cluster to be running
+func (o *CreateRestoreOptions) AddWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the restored cluster to be running. It will wait for a --timeout period")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", defaultWaitTimeout, "Time to wait for the restored cluster to be running when --wait is true, such as --timeout=10m")
+}
+
+// Run creates the restore, and waits for the restored cluster to be running if --wait is true
+func (o *CreateRestoreOptions) Run() error {
+	run := o.CreateOptions.Run
+	if o.restoredCluster != nil {
+		run = o.createRestoredCluster
+	}
+	if err := run(); err != nil {
+		return err
+	}
+	if dryRun, _ := o.GetDryRunStrategy(); !o.Wait || dryRun != action.DryRunNone {
+		return nil
+	}
+	var onPoll func() error
+	if o.limits.enabled() {
+		onPoll = func() error {
+			patched, err := applyRestoreLimits(o.Dynamic, o.Namespace, o.Name, &o.limits)
+			for _, name := range patched {
+				fmt.Fprintf(o.ErrOut, "Set the resource limits and bandwidth of restore %s\n", name)
+			}
+			return err
+		}
+	}
+	return waitClusterRestored(o.Dynamic, o.Namespace, o.Name, o.Timeout, o.Out, onPoll)
+}
+
+// waitClusterRestored waits for the restored cluster to be running, the progress is the count
+// of the running components. onPoll is called on each poll if it is not nil.
+func waitClusterRestored(dynamic dynamic.Interface, namespace, name string, timeout time.Duration, out io.Writer, onPoll func() error) error {
+	bar := progress.NewBar(out, fmt.Sprintf("Restore cluster %s", name), 0)
+	defer bar.Done()
+	return wait.PollUntilContextTimeout(context.Background(), 5*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		if onPoll != nil {
+			if err := onPoll(); err != nil {
+				return false, err
+			}
+		}
+		cls := &appsv1alpha1.Cluster{}
+		if err := util.GetResourceObjectFromGVR(types.ClusterGVR(), client.ObjectKey{Namespace: namespace, Name: name}, dynamic, cls); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		running := 0
+		for _, comp := range cls.Spec.ComponentSpecs {
+			if cls.Status.Components[comp.Name].Phase == appsv1alpha1.RunningClusterCompPhase {
+				running++
+			}
+		}
+		bar.Update(running, len(cls.Spec.ComponentSpecs), string(cls.Status.Phase))
+		switch cls.Status.Phase {
+		case appsv1alpha1.RunningClusterPhase:
+			return true, nil
+		case appsv1alpha1.FailedClusterPhase:
+			return false, fmt.Errorf("failed to restore cluster %s, run \"kbcli cluster describe %s -n %s\" to check the status", name, name, namespace)
+		}
+		return false, nil
+	})
+}
+
 func (o *CreateRestoreOptions) Validate() error {
 	if o.RestoreSpec.BackupName == "" {
-		return fmt.Errorf("must be specified one of the --backup ")
+		return clierrors.NewValidation("must be specified one of the --backup ")
 	}
 
 	if o.Name == "" {
@@ -545,6 +950,20 @@ func (o *CreateRestoreOptions) Validate() error {
 	o.ClusterRef = o.Name
 	o.OpsRequestName = o.Name
 
+	if o.limits.enabled() {
+		if err := o.limits.validate(); err != nil {
+			return err
+		}
+		// the limits are set to the Restores while waiting for the cluster to be restored
+		o.Wait = true
+	}
+
+	if o.overrides.enabled() {
+		var err error
+		if o.restoredCluster, err = o.buildRestoredCluster(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -577,8 +996,15 @@ func NewCreateRestoreCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 		},
 	}
 	cmd.Flags().StringVar(&o.RestoreSpec.BackupName, "backup", "", "Backup name")
+	util.RegisterFlagCompletionFunc(cmd, "backup", util.ResourceNameCompletionFunc(f, types.BackupGVR()))
 	cmd.Flags().StringVar(&o.RestoreSpec.RestoreTimeStr, "restore-to-time", "", "point in time recovery(PITR)")
 	cmd.Flags().StringVar(&o.RestoreSpec.VolumeRestorePolicy, "volume-restore-policy", "Parallel", "the volume claim restore policy, supported values: [Serial, Parallel]")
+	util.RegisterFlagCompletionFunc(cmd, "volume-restore-policy", util.ValuesCompletionFunc("Serial", "Parallel"))
+	o.overrides.addFlags(cmd)
+	o.limits.addFlags(cmd, "restore")
+	util.RegisterFlagCompletionFunc(cmd, "storage-class", util.ResourceNameCompletionFunc(f, types.StorageClassGVR()))
+	o.AddDryRunFlags(cmd, false)
+	o.AddWaitFlags(cmd)
 	return cmd
 }
 
@@ -599,18 +1025,14 @@ func NewListBackupPolicyCmd(f cmdutil.Factory, streams genericiooptions.IOStream
 		},
 	}
 	o.AddFlags(cmd)
+	o.TableOptions.AddFlags(cmd)
 	return cmd
 }
 
 // PrintBackupPolicyList prints the backup policy list.
 func PrintBackupPolicyList(o action.ListOptions) error {
-	var backupPolicyNameMap = make(map[string]bool)
-	for _, name := range o.Names {
-		backupPolicyNameMap[name] = true
-	}
-
-	// if format is JSON or YAML, use default printer to output the result.
-	if o.Format == printer.JSON || o.Format == printer.YAML {
+	// if format is JSON or YAML, or watch is enabled, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML || o.Watch {
 		_, err := o.Run()
 		return err
 	}
@@ -633,29 +1055,108 @@ func PrintBackupPolicyList(o action.ListOptions) error {
 		o.PrintNotFoundResources()
 		return nil
 	}
+	backupPolicies, err := util.ConvertUnstructuredList[dpv1alpha1.BackupPolicy](backupPolicyList.Items, util.NameFilter(o.Names...))
+	if err != nil {
+		return err
+	}
+
+	backupScheduleList, err := dynamic.Resource(types.BackupScheduleGVR()).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	backupSchedules, err := util.ConvertUnstructuredList[dpv1alpha1.BackupSchedule](backupScheduleList.Items, nil)
+	if err != nil {
+		return err
+	}
+	schedulePolicies := getEffectiveSchedulePolicies(backupSchedules)
 
 	tbl := printer.NewTablePrinter(o.Out)
-	tbl.SetHeader("NAME", "NAMESPACE", "DEFAULT", "CLUSTER", "CREATE-TIME", "STATUS")
-	for _, obj := range backupPolicyList.Items {
-		defaultPolicy, ok := obj.GetAnnotations()[dptypes.DefaultBackupPolicyAnnotationKey]
-		backupPolicy := &dpv1alpha1.BackupPolicy{}
-		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, backupPolicy); err != nil {
-			return err
-		}
+	tbl.SetFormat(o.Format)
+	header := []interface{}{"NAME", "NAMESPACE", "DEFAULT", "CLUSTER", "CREATE-TIME", "STATUS", "NEXT-RUN"}
+	if o.Format == printer.Wide {
+		header = append(header, "BACKUP-REPO", "METHODS", "SCHEDULE")
+	}
+	tbl.SetHeader(header...)
+	if err = tbl.ApplyOptions(&o.TableOptions); err != nil {
+		return err
+	}
+	for i := range backupPolicies {
+		backupPolicy := &backupPolicies[i]
+		defaultPolicy, ok := backupPolicy.Annotations[dptypes.DefaultBackupPolicyAnnotationKey]
 		if !ok {
 			defaultPolicy = "false"
 		}
-		if len(o.Names) > 0 && !backupPolicyNameMap[backupPolicy.Name] {
-			continue
+		policies := schedulePolicies[client.ObjectKeyFromObject(backupPolicy)]
+		row := []interface{}{backupPolicy.Name, backupPolicy.Namespace, defaultPolicy, backupPolicy.Labels[constant.AppInstanceLabelKey],
+			util.TimeFormat(&backupPolicy.CreationTimestamp), backupPolicy.Status.Phase, getNextRunTime(policies, time.Now())}
+		if o.Format == printer.Wide {
+			var methods, schedules []string
+			for _, m := range backupPolicy.Spec.BackupMethods {
+				methods = append(methods, m.Name)
+			}
+			for _, p := range policies {
+				schedules = append(schedules, fmt.Sprintf("%s(%s)", p.BackupMethod, p.CronExpression))
+			}
+			row = append(row, pointer.StringDeref(backupPolicy.Spec.BackupRepoName, ""), strings.Join(methods, ","), strings.Join(schedules, ","))
 		}
-		createTime := obj.GetCreationTimestamp()
-		tbl.AddRow(obj.GetName(), obj.GetNamespace(), defaultPolicy, obj.GetLabels()[constant.AppInstanceLabelKey],
-			util.TimeFormat(&createTime), backupPolicy.Status.Phase)
+		tbl.AddRow(row...)
 	}
 	tbl.Print()
 	return nil
 }
 
+// getEffectiveSchedulePolicies returns the enabled schedule policies of the backup policies, the
+// schedules failed to be reconciled are skipped as they will not create any backup.
+func getEffectiveSchedulePolicies(backupSchedules []dpv1alpha1.BackupSchedule) map[client.ObjectKey][]dpv1alpha1.SchedulePolicy {
+	result := map[client.ObjectKey][]dpv1alpha1.SchedulePolicy{}
+	for _, schedule := range backupSchedules {
+		if schedule.Status.Phase == dpv1alpha1.BackupSchedulePhaseFailed {
+			continue
+		}
+		key := client.ObjectKey{Namespace: schedule.Namespace, Name: schedule.Spec.BackupPolicyName}
+		for _, policy := range schedule.Spec.Schedules {
+			if boolptr.IsSetToTrue(policy.Enabled) {
+				result[key] = append(result[key], policy)
+			}
+		}
+	}
+	return result
+}
+
+// getNextScheduleTime returns the next time after now of the cron expression. The cron expression of
+// the backup schedule is in UTC, unless the timezone is specified by the CRON_TZ or TZ prefix.
+func getNextScheduleTime(cronExpression string, now time.Time) (time.Time, error) {
+	expr := strings.TrimSpace(cronExpression)
+	if !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		expr = "CRON_TZ=UTC " + expr
+	}
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(now), nil
+}
+
+// getNextRunTime returns the earliest next run time of the schedule policies in the local timezone, or
+// in the timezone specified by --timezone
+func getNextRunTime(policies []dpv1alpha1.SchedulePolicy, now time.Time) string {
+	var next time.Time
+	for _, p := range policies {
+		t, err := getNextScheduleTime(p.CronExpression, now)
+		if err != nil {
+			klog.V(1).Infof("invalid cron expression %s of backup method %s: %v", p.CronExpression, p.BackupMethod, err)
+			continue
+		}
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	if next.IsZero() {
+		return printer.NoneString
+	}
+	return util.TimeTimeFormat(next.Local())
+}
+
 type updateBackupPolicyFieldFunc func(backupPolicy *dpv1alpha1.BackupPolicy, targetVal string) error
 
 type editBackupPolicyOptions struct {
@@ -672,6 +1173,10 @@ type editBackupPolicyOptions struct {
 	target            string
 	values            []string
 	isTest            bool
+
+	mode string
+	// launchEditor opens the spec in the editor in the yaml mode, it's replaced in tests
+	launchEditor func(name string, content []byte) ([]byte, error)
 }
 
 type editorRow struct {
@@ -694,24 +1199,37 @@ func NewEditBackupPolicyCmd(f cmdutil.Factory, streams genericiooptions.IOStream
 		ValidArgsFunction:     util.ResourceNameCompletionFunc(f, types.BackupPolicyGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.runEditBackupPolicy())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.runEditBackupPolicy())
 		},
 	}
 	cmd.Flags().StringArrayVar(&o.values, "set", []string{},
 		"set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	cmd.Flags().StringVar(&o.mode, "mode", editModeKeyValue, fmt.Sprintf("The edit mode, %s edits the fields as key=value, %s edits the full spec of the backup policy", editModeKeyValue, editModeYAML))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{editModeKeyValue, editModeYAML}, cobra.ShellCompDirectiveNoFileComp
+	}))
 	return cmd
 }
 
 func (o *editBackupPolicyOptions) complete(args []string) error {
 	var err error
 	if len(args) == 0 {
-		return fmt.Errorf("missing backupPolicy name")
+		return clierrors.NewValidation("missing backupPolicy name")
 	}
 	if len(args) > 1 {
-		return fmt.Errorf("only support to update one backupPolicy or quote cronExpression")
+		return clierrors.NewValidation("only support to update one backupPolicy or quote cronExpression")
 	}
 	o.name = args[0]
+	switch o.mode {
+	case "", editModeKeyValue:
+	case editModeYAML:
+		if len(o.values) > 0 {
+			return clierrors.NewValidation("--set can not be used with --mode %s", editModeYAML)
+		}
+	default:
+		return clierrors.NewValidation("invalid edit mode %s, should be %s or %s", o.mode, editModeKeyValue, editModeYAML)
+	}
 	if o.namespace, _, err = o.Factory.ToRawKubeConfigLoader().Namespace(); err != nil {
 		return err
 	}
@@ -765,6 +1283,9 @@ func (o *editBackupPolicyOptions) runEditBackupPolicy() error {
 	if err != nil {
 		return err
 	}
+	if o.mode == editModeYAML {
+		return o.runYAMLEditor(backupPolicy)
+	}
 	if len(o.values) == 0 {
 		edited, err := o.runWithEditor(backupPolicy)
 		if err != nil {
@@ -867,11 +1388,11 @@ func (o *editBackupPolicyOptions) applyChanges(backupPolicy *dpv1alpha1.BackupPo
 		o.target += row
 		arr := strings.Split(row, "=")
 		if len(arr) != 2 {
-			return fmt.Errorf(`invalid row: %s, format should be "key=value"`, v)
+			return clierrors.NewValidation(`invalid row: %s, format should be "key=value"`, v)
 		}
 		updateFn, ok := o.editContentKeyMap[arr[0]]
 		if !ok {
-			return fmt.Errorf(`invalid key: %s`, arr[0])
+			return clierrors.NewValidation(`invalid key: %s`, arr[0])
 		}
 		arr[1] = strings.Trim(arr[1], `"`)
 		arr[1] = strings.Trim(arr[1], `'`)
@@ -884,10 +1405,20 @@ func (o *editBackupPolicyOptions) applyChanges(backupPolicy *dpv1alpha1.BackupPo
 		fmt.Fprintln(o.Out, "updated (no change)")
 		return nil
 	}
+	return o.updateBackupPolicy(backupPolicy)
+}
+
+// updateBackupPolicy updates the backup policy, or exports it if the export is enabled
+func (o *editBackupPolicyOptions) updateBackupPolicy(backupPolicy *dpv1alpha1.BackupPolicy) error {
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(backupPolicy)
 	if err != nil {
 		return err
 	}
+	if action.ExportEnabled() {
+		u := &unstructured.Unstructured{Object: obj}
+		u.SetGroupVersionKind(types.BackupPolicyGVR().GroupVersion().WithKind(types.KindBackupPolicy))
+		return action.ExportObject(u, o.Out)
+	}
 	if _, err = o.dynamic.Resource(types.BackupPolicyGVR()).Namespace(backupPolicy.Namespace).Update(context.TODO(),
 		&unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{}); err != nil {
 		return err
@@ -929,7 +1460,7 @@ func (o *DescribeBackupPolicyOptions) Complete() error {
 func (o *DescribeBackupPolicyOptions) Validate() error {
 	// must specify one of the cluster name or backup policy name
 	if len(o.ClusterNames) == 0 && len(o.Names) == 0 {
-		return fmt.Errorf("missing cluster name or backup policy name")
+		return clierrors.NewValidation("missing cluster name or backup policy name")
 	}
 
 	return nil
@@ -1020,11 +1551,17 @@ func NewDescribeBackupPolicyCmd(f cmdutil.Factory, streams genericiooptions.IOSt
 	return cmd
 }
 
+// AddFlags adds the flags to select the backups and the parallelism of describing.
+func (o *DescribeBackupOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", "", "Selector (label query) to filter the backups to describe if no backup name is specified, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2).")
+	cmd.Flags().IntVar(&o.Parallelism, "parallelism", util.DefaultParallelism, "The number of backups fetched concurrently.")
+}
+
 func (o *DescribeBackupOptions) Complete(args []string) error {
 	var err error
 
-	if len(args) == 0 {
-		return fmt.Errorf("backup name should be specified")
+	if len(args) == 0 && o.LabelSelector == "" {
+		return clierrors.NewValidation("backup name or label selector should be specified")
 	}
 
 	o.names = args
@@ -1043,20 +1580,51 @@ func (o *DescribeBackupOptions) Complete(args []string) error {
 	return nil
 }
 
+// Run fetches the backups concurrently and describes them in the order of the names.
 func (o *DescribeBackupOptions) Run() error {
-	for _, name := range o.names {
-		backupObj := &dpv1alpha1.Backup{}
-		if err := cluster.GetK8SClientObject(o.dynamic, backupObj, o.Gvr, o.namespace, name); err != nil {
+	names := o.names
+	if len(names) == 0 {
+		var err error
+		if names, err = util.GetResourceNames(o.dynamic, o.Gvr, o.namespace, o.LabelSelector); err != nil {
 			return err
 		}
-		if err := o.printBackupObj(backupObj); err != nil {
-			return err
+		if len(names) == 0 {
+			fmt.Fprintf(o.Out, "No backups found in %s namespace.\n", o.namespace)
+			return nil
 		}
 	}
+	infos, err := util.ParallelMap(names, o.Parallelism, o.getBackupDescribeInfo)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		o.printBackupObj(info)
+	}
 	return nil
 }
 
-func (o *DescribeBackupOptions) printBackupObj(obj *dpv1alpha1.Backup) error {
+func (o *DescribeBackupOptions) getBackupDescribeInfo(name string) (*backupDescribeInfo, error) {
+	obj := &dpv1alpha1.Backup{}
+	if err := cluster.GetK8SClientObject(o.dynamic, obj, o.Gvr, o.namespace, name); err != nil {
+		return nil, err
+	}
+	// get all events about backup
+	events, err := o.client.CoreV1().Events(o.namespace).Search(scheme.Scheme, obj)
+	if err != nil {
+		return nil, err
+	}
+	// enhance the failure reason with the pod error logs, ignore error
+	failureReason, _ := o.enhanceFailureReason(obj.Name, obj.Status.FailureReason)
+	return &backupDescribeInfo{
+		backup:        obj,
+		failureReason: failureReason,
+		events:        events,
+		causes:        diagnoseBackup(o.client, obj),
+	}, nil
+}
+
+func (o *DescribeBackupOptions) printBackupObj(info *backupDescribeInfo) {
+	obj := info.backup
 	targetCluster := obj.Labels[constant.AppInstanceLabelKey]
 	printer.PrintLineWithTabSeparator(
 		printer.NewPair("Name", obj.Name),
@@ -1068,7 +1636,7 @@ func (o *DescribeBackupOptions) printBackupObj(obj *dpv1alpha1.Backup) error {
 	realPrintPairStringToLine("Policy Name", obj.Spec.BackupPolicyName)
 
 	printer.PrintLine("\nStatus:")
-	realPrintPairStringToLine("Phase", string(obj.Status.Phase))
+	realPrintPairStringToLine("Phase", printer.ColorStatus(string(obj.Status.Phase)))
 	realPrintPairStringToLine("Total Size", obj.Status.TotalSize)
 	if obj.Status.BackupMethod != nil {
 		realPrintPairStringToLine("ActionSet Name", obj.Status.BackupMethod.ActionSetName)
@@ -1085,8 +1653,7 @@ func (o *DescribeBackupOptions) printBackupObj(obj *dpv1alpha1.Backup) error {
 	realPrintPairStringToLine("Expiration Time", util.TimeFormat(obj.Status.Expiration))
 	realPrintPairStringToLine("Start Time", util.TimeFormat(obj.Status.StartTimestamp))
 	realPrintPairStringToLine("Completion Time", util.TimeFormat(obj.Status.CompletionTimestamp))
-	// print failure reason, ignore error
-	_ = o.enhancePrintFailureReason(obj.Name, obj.Status.FailureReason)
+	realPrintPairStringToLine("Failure Reason", info.failureReason)
 
 	realPrintPairStringToLine("Path", obj.Status.Path)
 
@@ -1105,16 +1672,11 @@ func (o *DescribeBackupOptions) printBackupObj(obj *dpv1alpha1.Backup) error {
 		}
 	}
 
-	// get all events about backup
-	events, err := o.client.CoreV1().Events(o.namespace).Search(scheme.Scheme, obj)
-	if err != nil {
-		return err
-	}
-
 	// print the warning events
-	printer.PrintAllWarningEvents(events, o.Out)
+	printer.PrintAllWarningEvents(info.events, o.Out)
 
-	return nil
+	// print the probable causes correlated from the warning events and failure reason
+	showProbableCauses(info.causes, o.Out)
 }
 
 func realPrintPairStringToLine(name, value string, spaceCount ...int) {
@@ -1123,11 +1685,11 @@ func realPrintPairStringToLine(name, value string, spaceCount ...int) {
 	}
 }
 
-// print the pod error logs if failure reason has occurred
+// enhanceFailureReason appends the pod error logs to the failure reason if it has occurred
 // TODO: the failure reason should be improved in the backup controller
-func (o *DescribeBackupOptions) enhancePrintFailureReason(backupName, failureReason string, spaceCount ...int) error {
+func (o *DescribeBackupOptions) enhanceFailureReason(backupName, failureReason string) (string, error) {
 	if failureReason == "" {
-		return nil
+		return "", nil
 	}
 	ctx := context.Background()
 	// get the latest job log details.
@@ -1136,7 +1698,7 @@ func (o *DescribeBackupOptions) enhancePrintFailureReason(backupName, failureRea
 	)
 	jobList, err := o.client.BatchV1().Jobs("").List(ctx, metav1.ListOptions{LabelSelector: labels})
 	if err != nil {
-		return err
+		return failureReason, err
 	}
 	var failedJob *batchv1.Job
 	for _, i := range jobList.Items {
@@ -1151,7 +1713,7 @@ func (o *DescribeBackupOptions) enhancePrintFailureReason(backupName, failureRea
 		)
 		podList, err := o.client.CoreV1().Pods(failedJob.Namespace).List(ctx, metav1.ListOptions{LabelSelector: podLabels})
 		if err != nil {
-			return err
+			return failureReason, err
 		}
 		if len(podList.Items) > 0 {
 			tailLines := int64(5)
@@ -1160,13 +1722,11 @@ func (o *DescribeBackupOptions) enhancePrintFailureReason(backupName, failureRea
 				GetLogs(podList.Items[0].Name, &corev1.PodLogOptions{TailLines: &tailLines})
 			data, err := req.DoRaw(ctx)
 			if err != nil {
-				return err
+				return failureReason, err
 			}
 			failureReason = fmt.Sprintf("%s\n pod %s error logs:\n%s",
 				failureReason, podList.Items[0].Name, string(data))
 		}
 	}
-	printer.PrintPairStringToLine("Failure Reason", failureReason, spaceCount...)
-
-	return nil
+	return failureReason, nil
 }
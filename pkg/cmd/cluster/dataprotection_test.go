@@ -22,6 +22,7 @@ package cluster
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -29,21 +30,31 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/dynamic"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
 	clientfake "k8s.io/client-go/rest/fake"
+	clientgotesting "k8s.io/client-go/testing"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
@@ -108,6 +119,39 @@ var _ = Describe("DataProtection", func() {
 			Expect(len(strings.Split(strings.Trim(out.String(), "\n"), "\n"))).Should(Equal(4))
 		})
 
+		It("list-backup-policy with the next run time", func() {
+			defaultBackupPolicy := testing.FakeBackupPolicy(policyName, testing.ClusterName)
+			schedule := testing.FakeBackupSchedule("schedule", policyName)
+			schedule.Spec.Schedules = append(schedule.Spec.Schedules, dpv1alpha1.SchedulePolicy{
+				Enabled:        boolptr.False(),
+				BackupMethod:   "snapshot",
+				CronExpression: "*/5 * * * *",
+			})
+			tf.FakeDynamicClient = testing.FakeDynamicClient(defaultBackupPolicy, schedule)
+
+			cmd := NewListBackupPolicyCmd(tf, streams)
+			_ = cmd.Flags().Set("output", "wide")
+			cmd.Run(cmd, nil)
+			Expect(out.String()).Should(ContainSubstring("NEXT-RUN"))
+			Expect(out.String()).Should(ContainSubstring(testing.BackupMethodName + "(0 0 * * *)"))
+			Expect(out.String()).ShouldNot(ContainSubstring("*/5 * * * *"))
+			next, err := getNextScheduleTime("0 0 * * *", time.Now())
+			Expect(err).Should(Succeed())
+			Expect(out.String()).Should(ContainSubstring(util.TimeTimeFormat(next.Local())))
+
+			By("the cron expression is in UTC unless the timezone is specified")
+			now := time.Date(2023, 10, 1, 10, 30, 0, 0, time.UTC)
+			next, err = getNextScheduleTime("0 18 * * *", now)
+			Expect(err).Should(Succeed())
+			Expect(next.Equal(time.Date(2023, 10, 1, 18, 0, 0, 0, time.UTC))).Should(BeTrue())
+			next, err = getNextScheduleTime("CRON_TZ=Asia/Shanghai 0 18 * * *", now)
+			Expect(err).Should(Succeed())
+			Expect(next.Equal(time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC).Add(24 * time.Hour))).Should(BeTrue())
+			_, err = getNextScheduleTime("invalid", now)
+			Expect(err).Should(HaveOccurred())
+			Expect(getNextRunTime(nil, now)).Should(Equal(printer.NoneString))
+		})
+
 		It("edit-backup-policy", func() {
 			By("fake client")
 			defaultBackupPolicy := testing.FakeBackupPolicy(policyName, testing.ClusterName)
@@ -130,6 +174,61 @@ var _ = Describe("DataProtection", func() {
 			Expect(o.runEditBackupPolicy()).Should(Succeed())
 		})
 
+		It("edit-backup-policy in yaml mode", func() {
+			defaultBackupPolicy := testing.FakeBackupPolicy(policyName, testing.ClusterName)
+			repo := testing.FakeBackupRepo(repoName, false)
+			tf.FakeDynamicClient = testing.FakeDynamicClient(defaultBackupPolicy, repo, testing.FakeActionSet())
+
+			o := editBackupPolicyOptions{Factory: tf, IOStreams: streams, GVR: types.BackupPolicyGVR(), mode: editModeYAML}
+			o.values = []string{"backupRepoName=repo"}
+			Expect(o.complete([]string{policyName})).Should(MatchError(ContainSubstring("--set can not be used with --mode yaml")))
+			o.values = nil
+			Expect(o.complete([]string{policyName})).Should(Succeed())
+
+			By("the editor is reopened with the errors until the spec is valid")
+			var contents []string
+			edits := []func(string) string{
+				func(s string) string { return strings.Replace(s, "target:", "unknownField: true\ntarget:", 1) },
+				func(s string) string {
+					s = strings.Replace(s, "unknownField: true\n", "", 1)
+					return strings.Replace(s, "actionSetName: "+testing.ActionSetName, "actionSetName: not-exist", 1)
+				},
+				func(s string) string {
+					return strings.Replace(s, "actionSetName: not-exist", "actionSetName: "+testing.ActionSetName, 1) + "backupRepoName: " + repoName + "\n"
+				},
+			}
+			o.launchEditor = func(_ string, content []byte) ([]byte, error) {
+				contents = append(contents, string(content))
+				return []byte(edits[len(contents)-1](string(content))), nil
+			}
+			Expect(o.runEditBackupPolicy()).Should(Succeed())
+			Expect(contents).Should(HaveLen(3))
+			Expect(contents[1]).Should(ContainSubstring(`# * failed to parse the spec`))
+			Expect(contents[2]).Should(ContainSubstring(`# * the ActionSet not-exist of backup method`))
+			Expect(out.String()).Should(ContainSubstring("+backupRepoName: " + repoName))
+			Expect(out.String()).Should(ContainSubstring("updated"))
+			policy := &dpv1alpha1.BackupPolicy{}
+			Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, policy, types.BackupPolicyGVR(), testing.Namespace, policyName)).Should(Succeed())
+			Expect(*policy.Spec.BackupRepoName).Should(Equal(repoName))
+
+			By("the edit is cancelled if the invalid spec is saved again")
+			contents = nil
+			o.launchEditor = func(_ string, content []byte) ([]byte, error) {
+				contents = append(contents, string(content))
+				if len(contents) == 1 {
+					return []byte(strings.Replace(string(content), "backupMethods:", "backupMethods: []\nbackupMethods_:", 1)), nil
+				}
+				return content, nil
+			}
+			Expect(o.runEditBackupPolicy()).Should(MatchError(ContainSubstring("no valid changes were saved")))
+
+			By("the edit is cancelled without changes")
+			out.Reset()
+			o.launchEditor = func(_ string, content []byte) ([]byte, error) { return content, nil }
+			Expect(o.runEditBackupPolicy()).Should(Succeed())
+			Expect(out.String()).Should(ContainSubstring("no changes made"))
+		})
+
 		It("validate create backup", func() {
 			By("without cluster name")
 			o := &CreateBackupOptions{
@@ -139,20 +238,26 @@ var _ = Describe("DataProtection", func() {
 					Factory:   tf,
 				},
 			}
-			Expect(o.Validate()).To(MatchError("missing cluster name"))
+			err := o.Validate()
+			Expect(err).To(MatchError("missing cluster name"))
+			Expect(clierrors.ExitCode(err)).Should(Equal(clierrors.ExitCodeValidation))
 
 			By("test without default backupPolicy")
 			o.Name = testing.ClusterName
 			o.Namespace = testing.Namespace
 			initClient()
 			o.Dynamic = tf.FakeDynamicClient
-			Expect(o.Validate()).Should(MatchError(fmt.Errorf(`not found any backup policy for cluster "%s"`, testing.ClusterName)))
+			err = o.Validate()
+			Expect(err).Should(MatchError(fmt.Sprintf(`not found any backup policy for cluster "%s"`, testing.ClusterName)))
+			Expect(clierrors.IsNotFound(err)).Should(BeTrue())
 
 			By("test with two default backupPolicy")
 			defaultBackupPolicy := testing.FakeBackupPolicy(policyName, testing.ClusterName)
 			initClient(defaultBackupPolicy, testing.FakeBackupPolicy("policy2", testing.ClusterName))
 			o.Dynamic = tf.FakeDynamicClient
-			Expect(o.Validate()).Should(MatchError(fmt.Errorf(`cluster "%s" has multiple default backup policies`, o.Name)))
+			err = o.Validate()
+			Expect(err).Should(MatchError(fmt.Sprintf(`cluster "%s" has multiple default backup policies`, o.Name)))
+			Expect(clierrors.IsConflict(err)).Should(BeTrue())
 
 			By("test without method")
 			initClient(defaultBackupPolicy)
@@ -166,6 +271,169 @@ var _ = Describe("DataProtection", func() {
 			Expect(o.Validate()).Should(Succeed())
 		})
 
+		It("validate incremental backup", func() {
+			policy := testing.FakeBackupPolicy(testing.BackupPolicyName, testing.ClusterName)
+			o := &CreateBackupOptions{
+				CreateOptions: action.CreateOptions{
+					IOStreams: streams,
+					Factory:   tf,
+					Name:      testing.ClusterName,
+					Namespace: testing.Namespace,
+				},
+				BackupSpec: appsv1alpha1.BackupSpec{
+					BackupPolicyName: testing.BackupPolicyName,
+					BackupMethod:     testing.BackupMethodName,
+				},
+				Incremental: true,
+			}
+
+			By("no completed backup as the parent")
+			running := testing.FakeBackupWithPhase("running", dpv1alpha1.BackupPhaseRunning)
+			tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeCluster(testing.ClusterName, testing.Namespace), policy, running)
+			o.Dynamic = tf.FakeDynamicClient
+			err := o.Validate()
+			Expect(err).Should(MatchError(ContainSubstring("create a full backup first")))
+			Expect(clierrors.ExitCode(err)).Should(Equal(clierrors.ExitCodeValidation))
+
+			By("use the latest completed backup of the same method as the parent")
+			older := testing.FakeBackupWithPhase("older", dpv1alpha1.BackupPhaseCompleted)
+			latest := testing.FakeBackupWithPhase("latest", dpv1alpha1.BackupPhaseCompleted)
+			latest.Status.CompletionTimestamp = &metav1.Time{Time: older.Status.CompletionTimestamp.Add(time.Hour)}
+			otherMethod := testing.FakeBackupWithPhase("other-method", dpv1alpha1.BackupPhaseCompleted)
+			otherMethod.Spec.BackupMethod = "volume-snapshot"
+			otherMethod.Status.CompletionTimestamp = &metav1.Time{Time: latest.Status.CompletionTimestamp.Add(time.Hour)}
+			tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeCluster(testing.ClusterName, testing.Namespace), policy,
+				running, older, latest, otherMethod)
+			o.Dynamic = tf.FakeDynamicClient
+			Expect(o.Validate()).Should(Succeed())
+			Expect(o.BackupSpec.ParentBackupName).Should(Equal("latest"))
+
+			By("--incremental and --parent-backup are exclusive")
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("at the same time")))
+		})
+
+		It("backup and restore with workload limits", func() {
+			policy := testing.FakeBackupPolicy(testing.BackupPolicyName, testing.ClusterName)
+			policy.Spec.BackupMethods[0].RuntimeSettings = &dpv1alpha1.RuntimeSettings{
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+			}
+			o := &CreateBackupOptions{
+				CreateOptions: action.CreateOptions{
+					IOStreams: streams,
+					Factory:   tf,
+					Name:      testing.ClusterName,
+					Namespace: testing.Namespace,
+					Dynamic:   testing.FakeDynamicClient(testing.FakeCluster(testing.ClusterName, testing.Namespace), policy),
+				},
+				BackupSpec: appsv1alpha1.BackupSpec{
+					BackupPolicyName: testing.BackupPolicyName,
+					BackupMethod:     testing.BackupMethodName,
+				},
+			}
+
+			By("validate the limits")
+			o.Limits = workloadLimits{bandwidth: "fast"}
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("invalid --bandwidth")))
+			o.Limits = workloadLimits{cpuLimit: "0"}
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("--cpu-limit should be greater than 0")))
+
+			By("the backup uses the backup policy derived with the limits")
+			o.Limits = workloadLimits{cpuLimit: "1", memoryLimit: "1Gi", bandwidth: "50Mi"}
+			Expect(o.Validate()).Should(Succeed())
+			Expect(o.applyThrottledBackupPolicy()).Should(Succeed())
+			Expect(o.BackupSpec.BackupPolicyName).Should(Equal(o.Limits.throttledBackupPolicyName(testing.BackupPolicyName)))
+			throttled := &dpv1alpha1.BackupPolicy{}
+			Expect(cluster.GetK8SClientObject(o.Dynamic, throttled, types.BackupPolicyGVR(), testing.Namespace, o.BackupSpec.BackupPolicyName)).Should(Succeed())
+			Expect(throttled.Annotations).ShouldNot(HaveKey(dptypes.DefaultBackupPolicyAnnotationKey))
+			Expect(throttled.OwnerReferences[0].Name).Should(Equal(testing.BackupPolicyName))
+			method := throttled.Spec.BackupMethods[0]
+			Expect(method.RuntimeSettings.Resources.Limits.Cpu().String()).Should(Equal("1"))
+			Expect(method.RuntimeSettings.Resources.Limits.Memory().String()).Should(Equal("1Gi"))
+			// the request greater than the limit is lowered to the limit
+			Expect(method.RuntimeSettings.Resources.Requests.Cpu().String()).Should(Equal("1"))
+			Expect(method.Env).Should(ContainElement(corev1.EnvVar{Name: bandwidthEnvName, Value: "52428800"}))
+
+			By("the limits are set to the unfinished restores of the restored cluster")
+			newRestore := func(name string, phase dpv1alpha1.RestorePhase) *dpv1alpha1.Restore {
+				return &dpv1alpha1.Restore{
+					TypeMeta: metav1.TypeMeta{APIVersion: types.RestoreGVR().GroupVersion().String(), Kind: "Restore"},
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testing.Namespace,
+						Labels: map[string]string{constant.AppInstanceLabelKey: "new-cluster"}},
+					Status: dpv1alpha1.RestoreStatus{Phase: phase},
+				}
+			}
+			dynamic := testing.FakeDynamicClient(newRestore("preparedata", dpv1alpha1.RestorePhaseRunning),
+				newRestore("completed", dpv1alpha1.RestorePhaseCompleted))
+			limits := &workloadLimits{cpuLimit: "500m", bandwidth: "10Mi"}
+			patched, err := applyRestoreLimits(dynamic, testing.Namespace, "new-cluster", limits)
+			Expect(err).Should(Succeed())
+			Expect(patched).Should(Equal([]string{"preparedata"}))
+			restore := &dpv1alpha1.Restore{}
+			Expect(cluster.GetK8SClientObject(dynamic, restore, types.RestoreGVR(), testing.Namespace, "preparedata")).Should(Succeed())
+			Expect(restore.Spec.ContainerResources.Limits.Cpu().String()).Should(Equal("500m"))
+			Expect(restore.Spec.Env).Should(ContainElement(corev1.EnvVar{Name: bandwidthEnvName, Value: "10485760"}))
+			// the restore already with the limits is not patched again
+			patched, err = applyRestoreLimits(dynamic, testing.Namespace, "new-cluster", limits)
+			Expect(err).Should(Succeed())
+			Expect(patched).Should(BeEmpty())
+		})
+
+		It("validate volume snapshot backup", func() {
+			policy := testing.FakeBackupPolicy(policyName, testing.ClusterName)
+			policy.Spec.BackupMethods[0].SnapshotVolumes = boolptr.True()
+			sc := testing.FakeStorageClass(testing.StorageClassName, false)
+			sc.Provisioner = "hostpath.csi.k8s.io"
+			pvcs := testing.FakePVCs()
+			o := &CreateBackupOptions{
+				CreateOptions: action.CreateOptions{
+					IOStreams: streams,
+					Factory:   tf,
+					Name:      testing.ClusterName,
+					Namespace: testing.Namespace,
+					Client:    testing.FakeClientSet(&pvcs.Items[0], sc),
+				},
+				BackupSpec: appsv1alpha1.BackupSpec{
+					BackupPolicyName: policyName,
+					BackupMethod:     testing.BackupMethodName,
+				},
+			}
+			newDynamic := func(objects ...runtime.Object) *dynamicfakeclient.FakeDynamicClient {
+				return dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme,
+					map[schema.GroupVersionResource]string{types.VolumeSnapshotClassGVR(): "VolumeSnapshotClassList"},
+					append(objects, policy)...)
+			}
+			newVolumeSnapshotClass := func(driver string) *unstructured.Unstructured {
+				return &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "snapshot.storage.k8s.io/v1",
+					"kind":       "VolumeSnapshotClass",
+					"metadata":   map[string]interface{}{"name": "csi-snapclass-" + driver},
+					"driver":     driver,
+				}}
+			}
+
+			By("test without the volume snapshot CRDs")
+			dynamic := newDynamic()
+			dynamic.PrependReactor("list", "volumesnapshotclasses", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewNotFound(types.VolumeSnapshotClassGVR().GroupResource(), "")
+			})
+			o.Dynamic = dynamic
+			err := o.Validate()
+			Expect(err).Should(MatchError(ContainSubstring("the volume snapshot CRDs are not installed")))
+			Expect(clierrors.ExitCode(err)).Should(Equal(clierrors.ExitCodeValidation))
+
+			By("test without the VolumeSnapshotClass of the CSI driver")
+			o.Dynamic = newDynamic(newVolumeSnapshotClass("ebs.csi.aws.com"))
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("no VolumeSnapshotClass found for the CSI driver hostpath.csi.k8s.io")))
+
+			By("test with the VolumeSnapshotClass of the CSI driver")
+			o.Dynamic = newDynamic(newVolumeSnapshotClass(sc.Provisioner))
+			Expect(o.Validate()).Should(Succeed())
+
+			By("test with an unknown backup method")
+			o.BackupSpec.BackupMethod = "unknown"
+			Expect(o.Validate()).Should(MatchError(ContainSubstring("backup method unknown is not found")))
+		})
+
 		It("run backup command", func() {
 			defaultBackupPolicy := testing.FakeBackupPolicy(policyName, testing.ClusterName)
 			otherBackupPolicy := testing.FakeBackupPolicy("otherPolicy", testing.ClusterName)
@@ -195,10 +463,22 @@ var _ = Describe("DataProtection", func() {
 			err := o.Validate()
 			Expect(err).Should(Succeed())
 		})
+
+		It("backup cluster with the default backup policy and method", func() {
+			initClient(testing.FakeBackupPolicy(policyName, testing.ClusterName))
+			name, err := BackupCluster(tf, streams, testing.Namespace, testing.ClusterName)
+			Expect(err).Should(Succeed())
+			Expect(name).Should(HavePrefix(fmt.Sprintf("backup-%s-%s-", testing.Namespace, testing.ClusterName)))
+			ops := &appsv1alpha1.OpsRequest{}
+			Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, ops, types.OpsGVR(), testing.Namespace, name)).Should(Succeed())
+			Expect(ops.Spec.BackupSpec.BackupMethod).Should(Equal(testing.BackupMethodName))
+			Expect(ops.Spec.BackupSpec.BackupPolicyName).Should(Equal(policyName))
+		})
 	})
 
 	It("delete-backup", func() {
 		By("test delete-backup cmd")
+		tf.FakeDynamicClient = testing.FakeDynamicClient()
 		cmd := NewDeleteBackupCmd(tf, streams)
 		Expect(cmd).ShouldNot(BeNil())
 
@@ -207,18 +487,18 @@ var _ = Describe("DataProtection", func() {
 
 		By("test delete-backup with cluster")
 		o := action.NewDeleteOptions(tf, streams, types.BackupGVR())
-		Expect(completeForDeleteBackup(o, args)).Should(HaveOccurred())
+		Expect(completeForDeleteBackup(o, args, false)).Should(HaveOccurred())
 
 		By("test delete-backup with cluster and force")
 		o.Force = true
-		Expect(completeForDeleteBackup(o, args)).Should(Succeed())
+		Expect(completeForDeleteBackup(o, args, false)).Should(Succeed())
 		Expect(o.LabelSelector == clusterLabel).Should(BeTrue())
 
 		By("test delete-backup with cluster and force and labels")
 		o.Force = true
 		customLabel := "test=test"
 		o.LabelSelector = customLabel
-		Expect(completeForDeleteBackup(o, args)).Should(Succeed())
+		Expect(completeForDeleteBackup(o, args, false)).Should(Succeed())
 		Expect(o.LabelSelector == customLabel+","+clusterLabel).Should(BeTrue())
 	})
 
@@ -232,11 +512,10 @@ var _ = Describe("DataProtection", func() {
 		Expect(o.ErrOut.(*bytes.Buffer).String()).Should(ContainSubstring("No backups found"))
 
 		By("test list-backup")
-		backup1 := testing.FakeBackup("test1")
+		backup1 := testing.FakeBackupWithPhase("test1", dpv1alpha1.BackupPhaseRunning)
 		backup1.Labels = map[string]string{
 			constant.AppInstanceLabelKey: "apecloud-mysql",
 		}
-		backup1.Status.Phase = dpv1alpha1.BackupPhaseRunning
 		backup2 := testing.FakeBackup("test1")
 		backup2.Namespace = "backup"
 		tf.FakeDynamicClient = testing.FakeDynamicClient(backup1, backup2)
@@ -249,6 +528,32 @@ var _ = Describe("DataProtection", func() {
 		o.AllNamespaces = true
 		Expect(PrintBackupList(o)).Should(Succeed())
 		Expect(len(strings.Split(strings.Trim(o.Out.(*bytes.Buffer).String(), "\n"), "\n"))).Should(Equal(3))
+
+		By("test list sorted by the creation timestamp within the same minute")
+		o.Out.(*bytes.Buffer).Reset()
+		now := time.Now().Truncate(time.Minute)
+		backup3 := testing.FakeBackup("later")
+		backup3.CreationTimestamp = metav1.NewTime(now.Add(30 * time.Second))
+		backup4 := testing.FakeBackup("earlier")
+		backup4.CreationTimestamp = metav1.NewTime(now.Add(10 * time.Second))
+		tf.FakeDynamicClient = testing.FakeDynamicClient(backup3, backup4)
+		Expect(PrintBackupList(o)).Should(Succeed())
+		out := o.Out.(*bytes.Buffer).String()
+		Expect(strings.Index(out, "earlier")).Should(BeNumerically("<", strings.Index(out, "later")))
+		tf.FakeDynamicClient = testing.FakeDynamicClient(backup1, backup2)
+
+		By("test list in wide format with selected columns and no headers")
+		o.Out.(*bytes.Buffer).Reset()
+		o.Format = printer.Wide
+		o.TableOptions = printer.TableOptions{NoHeaders: true, Columns: []string{"NAME", "BACKUP-POLICY"}}
+		Expect(PrintBackupList(o)).Should(Succeed())
+		Expect(o.Out.(*bytes.Buffer).String()).Should(ContainSubstring(backup1.Spec.BackupPolicyName))
+		Expect(o.Out.(*bytes.Buffer).String()).ShouldNot(ContainSubstring("NAME"))
+		Expect(o.Out.(*bytes.Buffer).String()).ShouldNot(ContainSubstring("apecloud-mysql"))
+
+		By("test list with unknown column")
+		o.TableOptions.Columns = []string{"UNKNOWN"}
+		Expect(PrintBackupList(o)).Should(HaveOccurred())
 	})
 
 	It("restore", func() {
@@ -286,6 +591,75 @@ var _ = Describe("DataProtection", func() {
 		Expect(clusterObj.Spec.ComponentSpecs[0].Replicas).Should(Equal(int32(1)))
 	})
 
+	It("restore with resource overrides", func() {
+		sourceCluster := testing.FakeCluster("source-cluster", testing.Namespace)
+		sourceCluster.Spec.ComponentSpecs[0].ClassDefRef = &appsv1alpha1.ClassDefRef{Class: "general-1c1g"}
+		clusterJSON, err := json.Marshal(sourceCluster)
+		Expect(err).Should(Succeed())
+		backup := testing.FakeBackupWithPhase("backup-overrides", dpv1alpha1.BackupPhaseCompleted)
+		backup.Annotations = map[string]string{constant.ClusterSnapshotAnnotationKey: string(clusterJSON)}
+		tf.FakeDynamicClient = testing.FakeDynamicClient(backup, testing.FakeStorageClass("gp3", true))
+
+		newRestoreOptions := func() *CreateRestoreOptions {
+			o := &CreateRestoreOptions{}
+			o.CreateOptions = action.CreateOptions{IOStreams: streams, Factory: tf, Options: o, GVR: types.OpsGVR()}
+			o.Args = []string{"new-cluster"}
+			o.RestoreSpec.BackupName = backup.Name
+			Expect(o.Complete()).Should(Succeed())
+			return o
+		}
+
+		By("validate the overrides")
+		o := newRestoreOptions()
+		o.overrides = restoreOverrides{components: []string{"proxy"}, replicas: 3}
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("component proxy is not found")))
+		o.overrides = restoreOverrides{replicas: 3}
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("class not found")))
+		o.overrides = restoreOverrides{cpu: "1", storageClass: "not-exist"}
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("not-exist")))
+		o.overrides = restoreOverrides{cpu: "two"}
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("invalid --cpu")))
+
+		By("create the cluster with the overridden resources")
+		o = newRestoreOptions()
+		o.overrides = restoreOverrides{cpu: "2", memory: "4Gi", replicas: 3, storageClass: "gp3"}
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.Run()).Should(Succeed())
+		restored := &appsv1alpha1.Cluster{}
+		Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, restored, types.ClusterGVR(), testing.Namespace, "new-cluster")).Should(Succeed())
+		Expect(restored.Annotations).Should(HaveKey(constant.RestoreFromBackupAnnotationKey))
+		comp := restored.Spec.ComponentSpecs[0]
+		Expect(comp.Replicas).Should(Equal(int32(3)))
+		Expect(comp.ClassDefRef).Should(BeNil())
+		Expect(comp.Resources.Limits.Cpu().String()).Should(Equal("2"))
+		Expect(comp.Resources.Requests.Memory().String()).Should(Equal("4Gi"))
+		Expect(*comp.VolumeClaimTemplates[0].Spec.StorageClassName).Should(Equal("gp3"))
+		// the cluster is restored without the Restore OpsRequest
+		opsList, err := tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(opsList.Items).Should(BeEmpty())
+	})
+
+	It("wait for backup and restore", func() {
+		backup := testing.FakeBackupWithPhase("backup-wait", dpv1alpha1.BackupPhaseCompleted)
+		failedBackup := testing.FakeBackupWithPhase("backup-failed", dpv1alpha1.BackupPhaseFailed)
+		failedBackup.Status.FailureReason = "no space left"
+		clusterObj := testing.FakeCluster("cluster-wait", testing.Namespace)
+		clusterObj.Status.Phase = appsv1alpha1.RunningClusterPhase
+		dynamic := testing.FakeDynamicClient(backup, failedBackup, clusterObj)
+
+		By("the completed backup")
+		Expect(waitBackupCompleted(dynamic, testing.Namespace, backup.Name, time.Second, out)).Should(Succeed())
+
+		By("the failed backup")
+		Expect(waitBackupCompleted(dynamic, testing.Namespace, failedBackup.Name, time.Second, out)).Should(MatchError(ContainSubstring("no space left")))
+
+		By("the restored cluster")
+		out.Reset()
+		Expect(waitClusterRestored(dynamic, testing.Namespace, clusterObj.Name, time.Second, out, nil)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("Restore cluster cluster-wait"))
+	})
+
 	// It("restore-to-time", func() {
 	//	timestamp := time.Now().Format("20060102150405")
 	//	backupName := "backup-test-" + timestamp
@@ -373,18 +747,27 @@ var _ = Describe("DataProtection", func() {
 
 		By("test describe-backup")
 		backupName := "test1"
-		backup1 := testing.FakeBackup(backupName)
+		backup1 := testing.FakeBackupWithPhase(backupName, dpv1alpha1.BackupPhaseCompleted)
 		args = append(args, backupName)
-		backup1.Status.Phase = dpv1alpha1.BackupPhaseCompleted
-		logNow := metav1.Now()
-		backup1.Status.StartTimestamp = &logNow
-		backup1.Status.CompletionTimestamp = &logNow
-		backup1.Status.Expiration = &logNow
-		backup1.Status.Duration = &metav1.Duration{Duration: logNow.Sub(logNow.Time)}
 		tf.FakeDynamicClient = testing.FakeDynamicClient(backup1)
 		Expect(o.Complete(args)).Should(Succeed())
 		o.client = testing.FakeClientSet()
 		Expect(o.Run()).Should(Succeed())
+
+		By("test describe-backup with multiple backups and label selector")
+		backup2 := testing.FakeBackup("test2")
+		backup2.SetLabels(map[string]string{"env": "test"})
+		tf.FakeDynamicClient = testing.FakeDynamicClient(backup1, backup2)
+		Expect(o.Complete([]string{backupName, "test2"})).Should(Succeed())
+		o.client = testing.FakeClientSet()
+		Expect(o.Run()).Should(Succeed())
+		o.LabelSelector = "env=test"
+		Expect(o.Complete(nil)).Should(Succeed())
+		o.client = testing.FakeClientSet()
+		Expect(o.Run()).Should(Succeed())
+		o.LabelSelector = "env=none"
+		Expect(o.Run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("No backups found"))
 	})
 
 	It("describe-backup-policy", func() {
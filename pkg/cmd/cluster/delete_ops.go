@@ -65,7 +65,7 @@ func NewDeleteOpsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cob
 		},
 	}
 	cmd.Flags().StringSliceVar(&o.Names, "name", []string{}, "OpsRequest names")
-	_ = cmd.RegisterFlagCompletionFunc("name", util.ResourceNameCompletionFunc(f, types.OpsGVR()))
+	util.RegisterFlagCompletionFunc(cmd, "name", util.ClusterResourceCompletionFunc(f, types.OpsGVR()))
 	o.AddFlags(cmd)
 	return cmd
 }
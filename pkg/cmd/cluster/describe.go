@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -49,7 +50,13 @@ import (
 var (
 	describeExample = templates.Examples(`
 		# describe a specified cluster
-		kbcli cluster describe mycluster`)
+		kbcli cluster describe mycluster
+
+		# describe multiple clusters
+		kbcli cluster describe mycluster1 mycluster2
+
+		# describe the clusters matching the label selector
+		kbcli cluster describe -l env=test`)
 
 	newTbl = func(out io.Writer, title string, header ...interface{}) *printer.TablePrinter {
 		fmt.Fprintln(out, title)
@@ -69,6 +76,9 @@ type describeOptions struct {
 	gvr   schema.GroupVersionResource
 	names []string
 
+	labelSelector string
+	parallelism   int
+
 	*cluster.ClusterObjects
 	genericiooptions.IOStreams
 }
@@ -84,8 +94,8 @@ func newOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *describe
 func NewDescribeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
 	o := newOptions(f, streams)
 	cmd := &cobra.Command{
-		Use:               "describe NAME",
-		Short:             "Show details of a specific cluster.",
+		Use:               "describe NAME...",
+		Short:             "Show details of the specific clusters.",
 		Example:           describeExample,
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -93,14 +103,27 @@ func NewDescribeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 			util.CheckErr(o.run())
 		},
 	}
+	cmd.Flags().StringVarP(&o.labelSelector, "selector", "l", "", "Selector (label query) to filter the clusters to describe if no cluster name is specified, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2).")
+	cmd.Flags().IntVar(&o.parallelism, "parallelism", util.DefaultParallelism, "The number of clusters fetched concurrently.")
 	return cmd
 }
 
+// DescribeCluster describes the cluster in the specified namespace, it is used by
+// the callers that are not a command, such as the dashboard TUI.
+func DescribeCluster(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) error {
+	o := newOptions(f, streams)
+	if err := o.complete([]string{name}); err != nil {
+		return err
+	}
+	o.namespace = namespace
+	return o.run()
+}
+
 func (o *describeOptions) complete(args []string) error {
 	var err error
 
-	if len(args) == 0 {
-		return fmt.Errorf("cluster name should be specified")
+	if len(args) == 0 && o.labelSelector == "" {
+		return fmt.Errorf("cluster name or label selector should be specified")
 	}
 	o.names = args
 
@@ -118,16 +141,35 @@ func (o *describeOptions) complete(args []string) error {
 	return nil
 }
 
+// run fetches the objects of the clusters concurrently and describes them in the order of the names.
 func (o *describeOptions) run() error {
-	for _, name := range o.names {
-		if err := o.describeCluster(name); err != nil {
+	names := o.names
+	if len(names) == 0 {
+		var err error
+		if names, err = util.GetResourceNames(o.dynamic, o.gvr, o.namespace, o.labelSelector); err != nil {
 			return err
 		}
+		if len(names) == 0 {
+			fmt.Fprintf(o.Out, "No clusters found in %s namespace.\n", o.namespace)
+			return nil
+		}
+	}
+	clusterObjs, err := util.ParallelMap(names, o.parallelism, o.getClusterObjects)
+	if err != nil {
+		return err
+	}
+	defaultBackupRepo, err := o.getDefaultBackupRepo()
+	if err != nil {
+		return err
+	}
+	for _, objs := range clusterObjs {
+		o.ClusterObjects = objs
+		o.describeCluster(defaultBackupRepo)
 	}
 	return nil
 }
 
-func (o *describeOptions) describeCluster(name string) error {
+func (o *describeOptions) getClusterObjects(name string) (*cluster.ClusterObjects, error) {
 	clusterGetter := cluster.ObjectsGetter{
 		Client:    o.client,
 		Dynamic:   o.dynamic,
@@ -142,11 +184,10 @@ func (o *describeOptions) describeCluster(name string) error {
 		},
 	}
 
-	var err error
-	if o.ClusterObjects, err = clusterGetter.Get(); err != nil {
-		return err
-	}
+	return clusterGetter.Get()
+}
 
+func (o *describeOptions) describeCluster(defaultBackupRepo string) {
 	// cluster summary
 	showCluster(o.Cluster, o.Out)
 
@@ -164,17 +205,17 @@ func (o *describeOptions) describeCluster(name string) error {
 	showImages(comps, o.Out)
 
 	// data protection info
-	defaultBackupRepo, err := o.getDefaultBackupRepo()
-	if err != nil {
-		return err
-	}
 	showDataProtection(o.BackupPolicies, o.BackupSchedules, defaultBackupRepo, o.Out)
 
+	// TLS certificates
+	showTLS(o.client, o.Cluster, time.Now(), o.Out)
+
+	// probable causes correlated from the warning events, pod restarts and failed ops
+	showProbableCauses(diagnoseCluster(o.client, o.dynamic, o.ClusterObjects), o.Out)
+
 	// events
 	showEvents(o.Cluster.Name, o.Cluster.Namespace, o.Out)
 	fmt.Fprintln(o.Out)
-
-	return nil
 }
 
 func (o *describeOptions) getDefaultBackupRepo() (string, error) {
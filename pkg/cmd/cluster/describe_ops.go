@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -119,6 +120,17 @@ func getCommandFlagsSlice[T opsObject](opsSt []T,
 	return targetNameSlice, opsObjectSlice
 }
 
+// DescribeOps describes the OpsRequest in the specified namespace, it is used by
+// the callers that are not a command, such as the dashboard TUI.
+func DescribeOps(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) error {
+	o := newDescribeOpsOptions(f, streams)
+	if err := o.complete([]string{name}); err != nil {
+		return err
+	}
+	o.namespace = namespace
+	return o.run()
+}
+
 func (o *describeOpsOptions) complete(args []string) error {
 	var err error
 
@@ -176,6 +188,11 @@ func (o *describeOpsOptions) printOpsRequest(ops *appsv1alpha1.OpsRequest) error
 	// print the last configuration of the cluster.
 	o.printLastConfiguration(ops.Status.LastConfiguration, ops.Spec.Type)
 
+	// print the requested parameters alongside the values before the OpsRequest and on the cluster now.
+	if err := o.printOpsChanges(ops); err != nil {
+		return err
+	}
+
 	// print the OpsRequest.status
 	o.printOpsRequestStatus(&ops.Status)
 
@@ -191,6 +208,10 @@ func (o *describeOpsOptions) printOpsRequest(ops *appsv1alpha1.OpsRequest) error
 	// print the warning events
 	printer.PrintAllWarningEvents(events, o.Out)
 
+	// print the probable causes of the failed OpsRequest
+	if ops.Status.Phase == appsv1alpha1.OpsFailedPhase {
+		showProbableCauses(diagnoseOps(o.client, ops), o.Out)
+	}
 	return nil
 }
 
@@ -380,10 +401,187 @@ func (o *describeOpsOptions) printOpsRequestStatus(opsStatus *appsv1alpha1.OpsRe
 	if !startTime.IsZero() {
 		printer.PrintPairStringToLine("Duration", util.GetHumanReadableDuration(startTime, completeTime))
 	}
-	printer.PrintPairStringToLine("Status", string(opsStatus.Phase))
+	printer.PrintPairStringToLine("Status", printer.ColorStatus(string(opsStatus.Phase)))
+	o.printComponentsProgress(opsStatus)
 	o.printProgressDetails(opsStatus)
 }
 
+// printComponentsProgress prints the phase and the count of the finished objects of each component.
+func (o *describeOpsOptions) printComponentsProgress(opsStatus *appsv1alpha1.OpsRequestStatus) {
+	if len(opsStatus.Components) == 0 {
+		return
+	}
+	keys := maps.Keys(opsStatus.Components)
+	sort.Strings(keys)
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader(fmt.Sprintf("%-22s%s", "", "COMPONENT"), "PHASE", "PROGRESS", "FAILED", "MESSAGE")
+	for _, cName := range keys {
+		compStatus := opsStatus.Components[cName]
+		var succeed, failed int
+		for _, v := range compStatus.ProgressDetails {
+			switch v.Status {
+			case appsv1alpha1.SucceedProgressStatus:
+				succeed++
+			case appsv1alpha1.FailedProgressStatus:
+				failed++
+			}
+		}
+		progress := "-/-"
+		if len(compStatus.ProgressDetails) > 0 {
+			progress = fmt.Sprintf("%d/%d", succeed+failed, len(compStatus.ProgressDetails))
+		}
+		tbl.AddRow(fmt.Sprintf("%-22s%s", "", cName), string(compStatus.Phase), progress, failed, compStatus.Message)
+	}
+	tbl.Print()
+}
+
+// opsParameterChange is a parameter changed by the OpsRequest, with the value before the OpsRequest,
+// the value requested by the OpsRequest and the value on the cluster now.
+type opsParameterChange struct {
+	component string
+	parameter string
+	before    string
+	requested string
+	current   string
+}
+
+// printOpsChanges prints the parameters changed by the OpsRequest, the current values are empty if
+// the cluster is deleted.
+func (o *describeOpsOptions) printOpsChanges(ops *appsv1alpha1.OpsRequest) error {
+	c := &appsv1alpha1.Cluster{}
+	if err := cluster.GetK8SClientObject(o.dynamic, c, types.ClusterGVR(), ops.Namespace, ops.Spec.ClusterRef); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		c = nil
+	}
+	changes := getOpsParameterChanges(ops, c)
+	if len(changes) == 0 {
+		return nil
+	}
+	printer.PrintTitle("Changes")
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("COMPONENT", "PARAMETER", "BEFORE", "REQUESTED", "CURRENT")
+	for _, change := range changes {
+		tbl.AddRow(change.component, change.parameter, change.before, change.requested, change.current)
+	}
+	tbl.Print()
+	return nil
+}
+
+// getOpsParameterChanges returns the parameters changed by the OpsRequest, the values before the
+// OpsRequest are from the last configuration recorded in the status of the OpsRequest.
+func getOpsParameterChanges(ops *appsv1alpha1.OpsRequest, c *appsv1alpha1.Cluster) []opsParameterChange {
+	var (
+		changes []opsParameterChange
+		last    = ops.Status.LastConfiguration
+	)
+	valueOrNone := func(value string) string {
+		if value == "" {
+			return printer.NoneString
+		}
+		return value
+	}
+	getComp := func(name string) *appsv1alpha1.ClusterComponentSpec {
+		if c == nil {
+			return nil
+		}
+		return c.Spec.GetComponentByName(name)
+	}
+	quantity := func(list corev1.ResourceList, name corev1.ResourceName) string {
+		if q, ok := list[name]; ok {
+			return q.String()
+		}
+		return ""
+	}
+	switch ops.Spec.Type {
+	case appsv1alpha1.UpgradeType:
+		if ops.Spec.Upgrade == nil {
+			return nil
+		}
+		current := ""
+		if c != nil {
+			current = c.Spec.ClusterVersionRef
+		}
+		changes = append(changes, opsParameterChange{component: printer.NoneString, parameter: "clusterVersion",
+			before: valueOrNone(last.ClusterVersionRef), requested: ops.Spec.Upgrade.ClusterVersionRef, current: valueOrNone(current)})
+	case appsv1alpha1.HorizontalScalingType:
+		for _, h := range ops.Spec.HorizontalScalingList {
+			var before, current string
+			if replicas := last.Components[h.ComponentName].Replicas; replicas != nil {
+				before = fmt.Sprintf("%d", *replicas)
+			}
+			if comp := getComp(h.ComponentName); comp != nil {
+				current = fmt.Sprintf("%d", comp.Replicas)
+			}
+			changes = append(changes, opsParameterChange{component: h.ComponentName, parameter: "replicas",
+				before: valueOrNone(before), requested: fmt.Sprintf("%d", h.Replicas), current: valueOrNone(current)})
+		}
+	case appsv1alpha1.VerticalScalingType:
+		for _, v := range ops.Spec.VerticalScalingList {
+			lastComp := last.Components[v.ComponentName]
+			comp := getComp(v.ComponentName)
+			if v.ClassDefRef != nil {
+				var before, current string
+				if lastComp.ClassDefRef != nil {
+					before = lastComp.ClassDefRef.Class
+				}
+				if comp != nil && comp.ClassDefRef != nil {
+					current = comp.ClassDefRef.Class
+				}
+				changes = append(changes, opsParameterChange{component: v.ComponentName, parameter: "class",
+					before: valueOrNone(before), requested: v.ClassDefRef.Class, current: valueOrNone(current)})
+				continue
+			}
+			for _, r := range []struct {
+				parameter string
+				requested corev1.ResourceList
+				before    corev1.ResourceList
+				current   func(spec *appsv1alpha1.ClusterComponentSpec) corev1.ResourceList
+				name      corev1.ResourceName
+			}{
+				{"requests.cpu", v.Requests, lastComp.Requests, func(s *appsv1alpha1.ClusterComponentSpec) corev1.ResourceList { return s.Resources.Requests }, corev1.ResourceCPU},
+				{"requests.memory", v.Requests, lastComp.Requests, func(s *appsv1alpha1.ClusterComponentSpec) corev1.ResourceList { return s.Resources.Requests }, corev1.ResourceMemory},
+				{"limits.cpu", v.Limits, lastComp.Limits, func(s *appsv1alpha1.ClusterComponentSpec) corev1.ResourceList { return s.Resources.Limits }, corev1.ResourceCPU},
+				{"limits.memory", v.Limits, lastComp.Limits, func(s *appsv1alpha1.ClusterComponentSpec) corev1.ResourceList { return s.Resources.Limits }, corev1.ResourceMemory},
+			} {
+				requested := quantity(r.requested, r.name)
+				if requested == "" {
+					continue
+				}
+				var current string
+				if comp != nil {
+					current = quantity(r.current(comp), r.name)
+				}
+				changes = append(changes, opsParameterChange{component: v.ComponentName, parameter: r.parameter,
+					before: valueOrNone(quantity(r.before, r.name)), requested: requested, current: valueOrNone(current)})
+			}
+		}
+	case appsv1alpha1.VolumeExpansionType:
+		for _, v := range ops.Spec.VolumeExpansionList {
+			comp := getComp(v.ComponentName)
+			for _, vct := range v.VolumeClaimTemplates {
+				var before, current string
+				for _, lastVCT := range last.Components[v.ComponentName].VolumeClaimTemplates {
+					if lastVCT.Name == vct.Name {
+						before = lastVCT.Storage.String()
+					}
+				}
+				if comp != nil {
+					for _, compVCT := range comp.VolumeClaimTemplates {
+						if compVCT.Name == vct.Name {
+							current = quantity(compVCT.Spec.Resources.Requests, corev1.ResourceStorage)
+						}
+					}
+				}
+				changes = append(changes, opsParameterChange{component: v.ComponentName, parameter: "storage(" + vct.Name + ")",
+					before: valueOrNone(before), requested: vct.Storage.String(), current: valueOrNone(current)})
+			}
+		}
+	}
+	return changes
+}
+
 // printLastConfiguration prints the last configuration of the cluster before doing the OpsRequest.
 func (o *describeOpsOptions) printLastConfiguration(configuration appsv1alpha1.LastConfiguration, opsType appsv1alpha1.OpsType) {
 	if reflect.DeepEqual(configuration, appsv1alpha1.LastConfiguration{}) {
@@ -41,6 +41,7 @@ import (
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 
+	"github.com/apecloud/kbcli/pkg/printer"
 	clitesting "github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 )
@@ -307,4 +308,52 @@ var _ = Describe("Expose", func() {
 		}, appsv1alpha1.VolumeExpansionType, "VOLUME-CLAIM-TEMPLATE", "STORAGE", "data", "2Gi", "log")
 
 	})
+
+	It("parameter changes and components progress", func() {
+		c := clitesting.FakeCluster("test-cluster", namespace)
+		comp := &c.Spec.ComponentSpecs[0]
+		comp.Name = componentName
+		comp.Replicas = 3
+		comp.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("2")}
+
+		By("the replicas of HorizontalScaling")
+		ops := generateOpsObject(opsName, appsv1alpha1.HorizontalScalingType)
+		ops.Spec.HorizontalScalingList = []appsv1alpha1.HorizontalScaling{
+			{ComponentOps: appsv1alpha1.ComponentOps{ComponentName: componentName}, Replicas: 3},
+		}
+		replicas := int32(1)
+		ops.Status.LastConfiguration.Components = map[string]appsv1alpha1.LastComponentConfiguration{
+			componentName: {Replicas: &replicas},
+		}
+		Expect(getOpsParameterChanges(ops, c)).Should(Equal([]opsParameterChange{
+			{component: componentName, parameter: "replicas", before: "1", requested: "3", current: "3"},
+		}))
+
+		By("the resources of VerticalScaling, the current values are none if the cluster is deleted")
+		ops = generateOpsObject(opsName, appsv1alpha1.VerticalScalingType)
+		ops.Spec.VerticalScalingList = []appsv1alpha1.VerticalScaling{
+			{
+				ComponentOps: appsv1alpha1.ComponentOps{ComponentName: componentName},
+				ResourceRequirements: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: apiresource.MustParse("2")},
+				},
+			},
+		}
+		Expect(getOpsParameterChanges(ops, c)).Should(Equal([]opsParameterChange{
+			{component: componentName, parameter: "limits.cpu", before: printer.NoneString, requested: "2", current: "2"},
+		}))
+		Expect(getOpsParameterChanges(ops, nil)[0].current).Should(Equal(printer.NoneString))
+
+		By("print the changes and the progress of the components")
+		ops.Status = fakeOpsStatusAndProgress()
+		tf.FakeDynamicClient = clitesting.FakeDynamicClient(ops, c)
+		o := newDescribeOpsOptions(tf, streams)
+		Expect(o.complete([]string{opsName})).Should(Succeed())
+		Expect(o.printOpsChanges(ops)).Should(Succeed())
+		o.printComponentsProgress(&ops.Status)
+		out := o.Out.(*bytes.Buffer).String()
+		Expect(out).Should(ContainSubstring("REQUESTED"))
+		Expect(out).Should(ContainSubstring("limits.cpu"))
+		Expect(out).Should(MatchRegexp(componentName + `\s+Failed\s+2/2\s+1`))
+	})
 })
@@ -98,6 +98,10 @@ var _ = Describe("Expose", func() {
 		Expect(o.client).ShouldNot(BeNil())
 		Expect(o.dynamic).ShouldNot(BeNil())
 		Expect(o.namespace).Should(Equal(namespace))
+
+		o = newOptions(tf, streams)
+		o.labelSelector = "env=test"
+		Expect(o.complete(nil)).Should(Succeed())
 	})
 
 	It("run", func() {
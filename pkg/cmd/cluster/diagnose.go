@@ -0,0 +1,174 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// diagnoseCluster correlates the warning events, pod restarts and failed OpsRequests of the
+// cluster into the probable causes. The diagnosis is best effort, the objects failed to get
+// are skipped instead of failing the describe command.
+func diagnoseCluster(client clientset.Interface, dynamic dynamic.Interface, objs *cluster.ClusterObjects) []cluster.ProbableCause {
+	if objs == nil || objs.Cluster == nil {
+		return nil
+	}
+	c := objs.Cluster
+	diagObjs := &cluster.DiagnoseObjects{StorageClasses: listStorageClasses(client)}
+	involved := map[string]bool{"Cluster/" + c.Name: true}
+	if objs.Pods != nil {
+		diagObjs.Pods = objs.Pods.Items
+		for _, pod := range objs.Pods.Items {
+			involved["Pod/"+pod.Name] = true
+		}
+	}
+	if objs.PVCs != nil {
+		diagObjs.PVCs = objs.PVCs.Items
+		for _, pvc := range objs.PVCs.Items {
+			involved["PersistentVolumeClaim/"+pvc.Name] = true
+		}
+	}
+
+	opsList, err := dynamic.Resource(types.OpsGVR()).Namespace(c.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, c.Name),
+	})
+	if err != nil {
+		klog.V(1).Infof("failed to list the OpsRequests of cluster %s: %v", c.Name, err)
+	} else {
+		for _, item := range opsList.Items {
+			ops := appsv1alpha1.OpsRequest{}
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &ops); err != nil {
+				continue
+			}
+			diagObjs.OpsRequests = append(diagObjs.OpsRequests, ops)
+			involved["OpsRequest/"+ops.Name] = true
+		}
+	}
+
+	diagObjs.Events = listWarningEvents(client, c.Namespace, involved)
+	return cluster.Diagnose(diagObjs)
+}
+
+// diagnoseBackup correlates the warning events of the backup and its job pods, and the
+// failure reason of the backup into the probable causes.
+func diagnoseBackup(client clientset.Interface, backup *dpv1alpha1.Backup) []cluster.ProbableCause {
+	diagObjs := &cluster.DiagnoseObjects{Backups: []dpv1alpha1.Backup{*backup}}
+	involved := map[string]bool{"Backup/" + backup.Name: true}
+	pods, err := client.CoreV1().Pods(backup.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", dptypes.BackupNameLabelKey, backup.Name),
+	})
+	if err != nil {
+		klog.V(1).Infof("failed to list the pods of backup %s: %v", backup.Name, err)
+	} else {
+		diagObjs.Pods = pods.Items
+		for _, pod := range pods.Items {
+			involved["Pod/"+pod.Name] = true
+		}
+	}
+	diagObjs.Events = listWarningEvents(client, backup.Namespace, involved)
+	return cluster.Diagnose(diagObjs)
+}
+
+// diagnoseOps correlates the warning events of the OpsRequest, its cluster and the objects failed to
+// process, and the status of the failed pods into the probable causes.
+func diagnoseOps(client clientset.Interface, ops *appsv1alpha1.OpsRequest) []cluster.ProbableCause {
+	diagObjs := &cluster.DiagnoseObjects{}
+	involved := map[string]bool{"OpsRequest/" + ops.Name: true, "Cluster/" + ops.Spec.ClusterRef: true}
+	for _, compStatus := range ops.Status.Components {
+		for _, v := range compStatus.ProgressDetails {
+			if v.Status != appsv1alpha1.FailedProgressStatus {
+				continue
+			}
+			involved[v.ObjectKey] = true
+			kind, name, found := strings.Cut(v.ObjectKey, "/")
+			if !found || kind != constant.PodKind {
+				continue
+			}
+			pod, err := client.CoreV1().Pods(ops.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				klog.V(1).Infof("failed to get the pod %s of OpsRequest %s: %v", name, ops.Name, err)
+				continue
+			}
+			diagObjs.Pods = append(diagObjs.Pods, *pod)
+		}
+	}
+	diagObjs.Events = listWarningEvents(client, ops.Namespace, involved)
+	return cluster.Diagnose(diagObjs)
+}
+
+// listWarningEvents lists the warning events in the namespace whose involved objects
+// in the format of kind/name are in the involved set.
+func listWarningEvents(client clientset.Interface, namespace string, involved map[string]bool) []corev1.Event {
+	events, err := client.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "type=" + corev1.EventTypeWarning,
+	})
+	if err != nil {
+		klog.V(1).Infof("failed to list the warning events in namespace %s: %v", namespace, err)
+		return nil
+	}
+	var result []corev1.Event
+	for _, e := range events.Items {
+		if involved[e.InvolvedObject.Kind+"/"+e.InvolvedObject.Name] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// listStorageClasses returns nil if the storage classes can not be listed, so the
+// checks depending on them are skipped
+func listStorageClasses(client clientset.Interface) []storagev1.StorageClass {
+	classes, err := client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		klog.V(1).Infof("failed to list the storage classes: %v", err)
+		return nil
+	}
+	return classes.Items
+}
+
+func showProbableCauses(causes []cluster.ProbableCause, out io.Writer) {
+	if len(causes) == 0 {
+		return
+	}
+	tbl := newTbl(out, "\nProbable Causes:", "OBJECT", "CAUSE", "SUGGESTION")
+	for _, c := range causes {
+		tbl.AddRow(c.Object, c.Cause, c.Suggestion)
+	}
+	tbl.Print()
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/cmd/util/editor"
+	"sigs.k8s.io/yaml"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	// editModeKeyValue edits the fields of the backup policy formatted as key=value
+	editModeKeyValue = "key-value"
+	// editModeYAML edits the full spec of the backup policy in YAML
+	editModeYAML = "yaml"
+)
+
+// launchYAMLEditor opens the content in the editor and returns the edited content
+func launchYAMLEditor(name string, content []byte) ([]byte, error) {
+	e := editor.NewDefaultEditor([]string{
+		"KUBE_EDITOR",
+		"EDITOR",
+	})
+	edited, _, err := e.LaunchTempFile(fmt.Sprintf("%s-edit-", name), ".yaml", bytes.NewBuffer(content))
+	return edited, err
+}
+
+// buildYAMLEditorContent builds the content of the editor with the errors of the previous edit as comments
+func buildYAMLEditorContent(name string, spec []byte, editErr error) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `# Please edit the spec of backup policy %s below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this file will be
+# reopened with the relevant failures.
+#
+`, name)
+	if editErr != nil {
+		fmt.Fprintf(buf, "# The edited spec is invalid:\n")
+		for _, line := range strings.Split(strings.TrimSpace(editErr.Error()), "\n") {
+			fmt.Fprintf(buf, "# * %s\n", line)
+		}
+		fmt.Fprintf(buf, "#\n")
+	}
+	buf.Write(spec)
+	return buf.Bytes()
+}
+
+// validateBackupPolicySpec validates the edited spec of the backup policy before updating it
+func (o *editBackupPolicyOptions) validateBackupPolicySpec(spec *dpv1alpha1.BackupPolicySpec) error {
+	if spec.Target == nil {
+		return fmt.Errorf("target is required")
+	}
+	if len(spec.BackupMethods) == 0 {
+		return fmt.Errorf("at least one backup method is required")
+	}
+	names := map[string]bool{}
+	for _, m := range spec.BackupMethods {
+		if m.Name == "" {
+			return fmt.Errorf("the name of the backup method is required")
+		}
+		if names[m.Name] {
+			return fmt.Errorf("backup method %s is duplicated", m.Name)
+		}
+		names[m.Name] = true
+		if m.ActionSetName == "" {
+			if !boolptr.IsSetToTrue(m.SnapshotVolumes) {
+				return fmt.Errorf("backup method %s should specify the actionSetName or enable snapshotVolumes", m.Name)
+			}
+			continue
+		}
+		if _, err := o.dynamic.Resource(types.ActionSetGVR()).Get(context.TODO(), m.ActionSetName, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("the ActionSet %s of backup method %s is invalid: %v", m.ActionSetName, m.Name, err)
+		}
+	}
+	if spec.BackupRepoName != nil && *spec.BackupRepoName != "" {
+		if _, err := o.dynamic.Resource(types.BackupRepoGVR()).Get(context.TODO(), *spec.BackupRepoName, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("the backup repo %s is invalid: %v", *spec.BackupRepoName, err)
+		}
+	}
+	return nil
+}
+
+// runYAMLEditor edits the full spec of the backup policy in YAML. Like kubectl edit, the editor is
+// reopened with the errors if the edited spec is invalid, and the edit is cancelled if the spec is
+// saved without any change after an error.
+func (o *editBackupPolicyOptions) runYAMLEditor(backupPolicy *dpv1alpha1.BackupPolicy) error {
+	launch := o.launchEditor
+	if launch == nil {
+		launch = launchYAMLEditor
+	}
+	original, err := yaml.Marshal(backupPolicy.Spec)
+	if err != nil {
+		return err
+	}
+
+	var (
+		edited  = original
+		editErr error
+	)
+	for {
+		result, err := launch(backupPolicy.Name, buildYAMLEditorContent(backupPolicy.Name, edited, editErr))
+		if err != nil {
+			return err
+		}
+		stripped := cmdutil.ManualStrip(result)
+		if len(bytes.TrimSpace(stripped)) == 0 {
+			fmt.Fprintln(o.Out, "Edit cancelled, saved file was empty.")
+			return nil
+		}
+		if editErr != nil && bytes.Equal(stripped, cmdutil.ManualStrip(edited)) {
+			return clierrors.NewValidation("edit cancelled, no valid changes were saved: %v", editErr)
+		}
+		edited = stripped
+
+		spec := dpv1alpha1.BackupPolicySpec{}
+		if err = yaml.UnmarshalStrict(stripped, &spec); err != nil {
+			editErr = fmt.Errorf("failed to parse the spec: %v", err)
+			continue
+		}
+		normalized, err := yaml.Marshal(spec)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(normalized, original) {
+			fmt.Fprintln(o.Out, "Edit cancelled, no changes made.")
+			return nil
+		}
+		if editErr = o.validateBackupPolicySpec(&spec); editErr != nil {
+			continue
+		}
+
+		diff, err := util.GetUnifiedDiffString(string(original), string(normalized), "Original", "Current", 3)
+		if err != nil {
+			return err
+		}
+		util.DisplayDiffWithColor(o.Out, diff)
+		backupPolicy.Spec = spec
+		editErr = o.updateBackupPolicy(backupPolicy)
+		// the spec rejected by the API server is reopened to fix it
+		if apierrors.IsInvalid(editErr) || apierrors.IsBadRequest(editErr) {
+			continue
+		}
+		return editErr
+	}
+}
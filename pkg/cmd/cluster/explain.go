@@ -0,0 +1,292 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var explainExample = templates.Examples(`
+	# show the fields of the cluster spec
+	kbcli cluster explain cluster.spec
+
+	# show the field docs of the component specs of the cluster
+	kbcli cluster explain cluster.spec.componentSpecs
+
+	# show all the fields of the OpsRequest spec recursively
+	kbcli cluster explain opsrequest.spec --recursive
+
+	# show the backup methods of the backup policy
+	kbcli cluster explain backuppolicy.spec.backupMethods`)
+
+// explainDescriptionWidth is the width to wrap the descriptions of the fields
+const explainDescriptionWidth = 80
+
+// explainResource is a KubeBlocks resource whose schema can be explained
+type explainResource struct {
+	names []string
+	gvr   schema.GroupVersionResource
+}
+
+var explainResources = []explainResource{
+	{names: []string{"cluster", "clusters"}, gvr: types.ClusterGVR()},
+	{names: []string{"opsrequest", "opsrequests", "ops"}, gvr: types.OpsGVR()},
+	{names: []string{"backuppolicy", "backuppolicies"}, gvr: types.BackupPolicyGVR()},
+}
+
+type explainOptions struct {
+	factory   cmdutil.Factory
+	dynamic   dynamic.Interface
+	resource  explainResource
+	path      []string
+	recursive bool
+
+	genericiooptions.IOStreams
+}
+
+func NewExplainCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &explainOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "explain RESOURCE[.FIELD...]",
+		Short:   "Show the documentation of the fields of the Cluster, OpsRequest and BackupPolicy.",
+		Example: explainExample,
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return explainResourceNames(), cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.recursive, "recursive", false, "Show the names and types of all the nested fields")
+	return cmd
+}
+
+func explainResourceNames() []string {
+	var names []string
+	for _, r := range explainResources {
+		names = append(names, r.names[0])
+	}
+	return names
+}
+
+func (o *explainOptions) complete(args []string) error {
+	var err error
+	parts := strings.Split(args[0], ".")
+	found := false
+	for _, r := range explainResources {
+		for _, name := range r.names {
+			if strings.EqualFold(name, parts[0]) {
+				o.resource, found = r, true
+			}
+		}
+	}
+	if !found {
+		return clierrors.NewValidation("unsupported resource %s, should be one of %s", parts[0], strings.Join(explainResourceNames(), ", "))
+	}
+	for _, p := range parts[1:] {
+		if p == "" {
+			return clierrors.NewValidation("invalid field path %s", args[0])
+		}
+	}
+	o.path = parts[1:]
+	if o.dynamic == nil {
+		if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *explainOptions) run() error {
+	crd := &apiextv1.CustomResourceDefinition{}
+	crdName := o.resource.gvr.Resource + "." + o.resource.gvr.Group
+	if err := util.GetResourceObjectFromGVR(types.CustomResourceDefinitionGVR(), client.ObjectKey{Name: crdName}, o.dynamic, crd); err != nil {
+		return fmt.Errorf("failed to get the CRD %s, make sure KubeBlocks is installed: %w", crdName, err)
+	}
+	version := getCRDVersion(crd, o.resource.gvr.Version)
+	if version == nil || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return fmt.Errorf("no schema found in the CRD %s", crdName)
+	}
+
+	// walk to the field of the path
+	fieldSchema := version.Schema.OpenAPIV3Schema
+	for i, name := range o.path {
+		props := fieldProperties(fieldSchema)
+		child, ok := props[name]
+		if !ok {
+			return clierrors.NewNotFoundf("field %s does not exist in %s", name, strings.Join(append([]string{crd.Spec.Names.Singular}, o.path[:i]...), "."))
+		}
+		fieldSchema = &child
+	}
+
+	fmt.Fprintf(o.Out, "KIND:     %s\n", crd.Spec.Names.Kind)
+	fmt.Fprintf(o.Out, "VERSION:  %s/%s\n\n", crd.Spec.Group, version.Name)
+	if len(o.path) > 0 {
+		fmt.Fprintf(o.Out, "FIELD:    %s <%s>\n\n", o.path[len(o.path)-1], schemaTypeName(fieldSchema))
+	}
+	fmt.Fprintln(o.Out, "DESCRIPTION:")
+	description := fieldSchema.Description
+	if description == "" {
+		description = "<empty>"
+	}
+	writeWrapped(o.Out, description, 5)
+
+	props := fieldProperties(fieldSchema)
+	if len(props) == 0 {
+		return nil
+	}
+	fmt.Fprintln(o.Out, "\nFIELDS:")
+	if o.recursive {
+		printFieldsRecursively(o.Out, fieldSchema, 3)
+		return nil
+	}
+	required := requiredFields(fieldSchema)
+	for _, name := range sortedFieldNames(props) {
+		prop := props[name]
+		line := fmt.Sprintf("   %s\t<%s>", name, schemaTypeName(&prop))
+		if required[name] {
+			line += " -required-"
+		}
+		fmt.Fprintln(o.Out, line)
+		desc := prop.Description
+		if desc == "" {
+			desc = "<no description>"
+		}
+		writeWrapped(o.Out, desc, 5)
+		fmt.Fprintln(o.Out)
+	}
+	return nil
+}
+
+// getCRDVersion returns the version of the CRD, or the storage version if the version is not served
+func getCRDVersion(crd *apiextv1.CustomResourceDefinition, name string) *apiextv1.CustomResourceDefinitionVersion {
+	var storage *apiextv1.CustomResourceDefinitionVersion
+	for i := range crd.Spec.Versions {
+		v := &crd.Spec.Versions[i]
+		if v.Name == name && v.Served {
+			return v
+		}
+		if v.Storage {
+			storage = v
+		}
+	}
+	return storage
+}
+
+// fieldProperties returns the properties of the object, or the properties of the items of the array
+func fieldProperties(s *apiextv1.JSONSchemaProps) map[string]apiextv1.JSONSchemaProps {
+	if s.Type == "array" && s.Items != nil && s.Items.Schema != nil {
+		return s.Items.Schema.Properties
+	}
+	return s.Properties
+}
+
+func requiredFields(s *apiextv1.JSONSchemaProps) map[string]bool {
+	required := s.Required
+	if s.Type == "array" && s.Items != nil && s.Items.Schema != nil {
+		required = s.Items.Schema.Required
+	}
+	res := map[string]bool{}
+	for _, name := range required {
+		res[name] = true
+	}
+	return res
+}
+
+func sortedFieldNames(props map[string]apiextv1.JSONSchemaProps) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaTypeName returns the type name of the field like kubectl explain, such as string,
+// []Object and map[string]string.
+func schemaTypeName(s *apiextv1.JSONSchemaProps) string {
+	switch {
+	case s.XIntOrString:
+		return "IntOrString"
+	case s.Type == "array":
+		if s.Items != nil && s.Items.Schema != nil {
+			return "[]" + schemaTypeName(s.Items.Schema)
+		}
+		return "[]Object"
+	case s.Type == "object" && len(s.Properties) == 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+		return "map[string]" + schemaTypeName(s.AdditionalProperties.Schema)
+	case s.Type == "object" || s.Type == "":
+		return "Object"
+	}
+	return s.Type
+}
+
+// printFieldsRecursively prints the names and types of the nested fields with indents
+func printFieldsRecursively(out io.Writer, s *apiextv1.JSONSchemaProps, indent int) {
+	props := fieldProperties(s)
+	for _, name := range sortedFieldNames(props) {
+		prop := props[name]
+		fmt.Fprintf(out, "%s%s\t<%s>\n", strings.Repeat(" ", indent), name, schemaTypeName(&prop))
+		printFieldsRecursively(out, &prop, indent+3)
+	}
+}
+
+// writeWrapped writes the text wrapped by words with the indent
+func writeWrapped(out io.Writer, text string, indent int) {
+	prefix := strings.Repeat(" ", indent)
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(prefix)+len(line)+1+len(word) > explainDescriptionWidth {
+				fmt.Fprintln(out, prefix+line)
+				line = ""
+			}
+			if line == "" {
+				line = word
+			} else {
+				line += " " + word
+			}
+		}
+		if line != "" {
+			fmt.Fprintln(out, prefix+line)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("explain", func() {
+	var (
+		out *bytes.Buffer
+		o   *explainOptions
+	)
+
+	BeforeEach(func() {
+		gvr := types.ClusterGVR()
+		crd := &apiextv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: gvr.Resource + "." + gvr.Group},
+			Spec: apiextv1.CustomResourceDefinitionSpec{
+				Group: gvr.Group,
+				Names: apiextv1.CustomResourceDefinitionNames{Kind: types.KindCluster, Singular: "cluster"},
+				Versions: []apiextv1.CustomResourceDefinitionVersion{{
+					Name: gvr.Version, Served: true, Storage: true,
+					Schema: &apiextv1.CustomResourceValidation{OpenAPIV3Schema: &apiextv1.JSONSchemaProps{
+						Type:        "object",
+						Description: "Cluster is the Schema for the clusters API.",
+						Properties: map[string]apiextv1.JSONSchemaProps{
+							"spec": {
+								Type:        "object",
+								Description: "ClusterSpec defines the desired state of Cluster.",
+								Required:    []string{"terminationPolicy"},
+								Properties: map[string]apiextv1.JSONSchemaProps{
+									"terminationPolicy": {Type: "string", Description: "Cluster termination policy."},
+									"componentSpecs": {
+										Type:        "array",
+										Description: "List of componentSpecs you want to replace.",
+										Items: &apiextv1.JSONSchemaPropsOrArray{Schema: &apiextv1.JSONSchemaProps{
+											Type: "object",
+											Properties: map[string]apiextv1.JSONSchemaProps{
+												"name":     {Type: "string", Description: "name defines cluster's component name."},
+												"replicas": {Type: "integer", Description: "Component replicas."},
+											},
+										}},
+									},
+								},
+							},
+						},
+					}},
+				}},
+			},
+		}
+		out = &bytes.Buffer{}
+		o = &explainOptions{
+			dynamic:   testing.FakeDynamicClient(crd),
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	It("explain the fields", func() {
+		Expect(o.complete([]string{"cluster.spec"})).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("KIND:     Cluster"))
+		Expect(out.String()).Should(ContainSubstring("ClusterSpec defines the desired state of Cluster."))
+		Expect(out.String()).Should(ContainSubstring("componentSpecs\t<[]Object>"))
+		Expect(out.String()).Should(ContainSubstring("terminationPolicy\t<string> -required-"))
+
+		By("explain the fields of the array items")
+		out.Reset()
+		Expect(o.complete([]string{"clusters.spec.componentSpecs"})).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("FIELD:    componentSpecs <[]Object>"))
+		Expect(out.String()).Should(ContainSubstring("replicas\t<integer>"))
+
+		By("explain the fields recursively")
+		out.Reset()
+		o.recursive = true
+		Expect(o.complete([]string{"cluster"})).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("         name\t<string>"))
+	})
+
+	It("explain the invalid fields", func() {
+		Expect(clierrors.IsValidation(o.complete([]string{"pod.spec"}))).Should(BeTrue())
+		Expect(clierrors.IsValidation(o.complete([]string{"cluster..spec"}))).Should(BeTrue())
+
+		Expect(o.complete([]string{"cluster.spec.unknown"})).Should(Succeed())
+		Expect(o.run()).Should(MatchError(ContainSubstring("field unknown does not exist in cluster.spec")))
+
+		By("the CRD is not installed")
+		Expect(o.complete([]string{"ops.spec"})).Should(Succeed())
+		Expect(o.run()).Should(MatchError(ContainSubstring("make sure KubeBlocks is installed")))
+	})
+
+	It("wrap the description", func() {
+		buf := &bytes.Buffer{}
+		writeWrapped(buf, "a b c\nd", 2)
+		Expect(buf.String()).Should(Equal("  a b c\n  d\n"))
+	})
+})
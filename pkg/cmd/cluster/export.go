@@ -0,0 +1,470 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/cmd/accounts"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	exportFormatHelm      = "helm"
+	exportFormatKustomize = "kustomize"
+
+	// exportNamePlaceholder replaces the cluster name in the names and labels of the exported
+	// resources of the helm chart, it's rendered by the tpl function.
+	exportNamePlaceholder = "{{ .Values.name }}"
+
+	// exportPatchesDir is the directory of the patches of the resources managed by KubeBlocks
+	exportPatchesDir = "patches"
+)
+
+var exportExample = templates.Examples(`
+	# Export cluster mycluster as a helm chart in the directory mycluster-helm
+	kbcli cluster export mycluster --format helm
+
+	# Recreate the cluster with another name in another namespace by the exported chart
+	helm install mycluster2 ./mycluster-helm --set name=mycluster2 -n other
+
+	# Apply the modified spec of the backup policies and backup schedules managed by KubeBlocks after the cluster is recreated
+	kubectl apply --server-side --force-conflicts -f ./mycluster-helm/patches -n other
+
+	# Export cluster mycluster as a kustomize base and an overlay of its namespace in the directory ./deploy
+	kbcli cluster export mycluster --format kustomize --output-dir ./deploy
+
+	# Include the Secrets synchronized with the account credentials
+	kbcli cluster export mycluster --format helm --include-secrets`)
+
+// exportHelmClusterTemplate renders the cluster from the values, so the spec can be customized
+// by the values files or --set.
+const exportHelmClusterTemplate = `apiVersion: {{ .Values.apiVersion }}
+kind: Cluster
+metadata:
+  name: {{ .Values.name }}
+  namespace: {{ .Release.Namespace }}
+  {{- with .Values.labels }}
+  labels:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+  {{- with .Values.annotations }}
+  annotations:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+spec:
+  {{- toYaml .Values.spec | nindent 2 }}
+`
+
+// exportHelmResourcesTemplate renders the resources related to the cluster, the cluster name in
+// them is replaced by the name in the values.
+const exportHelmResourcesTemplate = `{{- range .Values.resources }}
+---
+{{ tpl (toYaml .) $ }}
+{{- end }}
+`
+
+// exportResourceKind is a kind of resource exported with the cluster, the resources are selected
+// by the instance label of the cluster.
+type exportResourceKind struct {
+	gvr func() schema.GroupVersionResource
+	// include returns false if the resource is managed by KubeBlocks and recreated with the cluster
+	include func(obj *unstructured.Unstructured) bool
+	// patchModified exports the spec of the skipped resources modified after they are created as
+	// patches, which are applied on top of the ones recreated by the controller
+	patchModified bool
+}
+
+// exportResourceKinds are the kinds exported with the cluster, the backup policies, backup schedules
+// and services created by KubeBlocks for the cluster are skipped, or they would conflict with the
+// ones created by the controller for the recreated cluster. The customizations of the backup policies
+// and backup schedules are kept by the patches.
+var exportResourceKinds = []exportResourceKind{
+	{gvr: types.BackupPolicyGVR, include: notManagedByKubeBlocks, patchModified: true},
+	{gvr: types.BackupScheduleGVR, include: notManagedByKubeBlocks, patchModified: true},
+	{gvr: types.ServiceGVR, include: notManagedByKubeBlocks},
+}
+
+// notManagedByKubeBlocks returns true if the resource is not created by KubeBlocks
+func notManagedByKubeBlocks(obj *unstructured.Unstructured) bool {
+	return obj.GetLabels()[constant.AppManagedByLabelKey] != constant.AppName
+}
+
+// buildExportPatch returns the patch of the spec if the resource is modified after it's created,
+// the generation is increased by every change of the spec.
+func buildExportPatch(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	spec, ok := obj.Object["spec"]
+	if !ok || obj.GetGeneration() <= 1 {
+		return nil
+	}
+	patch := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	patch.SetAPIVersion(obj.GetAPIVersion())
+	patch.SetKind(obj.GetKind())
+	patch.SetName(obj.GetName())
+	return patch
+}
+
+// exportSecretKind are the Secrets synchronized with the account credentials, they are exported
+// only if --include-secrets is specified.
+var exportSecretKind = exportResourceKind{
+	gvr: types.SecretGVR,
+	include: func(obj *unstructured.Unstructured) bool {
+		_, ok := obj.GetLabels()[accounts.AccountSecretLabelKey]
+		return ok
+	},
+}
+
+type exportOptions struct {
+	clusterName    string
+	namespace      string
+	format         string
+	outputDir      string
+	includeSecrets bool
+
+	dynamic dynamic.Interface
+	genericiooptions.IOStreams
+}
+
+func NewExportCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &exportOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "export NAME",
+		Short:             "Export the cluster and its backup policies, backup schedules and services not managed by KubeBlocks as a helm chart or kustomize manifests to recreate it elsewhere.",
+		Example:           exportExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.format, "format", exportFormatHelm, "The format of the exported manifests, one of helm and kustomize")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", `The directory to write the manifests to, it must not exist, default is "NAME-FORMAT" in the current directory`)
+	cmd.Flags().BoolVar(&o.includeSecrets, "include-secrets", false, "Include the Secrets synchronized with the account credentials, the credentials are written in plain base64")
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{exportFormatHelm, exportFormatKustomize}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	return cmd
+}
+
+func (o *exportOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to export one cluster")
+	}
+	o.clusterName = args[0]
+	if o.outputDir == "" {
+		o.outputDir = fmt.Sprintf("%s-%s", o.clusterName, o.format)
+	}
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.dynamic, err = f.DynamicClient()
+	return err
+}
+
+func (o *exportOptions) validate() error {
+	if o.format != exportFormatHelm && o.format != exportFormatKustomize {
+		return clierrors.NewValidation("invalid --format %s, should be one of %s and %s", o.format, exportFormatHelm, exportFormatKustomize)
+	}
+	if _, err := os.Stat(o.outputDir); err == nil {
+		return clierrors.NewValidation("the output directory %s already exists", o.outputDir)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// sanitizeExportObject removes the fields populated by the server and identifying the source
+// cluster, so the object can be created in another namespace or Kubernetes cluster.
+func sanitizeExportObject(obj *unstructured.Unstructured) {
+	for _, field := range []string{"namespace", "resourceVersion", "uid", "creationTimestamp", "generation",
+		"managedFields", "selfLink", "ownerReferences", "finalizers"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	annotations := obj.GetAnnotations()
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	obj.SetAnnotations(annotations)
+	if obj.GetKind() == "Service" {
+		// the addresses and ports are allocated in the new Kubernetes cluster
+		for _, field := range []string{"clusterIP", "clusterIPs", "healthCheckNodePort"} {
+			unstructured.RemoveNestedField(obj.Object, "spec", field)
+		}
+		ports, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+		for _, p := range ports {
+			if port, ok := p.(map[string]interface{}); ok {
+				delete(port, "nodePort")
+			}
+		}
+		if len(ports) > 0 {
+			_ = unstructured.SetNestedSlice(obj.Object, ports, "spec", "ports")
+		}
+	}
+}
+
+// buildExportCluster builds the cluster to export, the fields identifying the source cluster are removed
+func buildExportCluster(source *appsv1alpha1.Cluster) (*unstructured.Unstructured, error) {
+	c := copyClusterSpec(source, source.Namespace)
+	c.TypeMeta = metav1.TypeMeta{APIVersion: fmt.Sprintf("%s/%s", types.AppsAPIGroup, types.AppsAPIVersion), Kind: types.KindCluster}
+	c.Name = source.Name
+	for k, v := range source.Annotations {
+		if k == "kubectl.kubernetes.io/last-applied-configuration" || strings.Contains(k, "kubeblocks.io/") {
+			continue
+		}
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[k] = v
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	sanitizeExportObject(u)
+	return u, nil
+}
+
+// collect returns the exported cluster, the resources related to it and the patches of the
+// modified resources managed by KubeBlocks
+func (o *exportOptions) collect() (*unstructured.Unstructured, []*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	source, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c, err := buildExportCluster(source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	selector := fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.clusterName)
+	kinds := exportResourceKinds[:len(exportResourceKinds):len(exportResourceKinds)]
+	if o.includeSecrets {
+		kinds = append(kinds, exportSecretKind)
+	}
+	var resources, patches []*unstructured.Unstructured
+	for _, kind := range kinds {
+		list, err := o.dynamic.Resource(kind.gvr()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if kind.include != nil && !kind.include(obj) {
+				if patch := buildExportPatch(obj); patch != nil && kind.patchModified {
+					patches = append(patches, patch)
+				}
+				continue
+			}
+			sanitizeExportObject(obj)
+			resources = append(resources, obj)
+		}
+	}
+	return c, resources, patches, nil
+}
+
+// templateClusterName replaces the cluster name and the prefix of the names derived from it
+// with the placeholder in the string values.
+func templateClusterName(value interface{}, name string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = templateClusterName(v[k], name)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = templateClusterName(v[i], name)
+		}
+	case string:
+		if v == name || strings.HasPrefix(v, name+"-") {
+			return exportNamePlaceholder + strings.TrimPrefix(v, name)
+		}
+	}
+	return value
+}
+
+func writeYAMLFile(path string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// writeHelmChart writes the chart whose values contain the spec of the cluster and the related
+// resources, the cluster is renamed by --set name=NAME.
+func (o *exportOptions) writeHelmChart(c *unstructured.Unstructured, resources []*unstructured.Unstructured) error {
+	templatesDir := filepath.Join(o.outputDir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return err
+	}
+	chart := map[string]interface{}{
+		"apiVersion":  "v2",
+		"name":        o.clusterName,
+		"description": fmt.Sprintf("The KubeBlocks cluster exported from %s/%s", o.namespace, o.clusterName),
+		"type":        "application",
+		"version":     "0.1.0",
+	}
+	if err := writeYAMLFile(filepath.Join(o.outputDir, "Chart.yaml"), chart); err != nil {
+		return err
+	}
+
+	var objs []interface{}
+	for _, r := range resources {
+		objs = append(objs, templateClusterName(r.Object, o.clusterName))
+	}
+	values := map[string]interface{}{
+		"name":        o.clusterName,
+		"apiVersion":  c.GetAPIVersion(),
+		"labels":      c.GetLabels(),
+		"annotations": c.GetAnnotations(),
+		"spec":        c.Object["spec"],
+		"resources":   objs,
+	}
+	if err := writeYAMLFile(filepath.Join(o.outputDir, "values.yaml"), values); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, "cluster.yaml"), []byte(exportHelmClusterTemplate), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(templatesDir, "resources.yaml"), []byte(exportHelmResourcesTemplate), 0644)
+}
+
+// writeKustomize writes the resources to the base, and the overlay which sets the namespace of
+// the source cluster, more overlays can be added for other namespaces or environments.
+func (o *exportOptions) writeKustomize(c *unstructured.Unstructured, resources []*unstructured.Unstructured) error {
+	baseDir := filepath.Join(o.outputDir, "base")
+	overlayDir := filepath.Join(o.outputDir, "overlays", o.namespace)
+	for _, dir := range []string{baseDir, overlayDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	var files []string
+	for _, obj := range append([]*unstructured.Unstructured{c}, resources...) {
+		fileName := fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())
+		if err := writeYAMLFile(filepath.Join(baseDir, fileName), obj.Object); err != nil {
+			return err
+		}
+		files = append(files, fileName)
+	}
+	base := map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  files,
+	}
+	if err := writeYAMLFile(filepath.Join(baseDir, "kustomization.yaml"), base); err != nil {
+		return err
+	}
+	overlay := map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"namespace":  o.namespace,
+		"resources":  []string{"../../base"},
+	}
+	return writeYAMLFile(filepath.Join(overlayDir, "kustomization.yaml"), overlay)
+}
+
+// writePatches writes the patches of the modified resources managed by KubeBlocks, they're applied
+// by the server-side apply after the controller recreates the resources, so only the fields in the
+// patches are changed.
+func (o *exportOptions) writePatches(patches []*unstructured.Unstructured) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	dir := filepath.Join(o.outputDir, exportPatchesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, p := range patches {
+		fileName := fmt.Sprintf("%s-%s.yaml", strings.ToLower(p.GetKind()), p.GetName())
+		if err := writeYAMLFile(filepath.Join(dir, fileName), p.Object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *exportOptions) run() error {
+	c, resources, patches, err := o.collect()
+	if err != nil {
+		return err
+	}
+	if o.format == exportFormatHelm {
+		err = o.writeHelmChart(c, resources)
+	} else {
+		err = o.writeKustomize(c, resources)
+	}
+	if err != nil {
+		return err
+	}
+	if err = o.writePatches(patches); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Cluster %s is exported to %s with %d related resources\n", o.clusterName, o.outputDir, len(resources))
+	for _, r := range resources {
+		fmt.Fprintf(o.Out, "\t%s/%s\n", r.GetKind(), r.GetName())
+	}
+	if !o.includeSecrets {
+		fmt.Fprintln(o.Out, "The account credentials are not exported, use --include-secrets to export the Secrets synchronized with them")
+	}
+	if o.format == exportFormatHelm {
+		fmt.Fprintf(o.Out, "Recreate the cluster by: helm install %s %s --set name=%s -n NAMESPACE\n", o.clusterName, o.outputDir, o.clusterName)
+	} else {
+		fmt.Fprintf(o.Out, "Recreate the cluster by: kubectl apply -k %s\n", filepath.Join(o.outputDir, "overlays", o.namespace))
+	}
+	if len(patches) > 0 {
+		printer.Warning(o.Out, "%d resource(s) managed by KubeBlocks are modified, they are recreated by KubeBlocks with the defaults, "+
+			"and the modified spec is exported to %s:\n", len(patches), filepath.Join(o.outputDir, exportPatchesDir))
+		for _, p := range patches {
+			fmt.Fprintf(o.Out, "\t%s/%s\n", p.GetKind(), p.GetName())
+		}
+		fmt.Fprintf(o.Out, "Apply the patches after the cluster is recreated by: kubectl apply --server-side --force-conflicts -f %s -n NAMESPACE, "+
+			"rename them if the cluster is recreated with another name\n", filepath.Join(o.outputDir, exportPatchesDir))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cmd/accounts"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("cluster export", func() {
+	const policyName = clitesting.ClusterName + "-mysql-backup-policy"
+
+	var (
+		out *bytes.Buffer
+		o   *exportOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		instanceLabels := map[string]string{constant.AppInstanceLabelKey: clitesting.ClusterName}
+		lb := &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: clitesting.ClusterName + "-lb", Namespace: clitesting.Namespace, Labels: instanceLabels},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.0.0.1",
+				Selector:  instanceLabels,
+				Ports:     []corev1.ServicePort{{Port: 3306, NodePort: 30306}},
+			},
+		}
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: clitesting.ClusterName + "-account-admin", Namespace: clitesting.Namespace,
+				Labels: map[string]string{constant.AppInstanceLabelKey: clitesting.ClusterName, accounts.AccountSecretLabelKey: "true"}},
+			Data: map[string][]byte{"password": []byte("secret")},
+		}
+		// the backup policy created by KubeBlocks is recreated by the controller with the cluster
+		managedPolicy := clitesting.FakeBackupPolicy(clitesting.ClusterName+"-managed-backup-policy", clitesting.ClusterName)
+		managedPolicy.Labels[constant.AppManagedByLabelKey] = constant.AppName
+		o = &exportOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			format:      exportFormatHelm,
+			outputDir:   filepath.Join(GinkgoT().TempDir(), "export"),
+			dynamic: clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace),
+				clitesting.FakeBackupPolicy(policyName, clitesting.ClusterName), managedPolicy, lb, secret),
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	It("validate", func() {
+		o.format = "json"
+		Expect(o.validate()).Should(MatchError(ContainSubstring("invalid --format")))
+		o.format = exportFormatKustomize
+		Expect(o.validate()).Should(Succeed())
+		Expect(os.MkdirAll(o.outputDir, 0755)).Should(Succeed())
+		Expect(o.validate()).Should(MatchError(ContainSubstring("already exists")))
+	})
+
+	It("template the cluster name", func() {
+		value := templateClusterName(map[string]interface{}{
+			"name":   "mycluster-mysql",
+			"labels": []interface{}{"mycluster", "other-mycluster"},
+		}, "mycluster")
+		Expect(value).Should(Equal(map[string]interface{}{
+			"name":   exportNamePlaceholder + "-mysql",
+			"labels": []interface{}{exportNamePlaceholder, "other-mycluster"},
+		}))
+	})
+
+	It("export as helm chart", func() {
+		Expect(o.run()).Should(Succeed())
+		for _, file := range []string{"Chart.yaml", "values.yaml", "templates/cluster.yaml", "templates/resources.yaml"} {
+			Expect(filepath.Join(o.outputDir, file)).Should(BeAnExistingFile())
+		}
+		data, err := os.ReadFile(filepath.Join(o.outputDir, "values.yaml"))
+		Expect(err).Should(Succeed())
+		values := map[string]interface{}{}
+		Expect(yaml.Unmarshal(data, &values)).Should(Succeed())
+		Expect(values["name"]).Should(Equal(clitesting.ClusterName))
+		Expect(values["spec"]).Should(HaveKey("componentSpecs"))
+		// the backup policy and the service, the secret is not included by default
+		Expect(values["resources"]).Should(HaveLen(2))
+		Expect(string(data)).Should(ContainSubstring(exportNamePlaceholder + "-mysql-backup-policy"))
+		Expect(string(data)).ShouldNot(ContainSubstring("managed-backup-policy"))
+		Expect(string(data)).ShouldNot(ContainSubstring("10.0.0.1"))
+		Expect(string(data)).ShouldNot(ContainSubstring("30306"))
+		Expect(string(data)).ShouldNot(ContainSubstring("namespace: " + clitesting.Namespace))
+		Expect(out.String()).Should(ContainSubstring("--include-secrets"))
+	})
+
+	It("export as kustomize", func() {
+		o.format = exportFormatKustomize
+		o.includeSecrets = true
+		Expect(o.run()).Should(Succeed())
+		base := filepath.Join(o.outputDir, "base")
+		Expect(filepath.Join(base, "cluster-"+clitesting.ClusterName+".yaml")).Should(BeAnExistingFile())
+		Expect(filepath.Join(base, "backuppolicy-"+policyName+".yaml")).Should(BeAnExistingFile())
+		Expect(filepath.Join(base, "backuppolicy-"+clitesting.ClusterName+"-managed-backup-policy.yaml")).ShouldNot(BeAnExistingFile())
+		Expect(filepath.Join(base, "secret-"+clitesting.ClusterName+"-account-admin.yaml")).Should(BeAnExistingFile())
+		data, err := os.ReadFile(filepath.Join(base, "kustomization.yaml"))
+		Expect(err).Should(Succeed())
+		Expect(string(data)).Should(ContainSubstring("service-" + clitesting.ClusterName + "-lb.yaml"))
+		data, err = os.ReadFile(filepath.Join(o.outputDir, "overlays", clitesting.Namespace, "kustomization.yaml"))
+		Expect(err).Should(Succeed())
+		Expect(string(data)).Should(ContainSubstring("namespace: " + clitesting.Namespace))
+		Expect(string(data)).Should(ContainSubstring("../../base"))
+		Expect(filepath.Join(o.outputDir, exportPatchesDir)).ShouldNot(BeAnExistingFile())
+	})
+
+	It("export the modified resources managed by KubeBlocks as patches", func() {
+		modifiedPolicy := clitesting.FakeBackupPolicy(clitesting.ClusterName+"-modified-backup-policy", clitesting.ClusterName)
+		modifiedPolicy.Labels[constant.AppManagedByLabelKey] = constant.AppName
+		modifiedPolicy.Generation = 2
+		managedPolicy := clitesting.FakeBackupPolicy(clitesting.ClusterName+"-managed-backup-policy", clitesting.ClusterName)
+		managedPolicy.Labels[constant.AppManagedByLabelKey] = constant.AppName
+		managedPolicy.Generation = 1
+		o.dynamic = clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace), modifiedPolicy, managedPolicy)
+		Expect(o.run()).Should(Succeed())
+		Expect(filepath.Join(o.outputDir, exportPatchesDir, "backuppolicy-"+clitesting.ClusterName+"-managed-backup-policy.yaml")).ShouldNot(BeAnExistingFile())
+		data, err := os.ReadFile(filepath.Join(o.outputDir, exportPatchesDir, "backuppolicy-"+modifiedPolicy.Name+".yaml"))
+		Expect(err).Should(Succeed())
+		patch := map[string]interface{}{}
+		Expect(yaml.Unmarshal(data, &patch)).Should(Succeed())
+		Expect(patch).Should(HaveKey("spec"))
+		Expect(patch).ShouldNot(HaveKey("status"))
+		Expect(patch["metadata"]).Should(Equal(map[string]interface{}{"name": modifiedPolicy.Name}))
+		Expect(out.String()).Should(ContainSubstring("1 resource(s) managed by KubeBlocks are modified"))
+		Expect(out.String()).Should(ContainSubstring("BackupPolicy/" + modifiedPolicy.Name))
+		Expect(out.String()).Should(ContainSubstring("kubectl apply --server-side"))
+	})
+})
@@ -0,0 +1,171 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	externalDNSHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/hostname"
+	externalDNSTTLAnnotationKey      = "external-dns.alpha.kubernetes.io/ttl"
+	externalDNSLabelSelector         = "app.kubernetes.io/name=external-dns"
+)
+
+var (
+	// dnsPollInterval is the interval to check whether the hostname resolves
+	dnsPollInterval = 10 * time.Second
+
+	// lookupHost resolves the hostname, it's replaced in tests
+	lookupHost = net.DefaultResolver.LookupHost
+)
+
+// validateExposeHostname validates the hostname of the exposed endpoint, the DNS records are
+// created by external-dns for the LoadBalancer service of one component.
+func (o *OperationsOptions) validateExposeHostname() error {
+	if o.ExposeHostname == "" {
+		if o.ExposeDNSTTL != 0 {
+			return fmt.Errorf("--dns-ttl must be used with --hostname")
+		}
+		return nil
+	}
+	if strings.ToLower(o.ExposeEnabled) != util.EnableValue {
+		return fmt.Errorf("--hostname can only be used when enabling the expose")
+	}
+	if errs := validation.IsDNS1123Subdomain(o.ExposeHostname); len(errs) > 0 {
+		return fmt.Errorf("invalid hostname %s: %s", o.ExposeHostname, strings.Join(errs, ", "))
+	}
+	if len(o.ComponentNames) != 1 {
+		return fmt.Errorf("--hostname can only be used to expose one component, use --components to specify it")
+	}
+	if o.ExposeDNSTTL < 0 {
+		return fmt.Errorf("--dns-ttl must be a positive number of seconds")
+	}
+	return nil
+}
+
+// buildExposeDNSAnnotations adds the external-dns annotations to the annotations of the exposed service
+func (o *OperationsOptions) buildExposeDNSAnnotations(annotations map[string]string) map[string]string {
+	if o.ExposeHostname == "" {
+		return annotations
+	}
+	// the annotations of the provider are shared, copy them before adding the DNS annotations
+	result := make(map[string]string, len(annotations)+2)
+	for k, v := range annotations {
+		result[k] = v
+	}
+	result[externalDNSHostnameAnnotationKey] = o.ExposeHostname
+	if o.ExposeDNSTTL > 0 {
+		result[externalDNSTTLAnnotationKey] = strconv.FormatInt(o.ExposeDNSTTL, 10)
+	}
+	return result
+}
+
+// checkExternalDNS warns if external-dns is not installed, the DNS records will not be created without it
+func (o *OperationsOptions) checkExternalDNS() error {
+	deploys, err := o.Client.AppsV1().Deployments(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: externalDNSLabelSelector,
+	})
+	if err != nil {
+		return err
+	}
+	if len(deploys.Items) == 0 {
+		printer.Warning(o.Out, "external-dns is not found, the DNS records of %s will not be created until it is installed\n", o.ExposeHostname)
+	}
+	return nil
+}
+
+// waitForExposeEndpoint waits for the hostname to resolve and prints the endpoint of the exposed service
+func (o *OperationsOptions) waitForExposeEndpoint(clusterName string) error {
+	fmt.Fprintf(o.Out, "Waiting for the DNS records of %s to be created, it may take a few minutes...\n", o.ExposeHostname)
+	var addrs []string
+	err := wait.PollUntilContextTimeout(context.Background(), dnsPollInterval, o.ExposeTimeout, true, func(ctx context.Context) (bool, error) {
+		var lookupErr error
+		addrs, lookupErr = lookupHost(ctx, o.ExposeHostname)
+		return lookupErr == nil && len(addrs) > 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("timed out waiting for %s to resolve, the DNS records may take more time to propagate, check the logs of external-dns for details", o.ExposeHostname)
+	}
+
+	svc, err := o.getExposedService(clusterName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Hostname %s resolves to %s\n", o.ExposeHostname, strings.Join(addrs, ","))
+	if svc == nil || len(svc.Spec.Ports) == 0 {
+		fmt.Fprintf(o.Out, "Endpoint: %s\n", o.ExposeHostname)
+		return nil
+	}
+	var endpoints []string
+	for _, port := range svc.Spec.Ports {
+		endpoints = append(endpoints, net.JoinHostPort(o.ExposeHostname, strconv.Itoa(int(port.Port))))
+	}
+	fmt.Fprintf(o.Out, "Endpoint: %s\n", strings.Join(endpoints, ","))
+	return nil
+}
+
+// getExposedService gets the service of the component with the hostname annotation
+func (o *OperationsOptions) getExposedService(clusterName string) (*corev1.Service, error) {
+	svcs, err := o.Client.CoreV1().Services(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			constant.AppInstanceLabelKey:    clusterName,
+			constant.KBAppComponentLabelKey: o.ComponentNames[0],
+		}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range svcs.Items {
+		if svcs.Items[i].Annotations[externalDNSHostnameAnnotationKey] == o.ExposeHostname {
+			return &svcs.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// runExpose creates the expose OpsRequest and waits for the endpoint if the hostname is specified
+func (o *OperationsOptions) runExpose() error {
+	clusterName := o.Name
+	if err := o.Run(); err != nil {
+		return err
+	}
+	if o.ExposeHostname == "" || o.ExposeTimeout == 0 || action.ExportEnabled() || (o.DryRun != "" && o.DryRun != "none") {
+		return nil
+	}
+	return o.waitForExposeEndpoint(clusterName)
+}
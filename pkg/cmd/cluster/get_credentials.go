@@ -0,0 +1,231 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var getCredentialsExample = templates.Examples(`
+	# Show the credentials of cluster mycluster as environment variables with the password masked
+	kbcli cluster get-credentials mycluster
+
+	# Export the credentials of cluster mycluster to the current shell
+	eval $(kbcli cluster get-credentials mycluster --reveal)
+
+	# Show the credentials of component mysql as JSON
+	kbcli cluster get-credentials mycluster --component mysql -o json --reveal
+
+	# Show the connection DSN of cluster mycluster
+	kbcli cluster get-credentials mycluster -o dsn --reveal`)
+
+const (
+	credentialsFormatEnv  = "env"
+	credentialsFormatJSON = "json"
+	credentialsFormatDSN  = "dsn"
+)
+
+// credentials is the connection credentials of a cluster component
+type credentials struct {
+	Engine    string `json:"engine"`
+	Component string `json:"component"`
+	Host      string `json:"host"`
+	Port      string `json:"port"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	DSN       string `json:"dsn"`
+}
+
+type getCredentialsOptions struct {
+	clusterName   string
+	componentName string
+	namespace     string
+	format        string
+	reveal        bool
+
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	genericiooptions.IOStreams
+}
+
+func NewGetCredentialsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &getCredentialsOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "get-credentials NAME",
+		Short:             "Get the connection credentials of the cluster.",
+		Example:           getCredentialsExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentFlag(f, cmd, &o.componentName, "The component to get the credentials, default to the first component")
+	cmd.Flags().StringVarP(&o.format, "output", "o", credentialsFormatEnv, fmt.Sprintf("Output format, one of %s, %s and %s",
+		credentialsFormatEnv, credentialsFormatJSON, credentialsFormatDSN))
+	cmd.Flags().BoolVar(&o.reveal, "reveal", false, "Reveal the password instead of masking it")
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{credentialsFormatEnv, credentialsFormatJSON, credentialsFormatDSN}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	return cmd
+}
+
+func (o *getCredentialsOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to get the credentials of one cluster")
+	}
+	switch o.format {
+	case credentialsFormatEnv, credentialsFormatJSON, credentialsFormatDSN:
+	default:
+		return fmt.Errorf("invalid output format %s, should be one of %s, %s and %s", o.format,
+			credentialsFormatEnv, credentialsFormatJSON, credentialsFormatDSN)
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.client, err = f.KubernetesClientSet(); err != nil {
+		return err
+	}
+	o.dynamic, err = f.DynamicClient()
+	return err
+}
+
+func (o *getCredentialsOptions) run() error {
+	getter := cluster.ObjectsGetter{
+		Client:    o.client,
+		Dynamic:   o.dynamic,
+		Name:      o.clusterName,
+		Namespace: o.namespace,
+		GetOptions: cluster.GetOptions{
+			WithClusterDef: true,
+			WithService:    true,
+			WithSecret:     true,
+		},
+	}
+	objs, err := getter.Get()
+	if err != nil {
+		return err
+	}
+	cred, err := o.buildCredentials(objs)
+	if err != nil {
+		return err
+	}
+	return printCredentials(cred, o.format, o.Out)
+}
+
+func (o *getCredentialsOptions) buildCredentials(objs *cluster.ClusterObjects) (*credentials, error) {
+	var comp *appsv1alpha1.ClusterComponentSpec
+	if o.componentName == "" {
+		comp = &objs.Cluster.Spec.ComponentSpecs[0]
+	} else if comp = objs.Cluster.Spec.GetComponentByName(o.componentName); comp == nil {
+		return nil, fmt.Errorf("failed to get component %s. Check the list of components use: \n\tkbcli cluster list-components %s -n %s",
+			o.componentName, o.clusterName, o.namespace)
+	}
+	compDef := objs.ClusterDef.GetComponentDefByName(comp.ComponentDefRef)
+	if compDef == nil {
+		return nil, fmt.Errorf("failed to get component def :%s", comp.ComponentDefRef)
+	}
+
+	var err error
+	cred := &credentials{Engine: compDef.CharacterType, Component: comp.Name}
+	if cred.Username, cred.Password, err = getUserAndPassword(objs.ClusterDef, objs.Secrets); err != nil {
+		return nil, err
+	}
+	if !o.reveal {
+		cred.Password = passwordMask
+	}
+
+	// use the external endpoint first, then the internal service DNS name
+	internalSvcs, externalSvcs := cluster.GetComponentServices(objs.Services, comp)
+	switch {
+	case len(externalSvcs) > 0:
+		cred.Host = cluster.GetExternalAddr(externalSvcs[0])
+		cred.Port = fmt.Sprintf("%d", externalSvcs[0].Spec.Ports[0].Port)
+	case len(internalSvcs) > 0:
+		cred.Host = fmt.Sprintf("%s.%s.svc", internalSvcs[0].Name, internalSvcs[0].Namespace)
+		cred.Port = fmt.Sprintf("%d", internalSvcs[0].Spec.Ports[0].Port)
+	default:
+		return nil, fmt.Errorf("failed to find any endpoints of component %s", comp.Name)
+	}
+	cred.DSN = buildDSN(cred)
+	return cred, nil
+}
+
+// buildDSN builds the connection URI of the credentials, the engine is used as the scheme
+func buildDSN(cred *credentials) string {
+	scheme := cred.Engine
+	if scheme == "" {
+		scheme = "tcp"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(cred.Username, cred.Password),
+		Host:   fmt.Sprintf("%s:%s", cred.Host, cred.Port),
+	}
+	return u.String()
+}
+
+func printCredentials(cred *credentials, format string, out io.Writer) error {
+	switch format {
+	case credentialsFormatJSON:
+		data, err := json.MarshalIndent(cred, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	case credentialsFormatDSN:
+		fmt.Fprintln(out, cred.DSN)
+	default:
+		for _, kv := range [][]string{
+			{"DB_ENGINE", cred.Engine},
+			{"DB_HOST", cred.Host},
+			{"DB_PORT", cred.Port},
+			{"DB_USERNAME", cred.Username},
+			{"DB_PASSWORD", cred.Password},
+			{"DB_DSN", cred.DSN},
+		} {
+			fmt.Fprintf(out, "export %s='%s'\n", kv[0], strings.ReplaceAll(kv[1], "'", `'\''`))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+const (
+	gitOpsArgoCD = "ArgoCD"
+	gitOpsFlux   = "Flux"
+)
+
+// gitOpsMarkers are the labels and annotations set by the GitOps tools on the objects they manage
+var gitOpsMarkers = []struct {
+	key        string
+	annotation bool
+	manager    string
+}{
+	{key: "argocd.argoproj.io/instance", manager: gitOpsArgoCD},
+	{key: "argocd.argoproj.io/tracking-id", annotation: true, manager: gitOpsArgoCD},
+	{key: "kustomize.toolkit.fluxcd.io/name", manager: gitOpsFlux},
+	{key: "helm.toolkit.fluxcd.io/name", manager: gitOpsFlux},
+}
+
+// gitOpsOptions are the options of the mutate commands for the clusters managed by GitOps tools,
+// the direct changes of such clusters may be reverted by the next sync.
+type gitOpsOptions struct {
+	patchFile   string
+	forceDirect bool
+	// patchWritten is true if the patch is written to the file and the changes should not be applied
+	patchWritten bool
+}
+
+func (o *gitOpsOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.patchFile, "gitops-patch-file", "", "Write the equivalent cluster patch to the file instead of applying the changes, it can be committed to the Git repository synced by ArgoCD or Flux")
+	cmd.Flags().BoolVar(&o.forceDirect, "force-direct", false, "Apply the changes directly even if the cluster is managed by ArgoCD or Flux")
+}
+
+// getGitOpsManager returns the GitOps tool managing the object, an empty string is returned
+// if the object is not managed by any GitOps tool.
+func getGitOpsManager(obj metav1.Object) string {
+	for _, m := range gitOpsMarkers {
+		values := obj.GetLabels()
+		if m.annotation {
+			values = obj.GetAnnotations()
+		}
+		if _, ok := values[m.key]; ok {
+			return m.manager
+		}
+	}
+	return ""
+}
+
+// check checks the cluster before applying the changes. If the patch file is specified, the
+// patch built by buildPatch is written to it and the changes should not be applied. If the
+// cluster is managed by a GitOps tool, the changes are rejected unless --force-direct is set.
+func (o *gitOpsOptions) check(c *appsv1alpha1.Cluster, buildPatch func(c *appsv1alpha1.Cluster) (map[string]interface{}, error), out io.Writer) error {
+	if o.patchFile != "" {
+		spec, err := buildPatch(c.DeepCopy())
+		if err != nil {
+			return err
+		}
+		if err = writeGitOpsPatch(c, spec, o.patchFile); err != nil {
+			return err
+		}
+		o.patchWritten = true
+		fmt.Fprintf(out, "The patch of cluster %s is written to %s, commit it to the Git repository to apply the changes\n", c.Name, o.patchFile)
+		return nil
+	}
+	manager := getGitOpsManager(c)
+	if manager == "" {
+		return nil
+	}
+	if !o.forceDirect {
+		return fmt.Errorf("cluster %s is managed by %s and the changes may be reverted by the next sync, "+
+			"use --gitops-patch-file to write the patch to a file and commit it to the Git repository, or --force-direct to apply the changes directly", c.Name, manager)
+	}
+	fmt.Fprintf(out, "%s\n", printer.BoldYellow(fmt.Sprintf("Warning: cluster %s is managed by %s, the changes may be reverted by the next sync", c.Name, manager)))
+	return nil
+}
+
+// CheckGitOps rejects the changes of the cluster managed by a GitOps tool unless forceDirect is true,
+// it's used by the commands changing the cluster without the declarative equivalent patch.
+func CheckGitOps(c *appsv1alpha1.Cluster, forceDirect bool, out io.Writer) error {
+	return (&gitOpsOptions{forceDirect: forceDirect}).check(c, nil, out)
+}
+
+// AddForceDirectFlag adds the --force-direct flag to the commands checking the clusters by CheckGitOps
+func AddForceDirectFlag(cmd *cobra.Command, forceDirect *bool) {
+	cmd.Flags().BoolVar(forceDirect, "force-direct", false, "Apply the changes directly even if the cluster is managed by ArgoCD or Flux")
+}
+
+// writeGitOpsPatch writes the patch of the cluster spec to the file, the patch is a partial
+// Cluster manifest which can be used as a kustomize patch.
+func writeGitOpsPatch(c *appsv1alpha1.Cluster, spec map[string]interface{}, file string) error {
+	patch := map[string]interface{}{
+		"apiVersion": fmt.Sprintf("%s/%s", types.AppsAPIGroup, types.AppsAPIVersion),
+		"kind":       types.KindCluster,
+		"metadata": map[string]interface{}{
+			"name":      c.Name,
+			"namespace": c.Namespace,
+		},
+		"spec": spec,
+	}
+	data, err := yaml.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// componentSpecsPatch returns the spec patch with all component specs, the lists are replaced
+// as a whole by the merge patch, so the unchanged components must be included.
+func componentSpecsPatch(comps []appsv1alpha1.ClusterComponentSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(comps)
+	if err != nil {
+		return nil, err
+	}
+	var specs []interface{}
+	if err = json.Unmarshal(data, &specs); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"componentSpecs": specs}, nil
+}
+
+// buildGitOpsPatch builds the cluster spec patch equivalent to the operation, the operations
+// without a declarative equivalent are not supported.
+func (o *OperationsOptions) buildGitOpsPatch(c *appsv1alpha1.Cluster) (map[string]interface{}, error) {
+	if o.OpsType == appsv1alpha1.UpgradeType {
+		return map[string]interface{}{"clusterVersionRef": o.ClusterVersionRef}, nil
+	}
+
+	updateComp := func(comp *appsv1alpha1.ClusterComponentSpec) error {
+		switch o.OpsType {
+		case appsv1alpha1.HorizontalScalingType:
+			comp.Replicas = int32(o.Replicas)
+		case appsv1alpha1.VerticalScalingType:
+			if o.Class != "" {
+				return fmt.Errorf("the patch of vertical scaling with class is not supported, please specify --cpu and --memory")
+			}
+			for name, val := range map[corev1.ResourceName]string{corev1.ResourceCPU: o.CPU, corev1.ResourceMemory: o.Memory} {
+				if val == "" {
+					continue
+				}
+				q, err := resource.ParseQuantity(val)
+				if err != nil {
+					return err
+				}
+				if comp.Resources.Requests == nil {
+					comp.Resources.Requests = corev1.ResourceList{}
+				}
+				if comp.Resources.Limits == nil {
+					comp.Resources.Limits = corev1.ResourceList{}
+				}
+				comp.Resources.Requests[name] = q
+				comp.Resources.Limits[name] = q
+			}
+		case appsv1alpha1.VolumeExpansionType:
+			q, err := resource.ParseQuantity(o.Storage)
+			if err != nil {
+				return err
+			}
+			for i, vct := range comp.VolumeClaimTemplates {
+				for _, name := range o.VCTNames {
+					if vct.Name != name {
+						continue
+					}
+					if comp.VolumeClaimTemplates[i].Spec.Resources.Requests == nil {
+						comp.VolumeClaimTemplates[i].Spec.Resources.Requests = corev1.ResourceList{}
+					}
+					comp.VolumeClaimTemplates[i].Spec.Resources.Requests[corev1.ResourceStorage] = q
+				}
+			}
+		default:
+			return fmt.Errorf("operation %s has no declarative equivalent in the cluster spec, use --export-to-dir to write the OpsRequest manifest instead", o.OpsType)
+		}
+		return nil
+	}
+
+	for i := range c.Spec.ComponentSpecs {
+		comp := &c.Spec.ComponentSpecs[i]
+		if len(o.ComponentNames) > 0 && !slices.Contains(o.ComponentNames, comp.Name) {
+			continue
+		}
+		if err := updateComp(comp); err != nil {
+			return nil, err
+		}
+	}
+	return componentSpecsPatch(c.Spec.ComponentSpecs)
+}
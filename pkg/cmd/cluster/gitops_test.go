@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("gitops", func() {
+	var (
+		c   *appsv1alpha1.Cluster
+		out *bytes.Buffer
+		dir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		c = clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace)
+		out = &bytes.Buffer{}
+		dir, err = os.MkdirTemp("", "kbcli-gitops-")
+		Expect(err).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).Should(Succeed())
+	})
+
+	It("detect gitops manager", func() {
+		Expect(getGitOpsManager(c)).Should(BeEmpty())
+		c.SetAnnotations(map[string]string{"argocd.argoproj.io/tracking-id": "app:apps.kubeblocks.io/Cluster:default/test"})
+		Expect(getGitOpsManager(c)).Should(Equal(gitOpsArgoCD))
+		c.SetAnnotations(nil)
+		c.SetLabels(map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"})
+		Expect(getGitOpsManager(c)).Should(Equal(gitOpsFlux))
+	})
+
+	It("check managed cluster", func() {
+		buildPatch := func(c *appsv1alpha1.Cluster) (map[string]interface{}, error) {
+			return map[string]interface{}{"terminationPolicy": "Delete"}, nil
+		}
+		o := &gitOpsOptions{}
+		Expect(o.check(c, buildPatch, out)).Should(Succeed())
+		Expect(o.patchWritten).Should(BeFalse())
+
+		c.SetLabels(map[string]string{"helm.toolkit.fluxcd.io/name": "db"})
+		Expect(o.check(c, buildPatch, out)).Should(MatchError(ContainSubstring("is managed by Flux")))
+
+		o.forceDirect = true
+		Expect(o.check(c, buildPatch, out)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("Warning"))
+		Expect(o.patchWritten).Should(BeFalse())
+
+		o.patchFile = filepath.Join(dir, "patch.yaml")
+		Expect(o.check(c, buildPatch, out)).Should(Succeed())
+		Expect(o.patchWritten).Should(BeTrue())
+		data, err := os.ReadFile(o.patchFile)
+		Expect(err).Should(Succeed())
+		patch := map[string]interface{}{}
+		Expect(yaml.Unmarshal(data, &patch)).Should(Succeed())
+		Expect(patch).Should(HaveKeyWithValue("kind", "Cluster"))
+		Expect(patch).Should(HaveKeyWithValue("spec", map[string]interface{}{"terminationPolicy": "Delete"}))
+	})
+
+	It("build operation patch", func() {
+		o := &OperationsOptions{
+			OpsType:        appsv1alpha1.HorizontalScalingType,
+			ComponentNames: []string{clitesting.ComponentName},
+			Replicas:       3,
+		}
+		spec, err := o.buildGitOpsPatch(c.DeepCopy())
+		Expect(err).Should(Succeed())
+		comps := spec["componentSpecs"].([]interface{})
+		Expect(comps).Should(HaveLen(len(c.Spec.ComponentSpecs)))
+		Expect(comps[0]).Should(HaveKeyWithValue("replicas", BeNumerically("==", 3)))
+		Expect(comps[1]).Should(HaveKeyWithValue("replicas", BeNumerically("==", c.Spec.ComponentSpecs[1].Replicas)))
+
+		o.OpsType = appsv1alpha1.VerticalScalingType
+		o.CPU = "2"
+		spec, err = o.buildGitOpsPatch(c.DeepCopy())
+		Expect(err).Should(Succeed())
+		resources := spec["componentSpecs"].([]interface{})[0].(map[string]interface{})["resources"].(map[string]interface{})
+		Expect(resources["limits"]).Should(HaveKeyWithValue("cpu", "2"))
+
+		o.OpsType = appsv1alpha1.UpgradeType
+		o.ClusterVersionRef = "v2"
+		Expect(o.buildGitOpsPatch(c.DeepCopy())).Should(Equal(map[string]interface{}{"clusterVersionRef": "v2"}))
+
+		o.OpsType = appsv1alpha1.RestartType
+		_, err = o.buildGitOpsPatch(c.DeepCopy())
+		Expect(err).Should(MatchError(ContainSubstring("no declarative equivalent")))
+	})
+})
@@ -0,0 +1,276 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/cluster/hardening"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var hardenExample = templates.Examples(`
+	# Restrict the ingress of cluster mycluster to the pods in the same namespace, and create a dedicated ServiceAccount
+	kbcli cluster harden mycluster
+
+	# Only allow the pods labeled app=web in namespace web to access the service ports of component mysql
+	kbcli cluster harden mycluster --components mysql --allow-namespaces web --allow-pod-labels app=web
+
+	# Print the manifests instead of creating them
+	kbcli cluster harden mycluster --dry-run=client`)
+
+type hardenOptions struct {
+	clusterName     string
+	namespace       string
+	components      []string
+	allowNamespaces []string
+	allowPodLabels  map[string]string
+	dryRun          cmdutil.DryRunStrategy
+
+	dynamic dynamic.Interface
+	genericiooptions.IOStreams
+}
+
+func NewHardenCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &hardenOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "harden NAME",
+		Short:             "Restrict the network access to the cluster and create a dedicated ServiceAccount with the minimal permissions.",
+		Example:           hardenExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, cmd, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentsFlag(f, cmd, &o.components, "Component names to restrict the ingress, default to all components")
+	cmd.Flags().StringSliceVar(&o.allowNamespaces, "allow-namespaces", nil, "The namespaces allowed to access the service ports of the cluster, default to the namespace of the cluster")
+	cmd.Flags().StringToStringVar(&o.allowPodLabels, "allow-pod-labels", nil, "The labels of the pods allowed to access the service ports of the cluster, e.g. --allow-pod-labels app=web,tier=backend")
+	cmdutil.AddDryRunFlag(cmd)
+	return cmd
+}
+
+func (o *hardenOptions) complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to harden one cluster")
+	}
+	o.clusterName = args[0]
+	if o.dryRun, err = cmdutil.GetDryRunStrategy(cmd); err != nil {
+		return err
+	}
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.dynamic, err = f.DynamicClient()
+	return err
+}
+
+func (o *hardenOptions) validate() error {
+	for _, ns := range o.allowNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %s: %s", ns, strings.Join(errs, ", "))
+		}
+	}
+	for k, v := range o.allowPodLabels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %s: %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid label value %s: %s", v, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// buildHardeningOptions builds the values of the hardening templates, the ports of a component are
+// the target ports of its services.
+func (o *hardenOptions) buildHardeningOptions(c *appsv1alpha1.Cluster) (*hardening.Options, error) {
+	var names []string
+	for _, comp := range c.Spec.ComponentSpecs {
+		names = append(names, comp.Name)
+	}
+	for _, name := range o.components {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("component %s is not found in cluster %s", name, c.Name)
+		}
+	}
+	if len(o.components) > 0 {
+		names = o.components
+	}
+
+	svcList, err := o.dynamic.Resource(types.ServiceGVR()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, c.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ports := map[string][]hardening.Port{}
+	for _, item := range svcList.Items {
+		svc := &corev1.Service{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, svc); err != nil {
+			return nil, err
+		}
+		// the headless services are used between the pods of the cluster
+		if svc.Spec.ClusterIP == corev1.ClusterIPNone {
+			continue
+		}
+		compName := svc.Labels[constant.KBAppComponentLabelKey]
+		for _, p := range svc.Spec.Ports {
+			port := hardening.Port{Port: strconv.Itoa(int(p.Port)), Protocol: string(p.Protocol)}
+			if p.TargetPort.Type == intstr.String || p.TargetPort.IntVal != 0 {
+				port.Port = p.TargetPort.String()
+			}
+			if port.Protocol == "" {
+				port.Protocol = string(corev1.ProtocolTCP)
+			}
+			if !slices.Contains(ports[compName], port) {
+				ports[compName] = append(ports[compName], port)
+			}
+		}
+	}
+
+	options := &hardening.Options{
+		ClusterName:     c.Name,
+		Namespace:       o.namespace,
+		AllowNamespaces: o.allowNamespaces,
+		AllowPodLabels:  o.allowPodLabels,
+	}
+	for _, name := range names {
+		options.Components = append(options.Components, hardening.Component{Name: name, Ports: ports[name]})
+	}
+	// KubeBlocks is allowed to access the pods, the namespace is unknown if KubeBlocks is not installed
+	options.KubeBlocksNamespace, _ = util.GetKubeBlocksNamespaceByDynamic(o.dynamic)
+	return options, nil
+}
+
+func (o *hardenOptions) run() error {
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	options, err := o.buildHardeningOptions(c)
+	if err != nil {
+		return err
+	}
+	policies, err := hardening.BuildNetworkPolicies(options)
+	if err != nil {
+		return err
+	}
+	rbac, err := hardening.BuildRBAC(options)
+	if err != nil {
+		return err
+	}
+	objs := append(policies, rbac...)
+
+	if o.dryRun == cmdutil.DryRunClient {
+		for _, obj := range objs {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "---\n%s", data)
+		}
+		return nil
+	}
+	for _, obj := range objs {
+		if err = o.apply(obj); err != nil {
+			return err
+		}
+	}
+	if o.dryRun == cmdutil.DryRunServer {
+		return nil
+	}
+
+	saName := hardening.ServiceAccountName(c.Name)
+	fmt.Fprintf(o.Out, "\nTo run the pods of the cluster with ServiceAccount %s, update the components with:\n", saName)
+	for _, comp := range options.Components {
+		fmt.Fprintf(o.Out, "  kbcli cluster update %s --set spec.componentSpecs[name=%s].serviceAccountName=%s\n", c.Name, comp.Name, saName)
+	}
+	return nil
+}
+
+// apply creates the object, or updates it if it already exists
+func (o *hardenOptions) apply(obj *unstructured.Unstructured) error {
+	gvr, ok := map[string]schema.GroupVersionResource{
+		"NetworkPolicy":  types.NetworkPolicyGVR(),
+		"ServiceAccount": types.ServiceAccountGVR(),
+		"Role":           types.RoleGVR(),
+		"RoleBinding":    types.RoleBindingGVR(),
+	}[obj.GetKind()]
+	if !ok {
+		return fmt.Errorf("unsupported kind %s", obj.GetKind())
+	}
+	var dryRun []string
+	if o.dryRun == cmdutil.DryRunServer {
+		dryRun = []string{metav1.DryRunAll}
+	}
+
+	ctx := context.TODO()
+	client := o.dynamic.Resource(gvr).Namespace(obj.GetNamespace())
+	operation := "created"
+	_, err := client.Create(ctx, obj, metav1.CreateOptions{DryRun: dryRun})
+	if apierrors.IsAlreadyExists(err) {
+		var existing *unstructured.Unstructured
+		if existing, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{}); err != nil {
+			return err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		operation = "configured"
+		_, err = client.Update(ctx, obj, metav1.UpdateOptions{DryRun: dryRun})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply %s %s: %v", obj.GetKind(), obj.GetName(), err)
+	}
+	if o.dryRun == cmdutil.DryRunServer {
+		operation += " (server dry run)"
+	}
+	fmt.Fprintf(o.Out, "%s/%s %s\n", strings.ToLower(obj.GetKind()), obj.GetName(), operation)
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster/hardening"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster harden", func() {
+	const (
+		namespace   = "test"
+		clusterName = "mycluster"
+	)
+
+	var (
+		out *bytes.Buffer
+		o   *hardenOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		labels := map[string]string{
+			constant.AppInstanceLabelKey:    clusterName,
+			constant.KBAppComponentLabelKey: clitesting.ComponentName,
+		}
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName + "-" + clitesting.ComponentName, Namespace: namespace, Labels: labels},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+				{Name: "mysql", Port: 3306, TargetPort: intstr.FromString("mysql")},
+				{Name: "metrics", Port: 9104, Protocol: corev1.ProtocolTCP},
+			}},
+		}
+		headless := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName + "-" + clitesting.ComponentName + "-headless", Namespace: namespace, Labels: labels},
+			Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, Ports: []corev1.ServicePort{{Name: "paxos", Port: 13306}}},
+		}
+		helmSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeblocks-release", Namespace: "kb-system", Labels: map[string]string{"name": types.KubeBlocksChartName, "owner": "helm"}},
+		}
+		o = &hardenOptions{
+			clusterName: clusterName,
+			namespace:   namespace,
+			components:  []string{clitesting.ComponentName},
+			dynamic:     clitesting.FakeDynamicClient(clitesting.FakeCluster(clusterName, namespace), svc, headless, helmSecret),
+			IOStreams:   genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	It("validate", func() {
+		o.allowNamespaces = []string{"Web"}
+		Expect(o.validate()).Should(MatchError(ContainSubstring("invalid namespace Web")))
+		o.allowNamespaces = []string{"web"}
+		o.allowPodLabels = map[string]string{"app": "web/frontend"}
+		Expect(o.validate()).Should(MatchError(ContainSubstring("invalid label value")))
+		o.allowPodLabels = map[string]string{"app": "web"}
+		Expect(o.validate()).Should(Succeed())
+
+		o.components = []string{"proxy"}
+		Expect(o.run()).Should(MatchError(ContainSubstring("component proxy is not found")))
+	})
+
+	It("print the manifests", func() {
+		o.dryRun = cmdutil.DryRunClient
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("kind: NetworkPolicy"))
+		Expect(out.String()).Should(ContainSubstring("kind: RoleBinding"))
+		Expect(out.String()).ShouldNot(ContainSubstring("13306"))
+		list, err := o.dynamic.Resource(types.NetworkPolicyGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(list.Items).Should(BeEmpty())
+	})
+
+	It("create the network policies and the service account", func() {
+		o.allowNamespaces = []string{"web"}
+		o.allowPodLabels = map[string]string{"app": "web"}
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("networkpolicy/mycluster-" + clitesting.ComponentName + "-ingress created"))
+		Expect(out.String()).Should(ContainSubstring("serviceAccountName=" + hardening.ServiceAccountName(clusterName)))
+
+		obj, err := o.dynamic.Resource(types.NetworkPolicyGVR()).Namespace(namespace).Get(context.TODO(), "mycluster-"+clitesting.ComponentName+"-ingress", metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		policy := &networkingv1.NetworkPolicy{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, policy)).Should(Succeed())
+		Expect(policy.Spec.PodSelector.MatchLabels).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, clitesting.ComponentName))
+		Expect(policy.Spec.Ingress).Should(HaveLen(3))
+		Expect(policy.Spec.Ingress[0].Ports).Should(BeEmpty())
+		Expect(policy.Spec.Ingress[1].From[0].NamespaceSelector.MatchLabels).Should(HaveKeyWithValue("kubernetes.io/metadata.name", "kb-system"))
+		allowed := policy.Spec.Ingress[2]
+		Expect(allowed.From).Should(HaveLen(1))
+		Expect(allowed.From[0].NamespaceSelector.MatchLabels).Should(HaveKeyWithValue("kubernetes.io/metadata.name", "web"))
+		Expect(allowed.From[0].PodSelector.MatchLabels).Should(HaveKeyWithValue("app", "web"))
+		Expect(allowed.Ports).Should(HaveLen(2))
+		Expect(allowed.Ports[0].Port.String()).Should(Equal("mysql"))
+		Expect(allowed.Ports[1].Port.IntValue()).Should(Equal(9104))
+
+		_, err = o.dynamic.Resource(types.RoleBindingGVR()).Namespace(namespace).Get(context.TODO(), hardening.ServiceAccountName(clusterName), metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+
+		By("harden again to update the resources")
+		out.Reset()
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("serviceaccount/" + hardening.ServiceAccountName(clusterName) + " configured"))
+	})
+})
@@ -51,7 +51,10 @@ var (
 		kbcli cluster list mycluster -o json
 
 		# list a single cluster in wide output format
-		kbcli cluster list mycluster -o wide`)
+		kbcli cluster list mycluster -o wide
+
+		# list the names and status of clusters without headers
+		kbcli cluster list --columns NAME,STATUS --no-headers`)
 
 	listInstancesExample = templates.Examples(`
 		# list all instances of all clusters in current namespace
@@ -93,6 +96,7 @@ func NewListCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 		},
 	}
 	o.AddFlags(cmd)
+	o.TableOptions.AddFlags(cmd)
 	return cmd
 }
 
@@ -151,8 +155,8 @@ func NewListEventsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 }
 
 func run(o *action.ListOptions, printType cluster.PrintType) error {
-	// if format is JSON or YAML, use default printer to output the result.
-	if o.Format == printer.JSON || o.Format == printer.YAML {
+	// if format is JSON or YAML, or watch is enabled, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML || o.Watch {
 		_, err := o.Run()
 		return err
 	}
@@ -186,9 +190,13 @@ func run(o *action.ListOptions, printType cluster.PrintType) error {
 
 	opt := &cluster.PrinterOptions{
 		ShowLabels: o.ShowLabels,
+		Format:     o.Format,
 	}
 
 	p := cluster.NewPrinter(o.IOStreams.Out, printType, opt)
+	if err = p.ApplyTableOptions(&o.TableOptions); err != nil {
+		return err
+	}
 	for _, info := range infos {
 		if err = addRow(dynamic, client, info.Namespace, info.Name, p); err != nil {
 			return err
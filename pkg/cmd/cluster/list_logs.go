@@ -41,6 +41,7 @@ import (
 	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
 )
 
 var (
@@ -87,7 +88,7 @@ func NewListLogsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 		},
 	}
 	cmd.Flags().StringVarP(&o.instName, "instance", "i", "", "Instance name.")
-	cmd.Flags().StringVar(&o.componentName, "component", "", "Component name.")
+	flags.AddComponentFlag(f, cmd, &o.componentName, "Component name.")
 	return cmd
 }
 
@@ -21,20 +21,24 @@ package cluster
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/printer"
@@ -48,9 +52,19 @@ var (
 		kbcli cluster list-ops
 
 		# list all opsRequests of specified cluster
-		kbcli cluster list-ops mycluster`)
+		kbcli cluster list-ops mycluster
+
+		# list the pending and running opsRequests
+		kbcli cluster list-ops --status pending,running
+
+		# show the queued opsRequests of each cluster to find the stuck ones
+		kbcli cluster list-ops --queue`)
 
 	defaultDisplayPhase = []string{"pending", "creating", "running", "canceling", "failed"}
+
+	// queuedOpsPhases are the phases of the OpsRequests which are still waiting in the queue of the cluster
+	queuedOpsPhases = []appsv1alpha1.OpsPhase{appsv1alpha1.OpsPendingPhase, appsv1alpha1.OpsCreatingPhase,
+		appsv1alpha1.OpsRunningPhase, appsv1alpha1.OpsCancellingPhase}
 )
 
 type opsListOptions struct {
@@ -58,6 +72,7 @@ type opsListOptions struct {
 	status         []string
 	opsType        []string
 	opsRequestName string
+	queue          bool
 }
 
 func NewListOpsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -85,12 +100,14 @@ func NewListOpsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().StringSliceVar(&o.status, "status", defaultDisplayPhase, fmt.Sprintf("Options include all, %s. by default, outputs the %s OpsRequest.",
 		strings.Join(defaultDisplayPhase, ", "), strings.Join(defaultDisplayPhase, "/")))
 	cmd.Flags().StringVar(&o.opsRequestName, "name", "", "The OpsRequest name to get the details.")
+	cmd.Flags().BoolVar(&o.queue, "queue", false, "Show the unfinished OpsRequests queued per cluster, the status flag is ignored.")
+	util.RegisterFlagCompletionFunc(cmd, "name", util.ClusterResourceCompletionFunc(f, types.OpsGVR()))
 	return cmd
 }
 
 func (o *opsListOptions) printOpsList() error {
-	// if format is JSON or YAML, use default printer to output the result.
-	if o.Format == printer.JSON || o.Format == printer.YAML {
+	// if format is JSON or YAML, or watch is enabled, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML || o.Watch {
 		if o.opsRequestName != "" {
 			o.Names = []string{o.opsRequestName}
 		}
@@ -119,22 +136,32 @@ func (o *opsListOptions) printOpsList() error {
 	}
 	// sort the unstructured objects with the creationTimestamp in positive order
 	sort.Sort(unstructuredList(opsList.Items))
+	if o.queue {
+		return o.printOpsQueue(dynamic, opsList.Items)
+	}
 
 	// check if specified with "all" keyword for status.
 	isAllStatus := o.isAllStatus()
 	tblPrinter := printer.NewTablePrinter(o.Out)
+	tblPrinter.SetFormat(o.Format)
 	tblPrinter.SetHeader("NAME", "NAMESPACE", "TYPE", "CLUSTER", "COMPONENT", "STATUS", "PROGRESS", "CREATED-TIME")
-	for _, obj := range opsList.Items {
-		ops := &appsv1alpha1.OpsRequest{}
-		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ops); err != nil {
-			return err
-		}
+	if err = tblPrinter.ApplyOptions(&o.TableOptions); err != nil {
+		return err
+	}
+	var nameFilter func(*unstructured.Unstructured) bool
+	if len(o.opsRequestName) != 0 {
+		nameFilter = util.NameFilter(o.opsRequestName)
+	}
+	opsRequests, err := util.ConvertUnstructuredList[appsv1alpha1.OpsRequest](opsList.Items, nameFilter)
+	if err != nil {
+		return err
+	}
+	for i := range opsRequests {
+		ops := &opsRequests[i]
 		phase := string(ops.Status.Phase)
 		opsType := string(ops.Spec.Type)
 		if len(o.opsRequestName) != 0 {
-			if ops.Name == o.opsRequestName {
-				tblPrinter.AddRow(ops.Name, ops.GetNamespace(), opsType, ops.Spec.ClusterRef, getComponentNameFromOps(ops), phase, ops.Status.Progress, util.TimeFormat(&ops.CreationTimestamp))
-			}
+			tblPrinter.AddRow(ops.Name, ops.GetNamespace(), opsType, ops.Spec.ClusterRef, getComponentNameFromOps(ops), phase, ops.Status.Progress, util.TimeFormat(&ops.CreationTimestamp))
 			continue
 		}
 		// if the OpsRequest phase is not expected, continue
@@ -159,6 +186,94 @@ func (o *opsListOptions) printOpsList() error {
 	return nil
 }
 
+// printOpsQueue prints the unfinished OpsRequests grouped by cluster in the order they are processed,
+// the OpsRequests recorded in the cluster annotation are being processed, and the others are blocked by them.
+func (o *opsListOptions) printOpsQueue(dynamic dynamic.Interface, items []unstructured.Unstructured) error {
+	type queueKey struct {
+		namespace string
+		cluster   string
+	}
+	var (
+		keys   []queueKey
+		queues = map[queueKey][]*appsv1alpha1.OpsRequest{}
+	)
+	for _, obj := range items {
+		ops := &appsv1alpha1.OpsRequest{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ops); err != nil {
+			return err
+		}
+		if !slices.Contains(queuedOpsPhases, ops.Status.Phase) {
+			continue
+		}
+		if len(o.opsType) != 0 && !o.containsIgnoreCase(o.opsType, string(ops.Spec.Type)) {
+			continue
+		}
+		key := queueKey{namespace: ops.Namespace, cluster: ops.Spec.ClusterRef}
+		if _, ok := queues[key]; !ok {
+			keys = append(keys, key)
+		}
+		queues[key] = append(queues[key], ops)
+	}
+	if len(keys) == 0 {
+		printer.PrintLine("No queued opsRequests found")
+		return nil
+	}
+
+	tblPrinter := printer.NewTablePrinter(o.Out)
+	tblPrinter.SetHeader("CLUSTER", "NAMESPACE", "POSITION", "NAME", "TYPE", "STATUS", "STATE", "AGE")
+	for _, key := range keys {
+		processing, err := getProcessingOps(dynamic, key.namespace, key.cluster)
+		if err != nil {
+			return err
+		}
+		queue := queues[key]
+		// the OpsRequests in process take the head of the queue
+		sort.SliceStable(queue, func(i, j int) bool {
+			return slices.Contains(processing, queue[i].Name) && !slices.Contains(processing, queue[j].Name)
+		})
+		head := queue[0].Name
+		for i, ops := range queue {
+			state := "Running"
+			if ops.Status.Phase == appsv1alpha1.OpsPendingPhase && !slices.Contains(processing, ops.Name) {
+				state = "Waiting"
+				if i > 0 {
+					state = fmt.Sprintf("Blocked by %s", head)
+				}
+			}
+			tblPrinter.AddRow(key.cluster, key.namespace, i+1, ops.Name, ops.Spec.Type, ops.Status.Phase, state,
+				util.GetHumanReadableDuration(ops.CreationTimestamp, metav1.Time{}))
+		}
+	}
+	tblPrinter.Print()
+	fmt.Fprintf(o.Out, "\nTo clear a stuck queue, cancel the blocking opsRequest:\n\tkbcli cluster cancel-ops <opsRequestName>\n")
+	return nil
+}
+
+// getProcessingOps returns the names of the OpsRequests recorded in the cluster annotation, which are
+// being processed by the cluster.
+func getProcessingOps(dynamic dynamic.Interface, namespace, clusterName string) ([]string, error) {
+	obj, err := dynamic.Resource(types.ClusterGVR()).Namespace(namespace).Get(context.TODO(), clusterName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	value := obj.GetAnnotations()[constant.OpsRequestAnnotationKey]
+	if value == "" {
+		return nil, nil
+	}
+	var recorders []appsv1alpha1.OpsRecorder
+	if err = json.Unmarshal([]byte(value), &recorders); err != nil {
+		return nil, fmt.Errorf("failed to parse the annotation %s of cluster %s: %v", constant.OpsRequestAnnotationKey, clusterName, err)
+	}
+	names := make([]string, 0, len(recorders))
+	for _, r := range recorders {
+		names = append(names, r.Name)
+	}
+	return names, nil
+}
+
 func getComponentNameFromOps(ops *appsv1alpha1.OpsRequest) string {
 	components := make([]string, 0)
 	opsSpec := ops.Spec
@@ -21,8 +21,10 @@ package cluster
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -33,6 +35,7 @@ import (
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 
 	"github.com/apecloud/kbcli/pkg/action"
 	clitesting "github.com/apecloud/kbcli/pkg/testing"
@@ -195,4 +198,36 @@ var _ = Describe("Expose", func() {
 		Expect(clitesting.ContainExpectStrings(capturedOutput, "kbcli cluster list-ops --status all")).Should(BeTrue())
 	})
 
+	It("list ops queue", func() {
+		By("init queued opsRequests for testing")
+		running := generateOpsObject(appsv1alpha1.VerticalScalingType, appsv1alpha1.OpsRunningPhase)
+		running.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		blocked := generateOpsObject(appsv1alpha1.HorizontalScalingType, appsv1alpha1.OpsPendingPhase)
+		blocked.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		finished := generateOpsObject(appsv1alpha1.RestartType, appsv1alpha1.OpsSucceedPhase)
+		cluster := clitesting.FakeCluster("test-cluster", namespace)
+		cluster.Annotations = map[string]string{
+			constant.OpsRequestAnnotationKey: fmt.Sprintf(`[{"name":"%s","type":"VerticalScaling"}]`, running.Name),
+		}
+		tf.FakeDynamicClient = clitesting.FakeDynamicClient(running, blocked, finished, cluster)
+
+		By("show the queue")
+		o := initOpsOption(nil, nil)
+		o.queue = true
+		Expect(o.printOpsList()).Should(Succeed())
+		out := o.Out.(*bytes.Buffer).String()
+		Expect(out).Should(ContainSubstring(running.Name))
+		Expect(out).Should(ContainSubstring("Blocked by " + running.Name))
+		Expect(out).ShouldNot(ContainSubstring(finished.Name))
+		Expect(out).Should(ContainSubstring("kbcli cluster cancel-ops"))
+
+		By("the head of the queue is waiting if no opsRequest is processing")
+		cluster.Annotations = nil
+		tf.FakeDynamicClient = clitesting.FakeDynamicClient(blocked, finished, cluster)
+		o = initOpsOption(nil, nil)
+		o.queue = true
+		Expect(o.printOpsList()).Should(Succeed())
+		Expect(o.Out.(*bytes.Buffer).String()).Should(ContainSubstring("Waiting"))
+	})
+
 })
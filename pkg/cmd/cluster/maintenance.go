@@ -0,0 +1,633 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	maintenanceSchedulerName = "kbcli-maintenance-scheduler"
+	alertManagerLabel        = "app=prometheus,component=alertmanager,release=kb-addon-prometheus"
+
+	// maintenanceUntilAnnotationKey records the time in RFC3339 format when the maintenance mode of
+	// the cluster expires, the risky operations are refused until then unless --force is specified.
+	maintenanceUntilAnnotationKey = "apps.kubeblocks.io/maintenance-until"
+	// maintenanceReasonAnnotationKey records the reason of the maintenance
+	maintenanceReasonAnnotationKey = "apps.kubeblocks.io/maintenance-reason"
+	// maintenanceSilenceAnnotationKey records the ID of the alertmanager silence of the cluster
+	maintenanceSilenceAnnotationKey = "apps.kubeblocks.io/maintenance-silence"
+	// maintenanceMethodsAnnotationKey records the backup methods of the backup schedule suspended
+	// in the maintenance, they are resumed when the maintenance is disabled or expires.
+	maintenanceMethodsAnnotationKey = "dataprotection.kubeblocks.io/maintenance-methods"
+	// maintenanceLabelKey is the label key of the CronJob which ends the maintenance when it expires
+	maintenanceLabelKey = "apps.kubeblocks.io/maintenance"
+)
+
+// maintenanceRiskyOpsTypes are the operations refused in the maintenance mode unless --force is specified
+var maintenanceRiskyOpsTypes = []appsv1alpha1.OpsType{
+	appsv1alpha1.RestartType,
+	appsv1alpha1.StopType,
+	appsv1alpha1.HorizontalScalingType,
+	appsv1alpha1.VerticalScalingType,
+	appsv1alpha1.VolumeExpansionType,
+	appsv1alpha1.UpgradeType,
+	appsv1alpha1.SwitchoverType,
+	appsv1alpha1.ReconfiguringType,
+}
+
+var (
+	maintenanceEnableExample = templates.Examples(`
+	# enable the maintenance mode of cluster mycluster for 2 hours
+	kbcli cluster maintenance enable mycluster --duration 2h --reason "migrate the storage"`)
+
+	maintenanceDisableExample = templates.Examples(`
+	# disable the maintenance mode of cluster mycluster before it expires
+	kbcli cluster maintenance disable mycluster`)
+)
+
+type maintenanceOptions struct {
+	clusterName string
+	namespace   string
+	duration    time.Duration
+	reason      string
+	image       string
+	forceDirect bool
+
+	dynamic dynamic.Interface
+	client  kubernetes.Interface
+	genericiooptions.IOStreams
+}
+
+// NewMaintenanceCmd creates the maintenance command to enable or disable the maintenance mode of a cluster
+func NewMaintenanceCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Enable or disable the maintenance mode of a cluster, which pauses the scheduled backups and alerts, and refuses the risky operations.",
+	}
+	cmd.AddCommand(newMaintenanceEnableCmd(f, streams), newMaintenanceDisableCmd(f, streams))
+	return cmd
+}
+
+func newMaintenanceEnableCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &maintenanceOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "enable NAME",
+		Short:             "Enable the maintenance mode of a cluster for a duration.",
+		Example:           maintenanceEnableExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.enable())
+		},
+	}
+	cmd.Flags().DurationVar(&o.duration, "duration", 0, "The duration of the maintenance mode, such as 2h, it's disabled automatically when expires")
+	cmd.Flags().StringVar(&o.reason, "reason", "", "The reason of the maintenance, it's recorded in the cluster annotation and the alert silence")
+	cmd.Flags().StringVar(&o.image, "scheduler-image", "", schedulerImageUsage("The kubectl image used by the CronJob to disable the maintenance mode when expires"))
+	AddForceDirectFlag(cmd, &o.forceDirect)
+	_ = cmd.MarkFlagRequired("duration")
+	return cmd
+}
+
+func newMaintenanceDisableCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &maintenanceOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "disable NAME",
+		Short:             "Disable the maintenance mode of a cluster, and resume the scheduled backups and alerts.",
+		Example:           maintenanceDisableExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.disable())
+		},
+	}
+	AddForceDirectFlag(cmd, &o.forceDirect)
+	return cmd
+}
+
+func (o *maintenanceOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to set the maintenance mode of one cluster")
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *maintenanceOptions) validate() error {
+	if o.duration < time.Minute {
+		return clierrors.NewValidation("--duration must be at least 1m")
+	}
+	return nil
+}
+
+// maintenanceUntil returns the expiry time of the maintenance mode of the cluster, the cluster is in
+// maintenance if it's after now.
+func maintenanceUntil(c *appsv1alpha1.Cluster) (time.Time, bool) {
+	s, ok := c.Annotations[maintenanceUntilAnnotationKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		klog.V(1).Infof("invalid annotation %s=%s of cluster %s: %v", maintenanceUntilAnnotationKey, s, c.Name, err)
+		return time.Time{}, false
+	}
+	return until, until.After(time.Now())
+}
+
+// checkMaintenance refuses the risky operations if the cluster is in maintenance, unless --force is specified
+func (o *OperationsOptions) checkMaintenance(c *appsv1alpha1.Cluster) error {
+	return CheckMaintenance(c, o.OpsType, o.force)
+}
+
+// CheckMaintenance refuses the risky operations of the type if the cluster is in maintenance unless force is
+// true, it's shared by all the commands creating the OpsRequests.
+func CheckMaintenance(c *appsv1alpha1.Cluster, opsType appsv1alpha1.OpsType, force bool) error {
+	if force || !slices.Contains(maintenanceRiskyOpsTypes, opsType) {
+		return nil
+	}
+	until, ok := maintenanceUntil(c)
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf("cluster %s is in maintenance mode until %s, the %s operation is refused, "+
+		"use --force to create it anyway, or disable the maintenance mode by \"kbcli cluster maintenance disable %s\"",
+		c.Name, until.UTC().Format(time.RFC3339), opsType, c.Name)
+}
+
+// getBackupSchedules returns the backup schedules of the cluster
+func (o *maintenanceOptions) getBackupSchedules() ([]dpv1alpha1.BackupSchedule, error) {
+	list, err := o.dynamic.Resource(types.BackupScheduleGVR()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.clusterName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return util.ConvertUnstructuredList[dpv1alpha1.BackupSchedule](list.Items, nil)
+}
+
+// updateBackupSchedule suspends the schedule policies of the methods and records them in the
+// annotation, or resumes the recorded methods.
+func (o *maintenanceOptions) updateBackupSchedule(schedule *dpv1alpha1.BackupSchedule, methods []string, suspend bool) error {
+	if schedule.Annotations == nil {
+		schedule.Annotations = map[string]string{}
+	}
+	if suspend {
+		schedule.Annotations[maintenanceMethodsAnnotationKey] = strings.Join(methods, ",")
+	} else {
+		delete(schedule.Annotations, maintenanceMethodsAnnotationKey)
+		// the methods suspended by a blackout window are resumed when the window ends
+		methods = excludeMethods(methods, blackoutSuspendedMethods(schedule))
+	}
+	for i := range schedule.Spec.Schedules {
+		p := &schedule.Spec.Schedules[i]
+		if slices.Contains(methods, p.BackupMethod) {
+			enabled := !suspend
+			p.Enabled = &enabled
+		}
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(schedule)
+	if err != nil {
+		return err
+	}
+	_, err = o.dynamic.Resource(types.BackupScheduleGVR()).Namespace(schedule.Namespace).Update(context.TODO(),
+		&unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+	return err
+}
+
+// maintenanceMethods returns the backup methods suspended in the maintenance
+func maintenanceMethods(schedule *dpv1alpha1.BackupSchedule) []string {
+	if methods := schedule.Annotations[maintenanceMethodsAnnotationKey]; methods != "" {
+		return strings.Split(methods, ",")
+	}
+	return nil
+}
+
+// buildMaintenanceScript builds the script of the CronJob to disable the maintenance mode when
+// it expires, the alert silence expires by itself. The methods suspended by a blackout window at
+// the expiry time are kept suspended, they are resumed when the blackout window ends.
+func buildMaintenanceScript(c *appsv1alpha1.Cluster, schedules []dpv1alpha1.BackupSchedule, cronJobName string) (string, error) {
+	cmds := []string{fmt.Sprintf("kubectl annotate cluster %s -n %s %s- %s- %s-", c.Name, c.Namespace,
+		maintenanceUntilAnnotationKey, maintenanceReasonAnnotationKey, maintenanceSilenceAnnotationKey)}
+	blackoutJSONPath := annotationJSONPath(blackoutSuspendedAnnotationKey) + ":" + annotationJSONPath(blackoutMethodsAnnotationKey)
+	for i := range schedules {
+		s := &schedules[i]
+		methods := maintenanceMethods(s)
+		if len(methods) == 0 {
+			continue
+		}
+		resumeCmds, err := buildResumeCommands(s, methods, blackoutJSONPath, "^true:(.*,)?%s(,|$)")
+		if err != nil {
+			return "", err
+		}
+		cmds = append(cmds, resumeCmds...)
+		cmds = append(cmds, fmt.Sprintf("kubectl annotate backupschedule %s -n %s %s-", s.Name, s.Namespace, maintenanceMethodsAnnotationKey))
+	}
+	cmds = append(cmds, fmt.Sprintf("kubectl delete cronjob %s -n %s", cronJobName, c.Namespace))
+	return strings.Join(cmds, " && "), nil
+}
+
+// buildMaintenanceCronJob builds the one-time CronJob which disables the maintenance mode at the
+// expiry time, it matches the time once a year and deletes itself after the first run.
+func (o *maintenanceOptions) buildMaintenanceCronJob(c *appsv1alpha1.Cluster, until time.Time, script, image string) *batchv1.CronJob {
+	utc := "Etc/UTC"
+	var backoffLimit int32 = 3
+	until = until.UTC()
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      maintenanceCronJobName(c.Name),
+			Namespace: c.Namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:  c.Name,
+				constant.AppManagedByLabelKey: "kbcli",
+				maintenanceLabelKey:           "expire",
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          fmt.Sprintf("%d %d %d %d *", until.Minute(), until.Hour(), until.Day(), int(until.Month())),
+			TimeZone:          &utc,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: maintenanceSchedulerName,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{
+								{
+									Name:    "expire-maintenance",
+									Image:   image,
+									Command: []string{"sh", "-c", script},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if c.UID != "" {
+		cronJob.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: fmt.Sprintf("%s/%s", types.AppsAPIGroup, types.AppsAPIVersion),
+				Kind:       types.KindCluster,
+				Name:       c.Name,
+				UID:        c.UID,
+			},
+		}
+	}
+	return cronJob
+}
+
+func maintenanceCronJobName(clusterName string) string {
+	return clusterName + "-maintenance-expire"
+}
+
+// maintenanceSchedulerRules returns the rules of the service account used by the CronJob, which is
+// allowed to remove the annotations of the cluster, resume the backup schedules and delete itself.
+func maintenanceSchedulerRules(c *appsv1alpha1.Cluster, schedules []dpv1alpha1.BackupSchedule) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{types.AppsAPIGroup},
+			Resources:     []string{types.ResourceClusters},
+			Verbs:         []string{"get", "patch"},
+			ResourceNames: []string{c.Name},
+		},
+		{
+			APIGroups:     []string{types.K8SBatchAPIGroup},
+			Resources:     []string{types.ResourceCronJobs},
+			Verbs:         []string{"delete"},
+			ResourceNames: []string{maintenanceCronJobName(c.Name)},
+		},
+	}
+	// the empty resource names allow all backup schedules
+	if rule := backupSchedulesRule(schedules); rule != nil {
+		rules = append(rules, *rule)
+	}
+	return rules
+}
+
+// alertSilence is the silence of the alertmanager API v2
+type alertSilence struct {
+	Matchers  []alertSilenceMatcher `json:"matchers"`
+	StartsAt  time.Time             `json:"startsAt"`
+	EndsAt    time.Time             `json:"endsAt"`
+	CreatedBy string                `json:"createdBy"`
+	Comment   string                `json:"comment"`
+}
+
+type alertSilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// buildMaintenanceSilence builds the silence which mutes the alerts of the cluster until the maintenance expires
+func buildMaintenanceSilence(c *appsv1alpha1.Cluster, start, until time.Time, reason string) *alertSilence {
+	comment := fmt.Sprintf("cluster %s is in maintenance mode", c.Name)
+	if reason != "" {
+		comment += ": " + reason
+	}
+	return &alertSilence{
+		Matchers: []alertSilenceMatcher{
+			{Name: "app_kubernetes_io_instance", Value: c.Name, IsEqual: true},
+			{Name: "namespace", Value: c.Namespace, IsEqual: true},
+		},
+		StartsAt:  start.UTC(),
+		EndsAt:    until.UTC(),
+		CreatedBy: "kbcli",
+		Comment:   comment,
+	}
+}
+
+// getAlertManagerService returns the alertmanager service installed by addon, it returns nil if not found
+func getAlertManagerService(client kubernetes.Interface) (*corev1.Service, error) {
+	svcs, err := client.CoreV1().Services(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: alertManagerLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(svcs.Items) == 0 || len(svcs.Items[0].Spec.Ports) == 0 {
+		return nil, nil
+	}
+	return &svcs.Items[0], nil
+}
+
+// alertManagerProxyName returns the name to access the alertmanager through the API server service proxy
+func alertManagerProxyName(svc *corev1.Service) string {
+	return net.JoinHostPort(svc.Name, strconv.Itoa(int(svc.Spec.Ports[0].Port)))
+}
+
+// createSilence creates the silence by the alertmanager API and returns the silence ID
+func createSilence(client kubernetes.Interface, svc *corev1.Service, silence *alertSilence) (string, error) {
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return "", err
+	}
+	data, err := client.CoreV1().RESTClient().Post().Namespace(svc.Namespace).Resource("services").
+		Name(alertManagerProxyName(svc)).SubResource("proxy").Suffix("api/v2/silences").
+		SetHeader("Content-Type", "application/json").Body(body).DoRaw(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	resp := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	if err = json.Unmarshal(data, &resp); err != nil {
+		return "", err
+	}
+	return resp.SilenceID, nil
+}
+
+// expireSilence expires the silence by the alertmanager API
+func expireSilence(client kubernetes.Interface, svc *corev1.Service, id string) error {
+	_, err := client.CoreV1().RESTClient().Delete().Namespace(svc.Namespace).Resource("services").
+		Name(alertManagerProxyName(svc)).SubResource("proxy").Suffix("api/v2/silence", id).DoRaw(context.TODO())
+	return err
+}
+
+// patchClusterAnnotations patches the annotations of the cluster, the annotation is removed if the value is nil
+func (o *maintenanceOptions) patchClusterAnnotations(annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = o.dynamic.Resource(types.ClusterGVR()).Namespace(o.namespace).Patch(context.TODO(), o.clusterName,
+		k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func (o *maintenanceOptions) enable() error {
+	if err := action.CheckExportUnsupported("maintenance enable"); err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	if until, ok := maintenanceUntil(c); ok {
+		return fmt.Errorf("cluster %s is already in maintenance mode until %s, disable it first to change the duration",
+			c.Name, until.UTC().Format(time.RFC3339))
+	}
+	if err = CheckGitOps(c, o.forceDirect, o.Out); err != nil {
+		return err
+	}
+
+	// the CronJob runs at the minute, so the expiry time is rounded up to the minute
+	now := time.Now()
+	until := now.Add(o.duration).Add(time.Minute - time.Nanosecond).Truncate(time.Minute).UTC()
+
+	// suspend the enabled backup schedule policies
+	schedules, err := o.getBackupSchedules()
+	if err != nil {
+		return err
+	}
+	var suspended []string
+	for i := range schedules {
+		s := &schedules[i]
+		// the methods suspended by an expired maintenance are kept to be resumed, and the methods
+		// suspended by a blackout window are recorded to be kept suspended when the window ends
+		methods := maintenanceMethods(s)
+		for _, m := range blackoutSuspendedMethods(s) {
+			if !slices.Contains(methods, m) {
+				methods = append(methods, m)
+			}
+		}
+		for _, p := range s.Spec.Schedules {
+			if p.Enabled != nil && *p.Enabled && !slices.Contains(methods, p.BackupMethod) {
+				methods = append(methods, p.BackupMethod)
+			}
+		}
+		if len(methods) == 0 {
+			continue
+		}
+		if err = o.updateBackupSchedule(s, methods, true); err != nil {
+			return err
+		}
+		suspended = append(suspended, methods...)
+	}
+
+	// the CronJob is built after the schedules are updated, so it resumes the recorded methods
+	script, err := buildMaintenanceScript(c, schedules, maintenanceCronJobName(c.Name))
+	if err != nil {
+		return err
+	}
+	image, err := schedulerImage(o.client, o.image)
+	if err != nil {
+		return err
+	}
+	if err = createSchedulerRBAC(ctx, o.client, o.namespace, maintenanceSchedulerName, maintenanceSchedulerRules(c, schedules), nil); err != nil {
+		return err
+	}
+	cronJob := o.buildMaintenanceCronJob(c, until, script, image)
+	if err = o.client.BatchV1().CronJobs(o.namespace).Delete(ctx, cronJob.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if _, err = o.client.BatchV1().CronJobs(o.namespace).Create(ctx, cronJob, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	// mute the alerts of the cluster, it's skipped if the alertmanager is not installed
+	annotations := map[string]interface{}{
+		maintenanceUntilAnnotationKey:   until.Format(time.RFC3339),
+		maintenanceReasonAnnotationKey:  nil,
+		maintenanceSilenceAnnotationKey: nil,
+	}
+	if o.reason != "" {
+		annotations[maintenanceReasonAnnotationKey] = o.reason
+	}
+	svc, err := getAlertManagerService(o.client)
+	if err != nil {
+		return err
+	}
+	if svc == nil {
+		fmt.Fprintf(o.ErrOut, "Warning: alertmanager is not found, the alerts of cluster %s are not muted\n", c.Name)
+	} else {
+		id, err := createSilence(o.client, svc, buildMaintenanceSilence(c, now, until, o.reason))
+		if err != nil {
+			return fmt.Errorf("failed to mute the alerts of cluster %s: %v", c.Name, err)
+		}
+		annotations[maintenanceSilenceAnnotationKey] = id
+	}
+	if err = o.patchClusterAnnotations(annotations); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Cluster %s is in maintenance mode until %s\n", c.Name, until.Format(time.RFC3339))
+	if len(suspended) > 0 {
+		fmt.Fprintf(o.Out, "The scheduled backups are suspended: %s\n", strings.Join(suspended, ","))
+	}
+	if svc != nil {
+		fmt.Fprintln(o.Out, "The alerts of the cluster are muted")
+	}
+	fmt.Fprintln(o.Out, "The risky operations such as restart, stop, scaling and upgrade are refused unless --force is specified")
+	return nil
+}
+
+func (o *maintenanceOptions) disable() error {
+	if err := action.CheckExportUnsupported("maintenance disable"); err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.Annotations[maintenanceUntilAnnotationKey]; !ok {
+		return fmt.Errorf("cluster %s is not in maintenance mode", c.Name)
+	}
+	if err = CheckGitOps(c, o.forceDirect, o.Out); err != nil {
+		return err
+	}
+
+	schedules, err := o.getBackupSchedules()
+	if err != nil {
+		return err
+	}
+	for i := range schedules {
+		s := &schedules[i]
+		if _, ok := s.Annotations[maintenanceMethodsAnnotationKey]; !ok {
+			continue
+		}
+		if err = o.updateBackupSchedule(s, maintenanceMethods(s), false); err != nil {
+			return err
+		}
+	}
+
+	if id := c.Annotations[maintenanceSilenceAnnotationKey]; id != "" {
+		svc, err := getAlertManagerService(o.client)
+		if err != nil {
+			return err
+		}
+		if svc == nil {
+			fmt.Fprintf(o.ErrOut, "Warning: alertmanager is not found, failed to unmute the alerts of cluster %s\n", c.Name)
+		} else if err = expireSilence(o.client, svc, id); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to unmute the alerts of cluster %s: %v", c.Name, err)
+		}
+	}
+
+	if err = o.client.BatchV1().CronJobs(o.namespace).Delete(ctx, maintenanceCronJobName(c.Name), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err = o.patchClusterAnnotations(map[string]interface{}{
+		maintenanceUntilAnnotationKey:   nil,
+		maintenanceReasonAnnotationKey:  nil,
+		maintenanceSilenceAnnotationKey: nil,
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "The maintenance mode of cluster %s is disabled\n", c.Name)
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster maintenance", func() {
+	const scheduleName = "mycluster-backup-schedule"
+
+	var (
+		out *bytes.Buffer
+		o   *maintenanceOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		o = &maintenanceOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			duration:    2 * time.Hour,
+			reason:      "migrate the storage",
+			dynamic: clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace),
+				clitesting.FakeBackupSchedule(scheduleName, "policy")),
+			client:    fake.NewSimpleClientset(clitesting.FakeKBDeploy("")),
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	getCluster := func() *appsv1alpha1.Cluster {
+		c, err := cluster.GetClusterByName(o.dynamic, clitesting.ClusterName, clitesting.Namespace)
+		Expect(err).Should(Succeed())
+		return c
+	}
+
+	getSchedule := func() *dpv1alpha1.BackupSchedule {
+		schedule := &dpv1alpha1.BackupSchedule{}
+		Expect(cluster.GetK8SClientObject(o.dynamic, schedule, types.BackupScheduleGVR(), clitesting.Namespace, scheduleName)).Should(Succeed())
+		return schedule
+	}
+
+	It("validate", func() {
+		o.duration = 0
+		Expect(o.validate()).Should(MatchError(ContainSubstring("at least 1m")))
+		o.duration = time.Hour
+		Expect(o.validate()).Should(Succeed())
+	})
+
+	It("build the alert silence", func() {
+		c := clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace)
+		now := time.Now()
+		silence := buildMaintenanceSilence(c, now, now.Add(time.Hour), "upgrade")
+		Expect(silence.Matchers).Should(HaveLen(2))
+		Expect(silence.Matchers[0].Value).Should(Equal(clitesting.ClusterName))
+		Expect(silence.EndsAt.Sub(silence.StartsAt)).Should(Equal(time.Hour))
+		Expect(silence.Comment).Should(ContainSubstring("upgrade"))
+	})
+
+	It("enable and disable the maintenance mode", func() {
+		By("enable the maintenance mode")
+		Expect(o.enable()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("alertmanager is not found"))
+		c := getCluster()
+		until, ok := maintenanceUntil(c)
+		Expect(ok).Should(BeTrue())
+		Expect(until).Should(BeTemporally(">", time.Now().Add(2*time.Hour-time.Minute)))
+		Expect(c.Annotations).Should(HaveKeyWithValue(maintenanceReasonAnnotationKey, o.reason))
+		schedule := getSchedule()
+		Expect(schedule.Annotations).Should(HaveKeyWithValue(maintenanceMethodsAnnotationKey, clitesting.BackupMethodName))
+		Expect(*schedule.Spec.Schedules[0].Enabled).Should(BeFalse())
+
+		cronJob, err := o.client.BatchV1().CronJobs(clitesting.Namespace).Get(context.TODO(), maintenanceCronJobName(clitesting.ClusterName), metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJob.Spec.Schedule).Should(Equal(fmt.Sprintf("%d %d %d %d *", until.Minute(), until.Hour(), until.Day(), int(until.Month()))))
+		script := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command[2]
+		Expect(script).Should(ContainSubstring("kubectl annotate cluster " + clitesting.ClusterName))
+		Expect(script).Should(ContainSubstring("kubectl patch backupschedule " + scheduleName))
+		Expect(script).Should(ContainSubstring("kubectl delete cronjob " + cronJob.Name))
+		role, err := o.client.RbacV1().Roles(clitesting.Namespace).Get(context.TODO(), maintenanceSchedulerName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		for _, rule := range role.Rules {
+			Expect(rule.ResourceNames).ShouldNot(BeEmpty())
+		}
+		Expect(role.Rules[2].ResourceNames).Should(Equal([]string{scheduleName}))
+
+		By("refuse the risky operations")
+		ops := &OperationsOptions{OpsType: appsv1alpha1.RestartType}
+		Expect(ops.checkMaintenance(c)).Should(MatchError(ContainSubstring("maintenance mode")))
+		ops.force = true
+		Expect(ops.checkMaintenance(c)).Should(Succeed())
+		ops = &OperationsOptions{OpsType: appsv1alpha1.StartType}
+		Expect(ops.checkMaintenance(c)).Should(Succeed())
+
+		By("enable again")
+		Expect(o.enable()).Should(MatchError(ContainSubstring("already in maintenance mode")))
+
+		By("disable the maintenance mode")
+		Expect(o.disable()).Should(Succeed())
+		c = getCluster()
+		Expect(c.Annotations).ShouldNot(HaveKey(maintenanceUntilAnnotationKey))
+		schedule = getSchedule()
+		Expect(schedule.Annotations).ShouldNot(HaveKey(maintenanceMethodsAnnotationKey))
+		Expect(*schedule.Spec.Schedules[0].Enabled).Should(BeTrue())
+		cronJobs, err := o.client.BatchV1().CronJobs(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(BeEmpty())
+		ops = &OperationsOptions{OpsType: appsv1alpha1.RestartType}
+		Expect(ops.checkMaintenance(c)).Should(Succeed())
+		Expect(o.disable()).Should(MatchError(ContainSubstring("not in maintenance mode")))
+	})
+
+	It("refuse the cluster managed by GitOps", func() {
+		c := clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace)
+		c.SetLabels(map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"})
+		o.dynamic = clitesting.FakeDynamicClient(c)
+		Expect(o.enable()).Should(MatchError(ContainSubstring("--force-direct")))
+		_, ok := maintenanceUntil(getCluster())
+		Expect(ok).Should(BeFalse())
+
+		o.forceDirect = true
+		Expect(o.enable()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("managed by Flux"))
+		o.forceDirect = false
+		Expect(o.disable()).Should(MatchError(ContainSubstring("--force-direct")))
+	})
+
+	It("keep the backups suspended by the blackout windows", func() {
+		By("suspend the backups in a blackout window")
+		now := time.Now().UTC()
+		blackout := &editBackupScheduleOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			blackouts:   []string{now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")},
+			dynamic:     o.dynamic,
+			client:      o.client,
+			IOStreams:   o.IOStreams,
+		}
+		Expect(blackout.validate()).Should(Succeed())
+		Expect(blackout.run()).Should(Succeed())
+		Expect(*getSchedule().Spec.Schedules[0].Enabled).Should(BeFalse())
+
+		By("enable the maintenance mode, the expiry keeps the methods suspended by the blackout window")
+		Expect(o.enable()).Should(Succeed())
+		schedule := getSchedule()
+		Expect(schedule.Annotations).Should(HaveKeyWithValue(maintenanceMethodsAnnotationKey, clitesting.BackupMethodName))
+		cronJob, err := o.client.BatchV1().CronJobs(clitesting.Namespace).Get(context.TODO(), maintenanceCronJobName(clitesting.ClusterName), metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		script := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command[2]
+		Expect(script).Should(ContainSubstring(`dataprotection\.kubeblocks\.io/blackout-suspended`))
+		Expect(script).Should(ContainSubstring("grep -Eq '^true:(.*,)?" + clitesting.BackupMethodName + "(,|$)' || kubectl patch backupschedule " + scheduleName))
+
+		By("the blackout window ends in the maintenance, the backups are kept suspended")
+		script, err = buildBlackoutScript([]dpv1alpha1.BackupSchedule{*schedule}, false)
+		Expect(err).Should(Succeed())
+		Expect(script).Should(ContainSubstring(`dataprotection\.kubeblocks\.io/maintenance-methods`))
+		Expect(script).Should(ContainSubstring("grep -Eq '(^|,)" + clitesting.BackupMethodName + "(,|$)' || kubectl patch backupschedule " + scheduleName))
+		blackout.blackouts = nil
+		blackout.clearBlackouts = true
+		Expect(blackout.validate()).Should(Succeed())
+		Expect(blackout.run()).Should(Succeed())
+		Expect(*getSchedule().Spec.Schedules[0].Enabled).Should(BeFalse())
+
+		By("disable the maintenance mode, the backups are resumed")
+		Expect(o.disable()).Should(Succeed())
+		Expect(*getSchedule().Spec.Schedules[0].Enabled).Should(BeTrue())
+	})
+
+	It("keep the backups suspended by the maintenance mode", func() {
+		Expect(o.enable()).Should(Succeed())
+		now := time.Now().UTC()
+		blackout := &editBackupScheduleOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			blackouts:   []string{now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")},
+			dynamic:     o.dynamic,
+			client:      o.client,
+			IOStreams:   o.IOStreams,
+		}
+		Expect(blackout.validate()).Should(Succeed())
+		Expect(blackout.run()).Should(Succeed())
+		schedule := getSchedule()
+		Expect(schedule.Annotations).Should(HaveKeyWithValue(blackoutMethodsAnnotationKey, clitesting.BackupMethodName))
+
+		By("disable the maintenance mode in the blackout window, the backups are kept suspended")
+		Expect(o.disable()).Should(Succeed())
+		Expect(*getSchedule().Spec.Schedules[0].Enabled).Should(BeFalse())
+
+		By("the blackout window is removed, the backups are resumed")
+		blackout.blackouts = nil
+		blackout.clearBlackouts = true
+		Expect(blackout.validate()).Should(Succeed())
+		Expect(blackout.run()).Should(Succeed())
+		Expect(*getSchedule().Spec.Schedules[0].Enabled).Should(BeTrue())
+	})
+})
@@ -0,0 +1,386 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var metricsExample = templates.Examples(`
+	# Show the key metrics of cluster mycluster in the last hour
+	kbcli cluster metrics mycluster
+
+	# Show the key metrics of component mysql in the last 6 hours
+	kbcli cluster metrics mycluster --component mysql --range 6h
+
+	# Query an external prometheus server instead of the prometheus addon
+	kbcli cluster metrics mycluster --prometheus-url http://prometheus.example.com:9090`)
+
+const (
+	// prometheusServerLabel is the label selector of the prometheus server service installed by addon
+	prometheusServerLabel = "app=prometheus,component=server,release=kb-addon-prometheus"
+	// sparklinePoints is the number of samples queried for each metric
+	sparklinePoints = 40
+)
+
+var sparklineTicks = []rune("▁▂▃▄▅▆▇█")
+
+// engineMetric is a key metric of a database engine, the query is a PromQL template in which
+// %[1]s is replaced by the label matchers of the component.
+type engineMetric struct {
+	name   string
+	query  string
+	format string
+}
+
+// diskUsageMetric is the percentage of the data volume used, it is collected by kubelet and
+// is supported by all engines.
+var diskUsageMetric = engineMetric{
+	name:   "Disk Usage",
+	query:  `max(kubelet_volume_stats_used_bytes{%[2]s} / kubelet_volume_stats_capacity_bytes{%[2]s}) * 100`,
+	format: "%.1f%%",
+}
+
+// engineMetrics are the key metrics of engines exported by the KubeBlocks metrics exporters,
+// the engine is matched by the definition name of the component.
+var engineMetrics = []struct {
+	engine  string
+	metrics []engineMetric
+}{
+	{
+		engine: "mysql",
+		metrics: []engineMetric{
+			{name: "QPS", query: `sum(rate(mysql_global_status_queries{%[1]s}[1m]))`, format: "%.2f/s"},
+			{name: "Connections", query: `sum(mysql_global_status_threads_connected{%[1]s})`, format: "%.0f"},
+			{name: "Replication Lag", query: `max(mysql_slave_status_seconds_behind_master{%[1]s})`, format: "%.2fs"},
+		},
+	},
+	{
+		engine: "postgresql",
+		metrics: []engineMetric{
+			{name: "QPS", query: `sum(rate(pg_stat_database_xact_commit{%[1]s}[1m]) + rate(pg_stat_database_xact_rollback{%[1]s}[1m]))`, format: "%.2f/s"},
+			{name: "Connections", query: `sum(pg_stat_database_numbackends{%[1]s})`, format: "%.0f"},
+			{name: "Replication Lag", query: `max(pg_replication_lag{%[1]s})`, format: "%.2fs"},
+		},
+	},
+	{
+		engine: "redis",
+		metrics: []engineMetric{
+			{name: "QPS", query: `sum(rate(redis_commands_processed_total{%[1]s}[1m]))`, format: "%.2f/s"},
+			{name: "Connections", query: `sum(redis_connected_clients{%[1]s})`, format: "%.0f"},
+			{name: "Replication Lag", query: `max(redis_connected_slave_lag_seconds{%[1]s})`, format: "%.2fs"},
+		},
+	},
+	{
+		engine: "mongodb",
+		metrics: []engineMetric{
+			{name: "QPS", query: `sum(rate(mongodb_op_counters_total{%[1]s}[1m]))`, format: "%.2f/s"},
+			{name: "Connections", query: `sum(mongodb_connections{state="current",%[1]s})`, format: "%.0f"},
+			{name: "Replication Lag", query: `max(mongodb_mongod_replset_member_replication_lag{%[1]s})`, format: "%.2fs"},
+		},
+	},
+}
+
+// promQueryRangeFunc queries the prometheus range API and returns the values of the first series
+type promQueryRangeFunc func(query string, start, end time.Time, step time.Duration) ([]float64, error)
+
+type MetricsOptions struct {
+	clusterName   string
+	componentName string
+	namespace     string
+	timeRange     time.Duration
+	prometheusURL string
+
+	client     kubernetes.Interface
+	dynamic    dynamic.Interface
+	queryRange promQueryRangeFunc
+	now        func() time.Time
+	genericiooptions.IOStreams
+}
+
+func NewMetricsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &MetricsOptions{IOStreams: streams, now: time.Now}
+	cmd := &cobra.Command{
+		Use:               "metrics NAME",
+		Short:             "Show the key metrics of the cluster queried from prometheus.",
+		Example:           metricsExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentFlag(f, cmd, &o.componentName, "Only show the metrics of the specified component")
+	cmd.Flags().DurationVar(&o.timeRange, "range", time.Hour, "The time range of the metrics to query, such as 30m, 1h and 24h")
+	cmd.Flags().StringVar(&o.prometheusURL, "prometheus-url", "", "The URL of the prometheus server, if not specified, the prometheus addon server is queried through the API server proxy")
+	return cmd
+}
+
+func (o *MetricsOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to show the metrics of one cluster")
+	}
+	if o.timeRange <= 0 {
+		return fmt.Errorf("the time range must be greater than 0")
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.client, err = f.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	if o.prometheusURL != "" {
+		o.queryRange = newHTTPPromQuerier(o.prometheusURL)
+		return nil
+	}
+	o.queryRange, err = newProxyPromQuerier(o.client)
+	return err
+}
+
+func (o *MetricsOptions) run() error {
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	var comps []appsv1alpha1.ClusterComponentSpec
+	for _, comp := range c.Spec.ComponentSpecs {
+		if o.componentName == "" || comp.Name == o.componentName {
+			comps = append(comps, comp)
+		}
+	}
+	if len(comps) == 0 {
+		return fmt.Errorf("component %s is not found in cluster %s", o.componentName, o.clusterName)
+	}
+
+	end := o.now()
+	start := end.Add(-o.timeRange)
+	step := o.timeRange / sparklinePoints
+	if step < time.Second {
+		step = time.Second
+	}
+
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("COMPONENT", "METRIC", "CURRENT", "MIN", "MAX", "TREND")
+	for _, comp := range comps {
+		for _, m := range getComponentMetrics(c.Spec.ClusterDefRef, comp.ComponentDefRef) {
+			query := buildMetricQuery(m, o.namespace, o.clusterName, comp.Name)
+			values, err := o.queryRange(query, start, end, step)
+			if err != nil {
+				return fmt.Errorf("failed to query metric %s of component %s: %v", m.name, comp.Name, err)
+			}
+			cur, min, max, ok := summarizeValues(values)
+			if !ok {
+				tbl.AddRow(comp.Name, m.name, printer.NoneString, printer.NoneString, printer.NoneString, "")
+				continue
+			}
+			tbl.AddRow(comp.Name, m.name, fmt.Sprintf(m.format, cur), fmt.Sprintf(m.format, min),
+				fmt.Sprintf(m.format, max), sparkline(values))
+		}
+	}
+	tbl.Print()
+	fmt.Fprintf(o.Out, "\nMetrics in the last %s, open the grafana dashboard for details: kbcli dashboard open grafana --cluster %s\n",
+		o.timeRange.String(), o.clusterName)
+	return nil
+}
+
+// getComponentMetrics returns the key metrics of the component, the disk usage is always included
+// and the engine metrics are only included if the engine is recognized.
+func getComponentMetrics(clusterDef, compDef string) []engineMetric {
+	var metrics []engineMetric
+	for _, e := range engineMetrics {
+		if strings.Contains(compDef, e.engine) || strings.Contains(clusterDef, e.engine) {
+			metrics = append(metrics, e.metrics...)
+			break
+		}
+	}
+	return append(metrics, diskUsageMetric)
+}
+
+// buildMetricQuery builds the PromQL of the metric for the component
+func buildMetricQuery(m engineMetric, namespace, clusterName, compName string) string {
+	compMatchers := fmt.Sprintf(`namespace="%s",app_kubernetes_io_instance="%s",apps_kubeblocks_io_component_name="%s"`,
+		namespace, clusterName, compName)
+	pvcMatchers := fmt.Sprintf(`namespace="%s",persistentvolumeclaim=~"[a-z0-9-]+-%s-%s-[0-9]+"`,
+		namespace, clusterName, compName)
+	return fmt.Sprintf(m.query, compMatchers, pvcMatchers)
+}
+
+// summarizeValues returns the current, minimum and maximum values ignoring NaN
+func summarizeValues(values []float64) (cur, min, max float64, ok bool) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		cur, ok = v, true
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return cur, min, max, ok
+}
+
+// sparkline renders the values as a line of unicode block characters, the NaN values are
+// rendered as spaces.
+func sparkline(values []float64) string {
+	_, min, max, ok := summarizeValues(values)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case max == min:
+			b.WriteRune(sparklineTicks[0])
+		default:
+			idx := int((v - min) / (max - min) * float64(len(sparklineTicks)-1))
+			b.WriteRune(sparklineTicks[idx])
+		}
+	}
+	return b.String()
+}
+
+// promQueryRangeResponse is the response of the prometheus query_range API
+type promQueryRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parsePromMatrix parses the values of the first series in the query_range response
+func parsePromMatrix(data []byte) ([]float64, error) {
+	resp := &promQueryRangeResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse the prometheus response: %v", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", resp.Error)
+	}
+	if len(resp.Data.Result) == 0 {
+		return nil, nil
+	}
+	var values []float64
+	for _, sample := range resp.Data.Result[0].Values {
+		if len(sample) != 2 {
+			continue
+		}
+		s, _ := sample[1].(string)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			v = math.NaN()
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func buildQueryRangeParams(query string, start, end time.Time, step time.Duration) map[string]string {
+	return map[string]string{
+		"query": query,
+		"start": strconv.FormatInt(start.Unix(), 10),
+		"end":   strconv.FormatInt(end.Unix(), 10),
+		"step":  strconv.FormatInt(int64(step.Seconds()), 10),
+	}
+}
+
+// newProxyPromQuerier queries the prometheus server installed by addon through the API server
+// service proxy, so it works without port-forwarding.
+func newProxyPromQuerier(client kubernetes.Interface) (promQueryRangeFunc, error) {
+	svcs, err := client.CoreV1().Services(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: prometheusServerLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(svcs.Items) == 0 || len(svcs.Items[0].Spec.Ports) == 0 {
+		return nil, fmt.Errorf("prometheus server is not found, please enable it by \"kbcli addon enable prometheus\" or specify --prometheus-url")
+	}
+	svc := svcs.Items[0]
+	port := strconv.Itoa(int(svc.Spec.Ports[0].Port))
+	return func(query string, start, end time.Time, step time.Duration) ([]float64, error) {
+		data, err := client.CoreV1().Services(svc.Namespace).ProxyGet("http", svc.Name, port, "/api/v1/query_range",
+			buildQueryRangeParams(query, start, end, step)).DoRaw(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		return parsePromMatrix(data)
+	}, nil
+}
+
+// newHTTPPromQuerier queries the prometheus server by its URL
+func newHTTPPromQuerier(serverURL string) promQueryRangeFunc {
+	return func(query string, start, end time.Time, step time.Duration) ([]float64, error) {
+		params := url.Values{}
+		for k, v := range buildQueryRangeParams(query, start, end, step) {
+			params.Set(k, v)
+		}
+		resp, err := http.Get(strings.TrimSuffix(serverURL, "/") + "/api/v1/query_range?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return parsePromMatrix(data)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("cluster metrics", func() {
+	const (
+		namespace   = "test"
+		clusterName = "mycluster"
+	)
+
+	It("build metric queries", func() {
+		metrics := getComponentMetrics("apecloud-mysql", "mysql")
+		Expect(metrics).Should(HaveLen(4))
+		Expect(metrics[0].name).Should(Equal("QPS"))
+		Expect(metrics[3]).Should(Equal(diskUsageMetric))
+
+		query := buildMetricQuery(metrics[1], namespace, clusterName, "mysql")
+		Expect(query).Should(Equal(`sum(mysql_global_status_threads_connected{namespace="test",app_kubernetes_io_instance="mycluster",apps_kubeblocks_io_component_name="mysql"})`))
+		query = buildMetricQuery(diskUsageMetric, namespace, clusterName, "mysql")
+		Expect(query).Should(ContainSubstring(`persistentvolumeclaim=~"[a-z0-9-]+-mycluster-mysql-[0-9]+"`))
+
+		// unknown engine only has the disk usage metric
+		Expect(getComponentMetrics("unknown", "unknown")).Should(Equal([]engineMetric{diskUsageMetric}))
+	})
+
+	It("render sparkline", func() {
+		Expect(sparkline(nil)).Should(BeEmpty())
+		Expect(sparkline([]float64{1, 1, 1})).Should(Equal("▁▁▁"))
+		Expect(sparkline([]float64{0, math.NaN(), 7, 14})).Should(Equal("▁ ▄█"))
+
+		cur, min, max, ok := summarizeValues([]float64{3, 1, math.NaN(), 5, 2, math.NaN()})
+		Expect(ok).Should(BeTrue())
+		Expect([]float64{cur, min, max}).Should(Equal([]float64{2, 1, 5}))
+		_, _, _, ok = summarizeValues([]float64{math.NaN()})
+		Expect(ok).Should(BeFalse())
+	})
+
+	It("parse prometheus response", func() {
+		values, err := parsePromMatrix([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1700000000,"1.5"],[1700000060,"NaN"],[1700000120,"3"]]}]}}`))
+		Expect(err).Should(Succeed())
+		Expect(values).Should(HaveLen(3))
+		Expect(values[0]).Should(Equal(1.5))
+		Expect(math.IsNaN(values[1])).Should(BeTrue())
+
+		values, err = parsePromMatrix([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+		Expect(err).Should(Succeed())
+		Expect(values).Should(BeEmpty())
+
+		_, err = parsePromMatrix([]byte(`{"status":"error","error":"bad query"}`))
+		Expect(err).Should(MatchError(ContainSubstring("bad query")))
+	})
+
+	It("discover prometheus server", func() {
+		_, err := newProxyPromQuerier(kubefakeclient.NewSimpleClientset())
+		Expect(err).Should(MatchError(ContainSubstring("prometheus server is not found")))
+
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kb-addon-prometheus-server",
+				Namespace: "kb-system",
+				Labels:    map[string]string{"app": "prometheus", "component": "server", "release": "kb-addon-prometheus"},
+			},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		}
+		querier, err := newProxyPromQuerier(kubefakeclient.NewSimpleClientset(svc))
+		Expect(err).Should(Succeed())
+		Expect(querier).ShouldNot(BeNil())
+	})
+
+	It("run", func() {
+		now := time.Unix(1700000000, 0)
+		var queries []string
+		out := &bytes.Buffer{}
+		o := &MetricsOptions{
+			clusterName: clusterName,
+			namespace:   namespace,
+			timeRange:   time.Hour,
+			dynamic:     clitesting.FakeDynamicClient(clitesting.FakeCluster(clusterName, namespace)),
+			now:         func() time.Time { return now },
+			queryRange: func(query string, start, end time.Time, step time.Duration) ([]float64, error) {
+				Expect(end).Should(Equal(now))
+				Expect(start).Should(Equal(now.Add(-time.Hour)))
+				Expect(step).Should(Equal(90 * time.Second))
+				queries = append(queries, query)
+				return []float64{10, 20, 15}, nil
+			},
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+		Expect(o.run()).Should(Succeed())
+		Expect(queries).Should(HaveLen(2))
+		Expect(out.String()).Should(ContainSubstring("Disk Usage"))
+		Expect(out.String()).Should(ContainSubstring("15.0%"))
+
+		o.componentName = "not-exist"
+		Expect(o.run()).Should(MatchError(ContainSubstring("component not-exist is not found")))
+	})
+})
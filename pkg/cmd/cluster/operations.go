@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/spf13/cobra"
@@ -86,6 +87,9 @@ type OperationsOptions struct {
 	ForceRestart    bool               `json:"forceRestart"`
 	FileContent     string             `json:"fileContent"`
 	HasPatch        bool               `json:"hasPatch"`
+	// Configurations are the config templates of the component to update in one OpsRequest,
+	// the single config template and file above are ignored if it's not empty.
+	Configurations []appsv1alpha1.ConfigurationItem `json:"configurations,omitempty"`
 
 	// VolumeExpansion options.
 	// VCTNames VolumeClaimTemplate names
@@ -97,9 +101,24 @@ type OperationsOptions struct {
 	ExposeEnabled string                                 `json:"-"`
 	Services      []appsv1alpha1.ClusterComponentService `json:"services,omitempty"`
 
+	// ExposeHostname is the hostname of the exposed endpoint, the DNS records are created by external-dns
+	ExposeHostname string        `json:"-"`
+	ExposeDNSTTL   int64         `json:"-"`
+	ExposeTimeout  time.Duration `json:"-"`
+
 	// Switchover options
 	Component string `json:"component"`
 	Instance  string `json:"instance"`
+
+	gitOps   gitOpsOptions
+	schedule scheduleOptions
+	hook     restartHookOptions
+
+	// switchoverRoles are the primary or leader roles of the component to switchover
+	switchoverRoles []string
+
+	// force creates the risky OpsRequest even if the cluster is in maintenance mode
+	force bool
 }
 
 func newBaseOperationsOptions(f cmdutil.Factory, streams genericiooptions.IOStreams,
@@ -134,16 +153,60 @@ func newBaseOperationsOptions(f cmdutil.Factory, streams genericiooptions.IOStre
 
 // addCommonFlags adds common flags for operations command
 func (o *OperationsOptions) addCommonFlags(cmd *cobra.Command, f cmdutil.Factory) {
-	// add print flags
-	printer.AddOutputFlagForCreate(cmd, &o.Format, false)
+	// add dry-run and print flags
+	o.AddDryRunFlags(cmd, false)
 
 	cmd.Flags().StringVar(&o.OpsRequestName, "name", "", "OpsRequest name. if not specified, it will be randomly generated ")
 	cmd.Flags().IntVar(&o.TTLSecondsAfterSucceed, "ttlSecondsAfterSucceed", 0, "Time to live after the OpsRequest succeed")
-	cmd.Flags().StringVar(&o.DryRun, "dry-run", "none", `Must be "client", or "server". If with client strategy, only print the object that would be sent, and no data is actually sent. If with server strategy, submit the server-side request, but no data is persistent.`)
-	cmd.Flags().Lookup("dry-run").NoOptDefVal = "unchanged"
 	if o.HasComponentNamesFlag {
 		flags.AddComponentsFlag(f, cmd, &o.ComponentNames, "Component names to this operations")
 	}
+	o.gitOps.addFlags(cmd)
+	// the command may define --force with more semantics, such as switchover
+	if cmd.Flags().Lookup("force") == nil {
+		cmd.Flags().BoolVar(&o.force, "force", false, "Create the OpsRequest even if the cluster is in maintenance mode")
+	}
+}
+
+// Run creates the OpsRequest. If the cluster is managed by ArgoCD or Flux, the equivalent patch
+// is written to the file specified by --gitops-patch-file, or the operation is rejected unless
+// --force-direct is specified.
+func (o *OperationsOptions) Run() error {
+	if (o.DryRun == "" || o.DryRun == "none") && !action.ExportEnabled() {
+		c, err := cluster.GetClusterByName(o.Dynamic, o.Name, o.Namespace)
+		if err != nil {
+			return err
+		}
+		if err = o.checkMaintenance(c); err != nil {
+			return err
+		}
+		if err = o.gitOps.check(c, o.buildGitOpsPatch, o.Out); err != nil || o.gitOps.patchWritten {
+			return err
+		}
+		if o.schedule.enabled() {
+			return o.runScheduled(c)
+		}
+		if o.hook.enabled() {
+			return o.runWithHooks(c.Name)
+		}
+	}
+	if o.schedule.enabled() {
+		return o.runScheduled(&appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: o.Name, Namespace: o.Namespace}})
+	}
+	return o.CreateOptions.Run()
+}
+
+// runWithHooks executes the pre-restart hooks before creating the OpsRequest, and the post-restart
+// hooks after the instances are restarted.
+func (o *OperationsOptions) runWithHooks(clusterName string) error {
+	if err := o.runPreRestartHooks(clusterName); err != nil {
+		return err
+	}
+	if err := o.CreateOptions.Run(); err != nil {
+		return err
+	}
+	// the name is set to the created OpsRequest
+	return o.runPostRestartHooks(o.Name)
 }
 
 // CompleteRestartOps restarts all components of the cluster
@@ -342,6 +405,17 @@ func (o *OperationsOptions) Validate() error {
 			return err
 		}
 	}
+	if err = o.schedule.validate(); err != nil {
+		return err
+	}
+	if o.hook.enabled() {
+		if o.schedule.enabled() {
+			return fmt.Errorf("the restart hooks can not be used with the scheduled restart")
+		}
+		if err = o.hook.validate(o.ComponentNames); err != nil {
+			return err
+		}
+	}
 	if !o.autoApprove && o.DryRun == "none" {
 		return prompt.Confirm([]string{o.Name}, o.In, "", "")
 	}
@@ -435,6 +509,7 @@ func (o *OperationsOptions) validatePromote(cluster *appsv1alpha1.Cluster) error
 		if err := getAndValidatePod(targetRoles...); err != nil {
 			return err
 		}
+		o.switchoverRoles = targetRoles
 		return nil
 	}
 
@@ -486,6 +561,7 @@ func (o *OperationsOptions) validatePromote(cluster *appsv1alpha1.Cluster) error
 		if err := getAndValidatePod(targetRole); err != nil {
 			return err
 		}
+		o.switchoverRoles = []string{targetRole}
 		return nil
 	}
 
@@ -508,6 +584,13 @@ func (o *OperationsOptions) validateExpose() error {
 	default:
 		return fmt.Errorf("invalid value for enable flag: %s", o.ExposeEnabled)
 	}
+
+	if err := o.validateExposeHostname(); err != nil {
+		return err
+	}
+	if o.ExposeHostname != "" {
+		return o.checkExternalDNS()
+	}
 	return nil
 }
 
@@ -534,6 +617,7 @@ func (o *OperationsOptions) fillExpose() error {
 	if err != nil {
 		return err
 	}
+	annotations = o.buildExposeDNSAnnotations(annotations)
 
 	gvr := schema.GroupVersionResource{Group: types.AppsAPIGroup, Version: types.AppsAPIVersion, Resource: types.ResourceClusters}
 	unstructuredObj, err := o.Dynamic.Resource(gvr).Namespace(o.Namespace).Get(context.TODO(), o.Name, metav1.GetOptions{})
@@ -584,6 +668,18 @@ var restartExample = templates.Examples(`
 
 		# specified component to restart, separate with commas for multiple components
 		kbcli cluster restart mycluster --components=mysql
+
+		# restart the cluster in the maintenance window
+		kbcli cluster restart mycluster --schedule-at 2024-05-01T02:00:00Z
+
+		# restart the cluster at 02:00 UTC every Sunday
+		kbcli cluster restart mycluster --schedule-cron "0 2 * * 0"
+
+		# flush the caches in each instance before restarting, and register it to the service discovery after it is restarted
+		kbcli cluster restart mycluster --pre-hook-exec "redis-cli save" --post-hook-exec "/scripts/register.sh"
+
+		# restart the cluster with the hooks of the components in the file
+		kbcli cluster restart mycluster --hook-file hooks.yaml
 `)
 
 // NewRestartCmd creates a restart command
@@ -597,17 +693,46 @@ func NewRestartCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.CompleteRestartOps())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteRestartOps())
+			util.CheckErr(o.hook.complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 	o.addCommonFlags(cmd, f)
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before restarting the cluster")
+	o.schedule.addFlags(cmd)
+	o.hook.addFlags(cmd)
 	return cmd
 }
 
+// RestartCluster creates an OpsRequest to restart all components of the cluster without
+// approval and returns the OpsRequest name, it is used by the callers that are not a command,
+// such as the dashboard TUI.
+func RestartCluster(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) (string, error) {
+	o := newBaseOperationsOptions(f, streams, appsv1alpha1.RestartType, true)
+	o.Args = []string{name}
+	o.autoApprove = true
+	o.Quiet = true
+	// the caller has confirmed the restart explicitly, restart it even if it's managed by GitOps tools
+	o.gitOps.forceDirect = true
+	if err := o.Complete(); err != nil {
+		return "", err
+	}
+	o.Namespace = namespace
+	if err := o.CompleteRestartOps(); err != nil {
+		return "", err
+	}
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	if err := o.Run(); err != nil {
+		return "", err
+	}
+	return o.Name, nil
+}
+
 var upgradeExample = templates.Examples(`
 		# upgrade the cluster to the target version 
 		kbcli cluster upgrade mycluster --cluster-version=ac-mysql-8.0.30
@@ -624,9 +749,9 @@ func NewUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 	o.addCommonFlags(cmd, f)
@@ -642,6 +767,9 @@ var verticalScalingExample = templates.Examples(`
 
 		# scale the computing resources of specified components by class, run command 'kbcli class list --cluster-definition cluster-definition-name' to get available classes
 		kbcli cluster vscale mycluster --components=mysql --class=general-2c4g
+
+		# scale the computing resources at the specified time
+		kbcli cluster vscale mycluster --components=mysql --cpu=1 --memory=2Gi --schedule-at 2024-05-01T02:00:00Z
 `)
 
 // NewVerticalScalingCmd creates a vertical scaling command
@@ -655,10 +783,10 @@ func NewVerticalScalingCmd(f cmdutil.Factory, streams genericiooptions.IOStreams
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.CompleteComponentsFlag())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteComponentsFlag())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 	o.addCommonFlags(cmd, f)
@@ -666,6 +794,7 @@ func NewVerticalScalingCmd(f cmdutil.Factory, streams genericiooptions.IOStreams
 	cmd.Flags().StringVar(&o.Memory, "memory", "", "Request and limit size of component memory")
 	cmd.Flags().StringVar(&o.Class, "class", "", "Component class")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before vertically scaling the cluster")
+	o.schedule.addFlags(cmd)
 	_ = cmd.MarkFlagRequired("components")
 	return cmd
 }
@@ -673,6 +802,9 @@ func NewVerticalScalingCmd(f cmdutil.Factory, streams genericiooptions.IOStreams
 var horizontalScalingExample = templates.Examples(`
 		# expand storage resources of specified components, separate with commas for multiple components
 		kbcli cluster hscale mycluster --components=mysql --replicas=3
+
+		# scale the replicas at the specified time
+		kbcli cluster hscale mycluster --components=mysql --replicas=3 --schedule-at 2024-05-01T02:00:00Z
 `)
 
 // NewHorizontalScalingCmd creates a horizontal scaling command
@@ -686,16 +818,17 @@ func NewHorizontalScalingCmd(f cmdutil.Factory, streams genericiooptions.IOStrea
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.CompleteComponentsFlag())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteComponentsFlag())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
 	o.addCommonFlags(cmd, f)
 	cmd.Flags().IntVar(&o.Replicas, "replicas", o.Replicas, "Replicas with the specified components")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before horizontally scaling the cluster")
+	o.schedule.addFlags(cmd)
 	_ = cmd.MarkFlagRequired("replicas")
 	_ = cmd.MarkFlagRequired("components")
 	return cmd
@@ -717,10 +850,10 @@ func NewVolumeExpansionCmd(f cmdutil.Factory, streams genericiooptions.IOStreams
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.CompleteComponentsFlag())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteComponentsFlag())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 	o.addCommonFlags(cmd, f)
@@ -743,6 +876,9 @@ var (
 		
 		# Stop exposing a cluster
 		kbcli cluster expose mycluster --type vpc --enable=false
+
+		# Expose a component to public internet with a hostname, the DNS records are created by external-dns
+		kbcli cluster expose mycluster --components mysql --type internet --enable=true --hostname mysql.example.com
 	`)
 )
 
@@ -757,16 +893,19 @@ func NewExposeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.CompleteComponentsFlag())
-			cmdutil.CheckErr(o.fillExpose())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteComponentsFlag())
+			util.CheckErr(o.fillExpose())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.runExpose())
 		},
 	}
 	o.addCommonFlags(cmd, f)
 	cmd.Flags().StringVar(&o.ExposeType, "type", "", "Expose type, currently supported types are 'vpc', 'internet'")
 	cmd.Flags().StringVar(&o.ExposeEnabled, "enable", "", "Enable or disable the expose, values can be true or false")
+	cmd.Flags().StringVar(&o.ExposeHostname, "hostname", "", "The hostname of the exposed endpoint, the DNS records are created by external-dns, only one component can be exposed with a hostname")
+	cmd.Flags().Int64Var(&o.ExposeDNSTTL, "dns-ttl", 0, "The TTL in seconds of the DNS records created by external-dns, use the default TTL of the DNS provider if not specified")
+	cmd.Flags().DurationVar(&o.ExposeTimeout, "timeout", 5*time.Minute, "The time to wait for the hostname to resolve, set to 0 to skip waiting")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before exposing the cluster")
 
 	util.CheckErr(cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -796,9 +935,9 @@ func NewStopCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 	o.addCommonFlags(cmd, f)
@@ -823,9 +962,9 @@ func NewStartCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.C
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 	o.addCommonFlags(cmd, f)
@@ -890,8 +1029,8 @@ func NewCancelCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(cancelOps(o))
+			util.CheckErr(o.Complete())
+			util.CheckErr(cancelOps(o))
 		},
 	}
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before cancel the opsRequest")
@@ -920,13 +1059,13 @@ func NewPromoteCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.CompleteComponentsFlag())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteComponentsFlag())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
-	cmd.Flags().StringVar(&o.Component, "component", "", "Specify the component name of the cluster, if the cluster has multiple components, you need to specify a component")
+	flags.AddComponentFlag(f, cmd, &o.Component, "Specify the component name of the cluster, if the cluster has multiple components, you need to specify a component")
 	cmd.Flags().StringVar(&o.Instance, "instance", "", "Specify the instance name as the new primary or leader of the cluster, you can get the instance name by running \"kbcli cluster list-instances\"")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before promote the instance")
 	o.addCommonFlags(cmd, f)
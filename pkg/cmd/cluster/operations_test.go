@@ -21,8 +21,14 @@ package cluster
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -30,15 +36,22 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
 	clientfake "k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/remotecommand"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 
+	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
 )
 
 var _ = Describe("operations", func() {
@@ -281,6 +294,14 @@ var _ = Describe("operations", func() {
 		o.Name = clusterName
 		Expect(o.CompleteRestartOps()).Should(Succeed())
 
+		By("test restart cluster without approval")
+		opsName, err := RestartCluster(tf, streams, testing.Namespace, clusterName)
+		Expect(err).Should(Succeed())
+		ops := &appsv1alpha1.OpsRequest{}
+		Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, ops, types.OpsGVR(), testing.Namespace, opsName)).Should(Succeed())
+		Expect(ops.Spec.RestartList).Should(HaveLen(2))
+		Expect(tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace).Delete(context.TODO(), opsName, metav1.DeleteOptions{})).Should(Succeed())
+
 		By("test Restart command")
 		restartCmd := NewRestartCmd(tf, streams)
 		_, _ = in.Write([]byte(clusterName + "\n"))
@@ -290,6 +311,147 @@ var _ = Describe("operations", func() {
 		Expect(testing.ContainExpectStrings(capturedOutput, "kbcli cluster describe-ops")).Should(BeTrue())
 	})
 
+	It("schedule ops", func() {
+		o := initCommonOperationOps(appsv1alpha1.RestartType, clusterName, true, testing.FakeKBDeploy(""))
+		o.autoApprove = true
+		Expect(o.CompleteRestartOps()).Should(Succeed())
+
+		By("validate the schedule")
+		o.schedule.scheduleAt = "2024-05-01 02:00"
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("RFC3339")))
+		o.schedule.scheduleAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("not in the future")))
+		o.schedule.scheduleAt = time.Now().AddDate(2, 0, 0).Format(time.RFC3339)
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("within one year")))
+		o.schedule.scheduleCron = "0 2 * * 0"
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("at the same time")))
+		o.schedule.scheduleAt = ""
+		o.schedule.scheduleCron = "0 2 * *"
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("invalid --schedule-cron")))
+
+		By("schedule the ops at the specified time")
+		at := time.Date(time.Now().Year()+1, 1, 2, 3, 4, 0, 0, time.UTC)
+		o.schedule = scheduleOptions{scheduleAt: at.Format(time.RFC3339)}
+		o.DryRun = "none"
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.Run()).Should(Succeed())
+		cronJobs, err := o.Client.BatchV1().CronJobs(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(HaveLen(1))
+		cronJob := cronJobs.Items[0]
+		Expect(cronJob.Spec.Schedule).Should(Equal("4 3 2 1 *"))
+		Expect(cronJob.Labels).Should(HaveKeyWithValue(scheduledOpsLabelKey, "restart"))
+		container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+		Expect(container.Command[2]).Should(ContainSubstring("kubectl delete cronjob " + cronJob.Name))
+		Expect(container.Env[0].Value).Should(ContainSubstring("type: Restart"))
+		Expect(container.Image).Should(Equal(testing.KBToolsImage))
+		_, err = o.Client.CoreV1().ServiceAccounts(testing.Namespace).Get(context.TODO(), opsSchedulerName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		role, err := o.Client.RbacV1().Roles(testing.Namespace).Get(context.TODO(), opsSchedulerName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(role.Rules[1].ResourceNames).Should(Equal([]string{cronJob.Name}))
+		Expect(o.Client.BatchV1().CronJobs(testing.Namespace).Delete(context.TODO(), cronJob.Name, metav1.DeleteOptions{})).Should(Succeed())
+
+		By("the existing role is updated and the scheduler image follows the image registry")
+		viper.Set(types.CfgKeyImageRegistry, "registry.example.com/")
+		defer viper.Set(types.CfgKeyImageRegistry, "")
+		Expect(o.Run()).Should(Succeed())
+		cronJobs, err = o.Client.BatchV1().CronJobs(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(HaveLen(1))
+		Expect(cronJobs.Items[0].Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image).Should(Equal("registry.example.com/apecloud/kubeblocks-tools:0.8.0"))
+		role, err = o.Client.RbacV1().Roles(testing.Namespace).Get(context.TODO(), opsSchedulerName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(role.Rules[1].ResourceNames).Should(ConsistOf(cronJob.Name, cronJobs.Items[0].Name))
+		Expect(o.Client.BatchV1().CronJobs(testing.Namespace).Delete(context.TODO(), cronJobs.Items[0].Name, metav1.DeleteOptions{})).Should(Succeed())
+
+		By("resolve the scheduler image")
+		Expect(replaceImageRegistry("apecloud/kubeblocks-tools:0.8.0", "registry.example.com")).Should(Equal("registry.example.com/apecloud/kubeblocks-tools:0.8.0"))
+		Expect(replaceImageRegistry("localhost:5000/apecloud/kubeblocks-tools:0.8.0", "registry.example.com")).Should(Equal("registry.example.com/apecloud/kubeblocks-tools:0.8.0"))
+		Expect(replaceImageRegistry("apecloud/kubeblocks-tools:0.8.0", "")).Should(Equal("apecloud/kubeblocks-tools:0.8.0"))
+		Expect(schedulerImage(o.Client, "kubectl:latest")).Should(Equal("kubectl:latest"))
+		_, err = schedulerImage(testing.FakeClientSet(), "")
+		Expect(err).Should(MatchError(ContainSubstring("--scheduler-image")))
+
+		By("schedule the ops periodically")
+		o.OpsRequestName = "weekly-restart"
+		o.schedule = scheduleOptions{scheduleCron: "0 2 * * 0"}
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.Run()).Should(Succeed())
+		cronJobs, err = o.Client.BatchV1().CronJobs(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(cronJobs.Items).Should(HaveLen(1))
+		container = cronJobs.Items[0].Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+		Expect(container.Command[2]).ShouldNot(ContainSubstring("delete cronjob"))
+		Expect(container.Env[0].Value).Should(ContainSubstring("generateName: weekly-restart-"))
+
+		By("no OpsRequest is created directly")
+		opsList, err := tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(opsList.Items).Should(BeEmpty())
+	})
+
+	It("restart hooks", func() {
+		pods := testing.FakePods(2, testing.Namespace, clusterName)
+		o := initCommonOperationOps(appsv1alpha1.RestartType, clusterName, true, &pods.Items[0], &pods.Items[1])
+		o.autoApprove = true
+		o.DryRun = "none"
+		Expect(o.CompleteRestartOps()).Should(Succeed())
+		executor := &restartHookExecutor{}
+		tf.ClientConfigVal = &restclient.Config{APIPath: "/api", ContentConfig: restclient.ContentConfig{NegotiatedSerializer: scheme.Codecs, GroupVersion: &schema.GroupVersion{Version: "v1"}}}
+
+		By("validate the hooks")
+		hookFile := filepath.Join(GinkgoT().TempDir(), "hooks.yaml")
+		Expect(os.WriteFile(hookFile, []byte("preRestart:\n- component: not-exist\n  command: [\"sync\"]\n"), 0600)).Should(Succeed())
+		o.hook = restartHookOptions{hookFile: hookFile}
+		Expect(o.hook.complete()).Should(Succeed())
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("not-exist")))
+		o.hook = restartHookOptions{preExec: "sync"}
+		Expect(o.hook.complete()).Should(Succeed())
+		o.schedule.scheduleCron = "0 2 * * 0"
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("scheduled restart")))
+		o.schedule.scheduleCron = ""
+
+		By("execute the pre-restart hooks in all instances before restarting")
+		o.hook.executor = executor
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.Run()).Should(Succeed())
+		Expect(executor.pods).Should(Equal([]string{pods.Items[0].Name, pods.Items[1].Name}))
+		Expect(executor.commands[0]).Should(ContainSubstring("sync"))
+		opsList, err := tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(opsList.Items).Should(HaveLen(1))
+
+		By("do not restart if the pre-restart hook fails")
+		o.Name = clusterName
+		executor.err = fmt.Errorf("connection refused")
+		Expect(o.Run()).Should(MatchError(ContainSubstring("connection refused")))
+		opsList, err = tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(opsList.Items).Should(HaveLen(1))
+		o.hook.ignoreErrors = true
+		Expect(o.runPreRestartHooks(clusterName)).Should(Succeed())
+
+		By("execute the post-restart hooks in the restarted instances")
+		restartHookPollInterval = 10 * time.Millisecond
+		*executor = restartHookExecutor{}
+		o.hook = restartHookOptions{postExec: "register", timeout: time.Second, executor: executor}
+		Expect(o.hook.complete()).Should(Succeed())
+		ops := generationOps(appsv1alpha1.RestartType, appsv1alpha1.OpsFailedPhase)
+		ops.Status.Components = map[string]appsv1alpha1.OpsRequestComponentStatus{
+			testing.ComponentName: {
+				ProgressDetails: []appsv1alpha1.ProgressStatusDetail{
+					{ObjectKey: "Pod/" + pods.Items[0].Name, Status: appsv1alpha1.SucceedProgressStatus},
+					{ObjectKey: "Pod/" + pods.Items[1].Name, Status: appsv1alpha1.FailedProgressStatus},
+				},
+			},
+		}
+		o.Dynamic = testing.FakeDynamicClient(ops)
+		Expect(o.runPostRestartHooks(ops.Name)).Should(MatchError(ContainSubstring("is Failed")))
+		Expect(executor.pods).Should(Equal([]string{pods.Items[0].Name}))
+		Expect(executor.commands[0]).Should(ContainSubstring("register"))
+	})
+
 	It("cancel ops", func() {
 		By("init some opsRequests which are needed for canceling opsRequest")
 		completedPhases := []appsv1alpha1.OpsPhase{appsv1alpha1.OpsCancelledPhase, appsv1alpha1.OpsSucceedPhase, appsv1alpha1.OpsFailedPhase}
@@ -428,4 +590,87 @@ var _ = Describe("operations", func() {
 		fmt.Println(o.Validate().Error())
 		Expect(testing.ContainExpectStrings(o.Validate().Error(), "is invalid")).Should(BeTrue())
 	})
+
+	It("expose with hostname", func() {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName1 + "-" + testing.ComponentName,
+				Namespace: testing.Namespace,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName1,
+					constant.KBAppComponentLabelKey: testing.ComponentName,
+				},
+				Annotations: map[string]string{externalDNSHostnameAnnotationKey: "mysql.example.com"},
+			},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "mysql", Port: 3306}}},
+		}
+		o := initCommonOperationOps(appsv1alpha1.ExposeType, clusterName1, true, svc)
+		out := &bytes.Buffer{}
+		o.Out = out
+		o.ComponentNames = []string{testing.ComponentName}
+		o.ExposeEnabled = "true"
+		o.ExposeHostname = "mysql.example.com"
+		Expect(o.validateExposeHostname()).Should(Succeed())
+
+		By("validate the hostname")
+		o.ExposeHostname = "MySQL_example"
+		Expect(o.validateExposeHostname()).Should(MatchError(ContainSubstring("invalid hostname")))
+		o.ExposeHostname = "mysql.example.com"
+		o.ExposeEnabled = "false"
+		Expect(o.validateExposeHostname()).Should(HaveOccurred())
+		o.ExposeEnabled = "true"
+		o.ComponentNames = []string{testing.ComponentName, "proxy"}
+		Expect(o.validateExposeHostname()).Should(MatchError(ContainSubstring("only be used to expose one component")))
+		o.ComponentNames = []string{testing.ComponentName}
+
+		By("build the external-dns annotations")
+		o.ExposeDNSTTL = 60
+		annotations := map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}
+		result := o.buildExposeDNSAnnotations(annotations)
+		Expect(annotations).Should(HaveLen(1))
+		Expect(result).Should(HaveKeyWithValue(externalDNSHostnameAnnotationKey, "mysql.example.com"))
+		Expect(result).Should(HaveKeyWithValue(externalDNSTTLAnnotationKey, "60"))
+
+		By("warn if external-dns is not installed")
+		Expect(o.checkExternalDNS()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("external-dns is not found"))
+
+		By("wait for the hostname to resolve")
+		defer func(interval time.Duration, lookup func(context.Context, string) ([]string, error)) {
+			dnsPollInterval, lookupHost = interval, lookup
+		}(dnsPollInterval, lookupHost)
+		dnsPollInterval = 10 * time.Millisecond
+		o.ExposeTimeout = 50 * time.Millisecond
+		lookupHost = func(_ context.Context, host string) ([]string, error) {
+			return nil, fmt.Errorf("no such host %s", host)
+		}
+		Expect(o.waitForExposeEndpoint(clusterName1)).Should(MatchError(ContainSubstring("timed out waiting for mysql.example.com to resolve")))
+		resolved := false
+		lookupHost = func(_ context.Context, host string) ([]string, error) {
+			if !resolved {
+				resolved = true
+				return nil, fmt.Errorf("no such host %s", host)
+			}
+			return []string{"1.2.3.4"}, nil
+		}
+		o.ExposeTimeout = time.Second
+		Expect(o.waitForExposeEndpoint(clusterName1)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("Hostname mysql.example.com resolves to 1.2.3.4"))
+		Expect(out.String()).Should(ContainSubstring("Endpoint: mysql.example.com:3306"))
+	})
 })
+
+// restartHookExecutor records the pods and commands of the restart hooks
+type restartHookExecutor struct {
+	pods     []string
+	commands []string
+	err      error
+}
+
+func (e *restartHookExecutor) Execute(_ string, url *url.URL, _ *restclient.Config, _ io.Reader, _, _ io.Writer, _ bool, _ remotecommand.TerminalSizeQueue) error {
+	// the path is /api/v1/namespaces/{namespace}/pods/{name}/exec
+	segments := strings.Split(url.Path, "/")
+	e.pods = append(e.pods, segments[len(segments)-2])
+	e.commands = append(e.commands, strings.Join(url.Query()["command"], " "))
+	return e.err
+}
@@ -81,8 +81,8 @@ func newRegisterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			o.clusterType = cluster.ClusterType(args[0])
-			cmdutil.CheckErr(o.validate())
-			cmdutil.CheckErr(o.run())
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
 			fmt.Fprint(streams.Out, buildRegisterSuccessExamples(o.clusterType))
 		},
 	}
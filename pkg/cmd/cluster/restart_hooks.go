@@ -0,0 +1,250 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	cmdexec "k8s.io/kubectl/pkg/cmd/exec"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// restartHookPollInterval is the interval to check the restart progress of the instances
+var restartHookPollInterval = 5 * time.Second
+
+// restartHook is a command executed in the instances of the component
+type restartHook struct {
+	// Component is the component whose instances run the hook, all restarted components if empty
+	Component string `json:"component,omitempty"`
+	// Container is the container to execute the command, the default container of the pod if empty
+	Container string `json:"container,omitempty"`
+	// Command is the command to execute
+	Command []string `json:"command"`
+}
+
+// restartHookConfig is the content of the --hook-file, such as:
+//
+//	preRestart:
+//	- component: redis
+//	  command: ["redis-cli", "bgsave"]
+//	postRestart:
+//	- command: ["sh", "-c", "curl -X PUT http://registry:8500/v1/agent/service/register -d @/etc/service.json"]
+type restartHookConfig struct {
+	PreRestart  []restartHook `json:"preRestart,omitempty"`
+	PostRestart []restartHook `json:"postRestart,omitempty"`
+}
+
+// restartHookOptions are the options to execute the hooks in the instances when restarting the cluster.
+// The pre-restart hooks are executed in all target instances before the OpsRequest is created, and the
+// post-restart hook of an instance is executed once the instance is restarted.
+type restartHookOptions struct {
+	preExec      string
+	postExec     string
+	hookFile     string
+	container    string
+	timeout      time.Duration
+	ignoreErrors bool
+
+	hooks    restartHookConfig
+	executor cmdexec.RemoteExecutor
+}
+
+func (o *restartHookOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.preExec, "pre-hook-exec", "", "The shell command executed in each instance before restarting, such as flushing the caches")
+	cmd.Flags().StringVar(&o.postExec, "post-hook-exec", "", "The shell command executed in each instance after it is restarted, such as registering to the service discovery")
+	cmd.Flags().StringVar(&o.hookFile, "hook-file", "", "The YAML file of the preRestart and postRestart hooks of the components")
+	cmd.Flags().StringVar(&o.container, "hook-container", "", "The container to execute the --pre-hook-exec and --post-hook-exec, the default container of the pod if not specified")
+	cmd.Flags().DurationVar(&o.timeout, "hook-timeout", 30*time.Minute, "The time to wait for the instances to be restarted to execute the post-restart hooks")
+	cmd.Flags().BoolVar(&o.ignoreErrors, "ignore-hook-errors", false, "Continue restarting if the hooks fail")
+}
+
+func (o *restartHookOptions) enabled() bool {
+	return len(o.hooks.PreRestart) > 0 || len(o.hooks.PostRestart) > 0
+}
+
+// complete loads the hooks from the --hook-file and the --pre-hook-exec and --post-hook-exec flags
+func (o *restartHookOptions) complete() error {
+	if o.hookFile != "" {
+		data, err := os.ReadFile(o.hookFile)
+		if err != nil {
+			return err
+		}
+		if err = yaml.UnmarshalStrict(data, &o.hooks); err != nil {
+			return fmt.Errorf("failed to parse the hook file %s: %v", o.hookFile, err)
+		}
+	}
+	if o.preExec != "" {
+		o.hooks.PreRestart = append(o.hooks.PreRestart, restartHook{Container: o.container, Command: []string{"sh", "-c", o.preExec}})
+	}
+	if o.postExec != "" {
+		o.hooks.PostRestart = append(o.hooks.PostRestart, restartHook{Container: o.container, Command: []string{"sh", "-c", o.postExec}})
+	}
+	return nil
+}
+
+func (o *restartHookOptions) validate(componentNames []string) error {
+	components := map[string]bool{}
+	for _, name := range componentNames {
+		components[name] = true
+	}
+	for _, hook := range append(append([]restartHook{}, o.hooks.PreRestart...), o.hooks.PostRestart...) {
+		if len(hook.Command) == 0 {
+			return fmt.Errorf("the command of the restart hook is empty")
+		}
+		if hook.Component != "" && !components[hook.Component] {
+			return fmt.Errorf("the component %s of the restart hook is not restarted", hook.Component)
+		}
+	}
+	return nil
+}
+
+// componentHooks returns the hooks to execute in the instances of the component
+func componentHooks(hooks []restartHook, component string) []restartHook {
+	var result []restartHook
+	for _, hook := range hooks {
+		if hook.Component == "" || hook.Component == component {
+			result = append(result, hook)
+		}
+	}
+	return result
+}
+
+// runHooks executes the hooks in the pod and prints the output, an error is returned if the command fails
+// and the errors are not ignored.
+func (o *OperationsOptions) runHooks(stage string, pod *corev1.Pod, hooks []restartHook) error {
+	config, err := o.Factory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		exec := action.NewExecOptions(o.Factory, o.IOStreams)
+		exec.Config = config
+		exec.Quiet = true
+		exec.Pod = pod
+		exec.ContainerName = hook.Container
+		exec.Command = hook.Command
+		if o.hook.executor != nil {
+			exec.Executor = o.hook.executor
+		}
+		klog.V(1).Infof("execute %s hook %s in pod %s", stage, strings.Join(hook.Command, " "), pod.Name)
+		result, err := exec.RunCaptured()
+		if err == nil && result.ExitCode != 0 {
+			err = fmt.Errorf("exit code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+		}
+		if result != nil && result.Stdout != "" {
+			fmt.Fprint(o.Out, result.Stdout)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to execute %s hook in pod %s: %v", stage, pod.Name, err)
+			if !o.hook.ignoreErrors {
+				return err
+			}
+			fmt.Fprintf(o.ErrOut, "%v, ignored\n", err)
+			continue
+		}
+		fmt.Fprintf(o.Out, "Executed %s hook in pod %s\n", stage, pod.Name)
+	}
+	return nil
+}
+
+// runPreRestartHooks executes the pre-restart hooks in all instances of the restarted components
+func (o *OperationsOptions) runPreRestartHooks(clusterName string) error {
+	for _, compName := range o.ComponentNames {
+		hooks := componentHooks(o.hook.hooks.PreRestart, compName)
+		if len(hooks) == 0 {
+			continue
+		}
+		pods, err := o.Client.CoreV1().Pods(o.Namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(map[string]string{
+				constant.AppInstanceLabelKey:    clusterName,
+				constant.KBAppComponentLabelKey: compName,
+			}).String(),
+		})
+		if err != nil {
+			return err
+		}
+		for i := range pods.Items {
+			if err = o.runHooks("pre-restart", &pods.Items[i], hooks); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runPostRestartHooks waits for the instances to be restarted by the OpsRequest, and executes the post-restart
+// hooks in each instance once it is restarted.
+func (o *OperationsOptions) runPostRestartHooks(opsName string) error {
+	if len(o.hook.hooks.PostRestart) == 0 {
+		return nil
+	}
+	fmt.Fprintf(o.Out, "Waiting for the instances to be restarted to execute the post-restart hooks...\n")
+	done := map[string]bool{}
+	return wait.PollUntilContextTimeout(context.Background(), restartHookPollInterval, o.hook.timeout, true, func(ctx context.Context) (bool, error) {
+		ops := &appsv1alpha1.OpsRequest{}
+		if err := cluster.GetK8SClientObject(o.Dynamic, ops, types.OpsGVR(), o.Namespace, opsName); err != nil {
+			return false, err
+		}
+		for compName, compStatus := range ops.Status.Components {
+			hooks := componentHooks(o.hook.hooks.PostRestart, compName)
+			if len(hooks) == 0 {
+				continue
+			}
+			for _, detail := range compStatus.ProgressDetails {
+				podName, ok := strings.CutPrefix(detail.ObjectKey, constant.PodKind+"/")
+				if !ok || done[podName] || detail.Status != appsv1alpha1.SucceedProgressStatus {
+					continue
+				}
+				pod, err := o.Client.CoreV1().Pods(o.Namespace).Get(ctx, podName, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				done[podName] = true
+				if err = o.runHooks("post-restart", pod, hooks); err != nil {
+					return false, err
+				}
+			}
+		}
+		switch ops.Status.Phase {
+		case appsv1alpha1.OpsSucceedPhase:
+			return true, nil
+		case appsv1alpha1.OpsFailedPhase, appsv1alpha1.OpsCancelledPhase:
+			return false, fmt.Errorf("OpsRequest %s is %s, the post-restart hooks of the instances not restarted are skipped", opsName, ops.Status.Phase)
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,206 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/cmd/class"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// restoreOverrides are the resources of the restored cluster different from the source cluster
+type restoreOverrides struct {
+	components   []string
+	cpu          string
+	memory       string
+	replicas     int32
+	storageClass string
+}
+
+func (r *restoreOverrides) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&r.components, "components", nil, "Component names to apply the --cpu, --memory, --replicas and --storage-class, default to all components")
+	cmd.Flags().StringVar(&r.cpu, "cpu", "", "Override the CPU of the restored components, such as 2 or 500m")
+	cmd.Flags().StringVar(&r.memory, "memory", "", "Override the memory of the restored components, such as 4Gi")
+	cmd.Flags().Int32Var(&r.replicas, "replicas", 0, "Override the replicas of the restored components")
+	cmd.Flags().StringVar(&r.storageClass, "storage-class", "", "Override the storage class of the volumes of the restored components")
+}
+
+func (r *restoreOverrides) enabled() bool {
+	return r.cpu != "" || r.memory != "" || r.replicas != 0 || r.storageClass != ""
+}
+
+// apply overrides the resources of the component
+func (r *restoreOverrides) apply(comp *appsv1alpha1.ClusterComponentSpec) error {
+	if len(r.components) > 0 && !slices.Contains(r.components, comp.Name) {
+		return nil
+	}
+	resources := map[corev1.ResourceName]string{corev1.ResourceCPU: r.cpu, corev1.ResourceMemory: r.memory}
+	for name, value := range resources {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("invalid --%s %s: %v", name, value, err)
+		}
+		if comp.Resources.Requests == nil {
+			comp.Resources.Requests = corev1.ResourceList{}
+		}
+		if comp.Resources.Limits == nil {
+			comp.Resources.Limits = corev1.ResourceList{}
+		}
+		comp.Resources.Requests[name] = quantity
+		comp.Resources.Limits[name] = quantity
+		// the resources are specified directly instead of by the class
+		comp.ClassDefRef = nil
+	}
+	if r.replicas != 0 {
+		comp.Replicas = r.replicas
+	}
+	if r.storageClass != "" {
+		for i := range comp.VolumeClaimTemplates {
+			comp.VolumeClaimTemplates[i].Spec.StorageClassName = &r.storageClass
+		}
+	}
+	return nil
+}
+
+// buildRestoredCluster builds the cluster to restore from the snapshot of the source cluster in the
+// backup like the Restore OpsRequest, with the resources overridden and validated.
+func (o *CreateRestoreOptions) buildRestoredCluster() (*appsv1alpha1.Cluster, error) {
+	if o.overrides.replicas < 0 {
+		return nil, clierrors.NewValidation("--replicas should be greater than 0")
+	}
+	backup := &dpv1alpha1.Backup{}
+	if err := cluster.GetK8SClientObject(o.Dynamic, backup, types.BackupGVR(), o.Namespace, o.RestoreSpec.BackupName); err != nil {
+		return nil, err
+	}
+	if backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return nil, fmt.Errorf("backup %s status is %s, only completed backup can be used to restore", backup.Name, backup.Status.Phase)
+	}
+	if _, ok := backup.Annotations[constant.ClusterSnapshotAnnotationKey]; !ok {
+		return nil, fmt.Errorf("missing snapshot annotation in backup %s, %s is empty in Annotations", backup.Name, constant.ClusterSnapshotAnnotationKey)
+	}
+	restoreTimeStr, err := restore.FormatRestoreTimeAndValidate(o.RestoreSpec.RestoreTimeStr, backup)
+	if err != nil {
+		return nil, err
+	}
+	c, err := getSourceClusterFromBackup(backup)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Spec.ComponentSpecs) == 0 {
+		return nil, fmt.Errorf("no component found in the source cluster of backup %s", backup.Name)
+	}
+	for _, name := range o.overrides.components {
+		if c.Spec.GetComponentByName(name) == nil {
+			return nil, clierrors.NewValidation("component %s is not found in the source cluster of backup %s", name, backup.Name)
+		}
+	}
+
+	clsMgr, err := class.GetManager(o.Dynamic, c.Spec.ClusterDefRef)
+	if err != nil {
+		return nil, err
+	}
+	var comps []map[string]interface{}
+	for i := range c.Spec.ComponentSpecs {
+		comp := &c.Spec.ComponentSpecs[i]
+		if err = o.overrides.apply(comp); err != nil {
+			return nil, err
+		}
+		if err = clsMgr.ValidateResources(c.Spec.ClusterDefRef, comp); err != nil {
+			return nil, err
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(comp)
+		if err != nil {
+			return nil, err
+		}
+		comps = append(comps, obj)
+	}
+	if err = validateStorageClass(o.Dynamic, comps, false); err != nil {
+		return nil, err
+	}
+
+	restoreAnnotation, err := restore.GetRestoreFromBackupAnnotation(backup, o.RestoreSpec.VolumeRestorePolicy,
+		len(c.Spec.ComponentSpecs), c.Spec.ComponentSpecs[0].Name, restoreTimeStr)
+	if err != nil {
+		return nil, err
+	}
+	c.TypeMeta = metav1.TypeMeta{APIVersion: types.ClusterGVR().GroupVersion().String(), Kind: types.KindCluster}
+	c.ObjectMeta = metav1.ObjectMeta{
+		Name:        o.Name,
+		Namespace:   o.Namespace,
+		Annotations: map[string]string{constant.RestoreFromBackupAnnotationKey: restoreAnnotation},
+	}
+	c.Status = appsv1alpha1.ClusterStatus{}
+	return c, nil
+}
+
+// createRestoredCluster creates the cluster built with the overridden resources, instead of the
+// Restore OpsRequest which restores the cluster as it is in the backup.
+func (o *CreateRestoreOptions) createRestoredCluster() error {
+	dryRun, err := o.GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	if dryRun == action.DryRunClient {
+		data, err := yaml.Marshal(o.restoredCluster)
+		if err != nil {
+			return err
+		}
+		_, err = o.Out.Write(data)
+		return err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o.restoredCluster)
+	if err != nil {
+		return err
+	}
+	createOptions := metav1.CreateOptions{}
+	if dryRun == action.DryRunServer {
+		createOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err = o.Dynamic.Resource(types.ClusterGVR()).Namespace(o.Namespace).Create(context.TODO(),
+		&unstructured.Unstructured{Object: obj}, createOptions); err != nil {
+		return err
+	}
+	if !o.Quiet {
+		fmt.Fprintf(o.Out, "Cluster %s created\n", o.Name)
+	}
+	return nil
+}
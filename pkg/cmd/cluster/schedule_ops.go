@@ -0,0 +1,374 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	opsSchedulerName = "kbcli-ops-scheduler"
+	opsManifestEnv   = "OPS_MANIFEST"
+
+	// scheduledOpsLabelKey is the label key of the CronJobs which create the scheduled OpsRequests,
+	// the value is the OpsRequest type.
+	scheduledOpsLabelKey = "ops.kubeblocks.io/scheduled-type"
+	// scheduleAnnotationKey records the --schedule-at or --schedule-cron of the CronJob
+	scheduleAnnotationKey = "ops.kubeblocks.io/schedule"
+)
+
+// scheduleOptions are the options to create the OpsRequest at a specified time instead of now. The
+// OpsRequest is created by a CronJob managed by kbcli, which runs kubectl in the cluster namespace.
+type scheduleOptions struct {
+	scheduleAt   string
+	scheduleCron string
+	image        string
+
+	// at is the parsed time of scheduleAt
+	at time.Time
+}
+
+func (o *scheduleOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.scheduleAt, "schedule-at", "", `Create the OpsRequest at the specified time in RFC3339 format instead of now, such as "2024-05-01T02:00:00Z"`)
+	cmd.Flags().StringVar(&o.scheduleCron, "schedule-cron", "", `Create the OpsRequest periodically with the cron expression in UTC, such as "0 2 * * 0"`)
+	cmd.Flags().StringVar(&o.image, "scheduler-image", "", schedulerImageUsage("The kubectl image used by the CronJob to create the scheduled OpsRequest"))
+}
+
+// schedulerImageUsage returns the usage of the --scheduler-image flags with the default image
+func schedulerImageUsage(usage string) string {
+	return fmt.Sprintf("%s, default to the tools image of the installed KubeBlocks, "+
+		"where the registry can be changed by `kbcli config set %s`", usage, types.CfgKeyImageRegistry)
+}
+
+// schedulerImage returns the image specified by --scheduler-image, or the tools image of the installed
+// KubeBlocks which contains kubectl and follows the image registry of the installation. The registry
+// is replaced if the image registry is configured.
+func schedulerImage(client kubernetes.Interface, image string) (string, error) {
+	if image != "" {
+		return image, nil
+	}
+	deploy, err := util.GetKubeBlocksDeploy(client)
+	if err != nil {
+		return "", err
+	}
+	if deploy != nil {
+		for _, c := range deploy.Spec.Template.Spec.Containers {
+			for _, env := range c.Env {
+				if env.Name == constant.KBToolsImage && env.Value != "" {
+					return replaceImageRegistry(env.Value, viper.GetString(types.CfgKeyImageRegistry)), nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to find the tools image of KubeBlocks, please specify the kubectl image by --scheduler-image")
+}
+
+// replaceImageRegistry replaces the registry of the image, the first part of the image is regarded as
+// the registry if it contains "." or ":" or is "localhost", as the container runtime does.
+func replaceImageRegistry(image, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "" {
+		return image
+	}
+	if i := strings.Index(image, "/"); i > 0 {
+		if first := image[:i]; strings.ContainsAny(first, ".:") || first == "localhost" {
+			image = image[i+1:]
+		}
+	}
+	return registry + "/" + image
+}
+
+func (o *scheduleOptions) enabled() bool {
+	return o.scheduleAt != "" || o.scheduleCron != ""
+}
+
+func (o *scheduleOptions) validate() error {
+	if o.scheduleAt != "" && o.scheduleCron != "" {
+		return fmt.Errorf("--schedule-at and --schedule-cron can not be specified at the same time")
+	}
+	if o.scheduleAt != "" {
+		at, err := time.Parse(time.RFC3339, o.scheduleAt)
+		if err != nil {
+			return fmt.Errorf("invalid --schedule-at %s, it should be in RFC3339 format such as 2024-05-01T02:00:00Z: %v", o.scheduleAt, err)
+		}
+		if !at.After(time.Now()) {
+			return fmt.Errorf("--schedule-at %s is not in the future", o.scheduleAt)
+		}
+		// the one-time schedule is converted to the cron expression matching it once a year
+		if at.After(time.Now().AddDate(1, 0, 0)) {
+			return fmt.Errorf("--schedule-at %s should be within one year", o.scheduleAt)
+		}
+		o.at = at.UTC()
+	}
+	if o.scheduleCron != "" {
+		if _, err := cron.ParseStandard(o.scheduleCron); err != nil {
+			return fmt.Errorf("invalid --schedule-cron %s: %v", o.scheduleCron, err)
+		}
+	}
+	return nil
+}
+
+// cronSchedule returns the schedule of the CronJob, the specified time is converted to the cron
+// expression which matches it once a year, and the CronJob is deleted after the first run.
+func (o *scheduleOptions) cronSchedule() string {
+	if o.scheduleCron != "" {
+		return o.scheduleCron
+	}
+	return fmt.Sprintf("%d %d %d %d *", o.at.Minute(), o.at.Hour(), o.at.Day(), int(o.at.Month()))
+}
+
+// buildCronJob builds the CronJob to create the OpsRequest, the manifest of the OpsRequest is passed
+// by the environment variable and created by kubectl.
+func (o *scheduleOptions) buildCronJob(c *appsv1alpha1.Cluster, ops *unstructured.Unstructured, image string) (*batchv1.CronJob, error) {
+	opsType, _, _ := unstructured.NestedString(ops.Object, "spec", "type")
+	typeLower := strings.ToLower(opsType)
+	name := fmt.Sprintf("%s-%s-%s", c.Name, typeLower, rand.String(5))
+
+	// the periodic OpsRequests must have different names
+	if o.scheduleCron != "" && ops.GetName() != "" {
+		ops.SetGenerateName(ops.GetName() + "-")
+		ops.SetName("")
+	}
+	manifest, err := yaml.Marshal(ops.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(`echo "$%s" | kubectl create -f -`, opsManifestEnv)
+	if o.scheduleAt != "" {
+		script += fmt.Sprintf(" && kubectl delete cronjob %s -n %s", name, c.Namespace)
+	}
+	utc := "Etc/UTC"
+	var backoffLimit int32 = 3
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:  c.Name,
+				constant.AppManagedByLabelKey: "kbcli",
+				scheduledOpsLabelKey:          typeLower,
+			},
+			Annotations: map[string]string{
+				scheduleAnnotationKey: o.scheduleAt + o.scheduleCron,
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          o.cronSchedule(),
+			TimeZone:          &utc,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: opsSchedulerName,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{
+								{
+									Name:    "create-ops",
+									Image:   image,
+									Command: []string{"sh", "-c", script},
+									Env:     []corev1.EnvVar{{Name: opsManifestEnv, Value: string(manifest)}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if c.UID != "" {
+		// the scheduled OpsRequests are deleted with the cluster
+		cronJob.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: fmt.Sprintf("%s/%s", types.AppsAPIGroup, types.AppsAPIVersion),
+				Kind:       types.KindCluster,
+				Name:       c.Name,
+				UID:        c.UID,
+			},
+		}
+	}
+	return cronJob, nil
+}
+
+// opsSchedulerRules returns the rules of the service account used by the CronJobs, which is allowed to
+// create the OpsRequests and delete the one-time CronJob after the OpsRequest is created.
+func opsSchedulerRules(cronJob *batchv1.CronJob, oneTime bool) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{types.AppsAPIGroup},
+			Resources: []string{types.ResourceOpsRequests},
+			Verbs:     []string{"create"},
+		},
+	}
+	if oneTime {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{types.K8SBatchAPIGroup},
+			Resources:     []string{types.ResourceCronJobs},
+			Verbs:         []string{"delete"},
+			ResourceNames: []string{cronJob.Name},
+		})
+	}
+	return rules
+}
+
+// createSchedulerRBAC creates the service account with the rules used by the CronJobs managed by kbcli. The
+// role is shared by the CronJobs in the namespace, so the existing role is updated with the rules, and the
+// resource names of the existing rules with the same verbs are kept.
+func createSchedulerRBAC(ctx context.Context, client kubernetes.Interface, namespace, name string, rules []rbacv1.PolicyRule, dryRun []string) error {
+	labels := map[string]string{constant.AppManagedByLabelKey: "kbcli"}
+	objMeta := metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+	ignoreExists := func(err error) error {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: objMeta}
+	if _, err := client.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{DryRun: dryRun}); ignoreExists(err) != nil {
+		return err
+	}
+	role, err := client.RbacV1().Roles(namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		role.Rules = mergeResourceNames(rules, role.Rules)
+		if _, err = client.RbacV1().Roles(namespace).Update(ctx, role, metav1.UpdateOptions{DryRun: dryRun}); err != nil {
+			return err
+		}
+	case apierrors.IsNotFound(err):
+		role = &rbacv1.Role{ObjectMeta: objMeta, Rules: rules}
+		if _, err = client.RbacV1().Roles(namespace).Create(ctx, role, metav1.CreateOptions{DryRun: dryRun}); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: objMeta,
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}},
+	}
+	if _, err = client.RbacV1().RoleBindings(namespace).Create(ctx, binding, metav1.CreateOptions{DryRun: dryRun}); ignoreExists(err) != nil {
+		return err
+	}
+	return nil
+}
+
+// mergeResourceNames adds the resource names of the existing rules to the rules scoped by the resource
+// names with the same API groups, resources and verbs, so the objects of other clusters are still allowed.
+// The other existing rules are replaced.
+func mergeResourceNames(rules []rbacv1.PolicyRule, existing []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	res := make([]rbacv1.PolicyRule, len(rules))
+	for i, r := range rules {
+		res[i] = *r.DeepCopy()
+		if len(r.ResourceNames) == 0 {
+			continue
+		}
+		for _, e := range existing {
+			if len(e.ResourceNames) == 0 || !slices.Equal(e.APIGroups, r.APIGroups) ||
+				!slices.Equal(e.Resources, r.Resources) || !slices.Equal(e.Verbs, r.Verbs) {
+				continue
+			}
+			for _, name := range e.ResourceNames {
+				if !slices.Contains(res[i].ResourceNames, name) {
+					res[i].ResourceNames = append(res[i].ResourceNames, name)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// runScheduled creates the CronJob which creates the OpsRequest at the scheduled time
+func (o *OperationsOptions) runScheduled(c *appsv1alpha1.Cluster) error {
+	ops, err := o.BuildResourceObj()
+	if err != nil {
+		return err
+	}
+	image, err := schedulerImage(o.Client, o.schedule.image)
+	if err != nil {
+		return err
+	}
+	cronJob, err := o.schedule.buildCronJob(c, ops, image)
+	if err != nil {
+		return err
+	}
+
+	dryRunStrategy, err := o.GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	if dryRunStrategy == action.DryRunClient {
+		data, err := yaml.Marshal(cronJob)
+		if err != nil {
+			return err
+		}
+		_, err = o.Out.Write(data)
+		return err
+	}
+	var dryRun []string
+	if dryRunStrategy == action.DryRunServer {
+		dryRun = []string{metav1.DryRunAll}
+	}
+
+	ctx := context.TODO()
+	if err = createSchedulerRBAC(ctx, o.Client, o.Namespace, opsSchedulerName, opsSchedulerRules(cronJob, o.schedule.scheduleAt != ""), dryRun); err != nil {
+		return err
+	}
+	klog.V(1).Infof("create CronJob %s with schedule %s", cronJob.Name, cronJob.Spec.Schedule)
+	if _, err = o.Client.BatchV1().CronJobs(o.Namespace).Create(ctx, cronJob, metav1.CreateOptions{DryRun: dryRun}); err != nil {
+		return err
+	}
+	if o.schedule.scheduleAt != "" {
+		fmt.Fprintf(o.Out, "%s OpsRequest of cluster %s is scheduled at %s by CronJob %s\n", o.OpsType, c.Name, o.schedule.at.Format(time.RFC3339), cronJob.Name)
+	} else {
+		fmt.Fprintf(o.Out, "%s OpsRequest of cluster %s is scheduled by CronJob %s with %q\n", o.OpsType, c.Name, cronJob.Name, o.schedule.scheduleCron)
+	}
+	fmt.Fprintf(o.Out, "\tkubectl delete cronjob %s -n %s  # cancel the scheduled OpsRequest\n", cronJob.Name, o.Namespace)
+	return nil
+}
@@ -0,0 +1,409 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"k8s.io/utils/pointer"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+const (
+	// snapshotNameLabelKey groups the VolumeSnapshots of the PVCs taken by one snapshot command
+	snapshotNameLabelKey = "kubeblocks.io/snapshot-name"
+	// defaultVolumeSnapshotClassAnnotationKey marks the default VolumeSnapshotClass of a CSI driver
+	defaultVolumeSnapshotClassAnnotationKey = "snapshot.storage.kubernetes.io/is-default-class"
+)
+
+var (
+	snapshotExample = templates.Examples(`
+		# Take volume snapshots of all PVCs of cluster mycluster
+		kbcli cluster snapshot mycluster
+
+		# Take volume snapshots of the PVCs of component mysql with the name before-upgrade
+		kbcli cluster snapshot mycluster --components mysql --name before-upgrade`)
+
+	listSnapshotsExample = templates.Examples(`
+		# List the volume snapshots of all clusters
+		kbcli cluster list-snapshots
+
+		# List the volume snapshots of cluster mycluster
+		kbcli cluster list-snapshots mycluster`)
+
+	deleteSnapshotExample = templates.Examples(`
+		# Delete the volume snapshots named before-upgrade of cluster mycluster
+		kbcli cluster delete-snapshot mycluster --name before-upgrade`)
+)
+
+type snapshotOptions struct {
+	clusterName         string
+	namespace           string
+	name                string
+	components          []string
+	volumeSnapshotClass string
+
+	dynamic dynamic.Interface
+	genericiooptions.IOStreams
+}
+
+func NewSnapshotCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &snapshotOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "snapshot NAME",
+		Short:             "Take volume snapshots of the PVCs of a cluster, without the backup policy and backup repo.",
+		Example:           snapshotExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentsFlag(f, cmd, &o.components, "Component names to take the volume snapshots, default to all components")
+	cmd.Flags().StringVar(&o.name, "name", "", "The name of the snapshot, default to the cluster name with the current time")
+	cmd.Flags().StringVar(&o.volumeSnapshotClass, "volume-snapshot-class", "", "The VolumeSnapshotClass used by all volume snapshots, default to the VolumeSnapshotClass of the CSI driver of each PVC")
+	util.RegisterFlagCompletionFunc(cmd, "volume-snapshot-class", util.ResourceNameCompletionFunc(f, types.VolumeSnapshotClassGVR()))
+	return cmd
+}
+
+func (o *snapshotOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to snapshot one cluster")
+	}
+	o.clusterName = args[0]
+	if o.name == "" {
+		o.name = fmt.Sprintf("%s-%s", o.clusterName, time.Now().Format("20060102150405"))
+	}
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.dynamic, err = f.DynamicClient()
+	return err
+}
+
+func (o *snapshotOptions) validate() error {
+	if errs := validation.IsValidLabelValue(o.name); len(errs) > 0 {
+		return clierrors.NewValidation("invalid snapshot name %s: %s", o.name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// getVolumeSnapshotClasses returns the VolumeSnapshotClass of each CSI driver, the default one is
+// preferred if a driver has multiple VolumeSnapshotClasses.
+func (o *snapshotOptions) getVolumeSnapshotClasses() (map[string]string, error) {
+	list, err := o.dynamic.Resource(types.VolumeSnapshotClassGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, clierrors.NewValidation("the volume snapshot CRDs are not installed, %s", util.VolumeSnapshotInstallHint)
+		}
+		return nil, err
+	}
+	classes := map[string]string{}
+	for _, item := range list.Items {
+		vsc := &snapshotv1.VolumeSnapshotClass{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, vsc); err != nil {
+			return nil, err
+		}
+		if _, ok := classes[vsc.Driver]; !ok || vsc.Annotations[defaultVolumeSnapshotClassAnnotationKey] == "true" {
+			classes[vsc.Driver] = vsc.Name
+		}
+	}
+	return classes, nil
+}
+
+// getPVCs returns the PVCs of the components to snapshot, sorted by the name
+func (o *snapshotOptions) getPVCs() ([]corev1.PersistentVolumeClaim, error) {
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range o.components {
+		if c.Spec.GetComponentByName(name) == nil {
+			return nil, fmt.Errorf("component %s is not found in cluster %s", name, c.Name)
+		}
+	}
+	selector := k8slabels.Set{constant.AppInstanceLabelKey: o.clusterName}.AsSelector().String()
+	if len(o.components) > 0 {
+		selector += fmt.Sprintf(",%s in (%s)", constant.KBAppComponentLabelKey, strings.Join(o.components, ","))
+	}
+	list, err := o.dynamic.Resource(types.PVCGVR()).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := util.ConvertUnstructuredList[corev1.PersistentVolumeClaim](list.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(pvcs) == 0 {
+		return nil, fmt.Errorf("no PVC found in cluster %s", o.clusterName)
+	}
+	slices.SortFunc(pvcs, func(a, b corev1.PersistentVolumeClaim) bool {
+		return a.Name < b.Name
+	})
+	return pvcs, nil
+}
+
+// getVolumeSnapshotClass returns the VolumeSnapshotClass of the CSI driver of the PVC
+func (o *snapshotOptions) getVolumeSnapshotClass(pvc *corev1.PersistentVolumeClaim, classes map[string]string) (string, error) {
+	var sc *storagev1.StorageClass
+	if pvc.Spec.StorageClassName != nil {
+		obj, err := o.dynamic.Resource(types.StorageClassGVR()).Get(context.TODO(), *pvc.Spec.StorageClassName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		sc = &storagev1.StorageClass{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, sc); err != nil {
+			return "", err
+		}
+	} else {
+		list, err := o.dynamic.Resource(types.StorageClassGVR()).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return "", err
+		}
+		classList, err := util.ConvertUnstructuredList[storagev1.StorageClass](list.Items, nil)
+		if err != nil {
+			return "", err
+		}
+		for i := range classList {
+			if util.IsDefaultStorageClass(classList[i].Annotations) {
+				sc = &classList[i]
+				break
+			}
+		}
+		if sc == nil {
+			return "", clierrors.NewValidation("PVC %s has no storage class and there is no default storage class", pvc.Name)
+		}
+	}
+	class, ok := classes[sc.Provisioner]
+	if !ok {
+		return "", clierrors.NewValidation("no VolumeSnapshotClass found for the CSI driver %s of storage class %s used by PVC %s, "+
+			"create a VolumeSnapshotClass with the driver %s or specify one by --volume-snapshot-class", sc.Provisioner, sc.Name, pvc.Name, sc.Provisioner)
+	}
+	return class, nil
+}
+
+func (o *snapshotOptions) run() error {
+	if err := action.CheckExportUnsupported("snapshot"); err != nil {
+		return err
+	}
+	pvcs, err := o.getPVCs()
+	if err != nil {
+		return err
+	}
+	var classes map[string]string
+	if o.volumeSnapshotClass == "" {
+		if classes, err = o.getVolumeSnapshotClasses(); err != nil {
+			return err
+		}
+	}
+
+	// resolve all VolumeSnapshotClasses before creating any snapshot, so no partial snapshot is left
+	snapshots := make([]*snapshotv1.VolumeSnapshot, 0, len(pvcs))
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		class := o.volumeSnapshotClass
+		if class == "" {
+			if class, err = o.getVolumeSnapshotClass(pvc, classes); err != nil {
+				return err
+			}
+		}
+		snapshots = append(snapshots, &snapshotv1.VolumeSnapshot{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: types.VolumeSnapshotGVR().GroupVersion().String(),
+				Kind:       "VolumeSnapshot",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", o.name, pvc.Name),
+				Namespace: o.namespace,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    o.clusterName,
+					constant.KBAppComponentLabelKey: pvc.Labels[constant.KBAppComponentLabelKey],
+					constant.AppManagedByLabelKey:   "kbcli",
+					snapshotNameLabelKey:            o.name,
+				},
+			},
+			Spec: snapshotv1.VolumeSnapshotSpec{
+				Source:                  snapshotv1.VolumeSnapshotSource{PersistentVolumeClaimName: pointer.String(pvc.Name)},
+				VolumeSnapshotClassName: pointer.String(class),
+			},
+		})
+	}
+
+	for _, snapshot := range snapshots {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(snapshot)
+		if err != nil {
+			return err
+		}
+		if _, err = o.dynamic.Resource(types.VolumeSnapshotGVR()).Namespace(o.namespace).Create(context.TODO(),
+			&unstructured.Unstructured{Object: obj}, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create the volume snapshot of PVC %s: %v", *snapshot.Spec.Source.PersistentVolumeClaimName, err)
+		}
+		fmt.Fprintf(o.Out, "volumesnapshot/%s created\n", snapshot.Name)
+	}
+	fmt.Fprintf(o.Out, "\nSnapshot %s of cluster %s is created, check the status with:\n  kbcli cluster list-snapshots %s\n", o.name, o.clusterName, o.clusterName)
+	return nil
+}
+
+func NewListSnapshotsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := action.NewListOptions(f, streams, types.VolumeSnapshotGVR())
+	cmd := &cobra.Command{
+		Use:               "list-snapshots",
+		Short:             "List the volume snapshots taken by the snapshot command.",
+		Aliases:           []string{"ls-snapshots"},
+		Example:           listSnapshotsExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.LabelSelector = util.BuildLabelSelectorByNames(o.LabelSelector, args)
+			util.CheckErr(o.Complete())
+			util.CheckErr(printSnapshotList(o))
+		},
+	}
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func printSnapshotList(o *action.ListOptions) error {
+	// if format is JSON or YAML, use default printer to output the result.
+	if o.Format == printer.JSON || o.Format == printer.YAML {
+		_, err := o.Run()
+		return err
+	}
+	dynamic, err := o.Factory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	if o.AllNamespaces {
+		o.Namespace = ""
+	}
+	// only the volume snapshots taken by the snapshot command are listed
+	selector := snapshotNameLabelKey
+	if o.LabelSelector != "" {
+		selector = o.LabelSelector + "," + selector
+	}
+	list, err := dynamic.Resource(types.VolumeSnapshotGVR()).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return clierrors.NewValidation("the volume snapshot CRDs are not installed, %s", util.VolumeSnapshotInstallHint)
+		}
+		return err
+	}
+	if len(list.Items) == 0 {
+		o.PrintNotFoundResources()
+		return nil
+	}
+	snapshots, err := util.ConvertUnstructuredList[snapshotv1.VolumeSnapshot](list.Items, nil)
+	if err != nil {
+		return err
+	}
+
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("NAME", "NAMESPACE", "SNAPSHOT", "CLUSTER", "COMPONENT", "SOURCE-PVC", "READY", "RESTORE-SIZE", "CREATE-TIME")
+	for _, s := range snapshots {
+		pvc, ready, size := "", "false", ""
+		if s.Spec.Source.PersistentVolumeClaimName != nil {
+			pvc = *s.Spec.Source.PersistentVolumeClaimName
+		}
+		if s.Status != nil {
+			if s.Status.ReadyToUse != nil && *s.Status.ReadyToUse {
+				ready = "true"
+			}
+			if s.Status.RestoreSize != nil {
+				size = s.Status.RestoreSize.String()
+			}
+			if s.Status.Error != nil && s.Status.Error.Message != nil {
+				ready = fmt.Sprintf("false(%s)", *s.Status.Error.Message)
+			}
+		}
+		tbl.AddRow(s.Name, s.Namespace, s.Labels[snapshotNameLabelKey], s.Labels[constant.AppInstanceLabelKey],
+			s.Labels[constant.KBAppComponentLabelKey], pvc, ready, size, util.TimeFormat(&s.CreationTimestamp))
+	}
+	tbl.Print()
+	return nil
+}
+
+func NewDeleteSnapshotCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := action.NewDeleteOptions(f, streams, types.VolumeSnapshotGVR())
+	var names []string
+	cmd := &cobra.Command{
+		Use:               "delete-snapshot NAME",
+		Short:             "Delete the volume snapshots taken by the snapshot command.",
+		Example:           deleteSnapshotExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(completeForDeleteSnapshot(o, args, names))
+			util.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringSliceVar(&names, "name", nil, "The names of the snapshots to delete")
+	o.AddFlags(cmd)
+	return cmd
+}
+
+// completeForDeleteSnapshot selects the volume snapshots of the snapshot names in the cluster
+func completeForDeleteSnapshot(o *action.DeleteOptions, args []string, names []string) error {
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return clierrors.NewValidation("only support to delete the snapshots of one cluster")
+	}
+	if len(names) == 0 {
+		return clierrors.NewValidation("missing --name as the snapshot name")
+	}
+	o.LabelSelector = util.BuildLabelSelectorByNames(o.LabelSelector, args)
+	o.LabelSelector += fmt.Sprintf(",%s in (%s)", snapshotNameLabelKey, strings.Join(names, ","))
+	return nil
+}
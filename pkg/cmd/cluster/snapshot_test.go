@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	clientfake "k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster snapshot", func() {
+	const driver = "ebs.csi.aws.com"
+
+	var (
+		out *bytes.Buffer
+		sc  *storagev1.StorageClass
+		o   *snapshotOptions
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		sc = clitesting.FakeStorageClass(clitesting.StorageClassName, false)
+		sc.Provisioner = driver
+		vsc := clitesting.FakeVolumeSnapshotClass()
+		vsc.Name = "ebs-snapshot"
+		vsc.Driver = driver
+		// an existing snapshot of another cluster
+		existing := &snapshotv1.VolumeSnapshot{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "snapshot.storage.k8s.io/v1", Kind: "VolumeSnapshot"},
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: clitesting.Namespace},
+		}
+		o = &snapshotOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			name:        "before-upgrade",
+			dynamic: clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace),
+				&clitesting.FakePVCs().Items[0], sc, vsc, existing),
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	It("validate", func() {
+		o.name = "Before Upgrade"
+		Expect(o.validate()).Should(MatchError(ContainSubstring("invalid snapshot name")))
+		o.components = []string{"proxy"}
+		Expect(o.run()).Should(MatchError(ContainSubstring("component proxy is not found")))
+	})
+
+	It("take the volume snapshots of the PVCs", func() {
+		o.components = []string{clitesting.ComponentName}
+		Expect(o.run()).Should(Succeed())
+		name := "before-upgrade-" + clitesting.PVCName
+		Expect(out.String()).Should(ContainSubstring("volumesnapshot/" + name + " created"))
+
+		obj, err := o.dynamic.Resource(types.VolumeSnapshotGVR()).Namespace(clitesting.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		snapshot := &snapshotv1.VolumeSnapshot{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, snapshot)).Should(Succeed())
+		Expect(*snapshot.Spec.Source.PersistentVolumeClaimName).Should(Equal(clitesting.PVCName))
+		Expect(*snapshot.Spec.VolumeSnapshotClassName).Should(Equal("ebs-snapshot"))
+		Expect(snapshot.Labels).Should(HaveKeyWithValue(snapshotNameLabelKey, "before-upgrade"))
+		Expect(snapshot.Labels).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, clitesting.ComponentName))
+
+		By("no VolumeSnapshotClass for the CSI driver")
+		o.name = "after-upgrade"
+		o.dynamic = clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace),
+			&clitesting.FakePVCs().Items[0], sc, clitesting.FakeVolumeSnapshotClass())
+		Expect(o.run()).Should(MatchError(ContainSubstring("no VolumeSnapshotClass found")))
+
+		By("specify the VolumeSnapshotClass")
+		o.volumeSnapshotClass = "csi-snapshot"
+		Expect(o.run()).Should(Succeed())
+	})
+
+	It("list and delete the snapshots", func() {
+		tf := cmdtesting.NewTestFactory().WithNamespace(clitesting.Namespace)
+		defer tf.Cleanup()
+		tf.Client = &clientfake.RESTClient{}
+		Expect(o.run()).Should(Succeed())
+		obj, err := o.dynamic.Resource(types.VolumeSnapshotGVR()).Namespace(clitesting.Namespace).Get(context.TODO(),
+			"before-upgrade-"+clitesting.PVCName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(unstructured.SetNestedField(obj.Object, true, "status", "readyToUse")).Should(Succeed())
+		Expect(unstructured.SetNestedField(obj.Object, "20Gi", "status", "restoreSize")).Should(Succeed())
+		tf.FakeDynamicClient = clitesting.FakeDynamicClient(obj)
+
+		streams, _, listOut, _ := genericiooptions.NewTestIOStreams()
+		listOptions := action.NewListOptions(tf, streams, types.VolumeSnapshotGVR())
+		Expect(listOptions.Complete()).Should(Succeed())
+		Expect(printSnapshotList(listOptions)).Should(Succeed())
+		Expect(listOut.String()).Should(ContainSubstring("before-upgrade"))
+		Expect(listOut.String()).Should(ContainSubstring("true"))
+		Expect(listOut.String()).Should(ContainSubstring("20Gi"))
+
+		deleteOptions := action.NewDeleteOptions(tf, streams, types.VolumeSnapshotGVR())
+		Expect(completeForDeleteSnapshot(deleteOptions, nil, nil)).Should(HaveOccurred())
+		Expect(completeForDeleteSnapshot(deleteOptions, []string{clitesting.ClusterName}, nil)).Should(HaveOccurred())
+		Expect(completeForDeleteSnapshot(deleteOptions, []string{clitesting.ClusterName}, []string{"before-upgrade"})).Should(Succeed())
+		Expect(deleteOptions.LabelSelector).Should(Equal(constant.AppInstanceLabelKey + " in (" + clitesting.ClusterName + ")," +
+			snapshotNameLabelKey + " in (before-upgrade)"))
+	})
+})
@@ -0,0 +1,264 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdexec "k8s.io/kubectl/pkg/cmd/exec"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	lorryclient "github.com/apecloud/kubeblocks/pkg/lorry/client"
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+// switchoverPollInterval is the interval to check the progress of the switchover
+var switchoverPollInterval = 5 * time.Second
+
+var switchoverExample = templates.Examples(`
+		# switchover the primary or leader to the instance mycluster-mysql-1
+		kbcli cluster switchover mycluster --instance mycluster-mysql-1
+
+		# switchover the primary or leader to an instance determined by the system
+		kbcli cluster switchover mycluster --component mysql
+
+		# failover when the primary or leader is unhealthy, skip the health checks of the instances
+		kbcli cluster switchover mycluster --instance mycluster-mysql-1 --force
+`)
+
+type switchoverOptions struct {
+	*OperationsOptions
+
+	wait    bool
+	timeout time.Duration
+
+	clusterName   string
+	componentName string
+	// primary is the current primary or leader instance
+	primary string
+	// executor is the executor of the lorry client, it's replaced in tests
+	executor cmdexec.RemoteExecutor
+}
+
+func (o *switchoverOptions) complete() error {
+	o.clusterName = o.Name
+	o.componentName = o.Component
+	if o.componentName == "" && len(o.ComponentNames) > 0 {
+		o.componentName = o.ComponentNames[0]
+	}
+	return nil
+}
+
+// componentPods returns the pods of the component
+func (o *switchoverOptions) componentPods() ([]corev1.Pod, error) {
+	pods, err := o.Client.CoreV1().Pods(o.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			constant.AppInstanceLabelKey:    o.clusterName,
+			constant.KBAppComponentLabelKey: o.componentName,
+		}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// checkHealth checks the role and the health of the instances by lorry, the candidate must be healthy
+// and has the same role as its label, and the primary must be healthy to hand over its role.
+func (o *switchoverOptions) checkHealth(pods []corev1.Pod) error {
+	for i := range pods {
+		pod := &pods[i]
+		role := pod.Labels[constant.RoleLabelKey]
+		isPrimary := slices.Contains(o.switchoverRoles, role)
+		if isPrimary {
+			o.primary = pod.Name
+		}
+		if !isPrimary && pod.Name != o.Instance {
+			continue
+		}
+
+		var err error
+		if !podutils.IsPodReady(pod) {
+			err = fmt.Errorf("instance %s is not ready", pod.Name)
+		} else {
+			err = o.checkInstance(pod, role)
+		}
+		if err == nil {
+			continue
+		}
+		if isPrimary {
+			return fmt.Errorf("the %s instance %s is unhealthy: %v, use --force to failover", role, pod.Name, err)
+		}
+		return fmt.Errorf("the candidate instance %s is unhealthy: %v", pod.Name, err)
+	}
+	if o.primary == "" {
+		return fmt.Errorf("no %s instance is found in component %s, use --force to failover", o.switchoverRoles[0], o.componentName)
+	}
+	return nil
+}
+
+// checkInstance checks the database is running and the role reported by lorry is the same as the label
+func (o *switchoverOptions) checkInstance(pod *corev1.Pod, role string) error {
+	cli, err := lorryclient.NewK8sExecClientWithPod(pod)
+	if err != nil {
+		return err
+	}
+	if cli == nil {
+		return fmt.Errorf("lorry container not found in instance %s", pod.Name)
+	}
+	if o.executor != nil {
+		cli.Executor = o.executor
+	}
+	ctx := context.Background()
+	if _, err = cli.Request(ctx, string(lorryutil.CheckRunningOperation), "GET", nil); err != nil {
+		return err
+	}
+	actualRole, err := cli.GetRole(ctx)
+	if err != nil {
+		return err
+	}
+	if actualRole != role {
+		return fmt.Errorf("the role of the instance is %s, but it's labeled as %s", actualRole, role)
+	}
+	return nil
+}
+
+// waitForRoleChange waits for the OpsRequest to succeed and the primary or leader role is changed
+func (o *switchoverOptions) waitForRoleChange(opsName string) error {
+	fmt.Fprintf(o.Out, "Waiting for the switchover to complete...\n")
+	return wait.PollUntilContextTimeout(context.Background(), switchoverPollInterval, o.timeout, true, func(ctx context.Context) (bool, error) {
+		ops := &appsv1alpha1.OpsRequest{}
+		if err := cluster.GetK8SClientObject(o.Dynamic, ops, types.OpsGVR(), o.Namespace, opsName); err != nil {
+			return false, err
+		}
+		if ops.Status.Phase == appsv1alpha1.OpsFailedPhase || ops.Status.Phase == appsv1alpha1.OpsCancelledPhase {
+			return false, fmt.Errorf("switchover OpsRequest %s is %s", opsName, ops.Status.Phase)
+		}
+		if ops.Status.Phase != appsv1alpha1.OpsSucceedPhase {
+			return false, nil
+		}
+
+		pods, err := o.componentPods()
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range pods {
+			if !slices.Contains(o.switchoverRoles, pod.Labels[constant.RoleLabelKey]) {
+				continue
+			}
+			if o.Instance != "" {
+				return pod.Name == o.Instance, nil
+			}
+			return pod.Name != o.primary, nil
+		}
+		return false, nil
+	})
+}
+
+// printTopology prints the roles of the instances of the component
+func (o *switchoverOptions) printTopology() error {
+	pods, err := o.componentPods()
+	if err != nil {
+		return err
+	}
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("INSTANCE", "ROLE", "STATUS", "NODE")
+	for _, pod := range pods {
+		tbl.AddRow(pod.Name, pod.Labels[constant.RoleLabelKey], pod.Status.Phase, pod.Spec.NodeName)
+	}
+	tbl.Print()
+	return nil
+}
+
+func (o *switchoverOptions) run() error {
+	if err := o.complete(); err != nil {
+		return err
+	}
+	// only check the health when the OpsRequest is created
+	dryRun := (o.DryRun != "" && o.DryRun != "none") || action.ExportEnabled()
+	if !o.force && !dryRun {
+		pods, err := o.componentPods()
+		if err != nil {
+			return err
+		}
+		if err = o.checkHealth(pods); err != nil {
+			return err
+		}
+	}
+	if err := o.Run(); err != nil {
+		return err
+	}
+	if !o.wait || dryRun {
+		return nil
+	}
+	// the name is set to the created OpsRequest
+	if err := o.waitForRoleChange(o.Name); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Switchover of component %s in cluster %s completed\n", o.componentName, o.clusterName)
+	return o.printTopology()
+}
+
+// NewSwitchoverCmd creates a switchover command which checks the health of the instances before the switchover
+func NewSwitchoverCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &switchoverOptions{OperationsOptions: newBaseOperationsOptions(f, streams, appsv1alpha1.SwitchoverType, false)}
+	cmd := &cobra.Command{
+		Use:               "switchover NAME [--component=<comp-name>] [--instance <instance-name>]",
+		Short:             "Switchover the primary or leader of the component to another instance after checking the health of the instances",
+		Example:           switchoverExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Args = args
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.CompleteComponentsFlag())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentFlag(f, cmd, &o.Component, "Specify the component name of the cluster, if the cluster has multiple components, you need to specify a component")
+	cmd.Flags().StringVar(&o.Instance, "instance", "", "Specify the instance name as the new primary or leader of the cluster, you can get the instance name by running \"kbcli cluster list-instances\"")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Skip the health checks of the instances and the maintenance mode check, it's used to failover when the primary or leader is unhealthy")
+	cmd.Flags().BoolVar(&o.wait, "wait", true, "Wait for the role of the instances to be changed and print the new topology")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 10*time.Minute, "The time to wait for the switchover to complete")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before switchover")
+	o.addCommonFlags(cmd, f)
+	return cmd
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	clientfake "k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("switchover", func() {
+	const clusterName = "cluster-switchover"
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+		pods    []*corev1.Pod
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = cmdtesting.NewTestFactory().WithNamespace(testing.Namespace)
+		tf.Client = &clientfake.RESTClient{}
+
+		c := testing.FakeCluster(clusterName, testing.Namespace)
+		c.Spec.ComponentSpecs = c.Spec.ComponentSpecs[:1]
+		pods = nil
+		for i, role := range []string{constant.Leader, constant.Follower} {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%s-%d", clusterName, testing.ComponentName, i),
+					Namespace: testing.Namespace,
+					Labels: map[string]string{
+						constant.AppInstanceLabelKey:    clusterName,
+						constant.KBAppComponentLabelKey: testing.ComponentName,
+						constant.RoleLabelKey:           role,
+					},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{testing.FakeLorryContainer()}},
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			pods = append(pods, pod)
+		}
+		tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeClusterDef(), testing.FakeClusterVersion(), c, pods[0], pods[1])
+
+		kubeConfig, err := testing.WriteFakeKubeConfig(GinkgoT().TempDir())
+		Expect(err).Should(Succeed())
+		GinkgoT().Setenv("KUBECONFIG", kubeConfig)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	newSwitchoverOptions := func(executor *testing.FakeLorryExecutor) *switchoverOptions {
+		o := &switchoverOptions{OperationsOptions: newBaseOperationsOptions(tf, streams, appsv1alpha1.SwitchoverType, false)}
+		o.Dynamic = tf.FakeDynamicClient
+		o.Client = testing.FakeClientSet(pods[0], pods[1])
+		o.Name = clusterName
+		o.Namespace = testing.Namespace
+		o.Instance = pods[1].Name
+		o.OpsRequestName = "switchover"
+		o.autoApprove = true
+		o.timeout = time.Second
+		o.executor = executor
+		Expect(o.CompleteComponentsFlag()).Should(Succeed())
+		Expect(o.Validate()).Should(Succeed())
+		return o
+	}
+
+	It("check the health of the instances before switchover", func() {
+		By("the candidate has a different role reported by lorry")
+		executor := testing.NewFakeLorryExecutor().WithResponse(string(lorryutil.GetRoleOperation), map[string]any{"role": constant.Leader})
+		o := newSwitchoverOptions(executor)
+		Expect(o.run()).Should(MatchError(ContainSubstring("the candidate instance " + pods[1].Name + " is unhealthy")))
+
+		By("the primary is unhealthy")
+		executor = testing.NewFakeLorryExecutor().
+			WithPodError(pods[0].Name, string(lorryutil.CheckRunningOperation), "connection refused")
+		o = newSwitchoverOptions(executor)
+		Expect(o.run()).Should(MatchError(ContainSubstring("use --force to failover")))
+
+		By("the instances are healthy")
+		executor = testing.NewFakeLorryExecutor().
+			WithPodResponse(pods[0].Name, string(lorryutil.GetRoleOperation), map[string]any{"role": constant.Leader}).
+			WithPodResponse(pods[1].Name, string(lorryutil.GetRoleOperation), map[string]any{"role": constant.Follower})
+		o = newSwitchoverOptions(executor)
+		o.wait = false
+		Expect(o.run()).Should(Succeed())
+		Expect(o.primary).Should(Equal(pods[0].Name))
+		Expect(executor.Requests()).Should(HaveLen(4))
+		ops := &appsv1alpha1.OpsRequest{}
+		Expect(cluster.GetK8SClientObject(o.Dynamic, ops, types.OpsGVR(), testing.Namespace, "switchover")).Should(Succeed())
+		Expect(ops.Spec.SwitchoverList).Should(HaveLen(1))
+		Expect(ops.Spec.SwitchoverList[0].InstanceName).Should(Equal(pods[1].Name))
+	})
+
+	It("force switchover and wait for the role change", func() {
+		executor := testing.NewFakeLorryExecutor().WithError(string(lorryutil.CheckRunningOperation), "connection refused")
+		o := newSwitchoverOptions(executor)
+		o.force = true
+		o.wait = false
+		Expect(o.run()).Should(Succeed())
+		Expect(executor.Requests()).Should(BeEmpty())
+
+		By("wait for the OpsRequest to succeed and the role to be changed")
+		switchoverPollInterval = 10 * time.Millisecond
+		Expect(o.waitForRoleChange("switchover")).Should(HaveOccurred())
+		opsClient := tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace)
+		obj, err := opsClient.Get(context.TODO(), "switchover", metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(unstructured.SetNestedField(obj.Object, string(appsv1alpha1.OpsSucceedPhase), "status", "phase")).Should(Succeed())
+		_, err = opsClient.Update(context.TODO(), obj, metav1.UpdateOptions{})
+		Expect(err).Should(Succeed())
+		for i, role := range []string{constant.Follower, constant.Leader} {
+			pod := pods[i].DeepCopy()
+			pod.Labels[constant.RoleLabelKey] = role
+			_, err = o.Client.CoreV1().Pods(testing.Namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+			Expect(err).Should(Succeed())
+		}
+		Expect(o.waitForRoleChange("switchover")).Should(Succeed())
+		Expect(o.printTopology()).Should(Succeed())
+		Expect(out.String()).Should(MatchRegexp(pods[1].Name + `\s+leader`))
+	})
+})
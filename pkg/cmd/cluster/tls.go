@@ -0,0 +1,581 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sapitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/factory"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var (
+	tlsEnableExample = templates.Examples(`
+		# Enable TLS of all components in cluster mycluster with certificates signed by KubeBlocks
+		kbcli cluster tls enable mycluster
+
+		# Enable TLS of component mysql with certificates issued by the cert-manager ClusterIssuer my-issuer
+		kbcli cluster tls enable mycluster --components mysql --issuer cert-manager --cert-manager-issuer my-issuer --cert-manager-issuer-kind ClusterIssuer
+
+		# Enable TLS with the certificates provided by user in secret my-certs
+		kbcli cluster tls enable mycluster --issuer UserProvided --secret-name my-certs`)
+
+	tlsDisableExample = templates.Examples(`
+		# Disable TLS of all components in cluster mycluster
+		kbcli cluster tls disable mycluster`)
+
+	tlsRotateExample = templates.Examples(`
+		# Rotate the certificates of all TLS enabled components in cluster mycluster and restart them
+		kbcli cluster tls rotate mycluster
+
+		# Rotate the certificates of component mysql without restarting it
+		kbcli cluster tls rotate mycluster --components mysql --restart=false`)
+)
+
+const (
+	// tlsIssuerCertManager issues the certificates by cert-manager, the certificates are passed
+	// to KubeBlocks as the user provided certificates.
+	tlsIssuerCertManager = "cert-manager"
+	// certManagerCertificateAnnotation is the annotation of the secret managed by cert-manager
+	certManagerCertificateAnnotation = "cert-manager.io/certificate-name"
+	// certExpiringThreshold is the duration before expiry to warn about the expiring certificates
+	certExpiringThreshold = 30 * 24 * time.Hour
+)
+
+type tlsOptions struct {
+	clusterName string
+	namespace   string
+	components  []string
+
+	// enable options
+	issuer                string
+	secretName            string
+	caKey                 string
+	certKey               string
+	keyKey                string
+	certManagerIssuer     string
+	certManagerIssuerKind string
+
+	// rotate options
+	restart bool
+
+	// force changes the TLS settings even if the cluster is in maintenance mode
+	force  bool
+	gitOps gitOpsOptions
+
+	dynamic dynamic.Interface
+	client  kubernetes.Interface
+	genericiooptions.IOStreams
+}
+
+func NewTLSCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tls",
+		Short: "Manage the TLS certificates of the cluster.",
+	}
+	cmd.AddCommand(
+		newTLSEnableCmd(f, streams),
+		newTLSDisableCmd(f, streams),
+		newTLSRotateCmd(f, streams),
+	)
+	return cmd
+}
+
+func newTLSEnableCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &tlsOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "enable NAME",
+		Short:             "Enable TLS of the cluster components.",
+		Example:           tlsEnableExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.validateEnable())
+			util.CheckErr(o.runEnable())
+		},
+	}
+	flags.AddComponentsFlag(f, cmd, &o.components, "Component names to enable TLS, default to all components")
+	cmd.Flags().StringVar(&o.issuer, "issuer", string(appsv1alpha1.IssuerKubeBlocks), fmt.Sprintf("The issuer of the certificates, one of %s, %s and %s",
+		appsv1alpha1.IssuerKubeBlocks, appsv1alpha1.IssuerUserProvided, tlsIssuerCertManager))
+	cmd.Flags().StringVar(&o.secretName, "secret-name", "", "The secret containing the certificates provided by user, required by the UserProvided issuer")
+	cmd.Flags().StringVar(&o.caKey, "ca-key", factory.CAName, "The key of the CA certificate in the secret")
+	cmd.Flags().StringVar(&o.certKey, "cert-key", factory.CertName, "The key of the certificate in the secret")
+	cmd.Flags().StringVar(&o.keyKey, "key-key", factory.KeyName, "The key of the private key in the secret")
+	cmd.Flags().StringVar(&o.certManagerIssuer, "cert-manager-issuer", "", "The name of the cert-manager issuer, required by the cert-manager issuer")
+	cmd.Flags().StringVar(&o.certManagerIssuerKind, "cert-manager-issuer-kind", "Issuer", "The kind of the cert-manager issuer, Issuer or ClusterIssuer")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Enable TLS even if the cluster is in maintenance mode, the components are restarted to apply it")
+	o.gitOps.addFlags(cmd)
+	return cmd
+}
+
+func newTLSDisableCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &tlsOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "disable NAME",
+		Short:             "Disable TLS of the cluster components.",
+		Example:           tlsDisableExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.runDisable())
+		},
+	}
+	flags.AddComponentsFlag(f, cmd, &o.components, "Component names to disable TLS, default to all components")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Disable TLS even if the cluster is in maintenance mode, the components are restarted to apply it")
+	o.gitOps.addFlags(cmd)
+	return cmd
+}
+
+func newTLSRotateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &tlsOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "rotate NAME",
+		Short:             "Rotate the TLS certificates of the cluster components.",
+		Example:           tlsRotateExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.runRotate())
+		},
+	}
+	flags.AddComponentsFlag(f, cmd, &o.components, "Component names to rotate the certificates, default to all TLS enabled components")
+	cmd.Flags().BoolVar(&o.restart, "restart", true, "Restart the components by an OpsRequest to load the new certificates")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Restart the components even if the cluster is in maintenance mode")
+	AddForceDirectFlag(cmd, &o.gitOps.forceDirect)
+	return cmd
+}
+
+func (o *tlsOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to manage TLS of one cluster")
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *tlsOptions) validateEnable() error {
+	switch o.issuer {
+	case string(appsv1alpha1.IssuerKubeBlocks):
+	case string(appsv1alpha1.IssuerUserProvided):
+		if o.secretName == "" {
+			return fmt.Errorf("--secret-name is required by the %s issuer", appsv1alpha1.IssuerUserProvided)
+		}
+		secret, err := o.client.CoreV1().Secrets(o.namespace).Get(context.TODO(), o.secretName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, key := range []string{o.caKey, o.certKey, o.keyKey} {
+			if _, ok := secret.Data[key]; !ok {
+				return fmt.Errorf("key %s is not found in secret %s", key, o.secretName)
+			}
+		}
+	case tlsIssuerCertManager:
+		if o.certManagerIssuer == "" {
+			return fmt.Errorf("--cert-manager-issuer is required by the %s issuer", tlsIssuerCertManager)
+		}
+		if o.certManagerIssuerKind != "Issuer" && o.certManagerIssuerKind != "ClusterIssuer" {
+			return fmt.Errorf("invalid cert-manager issuer kind %s, should be Issuer or ClusterIssuer", o.certManagerIssuerKind)
+		}
+	default:
+		return fmt.Errorf("invalid issuer %s, should be one of %s, %s and %s", o.issuer,
+			appsv1alpha1.IssuerKubeBlocks, appsv1alpha1.IssuerUserProvided, tlsIssuerCertManager)
+	}
+	return nil
+}
+
+// getTLSComponents returns the indexes of the components to manage TLS, all components are
+// returned if no component is specified.
+func (o *tlsOptions) getTLSComponents(c *appsv1alpha1.Cluster) ([]int, error) {
+	var (
+		indexes []int
+		names   []string
+	)
+	for i, comp := range c.Spec.ComponentSpecs {
+		names = append(names, comp.Name)
+		if len(o.components) == 0 || slices.Contains(o.components, comp.Name) {
+			indexes = append(indexes, i)
+		}
+	}
+	for _, name := range o.components {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("component %s is not found in cluster %s", name, c.Name)
+		}
+	}
+	return indexes, nil
+}
+
+func (o *tlsOptions) runEnable() error {
+	if err := action.CheckExportUnsupported("tls enable"); err != nil {
+		return err
+	}
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	// changing the TLS settings restarts the components
+	if err = CheckMaintenance(c, appsv1alpha1.RestartType, o.force); err != nil {
+		return err
+	}
+	indexes, err := o.getTLSComponents(c)
+	if err != nil {
+		return err
+	}
+	var (
+		names []string
+		certs []*unstructured.Unstructured
+	)
+	for _, i := range indexes {
+		comp := &c.Spec.ComponentSpecs[i]
+		issuer := &appsv1alpha1.Issuer{Name: appsv1alpha1.IssuerName(o.issuer)}
+		switch o.issuer {
+		case string(appsv1alpha1.IssuerUserProvided):
+			issuer.SecretRef = &appsv1alpha1.TLSSecretRef{Name: o.secretName, CA: o.caKey, Cert: o.certKey, Key: o.keyKey}
+		case tlsIssuerCertManager:
+			certs = append(certs, buildCertManagerCertificate(c, comp.Name, o.certManagerIssuer, o.certManagerIssuerKind))
+			// the certificates issued by cert-manager are passed to KubeBlocks as user provided
+			issuer.Name = appsv1alpha1.IssuerUserProvided
+			issuer.SecretRef = &appsv1alpha1.TLSSecretRef{
+				Name: certManagerSecretName(c.Name, comp.Name),
+				CA:   factory.CAName,
+				Cert: factory.CertName,
+				Key:  factory.KeyName,
+			}
+		}
+		comp.TLS = true
+		comp.Issuer = issuer
+		names = append(names, comp.Name)
+	}
+	if err = o.gitOps.check(c, tlsGitOpsPatch, o.Out); err != nil {
+		return err
+	}
+	// the certificates are required by the patch written for the GitOps tools too
+	for _, cert := range certs {
+		if err = util.CreateResourceIfAbsent(o.dynamic, types.CertificateGVR(), c.Namespace, cert); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("cert-manager is not installed, please install it first: %v", err)
+			}
+			return err
+		}
+	}
+	if o.gitOps.patchWritten {
+		return nil
+	}
+	if err = o.patchComponents(c); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "TLS of cluster %s components %s is enabled with the %s issuer\n", c.Name, strings.Join(names, ","), o.issuer)
+	return nil
+}
+
+func (o *tlsOptions) runDisable() error {
+	if err := action.CheckExportUnsupported("tls disable"); err != nil {
+		return err
+	}
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	// changing the TLS settings restarts the components
+	if err = CheckMaintenance(c, appsv1alpha1.RestartType, o.force); err != nil {
+		return err
+	}
+	indexes, err := o.getTLSComponents(c)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, i := range indexes {
+		comp := &c.Spec.ComponentSpecs[i]
+		if !comp.TLS {
+			continue
+		}
+		comp.TLS = false
+		comp.Issuer = nil
+		names = append(names, comp.Name)
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(o.Out, "TLS of cluster %s is not enabled\n", c.Name)
+		return nil
+	}
+	if err = o.gitOps.check(c, tlsGitOpsPatch, o.Out); err != nil || o.gitOps.patchWritten {
+		return err
+	}
+	if err = o.patchComponents(c); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "TLS of cluster %s components %s is disabled\n", c.Name, strings.Join(names, ","))
+	return nil
+}
+
+// runRotate deletes the certificate secrets to make them be regenerated, the secrets generated by
+// KubeBlocks are recreated by the controller and the secrets issued by cert-manager are reissued,
+// then the components are restarted to load the new certificates.
+func (o *tlsOptions) runRotate() error {
+	if err := action.CheckExportUnsupported("tls rotate"); err != nil {
+		return err
+	}
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	if o.restart {
+		if err = CheckMaintenance(c, appsv1alpha1.RestartType, o.force); err != nil {
+			return err
+		}
+	}
+	indexes, err := o.getTLSComponents(c)
+	if err != nil {
+		return err
+	}
+	if err = CheckGitOps(c, o.gitOps.forceDirect, o.Out); err != nil {
+		return err
+	}
+	var names []string
+	for _, i := range indexes {
+		comp := c.Spec.ComponentSpecs[i]
+		if !comp.TLS {
+			if len(o.components) > 0 {
+				return fmt.Errorf("TLS of component %s is not enabled", comp.Name)
+			}
+			continue
+		}
+		secretName, _ := getTLSSecretRef(c.Name, &comp)
+		if comp.Issuer != nil && comp.Issuer.Name == appsv1alpha1.IssuerUserProvided {
+			secret, err := o.client.CoreV1().Secrets(c.Namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if _, ok := secret.Annotations[certManagerCertificateAnnotation]; !ok {
+				return fmt.Errorf("the certificates of component %s are provided by user, please update secret %s to rotate them", comp.Name, secretName)
+			}
+		}
+		if err = o.client.CoreV1().Secrets(c.Namespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		names = append(names, comp.Name)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("TLS of cluster %s is not enabled", c.Name)
+	}
+	fmt.Fprintf(o.Out, "Certificates of cluster %s components %s are rotated\n", c.Name, strings.Join(names, ","))
+	if !o.restart {
+		fmt.Fprintf(o.Out, "Restart the components to load the new certificates: kbcli cluster restart %s --components %s\n", c.Name, strings.Join(names, ","))
+		return nil
+	}
+	ops, err := util.ConvertObjToUnstructured(buildTLSRestartOps(c, names))
+	if err != nil {
+		return err
+	}
+	if _, err = o.dynamic.Resource(types.OpsGVR()).Namespace(c.Namespace).Create(context.TODO(), ops, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "OpsRequest %s is created to restart the components, check it by: kbcli cluster describe-ops %s -n %s\n", ops.GetName(), ops.GetName(), c.Namespace)
+	return nil
+}
+
+// tlsGitOpsPatch returns the patch of the TLS settings changed in the components of the cluster
+func tlsGitOpsPatch(c *appsv1alpha1.Cluster) (map[string]interface{}, error) {
+	return componentSpecsPatch(c.Spec.ComponentSpecs)
+}
+
+func (o *tlsOptions) patchComponents(c *appsv1alpha1.Cluster) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"componentSpecs": c.Spec.ComponentSpecs,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = o.dynamic.Resource(types.ClusterGVR()).Namespace(c.Namespace).Patch(context.TODO(), c.Name,
+		k8sapitypes.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func buildTLSRestartOps(c *appsv1alpha1.Cluster, compNames []string) *appsv1alpha1.OpsRequest {
+	ops := &appsv1alpha1.OpsRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fmt.Sprintf("%s/%s", types.AppsAPIGroup, types.AppsAPIVersion),
+			Kind:       types.KindOps,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-tls-rotate-%s", c.Name, uuid.NewString()[:8]),
+			Namespace: c.Namespace,
+		},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			ClusterRef: c.Name,
+			Type:       appsv1alpha1.RestartType,
+		},
+	}
+	for _, name := range compNames {
+		ops.Spec.RestartList = append(ops.Spec.RestartList, appsv1alpha1.ComponentOps{ComponentName: name})
+	}
+	return ops
+}
+
+func certManagerSecretName(clusterName, compName string) string {
+	return fmt.Sprintf("%s-%s-cert-manager-tls", clusterName, compName)
+}
+
+// buildCertManagerCertificate builds the cert-manager Certificate of the component, the DNS names
+// cover the component services and the pods of the headless service.
+func buildCertManagerCertificate(c *appsv1alpha1.Cluster, compName, issuer, issuerKind string) *unstructured.Unstructured {
+	svc := fmt.Sprintf("%s-%s", c.Name, compName)
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": fmt.Sprintf("%s/%s", types.CertificateGVR().Group, types.CertificateGVR().Version),
+			"kind":       "Certificate",
+			"metadata": map[string]interface{}{
+				"name":      fmt.Sprintf("%s-%s-tls", c.Name, compName),
+				"namespace": c.Namespace,
+				"labels": map[string]interface{}{
+					constant.AppInstanceLabelKey:    c.Name,
+					constant.KBAppComponentLabelKey: compName,
+				},
+			},
+			"spec": map[string]interface{}{
+				"secretName": certManagerSecretName(c.Name, compName),
+				"commonName": svc,
+				"dnsNames": []interface{}{
+					svc,
+					fmt.Sprintf("%s.%s.svc", svc, c.Namespace),
+					fmt.Sprintf("*.%s-headless.%s.svc", svc, c.Namespace),
+				},
+				"issuerRef": map[string]interface{}{
+					"name": issuer,
+					"kind": issuerKind,
+				},
+			},
+		},
+	}
+}
+
+// getTLSSecretRef returns the secret name and the certificate key of the component TLS certificates
+func getTLSSecretRef(clusterName string, comp *appsv1alpha1.ClusterComponentSpec) (string, string) {
+	if comp.Issuer != nil && comp.Issuer.Name == appsv1alpha1.IssuerUserProvided && comp.Issuer.SecretRef != nil {
+		return comp.Issuer.SecretRef.Name, comp.Issuer.SecretRef.Cert
+	}
+	return fmt.Sprintf("%s-%s-tls-certs", clusterName, comp.Name), factory.CertName
+}
+
+// getCertExpiry parses the certificate in the secret and returns its expiry time
+func getCertExpiry(secret *corev1.Secret, certKey string) (time.Time, error) {
+	data, ok := secret.Data[certKey]
+	if !ok {
+		return time.Time{}, fmt.Errorf("key %s is not found in secret %s", certKey, secret.Name)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("failed to decode the certificate in secret %s", secret.Name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// showTLS shows the TLS issuer and the certificate expiry of the TLS enabled components, and warns
+// if the certificates are expired or close to expiring.
+func showTLS(client kubernetes.Interface, c *appsv1alpha1.Cluster, now time.Time, out io.Writer) {
+	if c == nil || !slices.ContainsFunc(c.Spec.ComponentSpecs, func(comp appsv1alpha1.ClusterComponentSpec) bool {
+		return comp.TLS
+	}) {
+		return
+	}
+	var warnings []string
+	tbl := newTbl(out, "\nTLS:", "COMPONENT", "ISSUER", "SECRET", "EXPIRES", "STATUS")
+	for i := range c.Spec.ComponentSpecs {
+		comp := &c.Spec.ComponentSpecs[i]
+		if !comp.TLS {
+			continue
+		}
+		issuer := string(appsv1alpha1.IssuerKubeBlocks)
+		if comp.Issuer != nil {
+			issuer = string(comp.Issuer.Name)
+		}
+		secretName, certKey := getTLSSecretRef(c.Name, comp)
+		expires, status := printer.NoneString, "Unknown"
+		secret, err := client.CoreV1().Secrets(c.Namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+		if err == nil {
+			if _, ok := secret.Annotations[certManagerCertificateAnnotation]; ok {
+				issuer = tlsIssuerCertManager
+			}
+			if notAfter, err := getCertExpiry(secret, certKey); err == nil {
+				expires = util.TimeFormat(&metav1.Time{Time: notAfter})
+				switch left := notAfter.Sub(now); {
+				case left <= 0:
+					status = "Expired"
+					warnings = append(warnings, fmt.Sprintf("the certificates of component %s are expired", comp.Name))
+				case left < certExpiringThreshold:
+					status = fmt.Sprintf("Expiring in %dd", int(left.Hours()/24))
+					warnings = append(warnings, fmt.Sprintf("the certificates of component %s expire in %d days", comp.Name, int(left.Hours()/24)))
+				default:
+					status = "Valid"
+				}
+			}
+		}
+		tbl.AddRow(comp.Name, issuer, secretName, expires, status)
+	}
+	tbl.Print()
+	for _, w := range warnings {
+		fmt.Fprintf(out, "Warning: %s, rotate them by: kbcli cluster tls rotate %s -n %s\n", w, c.Name, c.Namespace)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster tls", func() {
+	const (
+		namespace   = "test"
+		clusterName = "mycluster"
+	)
+
+	var (
+		out *bytes.Buffer
+		o   *tlsOptions
+	)
+
+	genCert := func(notAfter time.Time) []byte {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).Should(Succeed())
+		tpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+		Expect(err).Should(Succeed())
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		o = &tlsOptions{
+			clusterName: clusterName,
+			namespace:   namespace,
+			dynamic:     clitesting.FakeDynamicClient(clitesting.FakeCluster(clusterName, namespace)),
+			client:      kubefakeclient.NewSimpleClientset(),
+			IOStreams:   genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+	})
+
+	It("validate enable", func() {
+		o.issuer = "unknown"
+		Expect(o.validateEnable()).Should(MatchError(ContainSubstring("invalid issuer")))
+		o.issuer = string(appsv1alpha1.IssuerUserProvided)
+		Expect(o.validateEnable()).Should(MatchError(ContainSubstring("--secret-name is required")))
+		o.issuer = tlsIssuerCertManager
+		Expect(o.validateEnable()).Should(MatchError(ContainSubstring("--cert-manager-issuer is required")))
+		o.certManagerIssuer = "my-issuer"
+		o.certManagerIssuerKind = "Other"
+		Expect(o.validateEnable()).Should(MatchError(ContainSubstring("invalid cert-manager issuer kind")))
+		o.certManagerIssuerKind = "ClusterIssuer"
+		Expect(o.validateEnable()).Should(Succeed())
+	})
+
+	It("enable and disable", func() {
+		o.issuer = string(appsv1alpha1.IssuerKubeBlocks)
+		o.components = []string{"not-exist"}
+		Expect(o.runEnable()).Should(MatchError(ContainSubstring("component not-exist is not found")))
+
+		o.components = []string{clitesting.ComponentName}
+		Expect(o.runEnable()).Should(Succeed())
+		c, err := cluster.GetClusterByName(o.dynamic, clusterName, namespace)
+		Expect(err).Should(Succeed())
+		Expect(c.Spec.ComponentSpecs[0].TLS).Should(BeTrue())
+		Expect(c.Spec.ComponentSpecs[0].Issuer.Name).Should(Equal(appsv1alpha1.IssuerKubeBlocks))
+		Expect(c.Spec.ComponentSpecs[1].TLS).Should(BeFalse())
+
+		o.components = nil
+		Expect(o.runDisable()).Should(Succeed())
+		c, err = cluster.GetClusterByName(o.dynamic, clusterName, namespace)
+		Expect(err).Should(Succeed())
+		Expect(c.Spec.ComponentSpecs[0].TLS).Should(BeFalse())
+		Expect(c.Spec.ComponentSpecs[0].Issuer).Should(BeNil())
+	})
+
+	It("refuse the cluster managed by GitOps", func() {
+		c := clitesting.FakeCluster(clusterName, namespace)
+		c.SetLabels(map[string]string{"argocd.argoproj.io/instance": "db"})
+		o.dynamic = clitesting.FakeDynamicClient(c)
+		o.issuer = string(appsv1alpha1.IssuerKubeBlocks)
+		o.components = []string{clitesting.ComponentName}
+		Expect(o.runEnable()).Should(MatchError(ContainSubstring("--force-direct")))
+		c, err := cluster.GetClusterByName(o.dynamic, clusterName, namespace)
+		Expect(err).Should(Succeed())
+		Expect(c.Spec.ComponentSpecs[0].TLS).Should(BeFalse())
+
+		o.gitOps.forceDirect = true
+		Expect(o.runEnable()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("managed by ArgoCD"))
+		o.gitOps.forceDirect = false
+		Expect(o.runRotate()).Should(MatchError(ContainSubstring("--force-direct")))
+		Expect(o.runDisable()).Should(MatchError(ContainSubstring("--force-direct")))
+	})
+
+	It("refuse the cluster in maintenance mode", func() {
+		c := clitesting.FakeCluster(clusterName, namespace)
+		c.SetAnnotations(map[string]string{maintenanceUntilAnnotationKey: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)})
+		o.dynamic = clitesting.FakeDynamicClient(c)
+		o.issuer = string(appsv1alpha1.IssuerKubeBlocks)
+		o.components = []string{clitesting.ComponentName}
+		Expect(o.runEnable()).Should(MatchError(ContainSubstring("maintenance mode")))
+
+		o.force = true
+		Expect(o.runEnable()).Should(Succeed())
+		o.force = false
+		o.restart = true
+		Expect(o.runRotate()).Should(MatchError(ContainSubstring("maintenance mode")))
+		o.restart = false
+		Expect(o.runRotate()).Should(Succeed())
+		Expect(o.runDisable()).Should(MatchError(ContainSubstring("maintenance mode")))
+	})
+
+	It("refuse to export the manifests", func() {
+		action.SetExportDir(GinkgoT().TempDir())
+		defer action.SetExportDir("")
+		o.issuer = string(appsv1alpha1.IssuerKubeBlocks)
+		Expect(o.runEnable()).Should(MatchError(ContainSubstring("--export-to-dir")))
+		Expect(o.runDisable()).Should(MatchError(ContainSubstring("--export-to-dir")))
+		Expect(o.runRotate()).Should(MatchError(ContainSubstring("--export-to-dir")))
+	})
+
+	It("build cert-manager certificate", func() {
+		c := clitesting.FakeCluster(clusterName, namespace)
+		cert := buildCertManagerCertificate(c, "mysql", "my-issuer", "ClusterIssuer")
+		Expect(cert.GetName()).Should(Equal("mycluster-mysql-tls"))
+		Expect(cert.Object["spec"]).Should(HaveKeyWithValue("secretName", "mycluster-mysql-cert-manager-tls"))
+		Expect(cert.Object["spec"]).Should(HaveKeyWithValue("issuerRef", map[string]interface{}{"name": "my-issuer", "kind": "ClusterIssuer"}))
+	})
+
+	It("rotate", func() {
+		Expect(o.runRotate()).Should(MatchError(ContainSubstring("TLS of cluster mycluster is not enabled")))
+
+		o.issuer = string(appsv1alpha1.IssuerKubeBlocks)
+		o.components = []string{clitesting.ComponentName}
+		Expect(o.runEnable()).Should(Succeed())
+		secretName := clusterName + "-" + clitesting.ComponentName + "-tls-certs"
+		_, err := o.client.CoreV1().Secrets(namespace).Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+		}, metav1.CreateOptions{})
+		Expect(err).Should(Succeed())
+
+		o.restart = true
+		Expect(o.runRotate()).Should(Succeed())
+		_, err = o.client.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+		Expect(err).Should(HaveOccurred())
+		ops, err := o.dynamic.Resource(types.OpsGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(ops.Items).Should(HaveLen(1))
+		Expect(ops.Items[0].Object["spec"]).Should(HaveKeyWithValue("type", string(appsv1alpha1.RestartType)))
+	})
+
+	It("show tls", func() {
+		now := time.Now()
+		c := clitesting.FakeCluster(clusterName, namespace)
+		showTLS(o.client, c, now, out)
+		Expect(out.String()).Should(BeEmpty())
+
+		c.Spec.ComponentSpecs[0].TLS = true
+		c.Spec.ComponentSpecs[0].Issuer = &appsv1alpha1.Issuer{Name: appsv1alpha1.IssuerKubeBlocks}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName + "-" + clitesting.ComponentName + "-tls-certs", Namespace: namespace},
+			Data:       map[string][]byte{"tls.crt": genCert(now.Add(10 * 24 * time.Hour))},
+		}
+		o.client = kubefakeclient.NewSimpleClientset(secret)
+		showTLS(o.client, c, now, out)
+		Expect(out.String()).Should(ContainSubstring("TLS:"))
+		Expect(out.String()).Should(ContainSubstring("Expiring in 9d"))
+		Expect(out.String()).Should(ContainSubstring("Warning: the certificates of component " + clitesting.ComponentName + " expire in 9 days"))
+
+		expiry, err := getCertExpiry(secret, "tls.crt")
+		Expect(err).Should(Succeed())
+		Expect(expiry.Unix()).Should(Equal(now.Add(10 * 24 * time.Hour).Unix()))
+		_, err = getCertExpiry(secret, "ca.crt")
+		Expect(err).Should(HaveOccurred())
+	})
+})
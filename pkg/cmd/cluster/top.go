@@ -0,0 +1,330 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var topExample = templates.Examples(`
+	# Show the CPU, memory and engine metrics of the instances of cluster mycluster, refreshed every 5 seconds
+	kbcli cluster top mycluster
+
+	# Show the metrics of component mysql once
+	kbcli cluster top mycluster --component mysql --once
+
+	# Refresh every 10 seconds, and query an external prometheus server for the engine metrics
+	kbcli cluster top mycluster --interval 10s --prometheus-url http://prometheus.example.com:9090`)
+
+// topEngineMetrics are the names of the engine metrics shown by top
+var topEngineMetrics = []string{"QPS", "Connections"}
+
+// clearScreen moves the cursor to the top left and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+type topOptions struct {
+	clusterName   string
+	componentName string
+	namespace     string
+	interval      time.Duration
+	once          bool
+	prometheusURL string
+
+	client     kubernetes.Interface
+	dynamic    dynamic.Interface
+	metrics    metrics.Interface
+	queryRange promQueryRangeFunc
+	now        func() time.Time
+	genericiooptions.IOStreams
+}
+
+// instanceUsage is the resource usage of an instance of the cluster
+type instanceUsage struct {
+	component string
+	name      string
+	role      string
+	status    string
+	cpu       *resource.Quantity
+	memory    *resource.Quantity
+	cpuLimit  *resource.Quantity
+	memLimit  *resource.Quantity
+	// engine are the values of topEngineMetrics, the missing values are printed as <none>
+	engine map[string]string
+}
+
+func NewTopCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &topOptions{IOStreams: streams, now: time.Now}
+	cmd := &cobra.Command{
+		Use:               "top NAME",
+		Short:             "Display the live CPU, memory and engine metrics of the cluster instances.",
+		Example:           topExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f, args))
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentFlag(f, cmd, &o.componentName, "Only show the instances of the specified component")
+	cmd.Flags().DurationVar(&o.interval, "interval", 5*time.Second, "The interval to refresh the metrics")
+	cmd.Flags().BoolVar(&o.once, "once", false, "Print the metrics once instead of refreshing them")
+	cmd.Flags().StringVar(&o.prometheusURL, "prometheus-url", "", "The URL of the prometheus server to query the engine metrics, if not specified, the prometheus addon server is queried through the API server proxy")
+	return cmd
+}
+
+func (o *topOptions) complete(f cmdutil.Factory, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to show the metrics of one cluster")
+	}
+	if o.interval < time.Second {
+		return fmt.Errorf("the interval must be at least 1s")
+	}
+	o.clusterName = args[0]
+	if o.namespace, _, err = f.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.client, err = f.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = f.DynamicClient(); err != nil {
+		return err
+	}
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	if o.metrics, err = metrics.NewForConfig(config); err != nil {
+		return err
+	}
+	if o.prometheusURL != "" {
+		o.queryRange = newHTTPPromQuerier(o.prometheusURL)
+		return nil
+	}
+	// the engine metrics are optional, the CPU and memory are still shown without prometheus
+	if o.queryRange, err = newProxyPromQuerier(o.client); err != nil {
+		fmt.Fprintf(o.ErrOut, "Warning: %v, the engine metrics are not shown\n", err)
+		o.queryRange = nil
+	}
+	return nil
+}
+
+func (o *topOptions) run() error {
+	for {
+		usages, err := o.collect()
+		if err != nil {
+			return err
+		}
+		if !o.once {
+			fmt.Fprint(o.Out, clearScreen)
+			fmt.Fprintf(o.Out, "Cluster %s, refreshed every %s at %s, press Ctrl+C to exit\n\n",
+				o.clusterName, o.interval, o.now().Format(time.RFC3339))
+		}
+		printInstanceUsages(o.Out, usages)
+		if o.once {
+			return nil
+		}
+		time.Sleep(o.interval)
+	}
+}
+
+// collect collects the resource usages and engine metrics of the instances
+func (o *topOptions) collect() ([]*instanceUsage, error) {
+	ctx := context.TODO()
+	c, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return nil, err
+	}
+	comps := map[string]appsv1alpha1.ClusterComponentSpec{}
+	for _, comp := range c.Spec.ComponentSpecs {
+		if o.componentName == "" || comp.Name == o.componentName {
+			comps[comp.Name] = comp
+		}
+	}
+	if len(comps) == 0 {
+		return nil, fmt.Errorf("component %s is not found in cluster %s", o.componentName, o.clusterName)
+	}
+
+	selector := fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.clusterName)
+	if o.componentName != "" {
+		selector += fmt.Sprintf(",%s=%s", constant.KBAppComponentLabelKey, o.componentName)
+	}
+	pods, err := o.client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	podMetrics := map[string]metricsapi.PodMetrics{}
+	if list, err := o.metrics.MetricsV1beta1().PodMetricses(o.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector}); err != nil {
+		klog.V(1).Infof("failed to get the pod metrics of cluster %s: %v", o.clusterName, err)
+	} else {
+		for _, m := range list.Items {
+			podMetrics[m.Name] = m
+		}
+	}
+
+	end := o.now()
+	var usages []*instanceUsage
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		compName := pod.Labels[constant.KBAppComponentLabelKey]
+		u := &instanceUsage{
+			component: compName,
+			name:      pod.Name,
+			role:      pod.Labels[constant.RoleLabelKey],
+			status:    string(pod.Status.Phase),
+			engine:    map[string]string{},
+		}
+		u.cpuLimit, u.memLimit = podLimits(pod)
+		if m, ok := podMetrics[pod.Name]; ok {
+			u.cpu, u.memory = podUsage(&m)
+		}
+		if comp, ok := comps[compName]; ok && o.queryRange != nil {
+			o.queryEngineMetrics(u, c.Spec.ClusterDefRef, comp.ComponentDefRef, end)
+		}
+		usages = append(usages, u)
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].component != usages[j].component {
+			return usages[i].component < usages[j].component
+		}
+		return usages[i].name < usages[j].name
+	})
+	return usages, nil
+}
+
+// queryEngineMetrics queries the latest values of the engine metrics of the instance from prometheus,
+// the rate metrics such as QPS need the samples in the last minutes.
+func (o *topOptions) queryEngineMetrics(u *instanceUsage, clusterDef, compDef string, end time.Time) {
+	matchers := fmt.Sprintf(`namespace="%s",app_kubernetes_io_instance="%s",pod="%s"`, o.namespace, o.clusterName, u.name)
+	for _, m := range getComponentMetrics(clusterDef, compDef) {
+		if !slices.Contains(topEngineMetrics, m.name) {
+			continue
+		}
+		values, err := o.queryRange(fmt.Sprintf(m.query, matchers), end.Add(-2*time.Minute), end, 30*time.Second)
+		if err != nil {
+			klog.V(1).Infof("failed to query metric %s of instance %s: %v", m.name, u.name, err)
+			continue
+		}
+		if cur, _, _, ok := summarizeValues(values); ok {
+			u.engine[m.name] = fmt.Sprintf(m.format, cur)
+		}
+	}
+}
+
+func noneIfEmpty(s string) string {
+	if s == "" {
+		return printer.NoneString
+	}
+	return s
+}
+
+// podUsage returns the total CPU and memory usage of the containers
+func podUsage(m *metricsapi.PodMetrics) (*resource.Quantity, *resource.Quantity) {
+	cpu, memory := resource.NewQuantity(0, resource.DecimalSI), resource.NewQuantity(0, resource.BinarySI)
+	for _, c := range m.Containers {
+		cpu.Add(*c.Usage.Cpu())
+		memory.Add(*c.Usage.Memory())
+	}
+	return cpu, memory
+}
+
+// podLimits returns the total CPU and memory limits of the containers, it's nil if any container has no limit
+func podLimits(pod *corev1.Pod) (*resource.Quantity, *resource.Quantity) {
+	sum := func(name corev1.ResourceName) *resource.Quantity {
+		total := resource.Quantity{}
+		for _, c := range pod.Spec.Containers {
+			limit, ok := c.Resources.Limits[name]
+			if !ok {
+				return nil
+			}
+			total.Add(limit)
+		}
+		return &total
+	}
+	return sum(corev1.ResourceCPU), sum(corev1.ResourceMemory)
+}
+
+// formatUsage formats the usage and its percentage of the limit
+func formatUsage(usage, limit *resource.Quantity, format func(q *resource.Quantity) string) (string, string) {
+	if usage == nil {
+		return printer.NoneString, printer.NoneString
+	}
+	if limit == nil || limit.IsZero() {
+		return format(usage), printer.NoneString
+	}
+	return format(usage), fmt.Sprintf("%.0f%%", float64(usage.MilliValue())/float64(limit.MilliValue())*100)
+}
+
+func formatCPU(q *resource.Quantity) string {
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMemory(q *resource.Quantity) string {
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
+
+func printInstanceUsages(out io.Writer, usages []*instanceUsage) {
+	tbl := printer.NewTablePrinter(out)
+	header := []interface{}{"COMPONENT", "NAME", "ROLE", "STATUS", "CPU(cores)", "CPU%", "MEMORY(bytes)", "MEMORY%"}
+	for _, name := range topEngineMetrics {
+		header = append(header, strings.ToUpper(name))
+	}
+	tbl.SetHeader(header...)
+	for _, u := range usages {
+		cpu, cpuPercent := formatUsage(u.cpu, u.cpuLimit, formatCPU)
+		memory, memPercent := formatUsage(u.memory, u.memLimit, formatMemory)
+		row := []interface{}{u.component, u.name, noneIfEmpty(u.role), u.status, cpu, cpuPercent, memory, memPercent}
+		for _, name := range topEngineMetrics {
+			row = append(row, noneIfEmpty(u.engine[name]))
+		}
+		tbl.AddRow(row...)
+	}
+	tbl.Print()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("cluster top", func() {
+	It("format usage", func() {
+		cpu, percent := formatUsage(resource.NewMilliQuantity(250, resource.DecimalSI), resource.NewQuantity(1, resource.DecimalSI), formatCPU)
+		Expect(cpu).Should(Equal("250m"))
+		Expect(percent).Should(Equal("25%"))
+		memory, percent := formatUsage(resource.NewQuantity(512*1024*1024, resource.BinarySI), nil, formatMemory)
+		Expect(memory).Should(Equal("512Mi"))
+		Expect(percent).Should(Equal("<none>"))
+		cpu, _ = formatUsage(nil, nil, formatCPU)
+		Expect(cpu).Should(Equal("<none>"))
+	})
+
+	It("run", func() {
+		c := clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace)
+		c.Spec.ComponentSpecs[0].ComponentDefRef = "mysql"
+		pods := clitesting.FakePods(2, clitesting.Namespace, clitesting.ClusterName)
+		pods.Items[0].Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		podMetrics := &metricsapi.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: pods.Items[0].Name, Namespace: clitesting.Namespace, Labels: pods.Items[0].Labels},
+			Containers: []metricsapi.ContainerMetrics{
+				{
+					Name: "fake-container",
+					Usage: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("256Mi"),
+					},
+				},
+			},
+		}
+
+		// the fake metrics client lists the pod metrics by the pods resource
+		mc := clitesting.FakeMetricsClientSet()
+		Expect(mc.Tracker().Create(metricsapi.SchemeGroupVersion.WithResource("pods"), podMetrics, clitesting.Namespace)).Should(Succeed())
+
+		var queries []string
+		out := &bytes.Buffer{}
+		o := &topOptions{
+			clusterName: clitesting.ClusterName,
+			namespace:   clitesting.Namespace,
+			once:        true,
+			client:      kubefakeclient.NewSimpleClientset(pods),
+			dynamic:     clitesting.FakeDynamicClient(c),
+			metrics:     mc,
+			now:         time.Now,
+			queryRange: func(query string, start, end time.Time, step time.Duration) ([]float64, error) {
+				queries = append(queries, query)
+				return []float64{10, 12}, nil
+			},
+			IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+		Expect(o.run()).Should(Succeed())
+		// QPS and connections of the two mysql instances
+		Expect(queries).Should(HaveLen(4))
+		Expect(queries[0]).Should(ContainSubstring(`pod="` + pods.Items[0].Name + `"`))
+		Expect(out.String()).Should(ContainSubstring("500m"))
+		Expect(out.String()).Should(ContainSubstring("50%"))
+		Expect(out.String()).Should(ContainSubstring("256Mi"))
+		Expect(out.String()).Should(ContainSubstring("12.00/s"))
+		Expect(out.String()).ShouldNot(ContainSubstring(clearScreen))
+
+		o.componentName = "not-exist"
+		Expect(o.run()).Should(MatchError(ContainSubstring("component not-exist is not found")))
+	})
+})
@@ -99,6 +99,15 @@ var clusterUpdateExample = templates.Examples(`
 
 	# update cluster backup pitr enabled
 	kbcli cluster update mycluster --pitr-enabled=true
+
+	# update the fields of the cluster spec by server-side apply
+	kbcli cluster update mycluster --set spec.componentSpecs[0].replicas=3 --set spec.terminationPolicy=WipeOut
+
+	# update the field of the component selected by the name, and show the result without applying it
+	kbcli cluster update mycluster --set spec.componentSpecs[name=mysql].replicas=3 --dry-run=server -o yaml
+
+	# take over the fields managed by others, such as the fields updated by the OpsRequests
+	kbcli cluster update mycluster --set spec.componentSpecs[0].replicas=3 --force-conflicts
 `)
 
 type updateOptions struct {
@@ -108,6 +117,13 @@ type updateOptions struct {
 
 	UpdatableFlags
 	*action.PatchOptions
+
+	gitOps gitOpsOptions
+
+	// sets are the fields to set by server-side apply, such as spec.componentSpecs[0].replicas=3
+	sets           []string
+	forceConflicts bool
+	setters        []fieldSetter
 }
 
 func NewUpdateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -126,11 +142,22 @@ func NewUpdateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, o.GVR),
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.complete(cmd, args))
+			if len(o.setters) > 0 {
+				util.CheckErr(o.runSetters(cmd))
+				return
+			}
+			util.CheckErr(o.checkGitOps(cmd))
+			if o.gitOps.patchWritten {
+				return
+			}
 			util.CheckErr(o.Run(cmd))
 		},
 	}
 	o.UpdatableFlags.addFlags(cmd)
 	o.PatchOptions.AddFlags(cmd)
+	o.gitOps.addFlags(cmd)
+	cmd.Flags().StringArrayVar(&o.sets, "set", nil, "Set the field of the cluster by the JSONPath-style path, the list element can be selected by the index or a field (e.g. --set spec.componentSpecs[0].replicas=3 or --set spec.componentSpecs[name=mysql].replicas=3)")
+	cmd.Flags().BoolVar(&o.forceConflicts, "force-conflicts", false, "Take over the fields managed by others when updating the cluster by --set")
 
 	return cmd
 }
@@ -162,9 +189,35 @@ func (o *updateOptions) complete(cmd *cobra.Command, args []string) error {
 	if o.dynamic, err = o.Factory.DynamicClient(); err != nil {
 		return err
 	}
+	if err = o.completeSetters(); err != nil {
+		return err
+	}
 	return o.buildPatch(flags)
 }
 
+// checkGitOps checks whether the cluster is managed by ArgoCD or Flux, the cluster spec patch
+// is written to the file if --gitops-patch-file is specified.
+func (o *updateOptions) checkGitOps(cmd *cobra.Command) error {
+	if dryRun, err := cmdutil.GetDryRunStrategy(cmd); err != nil || dryRun != cmdutil.DryRunNone {
+		return err
+	}
+	if len(o.Patch) == 0 || action.ExportEnabled() {
+		return nil
+	}
+	c, err := cluster.GetClusterByName(o.dynamic, o.Names[0], o.namespace)
+	if err != nil {
+		return err
+	}
+	return o.gitOps.check(c, func(_ *appsv1alpha1.Cluster) (map[string]interface{}, error) {
+		patch := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(o.Patch), &patch); err != nil {
+			return nil, err
+		}
+		spec, _ := patch["spec"].(map[string]interface{})
+		return spec, nil
+	}, o.Out)
+}
+
 func (o *updateOptions) buildPatch(flags []*pflag.Flag) error {
 	var err error
 	type buildFn func(obj map[string]interface{}, v pflag.Value, field string) error
@@ -248,6 +301,9 @@ func (o *updateOptions) buildPatch(flags []*pflag.Flag) error {
 
 	for _, flag := range flags {
 		if f, ok := flagFieldMapping[flag.Name]; ok {
+			if len(o.setters) > 0 {
+				return fmt.Errorf("--set can not be used with --%s", flag.Name)
+			}
 			if err = f.fn(f.obj, flag.Value, f.field); err != nil {
 				return err
 			}
@@ -0,0 +1,382 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// setFieldManager is the field manager of the server-side apply by --set, the fields set by
+// the previous --set are kept in the apply configuration, otherwise they will be removed.
+const setFieldManager = "kbcli-update"
+
+// pathElement is an element of the path of --set, it's a map key such as `replicas`, a list
+// index such as `[0]`, or a list selector matching the element by a field such as `[name=mysql]`.
+type pathElement struct {
+	key      string
+	isList   bool
+	index    int
+	selKey   string
+	selValue string
+}
+
+func (e pathElement) String() string {
+	switch {
+	case !e.isList:
+		return e.key
+	case e.selKey != "":
+		return fmt.Sprintf("[%s=%s]", e.selKey, e.selValue)
+	default:
+		return fmt.Sprintf("[%d]", e.index)
+	}
+}
+
+// fieldSetter sets the value of the field specified by the path
+type fieldSetter struct {
+	path     string
+	elements []pathElement
+	value    interface{}
+}
+
+// parseSetters parses the --set values formatted as path=value, such as
+// spec.componentSpecs[0].replicas=3 or spec.componentSpecs[name=mysql].replicas=3.
+func parseSetters(sets []string) ([]fieldSetter, error) {
+	var setters []fieldSetter
+	for _, s := range sets {
+		// the path may contain '=' in the list selector
+		depth, sep := 0, -1
+		for i, c := range s {
+			if c == '[' {
+				depth++
+			} else if c == ']' {
+				depth--
+			} else if c == '=' && depth == 0 {
+				sep = i
+				break
+			}
+		}
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid --set %q, should be formatted as path=value, such as spec.componentSpecs[0].replicas=3", s)
+		}
+		path := s[:sep]
+		elements, err := parseSetPath(path)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseSetValue(s[sep+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %v", path, err)
+		}
+		setters = append(setters, fieldSetter{path: path, elements: elements, value: value})
+	}
+	return setters, nil
+}
+
+// parseSetPath parses the JSONPath-style path, only the spec, labels and annotations of the cluster can be set
+func parseSetPath(path string) ([]pathElement, error) {
+	var (
+		elements []pathElement
+		rest     = strings.TrimPrefix(path, ".")
+	)
+	invalidErr := func(msg string) error {
+		return fmt.Errorf("invalid path %q: %s", path, msg)
+	}
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, invalidErr("missing ']'")
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case strings.HasPrefix(selector, `"`):
+				// the quoted key contains '.', such as labels["app.kubernetes.io/name"]
+				key, err := strconv.Unquote(selector)
+				if err != nil {
+					return nil, invalidErr(err.Error())
+				}
+				elements = append(elements, pathElement{key: key})
+			case strings.Contains(selector, "="):
+				kv := strings.SplitN(selector, "=", 2)
+				if kv[0] == "" {
+					return nil, invalidErr("the field of the list selector is empty")
+				}
+				elements = append(elements, pathElement{isList: true, selKey: kv[0], selValue: kv[1]})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, invalidErr(fmt.Sprintf("invalid list index %q", selector))
+				}
+				elements = append(elements, pathElement{isList: true, index: index})
+			}
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			elements = append(elements, pathElement{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	if len(elements) < 2 || elements[0].isList {
+		return nil, invalidErr("the path should start with spec, metadata.labels or metadata.annotations")
+	}
+	switch elements[0].key {
+	case "spec":
+	case "metadata":
+		if elements[1].isList || (elements[1].key != "labels" && elements[1].key != "annotations") || len(elements) != 3 {
+			return nil, invalidErr("only the labels and annotations of the metadata can be set")
+		}
+	default:
+		return nil, invalidErr("the path should start with spec, metadata.labels or metadata.annotations")
+	}
+	return elements, nil
+}
+
+// parseSetValue parses the value as YAML, the numbers are converted to int64 or float64
+func parseSetValue(val string) (interface{}, error) {
+	data, err := yaml.YAMLToJSON([]byte(val))
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err = decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertJSONNumber(value), nil
+}
+
+func convertJSONNumber(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case map[string]interface{}:
+		for k := range value {
+			value[k] = convertJSONNumber(value[k])
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = convertJSONNumber(value[i])
+		}
+	}
+	return v
+}
+
+// setNestedValue sets the value of the path in dst and returns the updated dst. The lists of the
+// custom resources are atomic in the server-side apply, so the whole list is copied from src when
+// the path goes through a list.
+func setNestedValue(dst, src interface{}, path string, elements []pathElement, value interface{}) (interface{}, error) {
+	if len(elements) == 0 {
+		return value, nil
+	}
+	e := elements[0]
+	if !e.isList {
+		if dst == nil {
+			dst = map[string]interface{}{}
+		}
+		m, ok := dst.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failed to set %s: the parent of %s is not a map", path, e)
+		}
+		var srcChild interface{}
+		if srcMap, ok := src.(map[string]interface{}); ok {
+			srcChild = srcMap[e.key]
+		}
+		child := m[e.key]
+		if child == nil && len(elements) > 1 && elements[1].isList {
+			if srcChild == nil {
+				return nil, fmt.Errorf("failed to set %s: the list %s is not found", path, e)
+			}
+			child = runtime.DeepCopyJSONValue(srcChild)
+		}
+		newChild, err := setNestedValue(child, srcChild, path, elements[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		m[e.key] = newChild
+		return m, nil
+	}
+
+	l, ok := dst.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to set %s: the parent of %s is not a list", path, e)
+	}
+	index := e.index
+	if e.selKey != "" {
+		index = -1
+		for i := range l {
+			if item, ok := l[i].(map[string]interface{}); ok && fmt.Sprint(item[e.selKey]) == e.selValue {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 || index >= len(l) {
+		return nil, fmt.Errorf("failed to set %s: the list element %s is not found", path, e)
+	}
+	item, err := setNestedValue(l[index], nil, path, elements[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	l[index] = item
+	return l, nil
+}
+
+// buildApplyConfig builds the apply configuration of the server-side apply, it contains the fields set by the
+// previous --set and the fields set this time.
+func buildApplyConfig(live *unstructured.Unstructured, setters []fieldSetter) (*unstructured.Unstructured, error) {
+	applyObj := map[string]interface{}{}
+	if err := managedfields.ExtractInto(live, typed.DeducedParseableType, setFieldManager, &applyObj, ""); err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: applyObj}
+	obj.SetAPIVersion(live.GetAPIVersion())
+	obj.SetKind(live.GetKind())
+	obj.SetName(live.GetName())
+	obj.SetNamespace(live.GetNamespace())
+	for _, s := range setters {
+		if _, err := setNestedValue(obj.Object, live.Object, s.path, s.elements, s.value); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// completeSetters parses the --set values, the other update flags can not be used with --set
+func (o *updateOptions) completeSetters() error {
+	if len(o.sets) == 0 {
+		return nil
+	}
+	if o.EditBeforeUpdate {
+		return fmt.Errorf("--set can not be used with --edit")
+	}
+	var err error
+	o.setters, err = parseSetters(o.sets)
+	return err
+}
+
+// runSetters updates the fields of the cluster by the server-side apply, the apply fails if the fields
+// are managed by other managers, such as the KubeBlocks controller or the OpsRequests, unless --force-conflicts.
+// checkSettersGitOps checks whether the cluster is managed by ArgoCD or Flux, the spec of the apply
+// configuration is written to the file if --gitops-patch-file is specified. The manifests can not
+// be exported by --set.
+func (o *updateOptions) checkSettersGitOps(live, applyObj *unstructured.Unstructured) error {
+	if err := action.CheckExportUnsupported("update --set"); err != nil {
+		return err
+	}
+	c := &appsv1alpha1.Cluster{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(live.Object, c); err != nil {
+		return err
+	}
+	return o.gitOps.check(c, func(_ *appsv1alpha1.Cluster) (map[string]interface{}, error) {
+		spec, _, err := unstructured.NestedMap(applyObj.Object, "spec")
+		return spec, err
+	}, o.Out)
+}
+
+func (o *updateOptions) runSetters(cmd *cobra.Command) error {
+	dryRun, err := cmdutil.GetDryRunStrategy(cmd)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	client := o.dynamic.Resource(types.ClusterGVR()).Namespace(o.namespace)
+	live, err := client.Get(ctx, o.Names[0], metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	var result *unstructured.Unstructured
+	if dryRun == cmdutil.DryRunClient {
+		// show the cluster with the fields set locally
+		result = live.DeepCopy()
+		for _, s := range o.setters {
+			if _, err = setNestedValue(result.Object, result.Object, s.path, s.elements, s.value); err != nil {
+				return err
+			}
+		}
+	} else {
+		applyObj, err := buildApplyConfig(live, o.setters)
+		if err != nil {
+			return err
+		}
+		// the same guards as the other updates are checked before applying
+		if dryRun == cmdutil.DryRunNone {
+			if err = o.checkSettersGitOps(live, applyObj); err != nil || o.gitOps.patchWritten {
+				return err
+			}
+		}
+		data, err := applyObj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		options := metav1.PatchOptions{FieldManager: setFieldManager, Force: &o.forceConflicts}
+		if dryRun == cmdutil.DryRunServer {
+			options.DryRun = []string{metav1.DryRunAll}
+		}
+		result, err = client.Patch(ctx, o.Names[0], k8stypes.ApplyPatchType, data, options)
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("%v\nthe fields are managed by others, use --force-conflicts to take over them", err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	o.PrintFlags.NamePrintFlags.Operation = "updated"
+	cmdutil.PrintFlagsWithDryRunStrategy(o.PrintFlags, dryRun)
+	p, err := o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	return p.PrintObj(result, o.Out)
+}
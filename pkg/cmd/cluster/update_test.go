@@ -20,12 +20,23 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/dynamic"
+	clienttesting "k8s.io/client-go/testing"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -108,6 +119,104 @@ var _ = Describe("cluster update", func() {
 			Expect(o.Patch).Should(ContainSubstring("k1"))
 		})
 	})
+
+	Context("set fields by server-side apply", func() {
+		It("parse the setters", func() {
+			setters, err := parseSetters([]string{
+				"spec.componentSpecs[name=mysql].replicas=3",
+				`metadata.labels["app.kubernetes.io/name"]=mysql`,
+				"spec.tolerations=[{key: engineType, operator: Exists}]",
+			})
+			Expect(err).Should(Succeed())
+			Expect(setters).Should(HaveLen(3))
+			Expect(setters[0].elements).Should(Equal([]pathElement{{key: "spec"}, {key: "componentSpecs"},
+				{isList: true, selKey: "name", selValue: "mysql"}, {key: "replicas"}}))
+			Expect(setters[0].value).Should(Equal(int64(3)))
+			Expect(setters[1].elements[2].key).Should(Equal("app.kubernetes.io/name"))
+			Expect(setters[2].value).Should(Equal([]interface{}{map[string]interface{}{"key": "engineType", "operator": "Exists"}}))
+
+			for _, set := range []string{"spec.terminationPolicy", "status.phase=Running", "metadata.name=c2",
+				"spec.componentSpecs[a].replicas=3", "spec.componentSpecs[0.replicas=3"} {
+				_, err = parseSetters([]string{set})
+				Expect(err).Should(HaveOccurred())
+			}
+		})
+
+		It("build the apply configuration and update the cluster", func() {
+			fakeCluster := testing.FakeCluster("c1", "default")
+			fakeCluster.ManagedFields = []metav1.ManagedFieldsEntry{{
+				Manager:   setFieldManager,
+				Operation: metav1.ManagedFieldsOperationApply,
+				FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:terminationPolicy":{}}}`)},
+			}}
+			tf.FakeDynamicClient = testing.FakeDynamicClient(fakeCluster)
+			live, err := tf.FakeDynamicClient.Resource(types.ClusterGVR()).Namespace("default").Get(context.TODO(), "c1", metav1.GetOptions{})
+			Expect(err).Should(Succeed())
+
+			By("the fields set previously are kept and the list is copied from the cluster")
+			setters, err := parseSetters([]string{"spec.componentSpecs[name=" + testing.ComponentName + "-1].replicas=5"})
+			Expect(err).Should(Succeed())
+			applyObj, err := buildApplyConfig(live, setters)
+			Expect(err).Should(Succeed())
+			Expect(applyObj.GetName()).Should(Equal("c1"))
+			Expect(applyObj.GetKind()).Should(Equal(types.KindCluster))
+			policy, _, _ := unstructured.NestedString(applyObj.Object, "spec", "terminationPolicy")
+			Expect(policy).Should(Equal(string(fakeCluster.Spec.TerminationPolicy)))
+			comps, _, _ := unstructured.NestedSlice(applyObj.Object, "spec", "componentSpecs")
+			Expect(comps).Should(HaveLen(2))
+			Expect(comps[0].(map[string]interface{})["replicas"]).Should(Equal(int64(fakeCluster.Spec.ComponentSpecs[0].Replicas)))
+			Expect(comps[1].(map[string]interface{})["replicas"]).Should(Equal(int64(5)))
+			setters, _ = parseSetters([]string{"spec.componentSpecs[3].replicas=5"})
+			_, err = buildApplyConfig(live, setters)
+			Expect(err).Should(MatchError(ContainSubstring("is not found")))
+
+			By("the conflicts are reported")
+			streams, _, out, _ := genericiooptions.NewTestIOStreams()
+			cmd := NewUpdateCmd(tf, streams)
+			o := &updateOptions{PatchOptions: action.NewPatchOptions(tf, streams, types.ClusterGVR())}
+			o.Names = []string{"c1"}
+			o.namespace = "default"
+			o.dynamic = tf.FakeDynamicClient
+			o.sets = []string{"spec.componentSpecs[0].replicas=3"}
+			Expect(o.completeSetters()).Should(Succeed())
+			var patch []byte
+			conflict := true
+			tf.FakeDynamicClient.PrependReactor("patch", types.ResourceClusters, func(action clienttesting.Action) (bool, runtime.Object, error) {
+				patchAction := action.(clienttesting.PatchAction)
+				Expect(patchAction.GetPatchType()).Should(Equal(k8stypes.ApplyPatchType))
+				patch = patchAction.GetPatch()
+				if conflict {
+					return true, nil, apierrors.NewConflict(types.ClusterGVR().GroupResource(), "c1", fmt.Errorf("conflict with \"kubeblocks\""))
+				}
+				return true, live, nil
+			})
+			Expect(o.runSetters(cmd)).Should(MatchError(ContainSubstring("--force-conflicts")))
+			Expect(string(patch)).Should(ContainSubstring(`"replicas":3`))
+
+			conflict = false
+			o.forceConflicts = true
+			Expect(o.runSetters(cmd)).Should(Succeed())
+			Expect(out.String()).Should(ContainSubstring("c1 updated"))
+
+			By("write the patch to the gitops patch file instead of applying")
+			patch = nil
+			o.gitOps.patchFile = filepath.Join(GinkgoT().TempDir(), "patch.yaml")
+			Expect(o.runSetters(cmd)).Should(Succeed())
+			Expect(patch).Should(BeNil())
+			data, err := os.ReadFile(o.gitOps.patchFile)
+			Expect(err).Should(Succeed())
+			Expect(string(data)).Should(ContainSubstring("replicas: 3"))
+			o.gitOps = gitOpsOptions{}
+
+			By("set the fields locally with client dry-run")
+			out.Reset()
+			patch = nil
+			Expect(cmd.Flags().Set("dry-run", "client")).Should(Succeed())
+			Expect(o.runSetters(cmd)).Should(Succeed())
+			Expect(patch).Should(BeNil())
+			Expect(out.String()).Should(ContainSubstring("updated (dry run)"))
+		})
+	})
 	Context("logs variables reconfiguring tests", func() {
 		var (
 			c        *appsv1alpha1.Cluster
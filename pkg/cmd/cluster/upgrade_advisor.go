@@ -0,0 +1,333 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	gv "github.com/hashicorp/go-version"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var upgradeAdvisorExample = templates.Examples(`
+	# List the upgrade paths of cluster mycluster to the installed ClusterVersions
+	kbcli cluster upgrade-advisor mycluster
+
+	# Create the Upgrade OpsRequest to the selected ClusterVersion
+	kbcli cluster upgrade-advisor mycluster --to ac-mysql-8.0.33
+
+	# Print the Upgrade OpsRequest to the selected ClusterVersion without creating it
+	kbcli cluster upgrade-advisor mycluster --to ac-mysql-8.0.33 --dry-run -o yaml`)
+
+const (
+	upgradeTypeMajor     = "Major"
+	upgradeTypeMinor     = "Minor"
+	upgradeTypePatch     = "Patch"
+	upgradeTypeSame      = "Same"
+	upgradeTypeDowngrade = "Downgrade"
+	upgradeTypeUnknown   = "Unknown"
+
+	// helmReleaseNameAnnotation is the annotation of the resources installed by helm, the addon
+	// resources are installed by the release named kb-addon-<addon>.
+	helmReleaseNameAnnotation = "meta.helm.sh/release-name"
+)
+
+var engineVersionRegex = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// upgradePath is an upgrade of the cluster from the running ClusterVersion to another one
+type upgradePath struct {
+	clusterVersion string
+	engineVersion  *gv.Version
+	addon          string
+	upgradeType    string
+	viable         bool
+	recommended    bool
+	note           string
+}
+
+type UpgradeAdvisorOptions struct {
+	*OperationsOptions
+	target string
+}
+
+func NewUpgradeAdvisorCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &UpgradeAdvisorOptions{OperationsOptions: newBaseOperationsOptions(f, streams, appsv1alpha1.UpgradeType, false)}
+	cmd := &cobra.Command{
+		Use:               "upgrade-advisor NAME",
+		Short:             "List the viable upgrade paths of the cluster engine and create the Upgrade OpsRequest to the selected one.",
+		Example:           upgradeAdvisorExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Args = args
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.run())
+		},
+	}
+	o.addCommonFlags(cmd, f)
+	cmd.Flags().StringVar(&o.target, "to", "", "The target ClusterVersion to upgrade to, the Upgrade OpsRequest is created if it's viable")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before upgrading the cluster")
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("to", util.ResourceNameCompletionFunc(f, types.ClusterVersionGVR())))
+	return cmd
+}
+
+func (o *UpgradeAdvisorOptions) run() error {
+	if o.Name == "" {
+		return makeMissingClusterNameErr()
+	}
+	c, err := cluster.GetClusterByName(o.Dynamic, o.Name, o.Namespace)
+	if err != nil {
+		return err
+	}
+	if c.Spec.ClusterVersionRef == "" {
+		return clierrors.NewValidation("cluster %s doesn't reference a ClusterVersion, its engine can't be upgraded by ClusterVersion", o.Name)
+	}
+	objs, err := o.Dynamic.Resource(types.ClusterVersionGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	allCVs, err := util.ConvertUnstructuredList[*appsv1alpha1.ClusterVersion](objs.Items, nil)
+	if err != nil {
+		return err
+	}
+	var (
+		current *appsv1alpha1.ClusterVersion
+		cvs     []*appsv1alpha1.ClusterVersion
+	)
+	for _, cv := range allCVs {
+		if cv.Spec.ClusterDefinitionRef != c.Spec.ClusterDefRef {
+			continue
+		}
+		if cv.Name == c.Spec.ClusterVersionRef {
+			current = cv
+		}
+		cvs = append(cvs, cv)
+	}
+	if current == nil {
+		return clierrors.NewNotFound(types.KindClusterVersion, c.Spec.ClusterVersionRef)
+	}
+	currentVersion := getEngineVersion(current)
+	paths := buildUpgradePaths(c, current, cvs)
+
+	fmt.Fprintf(o.Out, "Cluster %s is running ClusterVersion %s (engine version %s)\n\n", o.Name, current.Name, formatEngineVersion(currentVersion))
+	if len(paths) == 0 {
+		fmt.Fprintf(o.Out, "No other ClusterVersion of ClusterDefinition %s is installed, install or upgrade the addon to get the new versions\n", c.Spec.ClusterDefRef)
+		return nil
+	}
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("CLUSTER-VERSION", "ENGINE-VERSION", "ADDON", "UPGRADE", "VIABLE", "NOTE")
+	for _, p := range paths {
+		name := p.clusterVersion
+		if p.recommended {
+			name = printer.BoldGreen(name)
+		}
+		tbl.AddRow(name, formatEngineVersion(p.engineVersion), p.addon, p.upgradeType, p.viable, p.note)
+	}
+	tbl.Print()
+
+	if o.target == "" {
+		for _, p := range paths {
+			if p.viable {
+				fmt.Fprintf(o.Out, "\nCreate the Upgrade OpsRequest to a viable ClusterVersion by: kbcli cluster upgrade-advisor %s --to <cluster-version> --namespace=%s\n",
+					o.Name, o.Namespace)
+				break
+			}
+		}
+		return nil
+	}
+	for _, p := range paths {
+		if p.clusterVersion != o.target {
+			continue
+		}
+		if !p.viable {
+			return clierrors.NewValidation("upgrading cluster %s to ClusterVersion %s is not viable: %s", o.Name, o.target, p.note)
+		}
+		fmt.Fprintln(o.Out)
+		o.ClusterVersionRef = o.target
+		if err = o.Validate(); err != nil {
+			return err
+		}
+		return o.Run()
+	}
+	return clierrors.NewValidation("ClusterVersion %s is not an upgrade path of cluster %s", o.target, o.Name)
+}
+
+// buildUpgradePaths builds the upgrade paths from the running ClusterVersion to the others of the
+// same ClusterDefinition, sorted by the engine version. The latest viable path without the major
+// upgrade is recommended, or the latest viable one if all paths are major upgrades.
+func buildUpgradePaths(c *appsv1alpha1.Cluster, current *appsv1alpha1.ClusterVersion, cvs []*appsv1alpha1.ClusterVersion) []*upgradePath {
+	currentVersion := getEngineVersion(current)
+	var paths []*upgradePath
+	for _, cv := range cvs {
+		if cv.Name == current.Name {
+			continue
+		}
+		p := &upgradePath{
+			clusterVersion: cv.Name,
+			engineVersion:  getEngineVersion(cv),
+			addon:          printer.NoneString,
+			viable:         true,
+		}
+		if release := cv.Annotations[helmReleaseNameAnnotation]; release != "" {
+			p.addon = strings.TrimPrefix(release, "kb-addon-")
+		}
+		p.upgradeType = compareEngineVersions(currentVersion, p.engineVersion)
+		var notes []string
+		switch p.upgradeType {
+		case upgradeTypeDowngrade:
+			p.viable = false
+			notes = append(notes, "downgrading the engine is not supported")
+		case upgradeTypeMajor:
+			notes = append(notes, "verify it with a cluster restored from a backup first")
+		case upgradeTypeUnknown:
+			notes = append(notes, "unable to compare the engine versions")
+		}
+		if cv.Status.Phase != appsv1alpha1.AvailablePhase {
+			p.viable = false
+			notes = append(notes, fmt.Sprintf("ClusterVersion is %s", util.CheckEmpty(string(cv.Status.Phase))))
+		}
+		if missing := getMissingComponentDefs(c, current, cv); len(missing) > 0 {
+			p.viable = false
+			notes = append(notes, fmt.Sprintf("missing components %s", strings.Join(missing, ",")))
+		}
+		p.note = strings.Join(notes, "; ")
+		paths = append(paths, p)
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		vi, vj := paths[i].engineVersion, paths[j].engineVersion
+		if vi == nil || vj == nil {
+			return vi != nil
+		}
+		if !vi.Equal(vj) {
+			return vi.LessThan(vj)
+		}
+		return paths[i].clusterVersion < paths[j].clusterVersion
+	})
+
+	var recommended *upgradePath
+	for _, p := range paths {
+		if !p.viable || p.upgradeType == upgradeTypeSame || p.upgradeType == upgradeTypeUnknown {
+			continue
+		}
+		if recommended == nil || recommended.upgradeType == upgradeTypeMajor || p.upgradeType != upgradeTypeMajor {
+			recommended = p
+		}
+	}
+	if recommended != nil {
+		recommended.recommended = true
+		if recommended.note == "" {
+			recommended.note = "recommended"
+		} else {
+			recommended.note = "recommended; " + recommended.note
+		}
+	}
+	return paths
+}
+
+// getEngineVersion returns the engine version of the ClusterVersion parsed from its name, such as
+// 8.0.30 of ac-mysql-8.0.30, or from the image tag of its first container.
+func getEngineVersion(cv *appsv1alpha1.ClusterVersion) *gv.Version {
+	candidates := []string{cv.Name}
+	for _, comp := range cv.Spec.ComponentVersions {
+		for _, container := range comp.VersionsCtx.Containers {
+			if i := strings.LastIndex(container.Image, ":"); i > 0 && !strings.Contains(container.Image[i:], "/") {
+				candidates = append(candidates, container.Image[i+1:])
+			}
+		}
+	}
+	for _, s := range candidates {
+		matches := engineVersionRegex.FindAllString(s, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		if v, err := gv.NewVersion(matches[len(matches)-1]); err == nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// compareEngineVersions returns the upgrade type from the current engine version to the target
+func compareEngineVersions(current, target *gv.Version) string {
+	if current == nil || target == nil {
+		return upgradeTypeUnknown
+	}
+	switch {
+	case target.Equal(current):
+		return upgradeTypeSame
+	case target.LessThan(current):
+		return upgradeTypeDowngrade
+	}
+	cs, ts := current.Segments(), target.Segments()
+	switch {
+	case cs[0] != ts[0]:
+		return upgradeTypeMajor
+	case cs[1] != ts[1]:
+		return upgradeTypeMinor
+	default:
+		return upgradeTypePatch
+	}
+}
+
+// getMissingComponentDefs returns the component definitions of the cluster versioned by the
+// current ClusterVersion but not by the target one, the cluster can't be upgraded to the target.
+func getMissingComponentDefs(c *appsv1alpha1.Cluster, current, target *appsv1alpha1.ClusterVersion) []string {
+	versioned := func(cv *appsv1alpha1.ClusterVersion, compDef string) bool {
+		for _, comp := range cv.Spec.ComponentVersions {
+			if comp.ComponentDefRef == compDef {
+				return true
+			}
+		}
+		return false
+	}
+	var missing []string
+	for _, comp := range c.Spec.ComponentSpecs {
+		if comp.ComponentDefRef == "" || !versioned(current, comp.ComponentDefRef) || versioned(target, comp.ComponentDefRef) {
+			continue
+		}
+		if !slices.Contains(missing, comp.ComponentDefRef) {
+			missing = append(missing, comp.ComponentDefRef)
+		}
+	}
+	return missing
+}
+
+func formatEngineVersion(v *gv.Version) string {
+	if v == nil {
+		return printer.NoneString
+	}
+	return v.Original()
+}
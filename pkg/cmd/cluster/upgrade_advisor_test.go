@@ -0,0 +1,149 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	clientfake "k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster upgrade-advisor", func() {
+	var (
+		streams genericiooptions.IOStreams
+		tf      *cmdtesting.TestFactory
+		c       *appsv1alpha1.Cluster
+	)
+
+	newClusterVersion := func(name string, phase appsv1alpha1.Phase, compDefs ...string) *appsv1alpha1.ClusterVersion {
+		cv := testing.FakeClusterVersion()
+		cv.Name = name
+		cv.Annotations = map[string]string{helmReleaseNameAnnotation: "kb-addon-mysql"}
+		cv.Status.Phase = phase
+		for _, compDef := range compDefs {
+			cv.Spec.ComponentVersions = append(cv.Spec.ComponentVersions, appsv1alpha1.ClusterComponentVersion{ComponentDefRef: compDef})
+		}
+		return cv
+	}
+
+	BeforeEach(func() {
+		streams, _, _, _ = genericiooptions.NewTestIOStreams()
+		tf = cmdtesting.NewTestFactory().WithNamespace(testing.Namespace)
+		tf.Client = &clientfake.RESTClient{}
+		c = testing.FakeCluster(testing.ClusterName, testing.Namespace)
+		c.Spec.ClusterVersionRef = "ac-mysql-8.0.30"
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("build the upgrade paths", func() {
+		compDef := c.Spec.ComponentSpecs[0].ComponentDefRef
+		current := newClusterVersion("ac-mysql-8.0.30", appsv1alpha1.AvailablePhase, compDef)
+		custom := newClusterVersion("custom", appsv1alpha1.AvailablePhase, compDef)
+		cvs := []*appsv1alpha1.ClusterVersion{
+			newClusterVersion("ac-mysql-9.0.1", appsv1alpha1.AvailablePhase, compDef),
+			newClusterVersion("ac-mysql-8.0.35", appsv1alpha1.UnavailablePhase, compDef),
+			newClusterVersion("ac-mysql-8.1.0", appsv1alpha1.AvailablePhase, compDef),
+			newClusterVersion("ac-mysql-8.0.33", appsv1alpha1.AvailablePhase),
+			newClusterVersion("ac-mysql-5.7.44", appsv1alpha1.AvailablePhase, compDef),
+			custom,
+			current,
+		}
+		paths := buildUpgradePaths(c, current, cvs)
+		Expect(paths).Should(HaveLen(6))
+
+		results := map[string]*upgradePath{}
+		var names []string
+		for _, p := range paths {
+			names = append(names, p.clusterVersion)
+			results[p.clusterVersion] = p
+		}
+		Expect(names).Should(Equal([]string{"ac-mysql-5.7.44", "ac-mysql-8.0.33", "ac-mysql-8.0.35", "ac-mysql-8.1.0", "ac-mysql-9.0.1", "custom"}))
+		Expect(results["ac-mysql-5.7.44"].upgradeType).Should(Equal(upgradeTypeDowngrade))
+		Expect(results["ac-mysql-5.7.44"].viable).Should(BeFalse())
+		Expect(results["ac-mysql-8.0.33"].viable).Should(BeFalse())
+		Expect(results["ac-mysql-8.0.33"].note).Should(ContainSubstring("missing components " + compDef))
+		Expect(results["ac-mysql-8.0.35"].viable).Should(BeFalse())
+		Expect(results["ac-mysql-8.0.35"].note).Should(ContainSubstring("ClusterVersion is Unavailable"))
+		Expect(results["ac-mysql-8.1.0"].upgradeType).Should(Equal(upgradeTypeMinor))
+		Expect(results["ac-mysql-8.1.0"].recommended).Should(BeTrue())
+		Expect(results["ac-mysql-8.1.0"].addon).Should(Equal("mysql"))
+		Expect(results["ac-mysql-9.0.1"].upgradeType).Should(Equal(upgradeTypeMajor))
+		Expect(results["ac-mysql-9.0.1"].recommended).Should(BeFalse())
+		Expect(results["custom"].upgradeType).Should(Equal(upgradeTypeUnknown))
+
+		By("the major upgrade is recommended if it's the only viable one")
+		paths = buildUpgradePaths(c, current, []*appsv1alpha1.ClusterVersion{current, cvs[0]})
+		Expect(paths[0].recommended).Should(BeTrue())
+		Expect(paths[0].note).Should(HavePrefix("recommended"))
+	})
+
+	It("parse the engine version", func() {
+		cv := newClusterVersion("custom", appsv1alpha1.AvailablePhase, testing.ComponentDefName)
+		Expect(getEngineVersion(cv)).Should(BeNil())
+		cv.Spec.ComponentVersions[0].VersionsCtx.Containers = append(cv.Spec.ComponentVersions[0].VersionsCtx.Containers,
+			corev1.Container{Image: "registry:5000/apecloud/mysql:8.0.33-r1"})
+		Expect(formatEngineVersion(getEngineVersion(cv))).Should(Equal("8.0.33"))
+		Expect(compareEngineVersions(getEngineVersion(cv), nil)).Should(Equal(upgradeTypeUnknown))
+	})
+
+	It("create the Upgrade OpsRequest to the target", func() {
+		compDef := c.Spec.ComponentSpecs[0].ComponentDefRef
+		tf.FakeDynamicClient = testing.FakeDynamicClient(c,
+			newClusterVersion("ac-mysql-8.0.30", appsv1alpha1.AvailablePhase, compDef),
+			newClusterVersion("ac-mysql-8.0.33", appsv1alpha1.AvailablePhase, compDef),
+			newClusterVersion("ac-mysql-5.7.44", appsv1alpha1.AvailablePhase, compDef))
+		o := &UpgradeAdvisorOptions{OperationsOptions: newBaseOperationsOptions(tf, streams, appsv1alpha1.UpgradeType, false)}
+		o.Args = []string{c.Name}
+		Expect(o.Complete()).Should(Succeed())
+		o.autoApprove = true
+		o.Quiet = true
+		Expect(o.run()).Should(Succeed())
+
+		o.target = "ac-mysql-5.7.44"
+		Expect(o.run()).Should(MatchError(ContainSubstring("not viable: downgrading the engine is not supported")))
+		o.target = "not-exist"
+		Expect(o.run()).Should(MatchError(ContainSubstring("is not an upgrade path")))
+
+		o.target = "ac-mysql-8.0.33"
+		Expect(o.run()).Should(Succeed())
+		objs, err := tf.FakeDynamicClient.Resource(types.OpsGVR()).Namespace(testing.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(objs.Items).Should(HaveLen(1))
+		ops := &appsv1alpha1.OpsRequest{}
+		Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, ops, types.OpsGVR(), testing.Namespace, objs.Items[0].GetName())).Should(Succeed())
+		Expect(ops.Spec.Upgrade.ClusterVersionRef).Should(Equal("ac-mysql-8.0.33"))
+	})
+})
@@ -0,0 +1,280 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// bandwidthEnvName is the env of the backup and restore workloads to throttle the transfer rate
+// in bytes per second, the backup tools of the ActionSet can read it to limit the bandwidth.
+const bandwidthEnvName = "DP_BANDWIDTH"
+
+// workloadLimits are the resource limits and the bandwidth of the backup or restore workloads, so
+// that a large backup or restore doesn't starve the production workloads.
+type workloadLimits struct {
+	cpuLimit    string
+	memoryLimit string
+	bandwidth   string
+}
+
+func (l *workloadLimits) addFlags(cmd *cobra.Command, workload string) {
+	cmd.Flags().StringVar(&l.cpuLimit, "cpu-limit", "", fmt.Sprintf("The CPU limit of the %s workloads, such as 500m", workload))
+	cmd.Flags().StringVar(&l.memoryLimit, "memory-limit", "", fmt.Sprintf("The memory limit of the %s workloads, such as 1Gi", workload))
+	cmd.Flags().StringVar(&l.bandwidth, "bandwidth", "", fmt.Sprintf("The bandwidth in bytes per second of the %s workloads, such as 50Mi", workload))
+}
+
+func (l *workloadLimits) enabled() bool {
+	return l.cpuLimit != "" || l.memoryLimit != "" || l.bandwidth != ""
+}
+
+func (l *workloadLimits) validate() error {
+	for flag, value := range map[string]string{"cpu-limit": l.cpuLimit, "memory-limit": l.memoryLimit, "bandwidth": l.bandwidth} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return clierrors.NewValidation("invalid --%s %s: %v", flag, value, err)
+		}
+		if quantity.Sign() <= 0 {
+			return clierrors.NewValidation("--%s should be greater than 0", flag)
+		}
+	}
+	return nil
+}
+
+// limits returns the resource limits of the workloads
+func (l *workloadLimits) limits() corev1.ResourceList {
+	limits := corev1.ResourceList{}
+	if l.cpuLimit != "" {
+		limits[corev1.ResourceCPU] = resource.MustParse(l.cpuLimit)
+	}
+	if l.memoryLimit != "" {
+		limits[corev1.ResourceMemory] = resource.MustParse(l.memoryLimit)
+	}
+	return limits
+}
+
+// env returns the throttling env of the workloads
+func (l *workloadLimits) env() []corev1.EnvVar {
+	if l.bandwidth == "" {
+		return nil
+	}
+	bandwidth := resource.MustParse(l.bandwidth)
+	return []corev1.EnvVar{{Name: bandwidthEnvName, Value: fmt.Sprintf("%d", bandwidth.Value())}}
+}
+
+// applyResources sets the limits to the resources, the requests greater than the limits are
+// lowered to the limits, otherwise the workloads are rejected by the API server.
+func (l *workloadLimits) applyResources(resources *corev1.ResourceRequirements) {
+	limits := l.limits()
+	if len(limits) == 0 {
+		return
+	}
+	if resources.Limits == nil {
+		resources.Limits = corev1.ResourceList{}
+	}
+	for name, limit := range limits {
+		resources.Limits[name] = limit
+		if request, ok := resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+			resources.Requests[name] = limit
+		}
+	}
+}
+
+// throttledBackupPolicyName returns the name of the backup policy derived from the policy with the
+// limits, the backups with the same limits share the derived policy.
+func (l *workloadLimits) throttledBackupPolicyName(policyName string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(l.cpuLimit + "/" + l.memoryLimit + "/" + l.bandwidth))
+	return fmt.Sprintf("%s-throttled-%x", policyName, h.Sum32())
+}
+
+// buildThrottledBackupPolicy builds the backup policy derived from the policy, whose backup method
+// runs with the limits. It is owned by the original policy and never the default backup policy.
+func (l *workloadLimits) buildThrottledBackupPolicy(policy *dpv1alpha1.BackupPolicy, methodName string) (*dpv1alpha1.BackupPolicy, error) {
+	throttled := &dpv1alpha1.BackupPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: types.BackupPolicyGVR().GroupVersion().String(), Kind: types.KindBackupPolicy},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      l.throttledBackupPolicyName(policy.Name),
+			Namespace: policy.Namespace,
+			Labels:    map[string]string{constant.AppManagedByLabelKey: "kbcli"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: types.BackupPolicyGVR().GroupVersion().String(),
+				Kind:       types.KindBackupPolicy,
+				Name:       policy.Name,
+				UID:        policy.UID,
+			}},
+		},
+		Spec: *policy.Spec.DeepCopy(),
+	}
+	for k, v := range policy.Labels {
+		if _, ok := throttled.Labels[k]; !ok {
+			throttled.Labels[k] = v
+		}
+	}
+	for i := range throttled.Spec.BackupMethods {
+		m := &throttled.Spec.BackupMethods[i]
+		if m.Name != methodName {
+			continue
+		}
+		if m.RuntimeSettings == nil {
+			m.RuntimeSettings = &dpv1alpha1.RuntimeSettings{}
+		}
+		l.applyResources(&m.RuntimeSettings.Resources)
+		m.Env = dputils.MergeEnv(m.Env, l.env())
+		return throttled, nil
+	}
+	return nil, clierrors.NewValidation("backup method %s is not found in backup policy %s", methodName, policy.Name)
+}
+
+// applyThrottledBackupPolicy creates or updates the backup policy derived from the backup policy of
+// the backup with the limits, and uses it to create the backup.
+func (o *CreateBackupOptions) applyThrottledBackupPolicy() error {
+	dryRun, err := o.GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	policy := &dpv1alpha1.BackupPolicy{}
+	if err = cluster.GetK8SClientObject(o.Dynamic, policy, types.BackupPolicyGVR(), o.Namespace, o.BackupSpec.BackupPolicyName); err != nil {
+		return err
+	}
+	throttled, err := o.Limits.buildThrottledBackupPolicy(policy, o.BackupSpec.BackupMethod)
+	if err != nil {
+		return err
+	}
+	o.BackupSpec.BackupPolicyName = throttled.Name
+	if dryRun == action.DryRunClient {
+		return nil
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(throttled)
+	if err != nil {
+		return err
+	}
+	var dryRunOpts []string
+	if dryRun == action.DryRunServer {
+		dryRunOpts = []string{metav1.DryRunAll}
+	}
+	client := o.Dynamic.Resource(types.BackupPolicyGVR()).Namespace(o.Namespace)
+	existing, err := client.Get(context.TODO(), throttled.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(context.TODO(), &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{DryRun: dryRunOpts})
+	case err == nil:
+		// keep the derived policy up to date with the original policy
+		existing.Object["spec"] = obj["spec"]
+		_, err = client.Update(context.TODO(), existing, metav1.UpdateOptions{DryRun: dryRunOpts})
+	}
+	return err
+}
+
+// applyRestoreLimits sets the limits to the Restores of the restored cluster which are created by
+// the cluster controller and not finished yet. It returns the names of the Restores patched.
+func applyRestoreLimits(dynamic dynamic.Interface, namespace, clusterName string, limits *workloadLimits) ([]string, error) {
+	objs, err := dynamic.Resource(types.RestoreGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: util.BuildLabelSelectorByNames("", []string{clusterName}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	restores, err := util.ConvertUnstructuredList[*dpv1alpha1.Restore](objs.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	var patched []string
+	for _, restore := range restores {
+		if restore.Status.Phase == dpv1alpha1.RestorePhaseCompleted || restore.Status.Phase == dpv1alpha1.RestorePhaseFailed {
+			continue
+		}
+		resources := *restore.Spec.ContainerResources.DeepCopy()
+		limits.applyResources(&resources)
+		env := dputils.MergeEnv(restore.Spec.Env, limits.env())
+		if equalResources(resources, restore.Spec.ContainerResources) && equalEnv(env, restore.Spec.Env) {
+			continue
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{"containerResources": resources, "env": env},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = dynamic.Resource(types.RestoreGVR()).Namespace(namespace).Patch(context.TODO(), restore.Name,
+			k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return nil, err
+		}
+		patched = append(patched, restore.Name)
+	}
+	return patched, nil
+}
+
+func equalResources(a, b corev1.ResourceRequirements) bool {
+	equal := func(x, y corev1.ResourceList) bool {
+		if len(x) != len(y) {
+			return false
+		}
+		for name, q := range x {
+			if v, ok := y[name]; !ok || v.Cmp(q) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return equal(a.Limits, b.Limits) && equal(a.Requests, b.Requests)
+}
+
+func equalEnv(a, b []corev1.EnvVar) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	values := map[string]string{}
+	for _, e := range b {
+		values[e.Name] = e.Value
+	}
+	for _, e := range a {
+		if v, ok := values[e.Name]; !ok || v != e.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,8 +69,8 @@ func NewDescribeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 		Aliases:           []string{"desc"},
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterDefGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.run())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
 		},
 	}
 	return cmd
@@ -137,6 +138,8 @@ func (o *describeOptions) describeClusterDef(name string) error {
 
 	showClusterDef(&clusterDef, o.Out)
 
+	showComponentDefs(&clusterDef, getDefaultBackupPolicyTemplate(backupPolicyTemplates), o.Out)
+
 	showBackupConfig(backupPolicyTemplates, o.Out)
 
 	return nil
@@ -149,23 +152,106 @@ func showClusterDef(cd *v1alpha1.ClusterDefinition, out io.Writer) {
 	fmt.Fprintf(out, "Name: %s\t Type: %s\n\n", cd.Name, cd.Spec.Type)
 }
 
-func showBackupConfig(backupPolicyTemplates []*v1alpha1.BackupPolicyTemplate, out io.Writer) {
-	if len(backupPolicyTemplates) == 0 {
-		return
+// showComponentDefs shows the services, config templates, volumes, system accounts and backup methods
+// of each component definition.
+func showComponentDefs(cd *v1alpha1.ClusterDefinition, bpt *v1alpha1.BackupPolicyTemplate, out io.Writer) {
+	for _, comp := range cd.Spec.ComponentDefs {
+		fmt.Fprintf(out, "Component: %s\t WorkloadType: %s\t CharacterType: %s\n", comp.Name, comp.WorkloadType, comp.CharacterType)
+		if comp.Description != "" {
+			fmt.Fprintf(out, "Description: %s\n", comp.Description)
+		}
+
+		if comp.Service != nil && len(comp.Service.Ports) > 0 {
+			showSection(out, "Services", func(tbl *printer.TablePrinter) error {
+				for _, port := range comp.Service.Ports {
+					tbl.AddRow(util.CheckEmpty(port.Name), port.Port, util.CheckEmpty(string(port.Protocol)))
+				}
+				return nil
+			}, "PORT-NAME", "PORT", "PROTOCOL")
+		}
+
+		if len(comp.ConfigSpecs) > 0 {
+			showSection(out, "Config Templates", func(tbl *printer.TablePrinter) error {
+				for _, spec := range comp.ConfigSpecs {
+					tbl.AddRow(spec.Name, spec.TemplateRef, util.CheckEmpty(spec.ConfigConstraintRef), spec.VolumeName, util.CheckEmpty(strings.Join(spec.Keys, ",")))
+				}
+				return nil
+			}, "NAME", "TEMPLATE", "CONSTRAINT", "VOLUME", "KEYS")
+		}
+
+		if len(comp.VolumeTypes) > 0 {
+			showSection(out, "Volume Claims", func(tbl *printer.TablePrinter) error {
+				for _, vt := range comp.VolumeTypes {
+					tbl.AddRow(vt.Name, util.CheckEmpty(string(vt.Type)))
+				}
+				return nil
+			}, "NAME", "TYPE")
+		}
+
+		if comp.SystemAccounts != nil && len(comp.SystemAccounts.Accounts) > 0 {
+			showSection(out, "System Accounts", func(tbl *printer.TablePrinter) error {
+				for _, account := range comp.SystemAccounts.Accounts {
+					tbl.AddRow(account.Name, account.ProvisionPolicy.Type, account.ProvisionPolicy.Scope)
+				}
+				return nil
+			}, "NAME", "PROVISION-TYPE", "SCOPE")
+		}
+
+		if methods := getComponentBackupMethods(bpt, comp.Name); len(methods) > 0 {
+			showSection(out, "Backup Methods", func(tbl *printer.TablePrinter) error {
+				for _, method := range methods {
+					tbl.AddRow(method.Name, method.ActionSetName, boolptr.IsSetToTrue(method.SnapshotVolumes))
+				}
+				return nil
+			}, "NAME", "ACTION-SET", "SNAPSHOT-VOLUME")
+		}
+		fmt.Fprintln(out)
 	}
-	fmt.Fprintf(out, "Backup Config:\n")
-	tbl := printer.NewTablePrinter(out)
-	defaultBackupPolicyTemplate := &v1alpha1.BackupPolicyTemplate{}
-	// if there is only one backup policy template, it will be the default backup policy template
+}
+
+// showSection prints the title and the table rendered by the row feeder.
+func showSection(out io.Writer, title string, rowFeeder func(tbl *printer.TablePrinter) error, header ...interface{}) {
+	fmt.Fprintf(out, "\n%s:\n", title)
+	_ = printer.PrintTable(out, nil, rowFeeder, header...)
+}
+
+// getComponentBackupMethods returns the backup methods of the component definition declared in the backup policy template.
+func getComponentBackupMethods(bpt *v1alpha1.BackupPolicyTemplate, compDefName string) []v1alpha1.BackupMethod {
+	if bpt == nil {
+		return nil
+	}
+	for _, policy := range bpt.Spec.BackupPolicies {
+		if policy.ComponentDefRef == compDefName {
+			return policy.BackupMethods
+		}
+	}
+	return nil
+}
+
+// getDefaultBackupPolicyTemplate returns the backup policy template annotated as default, if there is only
+// one backup policy template, it will be the default backup policy template.
+func getDefaultBackupPolicyTemplate(backupPolicyTemplates []*v1alpha1.BackupPolicyTemplate) *v1alpha1.BackupPolicyTemplate {
 	if len(backupPolicyTemplates) == 1 {
-		defaultBackupPolicyTemplate = backupPolicyTemplates[0]
+		return backupPolicyTemplates[0]
 	}
 	for _, item := range backupPolicyTemplates {
 		if item.Annotations[dptypes.DefaultBackupPolicyTemplateAnnotationKey] == "true" {
-			defaultBackupPolicyTemplate = item
-			break
+			return item
 		}
 	}
+	return nil
+}
+
+func showBackupConfig(backupPolicyTemplates []*v1alpha1.BackupPolicyTemplate, out io.Writer) {
+	if len(backupPolicyTemplates) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Backup Config:\n")
+	tbl := printer.NewTablePrinter(out)
+	defaultBackupPolicyTemplate := getDefaultBackupPolicyTemplate(backupPolicyTemplates)
+	if defaultBackupPolicyTemplate == nil {
+		defaultBackupPolicyTemplate = &v1alpha1.BackupPolicyTemplate{}
+	}
 	tbl.SetHeader("BACKUP-METHOD", "ACTION-SET", "SNAPSHOT-VOLUME")
 	for _, policy := range defaultBackupPolicyTemplate.Spec.BackupPolicies {
 		for _, method := range policy.BackupMethods {
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clusterdefinition
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("clusterdefinition describe", func() {
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		clusterDef := testing.FakeClusterDef()
+		clusterDef.Spec.ComponentDefs[0].Service = &appsv1alpha1.ServiceSpec{
+			Ports: []appsv1alpha1.ServicePort{{Name: "mysql", Port: 3306}},
+		}
+		clusterDef.Spec.ComponentDefs[0].VolumeTypes = []appsv1alpha1.VolumeTypeSpec{{Name: "data", Type: appsv1alpha1.VolumeTypeData}}
+		clusterDef.Spec.ComponentDefs[0].SystemAccounts.Accounts = []appsv1alpha1.SystemAccountConfig{
+			{Name: appsv1alpha1.AdminAccount, ProvisionPolicy: appsv1alpha1.ProvisionPolicy{Type: appsv1alpha1.CreateByStmt, Scope: appsv1alpha1.AnyPods}},
+		}
+		bpt := testing.FakeBackupPolicyTemplate("fake-backup-policy-template", testing.ClusterDefName)
+		bpt.Spec.BackupPolicies = []appsv1alpha1.BackupPolicy{
+			{
+				ComponentDefRef: testing.ComponentDefName,
+				BackupMethods: []appsv1alpha1.BackupMethod{
+					{BackupMethod: dpv1alpha1.BackupMethod{Name: "xtrabackup", ActionSetName: "xtrabackup-for-mysql"}},
+				},
+			},
+		}
+		tf = testing.NewTestFactory(testing.Namespace)
+		tf.FakeDynamicClient = testing.FakeDynamicClient(clusterDef, bpt)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("describe requires a clusterdefinition name", func() {
+		o := &describeOptions{factory: tf, IOStreams: streams}
+		Expect(o.complete(nil)).Should(HaveOccurred())
+	})
+
+	It("describe the components of the clusterdefinition", func() {
+		Expect(NewDescribeCmd(tf, streams)).ShouldNot(BeNil())
+		o := &describeOptions{
+			factory:   tf,
+			dynamic:   tf.FakeDynamicClient,
+			names:     []string{testing.ClusterDefName},
+			IOStreams: streams,
+		}
+		Expect(o.run()).Should(Succeed())
+		for _, s := range []string{
+			"Component: " + testing.ComponentDefName,
+			"Component: " + testing.ExtraComponentDefName,
+			"Services:", "3306",
+			"Config Templates:", "mysql8.0-config-template", "mysql8.0-config-constraints",
+			"Volume Claims:", "data",
+			"System Accounts:", string(appsv1alpha1.AdminAccount), string(appsv1alpha1.CreateByStmt),
+			"Backup Methods:", "xtrabackup-for-mysql",
+		} {
+			Expect(out.String()).Should(ContainSubstring(s))
+		}
+	})
+})
@@ -54,6 +54,7 @@ func NewClusterVersionCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 	}
 
 	cmd.AddCommand(NewListCmd(f, streams))
+	cmd.AddCommand(newDescribeCmd(f, streams))
 	cmd.AddCommand(newSetDefaultCMD(f, streams))
 	cmd.AddCommand(newUnSetDefaultCMD(f, streams))
 	return cmd
@@ -83,7 +84,7 @@ func NewListCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 }
 
 func run(o *ListClusterVersionOptions) error {
-	if !o.Format.IsHumanReadable() {
+	if !o.Format.IsHumanReadable() || o.Watch {
 		_, err := o.Run()
 		return err
 	}
@@ -99,6 +100,9 @@ func run(o *ListClusterVersionOptions) error {
 	p := printer.NewTablePrinter(o.Out)
 	p.SetHeader("NAME", "CLUSTER-DEFINITION", "STATUS", "IS-DEFAULT", "CREATED-TIME")
 	p.SortBy(2)
+	if err = p.ApplyOptions(&o.TableOptions); err != nil {
+		return err
+	}
 	for _, info := range infos {
 		var cv v1alpha1.ClusterVersion
 		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(info.Object.(*unstructured.Unstructured).Object, &cv); err != nil {
@@ -0,0 +1,238 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clusterversion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var describeExample = templates.Examples(`
+		# describe a specified clusterversion
+		kbcli clusterversion describe ac-mysql-8.0.30
+
+		# list the images referenced by the clusterversion with the digest and build time from the registry
+		kbcli clusterversion describe ac-mysql-8.0.30 --images
+
+		# flag the images built more than 90 days ago
+		kbcli clusterversion describe ac-mysql-8.0.30 --images --max-age 90`)
+
+// imageInfo is the image referenced by a clusterversion
+type imageInfo struct {
+	componentDef string
+	container    string
+	image        string
+	tag          string
+	digest       string
+	created      time.Time
+	err          error
+}
+
+// getImageMetaFunc gets the digest and the created time of the image from the registry,
+// it can be replaced in tests.
+var getImageMetaFunc = getImageMeta
+
+type describeOptions struct {
+	factory cmdutil.Factory
+	dynamic dynamic.Interface
+	names   []string
+
+	// images specifies whether to query the registry for the image details
+	images bool
+	// maxAge is the max age of the images in days, the older images are flagged
+	maxAge int
+	// now is the time to calculate the image age
+	now time.Time
+
+	genericiooptions.IOStreams
+}
+
+func newDescribeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &describeOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:               "describe NAME",
+		Short:             "Describe ClusterVersion.",
+		Example:           describeExample,
+		Aliases:           []string{"desc"},
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, clusterVersionGVR),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.images, "images", false, "Query the registry for the digest and build time of every image referenced by the clusterversion")
+	cmd.Flags().IntVar(&o.maxAge, "max-age", 180, "Flag the images built more than the given days ago when --images is specified, 0 disables the check")
+	return cmd
+}
+
+func (o *describeOptions) complete(args []string) error {
+	var err error
+	if len(args) == 0 {
+		return fmt.Errorf("clusterversion name should be specified")
+	}
+	if o.maxAge < 0 {
+		return fmt.Errorf("--max-age can not be negative")
+	}
+	o.names = args
+	o.now = time.Now()
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *describeOptions) run() error {
+	for _, n := range o.names {
+		if err := o.describeClusterVersion(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *describeOptions) describeClusterVersion(name string) error {
+	obj, err := o.dynamic.Resource(clusterVersionGVR).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	cv := &v1alpha1.ClusterVersion{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cv); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Name: %s\t ClusterDefinition: %s\t Status: %s\t IsDefault: %s\n\n",
+		cv.Name, cv.Spec.ClusterDefinitionRef, util.CheckEmpty(string(cv.Status.Phase)), isDefault(cv))
+	images := getClusterVersionImages(cv)
+	if !o.images {
+		return printer.PrintTable(o.Out, nil, func(tbl *printer.TablePrinter) error {
+			for _, img := range images {
+				tbl.AddRow(img.componentDef, img.container, img.image)
+			}
+			return nil
+		}, "COMPONENT-DEF", "CONTAINER", "IMAGE")
+	}
+
+	var outdated int
+	for i := range images {
+		img := &images[i]
+		img.tag, img.digest, img.created, img.err = getImageMetaFunc(img.image)
+	}
+	if err = printer.PrintTable(o.Out, nil, func(tbl *printer.TablePrinter) error {
+		for _, img := range images {
+			if img.err != nil {
+				tbl.AddRow(img.componentDef, img.container, img.image, util.CheckEmpty(img.tag), printer.NoneString,
+					printer.NoneString, printer.BoldRed(fmt.Sprintf("unknown: %v", img.err)))
+				continue
+			}
+			status := "ok"
+			if o.isOutdated(img.created) {
+				status = printer.BoldYellow("outdated")
+				outdated++
+			}
+			tbl.AddRow(img.componentDef, img.container, img.image, util.CheckEmpty(img.tag), util.CheckEmpty(img.digest),
+				duration.HumanDuration(o.now.Sub(img.created)), status)
+		}
+		return nil
+	}, "COMPONENT-DEF", "CONTAINER", "IMAGE", "TAG", "DIGEST", "AGE", "STATUS"); err != nil {
+		return err
+	}
+	if outdated > 0 {
+		fmt.Fprintf(o.Out, "\n%d image(s) were built more than %d days ago, please review them before enabling the clusterversion.\n", outdated, o.maxAge)
+	}
+	return nil
+}
+
+func (o *describeOptions) isOutdated(created time.Time) bool {
+	return o.maxAge > 0 && !created.IsZero() && o.now.Sub(created) > time.Duration(o.maxAge)*24*time.Hour
+}
+
+// getClusterVersionImages returns all the images referenced by the clusterversion in the order of the component versions,
+// including the images of containers, init containers, system account and switchover executors.
+func getClusterVersionImages(cv *v1alpha1.ClusterVersion) []imageInfo {
+	var images []imageInfo
+	add := func(compDef, container, image string) {
+		if image == "" {
+			return
+		}
+		images = append(images, imageInfo{componentDef: compDef, container: container, image: image})
+	}
+	for _, comp := range cv.Spec.ComponentVersions {
+		for _, c := range comp.VersionsCtx.InitContainers {
+			add(comp.ComponentDefRef, c.Name, c.Image)
+		}
+		for _, c := range comp.VersionsCtx.Containers {
+			add(comp.ComponentDefRef, c.Name, c.Image)
+		}
+		if comp.SystemAccountSpec != nil && comp.SystemAccountSpec.CmdExecutorConfig != nil {
+			add(comp.ComponentDefRef, "<system-account>", comp.SystemAccountSpec.CmdExecutorConfig.Image)
+		}
+		if comp.SwitchoverSpec != nil && comp.SwitchoverSpec.CmdExecutorConfig != nil {
+			add(comp.ComponentDefRef, "<switchover>", comp.SwitchoverSpec.CmdExecutorConfig.Image)
+		}
+	}
+	return images
+}
+
+// getImageMeta gets the tag, digest and created time of the image from the registry with the
+// credentials of the local docker config.
+func getImageMeta(image string) (string, string, time.Time, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	var tag string
+	if t, ok := ref.(name.Tag); ok {
+		tag = t.TagStr()
+	}
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return tag, "", time.Time{}, err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return tag, "", time.Time{}, err
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return tag, digest.String(), time.Time{}, err
+	}
+	return tag, digest.String(), cfg.Created.Time, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clusterversion
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("clusterversion describe", func() {
+	const (
+		mysqlImage   = "docker.io/apecloud/mysql:8.0.30"
+		agentImage   = "docker.io/apecloud/agent:0.1.0"
+		accountImage = "docker.io/apecloud/mysql-client:8.0.30"
+	)
+
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+		now     = time.Now()
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		cv := testing.FakeClusterVersion()
+		cv.Spec.ComponentVersions = []appsv1alpha1.ClusterComponentVersion{
+			{
+				ComponentDefRef: testing.ComponentDefName,
+				VersionsCtx: appsv1alpha1.VersionsContext{
+					InitContainers: []corev1.Container{{Name: "init", Image: agentImage}},
+					Containers:     []corev1.Container{{Name: "mysql", Image: mysqlImage}},
+				},
+				SystemAccountSpec: &appsv1alpha1.SystemAccountShortSpec{
+					CmdExecutorConfig: &appsv1alpha1.CommandExecutorEnvItem{Image: accountImage},
+				},
+			},
+		}
+		tf = testing.NewTestFactory(testing.Namespace)
+		tf.FakeDynamicClient = testing.FakeDynamicClient(cv)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+		getImageMetaFunc = getImageMeta
+	})
+
+	newOptions := func(images bool) *describeOptions {
+		return &describeOptions{
+			factory:   tf,
+			dynamic:   tf.FakeDynamicClient,
+			names:     []string{testing.ClusterVersionName},
+			images:    images,
+			maxAge:    90,
+			now:       now,
+			IOStreams: streams,
+		}
+	}
+
+	It("describe requires a clusterversion name", func() {
+		Expect(newDescribeCmd(tf, streams)).ShouldNot(BeNil())
+		o := &describeOptions{factory: tf, IOStreams: streams}
+		Expect(o.complete(nil)).Should(HaveOccurred())
+	})
+
+	It("list the images of the clusterversion", func() {
+		Expect(newOptions(false).run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring(testing.ClusterDefName))
+		for _, image := range []string{mysqlImage, agentImage, accountImage} {
+			Expect(out.String()).Should(ContainSubstring(image))
+		}
+		Expect(out.String()).ShouldNot(ContainSubstring("DIGEST"))
+	})
+
+	It("flag the outdated images", func() {
+		getImageMetaFunc = func(image string) (string, string, time.Time, error) {
+			switch image {
+			case mysqlImage:
+				return "8.0.30", "sha256:old", now.Add(-200 * 24 * time.Hour), nil
+			case agentImage:
+				return "0.1.0", "sha256:new", now.Add(-24 * time.Hour), nil
+			default:
+				return "8.0.30", "", time.Time{}, fmt.Errorf("unauthorized")
+			}
+		}
+		Expect(newOptions(true).run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("sha256:old"))
+		Expect(out.String()).Should(ContainSubstring("outdated"))
+		Expect(out.String()).Should(ContainSubstring("unauthorized"))
+		Expect(out.String()).Should(ContainSubstring("1 image(s) were built more than 90 days ago"))
+	})
+
+	It("get the images of the clusterversion in order", func() {
+		cv := testing.FakeClusterVersion()
+		cv.Spec.ComponentVersions = []appsv1alpha1.ClusterComponentVersion{
+			{
+				ComponentDefRef: testing.ComponentDefName,
+				VersionsCtx: appsv1alpha1.VersionsContext{
+					Containers: []corev1.Container{{Name: "mysql", Image: mysqlImage}, {Name: "empty"}},
+				},
+				SwitchoverSpec: &appsv1alpha1.SwitchoverShortSpec{
+					CmdExecutorConfig: &appsv1alpha1.CommandExecutorEnvItem{Image: accountImage},
+				},
+			},
+		}
+		images := getClusterVersionImages(cv)
+		Expect(images).Should(HaveLen(2))
+		Expect(images[0].image).Should(Equal(mysqlImage))
+		Expect(images[1].container).Should(Equal("<switchover>"))
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package completion
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var completionLong = templates.LongDesc(`
+	Generate the autocompletion script for kbcli for the specified shell, which completes the
+	commands, flags and the names of the resources such as clusters, backups and OpsRequests.
+
+	Generating the script on every shell startup is slow, so it is recommended to save the script
+	to a file once and load the file instead, and regenerate it after kbcli is upgraded. The names
+	of the resources are cached under the kbcli home dir for a short TTL set by
+	'kbcli config set completion-cache-ttl', 0 disables the cache. The cached names are completed
+	if the Kubernetes cluster is unreachable.`)
+
+// shell is a shell supported by the completion command
+type shell struct {
+	name    string
+	example string
+	gen     func(root *cobra.Command, o *completionOptions) error
+}
+
+var shells = []shell{
+	{
+		name: "bash",
+		example: templates.Examples(`
+		# the bash-completion package is required, load the completions in the current shell
+		source <(kbcli completion bash)
+
+		# save the script once and load it for every new shell on Linux
+		kbcli completion bash > /etc/bash_completion.d/kbcli
+
+		# save the script once and load it for every new shell on macOS
+		kbcli completion bash > $(brew --prefix)/etc/bash_completion.d/kbcli`),
+		gen: func(root *cobra.Command, o *completionOptions) error {
+			return root.GenBashCompletionV2(o.Out, !o.noDescriptions)
+		},
+	},
+	{
+		name: "zsh",
+		example: templates.Examples(`
+		# enable the completion system once if it is not enabled yet
+		echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+		# load the completions in the current shell
+		source <(kbcli completion zsh)
+
+		# save the script once to the fpath and load it for every new shell
+		kbcli completion zsh > "${fpath[1]}/_kbcli"`),
+		gen: func(root *cobra.Command, o *completionOptions) error {
+			if o.noDescriptions {
+				return root.GenZshCompletionNoDesc(o.Out)
+			}
+			return root.GenZshCompletion(o.Out)
+		},
+	},
+	{
+		name: "fish",
+		example: templates.Examples(`
+		# load the completions in the current shell
+		kbcli completion fish | source
+
+		# save the script once and load it for every new shell
+		kbcli completion fish > ~/.config/fish/completions/kbcli.fish`),
+		gen: func(root *cobra.Command, o *completionOptions) error {
+			return root.GenFishCompletion(o.Out, !o.noDescriptions)
+		},
+	},
+	{
+		name: "powershell",
+		example: templates.Examples(`
+		# load the completions in the current shell
+		kbcli completion powershell | Out-String | Invoke-Expression
+
+		# save the script once and load it for every new shell
+		kbcli completion powershell > $HOME\kbcli.ps1
+		Add-Content $PROFILE '. $HOME\kbcli.ps1'`),
+		gen: func(root *cobra.Command, o *completionOptions) error {
+			if o.noDescriptions {
+				return root.GenPowerShellCompletion(o.Out)
+			}
+			return root.GenPowerShellCompletionWithDesc(o.Out)
+		},
+	},
+}
+
+type completionOptions struct {
+	genericiooptions.IOStreams
+	noDescriptions bool
+}
+
+// NewCompletionCmd creates the completion command, which replaces the default completion command
+// of cobra to add the instructions to cache the scripts.
+func NewCompletionCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &completionOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Short: "Generate the autocompletion script for the specified shell.",
+		Long:  completionLong,
+	}
+	for i := range shells {
+		s := shells[i]
+		subCmd := &cobra.Command{
+			Use:                   s.name,
+			Short:                 fmt.Sprintf("Generate the autocompletion script for %s.", s.name),
+			Example:               s.example,
+			Args:                  cobra.NoArgs,
+			DisableFlagsInUseLine: true,
+			ValidArgsFunction:     cobra.NoFileCompletions,
+			Run: func(cmd *cobra.Command, args []string) {
+				util.CheckErr(s.gen(cmd.Root(), o))
+			},
+		}
+		subCmd.Flags().BoolVar(&o.noDescriptions, "no-descriptions", false, "Disable the completion descriptions")
+		cmd.AddCommand(subCmd)
+	}
+	return cmd
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package completion
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+var _ = Describe("completion", func() {
+	It("generates the scripts of the shells", func() {
+		out := &bytes.Buffer{}
+		root := &cobra.Command{Use: "kbcli"}
+		cmd := NewCompletionCmd(genericiooptions.IOStreams{Out: out, ErrOut: out})
+		root.AddCommand(cmd)
+
+		expected := map[string]string{
+			"bash":       "__start_kbcli",
+			"zsh":        "#compdef kbcli",
+			"fish":       "complete -c kbcli",
+			"powershell": "Register-ArgumentCompleter",
+		}
+		for shell, content := range expected {
+			out.Reset()
+			root.SetArgs([]string{"completion", shell})
+			Expect(root.Execute()).Should(Succeed())
+			Expect(out.String()).Should(ContainSubstring(content), shell)
+		}
+
+		By("disable the descriptions")
+		out.Reset()
+		root.SetArgs([]string{"completion", "fish", "--no-descriptions"})
+		Expect(root.Execute()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("__completeNoDesc"))
+	})
+})
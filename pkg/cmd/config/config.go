@@ -0,0 +1,444 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var configExample = templates.Examples(`
+	# set the default namespace used by all commands
+	kbcli config set namespace demo
+
+	# set the default termination policy when creating cluster
+	kbcli config set termination-policy WipeOut
+
+	# get the default namespace
+	kbcli config get namespace
+
+	# unset the default namespace
+	kbcli config unset namespace
+
+	# view all configs
+	kbcli config view`)
+
+// configKey is a config that can be set by `kbcli config set`, it is used as the
+// default value of the flag with the same name if the flag is not specified.
+type configKey struct {
+	name        string
+	description string
+	// isFlag is true if the config is the default value of the flag with the same name
+	isFlag   bool
+	validate func(value string) error
+}
+
+var configKeys = []configKey{
+	{
+		name:        types.CfgKeyDefaultNamespace,
+		description: "The default namespace used by all commands.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if errs := validation.IsDNS1123Label(value); len(errs) > 0 {
+				return fmt.Errorf("invalid namespace %s: %s", value, strings.Join(errs, ", "))
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyDefaultOutput,
+		description: "The default output format of the commands supporting --output.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if _, err := printer.ParseFormat(value); err != nil {
+				return fmt.Errorf("invalid output format %s, should be one of %s", value, strings.Join(printer.Formats(), "|"))
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyDefaultTerminationPolicy,
+		description: "The default termination policy when creating cluster.",
+		isFlag:      true,
+		validate: func(value string) error {
+			policies := []string{string(appsv1alpha1.DoNotTerminate), string(appsv1alpha1.Halt),
+				string(appsv1alpha1.Delete), string(appsv1alpha1.WipeOut)}
+			if !slices.Contains(policies, value) {
+				return fmt.Errorf("invalid termination policy %s, should be one of %s", value, strings.Join(policies, "|"))
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyDefaultMonitoringInterval,
+		description: "The default monitoring interval in seconds when creating cluster, 0 means disabling monitoring.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if _, err := strconv.ParseUint(value, 10, 8); err != nil {
+				return fmt.Errorf("invalid monitoring interval %s, should be an integer between 0 and 255", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyEditor,
+		description: "The editor used by the edit commands, KUBE_EDITOR and EDITOR environment variables take precedence.",
+		validate:    func(value string) error { return nil },
+	},
+	{
+		name:        types.CfgKeyAuditLog,
+		description: "Record the mutate commands to the audit log, which can be queried by `kbcli history`.",
+		validate: func(value string) error {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid audit-log %s, should be true or false", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyAuditEvents,
+		description: "Record the mutate commands as Kubernetes Events on the target Clusters and Backups, which appear in `kubectl describe`.",
+		validate: func(value string) error {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid audit-events %s, should be true or false", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyColor,
+		description: "When to color the outputs, NO_COLOR environment variable is honored in the auto mode.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if !slices.Contains(printer.ColorModes(), value) {
+				return fmt.Errorf("invalid color mode %s, should be one of %s", value, strings.Join(printer.ColorModes(), "|"))
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyStatusColors,
+		description: "The colors of the statuses in the outputs, such as Failed=magenta,Running=blue.",
+		validate: func(value string) error {
+			_, err := printer.ParseStatusColors(value)
+			return err
+		},
+	},
+	{
+		name:        types.CfgKeyImageRegistry,
+		description: "The image registry of the KubeBlocks tools image used by the CronJobs managed by kbcli, such as the scheduled operations and the blackout windows.",
+		validate: func(value string) error {
+			if value == "" || strings.Contains(value, " ") {
+				return fmt.Errorf("invalid image registry %q", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyAutoNamespace,
+		description: "Search the cluster across namespaces if it's not found in the current namespace.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid auto-namespace %s, should be true or false", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyDiscoveryCacheTTL,
+		description: "The TTL of the on-disk API discovery cache shared by all commands, such as 6h, 0 caches the discovery in memory only.",
+		validate: func(value string) error {
+			if ttl, err := time.ParseDuration(value); err != nil || ttl < 0 {
+				return fmt.Errorf("invalid discovery-cache-ttl %s, should be a non-negative duration such as 30m or 6h", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyRequestTimeout,
+		description: "The default timeout of a single request to the API server, such as 30s, 0 means no timeout.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if timeout, err := time.ParseDuration(value); err != nil || timeout < 0 {
+				return fmt.Errorf("invalid request-timeout %s, should be a non-negative duration such as 30s or 1m", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyMaxRetries,
+		description: "The default max number of retries of the requests to the API server failed with 429 or 5xx, 0 disables the retries.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if _, err := strconv.ParseUint(value, 10, 8); err != nil {
+				return fmt.Errorf("invalid max-retries %s, should be an integer between 0 and 255", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyClientQPS,
+		description: "The max QPS of the requests to the API server.",
+		validate:    validatePositiveInt(types.CfgKeyClientQPS),
+	},
+	{
+		name:        types.CfgKeyClientBurst,
+		description: "The max burst of the requests to the API server.",
+		validate:    validatePositiveInt(types.CfgKeyClientBurst),
+	},
+	{
+		name:        types.CfgKeyCompletionCacheTTL,
+		description: "The TTL of the cached shell completions listing the resources, such as 30s, 0 disables the cache.",
+		validate: func(value string) error {
+			if ttl, err := time.ParseDuration(value); err != nil || ttl < 0 {
+				return fmt.Errorf("invalid completion-cache-ttl %s, should be a non-negative duration such as 10s or 1m", value)
+			}
+			return nil
+		},
+	},
+	{
+		name:        types.CfgKeyTimezone,
+		description: "The timezone of the time outputs, such as UTC, Local or Asia/Shanghai.",
+		isFlag:      true,
+		validate: func(value string) error {
+			_, err := util.ParseTimezone(value)
+			return err
+		},
+	},
+	{
+		name:        types.CfgKeyTimeFormat,
+		description: "The format of the time outputs, the relative format prints the time such as 3h ago.",
+		isFlag:      true,
+		validate: func(value string) error {
+			if !slices.Contains(util.TimeFormats(), value) {
+				return fmt.Errorf("invalid time format %s, should be one of %s", value, strings.Join(util.TimeFormats(), "|"))
+			}
+			return nil
+		},
+	},
+}
+
+func validatePositiveInt(name string) func(value string) error {
+	return func(value string) error {
+		if i, err := strconv.Atoi(value); err != nil || i <= 0 {
+			return fmt.Errorf("invalid %s %s, should be a positive integer", name, value)
+		}
+		return nil
+	}
+}
+
+func getConfigKey(name string) (*configKey, error) {
+	for i := range configKeys {
+		if configKeys[i].name == name {
+			return &configKeys[i], nil
+		}
+	}
+	names := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		names[i] = k.name
+	}
+	return nil, fmt.Errorf("unsupported config %s, should be one of %s", name, strings.Join(names, "|"))
+}
+
+// NewConfigCmd creates the config command
+func NewConfigCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Manage the kbcli config file, the configs are used as the defaults of the flags.",
+		Example: configExample,
+	}
+	cmd.AddCommand(
+		newSetCmd(streams),
+		newGetCmd(streams),
+		newUnsetCmd(streams),
+		newViewCmd(streams),
+	)
+	return cmd
+}
+
+func newSetCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:       "set KEY VALUE",
+		Short:     "Set a config.",
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: configKeyNames(),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(setConfig(args[0], args[1]))
+			fmt.Fprintf(streams.Out, "Config %s is set to %s\n", args[0], args[1])
+		},
+	}
+}
+
+func newGetCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:       "get KEY",
+		Short:     "Get a config.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: configKeyNames(),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, err := getConfigKey(args[0])
+			util.CheckErr(err)
+			fmt.Fprintln(streams.Out, viper.GetString(args[0]))
+		},
+	}
+}
+
+func newUnsetCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:       "unset KEY",
+		Short:     "Unset a config.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: configKeyNames(),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(unsetConfig(args[0]))
+			fmt.Fprintf(streams.Out, "Config %s is unset\n", args[0])
+		},
+	}
+}
+
+func newViewCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "View the supported configs and their values.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			tbl := printer.NewTablePrinter(streams.Out)
+			tbl.SetHeader("KEY", "VALUE", "DESCRIPTION")
+			for _, k := range configKeys {
+				tbl.AddRow(k.name, viper.GetString(k.name), k.description)
+			}
+			tbl.Print()
+		},
+	}
+}
+
+func configKeyNames() []string {
+	names := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		names[i] = k.name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readConfigFile() (string, map[string]interface{}, error) {
+	file, err := util.GetCliConfigFile()
+	if err != nil {
+		return "", nil, err
+	}
+	configs := map[string]interface{}{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return file, configs, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+	if err = yaml.Unmarshal(data, &configs); err != nil {
+		return "", nil, fmt.Errorf("invalid config file %s: %v", file, err)
+	}
+	if configs == nil {
+		configs = map[string]interface{}{}
+	}
+	return file, configs, nil
+}
+
+func writeConfigFile(file string, configs map[string]interface{}) error {
+	data, err := yaml.Marshal(configs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0600)
+}
+
+func setConfig(key, value string) error {
+	k, err := getConfigKey(key)
+	if err != nil {
+		return err
+	}
+	if err = k.validate(value); err != nil {
+		return err
+	}
+	file, configs, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	configs[key] = value
+	viper.Set(key, value)
+	return writeConfigFile(file, configs)
+}
+
+func unsetConfig(key string) error {
+	if _, err := getConfigKey(key); err != nil {
+		return err
+	}
+	file, configs, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	delete(configs, key)
+	viper.Set(key, nil)
+	return writeConfigFile(file, configs)
+}
+
+// ApplyDefaults sets the default values of the flags not specified explicitly from the configs,
+// all commands resolve their flag defaults by this function before running. The flags already
+// resolved, such as the namespace of the current kbcli context, are not overridden.
+func ApplyDefaults(flags *pflag.FlagSet) {
+	for _, k := range configKeys {
+		value := viper.GetString(k.name)
+		if value == "" || !k.isFlag {
+			continue
+		}
+		f := flags.Lookup(k.name)
+		if f == nil || f.Changed || f.Value.String() != f.DefValue {
+			continue
+		}
+		// the flag with the same name may have different allowed values in different commands
+		if err := f.Value.Set(value); err != nil {
+			klog.V(1).Infof("failed to set the default value of flag %s to %s: %v", k.name, value, err)
+		}
+	}
+
+	if editor := viper.GetString(types.CfgKeyEditor); editor != "" &&
+		os.Getenv("KUBE_EDITOR") == "" && os.Getenv("EDITOR") == "" {
+		_ = os.Setenv("KUBE_EDITOR", editor)
+	}
+}
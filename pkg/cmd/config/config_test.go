@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var _ = Describe("config", func() {
+	BeforeEach(func() {
+		homeDir, err := os.MkdirTemp("", "kbcli-config")
+		Expect(err).Should(Succeed())
+		Expect(os.Setenv(types.CliHomeEnv, homeDir)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(os.Getenv(types.CliHomeEnv))).Should(Succeed())
+		Expect(os.Unsetenv(types.CliHomeEnv)).Should(Succeed())
+		viper.Reset()
+	})
+
+	It("config command", func() {
+		cmd := NewConfigCmd(genericiooptions.NewTestIOStreamsDiscard())
+		Expect(cmd).ShouldNot(BeNil())
+		Expect(cmd.Commands()).Should(HaveLen(4))
+	})
+
+	It("set and unset config", func() {
+		Expect(setConfig("not-exist", "test")).Should(HaveOccurred())
+		Expect(setConfig(types.CfgKeyDefaultNamespace, "Invalid_NS")).Should(HaveOccurred())
+		Expect(setConfig(types.CfgKeyDefaultTerminationPolicy, "Unknown")).Should(HaveOccurred())
+		Expect(setConfig(types.CfgKeyDefaultMonitoringInterval, "1000")).Should(HaveOccurred())
+		Expect(setConfig(types.CfgKeyTimezone, "Invalid/Zone")).Should(HaveOccurred())
+		Expect(setConfig(types.CfgKeyTimeFormat, "unknown")).Should(HaveOccurred())
+
+		Expect(setConfig(types.CfgKeyDefaultNamespace, "demo")).Should(Succeed())
+		Expect(setConfig(types.CfgKeyDefaultTerminationPolicy, "WipeOut")).Should(Succeed())
+		file, configs, err := readConfigFile()
+		Expect(err).Should(Succeed())
+		Expect(configs).Should(HaveKeyWithValue(types.CfgKeyDefaultNamespace, "demo"))
+		Expect(configs).Should(HaveKeyWithValue(types.CfgKeyDefaultTerminationPolicy, "WipeOut"))
+		configFile, _ := util.GetCliConfigFile()
+		Expect(file).Should(Equal(configFile))
+
+		Expect(unsetConfig(types.CfgKeyDefaultNamespace)).Should(Succeed())
+		_, configs, err = readConfigFile()
+		Expect(err).Should(Succeed())
+		Expect(configs).ShouldNot(HaveKey(types.CfgKeyDefaultNamespace))
+		Expect(viper.GetString(types.CfgKeyDefaultNamespace)).Should(BeEmpty())
+	})
+
+	It("apply defaults to flags", func() {
+		Expect(setConfig(types.CfgKeyDefaultTerminationPolicy, "WipeOut")).Should(Succeed())
+		Expect(setConfig(types.CfgKeyDefaultNamespace, "demo")).Should(Succeed())
+
+		var policy, namespace string
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.StringVar(&policy, "termination-policy", "Delete", "")
+		flags.StringVar(&namespace, "namespace", "", "")
+		Expect(flags.Parse([]string{"--namespace", "default"})).Should(Succeed())
+		ApplyDefaults(flags)
+		Expect(policy).Should(Equal("WipeOut"))
+		Expect(namespace).Should(Equal("default"))
+
+		By("the resolved value is not overridden")
+		namespace = ""
+		flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.StringVar(&namespace, "namespace", "", "")
+		namespace = "context-ns"
+		ApplyDefaults(flags)
+		Expect(namespace).Should(Equal("context-ns"))
+	})
+})
@@ -23,10 +23,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/cmd/organization"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var contextExample = templates.Examples(`
@@ -38,6 +39,10 @@ var contextExample = templates.Examples(`
 	kbcli context describe context1
 	// Switch to context context2.
 	kbcli context use context2
+	// Add a local context dev with the kubeconfig, kubernetes context and namespace.
+	kbcli context add dev --kubeconfig ~/.kube/dev.yaml --context kind-dev --namespace demo
+	// Remove the local context dev.
+	kbcli context remove dev
 `)
 
 const (
@@ -63,8 +68,8 @@ type ContextOptions struct {
 func NewContextCmd(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "context",
-		Short: "kbcli context allows you to manage cloud context. This command is currently only applicable to cloud," +
-			" and currently does not support switching the context of the local k8s cluster.",
+		Short: "kbcli context allows you to manage contexts. The cloud contexts are managed if you have logged in to the cloud," +
+			" otherwise the local contexts pointing to kubeconfigs and kubernetes contexts are managed.",
 		Example: contextExample,
 	}
 	cmd.AddCommand(
@@ -72,10 +77,44 @@ func NewContextCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		newContextUseCmd(streams),
 		newContextCurrentCmd(streams),
 		newContextDescribeCmd(streams),
+		newContextAddCmd(streams),
+		newContextRemoveCmd(streams),
 	)
 	return cmd
 }
 
+func newContextAddCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &ContextOptions{IOStreams: streams}
+
+	cmd := &cobra.Command{
+		Use:   "add NAME",
+		Short: "Add a local context with the kubeconfig, kubernetes context and namespace specified by the global flags.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runAdd(cmd))
+		},
+	}
+	return cmd
+}
+
+func newContextRemoveCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &ContextOptions{IOStreams: streams}
+
+	cmd := &cobra.Command{
+		Use:     "remove NAME",
+		Aliases: []string{"rm"},
+		Short:   "Remove a context.",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runRemove())
+		},
+	}
+	return cmd
+}
+
 func newContextListCmd(streams genericiooptions.IOStreams) *cobra.Command {
 	o := &ContextOptions{IOStreams: streams}
 
@@ -83,9 +122,9 @@ func newContextListCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "list",
 		Short: "List all created contexts.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runList())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runList())
 		},
 	}
 	return cmd
@@ -98,9 +137,9 @@ func newContextCurrentCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "current",
 		Short: "Get the currently used context.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runCurrent())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runCurrent())
 		},
 	}
 	return cmd
@@ -113,9 +152,9 @@ func newContextDescribeCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "describe",
 		Short: "Get the description information of a context.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runDescribe())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runDescribe())
 		},
 	}
 
@@ -131,9 +170,9 @@ func newContextUseCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "use",
 		Short: "Use another context that you have already created.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runUse())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runUse())
 		},
 	}
 
@@ -141,7 +180,7 @@ func newContextUseCmd(streams genericiooptions.IOStreams) *cobra.Command {
 }
 
 func (o *ContextOptions) validate(cmd *cobra.Command) error {
-	if cmd.Name() == "describe" || cmd.Name() == "use" {
+	if cmd.Name() == "describe" || cmd.Name() == "use" || cmd.Name() == "remove" || cmd.Name() == "add" {
 		if o.ContextName == "" {
 			return errors.New("context name is required")
 		}
@@ -155,29 +194,37 @@ func (o *ContextOptions) complete(args []string) error {
 		o.ContextName = args[0]
 	}
 
+	if o.Context != nil {
+		return nil
+	}
+
+	// use the local context if not logged in to the cloud or the cloud context is local
+	localCtx := &LocalContext{
+		ContextName:  o.ContextName,
+		OutputFormat: o.OutputFormat,
+		IOStreams:    o.IOStreams,
+	}
 	currentOrgAndContext, err := organization.GetCurrentOrgAndContext()
-	if err != nil {
-		return err
-	}
-
-	if o.Context == nil {
-		if currentOrgAndContext.CurrentContext != localContext {
-			token, err := organization.GetToken()
-			if err != nil {
-				return err
-			}
-			o.Context = &CloudContext{
-				ContextName:  o.ContextName,
-				Token:        token,
-				OrgName:      currentOrgAndContext.CurrentOrganization,
-				IOStreams:    o.IOStreams,
-				APIURL:       organization.APIURL,
-				APIPath:      organization.APIPath,
-				OutputFormat: o.OutputFormat,
-			}
-		}
+	if err != nil || currentOrgAndContext.CurrentContext == localContext {
+		o.Context = localCtx
+		return nil
 	}
 
+	token, err := organization.GetToken()
+	if err != nil {
+		klog.V(1).Infof("failed to get cloud token, use local context: %v", err)
+		o.Context = localCtx
+		return nil
+	}
+	o.Context = &CloudContext{
+		ContextName:  o.ContextName,
+		Token:        token,
+		OrgName:      currentOrgAndContext.CurrentOrganization,
+		IOStreams:    o.IOStreams,
+		APIURL:       organization.APIURL,
+		APIPath:      organization.APIPath,
+		OutputFormat: o.OutputFormat,
+	}
 	return nil
 }
 
@@ -196,3 +243,30 @@ func (o *ContextOptions) runDescribe() error {
 func (o *ContextOptions) runUse() error {
 	return o.Context.showUseContext()
 }
+
+func (o *ContextOptions) runRemove() error {
+	return o.Context.showRemoveContext()
+}
+
+func (o *ContextOptions) runAdd(cmd *cobra.Command) error {
+	localContext, ok := o.Context.(*LocalContext)
+	if !ok {
+		return errors.New("adding context is only supported for local contexts")
+	}
+	getFlag := func(name string) string {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return f.Value.String()
+		}
+		return ""
+	}
+	localContext.Item = &LocalContextItem{
+		Name:        o.ContextName,
+		Kubeconfig:  getFlag("kubeconfig"),
+		KubeContext: getFlag("context"),
+		Namespace:   getFlag("namespace"),
+	}
+	if localContext.Item.Kubeconfig == "" && localContext.Item.KubeContext == "" {
+		return errors.New("at least one of --kubeconfig and --context should be specified")
+	}
+	return localContext.addContext()
+}
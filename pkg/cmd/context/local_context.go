@@ -0,0 +1,321 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	LocalContextDir  = "local_context"
+	LocalContextFile = "contexts.json"
+)
+
+// LocalContextItem is a kbcli context pointing to a kubeconfig and a context in it, so that
+// the user can switch between kubernetes clusters without exporting KUBECONFIG.
+type LocalContextItem struct {
+	Name        string `json:"name" yaml:"name"`
+	Kubeconfig  string `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`
+	KubeContext string `json:"kubeContext,omitempty" yaml:"kubeContext,omitempty"`
+	Namespace   string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+type LocalContexts struct {
+	CurrentContext string             `json:"currentContext"`
+	Contexts       []LocalContextItem `json:"contexts"`
+}
+
+type LocalContext struct {
+	ContextName  string
+	OutputFormat string
+	// Item is the context to add
+	Item *LocalContextItem
+
+	genericiooptions.IOStreams
+}
+
+var _ Context = &LocalContext{}
+
+func GetLocalContextFilePath() (string, error) {
+	cliHomeDir, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cliHomeDir, LocalContextDir, LocalContextFile), nil
+}
+
+// GetLocalContexts reads the local contexts, an empty context list is returned if no context is added
+func GetLocalContexts() (*LocalContexts, error) {
+	filePath, err := GetLocalContextFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return &LocalContexts{}, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read local context file: %s", filePath)
+	}
+	contexts := &LocalContexts{}
+	if err = json.Unmarshal(data, contexts); err != nil {
+		return nil, errors.Wrapf(err, "Invalid local context file: %s, fix or remove it", filePath)
+	}
+	return contexts, nil
+}
+
+func writeLocalContexts(contexts *LocalContexts) error {
+	data, err := json.MarshalIndent(contexts, "", "    ")
+	if err != nil {
+		return err
+	}
+	filePath, err := GetLocalContextFilePath()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+		return errors.Wrap(err, "Failed to create necessary folders.")
+	}
+	return os.WriteFile(filePath, data, 0600)
+}
+
+func (c *LocalContexts) get(name string) *LocalContextItem {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// GetCurrentLocalContext returns the current local context, nil is returned if no context is used
+func GetCurrentLocalContext() (*LocalContextItem, error) {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return nil, err
+	}
+	if contexts.CurrentContext == "" {
+		return nil, nil
+	}
+	item := contexts.get(contexts.CurrentContext)
+	if item == nil {
+		return nil, errors.Errorf("current context %s does not exist, use 'kbcli context use' to switch to another context", contexts.CurrentContext)
+	}
+	return item, nil
+}
+
+// ApplyCurrentContext makes the kubernetes config flags honor the current local context, the
+// flags specified explicitly and the KUBECONFIG environment variable take precedence.
+func ApplyCurrentContext(configFlags *genericclioptions.ConfigFlags, flags *pflag.FlagSet) error {
+	if os.Getenv(clientcmd.RecommendedConfigPathEnvVar) != "" {
+		return nil
+	}
+	changed := func(name string) bool {
+		f := flags.Lookup(name)
+		return f != nil && f.Changed
+	}
+	if changed("kubeconfig") || changed("context") {
+		return nil
+	}
+	item, err := GetCurrentLocalContext()
+	if err != nil {
+		// do not break the commands, especially "kbcli context" which is used to fix the contexts
+		printer.Warning(os.Stderr, "%s, ignore the kbcli context\n", err.Error())
+		return nil
+	}
+	if item == nil {
+		return nil
+	}
+	if item.Kubeconfig != "" && configFlags.KubeConfig != nil {
+		*configFlags.KubeConfig = item.Kubeconfig
+	}
+	if item.KubeContext != "" && configFlags.Context != nil {
+		*configFlags.Context = item.KubeContext
+	}
+	if item.Namespace != "" && !changed("namespace") && configFlags.Namespace != nil {
+		*configFlags.Namespace = item.Namespace
+	}
+	return nil
+}
+
+func (l *LocalContext) addContext() error {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return err
+	}
+	if contexts.get(l.Item.Name) != nil {
+		return errors.Errorf("context %s already exists", l.Item.Name)
+	}
+	if l.Item.Kubeconfig != "" {
+		if l.Item.Kubeconfig, err = filepath.Abs(l.Item.Kubeconfig); err != nil {
+			return err
+		}
+		if _, err = os.Stat(l.Item.Kubeconfig); err != nil {
+			return errors.Wrapf(err, "invalid kubeconfig %s", l.Item.Kubeconfig)
+		}
+	}
+	// make sure the context exists in the kubeconfig
+	if l.Item.KubeContext != "" {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = l.Item.Kubeconfig
+		config, err := rules.Load()
+		if err != nil {
+			return err
+		}
+		if _, ok := config.Contexts[l.Item.KubeContext]; !ok {
+			return errors.Errorf("context %s does not exist in kubeconfig", l.Item.KubeContext)
+		}
+	}
+	contexts.Contexts = append(contexts.Contexts, *l.Item)
+	if err = writeLocalContexts(contexts); err != nil {
+		return err
+	}
+	fmt.Fprintf(l.Out, "Context %s added.\n", l.Item.Name)
+	return nil
+}
+
+func (l *LocalContext) showContext() error {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return err
+	}
+	item := contexts.get(l.ContextName)
+	if item == nil {
+		return errors.Errorf("context %s does not exist", l.ContextName)
+	}
+	switch l.OutputFormat {
+	case "yaml":
+		data, err := yaml.Marshal(item)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(l.Out, string(data))
+	case "json":
+		data, err := json.MarshalIndent(item, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(l.Out, string(data))
+	default:
+		l.printTable(contexts.CurrentContext, []LocalContextItem{*item})
+	}
+	return nil
+}
+
+func (l *LocalContext) printTable(current string, items []LocalContextItem) {
+	tbl := printer.NewTablePrinter(l.Out)
+	tbl.SetHeader("NAME", "KUBECONFIG", "KUBE-CONTEXT", "NAMESPACE", "CURRENT")
+	for _, item := range items {
+		isCurrent := ""
+		if item.Name == current {
+			isCurrent = "*"
+		}
+		tbl.AddRow(item.Name, noneIfEmpty(item.Kubeconfig), noneIfEmpty(item.KubeContext),
+			noneIfEmpty(item.Namespace), isCurrent)
+	}
+	tbl.Print()
+}
+
+func (l *LocalContext) showContexts() error {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return err
+	}
+	if len(contexts.Contexts) == 0 {
+		fmt.Fprintln(l.Out, "No context found, use 'kbcli context add' to add one.")
+		return nil
+	}
+	l.printTable(contexts.CurrentContext, contexts.Contexts)
+	return nil
+}
+
+func (l *LocalContext) showCurrentContext() error {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return err
+	}
+	if contexts.CurrentContext == "" {
+		fmt.Fprintln(l.Out, "No context is used, the default kubeconfig is used.")
+		return nil
+	}
+	fmt.Fprintf(l.Out, "Current context: %s\n", contexts.CurrentContext)
+	return nil
+}
+
+func (l *LocalContext) showUseContext() error {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return err
+	}
+	if contexts.get(l.ContextName) == nil {
+		return errors.Errorf("context %s does not exist", l.ContextName)
+	}
+	oldContextName := contexts.CurrentContext
+	contexts.CurrentContext = l.ContextName
+	if err = writeLocalContexts(contexts); err != nil {
+		return err
+	}
+	fmt.Fprintf(l.Out, "Successfully switched from %s to context %s.\n", noneIfEmpty(oldContextName), l.ContextName)
+	return nil
+}
+
+func (l *LocalContext) showRemoveContext() error {
+	contexts, err := GetLocalContexts()
+	if err != nil {
+		return err
+	}
+	if contexts.get(l.ContextName) == nil {
+		return errors.Errorf("context %s does not exist", l.ContextName)
+	}
+	items := make([]LocalContextItem, 0, len(contexts.Contexts))
+	for _, item := range contexts.Contexts {
+		if item.Name != l.ContextName {
+			items = append(items, item)
+		}
+	}
+	contexts.Contexts = items
+	if contexts.CurrentContext == l.ContextName {
+		contexts.CurrentContext = ""
+	}
+	if err = writeLocalContexts(contexts); err != nil {
+		return err
+	}
+	fmt.Fprintf(l.Out, "Context %s removed.\n", l.ContextName)
+	return nil
+}
+
+func noneIfEmpty(s string) string {
+	if s == "" {
+		return printer.NoneString
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package context
+
+import (
+	"os"
+	"path/filepath"
+
+	ginkgo_context "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = ginkgo_context.Describe("Test Local Context", func() {
+	var (
+		streams    genericiooptions.IOStreams
+		kubeconfig string
+	)
+
+	ginkgo_context.BeforeEach(func() {
+		streams, _, _, _ = genericiooptions.NewTestIOStreams()
+		homeDir, err := os.MkdirTemp("", "kbcli-context")
+		Expect(err).Should(Succeed())
+		Expect(os.Setenv(types.CliHomeEnv, homeDir)).Should(Succeed())
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters["dev"] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:6443"}
+		config.Contexts["kind-dev"] = &clientcmdapi.Context{Cluster: "dev"}
+		kubeconfig = filepath.Join(homeDir, "kubeconfig")
+		Expect(clientcmd.WriteToFile(*config, kubeconfig)).Should(Succeed())
+	})
+
+	ginkgo_context.AfterEach(func() {
+		Expect(os.RemoveAll(os.Getenv(types.CliHomeEnv))).Should(Succeed())
+		Expect(os.Unsetenv(types.CliHomeEnv)).Should(Succeed())
+	})
+
+	ginkgo_context.It("add, use and remove local context", func() {
+		l := &LocalContext{IOStreams: streams}
+		l.Item = &LocalContextItem{Name: "dev", Kubeconfig: kubeconfig, KubeContext: "kind-dev", Namespace: "demo"}
+		Expect(l.addContext()).Should(Succeed())
+		Expect(l.addContext()).Should(MatchError(ContainSubstring("already exists")))
+
+		l.Item = &LocalContextItem{Name: "test", Kubeconfig: kubeconfig, KubeContext: "not-exist"}
+		Expect(l.addContext()).Should(MatchError(ContainSubstring("does not exist in kubeconfig")))
+
+		l.ContextName = "dev"
+		Expect(l.showUseContext()).Should(Succeed())
+		Expect(l.showContexts()).Should(Succeed())
+		Expect(l.showContext()).Should(Succeed())
+		Expect(l.showCurrentContext()).Should(Succeed())
+
+		ginkgo_context.By("apply current context to config flags")
+		configFlags := genericclioptions.NewConfigFlags(true)
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		configFlags.AddFlags(flags)
+		Expect(ApplyCurrentContext(configFlags, flags)).Should(Succeed())
+		Expect(*configFlags.KubeConfig).Should(Equal(kubeconfig))
+		Expect(*configFlags.Context).Should(Equal("kind-dev"))
+		Expect(*configFlags.Namespace).Should(Equal("demo"))
+
+		ginkgo_context.By("the flags specified explicitly take precedence")
+		configFlags = genericclioptions.NewConfigFlags(true)
+		flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		configFlags.AddFlags(flags)
+		Expect(flags.Set("context", "other")).Should(Succeed())
+		Expect(ApplyCurrentContext(configFlags, flags)).Should(Succeed())
+		Expect(*configFlags.KubeConfig).Should(BeEmpty())
+		Expect(*configFlags.Context).Should(Equal("other"))
+
+		ginkgo_context.By("remove current context")
+		Expect(l.showRemoveContext()).Should(Succeed())
+		current, err := GetCurrentLocalContext()
+		Expect(err).Should(Succeed())
+		Expect(current).Should(BeNil())
+	})
+	ginkgo_context.It("ignore the corrupt contexts file", func() {
+		filePath, err := GetLocalContextFilePath()
+		Expect(err).Should(Succeed())
+		Expect(os.MkdirAll(filepath.Dir(filePath), 0755)).Should(Succeed())
+		Expect(os.WriteFile(filePath, []byte("{invalid"), 0644)).Should(Succeed())
+		_, err = GetLocalContexts()
+		Expect(err).Should(HaveOccurred())
+
+		configFlags := genericclioptions.NewConfigFlags(true)
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		configFlags.AddFlags(flags)
+		Expect(ApplyCurrentContext(configFlags, flags)).Should(Succeed())
+		Expect(*configFlags.KubeConfig).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+// dashboardAliases are the short names of the monitoring dashboards
+var dashboardAliases = map[string]string{
+	"grafana":    grafanaAddonName,
+	"prometheus": prometheusServer,
+}
+
+const (
+	// grafanaSecretName is the secret storing the admin credential of grafana addon
+	grafanaSecretName = "kb-addon-grafana"
+
+	grafanaUserKey     = "admin-user"
+	grafanaPasswordKey = "admin-password"
+)
+
+// getClusterEngineType returns the engine specific grafana dashboard type of the cluster by
+// its cluster definition, such as apecloud-mysql to mysql.
+func getClusterEngineType(c *appsv1alpha1.Cluster) (string, error) {
+	for _, t := range availableTypes {
+		if strings.Contains(c.Spec.ClusterDefRef, t) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("no grafana dashboard found for cluster %s of cluster definition %s, specify one of %s as the dashboard type",
+		c.Name, c.Spec.ClusterDefRef, strings.Join(availableTypes, ","))
+}
+
+// buildGrafanaClusterURL filters the grafana dashboard to the cluster by the dashboard variables
+func buildGrafanaClusterURL(dashURL *string, namespace, cluster string) {
+	values := url.Values{}
+	values.Set("var-namespace", namespace)
+	values.Set("var-cluster", cluster)
+	*dashURL += "?" + values.Encode()
+}
+
+// buildPrometheusClusterURL opens the prometheus graph querying the targets of the cluster
+func buildPrometheusClusterURL(dashURL *string, namespace, cluster string) {
+	values := url.Values{}
+	values.Set("g0.expr", fmt.Sprintf(`up{namespace="%s",app_kubernetes_io_instance="%s"}`, namespace, cluster))
+	values.Set("g0.tab", "1")
+	*dashURL += "/graph?" + values.Encode()
+}
+
+// getGrafanaCredential gets the admin credential of grafana from the secret of grafana addon
+func getGrafanaCredential(client kubernetes.Interface, namespace string) (string, string, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), grafanaSecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	return string(secret.Data[grafanaUserKey]), string(secret.Data[grafanaPasswordKey]), nil
+}
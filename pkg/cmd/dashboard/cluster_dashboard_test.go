@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dashboard
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("cluster dashboard", func() {
+	const baseURL = "http://127.0.0.1:8080"
+
+	It("get the engine type of cluster", func() {
+		c := testing.FakeCluster("test", "default")
+		c.Spec.ClusterDefRef = "apecloud-mysql"
+		Expect(getClusterEngineType(c)).Should(Equal("mysql"))
+
+		c.Spec.ClusterDefRef = "unknown"
+		_, err := getClusterEngineType(c)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("build the urls filtered to cluster", func() {
+		url := baseURL + "/d/mysql"
+		buildGrafanaClusterURL(&url, "default", "test")
+		Expect(url).Should(Equal(baseURL + "/d/mysql?var-cluster=test&var-namespace=default"))
+
+		url = baseURL
+		buildPrometheusClusterURL(&url, "default", "test")
+		Expect(url).Should(HavePrefix(baseURL + "/graph?g0.expr="))
+		Expect(url).Should(ContainSubstring("app_kubernetes_io_instance%3D%22test%22"))
+	})
+
+	It("get grafana credential", func() {
+		client := kubefakeclient.NewSimpleClientset()
+		_, _, err := getGrafanaCredential(client, "kb-system")
+		Expect(err).Should(HaveOccurred())
+
+		client = kubefakeclient.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: grafanaSecretName, Namespace: "kb-system"},
+			Data: map[string][]byte{
+				grafanaUserKey:     []byte("admin"),
+				grafanaPasswordKey: []byte("secret"),
+			},
+		})
+		user, password, err := getGrafanaCredential(client, "kb-system")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(user).Should(Equal("admin"))
+		Expect(password).Should(Equal("secret"))
+	})
+})
@@ -36,11 +36,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
+	"k8s.io/klog/v2"
 	cmdpf "k8s.io/kubectl/pkg/cmd/portforward"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 	"k8s.io/utils/pointer"
 
+	"github.com/apecloud/kbcli/pkg/cluster"
 	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/util"
 )
@@ -92,6 +94,12 @@ var (
 		# Open a dashboard with a specific local port
 		kbcli dashboard open kubeblocks-grafana --port 8080
 
+		# Open the engine specific grafana dashboard filtered to the cluster
+		kbcli dashboard open grafana --cluster mycluster
+
+		# Open the prometheus graph of the cluster targets
+		kbcli dashboard open prometheus --cluster mycluster
+
 		# for dashboard kubeblocks-grafana, support to direct the specified dashboard type
 		# now we support mysql,mongodb,postgresql,redis,weaviate,kafka,cadvisor,jmx and node
 		kbcli dashboard open kubeblocks-grafana mysql
@@ -186,6 +194,7 @@ func NewDashboardCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cob
 	cmd.AddCommand(
 		newListCmd(f, streams),
 		newOpenCmd(f, streams),
+		newTUICmd(f, streams),
 	)
 
 	return cmd
@@ -239,8 +248,13 @@ type openOptions struct {
 	genericiooptions.IOStreams
 	portForwardOptions *cmdpf.PortForwardOptions
 
-	name      string
-	localPort string
+	name        string
+	localPort   string
+	clusterName string
+	namespace   string
+
+	// credential is the login credential of the dashboard printed before opening it
+	credential string
 }
 
 func newOpenOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *openOptions {
@@ -273,6 +287,9 @@ func newOpenCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 			for _, d := range dashboards {
 				names = append(names, d.Name)
 			}
+			for alias := range dashboardAliases {
+				names = append(names, alias)
+			}
 			return names, cobra.ShellCompDirectiveNoFileComp
 		},
 		Run: func(cmd *cobra.Command, args []string) {
@@ -282,6 +299,8 @@ func newOpenCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Co
 	}
 
 	cmd.Flags().StringVar(&o.localPort, "port", "", "dashboard local port")
+	cmd.Flags().StringVar(&o.clusterName, "cluster", "", "Open the dashboard filtered to the cluster, only grafana and prometheus dashboards are supported")
+	util.RegisterClusterCompletionFunc(cmd, f)
 	cmd.Flags().Duration(podRunningTimeoutFlag, defaultPodExecTimeout,
 		"The time (like 5s, 2m, or 3h, higher than zero) to wait for at least one pod is running")
 	return cmd
@@ -293,6 +312,9 @@ func (o *openOptions) complete(cmd *cobra.Command, args []string) error {
 	}
 
 	o.name = args[0]
+	if name, ok := dashboardAliases[o.name]; ok {
+		o.name = name
+	}
 	client, err := o.factory.KubernetesClientSet()
 	if err != nil {
 		return err
@@ -313,6 +335,9 @@ func (o *openOptions) complete(cmd *cobra.Command, args []string) error {
 	if dash.Name == supportDirectDashboard && len(args) > 1 {
 		clusterType = args[1]
 	}
+	if err = o.completeCluster(client, dash); err != nil {
+		return err
+	}
 	if o.localPort == "" {
 		if o.name == lokiAddonName {
 			// revert the target port for loki dashboard
@@ -334,6 +359,15 @@ func (o *openOptions) run() error {
 		if err != nil {
 			return err
 		}
+		if o.clusterName != "" {
+			buildGrafanaClusterURL(&url, o.namespace, o.clusterName)
+		}
+	}
+	if o.name == prometheusServer && o.clusterName != "" {
+		buildPrometheusClusterURL(&url, o.namespace, o.clusterName)
+	}
+	if o.credential != "" {
+		fmt.Fprintln(o.Out, o.credential)
 	}
 	// customized by loki
 	if o.name == lokiAddonName {
@@ -352,6 +386,43 @@ func (o *openOptions) run() error {
 	return o.portForwardOptions.RunPortForward()
 }
 
+// completeCluster gets the engine type of the cluster for the grafana dashboard, and the
+// login credential of grafana
+func (o *openOptions) completeCluster(client kubernetes.Interface, dash *dashboard) error {
+	if dash.Name == grafanaAddonName {
+		if user, password, err := getGrafanaCredential(client, dash.Namespace); err != nil {
+			klog.V(1).Infof("failed to get the grafana credential: %v", err)
+		} else if user != "" {
+			o.credential = fmt.Sprintf("Grafana username: %s, password: %s", user, password)
+		}
+	}
+	if o.clusterName == "" {
+		return nil
+	}
+	if dash.Name != grafanaAddonName && dash.Name != prometheusServer {
+		return fmt.Errorf("--cluster is only supported by grafana and prometheus dashboards")
+	}
+
+	var err error
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	dynamic, err := o.factory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	c, err := cluster.GetClusterByName(dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	if dash.Name == grafanaAddonName && clusterType == "" {
+		if clusterType, err = getClusterEngineType(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getDashboardByName(name string) *dashboard {
 	for i, d := range dashboards {
 		if d.Name == name {
@@ -406,7 +477,7 @@ func getDashboardInfo(client *kubernetes.Clientset) error {
 func newFactory(namespace string) cmdutil.Factory {
 	cf := util.NewConfigFlagNoWarnings()
 	cf.Namespace = pointer.String(namespace)
-	return cmdutil.NewFactory(cf)
+	return cmdutil.NewFactory(util.NewCachedConfigFlags(cf))
 }
 
 type defaultPortForwarder struct {
@@ -0,0 +1,348 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	ui "github.com/replicatedhq/termui/v3"
+	"github.com/replicatedhq/termui/v3/widgets"
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmdcluster "github.com/apecloud/kbcli/pkg/cmd/cluster"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var tuiExample = templates.Examples(`
+	# open the terminal dashboard of the clusters in the current namespace
+	kbcli dashboard tui
+
+	# open the terminal dashboard of the clusters in all namespaces, and refresh it every 10 seconds
+	kbcli dashboard tui -A --refresh-interval=10s`)
+
+const tuiHelp = "[tab/←/→] switch view   [↑/↓] select   [enter] describe   [r] restart   [b] backup   [F5] refresh   [q] quit"
+
+// tuiAction is an operation triggered on the cluster of the selected row, it returns
+// the name of the created object.
+type tuiAction struct {
+	name   string
+	target tuiTarget
+	run    func(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) (string, error)
+}
+
+type tuiOptions struct {
+	factory cmdutil.Factory
+	genericiooptions.IOStreams
+
+	allNamespaces   bool
+	refreshInterval time.Duration
+
+	model       *tuiModel
+	refreshedAt time.Time
+	// message is the result of the last refresh or action shown in the footer
+	message string
+	// confirm is the action waiting for the confirmation of the user
+	confirm *tuiAction
+
+	// detail is the describe output of the selected object, the detail view is shown when it is not empty
+	detail       []string
+	detailTitle  string
+	detailOffset int
+
+	// describe describes the target, it can be replaced in tests
+	describe func(target tuiTarget) (string, error)
+}
+
+func newTUICmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &tuiOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "tui",
+		Short:   "Open the terminal dashboard to watch and operate the clusters, components, OpsRequests and backups.",
+		Example: tuiExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If present, list the resources across all namespaces.")
+	cmd.Flags().DurationVar(&o.refreshInterval, "refresh-interval", 5*time.Second, "The interval to refresh the resources.")
+	return cmd
+}
+
+func (o *tuiOptions) complete() error {
+	if o.refreshInterval <= 0 {
+		return fmt.Errorf("--refresh-interval must be greater than 0")
+	}
+	dynamic, err := o.factory.DynamicClient()
+	if err != nil {
+		return err
+	}
+	namespace := ""
+	if !o.allNamespaces {
+		if namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+			return err
+		}
+	}
+	o.model = newTUIModel(dynamic, namespace)
+	o.describe = o.describeTarget
+	return nil
+}
+
+func (o *tuiOptions) run() error {
+	if err := o.refresh(); err != nil {
+		return err
+	}
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to create terminal ui: %v", err)
+	}
+	defer ui.Close()
+
+	o.draw()
+	ticker := time.NewTicker(o.refreshInterval)
+	defer ticker.Stop()
+	uiEvents := ui.PollEvents()
+	for {
+		select {
+		case e := <-uiEvents:
+			if o.handleEvent(e.ID) {
+				return nil
+			}
+		case <-ticker.C:
+			if err := o.refresh(); err != nil {
+				o.message = fmt.Sprintf("failed to refresh: %v", err)
+			}
+		}
+		o.draw()
+	}
+}
+
+func (o *tuiOptions) refresh() error {
+	if err := o.model.refresh(); err != nil {
+		return err
+	}
+	o.refreshedAt = time.Now()
+	return nil
+}
+
+// handleEvent handles the key event, it returns true if the dashboard should quit
+func (o *tuiOptions) handleEvent(id string) bool {
+	if id == "<C-c>" {
+		return true
+	}
+	o.message = ""
+
+	// the confirmation of the action takes all keys
+	if o.confirm != nil {
+		action := o.confirm
+		o.confirm = nil
+		if id != "y" {
+			o.message = fmt.Sprintf("%s of cluster %s is canceled", action.name, action.target.cluster)
+			return false
+		}
+		o.runAction(action)
+		return false
+	}
+
+	// the detail view only supports scrolling and going back
+	if len(o.detail) > 0 {
+		switch id {
+		case "q", "<Escape>", "<Backspace>":
+			o.detail = nil
+		case "<Down>", "j":
+			if o.detailOffset < len(o.detail)-1 {
+				o.detailOffset++
+			}
+		case "<Up>", "k":
+			if o.detailOffset > 0 {
+				o.detailOffset--
+			}
+		}
+		return false
+	}
+
+	switch id {
+	case "q":
+		return true
+	case "<Tab>", "<Right>", "l":
+		o.model.switchView(1)
+	case "<Left>", "h":
+		o.model.switchView(-1)
+	case "1", "2", "3", "4":
+		o.model.switchView(int(id[0]-'1') - int(o.model.view))
+	case "<Down>", "j":
+		o.model.moveSelection(1)
+	case "<Up>", "k":
+		o.model.moveSelection(-1)
+	case "<F5>":
+		if err := o.refresh(); err != nil {
+			o.message = fmt.Sprintf("failed to refresh: %v", err)
+		}
+	case "<Enter>":
+		o.showDetail()
+	case "r":
+		o.confirmAction("restart", cmdcluster.RestartCluster)
+	case "b":
+		o.confirmAction("backup", cmdcluster.BackupCluster)
+	}
+	return false
+}
+
+func (o *tuiOptions) showDetail() {
+	target, ok := o.model.selectedTarget()
+	if !ok {
+		return
+	}
+	out, err := o.describe(target)
+	if err != nil {
+		o.message = fmt.Sprintf("failed to describe %s: %v", target.name, err)
+		return
+	}
+	o.detail = strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if target.view == clustersView || target.view == componentsView {
+		o.detailTitle = fmt.Sprintf("Cluster %s/%s", target.namespace, target.cluster)
+	} else {
+		o.detailTitle = fmt.Sprintf("%s %s/%s", strings.TrimSuffix(tuiViewNames[target.view], "s"), target.namespace, target.name)
+	}
+	o.detailOffset = 0
+}
+
+func (o *tuiOptions) confirmAction(name string, run func(cmdutil.Factory, genericiooptions.IOStreams, string, string) (string, error)) {
+	target, ok := o.model.selectedTarget()
+	if !ok || target.cluster == "" {
+		return
+	}
+	o.confirm = &tuiAction{name: name, target: target, run: run}
+}
+
+func (o *tuiOptions) runAction(action *tuiAction) {
+	var name string
+	_, err := captureStdout(func(out io.Writer) error {
+		var err error
+		name, err = action.run(o.factory, genericiooptions.IOStreams{In: o.In, Out: out, ErrOut: out}, action.target.namespace, action.target.cluster)
+		return err
+	})
+	if err != nil {
+		o.message = fmt.Sprintf("failed to %s cluster %s: %v", action.name, action.target.cluster, err)
+		return
+	}
+	o.message = fmt.Sprintf("%s of cluster %s is triggered by %s", action.name, action.target.cluster, name)
+	if err = o.refresh(); err != nil {
+		o.message = fmt.Sprintf("failed to refresh: %v", err)
+	}
+}
+
+// describeTarget describes the target with the describe commands, the clusters and
+// components are described by the cluster they belong to.
+func (o *tuiOptions) describeTarget(target tuiTarget) (string, error) {
+	out, err := captureStdout(func(out io.Writer) error {
+		streams := genericiooptions.IOStreams{In: o.In, Out: out, ErrOut: out}
+		switch target.view {
+		case opsView:
+			return cmdcluster.DescribeOps(o.factory, streams, target.namespace, target.name)
+		case backupsView:
+			return cmdcluster.DescribeBackup(o.factory, streams, target.namespace, target.name)
+		default:
+			return cmdcluster.DescribeCluster(o.factory, streams, target.namespace, target.cluster)
+		}
+	})
+	return text.StripEscape(out), err
+}
+
+// captureStdout calls fn with a writer and returns what is written to the writer and os.Stdout,
+// some describe functions print to os.Stdout directly which would mess up the terminal ui.
+func captureStdout(fn func(out io.Writer) error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	done := make(chan string, 1)
+	go func() {
+		buf := &bytes.Buffer{}
+		_, _ = io.Copy(buf, r)
+		r.Close()
+		done <- buf.String()
+	}()
+
+	saved := os.Stdout
+	os.Stdout = w
+	err = fn(w)
+	os.Stdout = saved
+	w.Close()
+	return <-done, err
+}
+
+func (o *tuiOptions) draw() {
+	termWidth, termHeight := ui.TerminalDimensions()
+	ui.Clear()
+
+	namespace := o.model.namespace
+	if namespace == "" {
+		namespace = "all namespaces"
+	}
+	tabs := widgets.NewTabPane(tuiViewNames...)
+	tabs.Title = fmt.Sprintf("KubeBlocks Dashboard (namespace: %s, refreshed at: %s)", namespace, o.refreshedAt.Format("15:04:05"))
+	tabs.ActiveTabIndex = int(o.model.view)
+	tabs.ActiveTabStyle = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierReverse)
+	tabs.SetRect(0, 0, termWidth, 3)
+
+	footer := widgets.NewParagraph()
+	switch {
+	case o.confirm != nil:
+		footer.Text = fmt.Sprintf("%s cluster %s/%s? [y/n]", o.confirm.name, o.confirm.target.namespace, o.confirm.target.cluster)
+	case len(o.detail) > 0:
+		footer.Text = "[↑/↓] scroll   [esc/q] back"
+	case o.message != "":
+		footer.Text = o.message
+	default:
+		footer.Text = tuiHelp
+	}
+	footer.SetRect(0, termHeight-3, termWidth, termHeight)
+
+	if len(o.detail) > 0 {
+		detail := widgets.NewParagraph()
+		detail.Title = o.detailTitle
+		detail.Text = strings.Join(o.detail[o.detailOffset:], "\n")
+		detail.SetRect(0, 3, termWidth, termHeight-3)
+		ui.Render(tabs, detail, footer)
+		return
+	}
+
+	// the title is drawn one column right to the rows, pad the rows to align them with the header
+	list := widgets.NewList()
+	list.Title = o.model.header()
+	for _, line := range o.model.lines() {
+		list.Rows = append(list.Rows, " "+line)
+	}
+	list.SelectedRow = o.model.selected
+	list.SelectedRowStyle = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierReverse)
+	list.SetRect(0, 3, termWidth, termHeight-3)
+	ui.Render(tabs, list, footer)
+}
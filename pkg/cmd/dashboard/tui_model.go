@@ -0,0 +1,270 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// tuiView is the resource view of the dashboard TUI
+type tuiView int
+
+const (
+	clustersView tuiView = iota
+	componentsView
+	opsView
+	backupsView
+)
+
+var tuiViewNames = []string{"Clusters", "Components", "OpsRequests", "Backups"}
+
+var tuiViewHeaders = map[tuiView][]string{
+	clustersView:   {"NAME", "NAMESPACE", "CLUSTER-DEFINITION", "VERSION", "STATUS", "CREATED-TIME"},
+	componentsView: {"NAME", "NAMESPACE", "CLUSTER", "TYPE", "REPLICAS", "STATUS"},
+	opsView:        {"NAME", "NAMESPACE", "TYPE", "CLUSTER", "STATUS", "PROGRESS", "CREATED-TIME"},
+	backupsView:    {"NAME", "NAMESPACE", "CLUSTER", "METHOD", "STATUS", "TOTAL-SIZE", "CREATED-TIME"},
+}
+
+// tuiTarget is the object a row of the view refers to, it is used to describe the
+// object or to trigger the operations on its cluster.
+type tuiTarget struct {
+	view      tuiView
+	name      string
+	namespace string
+	cluster   string
+}
+
+// tuiModel keeps the resources listed by the dashboard TUI and the selection state,
+// it has nothing to do with the terminal so that it can be tested without a terminal.
+type tuiModel struct {
+	dynamic dynamic.Interface
+	// namespace is empty when listing the resources in all namespaces
+	namespace string
+
+	view     tuiView
+	selected int
+	rows     map[tuiView][][]string
+	targets  map[tuiView][]tuiTarget
+}
+
+func newTUIModel(dynamic dynamic.Interface, namespace string) *tuiModel {
+	return &tuiModel{
+		dynamic:   dynamic,
+		namespace: namespace,
+		rows:      map[tuiView][][]string{},
+		targets:   map[tuiView][]tuiTarget{},
+	}
+}
+
+// refresh lists the clusters, OpsRequests and backups and rebuilds the rows of all views
+func (m *tuiModel) refresh() error {
+	var (
+		clusters []appsv1alpha1.Cluster
+		ops      []appsv1alpha1.OpsRequest
+		backups  []dpv1alpha1.Backup
+	)
+	if err := listObjects(m.dynamic, types.ClusterGVR(), m.namespace, &clusters); err != nil {
+		return err
+	}
+	if err := listObjects(m.dynamic, types.OpsGVR(), m.namespace, &ops); err != nil {
+		return err
+	}
+	if err := listObjects(m.dynamic, types.BackupGVR(), m.namespace, &backups); err != nil {
+		return err
+	}
+
+	m.rows, m.targets = map[tuiView][][]string{}, map[tuiView][]tuiTarget{}
+	for _, c := range clusters {
+		m.add(clustersView, tuiTarget{name: c.Name, namespace: c.Namespace, cluster: c.Name},
+			c.Name, c.Namespace, c.Spec.ClusterDefRef, c.Spec.ClusterVersionRef, string(c.Status.Phase), util.TimeFormat(&c.CreationTimestamp))
+		for _, comp := range c.Spec.ComponentSpecs {
+			m.add(componentsView, tuiTarget{name: comp.Name, namespace: c.Namespace, cluster: c.Name},
+				comp.Name, c.Namespace, c.Name, comp.ComponentDefRef, fmt.Sprintf("%d", comp.Replicas), string(c.Status.Components[comp.Name].Phase))
+		}
+	}
+	for _, o := range ops {
+		m.add(opsView, tuiTarget{name: o.Name, namespace: o.Namespace, cluster: o.Spec.ClusterRef},
+			o.Name, o.Namespace, string(o.Spec.Type), o.Spec.ClusterRef, string(o.Status.Phase), o.Status.Progress, util.TimeFormat(&o.CreationTimestamp))
+	}
+	for _, b := range backups {
+		m.add(backupsView, tuiTarget{name: b.Name, namespace: b.Namespace, cluster: b.Labels[constant.AppInstanceLabelKey]},
+			b.Name, b.Namespace, b.Labels[constant.AppInstanceLabelKey], b.Spec.BackupMethod, string(b.Status.Phase), b.Status.TotalSize, util.TimeFormat(&b.CreationTimestamp))
+	}
+	m.setSelected(m.selected)
+	return nil
+}
+
+// listObjects lists the objects sorted by namespace and name, and converts them to the typed items
+func listObjects[T any](dynamic dynamic.Interface, gvr schema.GroupVersionResource, namespace string, items *[]T) error {
+	objs, err := dynamic.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(objs.Items, func(i, j int) bool {
+		if objs.Items[i].GetNamespace() != objs.Items[j].GetNamespace() {
+			return objs.Items[i].GetNamespace() < objs.Items[j].GetNamespace()
+		}
+		return objs.Items[i].GetName() < objs.Items[j].GetName()
+	})
+	for _, obj := range objs.Items {
+		var item T
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &item); err != nil {
+			return err
+		}
+		*items = append(*items, item)
+	}
+	return nil
+}
+
+func (m *tuiModel) add(view tuiView, target tuiTarget, row ...string) {
+	target.view = view
+	for i := range row {
+		row[i] = util.CheckEmpty(row[i])
+	}
+	m.rows[view] = append(m.rows[view], row)
+	m.targets[view] = append(m.targets[view], target)
+}
+
+// switchView switches to the next view when step is positive, or the previous one when negative
+func (m *tuiModel) switchView(step int) {
+	n := len(tuiViewNames)
+	m.view = tuiView(((int(m.view)+step)%n + n) % n)
+	m.selected = 0
+}
+
+// moveSelection moves the selected row by step, it wraps around at both ends
+func (m *tuiModel) moveSelection(step int) {
+	n := len(m.rows[m.view])
+	if n == 0 {
+		m.selected = 0
+		return
+	}
+	m.setSelected(((m.selected+step)%n + n) % n)
+}
+
+func (m *tuiModel) setSelected(i int) {
+	n := len(m.rows[m.view])
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	m.selected = i
+}
+
+// selectedTarget returns the object of the selected row, false if the view is empty
+func (m *tuiModel) selectedTarget() (tuiTarget, bool) {
+	targets := m.targets[m.view]
+	if m.selected >= len(targets) {
+		return tuiTarget{}, false
+	}
+	return targets[m.selected], true
+}
+
+// header returns the aligned header line of the current view
+func (m *tuiModel) header() string {
+	header := tuiViewHeaders[m.view]
+	cells := padCells(header, columnWidths(append([][]string{header}, m.rows[m.view]...)))
+	return strings.TrimRight(strings.Join(cells, columnSeparator), " ")
+}
+
+// lines returns the aligned rows of the current view, the status is colored with the
+// termui style markup when the color is enabled.
+func (m *tuiModel) lines() []string {
+	rows := append([][]string{tuiViewHeaders[m.view]}, m.rows[m.view]...)
+	widths := columnWidths(rows)
+	col := statusColumn(m.view)
+	lines := make([]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		cells := padCells(row, widths)
+		if c := statusColor(row[col]); c != "" {
+			cells[col] = fmt.Sprintf("[%s](fg:%s)", cells[col], c)
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, columnSeparator), " "))
+	}
+	return lines
+}
+
+const columnSeparator = "   "
+
+// columnWidths returns the width of the widest cell of each column
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if l := len([]rune(cell)); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+	return widths
+}
+
+// padCells pads the cells of the row to the widths of the columns
+func padCells(row []string, widths []int) []string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = cell + strings.Repeat(" ", widths[i]-len([]rune(cell)))
+	}
+	return cells
+}
+
+// statusColumn returns the index of the STATUS column of the view
+func statusColumn(view tuiView) int {
+	for i, h := range tuiViewHeaders[view] {
+		if h == "STATUS" {
+			return i
+		}
+	}
+	return -1
+}
+
+// statusColor returns the termui color of the status following the color theme, empty
+// if the color is disabled or the theme color has no termui counterpart.
+func statusColor(status string) string {
+	if !printer.ColorEnabled() {
+		return ""
+	}
+	switch c := printer.StatusColor(status); c {
+	case "red", "green", "yellow", "blue", "magenta", "cyan", "white":
+		return c
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("dashboard tui", func() {
+	const clusterName = "test-cluster"
+
+	fakeOps := func() *appsv1alpha1.OpsRequest {
+		ops := &appsv1alpha1.OpsRequest{}
+		ops.APIVersion = types.OpsGVR().GroupVersion().String()
+		ops.Kind = types.KindOps
+		ops.Name = "test-ops"
+		ops.Namespace = clitesting.Namespace
+		ops.Spec.ClusterRef = clusterName
+		ops.Spec.Type = appsv1alpha1.RestartType
+		ops.Status.Phase = appsv1alpha1.OpsSucceedPhase
+		ops.Status.Progress = "2/2"
+		ops.SetCreationTimestamp(metav1.Now())
+		return ops
+	}
+
+	newOptions := func() *tuiOptions {
+		cluster := clitesting.FakeCluster(clusterName, clitesting.Namespace)
+		dynamic := clitesting.FakeDynamicClient(cluster, fakeOps(), clitesting.FakeBackupWithCluster(cluster, "test-backup"))
+		o := &tuiOptions{model: newTUIModel(dynamic, clitesting.Namespace)}
+		o.describe = func(target tuiTarget) (string, error) {
+			return fmt.Sprintf("describe %s\nline 2\n", target.name), nil
+		}
+		Expect(o.refresh()).Should(Succeed())
+		return o
+	}
+
+	It("list the resources of all views", func() {
+		o := newOptions()
+		m := o.model
+		Expect(m.rows[clustersView]).Should(HaveLen(1))
+		Expect(m.rows[clustersView][0]).Should(ContainElements(clusterName, clitesting.Namespace, string(appsv1alpha1.RunningClusterPhase)))
+		Expect(m.rows[componentsView]).Should(HaveLen(2))
+		Expect(m.targets[componentsView][1]).Should(Equal(tuiTarget{view: componentsView, name: clitesting.ComponentName + "-1", namespace: clitesting.Namespace, cluster: clusterName}))
+		Expect(m.rows[opsView][0]).Should(ContainElements("test-ops", "Restart", clusterName, "Succeed", "2/2"))
+		Expect(m.rows[backupsView][0]).Should(ContainElements("test-backup", clusterName))
+
+		lines := m.lines()
+		Expect(lines).Should(HaveLen(1))
+		Expect(strings.Index(lines[0], clitesting.Namespace)).Should(Equal(strings.Index(m.header(), "NAMESPACE")))
+	})
+
+	It("switch views and select rows", func() {
+		o := newOptions()
+		m := o.model
+		Expect(o.handleEvent("<Tab>")).Should(BeFalse())
+		Expect(m.view).Should(Equal(componentsView))
+		Expect(o.handleEvent("<Down>")).Should(BeFalse())
+		Expect(m.selected).Should(Equal(1))
+		// wrap around at the end
+		o.handleEvent("<Down>")
+		Expect(m.selected).Should(Equal(0))
+		o.handleEvent("<Up>")
+		Expect(m.selected).Should(Equal(1))
+
+		o.handleEvent("<Left>")
+		Expect(m.view).Should(Equal(clustersView))
+		Expect(m.selected).Should(Equal(0))
+		o.handleEvent("<Left>")
+		Expect(m.view).Should(Equal(backupsView))
+		o.handleEvent("3")
+		Expect(m.view).Should(Equal(opsView))
+		target, ok := m.selectedTarget()
+		Expect(ok).Should(BeTrue())
+		Expect(target.name).Should(Equal("test-ops"))
+
+		Expect(o.handleEvent("q")).Should(BeTrue())
+		Expect(o.handleEvent("<C-c>")).Should(BeTrue())
+	})
+
+	It("drill down into the describe view", func() {
+		o := newOptions()
+		o.handleEvent("4")
+		o.handleEvent("<Enter>")
+		Expect(o.detail).Should(Equal([]string{"describe test-backup", "line 2"}))
+		Expect(o.detailTitle).Should(Equal(fmt.Sprintf("Backup %s/test-backup", clitesting.Namespace)))
+		o.handleEvent("<Down>")
+		o.handleEvent("<Down>")
+		Expect(o.detailOffset).Should(Equal(1))
+		// q goes back to the list instead of quitting
+		Expect(o.handleEvent("q")).Should(BeFalse())
+		Expect(o.detail).Should(BeEmpty())
+		Expect(o.model.view).Should(Equal(backupsView))
+	})
+
+	It("confirm the operations", func() {
+		o := newOptions()
+		o.handleEvent("r")
+		Expect(o.confirm).ShouldNot(BeNil())
+		Expect(o.confirm.name).Should(Equal("restart"))
+		Expect(o.handleEvent("n")).Should(BeFalse())
+		Expect(o.confirm).Should(BeNil())
+		Expect(o.message).Should(ContainSubstring("canceled"))
+
+		var triggered string
+		o.confirm = &tuiAction{
+			name:   "backup",
+			target: tuiTarget{view: clustersView, name: clusterName, namespace: clitesting.Namespace, cluster: clusterName},
+			run: func(f cmdutil.Factory, streams genericiooptions.IOStreams, namespace, name string) (string, error) {
+				triggered = namespace + "/" + name
+				fmt.Println("created")
+				return "test-backup-1", nil
+			},
+		}
+		o.handleEvent("y")
+		Expect(triggered).Should(Equal(clitesting.Namespace + "/" + clusterName))
+		Expect(o.message).Should(Equal(fmt.Sprintf("backup of cluster %s is triggered by test-backup-1", clusterName)))
+	})
+})
@@ -29,6 +29,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/cmd/cluster"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
@@ -47,6 +48,9 @@ var (
 
 		# create a backup from a parent backup
 		kbcli dp backup mybackup --cluster mycluster --parent-backup myparentbackup
+
+		# create a backup and wait for it to be completed
+		kbcli dp backup mybackup --cluster mycluster --wait --timeout 30m
 	`)
 
 	deleteBackupExample = templates.Examples(`
@@ -57,6 +61,12 @@ var (
 	describeBackupExample = templates.Examples(`
 		# describe a backup
 		kbcli dp describe-backup mybackup
+
+		# describe multiple backups
+		kbcli dp describe-backup mybackup mybackup2
+
+		# describe the backups of the cluster mycluster
+		kbcli dp describe-backup -l app.kubernetes.io/instance=mycluster
 	`)
 
 	listBackupExample = templates.Examples(`
@@ -101,9 +111,9 @@ func newBackupCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 				o.Args = []string{clusterName}
 			}
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.CompleteBackup())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CompleteBackup())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -113,6 +123,8 @@ func newBackupCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 	cmd.Flags().StringVar(&o.BackupSpec.DeletionPolicy, "deletion-policy", "Delete", "Deletion policy for backup, determine whether the backup content in backup repo will be deleted after the backup is deleted, supported values: [Delete, Retain]")
 	cmd.Flags().StringVar(&o.BackupSpec.RetentionPeriod, "retention-period", "", "Retention period for backup, supported values: [1y, 1mo, 1d, 1h, 1m] or combine them [1y1mo1d1h1m], if not specified, the backup will not be automatically deleted, you need to manually delete it.")
 	cmd.Flags().StringVar(&o.BackupSpec.ParentBackupName, "parent-backup", "", "Parent backup name, used for incremental backup")
+	o.AddDryRunFlags(cmd, false)
+	o.AddWaitFlags(cmd)
 	util.RegisterClusterCompletionFunc(cmd, f)
 	o.RegisterBackupFlagCompletionFunc(cmd, f)
 
@@ -122,6 +134,7 @@ func newBackupCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *co
 func newBackupDeleteCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
 	o := action.NewDeleteOptions(f, streams, types.BackupGVR())
 	clusterName := ""
+	deleteProtected := false
 	cmd := &cobra.Command{
 		Use:               "delete-backup",
 		Short:             "Delete a backup.",
@@ -130,26 +143,27 @@ func newBackupDeleteCommand(f cmdutil.Factory, streams genericiooptions.IOStream
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Names = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			util.CheckErr(completeForDeleteBackup(o, clusterName))
+			util.CheckErr(completeForDeleteBackup(o, clusterName, deleteProtected))
 			util.CheckErr(o.Run())
 		},
 	}
 
 	o.AddFlags(cmd)
 	cmd.Flags().StringVar(&clusterName, "cluster", "", "The cluster name.")
+	cluster.AddDeleteProtectedFlag(cmd, &deleteProtected)
 	util.RegisterClusterCompletionFunc(cmd, f)
 
 	return cmd
 }
 
-func completeForDeleteBackup(o *action.DeleteOptions, cluster string) error {
+func completeForDeleteBackup(o *action.DeleteOptions, clusterName string, deleteProtected bool) error {
 	if o.Force && len(o.Names) == 0 {
-		if cluster == "" {
-			return fmt.Errorf("must give a backup name or cluster name")
+		if clusterName == "" {
+			return clierrors.NewValidation("must give a backup name or cluster name")
 		}
-		o.LabelSelector = util.BuildLabelSelectorByNames(o.LabelSelector, []string{cluster})
+		o.LabelSelector = util.BuildLabelSelectorByNames(o.LabelSelector, []string{clusterName})
 	}
-	return nil
+	return cluster.CheckProtectedBackupsToDelete(o, deleteProtected)
 }
 
 func newBackupDescribeCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -159,8 +173,8 @@ func newBackupDescribeCommand(f cmdutil.Factory, streams genericiooptions.IOStre
 		Gvr:       types.BackupGVR(),
 	}
 	cmd := &cobra.Command{
-		Use:               "describe-backup NAME",
-		Short:             "Describe a backup",
+		Use:               "describe-backup NAME...",
+		Short:             "Describe backups",
 		Aliases:           []string{"desc-backup"},
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.BackupGVR()),
 		Example:           describeBackupExample,
@@ -170,6 +184,7 @@ func newBackupDescribeCommand(f cmdutil.Factory, streams genericiooptions.IOStre
 			util.CheckErr(o.Run())
 		},
 	}
+	o.AddFlags(cmd)
 	return cmd
 }
 
@@ -188,12 +203,13 @@ func newListBackupCommand(f cmdutil.Factory, streams genericiooptions.IOStreams)
 			}
 			o.Names = args
 			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(cluster.PrintBackupList(*o))
+			util.CheckErr(o.Complete())
+			util.CheckErr(cluster.PrintBackupList(*o))
 		},
 	}
 	o.AddFlags(cmd, true)
 	cmd.Flags().StringVar(&clusterName, "cluster", "", "List backups in the specified cluster")
+	o.TableOptions.AddFlags(cmd)
 	util.RegisterClusterCompletionFunc(cmd, f)
 
 	return cmd
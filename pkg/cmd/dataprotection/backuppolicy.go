@@ -74,6 +74,7 @@ func newListBackupPolicyCmd(f cmdutil.Factory, streams genericclioptions.IOStrea
 	}
 	cmd.Flags().StringVar(&clusterName, "cluster", "", "The cluster name")
 	o.AddFlags(cmd)
+	o.TableOptions.AddFlags(cmd)
 
 	return cmd
 }
@@ -39,6 +39,7 @@ func NewDataProtectionCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 		newRestoreCommand(f, streams),
 		newListBackupPolicyCmd(f, streams),
 		newDescribeBackupPolicyCmd(f, streams),
+		newVeleroHookCmd(f, streams),
 	)
 	return cmd
 }
@@ -37,7 +37,10 @@ import (
 var (
 	createRestoreExample = templates.Examples(`
 		# restore a new cluster from a backup
-		kbcli dp restore mybackup --cluster cluster-name`)
+		kbcli dp restore mybackup --cluster cluster-name
+
+		# restore a new cluster from a backup and wait for the cluster to be running
+		kbcli dp restore mybackup --cluster cluster-name --wait`)
 )
 
 func newRestoreCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -80,5 +83,8 @@ func newRestoreCommand(f cmdutil.Factory, streams genericiooptions.IOStreams) *c
 	cmd.Flags().StringVar(&clusterName, "cluster", "", "The cluster to restore")
 	cmd.Flags().StringVar(&o.RestoreSpec.RestoreTimeStr, "restore-to-time", "", "point in time recovery(PITR)")
 	cmd.Flags().StringVar(&o.RestoreSpec.VolumeRestorePolicy, "volume-restore-policy", "Parallel", "the volume claim restore policy, supported values: [Serial, Parallel]")
+	util.RegisterFlagCompletionFunc(cmd, "volume-restore-policy", util.ValuesCompletionFunc("Serial", "Parallel"))
+	o.AddDryRunFlags(cmd, false)
+	o.AddWaitFlags(cmd)
 	return cmd
 }
@@ -0,0 +1,413 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var (
+	veleroHookInstallExample = templates.Examples(`
+		# Install the velero resource policies and the paused schedule capturing KubeBlocks resources,
+		# then create a backup by: velero backup create --from-schedule kubeblocks
+		kbcli dataprotection velero-hook install
+
+		# Install a schedule backing up the namespaces default and prod every day
+		kbcli dataprotection velero-hook install --namespaces default,prod --schedule "0 2 * * *"`)
+
+	veleroHookRestoreExample = templates.Examples(`
+		# Restore the velero backup kubeblocks-20231010 in the order of definitions, clusters and backups
+		kbcli dataprotection velero-hook restore --from-backup kubeblocks-20231010`)
+)
+
+const (
+	veleroScheduleName       = "kubeblocks"
+	veleroResourcePolicyName = "kubeblocks-velero-resource-policies"
+	veleroPausedSchedule     = "@every 24h"
+)
+
+// veleroRestoreStages are the stages to restore the KubeBlocks resources, the definitions must
+// be restored before the clusters referring to them, and the backups are restored at last to
+// avoid triggering the backup controllers before the clusters are ready.
+var veleroRestoreStages = []struct {
+	name      string
+	resources []string
+}{
+	{
+		name: "definitions",
+		resources: []string{
+			"storageclasses.storage.k8s.io",
+			"clusterdefinitions.apps.kubeblocks.io",
+			"clusterversions.apps.kubeblocks.io",
+			"componentdefinitions.apps.kubeblocks.io",
+			"configconstraints.apps.kubeblocks.io",
+			"actionsets.dataprotection.kubeblocks.io",
+			"backuprepos.dataprotection.kubeblocks.io",
+		},
+	},
+	{
+		name: "clusters",
+		resources: []string{
+			"serviceaccounts",
+			"secrets",
+			"configmaps",
+			"persistentvolumes",
+			"persistentvolumeclaims",
+			"clusters.apps.kubeblocks.io",
+		},
+	},
+	{
+		name: "backups",
+		resources: []string{
+			"backuppolicies.dataprotection.kubeblocks.io",
+			"backupschedules.dataprotection.kubeblocks.io",
+			"backups.dataprotection.kubeblocks.io",
+		},
+	},
+}
+
+// veleroRestorePollInterval is the interval to check the velero restore phase
+var veleroRestorePollInterval = 5 * time.Second
+
+type veleroHookOptions struct {
+	veleroNamespace string
+	namespaces      []string
+	schedule        string
+	backupName      string
+	timeout         time.Duration
+
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	genericiooptions.IOStreams
+}
+
+func newVeleroHookCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "velero-hook",
+		Short: "Integrate KubeBlocks resources with the velero cluster-level backups.",
+	}
+	cmd.AddCommand(
+		newVeleroHookInstallCmd(f, streams),
+		newVeleroHookRestoreCmd(f, streams),
+	)
+	return cmd
+}
+
+func newVeleroHookInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &veleroHookOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "install",
+		Short:   "Install the velero resource policies and the schedule to capture KubeBlocks resources consistently.",
+		Example: veleroHookInstallExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.runInstall())
+		},
+	}
+	cmd.Flags().StringVar(&o.veleroNamespace, "velero-namespace", "velero", "The namespace velero is installed in")
+	cmd.Flags().StringSliceVar(&o.namespaces, "namespaces", nil, "The namespaces to back up, default to all namespaces")
+	cmd.Flags().StringVar(&o.schedule, "schedule", "", "The cron expression of the velero schedule, if not specified, the schedule is paused and used as the template of the manual backups")
+	return cmd
+}
+
+func newVeleroHookRestoreCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &veleroHookOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "restore",
+		Short:   "Restore the velero backup in the order of definitions, clusters and backups.",
+		Example: veleroHookRestoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.runRestore())
+		},
+	}
+	cmd.Flags().StringVar(&o.veleroNamespace, "velero-namespace", "velero", "The namespace velero is installed in")
+	cmd.Flags().StringVar(&o.backupName, "from-backup", "", "The velero backup to restore from")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 30*time.Minute, "The timeout to wait for each restore stage to complete")
+	util.CheckErr(cmd.MarkFlagRequired("from-backup"))
+	return cmd
+}
+
+func (o *veleroHookOptions) complete(f cmdutil.Factory) error {
+	var err error
+	if o.client, err = f.KubernetesClientSet(); err != nil {
+		return err
+	}
+	o.dynamic, err = f.DynamicClient()
+	return err
+}
+
+func (o *veleroHookOptions) runInstall() error {
+	storageClasses, err := o.getKubeBlocksStorageClasses()
+	if err != nil {
+		return err
+	}
+	policies, err := buildVeleroResourcePolicies(o.veleroNamespace, storageClasses)
+	if err != nil {
+		return err
+	}
+	objs := []struct {
+		gvr schema.GroupVersionResource
+		obj *unstructured.Unstructured
+	}{
+		{types.ConfigmapGVR(), policies},
+		{types.VeleroScheduleGVR(), buildVeleroSchedule(o.veleroNamespace, o.namespaces, o.schedule)},
+	}
+	for _, item := range objs {
+		if action.ExportEnabled() {
+			if err = action.ExportObject(item.obj, o.Out); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = applyObject(o.dynamic, item.gvr, item.obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to apply %s %s, please make sure velero is installed in namespace %s: %v",
+					item.obj.GetKind(), item.obj.GetName(), o.veleroNamespace, err)
+			}
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s %s/%s applied\n", item.obj.GetKind(), o.veleroNamespace, item.obj.GetName())
+	}
+	if o.schedule == "" {
+		fmt.Fprintf(o.Out, "\nThe schedule is paused, create a backup by:\n\tvelero backup create --from-schedule %s -n %s\n", veleroScheduleName, o.veleroNamespace)
+	}
+	return nil
+}
+
+// getKubeBlocksStorageClasses returns the storage classes of the PVCs managed by KubeBlocks
+func (o *veleroHookOptions) getKubeBlocksStorageClasses() ([]string, error) {
+	pvcs, err := o.client.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constant.AppManagedByLabelKey, constant.AppName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	set := map[string]struct{}{}
+	for _, pvc := range pvcs.Items {
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			set[*pvc.Spec.StorageClassName] = struct{}{}
+		}
+	}
+	var classes []string
+	for sc := range set {
+		classes = append(classes, sc)
+	}
+	sort.Strings(classes)
+	return classes, nil
+}
+
+// buildVeleroResourcePolicies builds the velero resource policies ConfigMap, the volumes of
+// KubeBlocks are snapshotted to keep them consistent with the CRs captured in the same backup.
+func buildVeleroResourcePolicies(namespace string, storageClasses []string) (*unstructured.Unstructured, error) {
+	policy := map[string]interface{}{
+		"version": "v1",
+	}
+	if len(storageClasses) > 0 {
+		policy["volumePolicies"] = []interface{}{
+			map[string]interface{}{
+				"conditions": map[string]interface{}{"storageClass": storageClasses},
+				"action":     map[string]interface{}{"type": "snapshot"},
+			},
+		}
+	}
+	data, err := yaml.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      veleroResourcePolicyName,
+			Namespace: namespace,
+			Labels:    map[string]string{constant.AppManagedByLabelKey: constant.AppName},
+		},
+		Data: map[string]string{"policies.yaml": string(data)},
+	}
+	return util.ConvertObjToUnstructured(cm)
+}
+
+// buildVeleroSchedule builds the velero schedule capturing the cluster-scoped KubeBlocks
+// definitions, and flushing the file system buffers of the database pods before backup.
+func buildVeleroSchedule(namespace string, namespaces []string, schedule string) *unstructured.Unstructured {
+	includedNamespaces := []interface{}{"*"}
+	if len(namespaces) > 0 {
+		includedNamespaces = nil
+		for _, ns := range namespaces {
+			includedNamespaces = append(includedNamespaces, ns)
+		}
+	}
+	paused := schedule == ""
+	if paused {
+		schedule = veleroPausedSchedule
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": fmt.Sprintf("%s/%s", types.VeleroAPIGroup, types.VeleroAPIVersion),
+		"kind":       "Schedule",
+		"metadata": map[string]interface{}{
+			"name":      veleroScheduleName,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{constant.AppManagedByLabelKey: constant.AppName},
+		},
+		"spec": map[string]interface{}{
+			"schedule": schedule,
+			"paused":   paused,
+			"template": map[string]interface{}{
+				"includedNamespaces":      includedNamespaces,
+				"includeClusterResources": true,
+				"snapshotVolumes":         true,
+				"resourcePolicy": map[string]interface{}{
+					"kind": "configmap",
+					"name": veleroResourcePolicyName,
+				},
+				"hooks": map[string]interface{}{
+					"resources": []interface{}{
+						map[string]interface{}{
+							"name":               "kubeblocks-flush",
+							"includedNamespaces": includedNamespaces,
+							"labelSelector": map[string]interface{}{
+								"matchLabels": map[string]interface{}{constant.AppManagedByLabelKey: constant.AppName},
+							},
+							"pre": []interface{}{
+								map[string]interface{}{
+									"exec": map[string]interface{}{
+										"command": []interface{}{"/bin/sh", "-c", "sync"},
+										"onError": "Continue",
+										"timeout": "30s",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+// applyObject creates the object or updates it if it already exists
+func applyObject(dynamic dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	client := dynamic.Resource(gvr).Namespace(obj.GetNamespace())
+	_, err := client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	return err
+}
+
+func buildVeleroRestore(namespace, backupName, stage string, resources []string) *unstructured.Unstructured {
+	var included []interface{}
+	for _, r := range resources {
+		included = append(included, r)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": fmt.Sprintf("%s/%s", types.VeleroAPIGroup, types.VeleroAPIVersion),
+		"kind":       "Restore",
+		"metadata": map[string]interface{}{
+			"name":      fmt.Sprintf("%s-%s-%d", backupName, stage, time.Now().Unix()),
+			"namespace": namespace,
+			"labels":    map[string]interface{}{constant.AppManagedByLabelKey: constant.AppName},
+		},
+		"spec": map[string]interface{}{
+			"backupName":              backupName,
+			"includedResources":       included,
+			"includeClusterResources": true,
+			"restorePVs":              true,
+			"existingResourcePolicy":  "none",
+		},
+	}}
+}
+
+// runRestore restores the velero backup stage by stage, the next stage is started after the
+// previous one completes.
+func (o *veleroHookOptions) runRestore() error {
+	if _, err := o.dynamic.Resource(types.VeleroBackupGVR()).Namespace(o.veleroNamespace).Get(context.TODO(), o.backupName, metav1.GetOptions{}); err != nil {
+		return err
+	}
+	for _, stage := range veleroRestoreStages {
+		restore := buildVeleroRestore(o.veleroNamespace, o.backupName, stage.name, stage.resources)
+		if _, err := o.dynamic.Resource(types.VeleroRestoreGVR()).Namespace(o.veleroNamespace).Create(context.TODO(), restore, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Restoring %s by velero restore %s\n", stage.name, restore.GetName())
+		phase, err := o.waitVeleroRestore(restore.GetName())
+		if err != nil {
+			return fmt.Errorf("failed to wait velero restore %s: %v", restore.GetName(), err)
+		}
+		switch phase {
+		case "Completed":
+		case "PartiallyFailed":
+			fmt.Fprintf(o.Out, "Warning: velero restore %s partially failed, check it by: velero restore describe %s -n %s\n",
+				restore.GetName(), restore.GetName(), o.veleroNamespace)
+		default:
+			return fmt.Errorf("velero restore %s is %s, check it by: velero restore describe %s -n %s",
+				restore.GetName(), phase, restore.GetName(), o.veleroNamespace)
+		}
+	}
+	fmt.Fprintf(o.Out, "Velero backup %s is restored\n", o.backupName)
+	return nil
+}
+
+// waitVeleroRestore waits the velero restore to finish and returns its final phase
+func (o *veleroHookOptions) waitVeleroRestore(name string) (string, error) {
+	var phase string
+	err := wait.PollUntilContextTimeout(context.Background(), veleroRestorePollInterval, o.timeout, true, func(ctx context.Context) (bool, error) {
+		obj, err := o.dynamic.Resource(types.VeleroRestoreGVR()).Namespace(o.veleroNamespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+		switch phase {
+		case "Completed", "PartiallyFailed", "Failed", "FailedValidation":
+			return true, nil
+		}
+		return false, nil
+	})
+	return phase, err
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("velero hook", func() {
+	const veleroNamespace = "velero"
+
+	var (
+		out     *bytes.Buffer
+		o       *veleroHookOptions
+		dynamic *dynamicfakeclient.FakeDynamicClient
+	)
+
+	BeforeEach(func() {
+		sc := "csi-hostpath-sc"
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data-mycluster-mysql-0",
+				Namespace: "default",
+				Labels:    map[string]string{constant.AppManagedByLabelKey: constant.AppName},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &sc},
+		}
+		out = &bytes.Buffer{}
+		dynamic = clitesting.FakeDynamicClient()
+		o = &veleroHookOptions{
+			veleroNamespace: veleroNamespace,
+			timeout:         time.Second,
+			client:          kubefakeclient.NewSimpleClientset(pvc),
+			dynamic:         dynamic,
+			IOStreams:       genericiooptions.IOStreams{Out: out, ErrOut: out},
+		}
+		veleroRestorePollInterval = 10 * time.Millisecond
+	})
+
+	It("install", func() {
+		Expect(o.runInstall()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("velero backup create --from-schedule kubeblocks"))
+
+		obj, err := o.dynamic.Resource(types.ConfigmapGVR()).Namespace(veleroNamespace).Get(context.TODO(), veleroResourcePolicyName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		policies, _, _ := unstructured.NestedString(obj.Object, "data", "policies.yaml")
+		Expect(policies).Should(ContainSubstring("csi-hostpath-sc"))
+		Expect(policies).Should(ContainSubstring("type: snapshot"))
+
+		schedule, err := o.dynamic.Resource(types.VeleroScheduleGVR()).Namespace(veleroNamespace).Get(context.TODO(), veleroScheduleName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		paused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused")
+		Expect(paused).Should(BeTrue())
+
+		// install again with a schedule updates the existing one
+		o.schedule = "0 2 * * *"
+		o.namespaces = []string{"default"}
+		Expect(o.runInstall()).Should(Succeed())
+		schedule, err = o.dynamic.Resource(types.VeleroScheduleGVR()).Namespace(veleroNamespace).Get(context.TODO(), veleroScheduleName, metav1.GetOptions{})
+		Expect(err).Should(Succeed())
+		Expect(schedule.Object["spec"]).Should(HaveKeyWithValue("schedule", "0 2 * * *"))
+		Expect(schedule.Object["spec"]).Should(HaveKeyWithValue("paused", false))
+		namespaces, _, _ := unstructured.NestedStringSlice(schedule.Object, "spec", "template", "includedNamespaces")
+		Expect(namespaces).Should(Equal([]string{"default"}))
+	})
+
+	It("restore", func() {
+		Expect(o.runRestore()).Should(HaveOccurred())
+
+		o.backupName = "kubeblocks-backup"
+		backup := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "velero.io/v1",
+			"kind":       "Backup",
+			"metadata":   map[string]interface{}{"name": o.backupName, "namespace": veleroNamespace},
+		}}
+		_, err := o.dynamic.Resource(types.VeleroBackupGVR()).Namespace(veleroNamespace).Create(context.TODO(), backup, metav1.CreateOptions{})
+		Expect(err).Should(Succeed())
+
+		// complete the restores once they are created and record the restored resources
+		var stages [][]interface{}
+		dynamic.PrependReactor("create", "restores", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			obj := action.(clienttesting.CreateAction).GetObject().(*unstructured.Unstructured)
+			stages = append(stages, obj.Object["spec"].(map[string]interface{})["includedResources"].([]interface{}))
+			Expect(unstructured.SetNestedField(obj.Object, "Completed", "status", "phase")).Should(Succeed())
+			return false, nil, nil
+		})
+		Expect(o.runRestore()).Should(Succeed())
+		Expect(stages).Should(HaveLen(len(veleroRestoreStages)))
+		Expect(stages[0]).Should(ContainElement("clusterdefinitions.apps.kubeblocks.io"))
+		Expect(stages[1]).Should(ContainElement("clusters.apps.kubeblocks.io"))
+		Expect(stages[2]).Should(ContainElement("backups.dataprotection.kubeblocks.io"))
+		Expect(out.String()).Should(ContainSubstring("Velero backup kubeblocks-backup is restored"))
+	})
+})
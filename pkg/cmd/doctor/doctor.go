@@ -0,0 +1,392 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var doctorExample = templates.Examples(`
+	# check whether the current kubernetes context is ready for kbcli and KubeBlocks
+	kbcli doctor
+
+	# check the permissions in the specified namespace
+	kbcli doctor -n demo`)
+
+type checkStatus string
+
+const (
+	statusPass checkStatus = "PASS"
+	statusWarn checkStatus = "WARN"
+	statusFail checkStatus = "FAIL"
+)
+
+// checkResult is the result of a doctor check
+type checkResult struct {
+	name    string
+	status  checkStatus
+	message string
+}
+
+// resourceAccess is the resource kbcli accesses and the verbs it requires
+type resourceAccess struct {
+	gvr           schema.GroupVersionResource
+	clusterScoped bool
+	verbs         []string
+}
+
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+	// requiredAccesses are the resources used by kbcli commands
+	requiredAccesses = []resourceAccess{
+		{gvr: types.ClusterGVR(), verbs: writeVerbs},
+		{gvr: types.OpsGVR(), verbs: writeVerbs},
+		{gvr: types.BackupGVR(), verbs: writeVerbs},
+		{gvr: types.BackupPolicyGVR(), verbs: writeVerbs},
+		{gvr: types.BackupScheduleGVR(), verbs: writeVerbs},
+		{gvr: types.RestoreGVR(), verbs: writeVerbs},
+		{gvr: types.ClusterDefGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.ClusterVersionGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.BackupPolicyTemplateGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.ActionSetGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.BackupRepoGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.AddonGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.ConfigConstraintGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.StorageClassGVR(), clusterScoped: true, verbs: readVerbs},
+		{gvr: types.PodGVR(), verbs: readVerbs},
+		{gvr: schema.GroupVersionResource{Version: types.K8sCoreAPIVersion, Resource: "pods/exec"}, verbs: []string{"create"}},
+		{gvr: types.ServiceGVR(), verbs: readVerbs},
+		{gvr: types.SecretGVR(), verbs: readVerbs},
+		{gvr: types.ConfigmapGVR(), verbs: readVerbs},
+		{gvr: types.PVCGVR(), verbs: readVerbs},
+		{gvr: types.RSMGVR(), verbs: readVerbs},
+	}
+)
+
+type doctorOptions struct {
+	factory   cmdutil.Factory
+	client    kubernetes.Interface
+	dynamic   dynamic.Interface
+	namespace string
+
+	genericiooptions.IOStreams
+}
+
+// NewDoctorCmd creates the doctor command
+func NewDoctorCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &doctorOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "doctor",
+		Short:   "Check whether the kubernetes environment is ready for kbcli and KubeBlocks.",
+		Example: doctorExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	return cmd
+}
+
+func (o *doctorOptions) complete() error {
+	var err error
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *doctorOptions) run() error {
+	results := o.checkContext()
+	// the other checks make no sense if the kubernetes cluster is unreachable
+	if results[0].status != statusFail {
+		for _, check := range []func() []checkResult{
+			o.checkRBAC,
+			o.checkStorageClasses,
+			o.checkCSIDrivers,
+			o.checkKubeBlocks,
+			o.checkWebhooks,
+		} {
+			results = append(results, check()...)
+		}
+	}
+
+	counts := map[checkStatus]int{}
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("CHECK", "STATUS", "MESSAGE")
+	for _, r := range results {
+		counts[r.status]++
+		status := string(r.status)
+		switch r.status {
+		case statusPass:
+			status = printer.BoldGreen(status)
+		case statusWarn:
+			status = printer.BoldYellow(status)
+		case statusFail:
+			status = printer.BoldRed(status)
+		}
+		tbl.AddRow(r.name, status, r.message)
+	}
+	tbl.Print()
+	fmt.Fprintf(o.Out, "\n%d passed, %d warnings, %d failed\n", counts[statusPass], counts[statusWarn], counts[statusFail])
+	if counts[statusFail] > 0 {
+		return fmt.Errorf("%d check(s) failed", counts[statusFail])
+	}
+	return nil
+}
+
+// checkContext checks whether the kubernetes cluster of the current context is reachable
+func (o *doctorOptions) checkContext() []checkResult {
+	const name = "Kubernetes Context"
+	version, err := o.client.Discovery().ServerVersion()
+	if err != nil {
+		return []checkResult{{name, statusFail, fmt.Sprintf("failed to reach the kubernetes cluster: %v", err)}}
+	}
+	return []checkResult{{name, statusPass, fmt.Sprintf("kubernetes %s is reachable", version.GitVersion)}}
+}
+
+// checkRBAC checks whether the current user has the permissions kbcli requires
+func (o *doctorOptions) checkRBAC() []checkResult {
+	const name = "RBAC Permissions"
+	var (
+		denied []string
+		errs   []string
+	)
+	for _, access := range requiredAccesses {
+		var deniedVerbs []string
+		for _, verb := range access.verbs {
+			allowed, err := o.canI(access, verb)
+			if err != nil {
+				errs = append(errs, err.Error())
+				break
+			}
+			if !allowed {
+				deniedVerbs = append(deniedVerbs, verb)
+			}
+		}
+		if len(deniedVerbs) > 0 {
+			denied = append(denied, fmt.Sprintf("%s(%s)", gvrString(access.gvr), strings.Join(deniedVerbs, ",")))
+		}
+	}
+	switch {
+	case len(errs) > 0:
+		return []checkResult{{name, statusWarn, fmt.Sprintf("failed to review the permissions: %s", errs[0])}}
+	case len(denied) > 0:
+		return []checkResult{{name, statusFail, fmt.Sprintf("missing permissions in namespace %s: %s", o.namespace, strings.Join(denied, " "))}}
+	default:
+		return []checkResult{{name, statusPass, fmt.Sprintf("all the permissions kbcli requires are granted in namespace %s", o.namespace)}}
+	}
+}
+
+func (o *doctorOptions) canI(access resourceAccess, verb string) (bool, error) {
+	resource, subresource, _ := strings.Cut(access.gvr.Resource, "/")
+	attrs := &authorizationv1.ResourceAttributes{
+		Verb:        verb,
+		Group:       access.gvr.Group,
+		Version:     access.gvr.Version,
+		Resource:    resource,
+		Subresource: subresource,
+	}
+	if !access.clusterScoped {
+		attrs.Namespace = o.namespace
+	}
+	review, err := o.client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(),
+		&authorizationv1.SelfSubjectAccessReview{Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attrs}},
+		metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// checkStorageClasses checks whether there is a default storage class and the storage classes supporting volume snapshot
+func (o *doctorOptions) checkStorageClasses() []checkResult {
+	const name = "Storage Classes"
+	scs, err := o.client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []checkResult{{name, statusFail, fmt.Sprintf("failed to list storage classes: %v", err)}}
+	}
+	if len(scs.Items) == 0 {
+		return []checkResult{{name, statusFail, "no storage class found, the cluster volumes can not be provisioned"}}
+	}
+
+	var defaultSC *storagev1.StorageClass
+	for i, sc := range scs.Items {
+		if util.IsDefaultStorageClass(sc.Annotations) {
+			defaultSC = &scs.Items[i]
+			break
+		}
+	}
+	var results []checkResult
+	if defaultSC == nil {
+		results = append(results, checkResult{name, statusWarn, "no default storage class found, the storage class must be specified when creating clusters"})
+	} else {
+		results = append(results, checkResult{name, statusPass, fmt.Sprintf("default storage class is %s", defaultSC.Name)})
+	}
+
+	// volume snapshot is supported if there is a volume snapshot class with the same driver as the provisioner
+	const snapshotName = "Volume Snapshot"
+	drivers, installed, err := util.GetVolumeSnapshotClassDrivers(o.dynamic)
+	if err != nil {
+		return append(results, checkResult{snapshotName, statusWarn, fmt.Sprintf("failed to list volume snapshot classes: %v", err)})
+	}
+	if !installed {
+		return append(results, checkResult{snapshotName, statusWarn, fmt.Sprintf("the volume snapshot CRDs are not installed, %s", util.VolumeSnapshotInstallHint)})
+	}
+	var supported []string
+	for _, sc := range scs.Items {
+		if drivers[sc.Provisioner] {
+			supported = append(supported, sc.Name)
+		}
+	}
+	if len(supported) == 0 {
+		return append(results, checkResult{snapshotName, statusWarn, "no storage class supports volume snapshot, the snapshot backup method is unavailable"})
+	}
+	return append(results, checkResult{snapshotName, statusPass, fmt.Sprintf("storage classes supporting volume snapshot: %s", strings.Join(supported, ","))})
+}
+
+// checkCSIDrivers checks whether there are CSI drivers installed
+func (o *doctorOptions) checkCSIDrivers() []checkResult {
+	const name = "CSI Drivers"
+	list, err := o.client.StorageV1().CSIDrivers().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []checkResult{{name, statusWarn, fmt.Sprintf("failed to list CSI drivers: %v", err)}}
+	}
+	if len(list.Items) == 0 {
+		return []checkResult{{name, statusWarn, "no CSI driver found, volume expansion and snapshot may be unavailable"}}
+	}
+	var names []string
+	for _, d := range list.Items {
+		names = append(names, d.Name)
+	}
+	return []checkResult{{name, statusPass, strings.Join(names, ",")}}
+}
+
+// checkKubeBlocks checks whether KubeBlocks is installed and ready
+func (o *doctorOptions) checkKubeBlocks() []checkResult {
+	const name = "KubeBlocks"
+	deploy, err := util.GetKubeBlocksDeploy(o.client)
+	if err != nil {
+		return []checkResult{{name, statusFail, err.Error()}}
+	}
+	if deploy == nil {
+		return []checkResult{{name, statusFail, "KubeBlocks is not installed, install it with `kbcli kubeblocks install`"}}
+	}
+	var replicas int32 = 1
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	version := deploy.Labels["app.kubernetes.io/version"]
+	if deploy.Status.ReadyReplicas < replicas {
+		return []checkResult{{name, statusFail, fmt.Sprintf("KubeBlocks %s in namespace %s is not ready, %d/%d replicas are ready",
+			version, deploy.Namespace, deploy.Status.ReadyReplicas, replicas)}}
+	}
+	return []checkResult{{name, statusPass, fmt.Sprintf("KubeBlocks %s is running in namespace %s", version, deploy.Namespace)}}
+}
+
+// checkWebhooks checks whether the services of the KubeBlocks webhooks have ready endpoints
+func (o *doctorOptions) checkWebhooks() []checkResult {
+	const name = "KubeBlocks Webhooks"
+	type webhookService struct {
+		webhook   string
+		namespace string
+		name      string
+	}
+	var services []webhookService
+	validating, err := o.client.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []checkResult{{name, statusWarn, fmt.Sprintf("failed to list webhook configurations: %v", err)}}
+	}
+	for _, c := range validating.Items {
+		for _, w := range c.Webhooks {
+			if strings.Contains(c.Name, types.KubeBlocksChartName) && w.ClientConfig.Service != nil {
+				services = append(services, webhookService{w.Name, w.ClientConfig.Service.Namespace, w.ClientConfig.Service.Name})
+			}
+		}
+	}
+	mutating, err := o.client.AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []checkResult{{name, statusWarn, fmt.Sprintf("failed to list webhook configurations: %v", err)}}
+	}
+	for _, c := range mutating.Items {
+		for _, w := range c.Webhooks {
+			if strings.Contains(c.Name, types.KubeBlocksChartName) && w.ClientConfig.Service != nil {
+				services = append(services, webhookService{w.Name, w.ClientConfig.Service.Namespace, w.ClientConfig.Service.Name})
+			}
+		}
+	}
+	if len(services) == 0 {
+		return []checkResult{{name, statusPass, "no KubeBlocks webhook is registered"}}
+	}
+
+	var unhealthy []string
+	for _, s := range services {
+		endpoints, err := o.client.CoreV1().Endpoints(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+		ready := false
+		if err == nil {
+			for _, subset := range endpoints.Subsets {
+				if len(subset.Addresses) > 0 {
+					ready = true
+					break
+				}
+			}
+		}
+		if !ready {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s(service %s/%s)", s.webhook, s.namespace, s.name))
+		}
+	}
+	if len(unhealthy) > 0 {
+		return []checkResult{{name, statusFail, fmt.Sprintf("webhooks without ready endpoints, the requests to KubeBlocks resources may be rejected: %s",
+			strings.Join(unhealthy, " "))}}
+	}
+	return []checkResult{{name, statusPass, fmt.Sprintf("%d webhook(s) are healthy", len(services))}}
+}
+
+func gvrString(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource
+	}
+	return fmt.Sprintf("%s.%s", gvr.Resource, gvr.Group)
+}
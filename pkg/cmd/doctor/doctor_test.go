@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package doctor
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	dynamicfakeclient "k8s.io/client-go/dynamic/fake"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("doctor", func() {
+	const (
+		namespace   = clitesting.Namespace
+		provisioner = "ebs.csi.aws.com"
+	)
+
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = clitesting.NewTestFactory(namespace)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	newClient := func(allowed bool, objs ...runtime.Object) *kubefakeclient.Clientset {
+		client := kubefakeclient.NewSimpleClientset(objs...)
+		client.PrependReactor("create", "selfsubjectaccessreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			review := action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = allowed
+			return true, review, nil
+		})
+		return client
+	}
+
+	newDynamic := func(driver string) *dynamicfakeclient.FakeDynamicClient {
+		vsc := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion":     "snapshot.storage.k8s.io/v1",
+			"kind":           "VolumeSnapshotClass",
+			"metadata":       map[string]interface{}{"name": "csi-snapclass"},
+			"driver":         driver,
+			"deletionPolicy": "Delete",
+		}}
+		return dynamicfakeclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{types.VolumeSnapshotClassGVR(): "VolumeSnapshotClassList"}, vsc)
+	}
+
+	kbDeploy := func(ready int32) *appsv1.Deployment {
+		deploy := clitesting.FakeKBDeploy("0.8.0")
+		deploy.Name = "kubeblocks"
+		deploy.Namespace = "kb-system"
+		deploy.Status.ReadyReplicas = ready
+		return deploy
+	}
+
+	webhook := &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeblocks-validating-webhook"},
+		Webhooks: []admissionv1.ValidatingWebhook{{
+			Name: "vcluster.kb.io",
+			ClientConfig: admissionv1.WebhookClientConfig{
+				Service: &admissionv1.ServiceReference{Namespace: "kb-system", Name: "kubeblocks"},
+			},
+		}},
+	}
+
+	defaultSC := func() *storagev1.StorageClass {
+		sc := clitesting.FakeStorageClass("gp3", true)
+		sc.Provisioner = provisioner
+		return sc
+	}
+
+	It("doctor command", func() {
+		Expect(NewDoctorCmd(tf, streams)).ShouldNot(BeNil())
+	})
+
+	It("all checks pass", func() {
+		endpoints := &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kb-system", Name: "kubeblocks"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+		}
+		o := &doctorOptions{
+			client: newClient(true, defaultSC(), kbDeploy(1), webhook, endpoints,
+				&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: provisioner}}),
+			dynamic:   newDynamic(provisioner),
+			namespace: namespace,
+			IOStreams: streams,
+		}
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("default storage class is gp3"))
+		Expect(out.String()).Should(ContainSubstring("storage classes supporting volume snapshot: gp3"))
+		Expect(out.String()).Should(ContainSubstring("KubeBlocks 0.8.0 is running"))
+		Expect(out.String()).Should(ContainSubstring("1 webhook(s) are healthy"))
+		Expect(out.String()).Should(ContainSubstring("0 warnings, 0 failed"))
+	})
+
+	It("report the failed checks", func() {
+		sc := clitesting.FakeStorageClass("standard", false)
+		sc.Provisioner = "rancher.io/local-path"
+		o := &doctorOptions{
+			client:    newClient(false, sc, kbDeploy(0), webhook),
+			dynamic:   newDynamic(provisioner),
+			namespace: namespace,
+			IOStreams: streams,
+		}
+		Expect(o.run()).Should(HaveOccurred())
+		Expect(out.String()).Should(ContainSubstring("missing permissions in namespace " + namespace))
+		Expect(out.String()).Should(ContainSubstring("clusters.apps.kubeblocks.io(get,list,watch,create,update,patch,delete)"))
+		Expect(out.String()).Should(ContainSubstring("no default storage class found"))
+		Expect(out.String()).Should(ContainSubstring("no storage class supports volume snapshot"))
+		Expect(out.String()).Should(ContainSubstring("no CSI driver found"))
+		Expect(out.String()).Should(ContainSubstring("0/1 replicas are ready"))
+		Expect(out.String()).Should(ContainSubstring("webhooks without ready endpoints"))
+	})
+
+	It("report KubeBlocks is not installed", func() {
+		o := &doctorOptions{
+			client:    newClient(true, defaultSC()),
+			dynamic:   newDynamic(provisioner),
+			namespace: namespace,
+			IOStreams: streams,
+		}
+		Expect(o.run()).Should(HaveOccurred())
+		Expect(out.String()).Should(ContainSubstring("KubeBlocks is not installed"))
+		Expect(out.String()).Should(ContainSubstring("no KubeBlocks webhook is registered"))
+	})
+})
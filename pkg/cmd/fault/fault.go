@@ -26,16 +26,23 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
 	"github.com/apecloud/kbcli/pkg/action"
 	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
 )
 
@@ -64,6 +71,12 @@ type FaultBaseOptions struct {
 
 	Duration string `json:"duration"`
 
+	// Component is the component of the cluster specified by the argument to inject faults into
+	Component string `json:"-"`
+
+	// Interval injects the faults repeatedly at the interval by a chaos-mesh schedule
+	Interval string `json:"-"`
+
 	Selector `json:"selector"`
 
 	action.CreateOptions `json:"-"`
@@ -92,6 +105,7 @@ func registerFlagCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
 		"JSON": "Output result in JSON format",
 		"YAML": "Output result in YAML format",
 	}
+	util.RegisterFlagCompletionFunc(cmd, "component", util.ClusterComponentCompletionFunc(f))
 	util.CheckErr(cmd.RegisterFlagCompletionFunc("output",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			var names []string
@@ -108,6 +122,8 @@ func (o *FaultBaseOptions) AddCommonFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.Mode, "mode", "all", `You can select "one", "all", "fixed", "fixed-percent", "random-max-percent", Specify the experimental mode, that is, which Pods to experiment with.`)
 	cmd.Flags().StringVar(&o.Value, "value", "", `If you choose mode=fixed or fixed-percent or random-max-percent, you can enter a value to specify the number or percentage of pods you want to inject.`)
 	cmd.Flags().StringVar(&o.Duration, "duration", "10s", "Supported formats of the duration are: ms / s / m / h.")
+	cmd.Flags().StringVar(&o.Component, "component", "", "Inject faults into the pods of the component of the cluster specified by the argument.")
+	cmd.Flags().StringVar(&o.Interval, "interval", "", "Inject faults repeatedly at the interval, such as 30s, supported formats are: ms / s / m / h.")
 	cmd.Flags().StringToStringVar(&o.LabelSelectors, "label", map[string]string{}, `label for pod, such as '"app.kubernetes.io/component=mysql, statefulset.kubernetes.io/pod-name=mycluster-mysql-0.`)
 	cmd.Flags().StringArrayVar(&o.NamespaceSelectors, "ns-fault", []string{"default"}, `Specifies the namespace into which you want to inject faults.`)
 	cmd.Flags().StringArrayVar(&o.PodPhaseSelectors, "phase", []string{}, `Specify the pod that injects the fault by the state of the pod.`)
@@ -122,6 +138,9 @@ func (o *FaultBaseOptions) AddCommonFlag(cmd *cobra.Command) {
 
 func (o *FaultBaseOptions) BaseValidate() error {
 	if o.DryRun == "none" {
+		if err := o.checkChaosResourceInstalled(); err != nil {
+			return err
+		}
 		enable, err := o.checkChaosMeshEnable()
 		if err != nil {
 			return err
@@ -135,6 +154,10 @@ func (o *FaultBaseOptions) BaseValidate() error {
 		return err
 	}
 
+	if ok, err := IsRegularMatch(o.Interval); !ok {
+		return err
+	}
+
 	if o.Value == "" && (o.Mode == "fixed" || o.Mode == "fixed-percent" || o.Mode == "random-max-percent") {
 		return fmt.Errorf("you must use --value to specify an integer")
 	}
@@ -147,7 +170,15 @@ func (o *FaultBaseOptions) BaseValidate() error {
 }
 
 func (o *FaultBaseOptions) BaseComplete() error {
-	if len(o.Args) > 0 {
+	if o.Interval != "" && o.GVR.Resource != ResourceSchedule {
+		o.completeSchedule()
+	}
+
+	isCluster, err := o.completeClusterSelector()
+	if err != nil {
+		return err
+	}
+	if len(o.Args) > 0 && !isCluster {
 		o.PodNameSelectors = make(map[string][]string, len(o.NamespaceSelectors))
 		for _, ns := range o.NamespaceSelectors {
 			o.PodNameSelectors[ns] = o.Args
@@ -156,6 +187,87 @@ func (o *FaultBaseOptions) BaseComplete() error {
 	return nil
 }
 
+// completeClusterSelector selects the pods of the cluster if the only argument is the name of
+// an existing cluster, otherwise the arguments are the pod names.
+func (o *FaultBaseOptions) completeClusterSelector() (bool, error) {
+	if len(o.Args) != 1 {
+		if o.Component != "" {
+			return false, fmt.Errorf("the cluster name should be specified by the only argument if --component is specified")
+		}
+		return false, nil
+	}
+
+	name := o.Args[0]
+	for _, ns := range o.NamespaceSelectors {
+		_, err := o.Dynamic.Resource(types.ClusterGVR()).Namespace(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			if o.Component != "" {
+				return false, err
+			}
+			klog.V(1).Infof("failed to get cluster %s in namespace %s: %v", name, ns, err)
+			continue
+		}
+
+		if o.LabelSelectors == nil {
+			o.LabelSelectors = map[string]string{}
+		}
+		o.LabelSelectors[constant.AppInstanceLabelKey] = name
+		o.LabelSelectors[constant.AppManagedByLabelKey] = constant.AppName
+		if o.Component != "" {
+			o.LabelSelectors[constant.KBAppComponentLabelKey] = o.Component
+		}
+		o.NamespaceSelectors = []string{ns}
+		return true, nil
+	}
+
+	if o.Component != "" {
+		return false, fmt.Errorf("cluster %s is not found in namespace %s", name, strings.Join(o.NamespaceSelectors, ","))
+	}
+	return false, nil
+}
+
+// completeSchedule creates a chaos-mesh schedule wrapping the chaos to inject the faults repeatedly
+func (o *FaultBaseOptions) completeSchedule() {
+	preCreate := o.PreCreate
+	o.GVR = GetGVR(Group, Version, ResourceSchedule)
+	o.PreCreate = func(obj *unstructured.Unstructured) error {
+		if preCreate != nil {
+			if err := preCreate(obj); err != nil {
+				return err
+			}
+		}
+		return wrapSchedule(obj, o.Interval)
+	}
+}
+
+func wrapSchedule(obj *unstructured.Unstructured, interval string) error {
+	kind := obj.GetKind()
+	field, ok := scheduleChaosFields[kind]
+	if !ok {
+		return fmt.Errorf("chaos %s can not be scheduled", kind)
+	}
+	schedule := map[string]interface{}{
+		"apiVersion": GroupVersion,
+		"kind":       KindSchedule,
+		"metadata": map[string]interface{}{
+			"generateName": obj.GetGenerateName(),
+			"namespace":    obj.GetNamespace(),
+		},
+		"spec": map[string]interface{}{
+			"schedule":          "@every " + interval,
+			"type":              kind,
+			"historyLimit":      int64(1),
+			"concurrencyPolicy": "Forbid",
+			field:               obj.Object["spec"],
+		},
+	}
+	obj.SetUnstructuredContent(schedule)
+	return nil
+}
+
 func IsRegularMatch(str string) (bool, error) {
 	pattern := regexp.MustCompile(`^\d+(ms|s|m|h)$`)
 	if str != "" && !pattern.MatchString(str) {
@@ -177,6 +289,18 @@ func GetGVR(group, version, resourceName string) schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: group, Version: version, Resource: resourceName}
 }
 
+// checkChaosResourceInstalled checks the chaos CRD to be created is installed
+func (o *FaultBaseOptions) checkChaosResourceInstalled() error {
+	kinds, err := getAllChaosResourceKinds(o.Factory, GroupVersion)
+	if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+		return err
+	}
+	if !slices.Contains(kinds, o.GVR.Resource) {
+		return fmt.Errorf("the %s CRD of chaos-mesh is not installed, use `kbcli addon enable fault-chaos-mesh` to enable chaos-mesh first", o.GVR.Resource)
+	}
+	return nil
+}
+
 func (o *FaultBaseOptions) checkChaosMeshEnable() (bool, error) {
 	config, err := o.Factory.ToRESTConfig()
 	if err != nil {
@@ -41,11 +41,24 @@ const (
 	ResourceTimeChaos    = "timechaos"
 	ResourceAWSChaos     = "awschaos"
 	ResourceGCPChaos     = "gcpchaos"
+	ResourceSchedule     = "schedules"
 
 	KindAWSChaos = "AWSChaos"
 	KindGCPChaos = "GCPChaos"
+	KindSchedule = "Schedule"
 )
 
+// scheduleChaosFields are the fields of the schedule spec embedding the chaos of the kinds
+var scheduleChaosFields = map[string]string{
+	"PodChaos":     "podChaos",
+	"NetworkChaos": "networkChaos",
+	"DNSChaos":     "dnsChaos",
+	"HTTPChaos":    "httpChaos",
+	"IOChaos":      "ioChaos",
+	"StressChaos":  "stressChaos",
+	"TimeChaos":    "timeChaos",
+}
+
 // Cue Template Name
 const (
 	CueTemplatePodChaos     = "pod_chaos_template.cue"
@@ -32,6 +32,7 @@ import (
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var faultHTTPExample = templates.Examples(`
@@ -176,10 +177,10 @@ func (o *HTTPChaosOptions) NewCobraCommand(use, short string) *cobra.Command {
 		Example: faultHTTPExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.CreateOptions.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Run())
 		},
 	}
 }
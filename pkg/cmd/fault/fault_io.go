@@ -185,10 +185,10 @@ func (o *IOChaosOptions) NewCobraCommand(use, short string) *cobra.Command {
 		Example: faultIOExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.CreateOptions.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Run())
 		},
 	}
 }
@@ -274,10 +274,10 @@ func (o *NetworkChaosOptions) NewCobraCommand(use, short string) *cobra.Command
 		Example: faultNetWorkExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.CreateOptions.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Run())
 		},
 	}
 }
@@ -160,7 +160,7 @@ func (o *NodeChaoOptions) NewCobraCommand(use, short string) *cobra.Command {
 		Short:   short,
 		Example: faultNodeExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Execute(use, args, false))
+			util.CheckErr(o.Execute(use, args, false))
 		},
 	}
 }
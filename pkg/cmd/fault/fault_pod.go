@@ -65,6 +65,9 @@ var faultPodExample = templates.Examples(`
 
 	# kill container in pod
 	kbcli fault pod kill-container mysql-cluster-mysql-0 --container=mysql
+
+	# kill the pods of mysql component in cluster mycluster every 30 seconds
+	kbcli fault pod kill mycluster --component=mysql --interval=30s
 `)
 
 type PodChaosOptions struct {
@@ -151,10 +154,10 @@ func (o *PodChaosOptions) NewCobraCommand(use, short string) *cobra.Command {
 		Example: faultPodExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.CreateOptions.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.CreateOptions.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Run())
 		},
 	}
 }
@@ -24,10 +24,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
 	"github.com/apecloud/kbcli/pkg/testing"
 )
 
@@ -75,6 +78,40 @@ var _ = Describe("Fault POD", func() {
 			}
 		})
 
+		It("fault pod kill the pods of cluster at interval", func() {
+			tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeCluster(testing.ClusterName, "default"))
+			o := NewPodChaosOptions(tf, streams, string(v1alpha1.PodKillAction))
+			cmd := o.NewCobraCommand(Kill, KillShort)
+			o.AddCommonFlag(cmd)
+			o.Args = []string{testing.ClusterName}
+
+			Expect(cmd.Flags().Parse([]string{"--component=mysql", "--interval=30s", "--dry-run=client"})).Should(Succeed())
+			Expect(o.CreateOptions.Complete()).Should(Succeed())
+			Expect(o.Complete()).Should(Succeed())
+			Expect(o.Validate()).Should(Succeed())
+			Expect(o.PodNameSelectors).Should(BeEmpty())
+			Expect(o.LabelSelectors).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, testing.ClusterName))
+			Expect(o.LabelSelectors).Should(HaveKeyWithValue(constant.KBAppComponentLabelKey, "mysql"))
+			Expect(o.GVR.Resource).Should(Equal(ResourceSchedule))
+			Expect(o.Run()).Should(Succeed())
+
+			By("the schedule wraps the chaos")
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"kind": "PodChaos",
+				"spec": map[string]interface{}{"action": "pod-kill"},
+			}}
+			Expect(wrapSchedule(obj, "30s")).Should(Succeed())
+			Expect(obj.GetKind()).Should(Equal(KindSchedule))
+			schedule, _, _ := unstructured.NestedString(obj.Object, "spec", "schedule")
+			Expect(schedule).Should(Equal("@every 30s"))
+			action, _, _ := unstructured.NestedString(obj.Object, "spec", "podChaos", "action")
+			Expect(action).Should(Equal("pod-kill"))
+
+			By("the component of a cluster not found")
+			o.Args = []string{"not-exist"}
+			Expect(o.Complete()).Should(HaveOccurred())
+		})
+
 		It("fault pod kill-container", func() {
 			inputs := [][]string{
 				{"--container=mysql", "--container=config-manager", "--dry-run=client"},
@@ -0,0 +1,235 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	resultSucceed = "Succeed"
+	resultFailed  = "Failed"
+
+	redactedValue = "******"
+)
+
+// readOnlyVerbs are the commands that do not mutate anything, and will not be audited
+var readOnlyVerbs = []string{
+	"list", "describe", "get", "view", "current", "version", "help", "completion", "history",
+	"search", "explain", "report", "logs", "status", "options", "compare", "connect", "open",
+	"diff", "top", "preflight", "__complete", "__completeNoDesc",
+}
+
+// sensitiveFlagRegex matches the flags whose value should not be recorded
+var sensitiveFlagRegex = regexp.MustCompile(`(?i)^--?[\w-]*(password|passwd|secret|token|credential|access-?key)[\w-]*`)
+
+// Record is an audit log record of a mutate command
+type Record struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args"`
+	Context   string    `json:"context,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	// Targets are the positional arguments, such as the cluster name
+	Targets  []string `json:"targets,omitempty"`
+	Result   string   `json:"result"`
+	Error    string   `json:"error,omitempty"`
+	Duration string   `json:"duration"`
+}
+
+type auditor struct {
+	mu     sync.Mutex
+	record *Record
+	// newClients creates the clients to record the events of the command
+	newClients func() (dynamic.Interface, kubernetes.Interface, error)
+}
+
+var defaultAuditor = &auditor{}
+
+// auditEnabled checks if the audit log is enabled, it's disabled by default
+func auditEnabled() bool {
+	return viper.GetBool(types.CfgKeyAuditLog)
+}
+
+func isMutateCommand(cmd *cobra.Command) bool {
+	if !cmd.Runnable() {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		name := c.Name()
+		for _, verb := range readOnlyVerbs {
+			if name == verb || strings.HasPrefix(name, verb+"-") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// redactArgs replaces the values of sensitive flags with the redacted value
+func redactArgs(args []string) []string {
+	res := make([]string, 0, len(args))
+	redactNext := false
+	for _, arg := range args {
+		switch {
+		case redactNext:
+			res = append(res, redactedValue)
+			redactNext = false
+		case sensitiveFlagRegex.MatchString(arg):
+			if i := strings.Index(arg, "="); i > 0 {
+				res = append(res, arg[:i+1]+redactedValue)
+			} else {
+				res = append(res, arg)
+				redactNext = true
+			}
+		default:
+			res = append(res, arg)
+		}
+	}
+	return res
+}
+
+// StartAudit starts to audit the command if the audit log is enabled and the command is a
+// mutate command, the record is written when the command finishes or fails.
+func StartAudit(cmd *cobra.Command, args []string, configFlags *genericclioptions.ConfigFlags) {
+	if (!auditEnabled() && !auditEventsEnabled()) || !isMutateCommand(cmd) {
+		return
+	}
+	r := &Record{
+		Time:    time.Now(),
+		Command: cmd.CommandPath(),
+		Args:    redactArgs(os.Args[1:]),
+		Targets: args,
+	}
+	if u, err := user.Current(); err == nil {
+		r.User = u.Username
+	}
+	if configFlags != nil {
+		loader := configFlags.ToRawKubeConfigLoader()
+		if raw, err := loader.RawConfig(); err == nil {
+			r.Context = raw.CurrentContext
+			if configFlags.Context != nil && *configFlags.Context != "" {
+				r.Context = *configFlags.Context
+			}
+		}
+		if ns, _, err := loader.Namespace(); err == nil {
+			r.Namespace = ns
+		}
+	}
+	defaultAuditor.mu.Lock()
+	defer defaultAuditor.mu.Unlock()
+	defaultAuditor.record = r
+	defaultAuditor.newClients = nil
+	if configFlags != nil {
+		defaultAuditor.newClients = func() (dynamic.Interface, kubernetes.Interface, error) {
+			config, err := configFlags.ToRESTConfig()
+			if err != nil {
+				return nil, nil, err
+			}
+			dynamicClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return nil, nil, err
+			}
+			client, err := kubernetes.NewForConfig(config)
+			return dynamicClient, client, err
+		}
+	}
+}
+
+// FinishAudit writes the audit record with the result, it only writes once for a command.
+func FinishAudit(errMsg string) {
+	defaultAuditor.mu.Lock()
+	defer defaultAuditor.mu.Unlock()
+	r := defaultAuditor.record
+	if r == nil {
+		return
+	}
+	defaultAuditor.record = nil
+	r.Duration = time.Since(r.Time).Round(time.Millisecond).String()
+	r.Result = resultSucceed
+	if errMsg != "" {
+		r.Result = resultFailed
+		r.Error = strings.TrimSpace(errMsg)
+	}
+	if auditEnabled() {
+		if err := writeRecord(r); err != nil {
+			klog.V(1).Infof("failed to write audit log: %v", err)
+		}
+	}
+	if auditEventsEnabled() && defaultAuditor.newClients != nil {
+		if err := recordEvents(r, defaultAuditor.newClients); err != nil {
+			klog.V(1).Infof("failed to record audit events: %v", err)
+		}
+	}
+}
+
+func getHistoryFile() (string, error) {
+	cliHome, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cliHome, types.CliHistoryFile), nil
+}
+
+func writeRecord(r *Record) error {
+	file, err := getHistoryFile()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// FatalHandler records the failure of the audited command before kbcli exits
+func FatalHandler(msg string, code int) {
+	if msg == "" {
+		msg = fmt.Sprintf("exit with code %d", code)
+	}
+	FinishAudit(msg)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package history
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+const (
+	// eventReason is the reason of the events recorded for the kbcli operations
+	eventReason    = "KbcliOperation"
+	eventComponent = "kbcli"
+	// maxEventMessageLength is the max length of the event message, the long args are truncated
+	maxEventMessageLength = 1024
+)
+
+// eventTargetGVRs are the resources the events are recorded on, the targets of the command are
+// looked up in order, and the first found resource is the affected one.
+var eventTargetGVRs = []schema.GroupVersionResource{types.ClusterGVR(), types.BackupGVR()}
+
+// auditEventsEnabled checks if recording the mutate commands as events is enabled, it's disabled by default
+func auditEventsEnabled() bool {
+	return viper.GetBool(types.CfgKeyAuditEvents)
+}
+
+func buildEventMessage(r *Record) string {
+	msg := fmt.Sprintf("%s by user %s: kbcli %s", strings.ToLower(r.Result), r.User, strings.Join(r.Args, " "))
+	if r.Error != "" {
+		msg += ", error: " + r.Error
+	}
+	if len(msg) > maxEventMessageLength {
+		msg = msg[:maxEventMessageLength-3] + "..."
+	}
+	return msg
+}
+
+// recordEvents records the command as the events of the affected Clusters and Backups, so that the
+// operations performed by kbcli appear in the events of the resources for everyone.
+func recordEvents(r *Record, newClients func() (dynamic.Interface, kubernetes.Interface, error)) error {
+	if len(r.Targets) == 0 || r.Namespace == "" {
+		return nil
+	}
+	dynamicClient, client, err := newClients()
+	if err != nil {
+		return err
+	}
+	eventType := corev1.EventTypeNormal
+	if r.Result == resultFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	message := buildEventMessage(r)
+	for _, target := range r.Targets {
+		for _, gvr := range eventTargetGVRs {
+			obj, err := dynamicClient.Resource(gvr).Namespace(r.Namespace).Get(context.TODO(), target, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			now := metav1.NewTime(time.Now())
+			event := &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s.%x", obj.GetName(), now.UnixNano()),
+					Namespace: r.Namespace,
+				},
+				InvolvedObject: corev1.ObjectReference{
+					APIVersion:      obj.GetAPIVersion(),
+					Kind:            obj.GetKind(),
+					Name:            obj.GetName(),
+					Namespace:       obj.GetNamespace(),
+					UID:             obj.GetUID(),
+					ResourceVersion: obj.GetResourceVersion(),
+				},
+				Reason:              eventReason,
+				Message:             message,
+				Type:                eventType,
+				Source:              corev1.EventSource{Component: eventComponent},
+				FirstTimestamp:      now,
+				LastTimestamp:       now,
+				Count:               1,
+				ReportingController: eventComponent,
+				ReportingInstance:   r.User,
+			}
+			if _, err = client.CoreV1().Events(r.Namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
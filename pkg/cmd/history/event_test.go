@@ -0,0 +1,91 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package history
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	kubefakeclient "k8s.io/client-go/kubernetes/fake"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("audit events", func() {
+	var (
+		client     *kubefakeclient.Clientset
+		newClients func() (dynamic.Interface, kubernetes.Interface, error)
+	)
+
+	BeforeEach(func() {
+		client = clitesting.FakeClientSet()
+		dynamicClient := clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace),
+			clitesting.FakeBackup("mybackup"))
+		newClients = func() (dynamic.Interface, kubernetes.Interface, error) {
+			return dynamicClient, client, nil
+		}
+	})
+
+	listEvents := func() []corev1.Event {
+		events, err := client.CoreV1().Events(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		return events.Items
+	}
+
+	It("record the events on the target cluster and backup", func() {
+		r := &Record{
+			User:      "alice",
+			Args:      []string{"cluster", "restart", clitesting.ClusterName, "--password", redactedValue},
+			Namespace: clitesting.Namespace,
+			Targets:   []string{clitesting.ClusterName, "mybackup", "not-found"},
+			Result:    resultSucceed,
+		}
+		Expect(recordEvents(r, newClients)).Should(Succeed())
+		events := listEvents()
+		Expect(events).Should(HaveLen(2))
+		kinds := []string{events[0].InvolvedObject.Kind, events[1].InvolvedObject.Kind}
+		Expect(kinds).Should(ConsistOf("Cluster", "Backup"))
+		Expect(events[0].Reason).Should(Equal(eventReason))
+		Expect(events[0].Type).Should(Equal(corev1.EventTypeNormal))
+		Expect(events[0].Message).Should(Equal("succeed by user alice: kbcli cluster restart " + clitesting.ClusterName + " --password " + redactedValue))
+	})
+
+	It("record the warning event for the failed command", func() {
+		r := &Record{
+			User:      "alice",
+			Args:      []string{"cluster", "stop", clitesting.ClusterName},
+			Namespace: clitesting.Namespace,
+			Targets:   []string{clitesting.ClusterName},
+			Result:    resultFailed,
+			Error:     "error: forbidden",
+		}
+		Expect(recordEvents(r, newClients)).Should(Succeed())
+		events := listEvents()
+		Expect(events).Should(HaveLen(1))
+		Expect(events[0].Type).Should(Equal(corev1.EventTypeWarning))
+		Expect(events[0].Message).Should(HaveSuffix("error: forbidden"))
+	})
+})
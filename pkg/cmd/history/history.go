@@ -0,0 +1,180 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var historyExample = templates.Examples(`
+	# enable the audit log, the mutate commands will be recorded
+	kbcli config set audit-log true
+
+	# show the recorded commands
+	kbcli history
+
+	# show the failed commands in the last 24 hours
+	kbcli history --since 24h --failed
+
+	# show the latest 10 cluster commands
+	kbcli history --command cluster --limit 10`)
+
+type historyOptions struct {
+	since   time.Duration
+	command string
+	failed  bool
+	limit   int
+	format  printer.Format
+
+	genericiooptions.IOStreams
+}
+
+// NewHistoryCmd creates the history command
+func NewHistoryCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &historyOptions{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "history",
+		Short:   "Query the audit log of the mutate commands, enable it by `kbcli config set audit-log true`.",
+		Example: historyExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().DurationVar(&o.since, "since", 0, "Only show the commands newer than a relative duration like 5s, 2m, or 3h")
+	cmd.Flags().StringVar(&o.command, "command", "", "Only show the commands containing the specified command, such as 'cluster create'")
+	cmd.Flags().BoolVar(&o.failed, "failed", false, "Only show the failed commands")
+	cmd.Flags().IntVar(&o.limit, "limit", 0, "Only show the latest specified number of commands, 0 means no limit")
+	printer.AddOutputFlag(cmd, &o.format)
+	return cmd
+}
+
+func (o *historyOptions) run() error {
+	if !auditEnabled() {
+		printer.Warning(o.ErrOut, "the audit log is disabled, enable it by `kbcli config set audit-log true`\n")
+	}
+	records, err := readRecords()
+	if err != nil {
+		return err
+	}
+	records = o.filter(records)
+	if len(records) == 0 {
+		fmt.Fprintln(o.Out, "No command history found")
+		return nil
+	}
+
+	switch o.format {
+	case printer.JSON:
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+	case printer.YAML:
+		data, err := yaml.Marshal(records)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.Out, string(data))
+	default:
+		tbl := printer.NewTablePrinter(o.Out)
+		header := []interface{}{"TIME", "USER", "COMMAND", "NAMESPACE", "TARGETS", "RESULT", "DURATION"}
+		if o.format == printer.Wide {
+			header = append(header, "CONTEXT", "ARGS", "ERROR")
+		}
+		tbl.SetHeader(header...)
+		for _, r := range records {
+			row := []interface{}{util.TimeFormat(&metav1.Time{Time: r.Time}), r.User, r.Command, r.Namespace,
+				strings.Join(r.Targets, ","), r.Result, r.Duration}
+			if o.format == printer.Wide {
+				row = append(row, r.Context, strings.Join(r.Args, " "), r.Error)
+			}
+			tbl.AddRow(row...)
+		}
+		tbl.Print()
+	}
+	return nil
+}
+
+func (o *historyOptions) filter(records []*Record) []*Record {
+	var res []*Record
+	for _, r := range records {
+		if o.since > 0 && time.Since(r.Time) > o.since {
+			continue
+		}
+		if o.command != "" && !strings.Contains(r.Command, o.command) {
+			continue
+		}
+		if o.failed && r.Result != resultFailed {
+			continue
+		}
+		res = append(res, r)
+	}
+	if o.limit > 0 && len(res) > o.limit {
+		res = res[len(res)-o.limit:]
+	}
+	return res
+}
+
+func readRecords() ([]*Record, error) {
+	file, err := getHistoryFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		r := &Record{}
+		if err = json.Unmarshal(line, r); err != nil {
+			klog.V(1).Infof("skip invalid audit log record: %s", string(line))
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package history
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("history", func() {
+	var (
+		root   *cobra.Command
+		create *cobra.Command
+		list   *cobra.Command
+	)
+
+	BeforeEach(func() {
+		homeDir, err := os.MkdirTemp("", "kbcli-history")
+		Expect(err).Should(Succeed())
+		Expect(os.Setenv(types.CliHomeEnv, homeDir)).Should(Succeed())
+
+		run := func(cmd *cobra.Command, args []string) {}
+		root = &cobra.Command{Use: "kbcli"}
+		cluster := &cobra.Command{Use: "cluster"}
+		create = &cobra.Command{Use: "create", Run: run}
+		list = &cobra.Command{Use: "list", Run: run}
+		cluster.AddCommand(create, list)
+		root.AddCommand(cluster)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(os.Getenv(types.CliHomeEnv))).Should(Succeed())
+		Expect(os.Unsetenv(types.CliHomeEnv)).Should(Succeed())
+		viper.Reset()
+	})
+
+	It("check mutate command", func() {
+		Expect(isMutateCommand(create)).Should(BeTrue())
+		Expect(isMutateCommand(list)).Should(BeFalse())
+		Expect(isMutateCommand(root)).Should(BeFalse())
+	})
+
+	It("redact args", func() {
+		Expect(redactArgs([]string{"cluster", "create", "--password", "123", "--token=abc", "--replicas", "3"})).
+			Should(Equal([]string{"cluster", "create", "--password", redactedValue, "--token=" + redactedValue, "--replicas", "3"}))
+	})
+
+	It("audit and query history", func() {
+		By("audit is disabled by default")
+		StartAudit(create, []string{"mycluster"}, nil)
+		FinishAudit("")
+		records, err := readRecords()
+		Expect(err).Should(Succeed())
+		Expect(records).Should(BeEmpty())
+
+		By("enable audit")
+		viper.Set(types.CfgKeyAuditLog, true)
+		StartAudit(list, nil, nil)
+		FinishAudit("")
+		StartAudit(create, []string{"mycluster"}, nil)
+		FinishAudit("")
+		StartAudit(create, []string{"failed-cluster"}, nil)
+		FatalHandler("error: failed to create cluster", 1)
+		// the record is only written once
+		FinishAudit("")
+
+		records, err = readRecords()
+		Expect(err).Should(Succeed())
+		Expect(records).Should(HaveLen(2))
+		Expect(records[0].Command).Should(Equal("kbcli cluster create"))
+		Expect(records[0].Targets).Should(Equal([]string{"mycluster"}))
+		Expect(records[0].Result).Should(Equal(resultSucceed))
+		Expect(records[1].Result).Should(Equal(resultFailed))
+		Expect(records[1].Error).Should(Equal("error: failed to create cluster"))
+
+		By("query history")
+		out := &bytes.Buffer{}
+		o := &historyOptions{IOStreams: genericiooptions.IOStreams{Out: out, ErrOut: out}, format: printer.Wide}
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("failed-cluster"))
+
+		o.failed = true
+		Expect(o.filter(records)).Should(HaveLen(1))
+		o.failed = false
+		o.limit = 1
+		Expect(o.filter(records)[0].Targets).Should(Equal([]string{"failed-cluster"}))
+		o.limit = 0
+		o.since = time.Nanosecond
+		Expect(o.filter(records)).Should(BeEmpty())
+	})
+})
@@ -29,6 +29,7 @@ import (
 	"github.com/StudioSol/set"
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -36,6 +37,7 @@ import (
 
 	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
 	cfgutil "github.com/apecloud/kubeblocks/pkg/configuration/util"
+	"github.com/apecloud/kubeblocks/pkg/gotemplate"
 
 	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/builder"
 	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/types"
@@ -235,6 +237,36 @@ func validateUser(o *clusterOptions) error {
 	return nil
 }
 
+// buildClusterSpec builds the spec of the existing cluster, which is used by the commands
+// managing the cluster after it is created, so the kubernetes version doesn't matter.
+func (o *clusterOptions) buildClusterSpec() (*kubekeyapiv1alpha2.ClusterSpec, error) {
+	o.Cluster.Kubernetes.AutoDefaultFill()
+	return createClusterWithOptions(&gotemplate.TplValues{
+		builtinClusterNameObject:    o.clusterName,
+		builtinClusterVersionObject: "0.0.0",
+		builtinUserObject:           o.User,
+		builtinHostsObject:          o.Nodes,
+		builtinTimeoutObject:        o.timeout,
+		builtinKubernetesObject:     o.Cluster.Kubernetes,
+		builtinRoleGroupsObject: gotemplate.TplValues{
+			common.ETCD:   o.RoleGroup.ETCD,
+			common.Master: o.RoleGroup.Master,
+			common.Worker: o.RoleGroup.Worker,
+		},
+	})
+}
+
+// newKubeRuntime creates the runtime to run the pipelines on the hosts of the cluster
+func newKubeRuntime(clusterName string, cluster *kubekeyapiv1alpha2.ClusterSpec, debug bool) *common.KubeRuntime {
+	runtime := &common.KubeRuntime{
+		BaseRuntime: connector.NewBaseRuntime(clusterName, connector.NewDialer(), debug, false),
+		Cluster:     cluster,
+		ClusterName: clusterName,
+	}
+	syncClusterNodeRole(cluster, runtime)
+	return runtime
+}
+
 func syncClusterNodeRole(cluster *kubekeyapiv1alpha2.ClusterSpec, runtime *common.KubeRuntime) {
 	hostSet := set.NewLinkedHashSetString()
 	for _, role := range cluster.GroupHosts() {
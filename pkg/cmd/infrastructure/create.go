@@ -23,8 +23,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/spf13/cobra"
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -49,6 +47,8 @@ type createOptions struct {
 
 	securityEnhancement bool
 	outputKubeconfig    string
+	// parallel is the count of the hosts bootstrapped at the same time
+	parallel int
 }
 
 var createExamples = templates.Examples(`
@@ -115,6 +115,10 @@ addons:
             - "localprovisioner.hostpathClass.isDefaultClass=true"
 `)
 
+// defaultBootstrapParallel is the default count of the hosts bootstrapped at the same time, which
+// is the same as the concurrency of the tasks of kubekey.
+const defaultBootstrapParallel = 10
+
 func (o *createOptions) Run() error {
 	const minKubernetesVersion = "v1.24.0"
 
@@ -146,15 +150,10 @@ func (o *createOptions) Run() error {
 		return nil
 	}
 
-	runtime := &common.KubeRuntime{
-		BaseRuntime: connector.NewBaseRuntime(o.clusterName, connector.NewDialer(), o.debug, false),
-		Cluster:     cluster,
-		ClusterName: o.clusterName,
-	}
-
-	syncClusterNodeRole(cluster, runtime)
+	runtime := newKubeRuntime(o.clusterName, cluster, o.debug)
 	checkAndUpdateZone()
-	pipelineRunner := tasks.NewPipelineRunner("CreateCluster", NewCreatePipeline(o), runtime)
+	pipelineRunner := tasks.NewPipelineRunner("CreateCluster", NewCreatePipeline(o), runtime).
+		WithBootstrapBatch(o.parallel, o.debug)
 	if err := pipelineRunner.Do(o.IOStreams.Out); err != nil {
 		return err
 	}
@@ -198,6 +197,7 @@ func (o *createOptions) buildCreateInfraFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.criType, "container-runtime", "", string(container.ContainerdType), "Specify kubernetes container runtime. default is containerd")
 	cmd.Flags().BoolVarP(&o.debug, "debug", "", false, "set debug mode")
 	cmd.Flags().StringVarP(&o.outputKubeconfig, "output-kubeconfig", "", tasks.GetDefaultConfig(), "Specified output kubeconfig. [option]")
+	cmd.Flags().IntVar(&o.parallel, "parallel", defaultBootstrapParallel, "The number of hosts bootstrapped in parallel, the hosts are bootstrapped batch by batch, 0 means all hosts at once. [option]")
 }
 
 func (o *createOptions) checkAndSetDefaultVersion() {
@@ -22,15 +22,11 @@ package infrastructure
 import (
 	"fmt"
 
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/kubectl/pkg/util/templates"
 
-	"github.com/apecloud/kubeblocks/pkg/gotemplate"
-
 	"github.com/apecloud/kbcli/pkg/util"
 )
 
@@ -47,20 +43,7 @@ var deleteExamples = templates.Examples(`
 `)
 
 func (o *deleteOptions) Run() error {
-	o.Cluster.Kubernetes.AutoDefaultFill()
-	cluster, err := createClusterWithOptions(&gotemplate.TplValues{
-		builtinClusterNameObject:    o.clusterName,
-		builtinClusterVersionObject: "0.0.0",
-		builtinUserObject:           o.User,
-		builtinHostsObject:          o.Nodes,
-		builtinTimeoutObject:        o.timeout,
-		builtinKubernetesObject:     o.Cluster.Kubernetes,
-		builtinRoleGroupsObject: gotemplate.TplValues{
-			common.ETCD:   o.RoleGroup.ETCD,
-			common.Master: o.RoleGroup.Master,
-			common.Worker: o.RoleGroup.Worker,
-		},
-	})
+	cluster, err := o.buildClusterSpec()
 	if err != nil {
 		return err
 	}
@@ -73,13 +56,7 @@ func (o *deleteOptions) Run() error {
 		return nil
 	}
 
-	runtime := &common.KubeRuntime{
-		BaseRuntime: connector.NewBaseRuntime(o.clusterName, connector.NewDialer(), o.debug, false),
-		Cluster:     cluster,
-		ClusterName: o.clusterName,
-	}
-	syncClusterNodeRole(cluster, runtime)
-
+	runtime := newKubeRuntime(o.clusterName, cluster, o.debug)
 	pipeline := pipeline.Pipeline{
 		Name:    "DeleteCluster",
 		Modules: NewDeletePipeline(o),
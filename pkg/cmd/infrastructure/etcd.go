@@ -0,0 +1,223 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+
+	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/tasks"
+	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/utils"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+type etcdOptions struct {
+	clusterOptions
+	s3    utils.S3Options
+	debug bool
+}
+
+type etcdBackupOptions struct {
+	etcdOptions
+	output string
+}
+
+type etcdRestoreOptions struct {
+	etcdOptions
+	snapshot string
+}
+
+var etcdBackupExamples = templates.Examples(`
+	# save the etcd snapshot of the kubernetes cluster created by kbcli infra create to the current directory
+	kbcli infra etcd backup -c cluster.yaml
+
+	# save the etcd snapshot to an S3 bucket, the credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	kbcli infra etcd backup -c cluster.yaml --output s3://my-bucket/etcd --s3-region us-west-2
+
+	# save the etcd snapshot to an S3-compatible storage such as MinIO
+	kbcli infra etcd backup -c cluster.yaml --output s3://my-bucket/etcd --s3-endpoint http://minio.local:9000
+`)
+
+var etcdRestoreExamples = templates.Examples(`
+	# restore etcd of the kubernetes cluster from a local snapshot
+	kbcli infra etcd restore -c cluster.yaml --snapshot ./etcd-snapshot-kb-k8s-test-cluster-20231018120000.db
+
+	# restore etcd from the snapshot in an S3 bucket
+	kbcli infra etcd restore -c cluster.yaml --snapshot s3://my-bucket/etcd/etcd-snapshot-kb-k8s-test-cluster-20231018120000.db
+`)
+
+func NewEtcdCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Backup and restore etcd of the kubernetes cluster created by kbcli infra.",
+	}
+	cmd.AddCommand(newEtcdBackupCmd(streams), newEtcdRestoreCmd(streams))
+	return cmd
+}
+
+func newEtcdBackupCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &etcdBackupOptions{etcdOptions: etcdOptions{clusterOptions: clusterOptions{IOStreams: streams}}}
+	cmd := &cobra.Command{
+		Use:     "backup",
+		Short:   "Save the etcd snapshot to a local directory or an S3 bucket.",
+		Example: etcdBackupExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+	o.buildEtcdFlags(cmd)
+	cmd.Flags().StringVarP(&o.output, "output", "o", ".", "The local directory or the S3 url like s3://bucket/prefix to save the snapshot")
+	return cmd
+}
+
+func newEtcdRestoreCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &etcdRestoreOptions{etcdOptions: etcdOptions{clusterOptions: clusterOptions{IOStreams: streams}}}
+	cmd := &cobra.Command{
+		Use:     "restore",
+		Short:   "Restore etcd from a snapshot for disaster recovery, the kube-apiserver is unavailable during the restore.",
+		Example: etcdRestoreExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+	o.buildEtcdFlags(cmd)
+	cmd.Flags().StringVar(&o.snapshot, "snapshot", "", "The local file or the S3 url like s3://bucket/key of the snapshot to restore. [require]")
+	return cmd
+}
+
+func (o *etcdOptions) buildEtcdFlags(cmd *cobra.Command) {
+	buildCommonFlags(cmd, &o.clusterOptions)
+	cmd.Flags().StringVar(&o.s3.Endpoint, "s3-endpoint", "", "The endpoint of the S3-compatible storage, default to AWS S3")
+	cmd.Flags().StringVar(&o.s3.Region, "s3-region", "", "The region of the S3 bucket, default to the AWS_REGION environment variable")
+	cmd.Flags().BoolVarP(&o.debug, "debug", "", false, "set debug mode")
+}
+
+func (o *etcdBackupOptions) Validate() error {
+	if utils.IsS3URL(o.output) {
+		if _, err := utils.ParseS3URL(o.output); err != nil {
+			return err
+		}
+	}
+	return o.clusterOptions.Validate()
+}
+
+func (o *etcdBackupOptions) Run() error {
+	cluster, err := o.buildClusterSpec()
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("etcd-snapshot-%s-%s.db", o.clusterName, time.Now().Format("20060102150405"))
+	localPath := filepath.Join(o.output, name)
+	if utils.IsS3URL(o.output) {
+		// the snapshot is fetched to a temporary directory and then uploaded
+		tmpDir, err := os.MkdirTemp("", "kbcli-etcd-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+		localPath = filepath.Join(tmpDir, name)
+	} else if err = os.MkdirAll(o.output, os.ModePerm); err != nil {
+		return err
+	}
+
+	runtime := newKubeRuntime(o.clusterName, cluster, o.debug)
+	if err = tasks.NewPipelineRunner("BackupETCD", NewEtcdBackupPipeline(localPath), runtime).Do(o.IOStreams.Out); err != nil {
+		return err
+	}
+
+	target := localPath
+	if utils.IsS3URL(o.output) {
+		location, _ := utils.ParseS3URL(o.output)
+		location.Key = path.Join(location.Key, name)
+		if err = utils.UploadToS3(o.s3, location, localPath); err != nil {
+			return cfgcore.WrapError(err, "failed to upload the etcd snapshot to %s", location)
+		}
+		target = location.String()
+	}
+	fmt.Fprintf(o.IOStreams.Out, "\nThe etcd snapshot is saved to %s\n", target)
+	return nil
+}
+
+func (o *etcdRestoreOptions) Validate() error {
+	if o.snapshot == "" {
+		return cfgcore.MakeError("the snapshot to restore must be specified by --snapshot")
+	}
+	if utils.IsS3URL(o.snapshot) {
+		location, err := utils.ParseS3URL(o.snapshot)
+		if err != nil {
+			return err
+		}
+		if location.Key == "" {
+			return cfgcore.MakeError("the key of the snapshot is not found in %s", o.snapshot)
+		}
+	} else if _, err := os.Stat(o.snapshot); err != nil {
+		return err
+	}
+	return o.clusterOptions.Validate()
+}
+
+func (o *etcdRestoreOptions) Run() error {
+	cluster, err := o.buildClusterSpec()
+	if err != nil {
+		return err
+	}
+	yes, err := o.confirm(fmt.Sprintf("restore etcd of kubernetes %s from %s, all changes after the snapshot will be lost "+
+		"and the kube-apiserver is unavailable during the restore", o.clusterName, o.snapshot))
+	if err != nil {
+		return err
+	}
+	if !yes {
+		return nil
+	}
+
+	localPath := o.snapshot
+	if utils.IsS3URL(o.snapshot) {
+		tmpDir, err := os.MkdirTemp("", "kbcli-etcd-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+		location, _ := utils.ParseS3URL(o.snapshot)
+		localPath = filepath.Join(tmpDir, path.Base(location.Key))
+		if err = utils.DownloadFromS3(o.s3, location, localPath); err != nil {
+			return cfgcore.WrapError(err, "failed to download the etcd snapshot from %s", o.snapshot)
+		}
+	}
+
+	runtime := newKubeRuntime(o.clusterName, cluster, o.debug)
+	if err = tasks.NewPipelineRunner("RestoreETCD", NewEtcdRestorePipeline(localPath), runtime).Do(o.IOStreams.Out); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.IOStreams.Out, "\nThe etcd is restored from %s, and etcd and kube-apiserver are healthy.\n", o.snapshot)
+	return nil
+}
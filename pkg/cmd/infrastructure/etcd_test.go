@@ -0,0 +1,92 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/apecloud/kubeblocks/test/testdata"
+)
+
+var _ = Describe("infra etcd test", func() {
+	var (
+		streams genericiooptions.IOStreams
+		tmpDir  string
+	)
+
+	BeforeEach(func() {
+		streams, _, _, _ = genericiooptions.NewTestIOStreams()
+		tmpDir, _ = os.MkdirTemp(os.TempDir(), "test-")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	newClusterOptions := func() clusterOptions {
+		o := clusterOptions{IOStreams: streams, clusterConfig: testdata.SubTestDataPath("infrastructure/infra-cluster.yaml")}
+		Expect(o.Complete()).To(Succeed())
+		privateKeyFile := filepath.Join(tmpDir, "id_rsa.pem")
+		Expect(os.WriteFile(privateKeyFile, []byte("private key"), os.ModePerm)).Should(Succeed())
+		o.Cluster.User.PrivateKeyPath = privateKeyFile
+		return o
+	}
+
+	It("command should succeed", func() {
+		cmd := NewEtcdCmd(streams)
+		Expect(cmd).ShouldNot(BeNil())
+		Expect(cmd.Commands()).Should(HaveLen(2))
+	})
+
+	It("validate backup options", func() {
+		o := &etcdBackupOptions{etcdOptions: etcdOptions{clusterOptions: newClusterOptions()}, output: tmpDir}
+		Expect(o.Validate()).Should(Succeed())
+
+		o.output = "s3://my-bucket/etcd"
+		Expect(o.Validate()).Should(Succeed())
+
+		o.output = "s3:///etcd"
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("the bucket is not found")))
+	})
+
+	It("validate restore options", func() {
+		o := &etcdRestoreOptions{etcdOptions: etcdOptions{clusterOptions: newClusterOptions()}}
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("--snapshot")))
+
+		o.snapshot = filepath.Join(tmpDir, "not-exist.db")
+		Expect(o.Validate()).ShouldNot(Succeed())
+
+		o.snapshot = filepath.Join(tmpDir, "snapshot.db")
+		Expect(os.WriteFile(o.snapshot, []byte("snapshot"), os.ModePerm)).Should(Succeed())
+		Expect(o.Validate()).Should(Succeed())
+
+		o.snapshot = "s3://my-bucket"
+		Expect(o.Validate()).Should(MatchError(ContainSubstring("the key of the snapshot is not found")))
+
+		o.snapshot = "s3://my-bucket/etcd/snapshot.db"
+		Expect(o.Validate()).Should(Succeed())
+	})
+})
@@ -32,5 +32,8 @@ func NewInfraCmd(streams genericiooptions.IOStreams) *cobra.Command {
 	}
 	cmd.AddCommand(NewCreateKubernetesCmd(streams))
 	cmd.AddCommand(NewDeleteKubernetesCmd(streams))
+	cmd.AddCommand(NewEtcdCmd(streams))
+	cmd.AddCommand(NewRenewCertsCmd(streams))
+	cmd.AddCommand(NewStatusCmd(streams))
 	return cmd
 }
@@ -37,13 +37,13 @@ import (
 func NewCreatePipeline(o *createOptions) []module.Module {
 	return []module.Module{
 		&precheck.GreetingsModule{},
-		&tasks.CheckNodeArchitectureModule{},
-		&precheck.NodePreCheckModule{},
-		&tasks.InstallDependenciesModule{},
+		tasks.Bootstrap(&tasks.CheckNodeArchitectureModule{}),
+		tasks.Bootstrap(&precheck.NodePreCheckModule{}),
+		tasks.Bootstrap(&tasks.InstallDependenciesModule{}),
 		&tasks.PrepareK8sBinariesModule{BinaryVersion: o.version},
-		&tasks.ConfigureNodeOSModule{Nodes: o.Nodes},
+		tasks.Bootstrap(&tasks.ConfigureNodeOSModule{Nodes: o.Nodes}),
 		&kubernetes.StatusModule{},
-		&tasks.InstallCRIModule{SandBoxImage: o.Cluster.Kubernetes.CRI.SandBoxImage},
+		tasks.Bootstrap(&tasks.InstallCRIModule{SandBoxImage: o.Cluster.Kubernetes.CRI.SandBoxImage}),
 		&etcd.PreCheckModule{},
 		&etcd.CertsModule{},
 		&etcd.InstallETCDBinaryModule{},
@@ -72,3 +72,35 @@ func NewDeletePipeline(o *deleteOptions) []module.Module {
 		&certs.UninstallAutoRenewCertsModule{},
 	}
 }
+
+func NewEtcdBackupPipeline(localPath string) []module.Module {
+	return []module.Module{
+		&precheck.GreetingsModule{},
+		&tasks.ETCDSnapshotModule{LocalPath: localPath},
+	}
+}
+
+func NewEtcdRestorePipeline(localPath string) []module.Module {
+	return []module.Module{
+		&precheck.GreetingsModule{},
+		&tasks.ETCDRestoreModule{LocalPath: localPath},
+	}
+}
+
+func NewCheckCertsPipeline(result *tasks.ClusterCerts) []module.Module {
+	return []module.Module{
+		&precheck.GreetingsModule{},
+		&certs.CheckCertsModule{},
+		&tasks.CollectCertsModule{Result: result},
+	}
+}
+
+func NewRenewCertsPipeline(o *renewCertsOptions, result *tasks.ClusterCerts) []module.Module {
+	return []module.Module{
+		&tasks.RenewCertsModule{},
+		&kubernetes.StatusModule{},
+		&tasks.SaveKubeConfigModule{OutputKubeconfig: o.outputKubeconfig},
+		&certs.CheckCertsModule{},
+		&tasks.CollectCertsModule{Result: result},
+	}
+}
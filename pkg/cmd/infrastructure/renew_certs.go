@@ -0,0 +1,174 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package infrastructure
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/tasks"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// certExpiryWarningThreshold is the remaining time of the certificates to be warned
+const certExpiryWarningThreshold = 30 * 24 * time.Hour
+
+type renewCertsOptions struct {
+	clusterOptions
+
+	checkOnly        bool
+	outputKubeconfig string
+	debug            bool
+}
+
+var renewCertsExamples = templates.Examples(`
+	# check the expiry of the control plane certificates and renew them
+	kbcli infra renew-certs -c cluster.yaml
+
+	# only check the expiry of the control plane certificates
+	kbcli infra renew-certs -c cluster.yaml --check-only
+`)
+
+func NewRenewCertsCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &renewCertsOptions{clusterOptions: clusterOptions{IOStreams: streams}}
+	cmd := &cobra.Command{
+		Use:     "renew-certs",
+		Short:   "Check the expiry of the control plane certificates and renew them.",
+		Example: renewCertsExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+	buildCommonFlags(cmd, &o.clusterOptions)
+	cmd.Flags().BoolVar(&o.checkOnly, "check-only", false, "Only check the expiry of the certificates without renewing them")
+	cmd.Flags().StringVarP(&o.outputKubeconfig, "output-kubeconfig", "", tasks.GetDefaultConfig(), "Specified output kubeconfig with the renewed admin certificate. [option]")
+	cmd.Flags().BoolVarP(&o.debug, "debug", "", false, "set debug mode")
+	return cmd
+}
+
+func (o *renewCertsOptions) Run() error {
+	cluster, err := o.buildClusterSpec()
+	if err != nil {
+		return err
+	}
+	runtime := newKubeRuntime(o.clusterName, cluster, o.debug)
+
+	before := &tasks.ClusterCerts{}
+	if err = tasks.NewPipelineRunner("CheckCerts", NewCheckCertsPipeline(before), runtime).Do(o.IOStreams.Out); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.IOStreams.Out)
+	printCertsExpiry(o.IOStreams.Out, before, nil, time.Now())
+	if o.checkOnly {
+		return nil
+	}
+
+	yes, err := o.confirm(fmt.Sprintf("renew the control plane certificates of kubernetes %s, the control plane components will be restarted", o.clusterName))
+	if err != nil {
+		return err
+	}
+	if !yes {
+		return nil
+	}
+	after := &tasks.ClusterCerts{}
+	if err = tasks.NewPipelineRunner("RenewCerts", NewRenewCertsPipeline(o, after), runtime).Do(o.IOStreams.Out); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.IOStreams.Out)
+	printCertsExpiry(o.IOStreams.Out, before, after, time.Now())
+	fmt.Fprintf(o.IOStreams.Out, "\nThe certificates are renewed, the kubeconfig is updated in %s.\n", o.outputKubeconfig)
+	return nil
+}
+
+// printCertsExpiry prints the expiry of the certificates and the warnings of the certificates to
+// expire soon. The expiry after the renewal is printed if the after is not nil.
+func printCertsExpiry(out io.Writer, before, after *tasks.ClusterCerts, now time.Time) {
+	renewed := map[string]string{}
+	if after != nil {
+		for _, cert := range after.Certs {
+			renewed[cert.NodeName+"/"+cert.Name] = cert.Expires
+		}
+	}
+	residual := func(expires string) (string, bool) {
+		return certResidual(expires, now)
+	}
+
+	tbl := printer.NewTablePrinter(out)
+	if after != nil {
+		tbl.SetHeader("NODE", "CERTIFICATE", "EXPIRES-BEFORE", "EXPIRES-AFTER", "RESIDUAL")
+	} else {
+		tbl.SetHeader("NODE", "CERTIFICATE", "EXPIRES", "RESIDUAL")
+	}
+	var warnings []string
+	for _, cert := range before.Certs {
+		if after == nil {
+			res, warn := residual(cert.Expires)
+			tbl.AddRow(cert.NodeName, cert.Name, cert.Expires, res)
+			if warn {
+				warnings = append(warnings, fmt.Sprintf("certificate %s on node %s expires in %s, please renew it by `kbcli infra renew-certs`", cert.Name, cert.NodeName, res))
+			}
+			continue
+		}
+		expires := renewed[cert.NodeName+"/"+cert.Name]
+		res, warn := residual(expires)
+		tbl.AddRow(cert.NodeName, cert.Name, cert.Expires, expires, res)
+		if warn {
+			warnings = append(warnings, fmt.Sprintf("certificate %s on node %s still expires in %s after the renewal", cert.Name, cert.NodeName, res))
+		}
+	}
+	tbl.Print()
+
+	fmt.Fprintln(out)
+	tbl = printer.NewTablePrinter(out)
+	tbl.SetHeader("NODE", "CERTIFICATE-AUTHORITY", "EXPIRES", "RESIDUAL")
+	for _, ca := range before.CaCerts {
+		res, warn := residual(ca.Expires)
+		tbl.AddRow(ca.NodeName, ca.AuthorityName, ca.Expires, res)
+		if warn {
+			warnings = append(warnings, fmt.Sprintf("certificate authority %s on node %s expires in %s, it can't be renewed by `kbcli infra renew-certs`", ca.AuthorityName, ca.NodeName, res))
+		}
+	}
+	tbl.Print()
+
+	for _, w := range warnings {
+		printer.Warning(out, "%s\n", w)
+	}
+}
+
+// certResidual returns the residual time of the certificate, and whether it is to expire soon
+func certResidual(expires string, now time.Time) (string, bool) {
+	d, err := tasks.CertExpiresIn(expires, now)
+	if err != nil {
+		return "<unknown>", false
+	}
+	if d <= 0 {
+		return "expired", true
+	}
+	return duration.HumanDuration(d), d < certExpiryWarningThreshold
+}
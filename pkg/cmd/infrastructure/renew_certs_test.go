@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package infrastructure
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/tasks"
+)
+
+var _ = Describe("infra renew-certs test", func() {
+	const layout = "Jan 02, 2006 15:04 MST"
+	now := time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	newCerts := func(certExpires, caExpires time.Time) *tasks.ClusterCerts {
+		return &tasks.ClusterCerts{
+			Certs: []*certs.Certificate{
+				{Name: "apiserver.crt", NodeName: "node0", Expires: certExpires.Format(layout)},
+				{Name: "admin.conf", NodeName: "node0", Expires: certExpires.Format(layout)},
+			},
+			CaCerts: []*certs.CaCertificate{
+				{AuthorityName: "ca.crt", NodeName: "node0", Expires: caExpires.Format(layout)},
+			},
+		}
+	}
+
+	It("print the expiry and warn the certificates to expire", func() {
+		out := &bytes.Buffer{}
+		before := newCerts(now.Add(10*24*time.Hour), now.Add(20*24*time.Hour))
+		printCertsExpiry(out, before, nil, now)
+		Expect(out.String()).Should(ContainSubstring("apiserver.crt"))
+		Expect(out.String()).Should(ContainSubstring("certificate apiserver.crt on node node0 expires in 10d"))
+		Expect(out.String()).Should(ContainSubstring("certificate authority ca.crt on node node0 expires in 20d"))
+	})
+
+	It("print the expiry before and after the renewal", func() {
+		out := &bytes.Buffer{}
+		before := newCerts(now.Add(-time.Hour), now.Add(3650*24*time.Hour))
+		after := newCerts(now.Add(365*24*time.Hour), now.Add(3650*24*time.Hour))
+		printCertsExpiry(out, before, after, now)
+		Expect(out.String()).Should(ContainSubstring("EXPIRES-AFTER"))
+		Expect(out.String()).Should(ContainSubstring(after.Certs[0].Expires))
+		Expect(out.String()).ShouldNot(ContainSubstring("Warning"))
+	})
+})
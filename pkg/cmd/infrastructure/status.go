@@ -0,0 +1,152 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package infrastructure
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/tasks"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// diskPressureThreshold is the disk usage percent of the hosts to be warned, the kubelet
+// evicts the pods if the available disk is less than 10% by default.
+const diskPressureThreshold = 85
+
+type statusOptions struct {
+	clusterOptions
+}
+
+var statusExamples = templates.Examples(`
+	# report the status of the hosts and the control plane of the kubernetes cluster
+	kbcli infra status -c cluster.yaml
+`)
+
+func NewStatusCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &statusOptions{clusterOptions: clusterOptions{IOStreams: streams}}
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Report the status of the hosts and the control plane of the kubernetes cluster.",
+		Example: statusExamples,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+	buildCommonFlags(cmd, &o.clusterOptions)
+	return cmd
+}
+
+func (o *statusOptions) Run() error {
+	cluster, err := o.buildClusterSpec()
+	if err != nil {
+		return err
+	}
+	statuses := tasks.CollectClusterStatus(newKubeRuntime(o.clusterName, cluster, false))
+	printClusterStatus(o.IOStreams.Out, statuses, time.Now())
+	return nil
+}
+
+// printClusterStatus prints the statuses of the hosts and the control plane components, and the
+// warnings of the unhealthy hosts, components and the certificates to expire soon.
+func printClusterStatus(out io.Writer, statuses []*tasks.HostStatus, now time.Time) {
+	var warnings []string
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("NODE", "ADDRESS", "ROLES", "NODE-STATUS", "KUBELET", "CONTAINERD", "DISK-USAGE")
+	for _, s := range statuses {
+		roles := strings.Join(s.Roles, ",")
+		if !s.Reachable() {
+			tbl.AddRow(s.Name, s.Address, roles, "Unreachable", "", "", "")
+			warnings = append(warnings, fmt.Sprintf("host %s is unreachable: %s", s.Name, s.Error))
+			continue
+		}
+		diskUsage := "<unknown>"
+		if s.DiskUsage >= 0 {
+			diskUsage = fmt.Sprintf("%d%%", s.DiskUsage)
+		}
+		tbl.AddRow(s.Name, s.Address, roles, s.NodeStatus,
+			fmt.Sprintf("%s (%s)", s.KubeletVersion, s.KubeletStatus),
+			fmt.Sprintf("%s (%s)", s.RuntimeVersion, s.RuntimeStatus), diskUsage)
+		if s.KubeletStatus != "active" {
+			warnings = append(warnings, fmt.Sprintf("kubelet on host %s is %s", s.Name, s.KubeletStatus))
+		}
+		if s.RuntimeStatus != "active" {
+			warnings = append(warnings, fmt.Sprintf("containerd on host %s is %s", s.Name, s.RuntimeStatus))
+		}
+		if s.NodeStatus != "Ready" && !strings.HasPrefix(s.NodeStatus, "Ready,") {
+			warnings = append(warnings, fmt.Sprintf("node %s is %s", s.Name, s.NodeStatus))
+		}
+		if s.DiskUsage >= diskPressureThreshold {
+			warnings = append(warnings, fmt.Sprintf("disk of /var/lib on host %s is %d%% used, the node may be under disk pressure", s.Name, s.DiskUsage))
+		}
+	}
+	tbl.Print()
+
+	fmt.Fprintln(out)
+	tbl = printer.NewTablePrinter(out)
+	tbl.SetHeader("NODE", "COMPONENT", "STATUS")
+	for _, s := range statuses {
+		components := make([]string, 0, len(s.Components))
+		for c := range s.Components {
+			components = append(components, c)
+		}
+		sort.Strings(components)
+		for _, c := range components {
+			tbl.AddRow(s.Name, c, s.Components[c])
+			if s.Components[c] != "Running" {
+				warnings = append(warnings, fmt.Sprintf("%s on host %s is not running", c, s.Name))
+			}
+		}
+	}
+	tbl.Print()
+
+	for _, s := range statuses {
+		if s.Certs == nil {
+			continue
+		}
+		for _, cert := range s.Certs.Certs {
+			if res, warn := certResidual(cert.Expires, now); warn {
+				warnings = append(warnings, fmt.Sprintf("certificate %s on node %s expires in %s, please renew it by `kbcli infra renew-certs`", cert.Name, cert.NodeName, res))
+			}
+		}
+		for _, ca := range s.Certs.CaCerts {
+			if res, warn := certResidual(ca.Expires, now); warn {
+				warnings = append(warnings, fmt.Sprintf("certificate authority %s on node %s expires in %s", ca.AuthorityName, ca.NodeName, res))
+			}
+		}
+	}
+
+	if len(warnings) > 0 {
+		fmt.Fprintln(out)
+	}
+	for _, w := range warnings {
+		printer.Warning(out, "%s\n", w)
+	}
+}
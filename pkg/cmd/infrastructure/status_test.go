@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package infrastructure
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/kbcli/pkg/cmd/infrastructure/tasks"
+)
+
+var _ = Describe("infra status test", func() {
+	now := time.Date(2023, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	It("print the status of the healthy cluster", func() {
+		out := &bytes.Buffer{}
+		printClusterStatus(out, []*tasks.HostStatus{{
+			Name:           "node0",
+			Address:        "10.0.0.1",
+			Roles:          []string{"master", "etcd"},
+			NodeStatus:     "Ready",
+			KubeletStatus:  "active",
+			KubeletVersion: "v1.26.5",
+			RuntimeStatus:  "active",
+			RuntimeVersion: "v1.7.2",
+			DiskUsage:      42,
+			Components:     map[string]string{"kube-apiserver": "Running", "etcd": "Running"},
+			Certs: &tasks.ClusterCerts{Certs: []*certs.Certificate{
+				{Name: "apiserver.crt", NodeName: "node0", Expires: now.Add(300 * 24 * time.Hour).Format("Jan 02, 2006 15:04 MST")},
+			}},
+		}}, now)
+		Expect(out.String()).Should(ContainSubstring("v1.26.5 (active)"))
+		Expect(out.String()).Should(ContainSubstring("42%"))
+		Expect(out.String()).Should(ContainSubstring("kube-apiserver"))
+		Expect(out.String()).ShouldNot(ContainSubstring("Warning"))
+	})
+
+	It("warn the unhealthy hosts and components", func() {
+		out := &bytes.Buffer{}
+		printClusterStatus(out, []*tasks.HostStatus{
+			{Name: "node0", Error: "connection refused"},
+			{
+				Name:          "node1",
+				NodeStatus:    "NotReady",
+				KubeletStatus: "inactive",
+				RuntimeStatus: "active",
+				DiskUsage:     90,
+				Components:    map[string]string{"kube-scheduler": "NotRunning"},
+				Certs: &tasks.ClusterCerts{Certs: []*certs.Certificate{
+					{Name: "apiserver.crt", NodeName: "node1", Expires: now.Add(10 * 24 * time.Hour).Format("Jan 02, 2006 15:04 MST")},
+				}},
+			},
+		}, now)
+		Expect(out.String()).Should(ContainSubstring("host node0 is unreachable: connection refused"))
+		Expect(out.String()).Should(ContainSubstring("kubelet on host node1 is inactive"))
+		Expect(out.String()).Should(ContainSubstring("node node1 is NotReady"))
+		Expect(out.String()).Should(ContainSubstring("disk of /var/lib on host node1 is 90% used"))
+		Expect(out.String()).Should(ContainSubstring("kube-scheduler on host node1 is not running"))
+		Expect(out.String()).Should(ContainSubstring("certificate apiserver.crt on node node1 expires in 10d"))
+	})
+})
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
+
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+)
+
+// certExpiresLayout is the layout of the expiry time of the certificates listed by kubekey
+const certExpiresLayout = "Jan 02, 2006 15:04 MST"
+
+// ClusterCerts are the certificates of the control plane nodes listed by certs.CheckCertsModule
+type ClusterCerts struct {
+	Certs   []*certs.Certificate
+	CaCerts []*certs.CaCertificate
+}
+
+// CollectCertsModule collects the certificates listed by certs.CheckCertsModule from the host caches,
+// which are overwritten if the certificates are listed again after the renewal.
+type CollectCertsModule struct {
+	common.KubeModule
+	Result *ClusterCerts
+}
+
+// RenewCertsModule renews the certificates of the control plane like certs.RenewCertsModule, but
+// restarts the control plane components by crictl instead of docker.
+type RenewCertsModule struct {
+	common.KubeModule
+}
+
+func (c *CollectCertsModule) Init() {
+	c.Name = "CollectCertsModule"
+	c.Desc = "Collect the certificates of the control plane"
+	c.Tasks = []task.Interface{
+		&task.LocalTask{
+			Name:   "CollectCerts",
+			Desc:   "Collect the certificates of the control plane",
+			Action: &CollectCerts{Result: c.Result},
+		}}
+}
+
+func (r *RenewCertsModule) Init() {
+	r.Name = "RenewCertsModule"
+	r.Desc = "Renew the certificates of the control plane"
+	r.Tasks = []task.Interface{
+		&task.RemoteTask{
+			Name:   "RenewCerts",
+			Desc:   "Renew the certificates of the control plane",
+			Hosts:  r.Runtime.GetHostsByRole(common.Master),
+			Action: new(RenewCerts),
+			// the control plane nodes are restarted one by one
+			Parallel: false,
+			Retry:    2,
+		},
+		&task.RemoteTask{
+			Name:     "CopyKubeConfig",
+			Desc:     "Copy admin.conf to ~/.kube/config",
+			Hosts:    r.Runtime.GetHostsByRole(common.Master),
+			Action:   new(kubernetes.CopyKubeConfigForControlPlane),
+			Parallel: true,
+			Retry:    2,
+		}}
+}
+
+type CollectCerts struct {
+	common.KubeAction
+	Result *ClusterCerts
+}
+
+func (c *CollectCerts) Execute(runtime connector.Runtime) error {
+	for _, host := range runtime.GetHostsByRole(common.Master) {
+		hostCerts, ok := host.GetCache().Get(common.Certificate)
+		if !ok {
+			return cfgcore.MakeError("failed to get the certificates of host %s", host.GetName())
+		}
+		hostCaCerts, ok := host.GetCache().Get(common.CaCertificate)
+		if !ok {
+			return cfgcore.MakeError("failed to get the CA certificates of host %s", host.GetName())
+		}
+		c.Result.Certs = append(c.Result.Certs, hostCerts.([]*certs.Certificate)...)
+		c.Result.CaCerts = append(c.Result.CaCerts, hostCaCerts.([]*certs.CaCertificate)...)
+	}
+	return nil
+}
+
+type RenewCerts struct {
+	common.KubeAction
+}
+
+func (r *RenewCerts) Execute(runtime connector.Runtime) error {
+	var cmds []string
+	for _, cert := range []string{"apiserver", "apiserver-kubelet-client", "front-proxy-client", "admin.conf",
+		"controller-manager.conf", "scheduler.conf"} {
+		cmds = append(cmds, fmt.Sprintf("%s/kubeadm certs renew %s", common.BinDir, cert))
+	}
+	if _, err := runtime.GetRunner().SudoCmd(strings.Join(cmds, " && "), false); err != nil {
+		return cfgcore.WrapError(err, "failed to renew the certificates on host %s", runtime.RemoteHost().GetName())
+	}
+
+	// the static pods are restarted by the kubelet to load the new certificates
+	var restartCmds []string
+	for _, component := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler"} {
+		restartCmds = append(restartCmds, fmt.Sprintf("%s/crictl ps --name %s -q | xargs --no-run-if-empty %s/crictl stop", common.BinDir, component, common.BinDir))
+	}
+	restartCmds = append(restartCmds, "systemctl restart kubelet")
+	if _, err := runtime.GetRunner().SudoCmd(strings.Join(restartCmds, " && "), false); err != nil {
+		return cfgcore.WrapError(err, "failed to restart the control plane on host %s", runtime.RemoteHost().GetName())
+	}
+	return nil
+}
+
+// CertExpiresIn returns the duration until the certificate expires by the expiry time listed by kubekey
+func CertExpiresIn(expires string, now time.Time) (time.Duration, error) {
+	t, err := time.Parse(certExpiresLayout, expires)
+	if err != nil {
+		return 0, err
+	}
+	return t.Sub(now), nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
+
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+)
+
+const (
+	etcdEnvFile        = "/etc/etcd.env"
+	defaultEtcdDataDir = "/var/lib/etcd"
+	defaultEtcdToken   = "k8s_etcd"
+	remoteSnapshotPath = "/tmp/kbcli-etcd-snapshot.db"
+
+	kubeAPIServerManifest = "/etc/kubernetes/manifests/kube-apiserver.yaml"
+	// the manifest of the kube-apiserver is moved out of the manifests dir during the restore,
+	// so that the kube-apiserver is stopped by the kubelet and doesn't write to etcd.
+	kubeAPIServerManifestBackup = "/etc/kubernetes/kube-apiserver.yaml.etcd-restore"
+)
+
+type ETCDSnapshotModule struct {
+	common.KubeModule
+
+	// LocalPath is the local file to save the snapshot
+	LocalPath string
+}
+
+type ETCDRestoreModule struct {
+	common.KubeModule
+
+	// LocalPath is the local snapshot file to restore
+	LocalPath string
+}
+
+func (e *ETCDSnapshotModule) Init() {
+	e.Name = "ETCDSnapshotModule"
+	e.Desc = "Save the snapshot of etcd"
+
+	// the snapshot is taken from one member, it contains the data of the whole cluster
+	hosts := e.Runtime.GetHostsByRole(common.ETCD)
+	if len(hosts) > 1 {
+		hosts = hosts[:1]
+	}
+	e.Tasks = []task.Interface{
+		&task.RemoteTask{
+			Name:   "SaveETCDSnapshot",
+			Desc:   "Save the etcd snapshot to local",
+			Hosts:  hosts,
+			Action: &SaveETCDSnapshot{LocalPath: e.LocalPath},
+		}}
+}
+
+func (e *ETCDRestoreModule) Init() {
+	e.Name = "ETCDRestoreModule"
+	e.Desc = "Restore etcd from the snapshot"
+
+	masters := e.Runtime.GetHostsByRole(common.Master)
+	etcdHosts := e.Runtime.GetHostsByRole(common.ETCD)
+	firstMaster := masters
+	if len(masters) > 1 {
+		firstMaster = masters[:1]
+	}
+	e.Tasks = []task.Interface{
+		&task.RemoteTask{
+			Name:     "StopKubeAPIServer",
+			Desc:     "Stop kube-apiserver",
+			Hosts:    masters,
+			Action:   &sudoCommand{command: fmt.Sprintf("if [ -f %[1]s ]; then mv %[1]s %[2]s; fi", kubeAPIServerManifest, kubeAPIServerManifestBackup)},
+			Parallel: true,
+		},
+		&task.RemoteTask{
+			Name:     "StopETCD",
+			Desc:     "Stop etcd",
+			Hosts:    etcdHosts,
+			Action:   &sudoCommand{command: "systemctl stop etcd"},
+			Parallel: true,
+		},
+		&task.RemoteTask{
+			Name:     "RestoreETCDSnapshot",
+			Desc:     "Restore the etcd data from the snapshot",
+			Hosts:    etcdHosts,
+			Action:   &RestoreETCDSnapshot{LocalPath: e.LocalPath},
+			Parallel: true,
+		},
+		&task.RemoteTask{
+			// the members are started at the same time, otherwise the first one waits for the quorum
+			Name:     "StartETCD",
+			Desc:     "Start etcd",
+			Hosts:    etcdHosts,
+			Action:   &sudoCommand{command: "systemctl daemon-reload && systemctl start etcd"},
+			Parallel: true,
+		},
+		&task.RemoteTask{
+			Name:     "CheckETCDHealth",
+			Desc:     "Check the health of etcd",
+			Hosts:    etcdHosts,
+			Action:   new(CheckETCDHealth),
+			Parallel: true,
+			Retry:    20,
+			Delay:    5 * time.Second,
+		},
+		&task.RemoteTask{
+			Name:     "StartKubeAPIServer",
+			Desc:     "Start kube-apiserver",
+			Hosts:    masters,
+			Action:   &sudoCommand{command: fmt.Sprintf("if [ -f %[2]s ]; then mv %[2]s %[1]s; fi", kubeAPIServerManifest, kubeAPIServerManifestBackup)},
+			Parallel: true,
+		},
+		&task.RemoteTask{
+			Name:   "CheckKubeAPIServerHealth",
+			Desc:   "Check the health of kube-apiserver",
+			Hosts:  firstMaster,
+			Action: &sudoCommand{command: fmt.Sprintf("%s/kubectl --kubeconfig=/etc/kubernetes/admin.conf get --raw=/readyz", common.BinDir)},
+			Retry:  30,
+			Delay:  10 * time.Second,
+		}}
+}
+
+// sudoCommand runs the command with sudo on the host
+type sudoCommand struct {
+	common.KubeAction
+	command string
+}
+
+func (s *sudoCommand) Execute(runtime connector.Runtime) error {
+	_, err := runtime.GetRunner().SudoCmd(s.command, false)
+	return err
+}
+
+type SaveETCDSnapshot struct {
+	common.KubeAction
+	LocalPath string
+}
+
+func (s *SaveETCDSnapshot) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	runner := runtime.GetRunner()
+	// the snapshot is readable by the ssh user to be fetched
+	saveCmd := fmt.Sprintf("%s && chmod 644 %s", etcdctlCmd(host, "snapshot save "+remoteSnapshotPath), remoteSnapshotPath)
+	if _, err := runner.SudoCmd(saveCmd, false); err != nil {
+		return cfgcore.WrapError(err, "failed to save the etcd snapshot on host %s", host.GetName())
+	}
+	defer func() {
+		_, _ = runner.SudoCmd("rm -f "+remoteSnapshotPath, false)
+	}()
+	if err := runner.Fetch(s.LocalPath, remoteSnapshotPath); err != nil {
+		return cfgcore.WrapError(err, "failed to fetch the etcd snapshot from host %s", host.GetName())
+	}
+	return nil
+}
+
+type RestoreETCDSnapshot struct {
+	common.KubeAction
+	LocalPath string
+}
+
+func (r *RestoreETCDSnapshot) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	runner := runtime.GetRunner()
+	if err := runner.SudoScp(r.LocalPath, remoteSnapshotPath); err != nil {
+		return cfgcore.WrapError(err, "failed to copy the etcd snapshot to host %s", host.GetName())
+	}
+	envContent, err := runner.SudoCmd("cat "+etcdEnvFile, false)
+	if err != nil {
+		return cfgcore.WrapError(err, "failed to read %s on host %s", etcdEnvFile, host.GetName())
+	}
+	restoreCmd, err := buildSnapshotRestoreCmd(parseEtcdEnv(envContent), remoteSnapshotPath, time.Now().Format("20060102150405"))
+	if err != nil {
+		return cfgcore.WrapError(err, "failed to restore etcd on host %s", host.GetName())
+	}
+	if _, err = runner.SudoCmd(restoreCmd, false); err != nil {
+		return cfgcore.WrapError(err, "failed to restore etcd on host %s", host.GetName())
+	}
+	return nil
+}
+
+type CheckETCDHealth struct {
+	common.KubeAction
+}
+
+func (c *CheckETCDHealth) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd(etcdctlCmd(host, "endpoint health"), false); err != nil {
+		return cfgcore.WrapError(err, "etcd on host %s is not healthy", host.GetName())
+	}
+	return nil
+}
+
+// etcdctlCmd returns the etcdctl v3 command to the member on the host with the admin certificates
+func etcdctlCmd(host connector.Host, args string) string {
+	return fmt.Sprintf("export ETCDCTL_API=3; %[1]s/etcdctl --endpoints=https://%[2]s:2379 --cacert=%[3]s/ca.pem "+
+		"--cert=%[3]s/admin-%[4]s.pem --key=%[3]s/admin-%[4]s-key.pem %[5]s",
+		common.BinDir, host.GetInternalAddress(), common.ETCDCertDir, host.GetName(), args)
+}
+
+// parseEtcdEnv parses the environment file of etcd generated by kubekey
+func parseEtcdEnv(content string) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			env[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return env
+}
+
+// buildSnapshotRestoreCmd builds the command to restore the snapshot to the data dir of the member,
+// the current data dir is kept with the suffix of the timestamp.
+func buildSnapshotRestoreCmd(env map[string]string, snapshot, timestamp string) (string, error) {
+	for _, key := range []string{"ETCD_NAME", "ETCD_INITIAL_CLUSTER", "ETCD_INITIAL_ADVERTISE_PEER_URLS"} {
+		if env[key] == "" {
+			return "", cfgcore.MakeError("%s is not found in %s", key, etcdEnvFile)
+		}
+	}
+	dataDir := env["ETCD_DATA_DIR"]
+	if dataDir == "" {
+		dataDir = defaultEtcdDataDir
+	}
+	token := env["ETCD_INITIAL_CLUSTER_TOKEN"]
+	if token == "" {
+		token = defaultEtcdToken
+	}
+	restoreDir := dataDir + "-restore"
+	return fmt.Sprintf("rm -rf %[1]s && export ETCDCTL_API=3 && %[2]s/etcdctl snapshot restore %[3]s "+
+		"--name=%[4]s --initial-cluster=%[5]s --initial-cluster-token=%[6]s --initial-advertise-peer-urls=%[7]s --data-dir=%[1]s && "+
+		"if [ -d %[8]s ]; then mv %[8]s %[8]s.bak-%[9]s; fi && mv %[1]s %[8]s && rm -f %[3]s",
+		restoreDir, common.BinDir, snapshot, env["ETCD_NAME"], env["ETCD_INITIAL_CLUSTER"], token,
+		env["ETCD_INITIAL_ADVERTISE_PEER_URLS"], dataDir, timestamp), nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSnapshotRestoreCmd(t *testing.T) {
+	env := parseEtcdEnv(`# Environment file for etcd v3.4.26
+ETCD_DATA_DIR=/var/lib/etcd
+ETCD_INITIAL_ADVERTISE_PEER_URLS=https://10.0.0.1:2380
+ETCD_INITIAL_CLUSTER_TOKEN=k8s_etcd
+ETCD_NAME=etcd-node-0
+ETCD_INITIAL_CLUSTER=etcd-node-0=https://10.0.0.1:2380,etcd-node-1=https://10.0.0.2:2380
+`)
+	assert.Equal(t, "etcd-node-0", env["ETCD_NAME"])
+	assert.Equal(t, "etcd-node-0=https://10.0.0.1:2380,etcd-node-1=https://10.0.0.2:2380", env["ETCD_INITIAL_CLUSTER"])
+
+	cmd, err := buildSnapshotRestoreCmd(env, "/tmp/snapshot.db", "20231018120000")
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, "etcdctl snapshot restore /tmp/snapshot.db --name=etcd-node-0")
+	assert.Contains(t, cmd, "--initial-cluster=etcd-node-0=https://10.0.0.1:2380,etcd-node-1=https://10.0.0.2:2380")
+	assert.Contains(t, cmd, "--initial-advertise-peer-urls=https://10.0.0.1:2380")
+	assert.Contains(t, cmd, "--data-dir=/var/lib/etcd-restore")
+	assert.Contains(t, cmd, "mv /var/lib/etcd /var/lib/etcd.bak-20231018120000")
+
+	delete(env, "ETCD_NAME")
+	_, err = buildSnapshotRestoreCmd(env, "/tmp/snapshot.db", "20231018120000")
+	assert.ErrorContains(t, err, "ETCD_NAME is not found")
+}
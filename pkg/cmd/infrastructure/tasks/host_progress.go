@@ -0,0 +1,243 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+type hostState string
+
+const (
+	hostPending   hostState = "Pending"
+	hostRunning   hostState = "Running"
+	hostSucceeded hostState = "Succeeded"
+	hostFailed    hostState = "Failed"
+	hostAborted   hostState = "Aborted"
+)
+
+// hostStatus is the bootstrap status of a host, the logs are the results of the modules run
+// on the host, which are printed in the summary if the host is failed.
+type hostStatus struct {
+	name   string
+	state  hostState
+	module string
+	start  time.Time
+	end    time.Time
+	logs   []string
+}
+
+// hostProgress tracks the bootstrap of the hosts, a status line is printed whenever the status
+// of a host changes, and a summary of all hosts is printed at the end.
+type hostProgress struct {
+	out      io.Writer
+	hosts    []*hostStatus
+	batch    int
+	batches  int
+	nameSize int
+	// now is used to get the current time, it is replaced in tests
+	now func() time.Time
+}
+
+func newHostProgress(out io.Writer, hosts []connector.Host, batches int) *hostProgress {
+	p := &hostProgress{out: out, batches: batches, now: time.Now}
+	for _, h := range hosts {
+		p.hosts = append(p.hosts, &hostStatus{name: h.GetName(), state: hostPending})
+		if len(h.GetName()) > p.nameSize {
+			p.nameSize = len(h.GetName())
+		}
+	}
+	return p
+}
+
+func (p *hostProgress) get(name string) *hostStatus {
+	for _, h := range p.hosts {
+		if h.name == name {
+			return h
+		}
+	}
+	return nil
+}
+
+// startBatch starts the next batch of the hosts
+func (p *hostProgress) startBatch(hosts []connector.Host) {
+	p.batch++
+	if printer.Quiet() {
+		return
+	}
+	var names []string
+	for _, h := range hosts {
+		names = append(names, h.GetName())
+	}
+	fmt.Fprintf(p.out, "Bootstrapping hosts batch %d/%d: %v\n", p.batch, p.batches, names)
+}
+
+// startModule marks the hosts of the batch which are not failed as running the module
+func (p *hostProgress) startModule(hosts []connector.Host, module string) {
+	for _, h := range hosts {
+		s := p.get(h.GetName())
+		if s == nil || s.state == hostFailed || s.state == hostAborted {
+			continue
+		}
+		if s.start.IsZero() {
+			s.start = p.now()
+		}
+		s.state, s.module = hostRunning, module
+		p.printLine(s)
+	}
+}
+
+// finishModule updates the statuses of the hosts by the result of the module. If the module is
+// failed, the hosts without errors are aborted, since the rest modules are not run on them.
+func (p *hostProgress) finishModule(hosts []connector.Host, module string, res *ending.ModuleResult) {
+	for _, h := range hosts {
+		s := p.get(h.GetName())
+		if s == nil || s.state != hostRunning {
+			continue
+		}
+		r, ok := res.HostResults[s.name]
+		switch {
+		case ok && r.GetStatus() == ending.FAILED:
+			s.state = hostFailed
+			s.logs = append(s.logs, fmt.Sprintf("[%s] failed: %v", module, r.GetErr()))
+		case res.IsFailed() && len(failedHosts(res, hosts)) == 0:
+			// the module is failed without the results of the hosts, such as a local task
+			s.state = hostFailed
+			s.logs = append(s.logs, fmt.Sprintf("[%s] failed: %v", module, res.CombineResult))
+		case res.IsFailed():
+			s.state = hostAborted
+			s.logs = append(s.logs, fmt.Sprintf("[%s] aborted: the module is failed on other hosts", module))
+		default:
+			s.logs = append(s.logs, fmt.Sprintf("[%s] succeeded", module))
+			continue
+		}
+		s.end = p.now()
+		p.printLine(s)
+	}
+}
+
+// finishBatch marks the running hosts of the batch as succeeded
+func (p *hostProgress) finishBatch(hosts []connector.Host) {
+	for _, h := range hosts {
+		s := p.get(h.GetName())
+		if s == nil || s.state != hostRunning {
+			continue
+		}
+		s.state, s.end = hostSucceeded, p.now()
+		p.printLine(s)
+	}
+}
+
+func (p *hostProgress) printLine(s *hostStatus) {
+	if printer.Quiet() {
+		return
+	}
+	line := fmt.Sprintf("[%d/%d] %-*s %-10s %s", p.batch, p.batches, p.nameSize, s.name, s.state, s.module)
+	if s.state != hostRunning && !s.end.IsZero() {
+		line += " " + duration.HumanDuration(s.end.Sub(s.start))
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+// failed returns the names of the hosts which are not bootstrapped
+func (p *hostProgress) failed() []string {
+	var names []string
+	for _, s := range p.hosts {
+		if s.state != hostSucceeded {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
+
+// printSummary prints the statuses of all hosts and the collected logs of the failed hosts
+func (p *hostProgress) printSummary(logDir string) {
+	if printer.Quiet() {
+		return
+	}
+	fmt.Fprintln(p.out, "\nBootstrap summary:")
+	tbl := printer.NewTablePrinter(p.out)
+	tbl.SetHeader("HOST", "STATUS", "LAST-MODULE", "DURATION")
+	for _, s := range p.hosts {
+		elapsed := ""
+		if !s.end.IsZero() {
+			elapsed = duration.HumanDuration(s.end.Sub(s.start))
+		}
+		tbl.AddRow(s.name, s.state, s.module, elapsed)
+	}
+	tbl.Print()
+
+	failed := p.failed()
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Fprintf(p.out, "\n%d of %d hosts failed to bootstrap:\n", len(failed), len(p.hosts))
+	for _, s := range p.hosts {
+		if s.state == hostSucceeded {
+			continue
+		}
+		fmt.Fprintf(p.out, "  %s:\n", s.name)
+		if len(s.logs) == 0 {
+			fmt.Fprintln(p.out, "    not started")
+		}
+		for _, l := range s.logs {
+			fmt.Fprintf(p.out, "    %s\n", l)
+		}
+	}
+	if logDir != "" {
+		fmt.Fprintf(p.out, "\nThe full logs are in %s\n", logDir)
+	}
+}
+
+// failedHosts returns the names of the hosts which are failed in the module
+func failedHosts(res *ending.ModuleResult, hosts []connector.Host) []string {
+	var names []string
+	for _, h := range hosts {
+		if r, ok := res.HostResults[h.GetName()]; ok && r.GetStatus() == ending.FAILED {
+			names = append(names, h.GetName())
+		}
+	}
+	return names
+}
+
+// splitHostBatches splits the hosts into the batches of the size, all hosts are in one batch
+// if the size is not positive.
+func splitHostBatches(hosts []connector.Host, size int) [][]connector.Host {
+	if size <= 0 || size >= len(hosts) {
+		return [][]connector.Host{hosts}
+	}
+	var batches [][]connector.Host
+	for i := 0; i < len(hosts); i += size {
+		end := i + size
+		if end > len(hosts) {
+			end = len(hosts)
+		}
+		batches = append(batches, hosts[i:end])
+	}
+	return batches
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHosts(names ...string) []connector.Host {
+	var hosts []connector.Host
+	for _, name := range names {
+		h := connector.NewHost()
+		h.Name = name
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+func TestSplitHostBatches(t *testing.T) {
+	hosts := newTestHosts("node-0", "node-1", "node-2", "node-3", "node-4")
+	assert.Len(t, splitHostBatches(hosts, 0), 1)
+	assert.Len(t, splitHostBatches(hosts, 10), 1)
+
+	batches := splitHostBatches(hosts, 2)
+	assert.Len(t, batches, 3)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[2], 1)
+	assert.Equal(t, "node-4", batches[2][0].GetName())
+}
+
+func TestHostProgress(t *testing.T) {
+	out := &bytes.Buffer{}
+	hosts := newTestHosts("node-0", "node-1", "node-2")
+	batches := splitHostBatches(hosts, 2)
+	p := newHostProgress(out, hosts, len(batches))
+	now := time.Now()
+	p.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	// the first batch fails on node-1, node-0 is aborted since the rest modules are not run on it
+	p.startBatch(batches[0])
+	p.startModule(batches[0], "InstallDependenciesModule")
+	res := ending.NewModuleResult()
+	res.AppendHostResult(&ending.ActionResult{Host: hosts[0], Status: ending.SUCCESS})
+	res.AppendHostResult(&ending.ActionResult{Host: hosts[1], Status: ending.FAILED, Error: fmt.Errorf("apt-get: unable to locate package socat")})
+	res.ErrResult(fmt.Errorf("module failed"))
+	p.finishModule(batches[0], "InstallDependenciesModule", res)
+	p.finishBatch(batches[0])
+	assert.Contains(t, out.String(), "Bootstrapping hosts batch 1/2")
+	assert.Contains(t, out.String(), "[1/2] node-1 Failed     InstallDependenciesModule")
+
+	// the second batch succeeds
+	p.startBatch(batches[1])
+	p.startModule(batches[1], "InstallDependenciesModule")
+	res = ending.NewModuleResult()
+	res.AppendHostResult(&ending.ActionResult{Host: hosts[2], Status: ending.SUCCESS})
+	res.NormalResult()
+	p.finishModule(batches[1], "InstallDependenciesModule", res)
+	p.finishBatch(batches[1])
+	assert.Contains(t, out.String(), "[2/2] node-2 Succeeded")
+
+	assert.Equal(t, []string{"node-0", "node-1"}, p.failed())
+	out.Reset()
+	p.printSummary("/tmp/kubekey/logs")
+	assert.Contains(t, out.String(), "2 of 3 hosts failed to bootstrap")
+	assert.Contains(t, out.String(), "unable to locate package socat")
+	assert.Contains(t, out.String(), "aborted")
+	assert.Contains(t, out.String(), "/tmp/kubekey/logs")
+}
+
+func TestHostProgressLocalFailure(t *testing.T) {
+	hosts := newTestHosts("node-0")
+	p := newHostProgress(&bytes.Buffer{}, hosts, 1)
+	p.startBatch(hosts)
+	p.startModule(hosts, "CheckNodeArchitectureModule")
+	res := ending.NewModuleResult()
+	res.LocalErrResult(fmt.Errorf("local failure"))
+	p.finishModule(hosts, "CheckNodeArchitectureModule", res)
+	assert.Equal(t, hostFailed, p.hosts[0].state)
+	assert.Contains(t, p.hosts[0].logs[0], "local failure")
+}
+
+func TestGenerateClusterHosts(t *testing.T) {
+	cluster := &kubekeyapiv1alpha2.ClusterSpec{
+		Hosts: []kubekeyapiv1alpha2.HostCfg{
+			{Name: "node-0", InternalAddress: "10.0.0.1"},
+			{Name: "node-1", InternalAddress: "10.0.0.2"},
+		},
+		RoleGroups: map[string][]string{
+			common.Master: {"node-0"},
+			common.ETCD:   {"node-0"},
+			common.Worker: {"node-1"},
+		},
+		ControlPlaneEndpoint: kubekeyapiv1alpha2.ControlPlaneEndpoint{Domain: "lb.kubeblocks.local"},
+		Kubernetes:           kubekeyapiv1alpha2.Kubernetes{ClusterName: "cluster.local"},
+	}
+	assert.Equal(t, []string{
+		"10.0.0.1  node-0.cluster.local node-0",
+		"10.0.0.2  node-1.cluster.local node-1",
+		"10.0.0.1  lb.kubeblocks.local",
+	}, generateClusterHosts(&common.KubeConf{Cluster: cluster}))
+}
@@ -27,7 +27,6 @@ import (
 	"github.com/StudioSol/set"
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/os"
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/os/templates"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
@@ -96,7 +95,7 @@ func (c *ConfigureNodeOSModule) Init() {
 			Desc:  "Generate init os script",
 			Hosts: c.Runtime.GetAllHosts(),
 			Action: &NodeScriptGenerator{
-				Hosts: templates.GenerateHosts(c.Runtime, c.KubeConf),
+				Hosts: generateClusterHosts(c.KubeConf),
 				Nodes: c.Nodes,
 			},
 			Parallel: true,
@@ -122,6 +121,29 @@ func (p *SaveKubeConfigModule) Init() {
 		}}
 }
 
+// generateClusterHosts generates the entries of /etc/hosts like templates.GenerateHosts, but from
+// all hosts of the cluster instead of the runtime, which has only the hosts of the current batch
+// if the hosts are bootstrapped in batches.
+func generateClusterHosts(kubeConf *common.KubeConf) []string {
+	cluster := kubeConf.Cluster
+	masters := cluster.RoleGroups[kubekeyapiv1alpha2.Master]
+	if len(masters) == 0 {
+		masters = cluster.RoleGroups[kubekeyapiv1alpha2.ControlPlane]
+	}
+	lbAddress := cluster.ControlPlaneEndpoint.Address
+	var hosts []string
+	for _, host := range cluster.Hosts {
+		if host.Name == "" {
+			continue
+		}
+		hosts = append(hosts, fmt.Sprintf("%s  %s.%s %s", host.InternalAddress, host.Name, cluster.Kubernetes.ClusterName, host.Name))
+		if lbAddress == "" && len(masters) > 0 && masters[0] == host.Name {
+			lbAddress = host.InternalAddress
+		}
+	}
+	return append(hosts, fmt.Sprintf("%s  %s", lbAddress, cluster.ControlPlaneEndpoint.Domain))
+}
+
 type DownloadKubernetesBinary struct {
 	common.KubeAction
 	BinaryVersion types.InfraVersionInfo
@@ -22,7 +22,11 @@ package tasks
 import (
 	"fmt"
 	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
 
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
@@ -30,10 +34,28 @@ import (
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
 
 	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+
+	"github.com/apecloud/kbcli/pkg/printer/progress"
 )
 
 type PipelineWrapper struct {
 	pipeline.Pipeline
+
+	// batchSize is the count of the hosts bootstrapped at the same time, all hosts are
+	// bootstrapped at once if it is not positive
+	batchSize int
+	verbose   bool
+}
+
+// bootstrapModule marks the module which bootstraps each host independently, such as installing
+// the dependencies, the consecutive bootstrap modules are run on the hosts in batches.
+type bootstrapModule struct {
+	module.Module
+}
+
+// Bootstrap marks the module as a bootstrap module
+func Bootstrap(m module.Module) module.Module {
+	return &bootstrapModule{Module: m}
 }
 
 func NewPipelineRunner(name string, modules []module.Module, runtime connector.Runtime) *PipelineWrapper {
@@ -48,26 +70,116 @@ func NewPipelineRunner(name string, modules []module.Module, runtime connector.R
 	}
 }
 
+// WithBootstrapBatch sets the count of the hosts bootstrapped at the same time, the verbose is
+// the log level of the runtimes of the batches.
+func (w *PipelineWrapper) WithBootstrapBatch(size int, verbose bool) *PipelineWrapper {
+	w.batchSize = size
+	w.verbose = verbose
+	return w
+}
+
 func (w *PipelineWrapper) Do(output io.Writer) error {
 	defer func() {
 		w.PipelineCache.Clean()
 		w.releaseHostsConnector()
 	}()
 
+	var modules []module.Module
 	for i := range w.Modules {
-		m := w.Modules[i]
-		if m.IsSkip() {
+		if !w.Modules[i].IsSkip() {
+			modules = append(modules, w.Modules[i])
+		}
+	}
+	// the modules print their own logs, so the progress is printed line by line
+	bar := progress.NewBar(output, w.Name, len(modules)).WithoutRedraw()
+	defer bar.Done()
+	for i := 0; i < len(modules); {
+		m := modules[i]
+		if isBootstrapModule(m) {
+			// run the consecutive bootstrap modules together, so each batch of the hosts
+			// is bootstrapped by all of them before the next batch
+			j := i
+			for j < len(modules) && isBootstrapModule(modules[j]) {
+				j++
+			}
+			bar.Update(i, len(modules), "BootstrapHosts")
+			if err := w.bootstrapHosts(output, modules[i:j]); err != nil {
+				return err
+			}
+			i = j
 			continue
 		}
-		if res := w.safeRunModule(m); res.IsFailed() {
+		bar.Update(i, len(modules), moduleDisplayName(m))
+		if res := w.safeRunModule(m, w.Runtime); res.IsFailed() {
 			return cfgcore.WrapError(res.CombineResult, "failed to execute module: %s", getModuleName(m))
 		}
+		i++
 	}
+	bar.Update(len(modules), len(modules), "")
 	fmt.Fprintf(output, "succeed to execute all modules in the pipeline[%s]", w.Name)
 	return nil
 }
 
-func (w *PipelineWrapper) safeRunModule(m module.Module) *ending.ModuleResult {
+// bootstrapHosts runs the bootstrap modules on the hosts batch by batch. The failure of a batch
+// doesn't stop the others, so all the failed hosts are reported together in the summary.
+func (w *PipelineWrapper) bootstrapHosts(output io.Writer, modules []module.Module) error {
+	hosts := w.Runtime.GetAllHosts()
+	batches := splitHostBatches(hosts, w.batchSize)
+	progress := newHostProgress(output, hosts, len(batches))
+	for _, batch := range batches {
+		runtime, err := w.newBatchRuntime(batch, len(batches) > 1)
+		if err != nil {
+			return err
+		}
+		progress.startBatch(batch)
+		for _, m := range modules {
+			name := moduleDisplayName(m)
+			progress.startModule(batch, name)
+			res := w.safeRunModule(m, runtime)
+			progress.finishModule(batch, name, res)
+			if res.IsFailed() {
+				break
+			}
+		}
+		progress.finishBatch(batch)
+	}
+
+	logDir := ""
+	if workDir := w.Runtime.GetWorkDir(); workDir != "" {
+		logDir = filepath.Join(workDir, "logs")
+	}
+	progress.printSummary(logDir)
+	if failed := progress.failed(); len(failed) > 0 {
+		return cfgcore.MakeError("failed to bootstrap hosts: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// newBatchRuntime returns the runtime with the hosts of the batch only, which shares the
+// connector with the pipeline runtime.
+func (w *PipelineWrapper) newBatchRuntime(hosts []connector.Host, batched bool) (connector.Runtime, error) {
+	if !batched {
+		return w.Runtime, nil
+	}
+	kubeRuntime, ok := w.Runtime.(*common.KubeRuntime)
+	if !ok {
+		return nil, cfgcore.MakeError("the hosts can not be bootstrapped in batches with the runtime %T", w.Runtime)
+	}
+	runtime := &common.KubeRuntime{
+		BaseRuntime: connector.NewBaseRuntime(kubeRuntime.ObjName, kubeRuntime.GetConnector(), w.verbose, kubeRuntime.GetIgnoreErr()),
+		ClusterName: kubeRuntime.ClusterName,
+		Cluster:     kubeRuntime.Cluster,
+		Kubeconfig:  kubeRuntime.Kubeconfig,
+		Arg:         kubeRuntime.Arg,
+	}
+	for _, host := range hosts {
+		runtime.BaseRuntime.AppendHost(host)
+		runtime.BaseRuntime.AppendRoleMap(host)
+	}
+	return runtime, nil
+}
+
+func (w *PipelineWrapper) safeRunModule(m module.Module, runtime connector.Runtime) *ending.ModuleResult {
 	newCache := func() *cache.Cache {
 		if moduleCache, ok := w.ModuleCachePool.Get().(*cache.Cache); ok {
 			return moduleCache
@@ -81,7 +193,7 @@ func (w *PipelineWrapper) safeRunModule(m module.Module) *ending.ModuleResult {
 
 	moduleCache := newCache()
 	defer releaseCache(moduleCache)
-	m.Default(w.Runtime, w.PipelineCache, moduleCache)
+	m.Default(runtime, w.PipelineCache, moduleCache)
 	m.AutoAssert()
 	m.Init()
 	return w.RunModule(m)
@@ -95,9 +207,30 @@ func (w *PipelineWrapper) releaseHostsConnector() {
 	}
 }
 
+// moduleDisplayName returns the name of the module, the type name is used if the module
+// is not initialized yet.
+func moduleDisplayName(m module.Module) string {
+	if b, ok := m.(*bootstrapModule); ok {
+		m = b.Module
+	}
+	if name := getModuleName(m); name != "" {
+		return name
+	}
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
 func getModuleName(m module.Module) string {
 	if b, ok := m.(*module.BaseModule); ok {
 		return b.Name
 	}
 	return ""
 }
+
+func isBootstrapModule(m module.Module) bool {
+	_, ok := m.(*bootstrapModule)
+	return ok
+}
@@ -0,0 +1,202 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+)
+
+// ControlPlaneComponents are the components checked on the master hosts, which run as static pods
+var ControlPlaneComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler"}
+
+const unknownStatus = "<unknown>"
+
+// HostStatus is the status of a host of the cluster collected by ssh
+type HostStatus struct {
+	Name    string
+	Address string
+	Roles   []string
+	// Error is the error to connect to the host, the other fields are empty if it is set
+	Error string
+
+	// NodeStatus is the status of the kubernetes node, such as Ready or NotReady
+	NodeStatus     string
+	KubeletStatus  string
+	KubeletVersion string
+	RuntimeStatus  string
+	RuntimeVersion string
+	// DiskUsage is the usage percent of the disk of /var/lib, which stores the data of
+	// the kubelet, the container runtime and etcd. It is -1 if unknown.
+	DiskUsage int
+
+	// Components are the statuses of the control plane components and etcd on the host
+	Components map[string]string
+	// Certs are the control plane certificates on the master host
+	Certs *ClusterCerts
+}
+
+func (h *HostStatus) Reachable() bool {
+	return h.Error == ""
+}
+
+// CollectClusterStatus collects the statuses of all hosts in parallel, the unreachable hosts are
+// reported in the statuses instead of failing the collection.
+func CollectClusterStatus(runtime connector.Runtime) []*HostStatus {
+	hosts := runtime.GetAllHosts()
+	defer func() {
+		for _, host := range hosts {
+			runtime.GetConnector().Close(host)
+		}
+	}()
+	statuses := make([]*HostStatus, len(hosts))
+	runners := make([]*connector.Runner, len(hosts))
+	wg := sync.WaitGroup{}
+	for i := range hosts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i], runners[i] = collectHostStatus(runtime, hosts[i])
+		}(i)
+	}
+	wg.Wait()
+
+	// the node statuses are got from the first reachable master
+	for i, host := range hosts {
+		if !host.IsRole(common.Master) || runners[i] == nil {
+			continue
+		}
+		out, _, err := runners[i].SudoExec(fmt.Sprintf("%s/kubectl --kubeconfig=/etc/kubernetes/admin.conf get nodes --no-headers", common.BinDir), false)
+		if err != nil {
+			continue
+		}
+		nodes := parseNodeStatuses(out)
+		for _, s := range statuses {
+			if status, ok := nodes[s.Name]; ok {
+				s.NodeStatus = status
+			}
+		}
+		break
+	}
+	return statuses
+}
+
+func collectHostStatus(runtime connector.Runtime, host connector.Host) (*HostStatus, *connector.Runner) {
+	status := &HostStatus{
+		Name:       host.GetName(),
+		Address:    host.GetAddress(),
+		Roles:      host.GetRoles(),
+		NodeStatus: unknownStatus,
+		DiskUsage:  -1,
+		Components: map[string]string{},
+	}
+	conn, err := runtime.GetConnector().Connect(host)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+	runner := &connector.Runner{Conn: conn, Host: host}
+	// the output is used even if the command fails, such as "inactive" of systemctl is-active
+	output := func(cmd string) string {
+		out, _, _ := runner.SudoExec(cmd, false)
+		return strings.TrimSpace(out)
+	}
+
+	status.KubeletStatus = valueOrUnknown(output("systemctl is-active kubelet"))
+	status.KubeletVersion = valueOrUnknown(parseVersion(output(common.BinDir+"/kubelet --version"), 1))
+	status.RuntimeStatus = valueOrUnknown(output("systemctl is-active containerd"))
+	status.RuntimeVersion = valueOrUnknown(parseVersion(output("containerd --version"), 2))
+	status.DiskUsage = parseDiskUsage(output("df -P /var/lib | tail -1"))
+
+	if host.IsRole(common.Master) {
+		for _, component := range ControlPlaneComponents {
+			id := output(fmt.Sprintf("%s/crictl ps --name %s --state running -q | head -1", common.BinDir, component))
+			status.Components[component] = componentStatus(id != "")
+		}
+		rt := runtime.Copy()
+		rt.SetRunner(runner)
+		if err = new(certs.ListClusterCerts).Execute(rt); err == nil {
+			hostCerts, _ := host.GetCache().Get(common.Certificate)
+			hostCaCerts, _ := host.GetCache().Get(common.CaCertificate)
+			status.Certs = &ClusterCerts{}
+			status.Certs.Certs, _ = hostCerts.([]*certs.Certificate)
+			status.Certs.CaCerts, _ = hostCaCerts.([]*certs.CaCertificate)
+		}
+	}
+	if host.IsRole(common.ETCD) {
+		status.Components["etcd"] = componentStatus(output("systemctl is-active etcd") == "active")
+	}
+	return status, runner
+}
+
+func componentStatus(running bool) string {
+	if running {
+		return "Running"
+	}
+	return "NotRunning"
+}
+
+func valueOrUnknown(v string) string {
+	if v == "" {
+		return unknownStatus
+	}
+	return v
+}
+
+// parseVersion returns the field of the version output, such as "v1.26.5" of "Kubernetes v1.26.5"
+func parseVersion(out string, field int) string {
+	fields := strings.Fields(out)
+	if len(fields) <= field {
+		return ""
+	}
+	return fields[field]
+}
+
+// parseDiskUsage parses the usage percent from the output of df -P, it returns -1 if unknown
+func parseDiskUsage(out string) int {
+	fields := strings.Fields(out)
+	if len(fields) < 5 {
+		return -1
+	}
+	usage, err := strconv.Atoi(strings.TrimSuffix(fields[4], "%"))
+	if err != nil {
+		return -1
+	}
+	return usage
+}
+
+// parseNodeStatuses parses the statuses of the nodes from the output of kubectl get nodes --no-headers
+func parseNodeStatuses(out string) map[string]string {
+	nodes := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		nodes[fields[0]] = fields[1]
+	}
+	return nodes
+}
@@ -0,0 +1,41 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostStatus(t *testing.T) {
+	assert.Equal(t, "v1.26.5", parseVersion("Kubernetes v1.26.5", 1))
+	assert.Equal(t, "v1.7.2", parseVersion("containerd github.com/containerd/containerd v1.7.2 0cae528dd6cb557f7201036e9f43420650207b58", 2))
+	assert.Equal(t, "", parseVersion("", 1))
+
+	assert.Equal(t, 42, parseDiskUsage("/dev/vda1  102687672 43128416 59542872  42% /"))
+	assert.Equal(t, -1, parseDiskUsage("df: /var/lib: No such file or directory"))
+
+	nodes := parseNodeStatuses(`node0   Ready                      control-plane   10d   v1.26.5
+node1   NotReady                   <none>          10d   v1.26.5
+node2   Ready,SchedulingDisabled   <none>          10d   v1.26.5
+`)
+	assert.Equal(t, map[string]string{"node0": "Ready", "node1": "NotReady", "node2": "Ready,SchedulingDisabled"}, nodes)
+}
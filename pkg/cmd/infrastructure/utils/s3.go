@@ -0,0 +1,118 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const s3Scheme = "s3://"
+
+// S3Location is the location of an object in the S3-compatible storage, such as s3://bucket/key
+type S3Location struct {
+	Bucket string
+	Key    string
+}
+
+func (l S3Location) String() string {
+	return s3Scheme + l.Bucket + "/" + l.Key
+}
+
+// IsS3URL returns true if the url is an S3 url
+func IsS3URL(url string) bool {
+	return strings.HasPrefix(url, s3Scheme)
+}
+
+// ParseS3URL parses the url like s3://bucket/key, the key is empty if the url is the bucket only
+func ParseS3URL(url string) (S3Location, error) {
+	if !IsS3URL(url) {
+		return S3Location{}, fmt.Errorf("invalid S3 url %s, it should be like s3://bucket/key", url)
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(url, s3Scheme), "/")
+	if bucket == "" {
+		return S3Location{}, fmt.Errorf("the bucket is not found in S3 url %s", url)
+	}
+	return S3Location{Bucket: bucket, Key: strings.Trim(key, "/")}, nil
+}
+
+// S3Options are the options to access the S3-compatible storage, the credentials are loaded from
+// the environment variables or the shared config of AWS, such as AWS_ACCESS_KEY_ID.
+type S3Options struct {
+	Endpoint string
+	Region   string
+}
+
+func (o S3Options) newSession() (*session.Session, error) {
+	cfg := aws.NewConfig()
+	if o.Region != "" {
+		cfg = cfg.WithRegion(o.Region)
+	}
+	if o.Endpoint != "" {
+		cfg = cfg.WithEndpoint(o.Endpoint).WithS3ForcePathStyle(true)
+	}
+	return session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
+
+// UploadToS3 uploads the local file to the location
+func UploadToS3(opts S3Options, location S3Location, localPath string) error {
+	sess, err := opts.newSession()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = s3manager.NewUploader(sess).Upload(&s3manager.UploadInput{
+		Bucket: aws.String(location.Bucket),
+		Key:    aws.String(location.Key),
+		Body:   f,
+	})
+	return err
+}
+
+// DownloadFromS3 downloads the object of the location to the local file
+func DownloadFromS3(opts S3Options, location S3Location, localPath string) error {
+	sess, err := opts.newSession()
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = s3manager.NewDownloader(sess).Download(f, &s3.GetObjectInput{
+		Bucket: aws.String(location.Bucket),
+		Key:    aws.String(location.Key),
+	})
+	return err
+}
@@ -39,7 +39,10 @@ var (
 	kbcli kubeblocks compare 0.4.0
 
 	# compare two specified KubeBlocks version
-	kbcli kubeblocks compare 0.4.0 0.5.0`)
+	kbcli kubeblocks compare 0.4.0 0.5.0
+
+	# compare two specified KubeBlocks version by flags
+	kbcli kubeblocks compare --from 0.8.1 --to 0.9.0`)
 )
 
 func newCompareCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -48,10 +51,16 @@ func newCompareCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 			IOStreams: streams,
 		},
 	}
-	var showDetail bool
+	var (
+		showDetail bool
+		from, to   string
+	)
 	cmd := &cobra.Command{
-		Use:     "compare version [OTHER-VERSION]",
-		Short:   "List the changes between two different version KubeBlocks.",
+		Use:   "compare version [OTHER-VERSION]",
+		Short: "List the changes between two different version KubeBlocks.",
+		Long: templates.LongDesc(`List the changes between two different version KubeBlocks, including the CRD schemas
+		and the default values of the chart. If KubeBlocks is installed, the values overridden by user whose defaults
+		are changed or removed in the target version are highlighted, as their meaning may change after upgrading.`),
 		Args:    cobra.MaximumNArgs(2),
 		Example: diffExample,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -67,13 +76,34 @@ func newCompareCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.Complete(f, cmd))
+			args, err := buildCompareArgs(args, from, to)
+			util.CheckErr(err)
 			util.CheckErr(o.compare(args, showDetail))
 		},
 	}
 	cmd.Flags().BoolVar(&showDetail, "details", false, "show the different details between two kubeblocks version")
+	cmd.Flags().StringVar(&from, "from", "", "The version to compare from, default is the installed version")
+	cmd.Flags().StringVar(&to, "to", "", "The version to compare to")
 	return cmd
 }
 
+// buildCompareArgs converts the --from and --to flags to the version args
+func buildCompareArgs(args []string, from, to string) ([]string, error) {
+	if from == "" && to == "" {
+		return args, nil
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("the versions can not be specified by both args and flags")
+	}
+	if to == "" {
+		return nil, fmt.Errorf("--to is required when --from is specified")
+	}
+	if from == "" {
+		return []string{to}, nil
+	}
+	return []string{from, to}, nil
+}
+
 // validateCompareVersion validate the user inputs version and save the valid value into versionA and versionB
 func (o *InstallOptions) validateCompareVersion(args []string, versionA, versionB *string) error {
 	switch len(args) {
@@ -149,5 +179,30 @@ func (o *InstallOptions) showDiff(version1, version2 string, detail bool) error
 	if err != nil {
 		return err
 	}
-	return helm.OutputDiff(releaseA, releaseB, version1, version2, o.Out, detail)
+	if err = helm.OutputDiff(releaseA, releaseB, version1, version2, o.Out, detail); err != nil {
+		return err
+	}
+	overrides, err := o.getOverriddenValues()
+	if err != nil {
+		return err
+	}
+	helm.OutputValuesDiff(releaseA, releaseB, overrides, version1, version2, o.Out)
+	return nil
+}
+
+// getOverriddenValues gets the values overridden by user of the installed KubeBlocks,
+// it returns nil if KubeBlocks is not installed.
+func (o *InstallOptions) getOverriddenValues() (map[string]interface{}, error) {
+	if len(o.HelmCfg.Namespace()) == 0 {
+		namespace, err := util.GetKubeBlocksNamespace(o.Client)
+		if err != nil {
+			return nil, nil
+		}
+		o.HelmCfg.SetNamespace(namespace)
+	}
+	values, err := helm.GetUserValues(types.KubeBlocksReleaseName, o.HelmCfg)
+	if helm.ReleaseNotFound(err) {
+		return nil, nil
+	}
+	return values, err
 }
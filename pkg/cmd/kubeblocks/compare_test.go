@@ -71,4 +71,19 @@ var _ = Describe("kubeblocks compare", func() {
 			Expect(o.compare([]string{"0.5.0", "0.5.1"}, true)).Should(HaveOccurred())
 		})
 	})
+	It("build compare args", func() {
+		args, err := buildCompareArgs([]string{"0.8.1"}, "", "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(args).Should(Equal([]string{"0.8.1"}))
+		args, err = buildCompareArgs(nil, "0.8.1", "0.9.0")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(args).Should(Equal([]string{"0.8.1", "0.9.0"}))
+		args, err = buildCompareArgs(nil, "", "0.9.0")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(args).Should(Equal([]string{"0.9.0"}))
+		_, err = buildCompareArgs(nil, "0.8.1", "")
+		Expect(err).Should(HaveOccurred())
+		_, err = buildCompareArgs([]string{"0.8.1"}, "0.8.1", "0.9.0")
+		Expect(err).Should(HaveOccurred())
+	})
 })
@@ -22,8 +22,11 @@ package kubeblocks
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -47,6 +50,8 @@ import (
 
 	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
 
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/printer/progress"
 	"github.com/apecloud/kbcli/pkg/spinner"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
@@ -91,6 +96,12 @@ type InstallOptions struct {
 	TopologyKeys    []string
 	NodeLabels      map[string]string
 	TolerationsRaw  []string
+
+	// HTTPSProxy and NoProxy are the proxy settings to fetch the charts behind a proxy
+	HTTPSProxy string
+	NoProxy    string
+	// ChartRepoCAFile is the CA bundle to verify the certificate of the chart repository
+	ChartRepoCAFile string
 }
 
 type addonStatus struct {
@@ -115,7 +126,10 @@ var (
 	kbcli kubeblocks install --namespace=my-namespace --create-namespace
 
 	# Install KubeBlocks with other settings, for example, set replicaCount to 3
-	kbcli kubeblocks install --set replicaCount=3`)
+	kbcli kubeblocks install --set replicaCount=3
+
+	# Install KubeBlocks behind a proxy, and trust the private CA of the chart repository
+	kbcli kubeblocks install --https-proxy http://proxy.example.com:3128 --no-proxy .example.com --chart-repo-ca-file ca.crt`)
 
 	spinnerMsg = func(format string, a ...any) spinner.Option {
 		return spinner.WithMessage(fmt.Sprintf("%-50s", fmt.Sprintf(format, a...)))
@@ -143,6 +157,7 @@ func newInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 		Example: installExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.Complete(f, cmd))
+			util.CheckErr(o.CompleteNetworkOptions())
 			util.CheckErr(o.PreCheck())
 			util.CheckErr(o.CompleteInstallOptions())
 			util.CheckErr(p.Preflight(f, args, o.ValueOpts))
@@ -160,6 +175,7 @@ func newInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().StringArrayVar(&o.TopologyKeys, "topology-keys", nil, "Topology keys for affinity")
 	cmd.Flags().StringToStringVar(&o.NodeLabels, "node-labels", nil, "Node label selector")
 	cmd.Flags().StringSliceVar(&o.TolerationsRaw, "tolerations", nil, `Tolerations for Kubeblocks, such as '"dev=true:NoSchedule,large=true:NoSchedule"'`)
+	o.addNetworkFlags(cmd)
 	helm.AddValueOptionsFlags(cmd.Flags(), &o.ValueOpts)
 
 	return cmd
@@ -234,6 +250,39 @@ func (o *InstallOptions) PreCheck() error {
 	return nil
 }
 
+// addNetworkFlags adds the flags to fetch the charts behind a proxy or from a repository with a private CA
+func (o *InstallOptions) addNetworkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.HTTPSProxy, "https-proxy", "", "The proxy to fetch the charts and check the versions, it overrides the HTTPS_PROXY environment variable for these requests only, the Kubernetes API server is not accessed by it. "+
+		"It's not applied to pull the images, configure the proxy of the container runtime on the nodes instead")
+	cmd.Flags().StringVar(&o.NoProxy, "no-proxy", "", "The comma separated hosts, domains and CIDRs which are accessed without the proxy when fetching the charts, it overrides the NO_PROXY environment variable for these requests only")
+	cmd.Flags().StringVar(&o.ChartRepoCAFile, "chart-repo-ca-file", "", "The CA bundle in PEM format to verify the certificate of the KubeBlocks chart repository. "+
+		"It's not applied to pull the images, configure the CA of the image registry in the container runtime on the nodes instead")
+}
+
+// CompleteNetworkOptions applies the proxy and CA settings to the HTTP clients fetching the charts
+// and checking the versions, so it must be called before any of these requests. The Kubernetes
+// clients are not affected, and the images are pulled by the container runtime of the nodes, which
+// should be configured with the proxy and CA separately.
+func (o *InstallOptions) CompleteNetworkOptions() error {
+	if err := util.SetHTTPSProxy(o.HTTPSProxy, o.NoProxy); err != nil {
+		return fmt.Errorf("invalid --https-proxy %s: %v", o.HTTPSProxy, err)
+	}
+	if o.ChartRepoCAFile != "" {
+		data, err := os.ReadFile(o.ChartRepoCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read --chart-repo-ca-file: %v", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return fmt.Errorf("no valid certificate is found in --chart-repo-ca-file %s", o.ChartRepoCAFile)
+		}
+		if o.ChartRepoCAFile, err = filepath.Abs(o.ChartRepoCAFile); err != nil {
+			return err
+		}
+		helmRepoCAFile = o.ChartRepoCAFile
+	}
+	return nil
+}
+
 // CompleteInstallOptions complete options for real installation of kubeblocks
 func (o *InstallOptions) CompleteInstallOptions() error {
 	// add pod anti-affinity
@@ -271,22 +320,15 @@ func (o *InstallOptions) CompleteInstallOptions() error {
 
 func (o *InstallOptions) Install() error {
 	var err error
-	// add helm repo
-	s := spinner.New(o.Out, spinnerMsg("Add and update repo "+types.KubeBlocksRepoName))
-	defer s.Fail()
-	// Add repo, if exists, will update it
-	if err = helm.AddRepo(newHelmRepoEntry()); err != nil {
+	// add helm repo, if exists, will update it, then install KubeBlocks
+	if err = progress.NewChecklist(o.Out).
+		AddStep("Add and update repo "+types.KubeBlocksRepoName, func() error {
+			return helm.AddRepo(newHelmRepoEntry())
+		}).
+		AddStep("Install KubeBlocks "+o.Version, o.installChart).
+		Run(); err != nil {
 		return err
 	}
-	s.Success()
-
-	// install KubeBlocks
-	s = spinner.New(o.Out, spinnerMsg("Install KubeBlocks "+o.Version))
-	defer s.Fail()
-	if err = o.installChart(); err != nil {
-		return err
-	}
-	s.Success()
 
 	// wait for auto-install addons to be ready
 	if err = o.waitAddonsEnabled(); err != nil {
@@ -495,6 +537,9 @@ func (o *InstallOptions) installChart() error {
 }
 
 func (o *InstallOptions) printNotes() {
+	if printer.Quiet() {
+		return
+	}
 	fmt.Fprintf(o.Out, `
 -> Basic commands for cluster:
     kbcli cluster create -h     # help information about creating a database cluster
@@ -518,6 +563,7 @@ func (o *InstallOptions) buildChart() *helm.InstallOpts {
 		CreateNamespace: o.CreateNamespace,
 		Timeout:         o.Timeout,
 		Atomic:          false,
+		CaFile:          o.ChartRepoCAFile,
 		Upgrader: breakingchange.Upgrader{
 			FromVersion: o.OldVersion,
 			ToVersion:   o.Version,
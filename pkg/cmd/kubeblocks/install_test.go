@@ -20,6 +20,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package kubeblocks
 
 import (
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -130,4 +135,37 @@ var _ = Describe("kubeblocks install", func() {
 		Expect(o.TolerationsRaw).Should(Equal([]string{defaultTolerationsForInstallation}))
 		Expect(o.ValueOpts.JSONValues).ShouldNot(BeNil())
 	})
+	It("CompleteNetworkOptions test", func() {
+		defer func() {
+			helmRepoCAFile = ""
+			Expect(util.SetHTTPSProxy("", "")).Should(Succeed())
+		}()
+
+		By("invalid CA file")
+		dir := GinkgoT().TempDir()
+		caFile := filepath.Join(dir, "ca.crt")
+		Expect(os.WriteFile(caFile, []byte("invalid"), 0644)).Should(Succeed())
+		o := &InstallOptions{ChartRepoCAFile: caFile}
+		Expect(o.CompleteNetworkOptions()).Should(HaveOccurred())
+		o.ChartRepoCAFile = filepath.Join(dir, "not-exist.crt")
+		Expect(o.CompleteNetworkOptions()).Should(HaveOccurred())
+
+		By("valid proxy and CA file")
+		server := httptest.NewTLSServer(nil)
+		server.Close()
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		Expect(os.WriteFile(caFile, data, 0644)).Should(Succeed())
+		o = &InstallOptions{
+			Options:         Options{HelmCfg: helm.NewFakeConfig(namespace)},
+			HTTPSProxy:      "http://proxy.example.com:3128",
+			NoProxy:         ".example.com",
+			ChartRepoCAFile: caFile,
+		}
+		Expect(o.CompleteNetworkOptions()).Should(Succeed())
+		// the proxy is not applied to the Kubernetes clients by the environment variables
+		Expect(os.Getenv("HTTPS_PROXY")).Should(BeEmpty())
+		Expect(util.HTTPSProxyEnabled()).Should(BeTrue())
+		Expect(newHelmRepoEntry().CAFile).Should(Equal(caFile))
+		Expect(o.buildChart().CaFile).Should(Equal(caFile))
+	})
 })
@@ -60,6 +60,12 @@ var (
 		types.ConfigmapGVR(),
 		types.VolumeSnapshotClassGVR(),
 	}
+
+	// resources created by users, they will be orphaned after uninstalling KubeBlocks
+	orphanedResourceGVRs = []schema.GroupVersionResource{
+		types.ClusterGVR(),
+		types.BackupGVR(),
+	}
 )
 
 // getKBObjects returns all KubeBlocks objects including addons objects
@@ -208,29 +214,43 @@ func removeCustomResources(dynamic dynamic.Interface, objs kbObjects) error {
 	return nil
 }
 
-func deleteObjects(dynamic dynamic.Interface, gvr schema.GroupVersionResource, objects *unstructured.UnstructuredList) error {
+// removeCRDObjects returns the objects excluding the CRDs and the custom resources of them
+func removeCRDObjects(objs kbObjects) kbObjects {
+	crds, ok := objs[types.CRDGVR()]
+	if !ok {
+		return objs
+	}
+	res := kbObjects{}
+	crGVRs := map[schema.GroupVersionResource]bool{types.CRDGVR(): true}
+	for i := range crds.Items {
+		if gvr, err := getGVRByCRD(&crds.Items[i]); err == nil {
+			crGVRs[*gvr] = true
+		}
+	}
+	for k, v := range objs {
+		if !crGVRs[k] {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// keepResource returns true if the resource has annotation "helm.sh/resource-policy": "keep",
+// such resources are not deleted when uninstalling KubeBlocks
+// TODO: maybe a flag to control this behavior
+func keepResource(obj unstructured.Unstructured) bool {
 	const (
 		helmResourcePolicyKey  = "helm.sh/resource-policy"
 		helmResourcePolicyKeep = "keep"
 	)
+	return obj.GetAnnotations()[helmResourcePolicyKey] == helmResourcePolicyKeep
+}
 
+func deleteObjects(dynamic dynamic.Interface, gvr schema.GroupVersionResource, objects *unstructured.UnstructuredList) error {
 	if objects == nil {
 		return nil
 	}
 
-	// if resource has annotation "helm.sh/resource-policy": "keep", skip it
-	// TODO: maybe a flag to control this behavior
-	keepResource := func(obj unstructured.Unstructured) bool {
-		annotations := obj.GetAnnotations()
-		if len(annotations) == 0 {
-			return false
-		}
-		if annotations[helmResourcePolicyKey] == helmResourcePolicyKeep {
-			return true
-		}
-		return false
-	}
-
 	for _, s := range objects.Items {
 		if keepResource(s) {
 			continue
@@ -59,6 +59,7 @@ const (
 	flagVerbose                   = "verbose"
 	flagForce                     = "force"
 	flagFormat                    = "format"
+	flagBundle                    = "bundle"
 
 	PreflightPattern     = "data/%s_preflight.yaml"
 	HostPreflightPattern = "data/%s_hostpreflight.yaml"
@@ -66,12 +67,15 @@ const (
 
 	BasePreflightPattern     = "data/kubeblocks_base_preflight.yaml"
 	BaseHostPreflightPattern = "data/kubeblocks_base_hostpreflight.yaml"
+	CheckBundlePattern       = "data/bundles/%s.yaml"
 )
 
 var (
 	//go:embed data/*
 	defaultVendorYamlData embed.FS
-	preflightExample      = templates.Examples(`
+	// builtinCheckBundles are the check bundles of the KubeBlocks requirements shipped with kbcli
+	builtinCheckBundles = []string{"eks", "gke", "aks", "on-prem"}
+	preflightExample    = templates.Examples(`
 		# Run preflight provider checks against the default rules automatically
 		kbcli kubeblocks preflight
 
@@ -81,6 +85,12 @@ var (
 		# Run preflight checks against the customized rules of preflight-check.yaml
 		kbcli kubeblocks preflight preflight-check.yaml
 
+		# Run preflight checks against the customized rules of all yaml files in the directory, and output the results in json
+		kbcli kubeblocks preflight ./check-bundle --format json
+
+		# Run preflight checks against the built-in check bundle of EKS
+		kbcli kubeblocks preflight --bundle eks
+
 		# Run preflight checks and display AnalyzeResults with interactive mode
 		kbcli kubeblocks preflight preflight-check.yaml --interactive=true`)
 )
@@ -92,6 +102,7 @@ type PreflightOptions struct {
 	*preflight.PreflightFlags
 	checkFileList []string
 	checkYamlData [][]byte
+	bundles       []string
 	namespace     string
 	verbose       bool
 	force         bool
@@ -124,6 +135,10 @@ func NewPreflightCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cob
 	cmd.Flags().BoolVar(p.Debug, flagDebug, *p.Debug, "enable debug logging")
 	cmd.Flags().StringVarP(&p.namespace, flagNamespace, "n", "", "If present, the namespace scope for this CLI request")
 	cmd.Flags().BoolVar(&p.verbose, flagVerbose, p.verbose, "print more verbose logs, default value is false")
+	cmd.Flags().StringSliceVar(&p.bundles, flagBundle, nil, fmt.Sprintf("the built-in check bundles to run, one or more of [%s]", strings.Join(builtinCheckBundles, ", ")))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc(flagBundle, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return builtinCheckBundles, cobra.ShellCompDirectiveNoFileComp
+	}))
 	return cmd
 }
 
@@ -149,6 +164,15 @@ func LoadVendorCheckYaml(vendorName util.K8sProvider) ([][]byte, error) {
 	return yamlDataList, nil
 }
 
+// LoadCheckBundle loads the specs of the built-in check bundle
+func LoadCheckBundle(name string) ([]byte, error) {
+	data, err := defaultVendorYamlData.ReadFile(fmt.Sprintf(CheckBundlePattern, strings.ToLower(name)))
+	if err != nil {
+		return nil, errors.Errorf("unknown check bundle %s, supported bundles are [%s]", name, strings.Join(builtinCheckBundles, ", "))
+	}
+	return data, nil
+}
+
 func (p *PreflightOptions) Preflight(f cmdutil.Factory, args []string, opts values.Options) error {
 	// if force flag set, skip preflight
 
@@ -171,8 +195,15 @@ func (p *PreflightOptions) Preflight(f cmdutil.Factory, args []string, opts valu
 }
 
 func (p *PreflightOptions) complete(f cmdutil.Factory, args []string) error {
+	for _, name := range p.bundles {
+		data, err := LoadCheckBundle(name)
+		if err != nil {
+			return intctrlutil.NewError(intctrlutil.ErrorTypePreflightCommon, err.Error())
+		}
+		p.checkYamlData = append(p.checkYamlData, data)
+	}
 	// default no args, and run default validating vendor
-	if len(args) == 0 {
+	if len(args) == 0 && len(p.bundles) == 0 {
 		clientSet, err := f.KubernetesClientSet()
 		if err != nil {
 			return intctrlutil.NewError(intctrlutil.ErrorTypePreflightCommon, "init k8s client failed, please check the kubeconfig")
@@ -190,7 +221,11 @@ func (p *PreflightOptions) complete(f cmdutil.Factory, args []string) error {
 			return intctrlutil.NewError(intctrlutil.ErrorTypeSkipPreflight, err.Error())
 		}
 	} else {
-		p.checkFileList = args
+		files, err := kbpreflight.ExpandCheckFiles(args)
+		if err != nil {
+			return intctrlutil.NewError(intctrlutil.ErrorTypePreflightCommon, err.Error())
+		}
+		p.checkFileList = files
 	}
 	if len(p.checkFileList) < 1 && len(p.checkYamlData) < 1 {
 		return intctrlutil.NewError(intctrlutil.ErrorTypeSkipPreflight, "must specify at least one checks yaml")
@@ -34,6 +34,7 @@ import (
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	kbpreflight "github.com/apecloud/kbcli/pkg/preflight"
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
@@ -113,6 +114,32 @@ var _ = Describe("Preflight API Test", func() {
 		}).Should(Succeed())
 	})
 
+	It("complete with the built-in check bundles", func() {
+		p := &PreflightOptions{
+			factory:        tf,
+			IOStreams:      streams,
+			PreflightFlags: preflight.NewPreflightFlags(),
+			bundles:        []string{"eks", "on-prem"},
+		}
+		Expect(p.complete(tf, nil)).Should(Succeed())
+		Expect(p.checkYamlData).Should(HaveLen(2))
+		Expect(p.checkFileList).Should(BeEmpty())
+
+		p.bundles = []string{"unknown"}
+		Expect(p.complete(tf, nil)).Should(MatchError(ContainSubstring("unknown check bundle")))
+	})
+
+	It("load all built-in check bundles", func() {
+		for _, name := range builtinCheckBundles {
+			data, err := LoadCheckBundle(name)
+			Expect(err).NotTo(HaveOccurred())
+			preflightSpec, _, _, err := kbpreflight.LoadPreflightSpec(nil, [][]byte{data})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preflightSpec.Spec.Analyzers).ShouldNot(BeEmpty())
+			Expect(preflightSpec.Spec.ExtendAnalyzers).ShouldNot(BeEmpty())
+		}
+	})
+
 	It("LoadVendorCheckYaml test, and expect fail", func() {
 		_, err := LoadVendorCheckYaml(util.UnknownProvider)
 		Expect(err).Should(Succeed())
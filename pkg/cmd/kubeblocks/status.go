@@ -213,34 +213,7 @@ func (o *statusOptions) showAddons() {
 	fmt.Fprintln(o.Out, "\nKubeBlocks Addons:")
 	tbl := printer.NewTablePrinter(o.Out)
 
-	tbl.Tbl.SetColumnConfigs([]tablePrinter.ColumnConfig{
-		{
-			Name: "STATUS",
-			Transformer: func(val interface{}) string {
-				var ok bool
-				var addonPhase extensionsv1alpha1.AddonPhase
-				if addonPhase, ok = val.(extensionsv1alpha1.AddonPhase); !ok {
-					return fmt.Sprint(val)
-				}
-				var color text.Color
-				switch addonPhase {
-				case extensionsv1alpha1.AddonEnabled:
-					color = text.FgGreen
-				case extensionsv1alpha1.AddonFailed:
-					color = text.FgRed
-				case extensionsv1alpha1.AddonDisabled:
-					color = text.Faint
-				case extensionsv1alpha1.AddonEnabling, extensionsv1alpha1.AddonDisabling:
-					color = text.FgCyan
-				default:
-					return fmt.Sprint(addonPhase)
-				}
-				return color.Sprint(addonPhase)
-			},
-		},
-	},
-	)
-
+	// the STATUS column is colored by the printer theme
 	tbl.SetHeader("NAME", "STATUS", "TYPE", "PROVIDER")
 
 	var provider string
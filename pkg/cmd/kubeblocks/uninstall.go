@@ -23,6 +23,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -30,9 +32,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"helm.sh/helm/v3/pkg/repo"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8sapitypes "k8s.io/apimachinery/pkg/types"
@@ -43,8 +47,10 @@ import (
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 
 	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 
 	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/spinner"
@@ -56,7 +62,19 @@ import (
 var (
 	uninstallExample = templates.Examples(`
 		# uninstall KubeBlocks
-        kbcli kubeblocks uninstall`)
+        kbcli kubeblocks uninstall
+
+		# uninstall KubeBlocks and keep the CRDs, the existing clusters and backups will be retained
+		kbcli kubeblocks uninstall --keep-crds
+
+		# uninstall KubeBlocks and save the existing clusters and backups to a file which can be re-imported after reinstalling
+		kbcli kubeblocks uninstall --keep-crds --backup-state-to kubeblocks-state.yaml
+
+		# uninstall KubeBlocks and remove the "kb-system" namespace unless it holds the clusters or backups
+		kbcli kubeblocks uninstall --keep-crds --keep-namespaces --remove-namespace
+
+		# print the resources that will be deleted and the resources that will survive, without uninstalling KubeBlocks
+		kbcli kubeblocks uninstall --dry-run`)
 )
 
 type UninstallOptions struct {
@@ -71,6 +89,16 @@ type UninstallOptions struct {
 	addons          []*extensionsv1alpha1.Addon
 	Quiet           bool
 	force           bool
+	keepCRDs        bool
+	// keepNamespaces keeps the namespaces of the orphaned clusters and backups, the KubeBlocks namespace
+	// is not removed if it holds them, and the namespaces are saved to the file of backupStateTo
+	keepNamespaces bool
+	// orphanedNamespaces are the namespaces of the clusters and backups that will be orphaned
+	orphanedNamespaces []string
+	// backupStateTo is the file to save the resources that will be orphaned after uninstalling
+	backupStateTo string
+	// dryRun if true, only print the resources that will be deleted and survive
+	dryRun bool
 }
 
 func newUninstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -88,6 +116,10 @@ func newUninstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cob
 		Example: uninstallExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.Complete(f, cmd))
+			if o.dryRun {
+				util.CheckErr(o.DryRun())
+				return
+			}
 			util.CheckErr(o.PreCheck())
 			util.CheckErr(o.Uninstall())
 		},
@@ -97,12 +129,34 @@ func newUninstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cob
 	cmd.Flags().BoolVar(&o.removePVs, "remove-pvs", false, "Remove PersistentVolume or not")
 	cmd.Flags().BoolVar(&o.removePVCs, "remove-pvcs", false, "Remove PersistentVolumeClaim or not")
 	cmd.Flags().BoolVar(&o.RemoveNamespace, "remove-namespace", false, "Remove default created \"kb-system\" namespace or not")
+	cmd.Flags().BoolVar(&o.keepCRDs, "keep-crds", false, "Keep the KubeBlocks CRDs and the custom resources created by users, such as clusters and backups")
+	cmd.Flags().BoolVar(&o.keepNamespaces, "keep-namespaces", false, "Keep the namespaces of the clusters and backups, \"kb-system\" is not removed by --remove-namespace if it holds them, and the namespaces are saved to the file of --backup-state-to")
+	cmd.Flags().StringVar(&o.backupStateTo, "backup-state-to", "", "Save the clusters and backups that will be orphaned to the file, they can be re-imported by \"kubectl create -f\" after reinstalling KubeBlocks, it must be used with --keep-crds")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Print the dependency graph of the resources that will be deleted, together with the resources that will survive, without uninstalling KubeBlocks")
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for uninstalling KubeBlocks, such as --timeout=5m")
 	cmd.Flags().BoolVar(&o.Wait, "wait", true, "Wait for KubeBlocks to be uninstalled, including all the add-ons. It will wait for a --timeout period")
 	return cmd
 }
 
+func (o *UninstallOptions) validate() error {
+	// the clusters and backups are deleted with the CRDs, the saved state is useful only when they are kept
+	if o.backupStateTo != "" && !o.keepCRDs {
+		return fmt.Errorf("--backup-state-to must be used with --keep-crds, otherwise the clusters and backups will be deleted with the CRDs")
+	}
+	return nil
+}
+
 func (o *UninstallOptions) PreCheck() error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	// check if there is any resource will be orphaned, if so, they should be removed first,
+	// kept by --keep-crds, or saved by --backup-state-to
+	if err := o.checkOrphanedResources(); err != nil {
+		return err
+	}
+
 	// wait user to confirm
 	if !o.AutoApprove {
 		printer.Warning(o.Out, "this action will remove all KubeBlocks resources.\n")
@@ -111,12 +165,6 @@ func (o *UninstallOptions) PreCheck() error {
 		}
 	}
 
-	// check if there is any resource should be removed first, if so, return error
-	// and ask user to remove them manually
-	if err := checkResources(o.Dynamic); err != nil {
-		return err
-	}
-
 	// verify where kubeblocks is installed
 	kbNamespace, err := util.GetKubeBlocksNamespace(o.Client)
 	if err != nil {
@@ -180,7 +228,11 @@ func (o *UninstallOptions) Uninstall() error {
 	}
 
 	// remove finalizers of custom resources, then that will be deleted
-	printSpinner(newSpinner("Remove built-in custom resources"), removeCustomResources(o.Dynamic, objs))
+	if o.keepCRDs {
+		objs = removeCRDObjects(objs)
+	} else {
+		printSpinner(newSpinner("Remove built-in custom resources"), removeCustomResources(o.Dynamic, objs))
+	}
 
 	var gvrs []schema.GroupVersionResource
 	for k := range objs {
@@ -206,7 +258,7 @@ func (o *UninstallOptions) Uninstall() error {
 	}
 
 	// delete namespace if it is default namespace
-	if o.Namespace == types.DefaultNamespace && o.RemoveNamespace {
+	if o.removeNamespace() {
 		printSpinner(newSpinner("Remove namespace "+types.DefaultNamespace),
 			deleteNamespace(o.Client, types.DefaultNamespace))
 	}
@@ -219,6 +271,189 @@ func (o *UninstallOptions) Uninstall() error {
 	return nil
 }
 
+// DryRun prints the resources that will be deleted by uninstalling KubeBlocks as a dependency graph,
+// the children are deleted with their parents, and the resources that will survive. Nothing is changed.
+func (o *UninstallOptions) DryRun() error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	o.Namespace, _ = util.GetKubeBlocksNamespace(o.Client)
+	v, _ := util.GetVersionInfo(o.Client)
+
+	addons, err := getEnabledAddons(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	objs, err := getKBObjects(o.Dynamic, o.Namespace, addons)
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "Failed to get KubeBlocks objects %s\n", err.Error())
+	}
+	orphans, err := getOrphanedResources(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	o.orphanedNamespaces = getOrphanedNamespaces(orphans)
+
+	deleted, survived := o.buildUninstallGraph(v.KubeBlocks, addons, objs)
+	fmt.Fprintln(o.Out, "The following resources will be deleted:")
+	fmt.Fprintf(o.Out, "KubeBlocks %s\n", v.KubeBlocks)
+	deleted.print(o.Out, "")
+	fmt.Fprintln(o.Out)
+	fmt.Fprintln(o.Out, "The following resources will survive:")
+	if len(survived.children) == 0 {
+		fmt.Fprintln(o.Out, "<none>")
+	} else {
+		survived.print(o.Out, "")
+	}
+	fmt.Fprintln(o.Out)
+
+	if len(orphans) > 0 && !o.keepCRDs {
+		printer.Warning(o.Out, "the uninstallation will be refused as the clusters or backups exist, remove them first, or use --keep-crds to retain them.\n")
+	}
+	fmt.Fprintln(o.Out, "This is a dry run, nothing has been deleted.")
+	return nil
+}
+
+// uninstallNode is a node of the dependency graph printed by DryRun
+type uninstallNode struct {
+	name     string
+	children []*uninstallNode
+}
+
+func (n *uninstallNode) add(name string) *uninstallNode {
+	child := &uninstallNode{name: name}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *uninstallNode) print(out io.Writer, prefix string) {
+	for i, child := range n.children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(out, "%s%s%s\n", prefix, branch, child.name)
+		child.print(out, prefix+indent)
+	}
+}
+
+// buildUninstallGraph builds the dependency graphs of the resources that will be deleted and survive,
+// the addons are disabled first, then the helm release of KubeBlocks is uninstalled, the custom
+// resources are deleted with their CRDs, and the namespace is deleted at last.
+func (o *UninstallOptions) buildUninstallGraph(version string, addons []*extensionsv1alpha1.Addon, objs kbObjects) (*uninstallNode, *uninstallNode) {
+	deleted, survived := &uninstallNode{}, &uninstallNode{}
+	objectName := func(obj *unstructured.Unstructured) string {
+		if obj.GetNamespace() == "" {
+			return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+		}
+		return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	addonNodes := make(map[string]*uninstallNode)
+	for _, addon := range addons {
+		release := fmt.Sprintf("%s-%s", types.AddonReleasePrefix, addon.Name)
+		addonNodes[release] = deleted.add(fmt.Sprintf("Addon %s (helm release %s)", addon.Name, release))
+	}
+	releaseNode := deleted.add(fmt.Sprintf("Helm release %s %s", types.KubeBlocksReleaseName, version))
+
+	crdNodes := make(map[schema.GroupVersionResource]*uninstallNode)
+	if crds, ok := objs[types.CRDGVR()]; ok {
+		for i := range crds.Items {
+			gvr, err := getGVRByCRD(&crds.Items[i])
+			if err != nil || gvr == nil {
+				continue
+			}
+			if o.keepCRDs {
+				crdNodes[*gvr] = survived.add(objectName(&crds.Items[i]) + " (--keep-crds)")
+			} else {
+				crdNodes[*gvr] = deleted.add(objectName(&crds.Items[i]))
+			}
+		}
+	}
+
+	gvrs := maps.Keys(objs)
+	sort.SliceStable(gvrs, func(i, j int) bool {
+		return strings.Compare(gvrs[i].Resource, gvrs[j].Resource) < 0
+	})
+	for _, gvr := range gvrs {
+		if gvr == types.CRDGVR() || objs[gvr] == nil {
+			continue
+		}
+		for i := range objs[gvr].Items {
+			item := &objs[gvr].Items[i]
+			name := objectName(item)
+			if node, ok := crdNodes[gvr]; ok {
+				node.add(name)
+				continue
+			}
+			switch {
+			case gvr == types.PVCGVR() && !o.removePVCs:
+				survived.add(name + " (use --remove-pvcs to delete)")
+			case gvr == types.PVGVR() && !o.removePVs:
+				survived.add(name + " (use --remove-pvs to delete)")
+			case keepResource(*item):
+				survived.add(name + " (helm.sh/resource-policy: keep)")
+			default:
+				labels := item.GetLabels()
+				if node, ok := addonNodes[labels[constant.AppInstanceLabelKey]]; ok {
+					node.add(name)
+				} else if node, ok = addonNodes[labels["release"]]; ok {
+					node.add(name)
+				} else {
+					releaseNode.add(name)
+				}
+			}
+		}
+	}
+
+	switch {
+	case o.Namespace == "":
+	case o.removeNamespace():
+		deleted.add("Namespace " + o.Namespace)
+	case o.Namespace == types.DefaultNamespace && o.RemoveNamespace:
+		survived.add("Namespace " + o.Namespace + " (kept by --keep-namespaces as it holds the clusters or backups)")
+	case o.Namespace == types.DefaultNamespace:
+		survived.add("Namespace " + o.Namespace + " (use --remove-namespace to delete)")
+	default:
+		survived.add("Namespace " + o.Namespace)
+	}
+	return deleted, survived
+}
+
+// removeNamespace returns true if the default KubeBlocks namespace should be removed, it's kept by
+// --keep-namespaces if the clusters or backups are in it.
+func (o *UninstallOptions) removeNamespace() bool {
+	if o.Namespace != types.DefaultNamespace || !o.RemoveNamespace {
+		return false
+	}
+	return !o.keepNamespaces || !slices.Contains(o.orphanedNamespaces, o.Namespace)
+}
+
+// getEnabledAddons returns the KubeBlocks addons which are not disabled, they will be disabled when uninstalling
+func getEnabledAddons(dynamic dynamic.Interface) ([]*extensionsv1alpha1.Addon, error) {
+	objects, err := dynamic.Resource(types.AddonGVR()).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: buildKubeBlocksSelectorLabels(),
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var addons []*extensionsv1alpha1.Addon
+	for _, obj := range objects.Items {
+		addon := &extensionsv1alpha1.Addon{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, addon); err != nil {
+			return nil, err
+		}
+		if addon.Spec.InstallSpec.IsDisabled() {
+			continue
+		}
+		addons = append(addons, addon)
+	}
+	return addons, nil
+}
+
 // uninstallAddons uninstalls all KubeBlocks addons
 func (o *UninstallOptions) uninstallAddons() error {
 	var (
@@ -326,37 +561,116 @@ func (o *UninstallOptions) uninstallAddons() error {
 	return utilerrors.NewAggregate(allErrs)
 }
 
-func checkResources(dynamic dynamic.Interface) error {
-	ctx := context.Background()
-	gvrList := []schema.GroupVersionResource{
-		types.ClusterGVR(),
-		types.BackupGVR(),
+// checkOrphanedResources scans the resources created by users that will be orphaned after
+// uninstalling KubeBlocks and prints them, if they exist, the uninstallation is allowed only
+// when the CRDs are kept, and the resources can be saved to a file to re-import them later.
+func (o *UninstallOptions) checkOrphanedResources() error {
+	orphans, err := getOrphanedResources(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		return nil
 	}
+	o.orphanedNamespaces = getOrphanedNamespaces(orphans)
 
-	crs := map[string][]string{}
-	for _, gvr := range gvrList {
-		objList, err := dynamic.Resource(gvr).List(ctx, metav1.ListOptions{})
-		if err != nil && !apierrors.IsNotFound(err) {
+	fmt.Fprintln(o.Out, "The following resources will be orphaned after uninstalling KubeBlocks:")
+	printOrphanedResources(o.Out, orphans)
+	fmt.Fprintln(o.Out)
+
+	if o.backupStateTo != "" {
+		var namespaces []string
+		if o.keepNamespaces {
+			namespaces = o.orphanedNamespaces
+		}
+		if err = saveOrphanedResources(o.backupStateTo, namespaces, orphans); err != nil {
 			return err
 		}
+		// the saved namespaces may exist when re-importing, apply them instead of create
+		importCmd := "kubectl create -f"
+		if len(namespaces) > 0 {
+			importCmd = "kubectl apply -f"
+		}
+		fmt.Fprintf(o.Out, "The resources have been saved to %s, run \"%s %s\" to re-import them after reinstalling KubeBlocks.\n",
+			o.backupStateTo, importCmd, o.backupStateTo)
+	}
+	if o.keepCRDs {
+		return nil
+	}
+	return errors.New("failed to uninstall, the resources above need to be removed first, or use --keep-crds to retain them")
+}
 
-		if objList == nil {
+// getOrphanedResources returns the clusters and backups in all namespaces
+func getOrphanedResources(dynamic dynamic.Interface) (kbObjects, error) {
+	ctx := context.Background()
+	objs := kbObjects{}
+	for _, gvr := range orphanedResourceGVRs {
+		objList, err := dynamic.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if objList == nil || len(objList.Items) == 0 {
 			continue
 		}
+		objs[gvr] = objList
+	}
+	return objs, nil
+}
 
-		for _, item := range objList.Items {
-			crs[gvr.Resource] = append(crs[gvr.Resource], item.GetName())
+// getOrphanedNamespaces returns the sorted namespaces of the orphaned resources
+func getOrphanedNamespaces(objs kbObjects) []string {
+	var namespaces []string
+	for _, l := range objs {
+		for _, item := range l.Items {
+			if ns := item.GetNamespace(); ns != "" && !slices.Contains(namespaces, ns) {
+				namespaces = append(namespaces, ns)
+			}
 		}
 	}
+	sort.Strings(namespaces)
+	return namespaces
+}
 
-	if len(crs) > 0 {
-		errMsg := bytes.NewBufferString("failed to uninstall, the following resources need to be removed first\n")
-		for k, v := range crs {
-			errMsg.WriteString(fmt.Sprintf("  %s: %s\n", k, strings.Join(v, " ")))
+func printOrphanedResources(out io.Writer, objs kbObjects) {
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("KIND", "NAMESPACE", "NAME")
+	for _, gvr := range orphanedResourceGVRs {
+		if l, ok := objs[gvr]; ok {
+			for _, item := range l.Items {
+				tbl.AddRow(item.GetKind(), item.GetNamespace(), item.GetName())
+			}
 		}
-		return errors.Errorf(errMsg.String())
 	}
-	return nil
+	tbl.Print()
+}
+
+// saveOrphanedResources saves the namespaces and the resources as a multi-document YAML file, the
+// fields managed by the API server are removed so that the resources can be created again.
+func saveOrphanedResources(file string, namespaces []string, objs kbObjects) error {
+	buf := bytes.NewBuffer(nil)
+	for _, ns := range namespaces {
+		fmt.Fprintf(buf, "---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: %s\n", ns)
+	}
+	for _, gvr := range orphanedResourceGVRs {
+		l, ok := objs[gvr]
+		if !ok {
+			continue
+		}
+		for _, item := range l.Items {
+			obj := item.DeepCopy()
+			for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation",
+				"managedFields", "ownerReferences", "deletionTimestamp", "deletionGracePeriodSeconds"} {
+				unstructured.RemoveNestedField(obj.Object, "metadata", field)
+			}
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			buf.WriteString("---\n")
+			buf.Write(data)
+		}
+	}
+	return os.WriteFile(file, buf.Bytes(), 0600)
 }
 
 func disableAddon(dynamic dynamic.Interface, addon *extensionsv1alpha1.Addon) error {
@@ -20,15 +20,24 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package kubeblocks
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	clientfake "k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
 	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util/helm"
 )
 
@@ -79,8 +88,134 @@ var _ = Describe("kubeblocks uninstall", func() {
 		Expect(o.Uninstall()).Should(Succeed())
 	})
 
-	It("checkResources", func() {
-		fakeDynamic := testing.FakeDynamicClient()
-		Expect(checkResources(fakeDynamic)).Should(Succeed())
+	It("checkOrphanedResources", func() {
+		o := UninstallOptions{
+			Options: Options{
+				IOStreams: streams,
+				Dynamic:   testing.FakeDynamicClient(),
+			},
+		}
+		Expect(o.checkOrphanedResources()).Should(Succeed())
+
+		By("clusters and backups will be orphaned")
+		o.Dynamic = testing.FakeDynamicClient(testing.FakeCluster("test-cluster", namespace), testing.FakeBackup("test-backup"))
+		orphans, err := getOrphanedResources(o.Dynamic)
+		Expect(err).Should(Succeed())
+		Expect(orphans).Should(HaveLen(2))
+		Expect(o.checkOrphanedResources()).Should(HaveOccurred())
+
+		o.keepCRDs = true
+		Expect(o.checkOrphanedResources()).Should(Succeed())
+
+		By("save the orphaned resources")
+		o.backupStateTo = filepath.Join(GinkgoT().TempDir(), "state.yaml")
+		Expect(o.checkOrphanedResources()).Should(Succeed())
+		data, err := os.ReadFile(o.backupStateTo)
+		Expect(err).Should(Succeed())
+		Expect(string(data)).Should(ContainSubstring("name: test-cluster"))
+		Expect(string(data)).Should(ContainSubstring("name: test-backup"))
+		Expect(string(data)).ShouldNot(ContainSubstring("resourceVersion"))
+		Expect(string(data)).ShouldNot(ContainSubstring("kind: Namespace"))
+
+		By("save the namespaces of the orphaned resources")
+		o.keepNamespaces = true
+		o.backupStateTo = filepath.Join(GinkgoT().TempDir(), "state.yaml")
+		Expect(o.checkOrphanedResources()).Should(Succeed())
+		data, err = os.ReadFile(o.backupStateTo)
+		Expect(err).Should(Succeed())
+		Expect(string(data)).Should(HavePrefix("---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: " + testing.Namespace + "\n"))
+		Expect(string(data)).Should(ContainSubstring("kind: Namespace\nmetadata:\n  name: " + namespace + "\n"))
+	})
+
+	It("keep namespaces", func() {
+		o := UninstallOptions{
+			Options:            Options{Namespace: types.DefaultNamespace},
+			RemoveNamespace:    true,
+			orphanedNamespaces: []string{"default", types.DefaultNamespace},
+		}
+		Expect(o.removeNamespace()).Should(BeTrue())
+		o.keepNamespaces = true
+		Expect(o.removeNamespace()).Should(BeFalse())
+		_, survived := o.buildUninstallGraph("0.9.0", nil, kbObjects{})
+		buf := &bytes.Buffer{}
+		survived.print(buf, "")
+		Expect(buf.String()).Should(ContainSubstring("kept by --keep-namespaces"))
+		o.orphanedNamespaces = []string{"default"}
+		Expect(o.removeNamespace()).Should(BeTrue())
+	})
+
+	It("backup state requires keep CRDs", func() {
+		o := UninstallOptions{backupStateTo: "state.yaml"}
+		Expect(o.PreCheck()).Should(HaveOccurred())
+		Expect(o.DryRun()).Should(HaveOccurred())
+	})
+	It("dry run", func() {
+		o := UninstallOptions{
+			Options: Options{
+				IOStreams: streams,
+				Client:    testing.FakeClientSet(),
+				Dynamic:   testing.FakeDynamicClient(testing.FakeCluster("test-cluster", namespace), testing.FakeVolumeSnapshotClass()),
+			},
+		}
+		out := streams.Out.(*bytes.Buffer)
+		Expect(o.DryRun()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("The following resources will be deleted:"))
+		Expect(out.String()).Should(ContainSubstring("the uninstallation will be refused"))
+		Expect(out.String()).Should(ContainSubstring("This is a dry run"))
+
+	})
+
+	It("build uninstall graph", func() {
+		newObj := func(kind, ns, name string, labels, annotations map[string]string) unstructured.Unstructured {
+			obj := unstructured.Unstructured{}
+			obj.SetKind(kind)
+			obj.SetNamespace(ns)
+			obj.SetName(name)
+			obj.SetLabels(labels)
+			obj.SetAnnotations(annotations)
+			return obj
+		}
+		crd := newObj("CustomResourceDefinition", "", "clusters.apps.kubeblocks.io", nil, nil)
+		Expect(unstructured.SetNestedField(crd.Object, "apps.kubeblocks.io", "spec", "group")).Should(Succeed())
+		crGVR, _ := getGVRByCRD(&crd)
+		objs := kbObjects{
+			types.CRDGVR(): &unstructured.UnstructuredList{Items: []unstructured.Unstructured{crd}},
+			*crGVR:         &unstructured.UnstructuredList{Items: []unstructured.Unstructured{newObj("Cluster", "default", "mycluster", nil, nil)}},
+			types.DeployGVR(): &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+				newObj("Deployment", types.DefaultNamespace, "kubeblocks", nil, nil),
+				newObj("Deployment", types.DefaultNamespace, "mysql-operator", map[string]string{constant.AppInstanceLabelKey: "kb-addon-mysql"}, nil),
+			}},
+			types.ValidatingWebhookConfigurationGVR(): &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+				newObj("ValidatingWebhookConfiguration", "", "kubeblocks-webhook", nil, map[string]string{"helm.sh/resource-policy": "keep"}),
+			}},
+			types.PVCGVR(): &unstructured.UnstructuredList{Items: []unstructured.Unstructured{newObj("PersistentVolumeClaim", types.DefaultNamespace, "data", nil, nil)}},
+		}
+		o := UninstallOptions{Options: Options{Namespace: types.DefaultNamespace}}
+		deleted, survived := o.buildUninstallGraph("0.9.0", []*extensionsv1alpha1.Addon{testing.FakeAddon("mysql")}, objs)
+		buf := &bytes.Buffer{}
+		deleted.print(buf, "")
+		Expect(buf.String()).Should(Equal(`├── Addon mysql (helm release kb-addon-mysql)
+│   └── Deployment kb-system/mysql-operator
+├── Helm release kubeblocks 0.9.0
+│   └── Deployment kb-system/kubeblocks
+└── CustomResourceDefinition clusters.apps.kubeblocks.io
+    └── Cluster default/mycluster
+`))
+		buf.Reset()
+		survived.print(buf, "")
+		Expect(buf.String()).Should(Equal(`├── PersistentVolumeClaim kb-system/data (use --remove-pvcs to delete)
+├── ValidatingWebhookConfiguration kubeblocks-webhook (helm.sh/resource-policy: keep)
+└── Namespace kb-system (use --remove-namespace to delete)
+`))
+
+		By("keep CRDs and remove namespace")
+		o.keepCRDs, o.RemoveNamespace, o.removePVCs = true, true, true
+		deleted, survived = o.buildUninstallGraph("0.9.0", nil, objs)
+		Expect(deleted.children).Should(HaveLen(2))
+		Expect(deleted.children[0].children).Should(HaveLen(3))
+		Expect(deleted.children[1].name).Should(Equal("Namespace kb-system"))
+		Expect(survived.children).Should(HaveLen(2))
+		Expect(survived.children[0].name).Should(ContainSubstring("--keep-crds"))
+		Expect(survived.children[0].children).Should(HaveLen(1))
 	})
 })
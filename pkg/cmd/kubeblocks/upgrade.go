@@ -39,7 +39,7 @@ import (
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/printer"
-	"github.com/apecloud/kbcli/pkg/spinner"
+	"github.com/apecloud/kbcli/pkg/printer/progress"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
 	"github.com/apecloud/kbcli/pkg/util/breakingchange"
@@ -72,6 +72,7 @@ func newUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 		Example: upgradeExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			util.CheckErr(o.Complete(f, cmd))
+			util.CheckErr(o.CompleteNetworkOptions())
 			util.CheckErr(o.Upgrade())
 		},
 	}
@@ -81,6 +82,7 @@ func newUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for upgrading KubeBlocks, such as --timeout=10m")
 	cmd.Flags().BoolVar(&o.Wait, "wait", true, "Wait for KubeBlocks to be ready. It will wait for a --timeout period")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before upgrading KubeBlocks")
+	o.addNetworkFlags(cmd)
 	helm.AddValueOptionsFlags(cmd.Flags(), &o.ValueOpts)
 
 	return cmd
@@ -153,14 +155,10 @@ func (o *InstallOptions) Upgrade() error {
 		}
 	}
 
-	// add helm repo
-	s := spinner.New(o.Out, spinnerMsg("Add and update repo "+types.KubeBlocksChartName))
-	defer s.Fail()
-	// Add repo, if exists, will update it
-	if err = helm.AddRepo(newHelmRepoEntry()); err != nil {
-		return err
-	}
-	s.Success()
+	// add helm repo, if exists, will update it
+	steps := progress.NewChecklist(o.Out).AddStep("Add and update repo "+types.KubeBlocksChartName, func() error {
+		return helm.AddRepo(newHelmRepoEntry())
+	})
 
 	// it's time to upgrade
 	msg := ""
@@ -168,31 +166,22 @@ func (o *InstallOptions) Upgrade() error {
 		// stop the old version KubeBlocks, otherwise the old version KubeBlocks will reconcile the
 		// new version resources, which may not be compatible. helm will start the new version
 		// KubeBlocks after upgrade.
-		s = spinner.New(o.Out, spinnerMsg("Stop KubeBlocks "+kbVersion))
-		defer s.Fail()
-		if err = o.stopDeployment(util.GetKubeBlocksDeploy); err != nil {
-			return err
-		}
-		s.Success()
+		steps.AddStep("Stop KubeBlocks "+kbVersion, func() error {
+			return o.stopDeployment(util.GetKubeBlocksDeploy)
+		})
 
 		// stop the data protection deployment
-		s = spinner.New(o.Out, spinnerMsg("Stop DataProtection"))
-		defer s.Fail()
-		if err = o.stopDeployment(util.GetDataProtectionDeploy); err != nil {
-			return err
-		}
-		s.Success()
+		steps.AddStep("Stop DataProtection", func() error {
+			return o.stopDeployment(util.GetDataProtectionDeploy)
+		})
 
 		msg = "to " + o.Version
 	}
-	s = spinner.New(o.Out, spinnerMsg("Upgrading KubeBlocks "+msg))
-	defer s.Fail()
 	// upgrade KubeBlocks chart
-	if err = o.upgradeChart(); err != nil {
+	steps.AddStep("Upgrading KubeBlocks "+msg, o.upgradeChart)
+	if err = steps.Run(); err != nil {
 		return err
 	}
-	// successfully upgraded
-	s.Success()
 
 	if !o.Quiet {
 		fmt.Fprintf(o.Out, "\nKubeBlocks has been upgraded %s SUCCESSFULLY!\n", msg)
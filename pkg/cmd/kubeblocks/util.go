@@ -271,9 +271,14 @@ func checkAddons(addons []*extensionsv1alpha1.Addon, install bool) *addonStatus
 	return status
 }
 
+// helmRepoCAFile is the CA bundle to verify the certificate of the KubeBlocks chart repository,
+// it's set by --chart-repo-ca-file.
+var helmRepoCAFile string
+
 func newHelmRepoEntry() *repo.Entry {
 	return &repo.Entry{
-		Name: types.KubeBlocksChartName,
-		URL:  util.GetHelmChartRepoURL(),
+		Name:   types.KubeBlocksChartName,
+		URL:    util.GetHelmChartRepoURL(),
+		CAFile: helmRepoCAFile,
 	}
 }
@@ -31,6 +31,7 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/apecloud/kbcli/pkg/action"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/types"
 	migrationv1 "github.com/apecloud/kbcli/pkg/types/migrationapi"
 	"github.com/apecloud/kbcli/pkg/util"
@@ -87,9 +88,9 @@ func NewMigrationCreateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams
 		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.MigrationTaskGVR()),
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Args = args
-			cmdutil.CheckErr(o.Complete())
-			cmdutil.CheckErr(o.Validate())
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete())
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -100,6 +101,7 @@ func NewMigrationCreateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams
 	cmd.Flags().StringSliceVar(&o.Steps, "steps", []string{}, "Set up migration steps,such as: precheck=true,init-struct=true,init-data=true,cdc=true")
 	cmd.Flags().StringSliceVar(&o.Tolerations, "tolerations", []string{}, "Tolerations for migration, such as '\"key=engineType,value=pg,operator=Equal,effect=NoSchedule\"'")
 	cmd.Flags().StringSliceVar(&o.Resources, "resources", []string{}, "Resources limit for migration, such as '\"cpu=3000m,memory=3Gi\"'")
+	o.AddDryRunFlags(cmd, false)
 
 	util.CheckErr(cmd.MarkFlagRequired("template"))
 	util.CheckErr(cmd.MarkFlagRequired("source"))
@@ -116,7 +118,7 @@ func (o *CreateMigrationOptions) Validate() error {
 	}
 
 	if o.Template == "" {
-		return fmt.Errorf("migration template is needed, use \"kbcli migration templates\" to check and special one")
+		return clierrors.NewValidation("migration template is needed, use \"kbcli migration templates\" to check and special one")
 	}
 
 	errMsgArr := make([]string, 0)
@@ -158,7 +160,7 @@ func (o *CreateMigrationOptions) Validate() error {
 
 	// Log errors if necessary
 	if len(errMsgArr) > 0 {
-		return fmt.Errorf(strings.Join(errMsgArr, ";\n"))
+		return clierrors.NewValidation("%s", strings.Join(errMsgArr, ";\n"))
 	}
 	return nil
 }
@@ -39,6 +39,7 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/types"
 	v1alpha1 "github.com/apecloud/kbcli/pkg/types/migrationapi"
@@ -111,7 +112,7 @@ func (o *describeOptions) complete(args []string) error {
 	}
 
 	if len(args) == 0 {
-		return fmt.Errorf("migration task name should be specified")
+		return clierrors.NewValidation("migration task name should be specified")
 	}
 	o.names = args
 	return nil
@@ -37,6 +37,7 @@ import (
 	"k8s.io/kubectl/pkg/polymorphichelpers"
 
 	"github.com/apecloud/kbcli/pkg/action"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/types"
 	migrationv1 "github.com/apecloud/kbcli/pkg/types/migrationapi"
 	"github.com/apecloud/kbcli/pkg/util"
@@ -84,13 +85,13 @@ func (o *LogsOptions) addFlags(cmd *cobra.Command) {
 // complete customs complete function for logs
 func (o *LogsOptions) complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("migration task name should be specified")
+		return clierrors.NewValidation("migration task name should be specified")
 	}
 	if len(args) > 0 {
 		o.taskName = args[0]
 	}
 	if o.step == "" {
-		return fmt.Errorf("migration task step should be specified")
+		return clierrors.NewValidation("migration task step should be specified")
 	}
 	var err error
 	o.logOptions.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
@@ -114,11 +115,11 @@ func (o *LogsOptions) complete(f cmdutil.Factory, cmd *cobra.Command, args []str
 
 	taskObj, err := o.getMigrationObjects(o.taskName)
 	if err != nil {
-		return fmt.Errorf("failed to find the migrationtask")
+		return clierrors.NewNotFoundf("failed to find the migrationtask")
 	}
 	pod := o.getPodByStep(taskObj, strings.TrimSpace(o.step))
 	if pod == nil {
-		return fmt.Errorf("migrationtask[%s] step[%s] 's pod not found", taskObj.Task.Name, o.step)
+		return clierrors.NewNotFoundf("migrationtask[%s] step[%s] 's pod not found", taskObj.Task.Name, o.step)
 	}
 	o.logOptions.RESTClientGetter = f
 	o.logOptions.LogsForObject = polymorphichelpers.LogsForObjectFn
@@ -131,27 +132,27 @@ func (o *LogsOptions) complete(f cmdutil.Factory, cmd *cobra.Command, args []str
 
 func (o *LogsOptions) validate() error {
 	if len(o.taskName) == 0 {
-		return fmt.Errorf("migration task name must be specified")
+		return clierrors.NewValidation("migration task name must be specified")
 	}
 
 	if o.logOptions.LimitBytes < 0 {
-		return fmt.Errorf("--limit-bytes must be greater than 0")
+		return clierrors.NewValidation("--limit-bytes must be greater than 0")
 	}
 	if o.logOptions.Tail < -1 {
-		return fmt.Errorf("--tail must be greater than or equal to -1")
+		return clierrors.NewValidation("--tail must be greater than or equal to -1")
 	}
 	if len(o.logOptions.SinceTime) > 0 && o.logOptions.SinceSeconds != 0 {
-		return fmt.Errorf("at most one of `sinceTime` or `sinceSeconds` may be specified")
+		return clierrors.NewValidation("at most one of `sinceTime` or `sinceSeconds` may be specified")
 	}
 	logsOptions, ok := o.logOptions.Options.(*corev1.PodLogOptions)
 	if !ok {
 		return fmt.Errorf("unexpected logs options object")
 	}
 	if logsOptions.SinceSeconds != nil && *logsOptions.SinceSeconds < int64(0) {
-		return fmt.Errorf("--since must be greater than 0")
+		return clierrors.NewValidation("--since must be greater than 0")
 	}
 	if logsOptions.TailLines != nil && *logsOptions.TailLines < -1 {
-		return fmt.Errorf("--tail must be greater than or equal to -1")
+		return clierrors.NewValidation("--tail must be greater than or equal to -1")
 	}
 	return nil
 }
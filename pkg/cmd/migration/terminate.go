@@ -20,13 +20,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package migration
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/apecloud/kbcli/pkg/action"
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 	"github.com/apecloud/kbcli/pkg/types"
 	"github.com/apecloud/kbcli/pkg/util"
 )
@@ -50,7 +49,7 @@ func NewMigrationTerminateCmd(f cmdutil.Factory, streams genericiooptions.IOStre
 
 func deleteMigrationTask(o *action.DeleteOptions, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("missing migration task name")
+		return clierrors.NewValidation("missing migration task name")
 	}
 	o.Names = args
 	return o.Run()
@@ -0,0 +1,198 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cmd/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var applyExample = templates.Examples(`
+	# restart the cluster mycluster on the weekend
+	kbcli ops apply mycluster --template weekend-safe-restart
+
+	# expand the storage of the mysql component of the cluster mycluster by 30%
+	kbcli ops apply mycluster --template expand-storage --set pct=30 --set components=mysql
+
+	# print the OpsRequest rendered from the template without creating it
+	kbcli ops apply mycluster --template expand-storage --dry-run`)
+
+type applyOptions struct {
+	factory   cmdutil.Factory
+	client    kubernetes.Interface
+	dynamic   dynamic.Interface
+	namespace string
+
+	clusterName  string
+	templateName string
+	values       []string
+	dryRun       string
+	force        bool
+	forceDirect  bool
+
+	genericiooptions.IOStreams
+}
+
+func newApplyCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &applyOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "apply NAME --template TEMPLATE",
+		Short:             "Create an OpsRequest of the cluster from the template.",
+		Example:           applyExample,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.clusterName = args[0]
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.templateName, "template", "", "The name of the ops template, list the templates by `kbcli ops list-templates`")
+	cmd.Flags().StringArrayVar(&o.values, "set", nil, "Set the parameter of the template, such as --set pct=20")
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", "none", `Must be "client", or "server". If with client strategy, only print the object that would be sent, and no data is actually sent. If with server strategy, submit the server-side request, but no data is persistent.`)
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = "unchanged"
+	cmd.Flags().BoolVar(&o.force, "force", false, "Create the OpsRequest even if the cluster is in maintenance mode")
+	cluster.AddForceDirectFlag(cmd, &o.forceDirect)
+	_ = cmd.MarkFlagRequired("template")
+	return cmd
+}
+
+func (o *applyOptions) complete() error {
+	var err error
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *applyOptions) parseValues() (map[string]string, error) {
+	values := map[string]string{}
+	for _, v := range o.values {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid --set %s, must be key=value", v)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, nil
+}
+
+func (o *applyOptions) run() error {
+	dryRun, err := (&action.CreateOptions{DryRun: o.dryRun}).GetDryRunStrategy()
+	if err != nil {
+		return err
+	}
+	values, err := o.parseValues()
+	if err != nil {
+		return err
+	}
+	tpls, err := loadTemplates(o.client, o.namespace)
+	if err != nil {
+		return err
+	}
+	tpl, ok := tpls[o.templateName]
+	if !ok {
+		return errors.Errorf("ops template %s is not found, list the templates by `kbcli ops list-templates`", o.templateName)
+	}
+	obj, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(o.namespace).Get(context.TODO(), o.clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	ops, err := tpl.render(obj, values)
+	if err != nil {
+		return err
+	}
+	if ops.GetKind() != types.KindOps {
+		return errors.Errorf("template %s renders a %s instead of an OpsRequest", tpl.Name, ops.GetKind())
+	}
+	if ops.GetName() == "" {
+		ops.SetName(fmt.Sprintf("%s-%s-%s", o.clusterName, tpl.Name, rand.String(5)))
+	}
+	ops.SetNamespace(o.namespace)
+	labels := ops.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[opsTemplateLabelKey] = tpl.Name
+	ops.SetLabels(labels)
+
+	if dryRun == action.DryRunClient {
+		data, err := yaml.Marshal(ops.Object)
+		if err != nil {
+			return err
+		}
+		_, err = o.Out.Write(data)
+		return err
+	}
+	// the OpsRequest is exported or checked as the ones created by the cluster commands
+	if dryRun == action.DryRunNone {
+		if action.ExportEnabled() {
+			return action.ExportObject(ops, o.Out)
+		}
+		c := &appsv1alpha1.Cluster{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, c); err != nil {
+			return err
+		}
+		opsType, _, _ := unstructured.NestedString(ops.Object, "spec", "type")
+		if err = cluster.CheckMaintenance(c, appsv1alpha1.OpsType(opsType), o.force); err != nil {
+			return err
+		}
+		if err = cluster.CheckGitOps(c, o.forceDirect, o.Out); err != nil {
+			return err
+		}
+	}
+	createOptions := metav1.CreateOptions{}
+	if dryRun == action.DryRunServer {
+		createOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err = o.dynamic.Resource(types.OpsGVR()).Namespace(o.namespace).Create(context.TODO(), ops, createOptions); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "OpsRequest %s created from template %s, you can view the progress:\n\tkbcli cluster describe-ops %s -n %s\n",
+		ops.GetName(), tpl.Name, ops.GetName(), o.namespace)
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ops
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("ops apply", func() {
+	var (
+		out     *bytes.Buffer
+		options *applyOptions
+	)
+
+	// 2023-10-21 is a Saturday
+	saturday := time.Date(2023, 10, 21, 0, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		Expect(os.Setenv(types.CliHomeEnv, GinkgoT().TempDir())).Should(Succeed())
+		out = &bytes.Buffer{}
+		options = &applyOptions{
+			IOStreams:   genericiooptions.IOStreams{Out: out, ErrOut: out},
+			client:      clitesting.FakeClientSet(),
+			dynamic:     clitesting.FakeDynamicClient(clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace)),
+			namespace:   clitesting.Namespace,
+			clusterName: clitesting.ClusterName,
+			dryRun:      "none",
+		}
+		now = func() time.Time { return saturday }
+	})
+
+	AfterEach(func() {
+		now = time.Now
+		Expect(os.Unsetenv(types.CliHomeEnv)).Should(Succeed())
+	})
+
+	getOps := func() *appsv1alpha1.OpsRequest {
+		list, err := options.dynamic.Resource(types.OpsGVR()).Namespace(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(list.Items).Should(HaveLen(1))
+		ops := &appsv1alpha1.OpsRequest{}
+		Expect(runtimeConvert(&list.Items[0], ops)).Should(Succeed())
+		return ops
+	}
+
+	It("expand the storage by the percentage", func() {
+		options.templateName = "expand-storage"
+		options.values = []string{"pct=50"}
+		Expect(options.run()).Should(Succeed())
+		ops := getOps()
+		Expect(ops.Labels[opsTemplateLabelKey]).Should(Equal("expand-storage"))
+		Expect(ops.Spec.ClusterRef).Should(Equal(clitesting.ClusterName))
+		Expect(ops.Spec.Type).Should(Equal(appsv1alpha1.VolumeExpansionType))
+		Expect(ops.Spec.VolumeExpansionList).ShouldNot(BeEmpty())
+		Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Storage.String()).Should(Equal("1536Mi"))
+	})
+
+	It("restart the cluster on the allowed days", func() {
+		options.templateName = "weekend-safe-restart"
+		options.values = []string{"components=" + clitesting.ComponentName}
+		Expect(options.run()).Should(Succeed())
+		ops := getOps()
+		Expect(ops.Spec.Type).Should(Equal(appsv1alpha1.RestartType))
+		Expect(ops.Spec.RestartList).Should(HaveLen(1))
+		Expect(ops.Spec.RestartList[0].ComponentName).Should(Equal(clitesting.ComponentName))
+		Expect(*ops.Spec.TTLSecondsBeforeAbort).Should(BeEquivalentTo(3600))
+
+		By("today is not allowed")
+		options.values = []string{"days=Mon,Tue"}
+		Expect(options.run()).Should(MatchError(ContainSubstring("only allowed on Mon,Tue, but today is Sat")))
+	})
+
+	It("print the OpsRequest in dry-run mode", func() {
+		options.templateName = "expand-storage"
+		options.dryRun = "client"
+		Expect(options.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("type: VolumeExpansion"))
+		list, err := options.dynamic.Resource(types.OpsGVR()).Namespace(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(list.Items).Should(BeEmpty())
+	})
+
+	It("export the OpsRequest instead of creating it", func() {
+		dir := GinkgoT().TempDir()
+		action.SetExportDir(dir)
+		defer action.SetExportDir("")
+		options.templateName = "expand-storage"
+		Expect(options.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("exported to"))
+		files, err := os.ReadDir(filepath.Join(dir, clitesting.Namespace))
+		Expect(err).Should(Succeed())
+		Expect(files).Should(HaveLen(2))
+		list, err := options.dynamic.Resource(types.OpsGVR()).Namespace(clitesting.Namespace).List(context.TODO(), metav1.ListOptions{})
+		Expect(err).Should(Succeed())
+		Expect(list.Items).Should(BeEmpty())
+	})
+
+	It("refuse the risky OpsRequest of the cluster in maintenance", func() {
+		c := clitesting.FakeCluster(clitesting.ClusterName, clitesting.Namespace)
+		c.Annotations = map[string]string{"apps.kubeblocks.io/maintenance-until": time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+		options.dynamic = clitesting.FakeDynamicClient(c)
+		options.templateName = "weekend-safe-restart"
+		options.values = []string{"components=" + clitesting.ComponentName}
+		Expect(options.run()).Should(MatchError(ContainSubstring("is in maintenance mode")))
+
+		By("create it with --force")
+		options.force = true
+		Expect(options.run()).Should(Succeed())
+		Expect(getOps().Spec.Type).Should(Equal(appsv1alpha1.RestartType))
+	})
+
+	It("invalid template and parameters", func() {
+		options.templateName = "not-found"
+		Expect(options.run()).Should(MatchError(ContainSubstring("ops template not-found is not found")))
+		options.templateName = "expand-storage"
+		options.values = []string{"pct"}
+		Expect(options.run()).Should(MatchError(ContainSubstring("invalid --set pct")))
+	})
+})
+
+func runtimeConvert(obj *unstructured.Unstructured, ops *appsv1alpha1.OpsRequest) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ops)
+}
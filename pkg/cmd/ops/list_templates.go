@@ -0,0 +1,100 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ops
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var listTemplatesExample = templates.Examples(`
+	# list the builtin ops templates, the templates in ~/.kbcli/ops-templates and in the ConfigMaps
+	# labeled with kubeblocks.io/ops-template=true of the current namespace
+	kbcli ops list-templates`)
+
+type listTemplatesOptions struct {
+	factory   cmdutil.Factory
+	client    kubernetes.Interface
+	namespace string
+
+	genericiooptions.IOStreams
+}
+
+func newListTemplatesCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &listTemplatesOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "list-templates",
+		Short:   "List the ops templates.",
+		Example: listTemplatesExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	return cmd
+}
+
+func (o *listTemplatesOptions) complete() error {
+	var err error
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.client, err = o.factory.KubernetesClientSet()
+	return err
+}
+
+func (o *listTemplatesOptions) run() error {
+	tpls, err := loadTemplates(o.client, o.namespace)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(tpls))
+	for name := range tpls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("NAME", "SOURCE", "PARAMETERS", "DESCRIPTION")
+	for _, name := range names {
+		t := tpls[name]
+		var params []string
+		for _, p := range t.Parameters {
+			if p.Default != "" {
+				params = append(params, p.Name+"="+p.Default)
+			} else {
+				params = append(params, p.Name)
+			}
+		}
+		tbl.AddRow(t.Name, t.source, strings.Join(params, ","), t.Description)
+	}
+	tbl.Print()
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ops
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	// opsTemplateLabelKey is the label of the ConfigMaps storing the ops templates shared in the namespace
+	opsTemplateLabelKey = "kubeblocks.io/ops-template"
+	// opsTemplateDataKey is the key of the template in the ConfigMap data
+	opsTemplateDataKey = "template.yaml"
+	// opsTemplateDir is the directory of the ops templates in the kbcli home
+	opsTemplateDir = "ops-templates"
+
+	sourceBuiltin = "builtin"
+)
+
+var (
+	//go:embed templates/*
+	builtinTemplates embed.FS
+
+	// now returns the current time, replaced in tests
+	now = time.Now
+)
+
+// opsTemplate is a parameterized OpsRequest, the OpsRequest is a go template rendered with the
+// parameters and the target cluster.
+type opsTemplate struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Parameters  []templateParameter `json:"parameters,omitempty"`
+	OpsRequest  string              `json:"opsRequest"`
+
+	// source is where the template is loaded from, a builtin, file or ConfigMap template
+	source string
+}
+
+type templateParameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// templateContext is the data to render the OpsRequest of the template
+type templateContext struct {
+	ClusterName string
+	Namespace   string
+	Cluster     map[string]interface{}
+	Params      map[string]string
+}
+
+func parseTemplate(data []byte, source string) (*opsTemplate, error) {
+	t := &opsTemplate{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse ops template %s", source)
+	}
+	if t.Name == "" || t.OpsRequest == "" {
+		return nil, errors.Errorf("invalid ops template %s, the name and opsRequest are required", source)
+	}
+	t.source = source
+	return t, nil
+}
+
+// loadTemplates loads the builtin templates, the templates in the kbcli home and the templates in
+// the ConfigMaps of the namespace. The latter overrides the former with the same name, so that a
+// team can share its runbooks in the ConfigMaps.
+func loadTemplates(client kubernetes.Interface, namespace string) (map[string]*opsTemplate, error) {
+	templates := map[string]*opsTemplate{}
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := builtinTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseTemplate(data, sourceBuiltin)
+		if err != nil {
+			return nil, err
+		}
+		templates[t.Name] = t
+	}
+
+	cliHome, err := util.GetCliHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(cliHome, opsTemplateDir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseTemplate(data, file)
+		if err != nil {
+			return nil, err
+		}
+		templates[t.Name] = t
+	}
+
+	if client == nil {
+		return templates, nil
+	}
+	cms, err := client.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: opsTemplateLabelKey + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, cm := range cms.Items {
+		source := fmt.Sprintf("configmap/%s", cm.Name)
+		data, ok := cm.Data[opsTemplateDataKey]
+		if !ok {
+			return nil, errors.Errorf("ops template %s has no %s", source, opsTemplateDataKey)
+		}
+		t, err := parseTemplate([]byte(data), source)
+		if err != nil {
+			return nil, err
+		}
+		templates[t.Name] = t
+	}
+	return templates, nil
+}
+
+// buildParams builds the parameters of the template with the values and the defaults
+func (t *opsTemplate) buildParams(values map[string]string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, p := range t.Parameters {
+		v, ok := values[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, errors.Errorf("parameter %s of template %s is required", p.Name, t.Name)
+			}
+			v = p.Default
+		}
+		params[p.Name] = v
+	}
+	for k := range values {
+		if _, ok := params[k]; !ok {
+			return nil, errors.Errorf("unknown parameter %s of template %s", k, t.Name)
+		}
+	}
+	return params, nil
+}
+
+// render renders the OpsRequest of the template for the cluster
+func (t *opsTemplate) render(cluster *unstructured.Unstructured, values map[string]string) (*unstructured.Unstructured, error) {
+	params, err := t.buildParams(values)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := template.New(t.Name).Funcs(templateFuncs()).Option("missingkey=zero").Parse(t.OpsRequest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the opsRequest of template %s", t.Name)
+	}
+	buf := &bytes.Buffer{}
+	if err = tpl.Execute(buf, templateContext{
+		ClusterName: cluster.GetName(),
+		Namespace:   cluster.GetNamespace(),
+		Cluster:     cluster.Object,
+		Params:      params,
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to render template %s", t.Name)
+	}
+	obj := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal(buf.Bytes(), &obj.Object); err != nil {
+		return nil, errors.Wrapf(err, "template %s renders an invalid OpsRequest:\n%s", t.Name, buf.String())
+	}
+	return obj, nil
+}
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// splitList splits the string by the separator and drops the empty items
+		"splitList": func(sep, s string) []string {
+			var items []string
+			for _, item := range strings.Split(s, sep) {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items
+		},
+		"has": func(items []string, item string) bool {
+			for _, i := range items {
+				if strings.EqualFold(i, item) {
+					return true
+				}
+			}
+			return false
+		},
+		// weekday returns the abbreviated name of today, such as Sat
+		"weekday": func() string {
+			return now().Weekday().String()[:3]
+		},
+		"fail": func(msg string) (string, error) {
+			return "", errors.New(msg)
+		},
+		// growQuantity grows the quantity by the percentage, rounded up to Mi
+		"growQuantity": func(quantity interface{}, pct string) (string, error) {
+			q, err := resource.ParseQuantity(fmt.Sprint(quantity))
+			if err != nil {
+				return "", err
+			}
+			p, err := strconv.ParseFloat(pct, 64)
+			if err != nil || p <= 0 {
+				return "", errors.Errorf("invalid percentage %s", pct)
+			}
+			const mi = 1024 * 1024
+			v := int64(float64(q.Value()) * (1 + p/100))
+			v = (v + mi - 1) / mi * mi
+			return resource.NewQuantity(v, resource.BinarySI).String(), nil
+		},
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ops
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("ops template", func() {
+	BeforeEach(func() {
+		Expect(os.Setenv(types.CliHomeEnv, GinkgoT().TempDir())).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(types.CliHomeEnv)).Should(Succeed())
+	})
+
+	It("grow the quantity by the percentage", func() {
+		grow := templateFuncs()["growQuantity"].(func(interface{}, string) (string, error))
+		Expect(grow("10Gi", "20")).Should(Equal("12Gi"))
+		Expect(grow("1Gi", "50")).Should(Equal("1536Mi"))
+		_, err := grow("1Gi", "-1")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("build the parameters with the defaults", func() {
+		t := &opsTemplate{Name: "test", Parameters: []templateParameter{
+			{Name: "pct", Default: "20"},
+			{Name: "components"},
+			{Name: "target", Required: true},
+		}}
+		params, err := t.buildParams(map[string]string{"target": "mysql"})
+		Expect(err).Should(Succeed())
+		Expect(params).Should(Equal(map[string]string{"pct": "20", "components": "", "target": "mysql"}))
+
+		_, err = t.buildParams(map[string]string{})
+		Expect(err).Should(MatchError(ContainSubstring("parameter target of template test is required")))
+		_, err = t.buildParams(map[string]string{"target": "mysql", "unknown": "1"})
+		Expect(err).Should(MatchError(ContainSubstring("unknown parameter unknown")))
+	})
+
+	It("load the templates from the builtin, the kbcli home and the ConfigMaps", func() {
+		dir := filepath.Join(os.Getenv(types.CliHomeEnv), opsTemplateDir)
+		Expect(os.MkdirAll(dir, 0750)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "restart.yaml"), []byte("name: my-restart\nopsRequest: 'kind: OpsRequest'\n"), 0644)).Should(Succeed())
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared",
+				Namespace: clitesting.Namespace,
+				Labels:    map[string]string{opsTemplateLabelKey: "true"},
+			},
+			Data: map[string]string{opsTemplateDataKey: "name: expand-storage\nopsRequest: 'kind: OpsRequest'\n"},
+		}
+		tpls, err := loadTemplates(clitesting.FakeClientSet(cm), clitesting.Namespace)
+		Expect(err).Should(Succeed())
+		Expect(tpls).Should(HaveKey("weekend-safe-restart"))
+		Expect(tpls["weekend-safe-restart"].source).Should(Equal(sourceBuiltin))
+		Expect(tpls["my-restart"].source).Should(Equal(filepath.Join(dir, "restart.yaml")))
+		// the ConfigMap template overrides the builtin template
+		Expect(tpls["expand-storage"].source).Should(Equal("configmap/shared"))
+	})
+})
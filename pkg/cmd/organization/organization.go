@@ -29,10 +29,10 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var organizationExample = templates.Examples(`
@@ -117,9 +117,9 @@ func newOrgListCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "list",
 		Short: "List all organizations you have joined.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runList())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runList())
 		},
 	}
 
@@ -133,9 +133,9 @@ func newOrgSwitchCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "switch",
 		Short: "Switch to another organization you are already a member of.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runSwitch())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runSwitch())
 		},
 	}
 
@@ -149,9 +149,9 @@ func newOrgCurrentCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "current",
 		Short: "Get current organization.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runCurrent())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runCurrent())
 		},
 	}
 
@@ -165,9 +165,9 @@ func newOrgDescribeCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Use:   "describe",
 		Short: "Get the description information of an organization.",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.complete(args))
-			cmdutil.CheckErr(o.validate(cmd))
-			cmdutil.CheckErr(o.runDescribe())
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate(cmd))
+			util.CheckErr(o.runDescribe())
 		},
 	}
 
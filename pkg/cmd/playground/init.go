@@ -60,6 +60,9 @@ on the created kubernetes cluster, and an apecloud-mysql cluster named mycluster
 		# create a k3d cluster on local host and install KubeBlocks
 		kbcli playground init
 
+		# create a k3d cluster with 2 worker nodes on local host and install KubeBlocks
+		kbcli playground init --workers 2
+
 		# create an AWS EKS cluster and install KubeBlocks, the region is required
 		kbcli playground init --cloud-provider aws --region us-west-1
 
@@ -72,6 +75,9 @@ on the created kubernetes cluster, and an apecloud-mysql cluster named mycluster
 		# create a Google cloud GKE cluster and install KubeBlocks, the region is required
 		kbcli playground init --cloud-provider gcp --region us-east1
 
+		# create an AWS EKS cluster by the managed kubernetes service name and install KubeBlocks
+		kbcli playground init --cloud-provider eks --region us-west-1
+
 		# after init, run the following commands to experience KubeBlocks quickly
 		# list database cluster and check its status
 		kbcli cluster list
@@ -105,6 +111,8 @@ type initOptions struct {
 	region         string
 	autoApprove    bool
 	dockerVersion  *gv.Version
+	// workers is the number of worker nodes of the local k3d cluster
+	workers int
 
 	baseOptions
 }
@@ -129,7 +137,8 @@ func newInitCmd(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVar(&o.clusterDef, "cluster-definition", defaultClusterDef, "Specify the cluster definition, run \"kbcli cd list\" to get the available cluster definitions")
 	cmd.Flags().StringVar(&o.clusterVersion, "cluster-version", "", "Specify the cluster version, run \"kbcli cv list\" to get the available cluster versions")
 	cmd.Flags().StringVar(&o.kbVersion, "version", version.DefaultKubeBlocksVersion, "KubeBlocks version")
-	cmd.Flags().StringVar(&o.cloudProvider, "cloud-provider", defaultCloudProvider, fmt.Sprintf("Cloud provider type, one of %v", supportedCloudProviders))
+	cmd.Flags().StringVar(&o.cloudProvider, "cloud-provider", defaultCloudProvider, fmt.Sprintf("Cloud provider type, one of %v, or the managed kubernetes service name such as eks, ack and gke", supportedCloudProviders))
+	cmd.Flags().IntVar(&o.workers, "workers", 0, "The number of worker nodes of the local k3d cluster, only the server node is created if it is 0")
 	cmd.Flags().StringVar(&o.region, "region", "", "The region to create kubernetes cluster")
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 300*time.Second, "Time to wait for init playground, such as --timeout=10m")
 	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval during the initialization of playground")
@@ -145,6 +154,7 @@ func newInitCmd(streams genericiooptions.IOStreams) *cobra.Command {
 func (o *initOptions) complete(cmd *cobra.Command) error {
 	var err error
 
+	o.cloudProvider = cp.NormalizeCloudProvider(o.cloudProvider)
 	if o.cloudProvider != cp.Local {
 		return nil
 	}
@@ -169,6 +179,14 @@ func (o *initOptions) validate() error {
 		return fmt.Errorf("region should be specified when cloud provider %s is specified", o.cloudProvider)
 	}
 
+	if o.workers < 0 {
+		return fmt.Errorf("the number of worker nodes can not be negative")
+	}
+
+	if o.cloudProvider != cp.Local && o.workers > 0 {
+		return fmt.Errorf("--workers is only supported by the local k3d cluster")
+	}
+
 	if o.clusterDef == "" {
 		return fmt.Errorf("a valid cluster definition is needed, use --cluster-definition to specify one")
 	}
@@ -206,6 +224,7 @@ func (o *initOptions) local() error {
 		clusterInfo = &cp.K8sClusterInfo{
 			CloudProvider: provider.Name(),
 			ClusterName:   types.K3dClusterName,
+			Workers:       o.workers,
 		}
 	}
 
@@ -515,11 +534,11 @@ func (o *initOptions) createCluster() error {
 		Tenancy:            "SharedNode",
 	}
 
-	// if we are running on local, create cluster with one replica
-	if o.cloudProvider == cp.Local {
+	// if we are running on local with a single node, create cluster with one replica
+	if o.cloudProvider == cp.Local && o.workers < 2 {
 		c.Values = append(c.Values, "replicas=1")
 	} else {
-		// if we are running on cloud, create cluster with three replicas
+		// if we are running on cloud or multiple nodes, create cluster with three replicas
 		c.Values = append(c.Values, "replicas=3")
 	}
 
@@ -87,5 +87,23 @@ var _ = Describe("playground", func() {
 			cloudProvider:  cp.AWS,
 		}
 		Expect(o.validate()).Should(HaveOccurred())
+
+		By("worker nodes are only supported by local cluster")
+		o.region = "us-west-1"
+		o.workers = 2
+		Expect(o.validate()).Should(MatchError(ContainSubstring("--workers")))
+
+		By("azure is not supported")
+		o.workers = 0
+		o.cloudProvider = cp.Azure
+		Expect(o.validate()).Should(MatchError(ContainSubstring("not supported")))
+	})
+
+	It("normalize cloud provider", func() {
+		Expect(cp.NormalizeCloudProvider("eks")).Should(Equal(cp.AWS))
+		Expect(cp.NormalizeCloudProvider("ACK")).Should(Equal(cp.AliCloud))
+		Expect(cp.NormalizeCloudProvider("aks")).Should(Equal("aks"))
+		Expect(cp.NormalizeCloudProvider("gke")).Should(Equal(cp.GCP))
+		Expect(cp.NormalizeCloudProvider(cp.Local)).Should(Equal(cp.Local))
 	})
 })
@@ -25,8 +25,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var pluginDescribeExample = templates.Examples(`
@@ -44,7 +45,7 @@ func NewPluginDescribeCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Example: pluginDescribeExample,
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(printPluginInfo(streams.Out, args[0]))
+			util.CheckErr(printPluginInfo(streams.Out, args[0]))
 		},
 	}
 	return cmd
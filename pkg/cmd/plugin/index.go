@@ -28,7 +28,6 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/printer"
@@ -136,7 +135,7 @@ func NewPluginIndexListCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Short:   "List configured indexes",
 		Example: pluginListIndexExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.ListIndex())
+			util.CheckErr(o.ListIndex())
 		},
 	}
 
@@ -156,7 +155,7 @@ func NewPluginIndexAddCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			o.IndexName = args[0]
 			o.URL = args[1]
-			cmdutil.CheckErr(o.AddIndex())
+			util.CheckErr(o.AddIndex())
 		},
 	}
 
@@ -175,7 +174,7 @@ func NewPluginIndexDeleteCmd(streams genericiooptions.IOStreams) *cobra.Command
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			o.IndexName = args[0]
-			cmdutil.CheckErr(o.DeleteIndex())
+			util.CheckErr(o.DeleteIndex())
 		},
 	}
 
@@ -191,7 +190,7 @@ func NewPluginIndexUpdateCmd(streams genericiooptions.IOStreams) *cobra.Command
 		Use:   "update",
 		Short: "update all configured indexes",
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.UpdateIndex())
+			util.CheckErr(o.UpdateIndex())
 		},
 	}
 
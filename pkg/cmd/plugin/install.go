@@ -29,10 +29,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/cmd/plugin/download"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -65,8 +65,8 @@ func NewPluginInstallCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Short:   "Install kbcli or kubectl plugins",
 		Example: pluginInstallExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Install())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Install())
 		},
 	}
 	return cmd
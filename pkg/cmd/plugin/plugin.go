@@ -30,7 +30,6 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/printer"
@@ -59,6 +58,7 @@ func NewPluginCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Short: "Provides utilities for interacting with plugins.",
 		Long:  pluginLong,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(util.RunParentPersistentPreRun(cmd, args))
 			InitPlugin()
 		},
 	}
@@ -79,6 +79,8 @@ type PluginListOptions struct {
 	Verifier PathVerifier
 
 	PluginPaths []string
+	// ReceiptsPath is the directory of the receipts of the plugins installed from index
+	ReceiptsPath string
 
 	genericiooptions.IOStreams
 }
@@ -93,8 +95,8 @@ func NewPluginListCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Short:                 "List all visible plugin executables on a user's PATH",
 		Example:               pluginListExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(cmd))
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(cmd))
+			util.CheckErr(o.Run())
 		},
 	}
 	return cmd
@@ -107,6 +109,7 @@ func (o *PluginListOptions) Complete(cmd *cobra.Command) error {
 	}
 
 	o.PluginPaths = filepath.SplitList(os.Getenv("PATH"))
+	o.ReceiptsPath = paths.InstallReceiptsPath()
 	return nil
 }
 
@@ -117,6 +120,7 @@ func (o *PluginListOptions) Run() error {
 		pluginErrors = append(pluginErrors, fmt.Errorf("error: unable to find any kbcli or kubectl plugins in your PATH"))
 	}
 
+	receipts := installedPluginReceipts(o.ReceiptsPath)
 	pluginWarnings := 0
 	p := NewPluginPrinter(o.IOStreams.Out)
 	errMsg := ""
@@ -129,7 +133,7 @@ func (o *PluginListOptions) Run() error {
 				pluginWarnings++
 			}
 		}
-		addPluginRow(name, path, p)
+		addPluginRow(name, path, receipts[name], p)
 	}
 	p.Print()
 	klog.V(1).Info(errMsg)
@@ -281,12 +285,36 @@ func hasValidPrefix(filepath string, validPrefixes []string) bool {
 
 func NewPluginPrinter(out io.Writer) *printer.TablePrinter {
 	t := printer.NewTablePrinter(out)
-	t.SetHeader("NAME", "PATH")
+	t.SetHeader("NAME", "PATH", "VERSION", "INDEX")
 	return t
 }
 
-func addPluginRow(name, path string, p *printer.TablePrinter) {
-	p.AddRow(name, path)
+// addPluginRow adds a plugin row, the version and index are only available for the
+// plugins installed from index.
+func addPluginRow(name, path string, receipt *Receipt, p *printer.TablePrinter) {
+	version, index := "", ""
+	if receipt != nil {
+		version, index = receipt.Spec.Version, receipt.Status.Source.Name
+	}
+	p.AddRow(name, path, version, index)
+}
+
+// installedPluginReceipts returns the receipts of the plugins installed from index, keyed
+// by the plugin binary name.
+func installedPluginReceipts(receiptsDir string) map[string]*Receipt {
+	res := map[string]*Receipt{}
+	if receiptsDir == "" {
+		return res
+	}
+	receipts, err := GetInstalledPluginReceipts(receiptsDir)
+	if err != nil {
+		klog.V(1).Infof("failed to get installed plugin receipts: %v", err)
+		return res
+	}
+	for i := range receipts {
+		res[pluginNameToBin(receipts[i].Name, util.IsWindows())] = &receipts[i]
+	}
+	return res
 }
 
 func InitPlugin() {
@@ -27,7 +27,10 @@ import (
 	"strings"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 func TestPluginPathsAreUnaltered(t *testing.T) {
@@ -240,3 +243,27 @@ func (f *fakePluginPathVerifier) Verify(path string) []error {
 func newFakePluginPathVerifier() *fakePluginPathVerifier {
 	return &fakePluginPathVerifier{seen: make(map[string]bool)}
 }
+
+func TestInstalledPluginReceipts(t *testing.T) {
+	receiptsDir := t.TempDir()
+	plugin := Plugin{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-bar"},
+		Spec:       PluginSpec{Version: "v0.1.0"},
+	}
+	receipt := NewReceipt(plugin, "default", metav1.Now())
+	if err := StoreReceipt(receipt, filepath.Join(receiptsDir, "foo-bar"+ManifestExtension)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	receipts := installedPluginReceipts(receiptsDir)
+	r, ok := receipts[pluginNameToBin("foo-bar", util.IsWindows())]
+	if !ok {
+		t.Fatalf("receipt of plugin foo-bar not found in %v", receipts)
+	}
+	if r.Spec.Version != "v0.1.0" || r.Status.Source.Name != "default" {
+		t.Fatalf("unexpected receipt %v", r)
+	}
+	if len(installedPluginReceipts("")) != 0 {
+		t.Fatalf("expect no receipts without receipts dir")
+	}
+}
@@ -27,7 +27,6 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/apecloud/kbcli/pkg/util"
@@ -46,7 +45,7 @@ func NewPluginUninstallCmd(_ genericiooptions.IOStreams) *cobra.Command {
 		Short:   "Uninstall kbcli or kubectl plugins",
 		Example: pluginUninstallExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(uninstallPlugins(args))
+			util.CheckErr(uninstallPlugins(args))
 		},
 	}
 
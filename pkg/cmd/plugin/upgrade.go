@@ -29,8 +29,9 @@ import (
 	k8sver "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/klog/v2"
-	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var (
@@ -61,8 +62,8 @@ func NewPluginUpgradeCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		Short:   "Upgrade kbcli or kubectl plugins",
 		Example: pluginUpgradeExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(o.Complete(args))
-			cmdutil.CheckErr(o.Run())
+			util.CheckErr(o.Complete(args))
+			util.CheckErr(o.Run())
 		},
 	}
 
@@ -0,0 +1,408 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var generateExample = templates.Examples(`
+	# print the ClusterRoles and bindings for the viewer persona of user alice in the current namespace
+	kbcli rbac generate --role viewer --user alice
+
+	# grant the operator persona to the service account ci/deployer in namespace demo
+	kbcli rbac generate --role operator --serviceaccount ci:deployer -n demo --apply
+
+	# grant the backup-admin persona to group dba in all namespaces
+	kbcli rbac generate --role backup-admin --group dba --cluster-wide`)
+
+const (
+	roleOperator    = "operator"
+	roleViewer      = "viewer"
+	roleBackupAdmin = "backup-admin"
+)
+
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// permission is the verbs a persona requires on a resource
+type permission struct {
+	gvr           schema.GroupVersionResource
+	clusterScoped bool
+	verbs         []string
+}
+
+func namespaced(gvr schema.GroupVersionResource, verbs []string) permission {
+	return permission{gvr: gvr, verbs: verbs}
+}
+
+func clusterScoped(gvr schema.GroupVersionResource, verbs []string) permission {
+	return permission{gvr: gvr, clusterScoped: true, verbs: verbs}
+}
+
+func subresource(gvr schema.GroupVersionResource, sub string, verbs []string) permission {
+	gvr.Resource = gvr.Resource + "/" + sub
+	return permission{gvr: gvr, verbs: verbs}
+}
+
+// definitionPermissions are the permissions to read the cluster-scoped definitions all the personas require
+func definitionPermissions() []permission {
+	return []permission{
+		clusterScoped(types.ClusterDefGVR(), readVerbs),
+		clusterScoped(types.ClusterVersionGVR(), readVerbs),
+		clusterScoped(types.CompDefGVR(), readVerbs),
+		clusterScoped(types.ConfigConstraintGVR(), readVerbs),
+		clusterScoped(types.BackupPolicyTemplateGVR(), readVerbs),
+		clusterScoped(types.ActionSetGVR(), readVerbs),
+		clusterScoped(types.AddonGVR(), readVerbs),
+		clusterScoped(types.StorageClassGVR(), readVerbs),
+	}
+}
+
+// workloadPermissions are the permissions to read the workloads of the clusters
+func workloadPermissions() []permission {
+	return []permission{
+		namespaced(types.PodGVR(), readVerbs),
+		subresource(types.PodGVR(), "log", readVerbs),
+		namespaced(types.ServiceGVR(), readVerbs),
+		namespaced(types.ConfigmapGVR(), readVerbs),
+		namespaced(types.PVCGVR(), readVerbs),
+		namespaced(types.StatefulSetGVR(), readVerbs),
+		namespaced(types.RSMGVR(), readVerbs),
+		namespaced(schema.GroupVersionResource{Version: types.K8sCoreAPIVersion, Resource: "events"}, readVerbs),
+	}
+}
+
+// personaPermissions returns the permissions the persona requires
+func personaPermissions(role string) ([]permission, error) {
+	perms := append(definitionPermissions(), workloadPermissions()...)
+	switch role {
+	case roleViewer:
+		return append(perms,
+			namespaced(types.ClusterGVR(), readVerbs),
+			namespaced(types.OpsGVR(), readVerbs),
+			namespaced(types.BackupGVR(), readVerbs),
+			namespaced(types.BackupPolicyGVR(), readVerbs),
+			namespaced(types.BackupScheduleGVR(), readVerbs),
+			namespaced(types.RestoreGVR(), readVerbs),
+			clusterScoped(types.BackupRepoGVR(), readVerbs),
+		), nil
+	case roleOperator:
+		return append(perms,
+			namespaced(types.ClusterGVR(), writeVerbs),
+			namespaced(types.OpsGVR(), writeVerbs),
+			namespaced(types.BackupGVR(), writeVerbs),
+			namespaced(types.BackupPolicyGVR(), writeVerbs),
+			namespaced(types.BackupScheduleGVR(), writeVerbs),
+			namespaced(types.RestoreGVR(), writeVerbs),
+			namespaced(types.SecretGVR(), readVerbs),
+			subresource(types.PodGVR(), "exec", []string{"create"}),
+			subresource(types.PodGVR(), "portforward", []string{"create"}),
+			clusterScoped(types.BackupRepoGVR(), readVerbs),
+		), nil
+	case roleBackupAdmin:
+		return append(perms,
+			namespaced(types.ClusterGVR(), readVerbs),
+			namespaced(types.OpsGVR(), readVerbs),
+			namespaced(types.BackupGVR(), writeVerbs),
+			namespaced(types.BackupPolicyGVR(), writeVerbs),
+			namespaced(types.BackupScheduleGVR(), writeVerbs),
+			namespaced(types.RestoreGVR(), writeVerbs),
+			clusterScoped(types.BackupRepoGVR(), writeVerbs),
+			clusterScoped(types.StorageProviderGVR(), readVerbs),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown role %s, supported roles are %s", role, strings.Join(supportedRoles(), ", "))
+	}
+}
+
+func supportedRoles() []string {
+	return []string{roleOperator, roleViewer, roleBackupAdmin}
+}
+
+// buildPolicyRules merges the permissions with the same api group and verbs into one rule
+func buildPolicyRules(perms []permission) []rbacv1.PolicyRule {
+	type ruleKey struct {
+		group string
+		verbs string
+	}
+	var (
+		keys      []ruleKey
+		resources = map[ruleKey][]string{}
+	)
+	for _, p := range perms {
+		key := ruleKey{group: p.gvr.Group, verbs: strings.Join(p.verbs, ",")}
+		if _, ok := resources[key]; !ok {
+			keys = append(keys, key)
+		}
+		resources[key] = append(resources[key], p.gvr.Resource)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].verbs < keys[j].verbs
+	})
+	rules := make([]rbacv1.PolicyRule, 0, len(keys))
+	for _, key := range keys {
+		res := resources[key]
+		sort.Strings(res)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{key.group},
+			Resources: res,
+			Verbs:     strings.Split(key.verbs, ","),
+		})
+	}
+	return rules
+}
+
+type generateOptions struct {
+	factory   cmdutil.Factory
+	dynamic   dynamic.Interface
+	namespace string
+
+	role            string
+	users           []string
+	groups          []string
+	serviceAccounts []string
+	clusterWide     bool
+	apply           bool
+
+	genericiooptions.IOStreams
+}
+
+func newGenerateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &generateOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "generate",
+		Short:   "Generate the ClusterRoles and bindings covering the resources and verbs a kbcli persona needs.",
+		Example: generateExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.role, "role", "", fmt.Sprintf("The kbcli persona, one of %s", strings.Join(supportedRoles(), "|")))
+	cmd.Flags().StringSliceVar(&o.users, "user", nil, "The users to bind the persona to")
+	cmd.Flags().StringSliceVar(&o.groups, "group", nil, "The groups to bind the persona to")
+	cmd.Flags().StringSliceVar(&o.serviceAccounts, "serviceaccount", nil, "The service accounts to bind the persona to, in the format of <namespace>:<name>")
+	cmd.Flags().BoolVar(&o.clusterWide, "cluster-wide", false, "Grant the persona in all namespaces, by default the namespaced resources are granted in the current namespace only")
+	cmd.Flags().BoolVar(&o.apply, "apply", false, "Apply the manifests to the kubernetes cluster instead of printing them")
+	_ = cmd.MarkFlagRequired("role")
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("role", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return supportedRoles(), cobra.ShellCompDirectiveNoFileComp
+	}))
+	return cmd
+}
+
+func (o *generateOptions) complete() error {
+	var err error
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.apply {
+		if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *generateOptions) run() error {
+	objs, err := o.buildObjects()
+	if err != nil {
+		return err
+	}
+	if !o.apply {
+		for i, obj := range objs {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Fprintln(o.Out, "---")
+			}
+			fmt.Fprint(o.Out, string(data))
+		}
+		return nil
+	}
+	for _, obj := range objs {
+		if action.ExportEnabled() {
+			if err = action.ExportObject(obj, o.Out); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = o.applyObject(obj); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s/%s applied\n", strings.ToLower(obj.GetKind()), obj.GetName())
+	}
+	return nil
+}
+
+// buildObjects builds the ClusterRoles and the bindings of the persona, the namespaced permissions are bound
+// by a RoleBinding in the namespace unless cluster-wide, and the cluster-scoped permissions are always bound by
+// a ClusterRoleBinding because they can not be granted by a RoleBinding.
+func (o *generateOptions) buildObjects() ([]*unstructured.Unstructured, error) {
+	perms, err := personaPermissions(o.role)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := o.buildSubjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		objs       []*unstructured.Unstructured
+		nsPerms    []permission
+		clusPerms  []permission
+		roleName   = fmt.Sprintf("kbcli-%s", o.role)
+		labels     = map[string]string{constant.AppManagedByLabelKey: "kbcli"}
+		addObjects = func(name string, perms []permission, binding runtime.Object) error {
+			role := &rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+				Rules:      buildPolicyRules(perms),
+			}
+			for _, obj := range []runtime.Object{role, binding} {
+				if obj == nil {
+					continue
+				}
+				u, err := util.ConvertObjToUnstructured(obj)
+				if err != nil {
+					return err
+				}
+				objs = append(objs, u)
+			}
+			return nil
+		}
+	)
+	for _, p := range perms {
+		if p.clusterScoped || o.clusterWide {
+			clusPerms = append(clusPerms, p)
+		} else {
+			nsPerms = append(nsPerms, p)
+		}
+	}
+
+	if len(nsPerms) > 0 {
+		var binding runtime.Object
+		if len(subjects) > 0 {
+			binding = &rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: o.namespace, Labels: labels},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: roleName},
+				Subjects:   subjects,
+			}
+		}
+		if err = addObjects(roleName, nsPerms, binding); err != nil {
+			return nil, err
+		}
+	}
+
+	clusterRoleName := roleName
+	if len(nsPerms) > 0 {
+		clusterRoleName = roleName + "-cluster"
+	}
+	var binding runtime.Object
+	if len(subjects) > 0 {
+		binding = &rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName, Labels: labels},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: clusterRoleName},
+			Subjects:   subjects,
+		}
+	}
+	if err = addObjects(clusterRoleName, clusPerms, binding); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+func (o *generateOptions) buildSubjects() ([]rbacv1.Subject, error) {
+	var subjects []rbacv1.Subject
+	for _, u := range o.users {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: u})
+	}
+	for _, g := range o.groups {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: g})
+	}
+	for _, sa := range o.serviceAccounts {
+		ns, name, found := strings.Cut(sa, ":")
+		if !found || ns == "" || name == "" {
+			return nil, fmt.Errorf("invalid service account %s, should be in the format of <namespace>:<name>", sa)
+		}
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: ns, Name: name})
+	}
+	if o.apply && len(subjects) == 0 {
+		return nil, fmt.Errorf("at least one of --user, --group and --serviceaccount should be specified with --apply")
+	}
+	return subjects, nil
+}
+
+// applyObject creates the object, or updates it if it already exists
+func (o *generateOptions) applyObject(obj *unstructured.Unstructured) error {
+	gvr := types.ClusterRoleGVR()
+	switch obj.GetKind() {
+	case "ClusterRoleBinding":
+		gvr = types.ClusterRoleBindingGVR()
+	case "RoleBinding":
+		gvr = types.RoleBindingGVR()
+	}
+	client := o.dynamic.Resource(gvr).Namespace(obj.GetNamespace())
+	_, err := client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package rbac
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/exp/slices"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	clitesting "github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("rbac generate", func() {
+	const namespace = "demo"
+
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = clitesting.NewTestFactory(namespace)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	findRule := func(rules []rbacv1.PolicyRule, group, resource string) *rbacv1.PolicyRule {
+		for i, r := range rules {
+			if slices.Contains(r.APIGroups, group) && slices.Contains(r.Resources, resource) {
+				return &rules[i]
+			}
+		}
+		return nil
+	}
+
+	It("rbac command", func() {
+		cmd := NewRBACCmd(tf, streams)
+		Expect(cmd).ShouldNot(BeNil())
+		Expect(cmd.HasSubCommands()).Should(BeTrue())
+	})
+
+	It("unknown role", func() {
+		_, err := personaPermissions("admin")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("build the policy rules of the personas", func() {
+		perms, err := personaPermissions(roleViewer)
+		Expect(err).ShouldNot(HaveOccurred())
+		rules := buildPolicyRules(perms)
+		rule := findRule(rules, types.AppsAPIGroup, "clusters")
+		Expect(rule).ShouldNot(BeNil())
+		Expect(rule.Verbs).Should(Equal(readVerbs))
+		Expect(findRule(rules, "", "pods/exec")).Should(BeNil())
+
+		perms, err = personaPermissions(roleOperator)
+		Expect(err).ShouldNot(HaveOccurred())
+		rules = buildPolicyRules(perms)
+		Expect(findRule(rules, types.AppsAPIGroup, "opsrequests").Verbs).Should(Equal(writeVerbs))
+		Expect(findRule(rules, "", "pods/exec").Verbs).Should(Equal([]string{"create"}))
+
+		perms, err = personaPermissions(roleBackupAdmin)
+		Expect(err).ShouldNot(HaveOccurred())
+		rules = buildPolicyRules(perms)
+		Expect(findRule(rules, types.DPAPIGroup, "backuprepos").Verbs).Should(Equal(writeVerbs))
+		Expect(findRule(rules, types.AppsAPIGroup, "clusters").Verbs).Should(Equal(readVerbs))
+	})
+
+	It("generate the namespaced manifests", func() {
+		o := &generateOptions{
+			namespace:       namespace,
+			role:            roleOperator,
+			users:           []string{"alice"},
+			serviceAccounts: []string{"ci:deployer"},
+			IOStreams:       streams,
+		}
+		objs, err := o.buildObjects()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(objs).Should(HaveLen(4))
+		Expect(objs[0].GetKind()).Should(Equal("ClusterRole"))
+		Expect(objs[1].GetKind()).Should(Equal("RoleBinding"))
+		Expect(objs[1].GetNamespace()).Should(Equal(namespace))
+		Expect(objs[2].GetName()).Should(Equal("kbcli-operator-cluster"))
+		Expect(objs[3].GetKind()).Should(Equal("ClusterRoleBinding"))
+
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("kind: RoleBinding"))
+		Expect(out.String()).Should(ContainSubstring("name: deployer"))
+
+		By("invalid service account")
+		o.serviceAccounts = []string{"deployer"}
+		_, err = o.buildObjects()
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("generate the cluster-wide manifests without subjects", func() {
+		o := &generateOptions{
+			namespace:   namespace,
+			role:        roleViewer,
+			clusterWide: true,
+			IOStreams:   streams,
+		}
+		objs, err := o.buildObjects()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(objs).Should(HaveLen(1))
+		Expect(objs[0].GetName()).Should(Equal("kbcli-viewer"))
+	})
+
+	It("apply the manifests", func() {
+		dynamic := clitesting.FakeDynamicClient()
+		o := &generateOptions{
+			dynamic:   dynamic,
+			namespace: namespace,
+			role:      roleBackupAdmin,
+			groups:    []string{"dba"},
+			apply:     true,
+			IOStreams: streams,
+		}
+		Expect(o.run()).Should(Succeed())
+		// apply again to update the existing objects
+		Expect(o.run()).Should(Succeed())
+		binding, err := dynamic.Resource(types.RoleBindingGVR()).Namespace(namespace).Get(context.TODO(), "kbcli-backup-admin", metav1.GetOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		rb := &rbacv1.RoleBinding{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(binding.Object, rb)).Should(Succeed())
+		Expect(rb.Subjects[0].Name).Should(Equal("dba"))
+
+		By("subjects are required to apply")
+		o.groups = nil
+		Expect(o.run()).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,212 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package report
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+const (
+	zipArchive   = "zip"
+	tarGzArchive = "tar.gz"
+
+	truncatedMsg = "\n=============truncated: exceeds the report size limit=============\n"
+)
+
+var supportedArchiveFormats = []string{zipArchive, tarGzArchive}
+
+// archiveWritter is the archive the report is written into, zip.Writer implements it natively.
+type archiveWritter interface {
+	Create(name string) (io.Writer, error)
+	Close() error
+}
+
+var _ archiveWritter = &zip.Writer{}
+var _ archiveWritter = &tarGzWritter{}
+var _ archiveWritter = &sizeLimitedWritter{}
+
+// archiveFormatOf returns the archive format by the file extension, an empty string is
+// returned if the extension is unknown.
+func archiveFormatOf(file string) string {
+	switch {
+	case strings.HasSuffix(file, ".tar.gz"), strings.HasSuffix(file, ".tgz"):
+		return tarGzArchive
+	case strings.HasSuffix(file, ".zip"):
+		return zipArchive
+	default:
+		return ""
+	}
+}
+
+func newArchiveWritter(format string, w io.Writer) (archiveWritter, error) {
+	switch format {
+	case zipArchive, "":
+		return zip.NewWriter(w), nil
+	case tarGzArchive:
+		gz := gzip.NewWriter(w)
+		return &tarGzWritter{gz: gz, tw: tar.NewWriter(gz)}, nil
+	default:
+		return nil, fmt.Errorf("archive format %s is not supported, should be one of %s", format, strings.Join(supportedArchiveFormats, "|"))
+	}
+}
+
+// tarGzWritter writes files into a gzip compressed tarball, the tar header requires the
+// file size, so the content of current file is buffered until next file is created.
+type tarGzWritter struct {
+	gz      *gzip.Writer
+	tw      *tar.Writer
+	current string
+	buf     bytes.Buffer
+}
+
+func (t *tarGzWritter) Create(name string) (io.Writer, error) {
+	if err := t.flush(); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, "/") {
+		return io.Discard, t.tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     name,
+			Mode:     0755,
+			ModTime:  time.Now(),
+		})
+	}
+	t.current = name
+	return &t.buf, nil
+}
+
+func (t *tarGzWritter) flush() error {
+	if t.current == "" {
+		return nil
+	}
+	defer func() {
+		t.current = ""
+		t.buf.Reset()
+	}()
+	if err := t.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     t.current,
+		Mode:     0644,
+		Size:     int64(t.buf.Len()),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := t.tw.Write(t.buf.Bytes())
+	return err
+}
+
+func (t *tarGzWritter) Close() error {
+	if err := t.flush(); err != nil {
+		return err
+	}
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gz.Close()
+}
+
+// sizeLimitedWritter limits the size of each file and the total size of the files written
+// into the archive, the content exceeding the limit is dropped and a truncated message is
+// appended, zero means no limit. A notice is printed to out when a file is truncated.
+type sizeLimitedWritter struct {
+	archiveWritter
+	out         io.Writer
+	maxFileSize int64
+	maxSize     int64
+	written     int64
+	truncated   map[string]bool
+}
+
+func newSizeLimitedWritter(w archiveWritter, out io.Writer, maxFileSize int64, maxSize int64) *sizeLimitedWritter {
+	return &sizeLimitedWritter{
+		archiveWritter: w,
+		out:            out,
+		maxFileSize:    maxFileSize,
+		maxSize:        maxSize,
+		truncated:      map[string]bool{},
+	}
+}
+
+func (s *sizeLimitedWritter) Create(name string) (io.Writer, error) {
+	w, err := s.archiveWritter.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return &limitedFileWritter{parent: s, name: name, w: w}, nil
+}
+
+// TruncatedFiles returns the files truncated because of the size limit.
+func (s *sizeLimitedWritter) TruncatedFiles() []string {
+	files := make([]string, 0, len(s.truncated))
+	for f := range s.truncated {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+type limitedFileWritter struct {
+	parent  *sizeLimitedWritter
+	name    string
+	w       io.Writer
+	written int64
+}
+
+func (l *limitedFileWritter) Write(p []byte) (int, error) {
+	if l.parent.truncated[l.name] {
+		return len(p), nil
+	}
+	allowed := int64(len(p))
+	if l.parent.maxFileSize > 0 && l.written+allowed > l.parent.maxFileSize {
+		allowed = l.parent.maxFileSize - l.written
+	}
+	if l.parent.maxSize > 0 && l.parent.written+allowed > l.parent.maxSize {
+		allowed = l.parent.maxSize - l.parent.written
+	}
+	if allowed < 0 {
+		allowed = 0
+	}
+	n, err := l.w.Write(p[:allowed])
+	l.written += int64(n)
+	l.parent.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+	if allowed < int64(len(p)) {
+		l.parent.truncated[l.name] = true
+		_, _ = l.w.Write([]byte(truncatedMsg))
+		if l.parent.out != nil {
+			printer.Warning(l.parent.out, "%s is truncated because of the size limits, use --max-file-size and --max-size to adjust the limits\n", l.name)
+		}
+	}
+	// always report the whole buffer is written to avoid breaking the printers
+	return len(p), nil
+}
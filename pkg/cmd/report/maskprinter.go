@@ -22,6 +22,7 @@ package report
 import (
 	"encoding/base64"
 	"io"
+	"regexp"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -36,6 +37,9 @@ const (
 	EncryptedData = "KUBE_BLOCKS_ENCRYPTED_DATA"
 )
 
+// sensitiveEnvNameRegex matches the names of the environment variables that may contain credentials
+var sensitiveEnvNameRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|access_?key|private_?key)`)
+
 type MaskPrinter struct {
 	Delegate printers.ResourcePrinter
 }
@@ -83,6 +87,38 @@ func maskDataField(o runtime.Object) runtime.Object {
 				}
 			}
 		}
+		return o
+	}
+	// mask the plain values of sensitive environment variables in the pod templates
+	if u, ok := o.(*unstructured.Unstructured); ok {
+		maskSensitiveEnv(u.Object)
 	}
 	return o
 }
+
+// maskSensitiveEnv walks through the object and masks the values of environment variables
+// whose names look like credentials, the references to secrets are kept.
+func maskSensitiveEnv(obj interface{}) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		if envs, ok := o["env"].([]interface{}); ok {
+			for _, e := range envs {
+				env, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := env["name"].(string)
+				if _, ok = env["value"]; ok && sensitiveEnvNameRegex.MatchString(name) {
+					env["value"] = EncryptedData
+				}
+			}
+		}
+		for _, v := range o {
+			maskSensitiveEnv(v)
+		}
+	case []interface{}:
+		for _, v := range o {
+			maskSensitiveEnv(v)
+		}
+	}
+}
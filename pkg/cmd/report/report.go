@@ -27,6 +27,7 @@ import (
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -82,6 +83,9 @@ var (
 
 	# report KubeBlocks cluster information with logs for all containers
 	kbcli report cluster mycluster --with-logs --all-containers
+
+	# report KubeBlocks cluster information to a tar.gz file, and limit each log file to 10Mi
+	kbcli report cluster mycluster --with-logs --archive-format tar.gz --max-file-size 10Mi
 	`)
 
 	reportKBExamples = templates.Examples(`
@@ -96,6 +100,9 @@ var (
 
 	# report KubeBlocks information with logs and mask sensitive info
 	kbcli report kubeblocks --with-logs --mask
+
+	# report KubeBlocks information to a tar.gz file with total size limited to 100Mi
+	kbcli report kubeblocks --with-logs -f kubeblocks.tar.gz --max-size 100Mi
 	`)
 )
 
@@ -149,6 +156,12 @@ type reportOptions struct {
 	outputFormat string
 	// reportWritter is used to write report to file
 	reportWritter reportWritter
+	// archiveFormat is the format of the report file, zip or tar.gz
+	archiveFormat string
+	// maxFileSize limits the size of each file in the report, such as a log file
+	maxFileSize string
+	// maxSize limits the total size of the report
+	maxSize string
 }
 
 type reportKubeblocksOptions struct {
@@ -187,7 +200,42 @@ func (o *reportOptions) complete(f cmdutil.Factory) error {
 		return err
 	}
 
-	o.reportWritter = &reportZipWritter{}
+	// complete report writter with archive format and size limits
+	writter := &reportZipWritter{archiveFormat: o.archiveFormat, out: o.Out}
+	if writter.maxFileSize, err = parseSizeLimit("max-file-size", o.maxFileSize); err != nil {
+		return err
+	}
+	if writter.maxSize, err = parseSizeLimit("max-size", o.maxSize); err != nil {
+		return err
+	}
+	o.reportWritter = writter
+	return nil
+}
+
+func parseSizeLimit(flag string, size string) (int64, error) {
+	if len(size) == 0 {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s %s: %v", flag, size, err)
+	}
+	if q.Sign() < 0 {
+		return 0, fmt.Errorf("--%s should not be negative", flag)
+	}
+	return q.Value(), nil
+}
+
+// completeFile completes the report file name and the archive format, the archive format
+// is inferred from the file extension if the file name is specified.
+func (o *reportOptions) completeFile(kind string) error {
+	if format := archiveFormatOf(o.file); format != "" {
+		o.archiveFormat = format
+	}
+	o.file = formatReportName(o.file, kind, o.archiveFormat)
+	if exists, _ := cliutil.FileExists(o.file); exists {
+		return fmt.Errorf("file already exist will not overwrite")
+	}
 	return nil
 }
 
@@ -203,21 +251,30 @@ func (o *reportOptions) validate() error {
 	if slices.Index(o.JSONYamlPrintFlags.AllowedFormats(), o.outputFormat) == -1 {
 		return fmt.Errorf("output format %s is not supported", o.outputFormat)
 	}
+	if len(o.archiveFormat) > 0 && slices.Index(supportedArchiveFormats, o.archiveFormat) == -1 {
+		return fmt.Errorf("archive format %s is not supported, should be one of %s", o.archiveFormat, strings.Join(supportedArchiveFormats, "|"))
+	}
 	return nil
 }
 
 func (o *reportOptions) addFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&o.file, "file", "f", "", "zip file for output")
-	cmd.Flags().BoolVar(&o.mask, "mask", true, "mask sensitive info for secrets and configmaps")
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "zip or tar.gz file for output, the archive format is inferred from the file extension")
+	cmd.Flags().StringVar(&o.archiveFormat, "archive-format", zipArchive, fmt.Sprintf("Archive format of the report file. One of: %s.", strings.Join(supportedArchiveFormats, "|")))
+	cmd.Flags().StringVar(&o.maxFileSize, "max-file-size", "0", "Maximum size of each file in the report such as a log file, the exceeded content is truncated with a notice. 0 means no limit.")
+	cmd.Flags().StringVar(&o.maxSize, "max-size", "0", "Maximum total size of the report, the exceeded content is truncated with a notice. 0 means no limit.")
+	cmd.Flags().BoolVar(&o.mask, "mask", true, "mask sensitive info for secrets, configmaps and sensitive environment variables")
 	cmd.Flags().BoolVar(&o.withLogs, "with-logs", false, "include pod logs")
 	cmd.Flags().BoolVar(&o.allContainers, "all-containers", o.allContainers, "Get all containers' logs in the pod(s). Byt default, only the main container (the first container) will have logs recorded.")
 	cmd.Flags().StringVar(&o.sinceTime, "since-time", o.sinceTime, i18n.T("Only return logs after a specific date (RFC3339). Defaults to all logs. Only one of since-time / since may be used."))
 	cmd.Flags().DurationVar(&o.sinceDuration, "since", o.sinceDuration, "Only return logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs. Only one of since-time / since may be used.")
 
 	cmd.Flags().StringVarP(&o.outputFormat, "output", "o", "json", fmt.Sprintf("Output format. One of: %s.", strings.Join(o.JSONYamlPrintFlags.AllowedFormats(), "|")))
-	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cliutil.CheckErr(cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return o.JSONYamlPrintFlags.AllowedFormats(), cobra.ShellCompDirectiveNoFileComp
 	}))
+	cliutil.CheckErr(cmd.RegisterFlagCompletionFunc("archive-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return supportedArchiveFormats, cobra.ShellCompDirectiveNoFileComp
+	}))
 }
 
 func (o *reportOptions) toLogOptions() (*corev1.PodLogOptions, error) {
@@ -282,9 +339,8 @@ func (o *reportKubeblocksOptions) complete(f cmdutil.Factory) error {
 	}
 	o.namespace, _ = cliutil.GetKubeBlocksNamespace(o.genericClientSet.client)
 	// complete file name
-	o.file = formatReportName(o.file, kubeBlocksReport)
-	if exists, _ := cliutil.FileExists(o.file); exists {
-		return fmt.Errorf("file already exist will not overwrite")
+	if err := o.completeFile(kubeBlocksReport); err != nil {
+		return err
 	}
 	// complete kb selector
 	o.kubeBlocksSelector = metav1.ListOptions{LabelSelector: buildKubeBlocksSelector()}
@@ -318,7 +374,6 @@ func (o *reportKubeblocksOptions) run(f cmdutil.Factory, streams genericiooption
 	if err := o.handleLogs(ctx); err != nil {
 		return err
 	}
-
 	return nil
 }
 
@@ -436,11 +491,8 @@ func (o *reportClusterOptions) complete(f cmdutil.Factory) error {
 		return err
 	}
 	// complete file name
-
-	o.file = formatReportName(o.file, fmt.Sprintf("%s-%s", clusterReport, o.clusterName))
-
-	if exists, _ := cliutil.FileExists(o.file); exists {
-		return fmt.Errorf("file already exist will not overwrite")
+	if err := o.completeFile(fmt.Sprintf("%s-%s", clusterReport, o.clusterName)); err != nil {
+		return err
 	}
 
 	o.clusterSelector = metav1.ListOptions{LabelSelector: buildClusterResourceSelector(o.clusterName)}
@@ -497,6 +549,7 @@ func (o *reportClusterOptions) handleManifests(ctx context.Context) error {
 			types.BackupScheduleGVR(),
 			types.ActionSetGVR(),
 			types.RestoreGVR(),
+			types.OpsGVR(),
 			types.PVCGVR(),
 		}
 		globalGvrs = []schema.GroupVersionResource{
@@ -603,11 +656,14 @@ func spinnerMsg(format string, a ...any) spinner.Option {
 	return spinner.WithMessage(fmt.Sprintf("%-50s", fmt.Sprintf(format, a...)))
 }
 
-func formatReportName(fileName string, kind string) string {
+func formatReportName(fileName string, kind string, archiveFormat string) string {
 	if len(fileName) > 0 {
 		return fileName
 	}
-	return fmt.Sprintf("report-%s-%s.zip", kind, time.Now().Local().Format("2006-01-02-15-04-05"))
+	if len(archiveFormat) == 0 {
+		archiveFormat = zipArchive
+	}
+	return fmt.Sprintf("report-%s-%s.%s", kind, time.Now().Local().Format("2006-01-02-15-04-05"), archiveFormat)
 }
 
 func buildClusterResourceSelector(clusterName string) string {
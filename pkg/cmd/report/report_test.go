@@ -296,6 +296,34 @@ var _ = Describe("report", func() {
 				Expect(v).Should(Equal(EncryptedData))
 			}
 		})
+
+		It("use mask printer for sensitive env", func() {
+			var strBuffer bytes.Buffer
+
+			o := newReportOptions(streams)
+			o.outputFormat = jsonFormat
+			o.mask = true
+			Expect(o.validate()).ShouldNot(HaveOccurred())
+			print, err := o.parsePrinter()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			deploy := testing.FakeKBDeploy("0.5.23")
+			deploy.Spec.Template.Spec.Containers = []corev1.Container{{
+				Name: "test",
+				Env: []corev1.EnvVar{
+					{Name: "DB_PASSWORD", Value: charName},
+					{Name: "DB_HOST", Value: charName},
+				},
+			}}
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deploy)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(print.PrintObj(&unstructured.Unstructured{Object: obj}, &strBuffer)).Should(Succeed())
+			copyDeploy := &appsv1.Deployment{}
+			Expect(json.Unmarshal(strBuffer.Bytes(), copyDeploy)).Should(Succeed())
+			envs := copyDeploy.Spec.Template.Spec.Containers[0].Env
+			Expect(envs[0].Value).Should(Equal(EncryptedData))
+			Expect(envs[1].Value).Should(Equal(charName))
+		})
 	})
 
 	Context("report-kubeblocks options", func() {
@@ -353,6 +381,13 @@ var _ = Describe("report", func() {
 			Expect(o.genericClientSet).ShouldNot(BeNil())
 			Expect(o.namespace).Should(Equal(namespace))
 			Expect(o.file).Should(MatchRegexp("report-kubeblocks-.*.zip"))
+
+			o.file = "report.tgz"
+			Expect(o.complete(tf)).To(Succeed())
+			Expect(o.archiveFormat).Should(Equal(tarGzArchive))
+
+			o.maxFileSize = "invalid"
+			Expect(o.complete(tf)).Should(HaveOccurred())
 		})
 
 		It("complete kb-report manifest", func() {
@@ -20,7 +20,6 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package report
 
 import (
-	"archive/zip"
 	"context"
 	"fmt"
 	"io"
@@ -55,8 +54,16 @@ func NewReportWritter() reportWritter {
 
 type reportZipWritter struct {
 	outputFile *os.File
-	zipper     *zip.Writer
+	zipper     archiveWritter
 	printer    printers.ResourcePrinterFunc
+	// archiveFormat is the format of the archive, zip or tar.gz, default to zip
+	archiveFormat string
+	// out is where the notices of the truncated files are printed
+	out io.Writer
+	// maxFileSize and maxSize limit the size of each file and the whole report, zero means no limit
+	maxFileSize int64
+	maxSize     int64
+	limiter     *sizeLimitedWritter
 }
 
 func (w *reportZipWritter) Init(file string, printer printers.ResourcePrinterFunc) error {
@@ -73,7 +80,12 @@ func (w *reportZipWritter) Init(file string, printer printers.ResourcePrinterFun
 	if w.outputFile, err = util.CreateAndCleanFile(file); err != nil {
 		return fmt.Errorf("could not create zip file: %w", err)
 	}
-	w.zipper = zip.NewWriter(w.outputFile)
+	archive, err := newArchiveWritter(w.archiveFormat, w.outputFile)
+	if err != nil {
+		return err
+	}
+	w.limiter = newSizeLimitedWritter(archive, w.out, w.maxFileSize, w.maxSize)
+	w.zipper = w.limiter
 	w.printer = printer
 	return nil
 }
@@ -84,14 +96,14 @@ func (w *reportZipWritter) Close() error {
 		klog.Warning("zipWritter is not initialized")
 		return nil
 	}
-	// sync file
-	if err = w.outputFile.Sync(); err != nil {
-		return fmt.Errorf("could not sync zip file: %s, error: %w", w.outputFile.Name(), err)
-	}
 	// close zipper
 	if err = w.zipper.Close(); err != nil {
 		return fmt.Errorf("could not close zip file: %s, error: %w", w.outputFile.Name(), err)
 	}
+	// sync file
+	if err = w.outputFile.Sync(); err != nil {
+		return fmt.Errorf("could not sync zip file: %s, error: %w", w.outputFile.Name(), err)
+	}
 	// close file
 	if err = w.outputFile.Close(); err != nil {
 		return fmt.Errorf("could not close zip file: %s, error: %w", w.outputFile.Name(), err)
@@ -210,3 +222,11 @@ func (w *reportZipWritter) WriteLogs(folderName string, ctx context.Context, cli
 	}
 	return nil
 }
+
+// TruncatedFiles returns the files truncated because of the size limits.
+func (w *reportZipWritter) TruncatedFiles() []string {
+	if w.limiter == nil {
+		return nil
+	}
+	return w.limiter.TruncatedFiles()
+}
@@ -20,8 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package report
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
 	"os"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -133,5 +138,36 @@ var _ = Describe("zipwritter", func() {
 			err = zipwritter.Close()
 			Expect(err).Should(Succeed())
 		})
+
+		It("should succeed to write tar.gz with size limits", func() {
+			const tarFileName = "test.tar.gz"
+			defer os.Remove(tarFileName)
+			out := &bytes.Buffer{}
+			writter := &reportZipWritter{archiveFormat: archiveFormatOf(tarFileName), out: out, maxFileSize: 10}
+			err := writter.Init(tarFileName, printer.PrintObj)
+			Expect(err).Should(Succeed())
+
+			deploy := testing.FakeKBDeploy("0.5.23")
+			err = writter.WriteSingleObject("manifests", deploy.Kind, deploy.Name, deploy, "json")
+			Expect(err).Should(Succeed())
+			Expect(writter.TruncatedFiles()).Should(HaveLen(1))
+			Expect(out.String()).Should(ContainSubstring("manifests/Deployment-" + deploy.Name + ".json is truncated"))
+			Expect(writter.Close()).Should(Succeed())
+
+			By("check the files in tarball")
+			f, err := os.Open(tarFileName)
+			Expect(err).Should(Succeed())
+			defer f.Close()
+			gz, err := gzip.NewReader(f)
+			Expect(err).Should(Succeed())
+			tr := tar.NewReader(gz)
+			hdr, err := tr.Next()
+			Expect(err).Should(Succeed())
+			Expect(hdr.Name).Should(Equal("manifests/Deployment-" + deploy.Name + ".json"))
+			content, err := io.ReadAll(tr)
+			Expect(err).Should(Succeed())
+			Expect(strings.HasSuffix(string(content), truncatedMsg)).Should(BeTrue())
+			Expect(len(content)).Should(Equal(10 + len(truncatedMsg)))
+		})
 	})
 })
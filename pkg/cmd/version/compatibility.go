@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	gv "github.com/hashicorp/go-version"
+	"golang.org/x/exp/slices"
+)
+
+// compatibilityMatrix lists the KubeBlocks minor versions compatible with each kbcli minor
+// version, the kbcli version not in the matrix is only compatible with the same minor version.
+var compatibilityMatrix = map[string][]string{
+	"0.6": {"0.6"},
+	"0.7": {"0.6", "0.7"},
+	"0.8": {"0.7", "0.8"},
+}
+
+var (
+	// latestReleaseURL is the GitHub API to get the latest release, could be replaced in tests
+	latestReleaseURL = "https://api.github.com/repos/apecloud/%s/releases/latest"
+	releaseURL       = "https://github.com/apecloud/%s/releases/tag/%s"
+)
+
+func minorVersion(v *gv.Version) string {
+	segments := v.Segments()
+	return fmt.Sprintf("%d.%d", segments[0], segments[1])
+}
+
+// checkCompatibility checks whether the kbcli version is compatible with the KubeBlocks version
+// according to the compatibility matrix, and returns the upgrade suggestion if not.
+func checkCompatibility(cliVersion, kbVersion *gv.Version) (bool, string) {
+	cliMinor, kbMinor := minorVersion(cliVersion), minorVersion(kbVersion)
+	supported, ok := compatibilityMatrix[cliMinor]
+	if !ok {
+		supported = []string{cliMinor}
+	}
+	if slices.Contains(supported, kbMinor) {
+		return true, ""
+	}
+	if kbVersion.LessThan(cliVersion) {
+		return false, fmt.Sprintf("KubeBlocks %s is not supported by kbcli %s, upgrade KubeBlocks by `kbcli kubeblocks upgrade --version %s`",
+			kbVersion.Original(), cliVersion.Original(), cliVersion.String())
+	}
+	return false, fmt.Sprintf("kbcli %s does not support KubeBlocks %s, install kbcli %s from %s",
+		cliVersion.Original(), kbVersion.Original(), kbVersion.String(), fmt.Sprintf(releaseURL, "kbcli", "v"+kbVersion.String()))
+}
+
+// getLatestRelease gets the latest release version of the repo from GitHub
+func getLatestRelease(repo string) (*gv.Version, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(fmt.Sprintf(latestReleaseURL, repo))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the latest release of %s: %s", repo, resp.Status)
+	}
+	release := struct {
+		TagName string `json:"tag_name"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, err
+	}
+	return gv.NewVersion(release.TagName)
+}
@@ -20,71 +20,202 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package version
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"runtime"
 
 	gv "github.com/hashicorp/go-version"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 
+	"github.com/apecloud/kbcli/pkg/printer"
 	"github.com/apecloud/kbcli/pkg/util"
 	"github.com/apecloud/kbcli/version"
 )
 
+var versionExample = templates.Examples(`
+	# print the version of kubernetes, KubeBlocks and kbcli
+	kbcli version
+
+	# print the version in JSON format
+	kbcli version -o json
+
+	# check whether there is a newer release of kbcli and KubeBlocks
+	kbcli version --check-update`)
+
 type versionOptions struct {
-	verbose bool
+	verbose     bool
+	checkUpdate bool
+	format      printer.Format
+	out         io.Writer
+}
+
+// versionInfo is the version information printed in JSON or YAML format
+type versionInfo struct {
+	Kubernetes string     `json:"kubernetes,omitempty"`
+	KubeBlocks string     `json:"kubeblocks,omitempty"`
+	Cli        string     `json:"kbcli"`
+	Build      *buildInfo `json:"build,omitempty"`
+	// Compatible is nil if the KubeBlocks is not installed or the versions can't be parsed
+	Compatible *bool  `json:"compatible,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	// LatestKubeBlocks and LatestCli are set if --check-update is specified
+	LatestKubeBlocks string   `json:"latestKubeblocks,omitempty"`
+	LatestCli        string   `json:"latestKbcli,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+type buildInfo struct {
+	BuildDate string `json:"buildDate"`
+	GitCommit string `json:"gitCommit"`
+	GitTag    string `json:"gitTag"`
+	GoVersion string `json:"goVersion"`
+	Compiler  string `json:"compiler"`
+	Platform  string `json:"platform"`
 }
 
 // NewVersionCmd the version command
 func NewVersionCmd(f cmdutil.Factory) *cobra.Command {
-	o := &versionOptions{}
+	o := &versionOptions{out: os.Stdout}
 	cmd := &cobra.Command{
-		Use:   "version",
-		Short: "Print the version information, include kubernetes, KubeBlocks and kbcli version.",
+		Use:     "version",
+		Short:   "Print the version information, include kubernetes, KubeBlocks and kbcli version.",
+		Example: versionExample,
 		Run: func(cmd *cobra.Command, args []string) {
-			o.Run(f)
+			util.CheckErr(o.Run(f))
 		},
 	}
 	cmd.Flags().BoolVar(&o.verbose, "verbose", false, "print detailed kbcli information")
+	cmd.Flags().BoolVar(&o.checkUpdate, "check-update", false, "check the latest release of kbcli and KubeBlocks from GitHub and suggest an upgrade path")
+	printer.AddOutputFlag(cmd, &o.format)
 	return cmd
 }
 
-func (o *versionOptions) Run(f cmdutil.Factory) {
+func (o *versionOptions) Run(f cmdutil.Factory) error {
+	if o.out == nil {
+		o.out = os.Stdout
+	}
 	client, err := f.KubernetesClientSet()
 	if err != nil {
 		klog.V(1).Infof("failed to get clientset: %v", err)
 	}
 
 	v, _ := util.GetVersionInfo(client)
-	if v.Kubernetes != "" {
-		fmt.Printf("Kubernetes: %s\n", v.Kubernetes)
+	info := o.buildVersionInfo(v)
+
+	switch o.format {
+	case printer.JSON:
+		b, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.out, string(b))
+	case printer.YAML:
+		b, err := yaml.Marshal(info)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.out, string(b))
+	default:
+		o.printVersionInfo(info)
 	}
-	if v.KubeBlocks != "" {
-		fmt.Printf("KubeBlocks: %s\n", v.KubeBlocks)
+	return nil
+}
+
+func (o *versionOptions) buildVersionInfo(v util.Version) *versionInfo {
+	info := &versionInfo{
+		Kubernetes: v.Kubernetes,
+		KubeBlocks: v.KubeBlocks,
+		Cli:        v.Cli,
 	}
-	fmt.Printf("kbcli: %s\n", v.Cli)
 	if o.verbose {
-		fmt.Printf("  BuildDate: %s\n", version.BuildDate)
-		fmt.Printf("  GitCommit: %s\n", version.GitCommit)
-		fmt.Printf("  GitTag: %s\n", version.GitVersion)
-		fmt.Printf("  GoVersion: %s\n", runtime.Version())
-		fmt.Printf("  Compiler: %s\n", runtime.Compiler)
-		fmt.Printf("  Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+		info.Build = &buildInfo{
+			BuildDate: version.BuildDate,
+			GitCommit: version.GitCommit,
+			GitTag:    version.GitVersion,
+			GoVersion: runtime.Version(),
+			Compiler:  runtime.Compiler,
+			Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		}
 	}
 
+	cliVersion, err := gv.NewVersion(v.Cli)
+	if err != nil {
+		klog.V(1).Infof("failed to parse kbcli version: %v", err)
+	}
 	kbVersion, err := gv.NewVersion(v.KubeBlocks)
 	if err != nil {
 		klog.V(1).Infof("failed to parse KubeBlocks version: %v", err)
-		return
 	}
-	cliVersion, err := gv.NewVersion(v.Cli)
-	if err != nil {
-		klog.V(1).Infof("failed to parse kbcli version: %v", err)
+
+	if cliVersion != nil && kbVersion != nil {
+		compatible, suggestion := checkCompatibility(cliVersion, kbVersion)
+		info.Compatible = &compatible
+		info.Suggestion = suggestion
+		if compatible && !kbVersion.Equal(cliVersion) {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("version difference between kbcli (%s) and kubeblocks (%s)", v.Cli, v.KubeBlocks))
+		}
+	}
+
+	if o.checkUpdate {
+		o.checkLatestRelease(info, cliVersion, kbVersion)
+	}
+	return info
+}
+
+// checkLatestRelease gets the latest releases from GitHub, and suggests to upgrade if the
+// current version is older than the latest release.
+func (o *versionOptions) checkLatestRelease(info *versionInfo, cliVersion, kbVersion *gv.Version) {
+	if latest, err := getLatestRelease("kbcli"); err != nil {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("failed to check the latest kbcli release: %v", err))
+	} else {
+		info.LatestCli = latest.Original()
+		if cliVersion != nil && cliVersion.LessThan(latest) {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("kbcli %s is available, download it from %s",
+				latest.Original(), fmt.Sprintf(releaseURL, "kbcli", latest.Original())))
+		}
+	}
+
+	if kbVersion == nil {
 		return
 	}
+	if latest, err := getLatestRelease("kubeblocks"); err != nil {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("failed to check the latest KubeBlocks release: %v", err))
+	} else {
+		info.LatestKubeBlocks = latest.Original()
+		if kbVersion.LessThan(latest) {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("KubeBlocks %s is available, upgrade by `kbcli kubeblocks upgrade --version %s`",
+				latest.Original(), latest.String()))
+		}
+	}
+}
 
-	if !kbVersion.Equal(cliVersion) {
-		fmt.Printf("WARNING: version difference between kbcli (%s) and kubeblocks (%s) \n", v.Cli, v.KubeBlocks)
+func (o *versionOptions) printVersionInfo(info *versionInfo) {
+	if info.Kubernetes != "" {
+		fmt.Fprintf(o.out, "Kubernetes: %s\n", info.Kubernetes)
+	}
+	if info.KubeBlocks != "" {
+		fmt.Fprintf(o.out, "KubeBlocks: %s\n", info.KubeBlocks)
+	}
+	fmt.Fprintf(o.out, "kbcli: %s\n", info.Cli)
+	if info.Build != nil {
+		fmt.Fprintf(o.out, "  BuildDate: %s\n", info.Build.BuildDate)
+		fmt.Fprintf(o.out, "  GitCommit: %s\n", info.Build.GitCommit)
+		fmt.Fprintf(o.out, "  GitTag: %s\n", info.Build.GitTag)
+		fmt.Fprintf(o.out, "  GoVersion: %s\n", info.Build.GoVersion)
+		fmt.Fprintf(o.out, "  Compiler: %s\n", info.Build.Compiler)
+		fmt.Fprintf(o.out, "  Platform: %s\n", info.Build.Platform)
+	}
+	if info.Compatible != nil && !*info.Compatible {
+		fmt.Fprintf(o.out, "WARNING: kbcli (%s) is incompatible with kubeblocks (%s)\n", info.Cli, info.KubeBlocks)
+		fmt.Fprintf(o.out, "  %s\n", info.Suggestion)
+	}
+	for _, w := range info.Warnings {
+		fmt.Fprintf(o.out, "WARNING: %s\n", w)
 	}
 }
@@ -20,12 +20,21 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package version
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	gv "github.com/hashicorp/go-version"
 	"k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var _ = Describe("version", func() {
@@ -38,7 +47,64 @@ var _ = Describe("version", func() {
 
 		By("testing run")
 		o := &versionOptions{}
-		o.Run(tf)
+		Expect(o.Run(tf)).Should(Succeed())
+	})
+
+	It("version in json format", func() {
+		tf := cmdtesting.NewTestFactory()
+		tf.Client = &fake.RESTClient{}
+		out := &bytes.Buffer{}
+		o := &versionOptions{format: printer.JSON, verbose: true, out: out}
+		Expect(o.Run(tf)).Should(Succeed())
+		info := &versionInfo{}
+		Expect(json.Unmarshal(out.Bytes(), info)).Should(Succeed())
+		Expect(info.Cli).ShouldNot(BeEmpty())
+		Expect(info.Build).ShouldNot(BeNil())
+	})
+
+	It("check compatibility", func() {
+		check := func(cli, kb string) (bool, string) {
+			cliVersion, err := gv.NewVersion(cli)
+			Expect(err).Should(Succeed())
+			kbVersion, err := gv.NewVersion(kb)
+			Expect(err).Should(Succeed())
+			return checkCompatibility(cliVersion, kbVersion)
+		}
+		compatible, _ := check("0.7.1", "0.7.0")
+		Expect(compatible).Should(BeTrue())
+		compatible, _ = check("0.8.0-beta.1", "0.7.2")
+		Expect(compatible).Should(BeTrue())
+
+		compatible, suggestion := check("0.8.0", "0.6.3")
+		Expect(compatible).Should(BeFalse())
+		Expect(suggestion).Should(ContainSubstring("kbcli kubeblocks upgrade --version 0.8.0"))
+
+		compatible, suggestion = check("0.7.0", "0.8.1")
+		Expect(compatible).Should(BeFalse())
+		Expect(suggestion).Should(ContainSubstring("install kbcli 0.8.1"))
+
+		By("the version not in the matrix is only compatible with the same minor version")
+		compatible, _ = check("0.9.0", "0.9.1")
+		Expect(compatible).Should(BeTrue())
+		compatible, _ = check("0.9.0", "0.8.1")
+		Expect(compatible).Should(BeFalse())
+	})
+
+	It("check update", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"tag_name": "v99.0.0"}`)
+		}))
+		defer server.Close()
+		defaultURL := latestReleaseURL
+		latestReleaseURL = server.URL + "/%s"
+		defer func() { latestReleaseURL = defaultURL }()
+
+		o := &versionOptions{checkUpdate: true}
+		info := o.buildVersionInfo(util.Version{Cli: "0.7.0"})
+		Expect(info.LatestCli).Should(Equal("v99.0.0"))
+		Expect(info.LatestKubeBlocks).Should(BeEmpty())
+		Expect(info.Warnings).Should(HaveLen(1))
+		Expect(info.Warnings[0]).Should(ContainSubstring("kbcli v99.0.0 is available"))
 	})
 
 	It("version comparison", func() {
@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Reason is the category of an error, scripts can branch on the exit code mapped from it.
+type Reason string
+
+const (
+	ReasonNotFound   Reason = "NotFound"
+	ReasonValidation Reason = "Validation"
+	ReasonConflict   Reason = "Conflict"
+	ReasonForbidden  Reason = "Forbidden"
+	ReasonTimeout    Reason = "Timeout"
+)
+
+// The exit codes of kbcli, scripts can rely on them across all commands:
+//
+//	0 the command succeeded
+//	1 the command failed with an error of no other category, as kubectl does
+//	2 the input is invalid, such as an invalid flag, argument or spec
+//	3 the resource is not found
+//	4 the operation conflicts with the current state of the resource
+//	5 the user is not authenticated or not allowed to perform the operation
+//	6 the command timed out, such as waiting for the resource to be ready
+const (
+	ExitCodeDefault    = 1
+	ExitCodeValidation = 2
+	ExitCodeNotFound   = 3
+	ExitCodeConflict   = 4
+	ExitCodeForbidden  = 5
+	ExitCodeTimeout    = 6
+)
+
+// Error is an error with a reason
+type Error struct {
+	Reason  Reason
+	Message string
+	// Err is the underlying error, it can be nil
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewNotFound returns an error indicating the resource of the kind is not found.
+func NewNotFound(kind, name string) error {
+	return &Error{Reason: ReasonNotFound, Message: fmt.Sprintf("%s %q not found", kind, name)}
+}
+
+// NewNotFoundf returns a not found error with the formatted message.
+func NewNotFoundf(format string, a ...interface{}) error {
+	return &Error{Reason: ReasonNotFound, Message: fmt.Sprintf(format, a...)}
+}
+
+// NewValidation returns an error indicating the input of the user is invalid.
+func NewValidation(format string, a ...interface{}) error {
+	return &Error{Reason: ReasonValidation, Message: fmt.Sprintf(format, a...)}
+}
+
+// NewConflict returns an error indicating the operation conflicts with the current state,
+// such as the resource already exists or is being processed by another operation.
+func NewConflict(format string, a ...interface{}) error {
+	return &Error{Reason: ReasonConflict, Message: fmt.Sprintf(format, a...)}
+}
+
+// NewTimeout returns an error indicating the operation is not finished in time.
+func NewTimeout(format string, a ...interface{}) error {
+	return &Error{Reason: ReasonTimeout, Message: fmt.Sprintf(format, a...)}
+}
+
+// Wrap wraps the error with the reason and message, it returns nil if err is nil.
+func Wrap(reason Reason, err error, format string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Reason: reason, Message: fmt.Sprintf(format, a...), Err: err}
+}
+
+// ReasonForError returns the reason of the error, the API errors returned by the
+// server are mapped to the reasons too. An empty reason is returned for other errors.
+func ReasonForError(err error) Reason {
+	var e *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &e):
+		return e.Reason
+	case apierrors.IsNotFound(err):
+		return ReasonNotFound
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ReasonValidation
+	case apierrors.IsAlreadyExists(err), apierrors.IsConflict(err):
+		return ReasonConflict
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return ReasonForbidden
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), wait.Interrupted(err),
+		errors.Is(err, context.DeadlineExceeded):
+		return ReasonTimeout
+	}
+	return ""
+}
+
+// IsNotFound returns true if the error is a not found error.
+func IsNotFound(err error) bool {
+	return ReasonForError(err) == ReasonNotFound
+}
+
+// IsValidation returns true if the error is a validation error.
+func IsValidation(err error) bool {
+	return ReasonForError(err) == ReasonValidation
+}
+
+// IsConflict returns true if the error is a conflict error.
+func IsConflict(err error) bool {
+	return ReasonForError(err) == ReasonConflict
+}
+
+// IsTimeout returns true if the error is a timeout error.
+func IsTimeout(err error) bool {
+	return ReasonForError(err) == ReasonTimeout
+}
+
+// ExitCode returns the exit code kbcli exits with for the error.
+func ExitCode(err error) int {
+	switch ReasonForError(err) {
+	case ReasonValidation:
+		return ExitCodeValidation
+	case ReasonNotFound:
+		return ExitCodeNotFound
+	case ReasonConflict:
+		return ExitCodeConflict
+	case ReasonForbidden:
+		return ExitCodeForbidden
+	case ReasonTimeout:
+		return ExitCodeTimeout
+	}
+	return ExitCodeDefault
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package errors
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var _ = Describe("errors", func() {
+	It("typed errors", func() {
+		err := NewNotFound("cluster", "mycluster")
+		Expect(err.Error()).Should(Equal(`cluster "mycluster" not found`))
+		Expect(IsNotFound(err)).Should(BeTrue())
+		Expect(ExitCode(err)).Should(Equal(ExitCodeNotFound))
+
+		err = NewValidation("invalid value %s", "foo")
+		Expect(err.Error()).Should(Equal("invalid value foo"))
+		Expect(IsValidation(err)).Should(BeTrue())
+		Expect(ExitCode(err)).Should(Equal(ExitCodeValidation))
+
+		err = NewConflict("backup %s is running", "b1")
+		Expect(IsConflict(err)).Should(BeTrue())
+		Expect(ExitCode(err)).Should(Equal(ExitCodeConflict))
+
+		err = NewTimeout("backup %s is not completed in %s", "b1", "10m")
+		Expect(IsTimeout(err)).Should(BeTrue())
+		Expect(ExitCode(err)).Should(Equal(ExitCodeTimeout))
+		Expect(ExitCode(wait.ErrWaitTimeout)).Should(Equal(ExitCodeTimeout))
+		Expect(ExitCode(fmt.Errorf("failed to wait: %w", context.DeadlineExceeded))).Should(Equal(ExitCodeTimeout))
+	})
+
+	It("wrapped errors", func() {
+		err := fmt.Errorf("failed to create backup: %w", NewValidation("missing cluster name"))
+		Expect(IsValidation(err)).Should(BeTrue())
+		Expect(ExitCode(err)).Should(Equal(ExitCodeValidation))
+
+		inner := fmt.Errorf("connection refused")
+		err = Wrap(ReasonConflict, inner, "failed to update %s", "policy")
+		Expect(err.Error()).Should(Equal("failed to update policy: connection refused"))
+		Expect(err).Should(MatchError(inner))
+		Expect(Wrap(ReasonConflict, nil, "ignored")).Should(BeNil())
+	})
+
+	It("API errors", func() {
+		gr := schema.GroupResource{Group: "apps.kubeblocks.io", Resource: "clusters"}
+		Expect(ExitCode(apierrors.NewNotFound(gr, "mycluster"))).Should(Equal(ExitCodeNotFound))
+		Expect(ExitCode(apierrors.NewInvalid(schema.GroupKind{Group: gr.Group, Kind: "Cluster"}, "mycluster",
+			field.ErrorList{field.Required(field.NewPath("spec", "componentSpecs"), "")}))).Should(Equal(ExitCodeValidation))
+		Expect(ExitCode(apierrors.NewBadRequest("invalid replicas"))).Should(Equal(ExitCodeValidation))
+		Expect(ExitCode(apierrors.NewAlreadyExists(gr, "mycluster"))).Should(Equal(ExitCodeConflict))
+		Expect(ExitCode(apierrors.NewConflict(gr, "mycluster", fmt.Errorf("modified")))).Should(Equal(ExitCodeConflict))
+		Expect(ExitCode(apierrors.NewForbidden(gr, "mycluster", fmt.Errorf("denied")))).Should(Equal(ExitCodeForbidden))
+		Expect(ExitCode(apierrors.NewUnauthorized("unauthorized"))).Should(Equal(ExitCodeForbidden))
+		Expect(ExitCode(apierrors.NewTimeoutError("timeout", 1))).Should(Equal(ExitCodeTimeout))
+		Expect(ExitCode(fmt.Errorf("unknown"))).Should(Equal(ExitCodeDefault))
+		Expect(ReasonForError(nil)).Should(BeEmpty())
+	})
+})
@@ -30,6 +30,8 @@ import (
 	"github.com/replicatedhq/troubleshoot/cmd/util"
 	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
 	"github.com/replicatedhq/troubleshoot/pkg/convert"
+
+	"github.com/apecloud/kbcli/pkg/printer"
 )
 
 var (
@@ -161,9 +163,9 @@ func drawPreflightTable(analyzeResults []*analyzerunner.AnalyzeResult) {
 		}
 		switch {
 		case analyzeResult.IsPass:
-			title = fmt.Sprintf("✔  %s", title)
+			title = fmt.Sprintf("%s  %s", printer.SuccessSymbol(), title)
 		case analyzeResult.IsWarn:
-			title = fmt.Sprintf("⚠️  %s", title)
+			title = fmt.Sprintf("%s  %s", printer.WarningSymbol(), title)
 		case analyzeResult.IsFail:
 			title = fmt.Sprintf("✘  %s", title)
 		}
@@ -20,9 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package preflight
 
 import (
+	"bufio"
+	"bytes"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
 
 	preflightv1beta2 "github.com/apecloud/kubeblocks/externalapis/preflight/v1beta2"
@@ -47,7 +54,16 @@ func LoadPreflightSpec(checkFileList []string, checkYamlData [][]byte) (*preflig
 		}
 		checkYamlData = append(checkYamlData, preflightContent)
 	}
+	var docs [][]byte
 	for _, yamlData := range checkYamlData {
+		// a check bundle may contain multiple specs separated by "---"
+		data, err := splitYAMLDocuments(yamlData)
+		if err != nil {
+			return preflightSpec, hostPreflightSpec, preflightName, err
+		}
+		docs = append(docs, data...)
+	}
+	for _, yamlData := range docs {
 		obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(yamlData, nil, nil)
 		if err != nil {
 			return preflightSpec, hostPreflightSpec, preflightName, errors.Wrapf(err, "failed to parse %s", string(yamlData))
@@ -63,6 +79,56 @@ func LoadPreflightSpec(checkFileList []string, checkYamlData [][]byte) (*preflig
 	return preflightSpec, hostPreflightSpec, preflightName, nil
 }
 
+// ExpandCheckFiles expands the directories in the check files to the yaml files in them, so
+// that a directory of check specs can be used as a check bundle.
+func ExpandCheckFiles(checkFileList []string) ([]string, error) {
+	var files []string
+	for _, fileName := range checkFileList {
+		info, err := os.Stat(fileName)
+		if err != nil || !info.IsDir() {
+			// the stdin, URI and the file which will be checked when loading
+			files = append(files, fileName)
+			continue
+		}
+		entries, err := os.ReadDir(fileName)
+		if err != nil {
+			return nil, err
+		}
+		var dirFiles []string
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			dirFiles = append(dirFiles, filepath.Join(fileName, entry.Name()))
+		}
+		if len(dirFiles) == 0 {
+			return nil, errors.Errorf("no check spec found in directory %s", fileName)
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+func splitYAMLDocuments(data []byte) ([][]byte, error) {
+	var docs [][]byte
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the check spec")
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+}
+
 func init() {
 	// register the scheme of troubleshoot API and decode function
 	if err := util.AddToScheme(scheme.Scheme); err != nil {
@@ -20,6 +20,9 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package preflight
 
 import (
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -35,4 +38,30 @@ var _ = Describe("load_spec_test", func() {
 			g.Expect(preflightName).NotTo(BeNil())
 		}).Should(Succeed())
 	})
+
+	It("load the check bundle of a directory with multiple specs in a file", func() {
+		dir, err := os.MkdirTemp("", "check-bundle")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		preflightData, err := os.ReadFile("../testing/testdata/preflight.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		hostPreflightData, err := os.ReadFile("../testing/testdata/hostpreflight.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		bundle := append(append(preflightData, []byte("\n---\n")...), hostPreflightData...)
+		Expect(os.WriteFile(filepath.Join(dir, "bundle.yaml"), bundle, 0644)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644)).Should(Succeed())
+
+		files, err := ExpandCheckFiles([]string{dir})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).Should(Equal([]string{filepath.Join(dir, "bundle.yaml")}))
+		preflightSpec, hostPreflightSpec, _, err := LoadPreflightSpec(files, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preflightSpec.Spec.Analyzers).Should(HaveLen(1))
+		Expect(hostPreflightSpec.Spec.Analyzers).Should(HaveLen(1))
+
+		By("the directory without check spec")
+		Expect(os.Remove(filepath.Join(dir, "bundle.yaml"))).Should(Succeed())
+		_, err = ExpandCheckFiles([]string{dir})
+		Expect(err).Should(HaveOccurred())
+	})
 })
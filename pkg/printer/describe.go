@@ -102,10 +102,11 @@ func PrintComponentConfigMeta(tplInfos []types.ConfigTemplateInfo, clusterName,
 	tbl.Print()
 }
 
-// PrintHelmValues prints the helm values file of the release in specified format, supports JSON、YAML and Table
+// PrintHelmValues prints the helm values file of the release in specified format, supports JSON、YAML and the tabular formats
 func PrintHelmValues(configs map[string]interface{}, format Format, out io.Writer) {
 	inTable := func() {
 		p := NewTablePrinter(out)
+		p.SetFormat(format)
 		p.SetHeader("KEY", "VALUE")
 		p.SortBy(1)
 		for key, value := range configs {
@@ -113,7 +114,7 @@ func PrintHelmValues(configs map[string]interface{}, format Format, out io.Write
 		}
 		p.Print()
 	}
-	if format.IsHumanReadable() {
+	if format.IsTabular() {
 		inTable()
 		return
 	}
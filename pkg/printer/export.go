@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package printer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// renderCSV renders the table in CSV format, the values are escaped as RFC 4180 and the
+// colors are stripped, so the result can be imported by spreadsheets.
+func (t *TablePrinter) renderCSV(rows []table.Row) {
+	visible := func(row []interface{}) []string {
+		var record []string
+		for i, v := range row {
+			if i < len(t.hidden) && t.hidden[i] {
+				continue
+			}
+			if v == nil {
+				v = ""
+			}
+			record = append(record, text.StripEscape(fmt.Sprint(v)))
+		}
+		return record
+	}
+
+	w := csv.NewWriter(t.out)
+	if !t.noHeaders && len(t.header) > 0 {
+		_ = w.Write(visible(t.header))
+	}
+	for _, row := range rows {
+		_ = w.Write(visible(row))
+	}
+	w.Flush()
+}
+
+// ResourcePrinter prints the Kubernetes objects in CSV or Markdown format. The tables
+// returned by the server are printed with their columns, the other objects are printed
+// with their names only.
+type ResourcePrinter struct {
+	Format        Format
+	Wide          bool
+	WithNamespace bool
+	NoHeaders     bool
+
+	headerPrinted bool
+}
+
+// PrintObj prints the object, the CSV header is printed only once if the printer is used to
+// print the objects returned by multiple requests.
+func (p *ResourcePrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	tbl := NewTablePrinter(w)
+	tbl.SetFormat(p.Format)
+
+	var header []interface{}
+	if p.WithNamespace {
+		header = append(header, "NAMESPACE")
+	}
+
+	switch t := obj.(type) {
+	case *metav1.Table:
+		var columns []int
+		for i, c := range t.ColumnDefinitions {
+			if c.Priority == 0 || p.Wide {
+				columns = append(columns, i)
+				header = append(header, strings.ToUpper(c.Name))
+			}
+		}
+		for _, r := range t.Rows {
+			var row []interface{}
+			if p.WithNamespace {
+				row = append(row, namespaceOf(r.Object.Object))
+			}
+			for _, i := range columns {
+				if i >= len(r.Cells) {
+					row = append(row, "")
+					continue
+				}
+				row = append(row, formatCell(t.ColumnDefinitions[i], r.Cells[i]))
+			}
+			tbl.AddRow(row...)
+		}
+	default:
+		header = append(header, "NAME")
+		var objs []runtime.Object
+		if meta.IsListType(obj) {
+			var err error
+			if objs, err = meta.ExtractList(obj); err != nil {
+				return err
+			}
+		} else {
+			objs = []runtime.Object{obj}
+		}
+		for _, o := range objs {
+			accessor, err := meta.Accessor(o)
+			if err != nil {
+				return err
+			}
+			var row []interface{}
+			if p.WithNamespace {
+				row = append(row, accessor.GetNamespace())
+			}
+			tbl.AddRow(append(row, accessor.GetName())...)
+		}
+	}
+
+	// a Markdown table is invalid without header, so the header is repeated for each chunk
+	// returned by the server, the single objects are appended to the current table
+	if !p.NoHeaders && (!p.headerPrinted || p.Format == Markdown && isChunk(obj)) {
+		if p.headerPrinted {
+			fmt.Fprintln(w)
+		}
+		tbl.SetHeader(header...)
+		p.headerPrinted = true
+	}
+	tbl.Print()
+	return nil
+}
+
+// isChunk returns true if the object is a table or a list returned by a request
+func isChunk(obj runtime.Object) bool {
+	if _, ok := obj.(*metav1.Table); ok {
+		return true
+	}
+	return meta.IsListType(obj)
+}
+
+func namespaceOf(obj runtime.Object) string {
+	if obj == nil {
+		return ""
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetNamespace()
+}
+
+// formatCell formats the cell as kubectl does, the date is printed as the age
+func formatCell(column metav1.TableColumnDefinition, cell interface{}) interface{} {
+	if cell == nil {
+		return "<none>"
+	}
+	if s, ok := cell.(string); ok && column.Type == "string" && column.Format == "date" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return duration.HumanDuration(time.Since(t))
+	}
+	return cell
+}
@@ -27,30 +27,36 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/cmd/util"
+
+	kbutil "github.com/apecloud/kbcli/pkg/util"
 )
 
 // Format is a type for capturing supported output formats
 type Format string
 
 const (
-	Table Format = "table"
-	JSON  Format = "json"
-	YAML  Format = "yaml"
-	Wide  Format = "wide"
+	Table    Format = "table"
+	JSON     Format = "json"
+	YAML     Format = "yaml"
+	Wide     Format = "wide"
+	CSV      Format = "csv"
+	Markdown Format = "markdown"
 )
 
 var ErrInvalidFormatType = fmt.Errorf("invalid format type")
 
 func Formats() []string {
-	return []string{Table.String(), JSON.String(), YAML.String(), Wide.String()}
+	return []string{Table.String(), JSON.String(), YAML.String(), Wide.String(), CSV.String(), Markdown.String()}
 }
 
 func FormatsWithDesc() map[string]string {
 	return map[string]string{
-		Table.String(): "Output result in human-readable format",
-		JSON.String():  "Output result in JSON format",
-		YAML.String():  "Output result in YAML format",
-		Wide.String():  "Output result in human-readable format with more information",
+		Table.String():    "Output result in human-readable format",
+		JSON.String():     "Output result in JSON format",
+		YAML.String():     "Output result in YAML format",
+		Wide.String():     "Output result in human-readable format with more information",
+		CSV.String():      "Output result in CSV format",
+		Markdown.String(): "Output result in Markdown table format",
 	}
 }
 
@@ -62,6 +68,12 @@ func (f Format) IsHumanReadable() bool {
 	return f == Table || f == Wide
 }
 
+// IsTabular returns true if the result is rendered as a table, which is human-readable or
+// exported as CSV or Markdown.
+func (f Format) IsTabular() bool {
+	return f.IsHumanReadable() || f == CSV || f == Markdown
+}
+
 func ParseFormat(s string) (out Format, err error) {
 	switch s {
 	case Table.String():
@@ -72,6 +84,10 @@ func ParseFormat(s string) (out Format, err error) {
 		out, err = YAML, nil
 	case Wide.String():
 		out, err = Wide, nil
+	case CSV.String():
+		out, err = CSV, nil
+	case Markdown.String():
+		out, err = Markdown, nil
 	default:
 		out, err = "", ErrInvalidFormatType
 	}
@@ -98,7 +114,25 @@ func AddOutputFlagForCreate(cmd *cobra.Command, varRef *Format, persistent bool)
 	if persistent {
 		fs = cmd.PersistentFlags()
 	}
-	fs.VarP(newOutputValue(YAML, varRef), "output", "o", "Prints the output in the specified format. Allowed values: JSON and YAML")
+	fs.VarP(&createOutputValue{newOutputValue(YAML, varRef)}, "output", "o", "Prints the output in the specified format. Allowed values: JSON and YAML")
+}
+
+// createOutputValue is the output format of the create commands, the created object is
+// printed in JSON or YAML, the table formats are not supported.
+type createOutputValue struct {
+	*outputValue
+}
+
+func (o *createOutputValue) Set(s string) error {
+	outfmt, err := ParseFormat(s)
+	if err != nil {
+		return err
+	}
+	if outfmt != JSON && outfmt != YAML {
+		return fmt.Errorf("unsupported output format %s, allowed values: %s, %s", s, JSON, YAML)
+	}
+	*o.outputValue = outputValue(outfmt)
+	return nil
 }
 
 type outputValue Format
@@ -127,6 +161,7 @@ func (o *outputValue) Set(s string) error {
 
 // FatalWithRedColor when an error occurs, sets the red color to print it.
 func FatalWithRedColor(msg string, code int) {
+	kbutil.RunFatalHandlers(msg, code)
 	if klog.V(99).Enabled() {
 		klog.FatalDepth(2, msg)
 	}
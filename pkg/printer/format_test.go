@@ -55,3 +55,21 @@ func TestFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateFormat(t *testing.T) {
+	var format Format
+	cmd := &cobra.Command{}
+	AddOutputFlagForCreate(cmd, &format, false)
+	v := cmd.Flags().Lookup("output").Value
+	if v.String() != YAML.String() {
+		t.Errorf("expect yaml format")
+	}
+	if err := v.Set("json"); err != nil || format != JSON {
+		t.Errorf("failed to set json format")
+	}
+	for _, f := range []string{"csv", "markdown", "table"} {
+		if err := v.Set(f); err == nil {
+			t.Errorf("expect %s format is not supported", f)
+		}
+	}
+}
@@ -26,6 +26,20 @@ import (
 	"github.com/fatih/color"
 )
 
+// quiet suppresses the decorative outputs
+var quiet bool
+
+// SetQuiet suppresses the decorative outputs such as the notes, hints and spinners if quiet is
+// true, while the structured results such as the tables and the created resources are still printed.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// Quiet returns true if the decorative outputs are suppressed
+func Quiet() bool {
+	return quiet
+}
+
 // BoldYellow returns a string formatted with yellow and bold.
 func BoldYellow(msg interface{}) string {
 	return color.New(color.FgYellow).Add(color.Bold).Sprint(msg)
@@ -23,10 +23,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
 )
 
+// DefaultMaxColumnWidth is the max width of a table column, the longer values are truncated
+// unless the full values are required.
+const DefaultMaxColumnWidth = 64
+
 var (
 	// KubeCtlStyle renders a Table like kubectl
 	KubeCtlStyle table.Style
@@ -53,7 +60,47 @@ var (
 )
 
 type TablePrinter struct {
-	Tbl table.Writer
+	Tbl    table.Writer
+	out    io.Writer
+	header []interface{}
+	// format is the format to render the table, CSV and Markdown are exported as is,
+	// all the others are rendered in the table style
+	format Format
+
+	// the following fields are kept to sort the rows and export the table as CSV
+	rows      []table.Row
+	hidden    []bool
+	noHeaders bool
+	sortBy    []sortColumn
+}
+
+// TableOptions customizes the output of the table printers
+type TableOptions struct {
+	// NoHeaders does not print the table header
+	NoHeaders bool
+	// Columns are the names of the columns to print, all columns are printed if it is empty
+	Columns []string
+	// MaxWidth is the max width of the columns, zero means no limit
+	MaxWidth int
+	// Full prints the full values of the columns even if they exceed MaxWidth
+	Full bool
+	// SortBy are the columns to sort the rows by, a column can be suffixed with :desc to
+	// sort in descending order, it takes precedence over the default order of the command
+	SortBy []string
+}
+
+// AddFlags adds the flags to customize the table output, the columns are truncated to
+// DefaultMaxColumnWidth unless --full is specified.
+func (o *TableOptions) AddFlags(cmd *cobra.Command) {
+	o.MaxWidth = DefaultMaxColumnWidth
+	cmd.Flags().BoolVar(&o.NoHeaders, "no-headers", false, "When using the table or wide output format, don't print headers")
+	cmd.Flags().StringSliceVar(&o.Columns, "columns", nil, "Comma separated names of the columns to print, such as NAME,STATUS. All columns are printed if not specified")
+	cmd.Flags().BoolVar(&o.Full, "full", false, fmt.Sprintf("Print the full values of the columns, the values longer than %d characters are truncated by default", DefaultMaxColumnWidth))
+}
+
+// AddSortFlag adds the --sort-by flag to sort the table rows by the columns
+func (o *TableOptions) AddSortFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.SortBy, "sort-by", nil, "Comma separated names of the columns to sort the rows by, append :desc to a column to sort in descending order, such as STATUS,CREATED-TIME:desc")
 }
 
 func init() {
@@ -71,6 +118,13 @@ func init() {
 	}
 }
 
+// WithFormat returns the custom settings of PrintTable to render the table in the format
+func WithFormat(format Format) func(*TablePrinter) {
+	return func(t *TablePrinter) {
+		t.SetFormat(format)
+	}
+}
+
 // PrintTable high level wrapper function.
 func PrintTable(out io.Writer, customSettings func(*TablePrinter), rowFeeder func(*TablePrinter) error, header ...interface{}) error {
 	t := NewTablePrinter(out)
@@ -87,11 +141,34 @@ func PrintTable(out io.Writer, customSettings func(*TablePrinter), rowFeeder fun
 	return nil
 }
 
+// PrintTableWithOptions is like PrintTable, but renders the table in the format and customizes
+// it by the table options.
+func PrintTableWithOptions(out io.Writer, format Format, opts *TableOptions, rowFeeder func(*TablePrinter) error, header ...interface{}) error {
+	t := NewTablePrinter(out)
+	t.SetFormat(format)
+	t.SetHeader(header...)
+	if err := t.ApplyOptions(opts); err != nil {
+		return err
+	}
+	if rowFeeder != nil {
+		if err := rowFeeder(t); err != nil {
+			return err
+		}
+	}
+	t.Print()
+	return nil
+}
+
 func NewTablePrinter(out io.Writer) *TablePrinter {
 	t := table.NewWriter()
 	t.SetStyle(KubeCtlStyle)
 	t.SetOutputMirror(out)
-	return &TablePrinter{Tbl: t}
+	return &TablePrinter{Tbl: t, out: out}
+}
+
+// SetFormat sets the format to render the table, the table can be exported as CSV or Markdown
+func (t *TablePrinter) SetFormat(format Format) {
+	t.format = format
 }
 
 func (t *TablePrinter) SetStyle(style table.Style) {
@@ -99,14 +176,81 @@ func (t *TablePrinter) SetStyle(style table.Style) {
 }
 
 func (t *TablePrinter) SetHeader(header ...interface{}) {
+	t.header = header
 	t.Tbl.AppendHeader(header)
 }
 
+// ApplyOptions applies the table options, it should be called after the header is set
+// because the columns are selected by the header names.
+func (t *TablePrinter) ApplyOptions(opts *TableOptions) error {
+	if opts == nil {
+		return nil
+	}
+	columns := make([]string, len(t.header))
+	for i, h := range t.header {
+		columns[i] = fmt.Sprint(h)
+	}
+	if len(opts.SortBy) > 0 {
+		sortBy, err := parseSortColumns(opts.SortBy, columns)
+		if err != nil {
+			return err
+		}
+		t.sortBy = sortBy
+	}
+
+	// the column configs set by the command are kept if no option changes them
+	truncated := opts.MaxWidth > 0 && !opts.Full
+	if truncated || len(opts.Columns) > 0 {
+		configs := make([]table.ColumnConfig, len(t.header))
+		for i := range t.header {
+			configs[i].Number = i + 1
+			if truncated {
+				configs[i].WidthMax = opts.MaxWidth
+				configs[i].WidthMaxEnforcer = truncate
+			}
+		}
+		if len(opts.Columns) > 0 {
+			for i := range configs {
+				configs[i].Hidden = true
+			}
+			for _, c := range opts.Columns {
+				i, err := columnIndex(columns, c)
+				if err != nil {
+					return err
+				}
+				configs[i].Hidden = false
+			}
+		}
+		t.Tbl.SetColumnConfigs(configs)
+		t.hidden = make([]bool, len(configs))
+		for i := range configs {
+			t.hidden[i] = configs[i].Hidden
+		}
+	}
+	t.noHeaders = opts.NoHeaders
+	if opts.NoHeaders {
+		t.Tbl.ResetHeaders()
+	}
+	return nil
+}
+
+// truncate truncates the value to the max length and marks it with an ellipsis
+func truncate(col string, maxLen int) string {
+	if text.RuneWidthWithoutEscSequences(col) <= maxLen {
+		return col
+	}
+	if maxLen <= 3 {
+		return text.Trim(col, maxLen)
+	}
+	return text.Trim(col, maxLen-3) + "..."
+}
+
 func (t *TablePrinter) AddRow(row ...interface{}) {
 	rowObj := table.Row{}
 	for _, col := range row {
 		rowObj = append(rowObj, col)
 	}
+	t.rows = append(t.rows, rowObj)
 	t.Tbl.AppendRow(rowObj)
 }
 
@@ -114,25 +258,59 @@ func (t *TablePrinter) Print() {
 	if t == nil || t.Tbl == nil {
 		return
 	}
-	t.Tbl.Render()
+	rows := sortRows(t.rows, t.sortBy)
+	switch t.format {
+	case CSV:
+		t.renderCSV(rows)
+	case Markdown:
+		t.resetRows(rows, false)
+		t.Tbl.RenderMarkdown()
+	default:
+		// the CSV and Markdown are exported without colors, only the table style is colored
+		t.resetRows(rows, ColorEnabled())
+		t.Tbl.Render()
+	}
 }
 
-// SortBy sorts the table alphabetically by the column you specify, it will be sorted by the first table column in default.
-// The columnNumber index starts from 1
+// resetRows replaces the rows of the table writer with the sorted rows, and colors the
+// STATUS column by the theme if colored is true.
+func (t *TablePrinter) resetRows(rows []table.Row, colored bool) {
+	index := -1
+	if colored {
+		for i, h := range t.header {
+			if strings.EqualFold(fmt.Sprint(h), "STATUS") {
+				index = i
+				break
+			}
+		}
+	}
+	if len(t.sortBy) == 0 && index < 0 {
+		return
+	}
+	t.Tbl.ResetRows()
+	for _, row := range rows {
+		if index >= 0 && index < len(row) {
+			r := make(table.Row, len(row))
+			copy(r, row)
+			r[index] = ColorStatus(fmt.Sprint(r[index]))
+			row = r
+		}
+		t.Tbl.AppendRow(row)
+	}
+}
+
+// SortBy sorts the table in ascending order by the column you specify, it will be sorted by the first table column in default.
+// The columnNumber index starts from 1, the numbers, times and quantities are compared by their values,
+// and the rows with equal values keep the order they are added.
 func (t *TablePrinter) SortBy(columnNumber ...int) {
 	if len(columnNumber) == 0 {
-		t.Tbl.SortBy([]table.SortBy{
-			{
-				Number: 1,
-			},
-		})
-		return
+		columnNumber = []int{1}
 	}
-	res := make([]table.SortBy, len(columnNumber))
+	res := make([]sortColumn, len(columnNumber))
 	for i := range columnNumber {
-		res[i].Number = columnNumber[i]
+		res[i].index = columnNumber[i] - 1
 	}
-	t.Tbl.SortBy(res)
+	t.sortBy = res
 }
 
 // PrintPairStringToLine prints pair string for a line , the format is as follows "<space>*<key>:\t<value>".
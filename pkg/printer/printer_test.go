@@ -20,11 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package printer
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	clitesting "github.com/apecloud/kbcli/pkg/testing"
 )
@@ -134,3 +139,92 @@ func TestSort(t *testing.T) {
 	}
 	printer.Print()
 }
+
+func TestApplyOptions(t *testing.T) {
+	newPrinter := func(out *bytes.Buffer) *TablePrinter {
+		printer := NewTablePrinter(out)
+		printer.SetHeader("NAME", "STATUS", "MESSAGE")
+		printer.AddRow("brier63", "Running", strings.Repeat("a", 10))
+		return printer
+	}
+
+	out := &bytes.Buffer{}
+	printer := newPrinter(out)
+	assert.Nil(t, printer.ApplyOptions(&TableOptions{NoHeaders: true, Columns: []string{"name", "message"}, MaxWidth: 8}))
+	printer.Print()
+	assert.Equal(t, "brier63   aaaaa...   \n", out.String())
+
+	out.Reset()
+	printer = newPrinter(out)
+	assert.Nil(t, printer.ApplyOptions(&TableOptions{Columns: []string{"STATUS"}, MaxWidth: 8, Full: true}))
+	printer.Print()
+	assert.Equal(t, "STATUS    \nRunning   \n", out.String())
+
+	printer = newPrinter(out)
+	assert.NotNil(t, printer.ApplyOptions(&TableOptions{Columns: []string{"AGE"}}))
+}
+
+func TestPrintExportFormats(t *testing.T) {
+	out := &bytes.Buffer{}
+	printer := NewTablePrinter(out)
+	printer.SetFormat(CSV)
+	printer.SetHeader("NAME", "STATUS", "MESSAGE")
+	printer.AddRow("cedar51", "Running", BoldGreen("ok"))
+	printer.AddRow("brier63", "Running", "a,b")
+	printer.SortBy(1)
+	assert.Nil(t, printer.ApplyOptions(&TableOptions{Columns: []string{"NAME", "MESSAGE"}}))
+	printer.Print()
+	assert.Equal(t, "NAME,MESSAGE\nbrier63,\"a,b\"\ncedar51,ok\n", out.String())
+
+	out.Reset()
+	printer = NewTablePrinter(out)
+	printer.SetFormat(Markdown)
+	printer.SetHeader("NAME", "STATUS")
+	printer.AddRow("brier63", "Running")
+	printer.Print()
+	assert.Equal(t, "| NAME | STATUS |\n| --- | --- |\n| brier63 | Running |\n", out.String())
+}
+
+func TestResourcePrinter(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := &ResourcePrinter{Format: CSV, WithNamespace: true}
+	tbl := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Detail", Type: "string", Priority: 1},
+		},
+		Rows: []metav1.TableRow{
+			{
+				Cells:  []interface{}{"brier63", "Running", "detail"},
+				Object: runtime.RawExtension{Object: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "brier63", Namespace: "default"}}},
+			},
+		},
+	}
+	assert.Nil(t, p.PrintObj(tbl, out))
+	assert.Equal(t, "NAMESPACE,NAME,STATUS\ndefault,brier63,Running\n", out.String())
+
+	// the header is printed only once
+	out.Reset()
+	obj := &unstructured.Unstructured{}
+	obj.SetName("cedar51")
+	obj.SetNamespace("default")
+	assert.Nil(t, p.PrintObj(obj, out))
+	assert.Equal(t, "default,cedar51\n", out.String())
+
+	out.Reset()
+	p = &ResourcePrinter{Format: Markdown}
+	assert.Nil(t, p.PrintObj(obj, out))
+	assert.Equal(t, "| NAME |\n| --- |\n| cedar51 |\n", out.String())
+
+	// the single objects are appended to the current Markdown table
+	out.Reset()
+	assert.Nil(t, p.PrintObj(obj, out))
+	assert.Equal(t, "| cedar51 |\n", out.String())
+
+	// the Markdown header is repeated for each chunk
+	out.Reset()
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*obj}}
+	assert.Nil(t, p.PrintObj(list, out))
+	assert.Equal(t, "\n| NAME |\n| --- |\n| cedar51 |\n", out.String())
+}
@@ -0,0 +1,173 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+const barWidth = 30
+
+// Bar shows the progress of the countable works with the percentage and the estimated
+// remaining time, such as the components of a cluster to be ready.
+type Bar struct {
+	out      io.Writer
+	title    string
+	total    int
+	current  int
+	message  string
+	start    time.Time
+	terminal bool
+	// last is the state of the last printed line, the same state is not printed repeatedly
+	last string
+	mu   sync.Mutex
+	// now is used to get the current time, it is replaced in tests
+	now func() time.Time
+}
+
+// NewBar creates a progress bar with the title and the total count of the works
+func NewBar(out io.Writer, title string, total int) *Bar {
+	return &Bar{
+		out:      out,
+		title:    title,
+		total:    total,
+		start:    time.Now(),
+		terminal: isTerminal(out),
+		now:      time.Now,
+	}
+}
+
+// WithoutRedraw prints the progress line by line even if the output is a terminal, it is used
+// if other logs are printed between the updates of the bar.
+func (b *Bar) WithoutRedraw() *Bar {
+	b.terminal = false
+	return b
+}
+
+// SetTotal updates the total count, it is used if the total is unknown when the bar is created
+func (b *Bar) SetTotal(total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total = total
+	b.render()
+}
+
+// Set sets the count of the finished works
+func (b *Bar) Set(current int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = current
+	b.render()
+}
+
+// Add increases the count of the finished works by n
+func (b *Bar) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current += n
+	b.render()
+}
+
+// Update updates the counts and the message at once, it is used by the pollers which get
+// all of them in each poll.
+func (b *Bar) Update(current, total int, msg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current, b.total, b.message = current, total, msg
+	b.render()
+}
+
+// SetMessage sets the message shown after the bar, such as the current work
+func (b *Bar) SetMessage(msg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.message = msg
+	b.render()
+}
+
+// Done finishes the bar, the bar is not updated anymore
+func (b *Bar) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.terminal && !printer.Quiet() {
+		fmt.Fprintln(b.out)
+	}
+}
+
+// String returns the bar like "title [=========>          ] 3/10 30% ETA 1m10s message"
+func (b *Bar) String() string {
+	current, total := b.current, b.total
+	if current > total {
+		current = total
+	}
+	percent := 0
+	if total > 0 {
+		percent = current * 100 / total
+	}
+	filled := barWidth * percent / 100
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+
+	line := fmt.Sprintf("%s [%s] %d/%d %d%%", b.title, bar, current, total, percent)
+	if eta := b.eta(); eta > 0 {
+		line += fmt.Sprintf(" ETA %s", eta)
+	}
+	if b.message != "" {
+		line += " " + b.message
+	}
+	return line
+}
+
+// eta estimates the remaining time by the average time of the finished works
+func (b *Bar) eta() time.Duration {
+	if b.current <= 0 || b.current >= b.total {
+		return 0
+	}
+	elapsed := b.now().Sub(b.start)
+	remaining := elapsed * time.Duration(b.total-b.current) / time.Duration(b.current)
+	return remaining.Round(time.Second)
+}
+
+func (b *Bar) render() {
+	if printer.Quiet() {
+		return
+	}
+	line := b.String()
+	if b.terminal {
+		// redraw the line in place and clear the remaining characters
+		fmt.Fprintf(b.out, "\r%s\033[K", line)
+		return
+	}
+	// the ETA is ignored, otherwise a new line is printed every time the bar is updated
+	state := fmt.Sprintf("%d/%d %s", b.current, b.total, b.message)
+	if state == b.last {
+		return
+	}
+	b.last = state
+	fmt.Fprintln(b.out, line)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/spinner"
+)
+
+// messageWidth is the width of the step messages, so the statuses of the steps are aligned
+const messageWidth = 50
+
+// NewSpinner starts a spinner with the message, the spinner should be stopped by Success or Fail
+func NewSpinner(out io.Writer, format string, a ...any) spinner.Interface {
+	return spinner.New(out, spinner.WithMessage(fmt.Sprintf("%-*s", messageWidth, fmt.Sprintf(format, a...))))
+}
+
+// Checklist runs the steps one by one, each step is shown with a spinner and marked as
+// OK or FAIL when it is finished.
+type Checklist struct {
+	out   io.Writer
+	steps []step
+}
+
+type step struct {
+	name string
+	run  func() error
+}
+
+// NewChecklist creates an empty checklist
+func NewChecklist(out io.Writer) *Checklist {
+	return &Checklist{out: out}
+}
+
+// AddStep appends a step to the checklist
+func (c *Checklist) AddStep(name string, run func() error) *Checklist {
+	c.steps = append(c.steps, step{name: name, run: run})
+	return c
+}
+
+// Run runs the steps in order and stops at the first failed step, the spinners are not
+// shown if the output is not a terminal, the results of the steps are printed instead.
+// Nothing is printed in the quiet mode.
+func (c *Checklist) Run() error {
+	terminal := isTerminal(c.out)
+	for _, st := range c.steps {
+		if printer.Quiet() {
+			if err := st.run(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !terminal {
+			err := st.run()
+			status := printer.BoldGreen("OK")
+			if err != nil {
+				status = printer.BoldRed("FAIL")
+			}
+			fmt.Fprintf(c.out, "%-*s %s\n", messageWidth, st.name, status)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		s := NewSpinner(c.out, st.name)
+		if err := st.run(); err != nil {
+			s.Fail()
+			return err
+		}
+		s.Success()
+	}
+	return nil
+}
+
+// isTerminal returns true if the writer is a terminal, the progress is redrawn in place
+// for the terminals and printed line by line for the others such as the CI logs.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
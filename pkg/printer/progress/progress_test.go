@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+func TestChecklist(t *testing.T) {
+	var steps []string
+	out := &bytes.Buffer{}
+	err := NewChecklist(out).
+		AddStep("step 1", func() error {
+			steps = append(steps, "1")
+			return nil
+		}).
+		AddStep("step 2", func() error {
+			return fmt.Errorf("failed")
+		}).
+		AddStep("step 3", func() error {
+			steps = append(steps, "3")
+			return nil
+		}).Run()
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, []string{"1"}, steps)
+	assert.Contains(t, out.String(), "step 1")
+	assert.Contains(t, out.String(), "FAIL")
+
+	// nothing is printed in the quiet mode
+	printer.SetQuiet(true)
+	defer printer.SetQuiet(false)
+	out.Reset()
+	err = NewChecklist(out).AddStep("step 1", func() error { return fmt.Errorf("failed") }).Run()
+	assert.EqualError(t, err, "failed")
+	assert.Empty(t, out.String())
+}
+
+func TestBar(t *testing.T) {
+	out := &bytes.Buffer{}
+	start := time.Now()
+	now := start
+	bar := NewBar(out, "Wait for components", 4)
+	bar.start = start
+	bar.now = func() time.Time { return now }
+
+	now = start.Add(time.Minute)
+	bar.Set(1)
+	assert.Equal(t, "Wait for components [=======>                      ] 1/4 25% ETA 3m0s", bar.String())
+
+	// the same progress is not printed again
+	now = start.Add(2 * time.Minute)
+	bar.Set(1)
+	bar.SetMessage("mysql")
+	bar.Add(3)
+	bar.Done()
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "Wait for components [==============================] 4/4 100% mysql", lines[2])
+}
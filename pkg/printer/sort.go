@@ -0,0 +1,199 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package printer
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// timeLayouts are the layouts of the time columns printed by kbcli and kubernetes
+var timeLayouts = []string{
+	time.RFC3339,
+	"Jan 02,2006 15:04 UTC-0700",
+	"Jan 02,2006 15:04:05 UTC-0700",
+	"Jan 02,2006 15:04:05.000 UTC-0700",
+}
+
+// sortColumn is a column to sort the rows by
+type sortColumn struct {
+	index int
+	desc  bool
+}
+
+// parseSortColumns parses the columns in the format of NAME or NAME:asc|desc, the names are matched
+// against the columns case-insensitively.
+func parseSortColumns(specs []string, columns []string) ([]sortColumn, error) {
+	var res []sortColumn
+	for _, spec := range specs {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		name, order, _ := strings.Cut(spec, ":")
+		c := sortColumn{}
+		switch strings.ToLower(order) {
+		case "", "asc":
+		case "desc":
+			c.desc = true
+		default:
+			return nil, fmt.Errorf("invalid sort order %s of column %s, should be asc or desc", order, name)
+		}
+		i, err := columnIndex(columns, name)
+		if err != nil {
+			return nil, err
+		}
+		c.index = i
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// columnIndex returns the index of the column, the spaces, underscores and hyphens in
+// the names are treated as the same, so both CREATED-TIME and "Created Time" are matched.
+func columnIndex(columns []string, name string) (int, error) {
+	normalize := func(s string) string {
+		return strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToUpper(strings.TrimSpace(s)))
+	}
+	for i, c := range columns {
+		if normalize(c) == normalize(name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown column %s, supported columns: %s", name, strings.Join(columns, " "))
+}
+
+// sortRows returns the rows sorted stably by the columns, the rows are returned as is if
+// no column is specified.
+func sortRows(rows []table.Row, columns []sortColumn) []table.Row {
+	if len(columns) == 0 {
+		return rows
+	}
+	sorted := make([]table.Row, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return lessRow(sorted[i], sorted[j], columns)
+	})
+	return sorted
+}
+
+func lessRow(a, b []interface{}, columns []sortColumn) bool {
+	cell := func(row []interface{}, i int) interface{} {
+		if i < 0 || i >= len(row) {
+			return nil
+		}
+		return row[i]
+	}
+	for _, c := range columns {
+		r := compareValues(cell(a, c.index), cell(b, c.index))
+		if r == 0 {
+			continue
+		}
+		if c.desc {
+			return r > 0
+		}
+		return r < 0
+	}
+	return false
+}
+
+// compareValues compares the cells by their values if both cells are numbers, times, durations
+// or quantities, otherwise compares them as strings, the empty cells are always the smallest.
+func compareValues(a, b interface{}) int {
+	toString := func(v interface{}) string {
+		if v == nil {
+			return ""
+		}
+		return strings.TrimSpace(text.StripEscape(fmt.Sprint(v)))
+	}
+	x, y := toString(a), toString(b)
+	switch {
+	case x == y:
+		return 0
+	case x == "":
+		return -1
+	case y == "":
+		return 1
+	}
+
+	if fx, err := strconv.ParseFloat(x, 64); err == nil {
+		if fy, err := strconv.ParseFloat(y, 64); err == nil {
+			return cmp.Compare(fx, fy)
+		}
+	}
+	for _, layout := range timeLayouts {
+		if tx, err := time.Parse(layout, x); err == nil {
+			if ty, err := time.Parse(layout, y); err == nil {
+				return tx.Compare(ty)
+			}
+		}
+	}
+	if dx, err := time.ParseDuration(x); err == nil {
+		if dy, err := time.ParseDuration(y); err == nil {
+			return cmp.Compare(dx, dy)
+		}
+	}
+	if qx, err := resource.ParseQuantity(x); err == nil {
+		if qy, err := resource.ParseQuantity(y); err == nil {
+			return qx.Cmp(qy)
+		}
+	}
+	return strings.Compare(x, y)
+}
+
+// TableSorter sorts the rows of the server-side tables by the columns before printing
+// them with the delegate printer, the other objects are printed as is.
+type TableSorter struct {
+	Delegate printers.ResourcePrinter
+	// SortBy are the columns to sort by, a column can be suffixed with :desc
+	SortBy []string
+}
+
+var _ printers.ResourcePrinter = &TableSorter{}
+
+func (s *TableSorter) PrintObj(obj runtime.Object, w io.Writer) error {
+	tbl, ok := obj.(*metav1.Table)
+	if !ok {
+		return s.Delegate.PrintObj(obj, w)
+	}
+	columns := make([]string, len(tbl.ColumnDefinitions))
+	for i, c := range tbl.ColumnDefinitions {
+		columns[i] = c.Name
+	}
+	sortBy, err := parseSortColumns(s.SortBy, columns)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(tbl.Rows, func(i, j int) bool {
+		return lessRow(tbl.Rows[i].Cells, tbl.Rows[j].Cells, sortBy)
+	})
+	return s.Delegate.PrintObj(obj, w)
+}
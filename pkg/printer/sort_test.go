@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+func TestSortByColumns(t *testing.T) {
+	newPrinter := func(out *bytes.Buffer) *TablePrinter {
+		printer := NewTablePrinter(out)
+		printer.SetFormat(CSV)
+		printer.SetHeader("NAME", "STATUS", "SIZE", "CREATED-TIME")
+		printer.AddRow("cedar51", "Running", "2Gi", "Feb 20,2023 16:39 UTC+0800")
+		printer.AddRow("brier63", "Failed", "512Mi", "Feb 21,2023 09:10 UTC+0800")
+		printer.AddRow("alpha19", "Running", "10Gi", "Jan 03,2023 08:00 UTC+0800")
+		return printer
+	}
+
+	out := &bytes.Buffer{}
+	printer := newPrinter(out)
+	printer.SortBy(4)
+	printer.Print()
+	assert.Equal(t, "NAME,STATUS,SIZE,CREATED-TIME\n"+
+		"alpha19,Running,10Gi,\"Jan 03,2023 08:00 UTC+0800\"\n"+
+		"cedar51,Running,2Gi,\"Feb 20,2023 16:39 UTC+0800\"\n"+
+		"brier63,Failed,512Mi,\"Feb 21,2023 09:10 UTC+0800\"\n", out.String())
+
+	// the columns specified by the options take precedence, the equal rows keep their order
+	out.Reset()
+	printer = newPrinter(out)
+	printer.SortBy(1)
+	assert.Nil(t, printer.ApplyOptions(&TableOptions{Columns: []string{"NAME"}, SortBy: []string{"status:desc", "size"}}))
+	printer.Print()
+	assert.Equal(t, "NAME\ncedar51\nalpha19\nbrier63\n", out.String())
+
+	printer = newPrinter(out)
+	assert.NotNil(t, printer.ApplyOptions(&TableOptions{SortBy: []string{"AGE"}}))
+	assert.NotNil(t, printer.ApplyOptions(&TableOptions{SortBy: []string{"NAME:up"}}))
+}
+
+func TestCompareValues(t *testing.T) {
+	assert.Equal(t, -1, compareValues(2, 10))
+	assert.Equal(t, 1, compareValues("1.5Gi", "800Mi"))
+	assert.Equal(t, -1, compareValues("90s", "2m"))
+	assert.Equal(t, -1, compareValues("", "a"))
+	assert.Equal(t, 0, compareValues(BoldGreen("a"), "a"))
+	assert.Equal(t, 1, compareValues("b", "a"))
+}
+
+func TestTableSorter(t *testing.T) {
+	tbl := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Ready Replicas", Type: "integer"},
+		},
+		Rows: []metav1.TableRow{
+			{Cells: []interface{}{"cedar51", int64(3)}},
+			{Cells: []interface{}{"brier63", int64(1)}},
+			{Cells: []interface{}{"alpha19", int64(3)}},
+		},
+	}
+	var names []interface{}
+	s := &TableSorter{
+		SortBy: []string{"READY-REPLICAS:desc", "NAME"},
+		Delegate: printers.ResourcePrinterFunc(func(obj runtime.Object, _ io.Writer) error {
+			for _, r := range obj.(*metav1.Table).Rows {
+				names = append(names, r.Cells[0])
+			}
+			return nil
+		}),
+	}
+	assert.Nil(t, s.PrintObj(tbl, &bytes.Buffer{}))
+	assert.Equal(t, []interface{}{"alpha19", "cedar51", "brier63"}, names)
+
+	s.SortBy = []string{"AGE"}
+	assert.NotNil(t, s.PrintObj(tbl, &bytes.Buffer{}))
+}
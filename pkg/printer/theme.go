@@ -0,0 +1,220 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode controls when the outputs are colored
+type ColorMode string
+
+const (
+	// ColorAuto colors the outputs only if the stdout is a terminal and NO_COLOR is not set
+	ColorAuto ColorMode = "auto"
+	// ColorAlways always colors the outputs
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors the outputs
+	ColorNever ColorMode = "never"
+)
+
+// colorNames are the colors can be used by the theme
+var colorNames = map[string][]color.Attribute{
+	"red":     {color.FgRed},
+	"green":   {color.FgGreen},
+	"yellow":  {color.FgYellow},
+	"blue":    {color.FgBlue},
+	"magenta": {color.FgMagenta},
+	"cyan":    {color.FgCyan},
+	"white":   {color.FgWhite},
+	"faint":   {color.Faint},
+	"bold":    {color.Bold},
+	"none":    nil,
+}
+
+// defaultStatusColors are the colors of the well-known phases of the clusters, backups, OpsRequests and addons
+var defaultStatusColors = map[string]string{
+	"running":     "green",
+	"succeed":     "green",
+	"completed":   "green",
+	"available":   "green",
+	"enabled":     "green",
+	"ready":       "green",
+	"bound":       "green",
+	"failed":      "red",
+	"abnormal":    "red",
+	"error":       "red",
+	"unavailable": "red",
+	"creating":    "yellow",
+	"updating":    "yellow",
+	"pending":     "yellow",
+	"starting":    "yellow",
+	"stopping":    "yellow",
+	"deleting":    "yellow",
+	"enabling":    "cyan",
+	"disabling":   "cyan",
+	"stopped":     "faint",
+	"disabled":    "faint",
+}
+
+// Theme is the colors and symbols shared by the table and describe outputs
+type Theme struct {
+	// StatusColors maps the lower case status to the color name
+	StatusColors map[string]string
+	// Emoji prints the emoji symbols such as ✔, the plain text symbols are used if it's false
+	Emoji bool
+}
+
+var theme = newDefaultTheme()
+
+func newDefaultTheme() *Theme {
+	t := &Theme{StatusColors: map[string]string{}, Emoji: true}
+	for k, v := range defaultStatusColors {
+		t.StatusColors[k] = v
+	}
+	return t
+}
+
+// ColorModes returns the supported color modes
+func ColorModes() []string {
+	return []string{string(ColorAuto), string(ColorAlways), string(ColorNever)}
+}
+
+// SetColorMode enables or disables the colors and emojis of all outputs by the mode, the
+// NO_COLOR environment variable is honored in the auto mode, see https://no-color.org.
+func SetColorMode(mode string) error {
+	var enabled bool
+	switch ColorMode(strings.ToLower(mode)) {
+	case ColorAlways:
+		enabled = true
+	case ColorNever:
+		enabled = false
+	case ColorAuto, "":
+		enabled = colorSupported()
+	default:
+		return fmt.Errorf("invalid color mode %s, should be one of %s", mode, strings.Join(ColorModes(), "|"))
+	}
+	color.NoColor = !enabled
+	if enabled {
+		text.EnableColors()
+	} else {
+		text.DisableColors()
+	}
+	theme.Emoji = enabled
+	return nil
+}
+
+// ColorEnabled returns true if the outputs are colored
+func ColorEnabled() bool {
+	return !color.NoColor
+}
+
+func colorSupported() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+// SetStatusColors overrides the colors of the statuses, the spec is in the format of
+// status=color separated by comma such as "Failed=magenta,Running=blue".
+func SetStatusColors(spec string) error {
+	colors, err := ParseStatusColors(spec)
+	if err != nil {
+		return err
+	}
+	for k, v := range colors {
+		theme.StatusColors[k] = v
+	}
+	return nil
+}
+
+// ParseStatusColors parses the status colors in the format of status=color separated by comma
+func ParseStatusColors(spec string) (map[string]string, error) {
+	colors := map[string]string{}
+	for _, item := range strings.Split(spec, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid status color %s, should be in the format of status=color", item)
+		}
+		name := strings.ToLower(strings.TrimSpace(kv[1]))
+		if _, ok := colorNames[name]; !ok {
+			return nil, fmt.Errorf("invalid color %s, should be one of %s", kv[1], strings.Join(ColorNames(), "|"))
+		}
+		colors[strings.ToLower(strings.TrimSpace(kv[0]))] = name
+	}
+	return colors, nil
+}
+
+// ColorNames returns the color names can be used by the status colors
+func ColorNames() []string {
+	names := make([]string, 0, len(colorNames))
+	for k := range colorNames {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ColorStatus returns the status colored by the theme, the unknown statuses are returned as is.
+func ColorStatus(status string) string {
+	if status == "" || !ColorEnabled() {
+		return status
+	}
+	attrs := colorNames[StatusColor(status)]
+	if len(attrs) == 0 {
+		return status
+	}
+	return color.New(attrs...).Sprint(status)
+}
+
+// StatusColor returns the color name of the status in the theme, empty if the status has no color
+func StatusColor(status string) string {
+	return theme.StatusColors[strings.ToLower(status)]
+}
+
+// SuccessSymbol returns the symbol marking a successful check
+func SuccessSymbol() string {
+	if theme.Emoji {
+		return "✔"
+	}
+	return "[OK]"
+}
+
+// WarningSymbol returns the symbol marking a warning
+func WarningSymbol() string {
+	if theme.Emoji {
+		return "⚠️"
+	}
+	return "[WARN]"
+}
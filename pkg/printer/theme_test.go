@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetColorMode(t *testing.T) {
+	defer func() { _ = SetColorMode(string(ColorNever)) }()
+
+	assert.NoError(t, SetColorMode(string(ColorAlways)))
+	assert.True(t, ColorEnabled())
+	assert.Equal(t, "✔", SuccessSymbol())
+
+	assert.NoError(t, SetColorMode(string(ColorNever)))
+	assert.False(t, ColorEnabled())
+	assert.Equal(t, "Failed", ColorStatus("Failed"))
+	assert.Equal(t, "[OK]", SuccessSymbol())
+
+	t.Setenv("NO_COLOR", "1")
+	assert.NoError(t, SetColorMode(string(ColorAuto)))
+	assert.False(t, ColorEnabled())
+
+	assert.Error(t, SetColorMode("sometimes"))
+}
+
+func TestColorStatus(t *testing.T) {
+	defer func() {
+		theme = newDefaultTheme()
+		_ = SetColorMode(string(ColorNever))
+	}()
+	assert.NoError(t, SetColorMode(string(ColorAlways)))
+
+	assert.Contains(t, ColorStatus("Failed"), "\x1b[31m")
+	assert.Contains(t, ColorStatus("running"), "\x1b[32m")
+	assert.Equal(t, "Unknown", ColorStatus("Unknown"))
+
+	assert.NoError(t, SetStatusColors("Failed=magenta, Running=none"))
+	assert.Contains(t, ColorStatus("Failed"), "\x1b[35m")
+	assert.Equal(t, "Running", ColorStatus("Running"))
+
+	assert.Error(t, SetStatusColors("Failed"))
+	assert.Error(t, SetStatusColors("Failed=pink"))
+}
+
+func TestColorStatusColumn(t *testing.T) {
+	defer func() { _ = SetColorMode(string(ColorNever)) }()
+	assert.NoError(t, SetColorMode(string(ColorAlways)))
+
+	out := &bytes.Buffer{}
+	tbl := NewTablePrinter(out)
+	tbl.SetHeader("NAME", "STATUS")
+	tbl.AddRow("mycluster", "Failed")
+	tbl.Print()
+	assert.Contains(t, out.String(), "\x1b[31mFailed")
+
+	// the CSV is exported without colors
+	out.Reset()
+	tbl = NewTablePrinter(out)
+	tbl.SetFormat(CSV)
+	tbl.SetHeader("NAME", "STATUS")
+	tbl.AddRow("mycluster", "Failed")
+	tbl.Print()
+	assert.False(t, strings.Contains(out.String(), "\x1b["))
+}
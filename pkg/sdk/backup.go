@@ -0,0 +1,212 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cmd/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// BackupSpec is the spec to back up a cluster, the fields are the same as the flags of
+// `kbcli cluster backup`.
+type BackupSpec struct {
+	ClusterName string
+	Namespace   string
+	// Name is the backup name, a name is generated with the cluster name if it is empty
+	Name string
+	// Method is the backup method defined in the backup policy
+	Method string
+	// Policy uses the default backup policy of the cluster if it is empty
+	Policy string
+	// DeletionPolicy is one of Delete and Retain, default is Delete
+	DeletionPolicy  string
+	RetentionPeriod string
+	// ParentBackup is the parent backup name, used for incremental backup
+	ParentBackup string
+}
+
+// BackupResult is the result of a backup request
+type BackupResult struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	ClusterName string `json:"clusterName"`
+	// OpsRequest is the name of the OpsRequest which performs the backup
+	OpsRequest string `json:"opsRequest"`
+}
+
+// ListBackupsOptions is the options to list backups
+type ListBackupsOptions struct {
+	Namespace     string
+	AllNamespaces bool
+	// ClusterName lists the backups of the cluster only if it is not empty
+	ClusterName string
+}
+
+// Backup is the summary of a backup
+type Backup struct {
+	Name           string     `json:"name"`
+	Namespace      string     `json:"namespace"`
+	ClusterName    string     `json:"clusterName,omitempty"`
+	Method         string     `json:"method"`
+	Phase          string     `json:"phase,omitempty"`
+	FailureReason  string     `json:"failureReason,omitempty"`
+	TotalSize      string     `json:"totalSize,omitempty"`
+	CreationTime   time.Time  `json:"creationTime"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	Expiration     *time.Time `json:"expiration,omitempty"`
+}
+
+// Backup creates an OpsRequest to back up the cluster.
+func (c *Client) Backup(ctx context.Context, spec BackupSpec) (*BackupResult, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// the namespace is resolved first, it's a part of the generated backup name
+	namespace, err := c.namespaceOrDefault(spec.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	o := &cluster.CreateBackupOptions{
+		CreateOptions: action.CreateOptions{
+			IOStreams:       discardStreams(),
+			Factory:         c.factory,
+			GVR:             types.OpsGVR(),
+			CueTemplateName: "opsrequest_template.cue",
+			Args:            []string{spec.ClusterName},
+			Quiet:           true,
+		},
+		BackupSpec: appsv1alpha1.BackupSpec{
+			BackupName:       spec.Name,
+			BackupMethod:     spec.Method,
+			BackupPolicyName: spec.Policy,
+			DeletionPolicy:   spec.DeletionPolicy,
+			RetentionPeriod:  spec.RetentionPeriod,
+			ParentBackupName: spec.ParentBackup,
+		},
+	}
+	o.CreateOptions.Options = o
+	if o.BackupSpec.DeletionPolicy == "" {
+		o.BackupSpec.DeletionPolicy = string(dpv1alpha1.BackupDeletionPolicyDelete)
+	}
+	if o.BackupSpec.BackupName == "" {
+		o.BackupSpec.BackupName = cluster.GenerateBackupName(namespace, spec.ClusterName)
+	}
+
+	if err := o.CompleteBackup(); err != nil {
+		return nil, err
+	}
+	o.Namespace = namespace
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := o.Run(); err != nil {
+		return nil, err
+	}
+	return &BackupResult{
+		Name:        o.BackupSpec.BackupName,
+		Namespace:   o.Namespace,
+		ClusterName: o.ClusterRef,
+		OpsRequest:  o.OpsRequestName,
+	}, nil
+}
+
+// ListBackups returns the backups sorted by the creation time.
+func (c *Client) ListBackups(ctx context.Context, opts ListBackupsOptions) ([]Backup, error) {
+	namespace := metav1.NamespaceAll
+	if !opts.AllNamespaces {
+		var err error
+		if namespace, err = c.namespaceOrDefault(opts.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	dynamic, err := c.dynamic()
+	if err != nil {
+		return nil, err
+	}
+	listOpts := metav1.ListOptions{}
+	if opts.ClusterName != "" {
+		listOpts.LabelSelector = util.BuildLabelSelectorByNames("", []string{opts.ClusterName})
+	}
+	objs, err := dynamic.Resource(types.BackupGVR()).Namespace(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(objs.Items))
+	for _, obj := range objs.Items {
+		backup := &dpv1alpha1.Backup{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, backup); err != nil {
+			return nil, err
+		}
+		backups = append(backups, *toBackup(backup))
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreationTime.Before(backups[j].CreationTime)
+	})
+	return backups, nil
+}
+
+// GetBackup returns the backup with the name in the namespace.
+func (c *Client) GetBackup(ctx context.Context, namespace, name string) (*Backup, error) {
+	backup := &dpv1alpha1.Backup{}
+	if err := c.get(ctx, types.BackupGVR(), namespace, name, backup); err != nil {
+		return nil, err
+	}
+	return toBackup(backup), nil
+}
+
+func toBackup(backup *dpv1alpha1.Backup) *Backup {
+	return &Backup{
+		Name:           backup.Name,
+		Namespace:      backup.Namespace,
+		ClusterName:    backup.Labels[constant.AppInstanceLabelKey],
+		Method:         backup.Spec.BackupMethod,
+		Phase:          string(backup.Status.Phase),
+		FailureReason:  backup.Status.FailureReason,
+		TotalSize:      backup.Status.TotalSize,
+		CreationTime:   backup.CreationTimestamp.Time,
+		CompletionTime: timeOrNil(backup.Status.CompletionTimestamp),
+		Expiration:     timeOrNil(backup.Status.Expiration),
+	}
+}
+
+func timeOrNil(t *metav1.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package sdk exposes the kbcli functionalities to Go programs. The functions are decoupled
+// from cobra commands and IOStreams, they accept a context and typed specs, and return
+// structured results instead of printing them.
+package sdk
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// Client is the entry of the SDK, it is safe to be shared by multiple goroutines.
+type Client struct {
+	factory cmdutil.Factory
+}
+
+// NewClient creates a client with the kubeconfig file and the context name, empty values
+// use the default kubeconfig loading rules and the current context.
+func NewClient(kubeconfig, kubeContext string) *Client {
+	configFlags := util.NewConfigFlagNoWarnings()
+	if kubeconfig != "" {
+		configFlags.KubeConfig = &kubeconfig
+	}
+	if kubeContext != "" {
+		configFlags.Context = &kubeContext
+	}
+	return NewClientWithFactory(cmdutil.NewFactory(cmdutil.NewMatchVersionFlags(util.NewCachedConfigFlags(configFlags))))
+}
+
+// NewClientWithFactory creates a client with the factory.
+func NewClientWithFactory(f cmdutil.Factory) *Client {
+	// the default cluster resources are set when the kbcli command is initialized,
+	// set them here for the programs which do not run the command.
+	viper.SetDefault(types.CfgKeyClusterDefaultStorageSize, "20Gi")
+	viper.SetDefault(types.CfgKeyClusterDefaultReplicas, 1)
+	viper.SetDefault(types.CfgKeyClusterDefaultCPU, "1000m")
+	viper.SetDefault(types.CfgKeyClusterDefaultMemory, "1Gi")
+	return &Client{factory: f}
+}
+
+// DefaultNamespace returns the namespace of the current context, it is used when the
+// namespace is not specified in the specs.
+func (c *Client) DefaultNamespace() (string, error) {
+	namespace, _, err := c.factory.ToRawKubeConfigLoader().Namespace()
+	return namespace, err
+}
+
+func (c *Client) namespaceOrDefault(namespace string) (string, error) {
+	if namespace != "" {
+		return namespace, nil
+	}
+	return c.DefaultNamespace()
+}
+
+func (c *Client) dynamic() (dynamic.Interface, error) {
+	return c.factory.DynamicClient()
+}
+
+// get gets the object with the name in the namespace and converts it to the typed object
+func (c *Client) get(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, obj interface{}) error {
+	namespace, err := c.namespaceOrDefault(namespace)
+	if err != nil {
+		return err
+	}
+	dynamic, err := c.dynamic()
+	if err != nil {
+		return err
+	}
+	u, err := dynamic.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
+
+// discardStreams returns the IOStreams used by the command options, the outputs are
+// discarded because the results are returned to the caller.
+func discardStreams() genericiooptions.IOStreams {
+	return genericiooptions.IOStreams{In: strings.NewReader(""), Out: io.Discard, ErrOut: io.Discard}
+}
+
+// checkContext returns the error of the context if it is done, the command options do
+// not accept a context, so the context is checked before each step.
+func checkContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cmd/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// ClusterSpec is the spec to create a cluster, the fields are the same as the flags of
+// `kbcli cluster create`.
+type ClusterSpec struct {
+	// Name is the cluster name, a random name is generated if it is empty
+	Name      string
+	Namespace string
+
+	ClusterDefinition string
+	// ClusterVersion uses the default version of the cluster definition if it is empty
+	ClusterVersion string
+	// TerminationPolicy is one of DoNotTerminate, Halt, Delete and WipeOut, default is Delete
+	TerminationPolicy string
+
+	// Values set the cluster resources, each value corresponds to a component, such as
+	// "cpu=1,memory=1Gi,replicas=3,storage=20Gi".
+	Values []string
+	Labels map[string]string
+}
+
+// Cluster is the summary of a cluster
+type Cluster struct {
+	Name              string    `json:"name"`
+	Namespace         string    `json:"namespace"`
+	ClusterDefinition string    `json:"clusterDefinition"`
+	ClusterVersion    string    `json:"clusterVersion,omitempty"`
+	TerminationPolicy string    `json:"terminationPolicy"`
+	Phase             string    `json:"phase,omitempty"`
+	CreationTime      time.Time `json:"creationTime"`
+}
+
+// CreateCluster creates a cluster and returns the created cluster.
+func (c *Client) CreateCluster(ctx context.Context, spec ClusterSpec) (*Cluster, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	o := cluster.NewCreateOptions(c.factory, discardStreams())
+	if spec.Name != "" {
+		o.Args = []string{spec.Name}
+	}
+	o.ClusterDefRef = spec.ClusterDefinition
+	o.ClusterVersionRef = spec.ClusterVersion
+	o.Values = spec.Values
+	o.TerminationPolicy = spec.TerminationPolicy
+	if o.TerminationPolicy == "" {
+		o.TerminationPolicy = string(appsv1alpha1.Delete)
+	}
+	for k, v := range spec.Labels {
+		o.LabelStrs = append(o.LabelStrs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(o.LabelStrs)
+
+	// the defaults of the flags
+	o.PodAntiAffinity = string(appsv1alpha1.Preferred)
+	o.Tenancy = string(appsv1alpha1.SharedNode)
+	o.CPUOversellRatio = 1
+	o.MemoryOversellRatio = 1
+	o.VolumeRestorePolicy = "Parallel"
+	o.Quiet = true
+
+	if err := o.CreateOptions.Complete(); err != nil {
+		return nil, err
+	}
+	if spec.Namespace != "" {
+		o.Namespace = spec.Namespace
+	}
+	if err := o.Complete(); err != nil {
+		return nil, err
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := o.Run(); err != nil {
+		return nil, err
+	}
+	return c.GetCluster(ctx, o.Namespace, o.Name)
+}
+
+// GetCluster returns the cluster with the name in the namespace.
+func (c *Client) GetCluster(ctx context.Context, namespace, name string) (*Cluster, error) {
+	cls := &appsv1alpha1.Cluster{}
+	if err := c.get(ctx, types.ClusterGVR(), namespace, name, cls); err != nil {
+		return nil, err
+	}
+	return &Cluster{
+		Name:              cls.Name,
+		Namespace:         cls.Namespace,
+		ClusterDefinition: cls.Spec.ClusterDefRef,
+		ClusterVersion:    cls.Spec.ClusterVersionRef,
+		TerminationPolicy: string(cls.Spec.TerminationPolicy),
+		Phase:             string(cls.Status.Phase),
+		CreationTime:      cls.CreationTimestamp.Time,
+	}, nil
+}
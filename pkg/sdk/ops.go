@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+	"time"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// OpsRequest is the summary of an OpsRequest
+type OpsRequest struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Type        string `json:"type"`
+	ClusterName string `json:"clusterName"`
+	Phase       string `json:"phase,omitempty"`
+	Progress    string `json:"progress,omitempty"`
+	// Message is the message of the latest condition
+	Message        string     `json:"message,omitempty"`
+	CreationTime   time.Time  `json:"creationTime"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+}
+
+// GetOpsRequest returns the OpsRequest with the name in the namespace.
+func (c *Client) GetOpsRequest(ctx context.Context, namespace, name string) (*OpsRequest, error) {
+	ops := &appsv1alpha1.OpsRequest{}
+	if err := c.get(ctx, types.OpsGVR(), namespace, name, ops); err != nil {
+		return nil, err
+	}
+	result := &OpsRequest{
+		Name:         ops.Name,
+		Namespace:    ops.Namespace,
+		Type:         string(ops.Spec.Type),
+		ClusterName:  ops.Spec.ClusterRef,
+		Phase:        string(ops.Status.Phase),
+		Progress:     ops.Status.Progress,
+		CreationTime: ops.CreationTimestamp.Time,
+	}
+	if n := len(ops.Status.Conditions); n > 0 {
+		result.Message = ops.Status.Conditions[n-1].Message
+	}
+	if !ops.Status.CompletionTimestamp.IsZero() {
+		result.CompletionTime = &ops.Status.CompletionTimestamp.Time
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cmd/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// RestoreSpec is the spec to restore a new cluster from a backup, the fields are the same
+// as the flags of `kbcli cluster restore`.
+type RestoreSpec struct {
+	BackupName string
+	// ClusterName is the name of the new cluster, a random name is generated if it is empty
+	ClusterName string
+	Namespace   string
+	// RestoreTime is the time for point in time recovery
+	RestoreTime string
+	// VolumeRestorePolicy is one of Serial and Parallel, default is Parallel
+	VolumeRestorePolicy string
+}
+
+// RestoreResult is the result of a restore request
+type RestoreResult struct {
+	ClusterName string `json:"clusterName"`
+	Namespace   string `json:"namespace"`
+	// OpsRequest is the name of the OpsRequest which performs the restore
+	OpsRequest string `json:"opsRequest"`
+}
+
+// Restore creates an OpsRequest to restore a new cluster from the backup.
+func (c *Client) Restore(ctx context.Context, spec RestoreSpec) (*RestoreResult, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	o := &cluster.CreateRestoreOptions{
+		RestoreSpec: appsv1alpha1.RestoreSpec{
+			BackupName:          spec.BackupName,
+			RestoreTimeStr:      spec.RestoreTime,
+			VolumeRestorePolicy: spec.VolumeRestorePolicy,
+		},
+	}
+	o.CreateOptions = action.CreateOptions{
+		IOStreams:       discardStreams(),
+		Factory:         c.factory,
+		Options:         o,
+		GVR:             types.OpsGVR(),
+		CueTemplateName: "opsrequest_template.cue",
+		Quiet:           true,
+	}
+	if spec.ClusterName != "" {
+		o.Args = []string{spec.ClusterName}
+	}
+	if o.RestoreSpec.VolumeRestorePolicy == "" {
+		o.RestoreSpec.VolumeRestorePolicy = "Parallel"
+	}
+
+	if err := o.Complete(); err != nil {
+		return nil, err
+	}
+	if spec.Namespace != "" {
+		o.Namespace = spec.Namespace
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+	if err := o.Run(); err != nil {
+		return nil, err
+	}
+	return &RestoreResult{
+		ClusterName: o.ClusterRef,
+		Namespace:   o.Namespace,
+		OpsRequest:  o.OpsRequestName,
+	}, nil
+}
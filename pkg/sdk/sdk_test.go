@@ -0,0 +1,173 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("SDK", func() {
+	var (
+		tf  *cmdtesting.TestFactory
+		c   *Client
+		ctx = context.Background()
+	)
+
+	initClient := func(objs ...runtime.Object) {
+		tf.FakeDynamicClient = testing.FakeDynamicClient(objs...)
+	}
+
+	BeforeEach(func() {
+		tf = cmdtesting.NewTestFactory().WithNamespace(testing.Namespace)
+		tf.Client = &clientfake.RESTClient{}
+		c = NewClientWithFactory(tf)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("default namespace", func() {
+		Expect(c.DefaultNamespace()).Should(Equal(testing.Namespace))
+	})
+
+	It("create and get cluster", func() {
+		clusterDef := testing.FakeClusterDef()
+		resourceConstraint := testapps.NewComponentResourceConstraintFactory(testapps.DefaultResourceConstraintName).
+			AddConstraints(testapps.ProductionResourceConstraint).
+			AddSelector(appsv1alpha1.ClusterResourceConstraintSelector{
+				ClusterDefRef: clusterDef.Name,
+				Components: []appsv1alpha1.ComponentResourceConstraintSelector{
+					{
+						ComponentDefRef: testing.ComponentDefName,
+						Rules:           []string{"c1"},
+					},
+				},
+			}).
+			GetObject()
+		initClient(clusterDef,
+			testing.FakeStorageClass(testing.StorageClassName, testing.IsDefault),
+			testing.FakeClusterVersion(),
+			testing.FakeConfigMap("kubeblocks-manager-config", types.DefaultNamespace, map[string]string{"config.yaml": ""}),
+			resourceConstraint)
+
+		cls, err := c.CreateCluster(ctx, ClusterSpec{
+			Name:              "test-sdk",
+			ClusterDefinition: testing.ClusterDefName,
+			ClusterVersion:    testing.ClusterVersionName,
+			Values:            []string{fmt.Sprintf("type=%s,cpu=1,memory=1Gi", testing.ComponentDefName)},
+			Labels:            map[string]string{"foo": "bar"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cls.Name).Should(Equal("test-sdk"))
+		Expect(cls.Namespace).Should(Equal(testing.Namespace))
+		Expect(cls.ClusterDefinition).Should(Equal(testing.ClusterDefName))
+		Expect(cls.TerminationPolicy).Should(Equal(string(appsv1alpha1.Delete)))
+
+		By("get the cluster")
+		cls, err = c.GetCluster(ctx, "", "test-sdk")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cls.ClusterVersion).Should(Equal(testing.ClusterVersionName))
+
+		By("invalid spec")
+		_, err = c.CreateCluster(ctx, ClusterSpec{Name: "test-sdk"})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("backup and list backups", func() {
+		clusterObj := testing.FakeCluster(testing.ClusterName, testing.Namespace)
+		clusterObj.SetLabels(map[string]string{constant.ClusterDefLabelKey: testing.ClusterDefName})
+		pods := testing.FakePods(1, testing.Namespace, testing.ClusterName)
+		backup1 := testing.FakeBackup("backup1")
+		backup1.Labels = map[string]string{constant.AppInstanceLabelKey: testing.ClusterName}
+		backup2 := testing.FakeBackup("backup2")
+		backup2.Labels = map[string]string{constant.AppInstanceLabelKey: "other"}
+		initClient(clusterObj, testing.FakeClusterDef(), &pods.Items[0],
+			testing.FakeBackupPolicy("policy", testing.ClusterName), backup1, backup2)
+
+		result, err := c.Backup(ctx, BackupSpec{
+			ClusterName: testing.ClusterName,
+			Name:        "backup-sdk",
+			Method:      testing.BackupMethodName,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.Name).Should(Equal("backup-sdk"))
+		Expect(result.ClusterName).Should(Equal(testing.ClusterName))
+		ops := &appsv1alpha1.OpsRequest{}
+		Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, ops, types.OpsGVR(), testing.Namespace, result.OpsRequest)).Should(Succeed())
+		Expect(ops.Spec.Type).Should(Equal(appsv1alpha1.BackupType))
+
+		By("generate the backup name by the namespace in the spec")
+		tf.WithNamespace("other")
+		result, err = c.Backup(ctx, BackupSpec{
+			ClusterName: testing.ClusterName,
+			Namespace:   testing.Namespace,
+			Method:      testing.BackupMethodName,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.Name).Should(HavePrefix(fmt.Sprintf("backup-%s-%s-", testing.Namespace, testing.ClusterName)))
+		Expect(result.Namespace).Should(Equal(testing.Namespace))
+		tf.WithNamespace(testing.Namespace)
+
+		By("list backups")
+		backups, err := c.ListBackups(ctx, ListBackupsOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(backups).Should(HaveLen(2))
+		backups, err = c.ListBackups(ctx, ListBackupsOptions{ClusterName: testing.ClusterName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(backups).Should(HaveLen(1))
+		Expect(backups[0].Name).Should(Equal("backup1"))
+	})
+
+	It("restore", func() {
+		initClient()
+		result, err := c.Restore(ctx, RestoreSpec{BackupName: "backup1", ClusterName: "restored"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.ClusterName).Should(Equal("restored"))
+		ops := &appsv1alpha1.OpsRequest{}
+		Expect(cluster.GetK8SClientObject(tf.FakeDynamicClient, ops, types.OpsGVR(), testing.Namespace, result.OpsRequest)).Should(Succeed())
+		Expect(ops.Spec.RestoreSpec.BackupName).Should(Equal("backup1"))
+	})
+
+	It("cancelled context", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := c.Backup(cancelled, BackupSpec{ClusterName: testing.ClusterName})
+		Expect(err).Should(MatchError(context.Canceled))
+		_, err = c.Restore(cancelled, RestoreSpec{BackupName: "backup1"})
+		Expect(err).Should(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,148 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+// DefaultPollInterval is the interval to poll the status if it is not specified
+const DefaultPollInterval = 5 * time.Second
+
+// WaitOptions is the options of the wait helpers, the wait helpers return the error of
+// the context when it is done.
+type WaitOptions struct {
+	// Interval is the interval to poll the status, DefaultPollInterval is used if it is not positive
+	Interval time.Duration
+	// Timeout is the max duration to wait, zero means waiting until the context is done
+	Timeout time.Duration
+}
+
+func (o WaitOptions) poll(ctx context.Context, condition wait.ConditionWithContextFunc) error {
+	interval := o.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+	return wait.PollUntilContextCancel(ctx, interval, true, condition)
+}
+
+// WaitForClusterReady waits until the phase of the cluster is Running, the cluster may
+// not exist yet when it is restored from a backup.
+func (c *Client) WaitForClusterReady(ctx context.Context, namespace, name string, opts WaitOptions) (*Cluster, error) {
+	var (
+		cls   *Cluster
+		phase string
+	)
+	err := opts.poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		if cls, err = c.GetCluster(ctx, namespace, name); err != nil {
+			return false, ignoreNotFound(err)
+		}
+		phase = cls.Phase
+		return cls.Phase == string(appsv1alpha1.RunningClusterPhase), nil
+	})
+	if err != nil {
+		return cls, fmt.Errorf("failed to wait for cluster %s to be ready%s: %w", name, currentPhase(phase), err)
+	}
+	return cls, nil
+}
+
+// WaitForBackupCompleted waits until the phase of the backup is Completed, an error is
+// returned if the backup is failed. The backup may not exist yet when it is created by
+// an OpsRequest.
+func (c *Client) WaitForBackupCompleted(ctx context.Context, namespace, name string, opts WaitOptions) (*Backup, error) {
+	var (
+		backup *Backup
+		phase  string
+	)
+	err := opts.poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		if backup, err = c.GetBackup(ctx, namespace, name); err != nil {
+			return false, ignoreNotFound(err)
+		}
+		phase = backup.Phase
+		switch dpv1alpha1.BackupPhase(backup.Phase) {
+		case dpv1alpha1.BackupPhaseCompleted:
+			return true, nil
+		case dpv1alpha1.BackupPhaseFailed:
+			return false, fmt.Errorf("backup %s failed: %s", name, backup.FailureReason)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return backup, fmt.Errorf("failed to wait for backup %s to be completed%s: %w", name, currentPhase(phase), err)
+	}
+	return backup, nil
+}
+
+// WaitForOpsRequest waits until the OpsRequest is succeed, an error is returned if the
+// OpsRequest is failed or cancelled.
+func (c *Client) WaitForOpsRequest(ctx context.Context, namespace, name string, opts WaitOptions) (*OpsRequest, error) {
+	var (
+		ops   *OpsRequest
+		phase string
+	)
+	err := opts.poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		if ops, err = c.GetOpsRequest(ctx, namespace, name); err != nil {
+			return false, err
+		}
+		phase = ops.Phase
+		switch appsv1alpha1.OpsPhase(ops.Phase) {
+		case appsv1alpha1.OpsSucceedPhase:
+			return true, nil
+		case appsv1alpha1.OpsFailedPhase, appsv1alpha1.OpsCancelledPhase:
+			return false, fmt.Errorf("OpsRequest %s is %s: %s", name, ops.Phase, ops.Message)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return ops, fmt.Errorf("failed to wait for OpsRequest %s to succeed%s: %w", name, currentPhase(phase), err)
+	}
+	return ops, nil
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// currentPhase returns the description of the last observed phase used in the error message
+func currentPhase(phase string) string {
+	if phase == "" {
+		return ""
+	}
+	return fmt.Sprintf(", current phase: %s", phase)
+}
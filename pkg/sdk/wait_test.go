@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package sdk
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("SDK wait", func() {
+	var (
+		tf   *cmdtesting.TestFactory
+		c    *Client
+		ctx  = context.Background()
+		opts = WaitOptions{Interval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond}
+	)
+
+	fakeOpsRequest := func(name string, phase appsv1alpha1.OpsPhase, message string) *appsv1alpha1.OpsRequest {
+		return &appsv1alpha1.OpsRequest{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: types.AppsAPIGroup + "/" + types.AppsAPIVersion,
+				Kind:       types.KindOps,
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testing.Namespace},
+			Spec: appsv1alpha1.OpsRequestSpec{
+				ClusterRef: testing.ClusterName,
+				Type:       appsv1alpha1.RestartType,
+			},
+			Status: appsv1alpha1.OpsRequestStatus{
+				Phase:      phase,
+				Progress:   "0/1",
+				Conditions: []metav1.Condition{{Type: "Failed", Message: message}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		tf = cmdtesting.NewTestFactory().WithNamespace(testing.Namespace)
+		tf.Client = &clientfake.RESTClient{}
+		c = NewClientWithFactory(tf)
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("wait for cluster ready", func() {
+		running := testing.FakeCluster("running", testing.Namespace)
+		creating := testing.FakeCluster("creating", testing.Namespace)
+		creating.Status.Phase = appsv1alpha1.CreatingClusterPhase
+		tf.FakeDynamicClient = testing.FakeDynamicClient(running, creating)
+
+		cls, err := c.WaitForClusterReady(ctx, "", "running", opts)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cls.Phase).Should(Equal(string(appsv1alpha1.RunningClusterPhase)))
+
+		By("timed out with the last observed phase")
+		_, err = c.WaitForClusterReady(ctx, "", "creating", opts)
+		Expect(err).Should(MatchError(context.DeadlineExceeded))
+		Expect(err.Error()).Should(ContainSubstring("current phase: Creating"))
+
+		By("cancelled before the cluster is found")
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		cls, err = c.WaitForClusterReady(cancelled, "", "not-exist", WaitOptions{})
+		Expect(err).Should(MatchError(context.Canceled))
+		Expect(cls).Should(BeNil())
+	})
+
+	It("wait for backup completed", func() {
+		completed := testing.FakeBackupWithPhase("completed", dpv1alpha1.BackupPhaseCompleted)
+		failed := testing.FakeBackupWithPhase("failed", dpv1alpha1.BackupPhaseFailed)
+		failed.Status.FailureReason = "no space left"
+		tf.FakeDynamicClient = testing.FakeDynamicClient(completed, failed)
+
+		backup, err := c.WaitForBackupCompleted(ctx, "", "completed", opts)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(backup.Name).Should(Equal("completed"))
+
+		backup, err = c.WaitForBackupCompleted(ctx, "", "failed", opts)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("no space left"))
+		Expect(backup.FailureReason).Should(Equal("no space left"))
+	})
+
+	It("wait for OpsRequest", func() {
+		tf.FakeDynamicClient = testing.FakeDynamicClient(
+			fakeOpsRequest("succeed", appsv1alpha1.OpsSucceedPhase, ""),
+			fakeOpsRequest("failed", appsv1alpha1.OpsFailedPhase, "pod is crashing"),
+			fakeOpsRequest("running", appsv1alpha1.OpsRunningPhase, ""))
+
+		ops, err := c.WaitForOpsRequest(ctx, "", "succeed", opts)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ops.Type).Should(Equal(string(appsv1alpha1.RestartType)))
+		Expect(ops.ClusterName).Should(Equal(testing.ClusterName))
+
+		_, err = c.WaitForOpsRequest(ctx, "", "failed", opts)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("pod is crashing"))
+
+		_, err = c.WaitForOpsRequest(ctx, "", "running", opts)
+		Expect(err).Should(MatchError(context.DeadlineExceeded))
+		Expect(err.Error()).Should(ContainSubstring("current phase: Running"))
+
+		_, err = c.WaitForOpsRequest(ctx, "", "not-exist", opts)
+		Expect(err).Should(HaveOccurred())
+	})
+})
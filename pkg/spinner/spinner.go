@@ -127,6 +127,9 @@ func (s *Spinner) Fail() {
 }
 
 func New(w io.Writer, opts ...Option) Interface {
+	if printer.Quiet() {
+		return &quietSpinner{}
+	}
 	if util.IsWindows() {
 		return NewWindowsSpinner(w, opts...)
 	}
@@ -154,3 +157,14 @@ func New(w io.Writer, opts ...Option) Interface {
 	res.Start()
 	return res
 }
+
+// quietSpinner is the spinner printing nothing in the quiet mode
+type quietSpinner struct{}
+
+func (s *quietSpinner) Start()                          {}
+func (s *quietSpinner) Done(string)                     {}
+func (s *quietSpinner) Success()                        {}
+func (s *quietSpinner) Fail()                           {}
+func (s *quietSpinner) SetMessage(string)               {}
+func (s *quietSpinner) SetFinalMsg(string)              {}
+func (s *quietSpinner) updateSpinnerMessage(msg string) {}
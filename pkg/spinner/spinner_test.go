@@ -20,9 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package spinner
 
 import (
+	"bytes"
 	"os"
 
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/kbcli/pkg/printer"
 )
 
 var _ = Describe("Spinner", func() {
@@ -33,4 +37,14 @@ var _ = Describe("Spinner", func() {
 		s = New(os.Stdout, WithMessage("spinner test ... "))
 		s.Fail()
 	})
+
+	It("prints nothing in the quiet mode", func() {
+		printer.SetQuiet(true)
+		defer printer.SetQuiet(false)
+		out := &bytes.Buffer{}
+		s := New(out, WithMessage("spinner test ... "))
+		s.SetMessage("spinner test again ...")
+		s.Success()
+		Expect(out.String()).Should(BeEmpty())
+	})
 })
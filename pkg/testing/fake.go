@@ -70,6 +70,9 @@ const (
 	BackupMethodName    = "fake-backup-method"
 	ActionSetName       = "fake-action-set"
 	BackupName          = "fake-backup-name"
+	BackupPolicyName    = "fake-backup-policy"
+	BackupRepoName      = "fake-backup-repo"
+	KBToolsImage        = "docker.io/apecloud/kubeblocks-tools:0.8.0"
 
 	IsDefault    = true
 	IsNotDefault = false
@@ -481,8 +484,39 @@ func FakeClusterVersion() *appsv1alpha1.ClusterVersion {
 }
 
 func FakeActionSet() *dpv1alpha1.ActionSet {
-	as := &dpv1alpha1.ActionSet{}
-	as.Name = ActionSetName
+	as := &dpv1alpha1.ActionSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fmt.Sprintf("%s/%s", types.DPAPIGroup, types.DPAPIVersion),
+			Kind:       types.KindActionSet,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ActionSetName,
+			Labels: map[string]string{
+				constant.ClusterDefLabelKey: ClusterDefName,
+			},
+		},
+		Spec: dpv1alpha1.ActionSetSpec{
+			BackupType: dpv1alpha1.BackupTypeFull,
+			Backup: &dpv1alpha1.BackupActionSpec{
+				BackupData: &dpv1alpha1.BackupDataActionSpec{
+					JobActionSpec: dpv1alpha1.JobActionSpec{
+						Image:   "fake-backup-image",
+						Command: []string{"sh", "-c", "backup.sh"},
+					},
+				},
+			},
+			Restore: &dpv1alpha1.RestoreActionSpec{
+				PrepareData: &dpv1alpha1.JobActionSpec{
+					Image:   "fake-backup-image",
+					Command: []string{"sh", "-c", "restore.sh"},
+				},
+			},
+		},
+		Status: dpv1alpha1.ActionSetStatus{
+			Phase: dpv1alpha1.AvailablePhase,
+		},
+	}
+	as.SetCreationTimestamp(metav1.Now())
 	return as
 }
 
@@ -543,6 +577,77 @@ func FakeBackup(backupName string) *dpv1alpha1.Backup {
 	return backup
 }
 
+// FakeBackupWithPhase returns a backup of the fake cluster whose status is filled as the backup controller
+// does when the backup is in the specified phase.
+func FakeBackupWithPhase(backupName string, phase dpv1alpha1.BackupPhase) *dpv1alpha1.Backup {
+	backup := FakeBackup(backupName)
+	backup.Labels = map[string]string{
+		constant.AppInstanceLabelKey:    ClusterName,
+		constant.KBAppComponentLabelKey: ComponentName,
+	}
+	backup.Spec = dpv1alpha1.BackupSpec{
+		BackupPolicyName: BackupPolicyName,
+		BackupMethod:     BackupMethodName,
+	}
+	backup.Status.Phase = phase
+	if phase == dpv1alpha1.BackupPhaseNew {
+		return backup
+	}
+
+	startTime := metav1.NewTime(backup.CreationTimestamp.Add(time.Second))
+	backup.Status.StartTimestamp = &startTime
+	backup.Status.BackupRepoName = BackupRepoName
+	backup.Status.Path = fmt.Sprintf("/%s/%s/%s", Namespace, ClusterName, backupName)
+	switch phase {
+	case dpv1alpha1.BackupPhaseCompleted:
+		completionTime := metav1.NewTime(startTime.Add(time.Minute))
+		expiration := metav1.NewTime(completionTime.Add(24 * time.Hour))
+		backup.Status.CompletionTimestamp = &completionTime
+		backup.Status.Duration = &metav1.Duration{Duration: completionTime.Sub(startTime.Time)}
+		backup.Status.Expiration = &expiration
+		backup.Status.TotalSize = "1073741824"
+	case dpv1alpha1.BackupPhaseFailed:
+		completionTime := metav1.NewTime(startTime.Add(time.Minute))
+		backup.Status.CompletionTimestamp = &completionTime
+		backup.Status.Duration = &metav1.Duration{Duration: completionTime.Sub(startTime.Time)}
+		backup.Status.FailureReason = "fake failure reason"
+	}
+	return backup
+}
+
+// FakeRestore returns a completed restore from the specified backup.
+func FakeRestore(restoreName, backupName string) *dpv1alpha1.Restore {
+	restore := &dpv1alpha1.Restore{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fmt.Sprintf("%s/%s", types.DPAPIGroup, types.DPAPIVersion),
+			Kind:       types.KindRestore,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restoreName,
+			Namespace: Namespace,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey: ClusterName,
+			},
+		},
+		Spec: dpv1alpha1.RestoreSpec{
+			Backup: dpv1alpha1.BackupRef{
+				Name:      backupName,
+				Namespace: Namespace,
+			},
+		},
+	}
+	restore.SetCreationTimestamp(metav1.Now())
+	startTime := metav1.NewTime(restore.CreationTimestamp.Add(time.Second))
+	completionTime := metav1.NewTime(startTime.Add(time.Minute))
+	restore.Status = dpv1alpha1.RestoreStatus{
+		Phase:               dpv1alpha1.RestorePhaseCompleted,
+		StartTimestamp:      &startTime,
+		CompletionTimestamp: &completionTime,
+		Duration:            &metav1.Duration{Duration: completionTime.Sub(startTime.Time)},
+	}
+	return restore
+}
+
 func FakeBackupSchedule(backupScheduleName, backupPolicyName string) *dpv1alpha1.BackupSchedule {
 	backupSchedule := &dpv1alpha1.BackupSchedule{
 		TypeMeta: metav1.TypeMeta{
@@ -571,6 +676,14 @@ func FakeBackupSchedule(backupScheduleName, backupPolicyName string) *dpv1alpha1
 			Phase: dpv1alpha1.BackupSchedulePhaseAvailable,
 		},
 	}
+	lastScheduleTime := metav1.NewTime(time.Now().Truncate(24 * time.Hour))
+	backupSchedule.Status.Schedules = map[string]dpv1alpha1.ScheduleStatus{
+		BackupMethodName: {
+			Phase:              dpv1alpha1.ScheduleRunning,
+			LastScheduleTime:   &lastScheduleTime,
+			LastSuccessfulTime: &lastScheduleTime,
+		},
+	}
 	return backupSchedule
 }
 
@@ -747,6 +860,12 @@ func FakeKBDeploy(version string) *appsv1.Deployment {
 	if len(version) > 0 {
 		deploy.Labels["app.kubernetes.io/version"] = version
 	}
+	deploy.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name: types.KubeBlocksChartName,
+			Env:  []corev1.EnvVar{{Name: constant.KBToolsImage, Value: KBToolsImage}},
+		},
+	}
 	return deploy
 }
 
@@ -22,6 +22,8 @@ package testing
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 )
 
 var _ = Describe("test fake", func() {
@@ -93,4 +95,28 @@ var _ = Describe("test fake", func() {
 		StorageClassNotDefault := FakeStorageClass(StorageClassName, IsDefault)
 		Expect(StorageClassNotDefault).ShouldNot(BeNil())
 	})
+
+	It("fake dataprotection objects", func() {
+		completed := FakeBackupWithPhase(BackupName, dpv1alpha1.BackupPhaseCompleted)
+		Expect(completed.Spec.BackupMethod).Should(Equal(BackupMethodName))
+		Expect(completed.Status.CompletionTimestamp).ShouldNot(BeNil())
+		Expect(completed.Status.Duration.Duration).ShouldNot(BeZero())
+		Expect(completed.Status.TotalSize).ShouldNot(BeEmpty())
+		failed := FakeBackupWithPhase(BackupName, dpv1alpha1.BackupPhaseFailed)
+		Expect(failed.Status.FailureReason).ShouldNot(BeEmpty())
+		Expect(FakeBackupWithPhase(BackupName, dpv1alpha1.BackupPhaseNew).Status.StartTimestamp).Should(BeNil())
+
+		restore := FakeRestore("fake-restore", BackupName)
+		Expect(restore.Spec.Backup.Name).Should(Equal(BackupName))
+		Expect(restore.Status.Phase).Should(Equal(dpv1alpha1.RestorePhaseCompleted))
+
+		actionSet := FakeActionSet()
+		Expect(actionSet.Spec.Backup.BackupData.Image).ShouldNot(BeEmpty())
+		Expect(actionSet.Status.Phase).Should(Equal(dpv1alpha1.AvailablePhase))
+
+		schedule := FakeBackupSchedule("fake-schedule", BackupPolicyName)
+		Expect(schedule.Status.Schedules).Should(HaveKey(BackupMethodName))
+
+		Expect(FakeDynamicClient(completed, restore, actionSet, schedule)).ShouldNot(BeNil())
+	})
 })
@@ -0,0 +1,204 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+)
+
+const (
+	LorryContainerName = "lorry"
+	LorryHTTPPort      = 3501
+)
+
+var lorryCurlRegex = regexp.MustCompile(`curl .* -X (\w+) .* http://localhost:\d+/v1.0/(\w+)(?: -d '(.*)')?$`)
+
+// FakeLorryRequest is the lorry request received by the FakeLorryExecutor
+type FakeLorryRequest struct {
+	Pod        string
+	Operation  string
+	Method     string
+	Parameters map[string]any
+}
+
+// FakeLorryResponse is the scripted response of a lorry operation, the Body is written to
+// the stdout as JSON, and the exec fails with the Error as lorry does if it is not empty.
+type FakeLorryResponse struct {
+	Body  map[string]any
+	Error string
+}
+
+// FakeLorryExecutor is a fake cmdexec.RemoteExecutor that parses the curl command sent to lorry
+// and responds with the scripted responses instead of exec into a real pod. The operations not
+// scripted succeed with an empty response.
+type FakeLorryExecutor struct {
+	mu        sync.Mutex
+	responses map[string]FakeLorryResponse
+	requests  []FakeLorryRequest
+}
+
+func NewFakeLorryExecutor() *FakeLorryExecutor {
+	return &FakeLorryExecutor{responses: map[string]FakeLorryResponse{}}
+}
+
+// WithResponse scripts the response body of the lorry operation, such as listUsers
+func (e *FakeLorryExecutor) WithResponse(operation string, body map[string]any) *FakeLorryExecutor {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses[strings.ToLower(operation)] = FakeLorryResponse{Body: body}
+	return e
+}
+
+// WithPodResponse scripts the response body of the lorry operation of the pod, it takes precedence
+// over the response scripted by WithResponse.
+func (e *FakeLorryExecutor) WithPodResponse(pod string, operation string, body map[string]any) *FakeLorryExecutor {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses[pod+"/"+strings.ToLower(operation)] = FakeLorryResponse{Body: body}
+	return e
+}
+
+// WithPodError scripts the lorry operation of the pod to fail with the message
+func (e *FakeLorryExecutor) WithPodError(pod string, operation string, message string) *FakeLorryExecutor {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses[pod+"/"+strings.ToLower(operation)] = FakeLorryResponse{Error: message}
+	return e
+}
+
+// WithError scripts the lorry operation to fail with the message
+func (e *FakeLorryExecutor) WithError(operation string, message string) *FakeLorryExecutor {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses[strings.ToLower(operation)] = FakeLorryResponse{Error: message}
+	return e
+}
+
+// Requests returns the lorry requests received in order
+func (e *FakeLorryExecutor) Requests() []FakeLorryRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]FakeLorryRequest{}, e.requests...)
+}
+
+func (e *FakeLorryExecutor) Execute(_ string, url *url.URL, _ *restclient.Config, _ io.Reader, stdout, _ io.Writer, _ bool, _ remotecommand.TerminalSizeQueue) error {
+	command := url.Query()["command"]
+	if len(command) == 0 {
+		return fmt.Errorf("no command to execute")
+	}
+	matches := lorryCurlRegex.FindStringSubmatch(command[len(command)-1])
+	if matches == nil {
+		return fmt.Errorf("unexpected lorry command: %s", strings.Join(command, " "))
+	}
+	// the operation in the url is in lower case, and the path is /api/v1/namespaces/{namespace}/pods/{name}/exec
+	segments := strings.Split(url.Path, "/")
+	req := FakeLorryRequest{Operation: matches[2], Method: matches[1]}
+	if len(segments) > 2 {
+		req.Pod = segments[len(segments)-2]
+	}
+	if len(matches[3]) > 0 {
+		body := map[string]any{}
+		if err := json.Unmarshal([]byte(strings.ReplaceAll(matches[3], "\\'", "'")), &body); err != nil {
+			return err
+		}
+		if params, ok := body["parameters"].(map[string]any); ok {
+			req.Parameters = params
+		}
+	}
+
+	e.mu.Lock()
+	resp, ok := e.responses[req.Pod+"/"+req.Operation]
+	if !ok {
+		resp = e.responses[req.Operation]
+	}
+	e.requests = append(e.requests, req)
+	e.mu.Unlock()
+
+	if resp.Error != "" {
+		fmt.Fprintf(stdout, `{"errorCode":"ERR_OPERATION_FAILED","message":%q}`, resp.Error)
+		// curl --fail-with-body exits with 22 if the server responds with error
+		return utilexec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 22"), Code: 22}
+	}
+	if resp.Body != nil {
+		return json.NewEncoder(stdout).Encode(resp.Body)
+	}
+	return nil
+}
+
+// FakeLorryContainer returns the lorry container which the lorry client exec into
+func FakeLorryContainer() corev1.Container {
+	return corev1.Container{
+		Name:    LorryContainerName,
+		Image:   "fake-lorry-image",
+		Command: []string{"lorry", "--port", fmt.Sprintf("%d", LorryHTTPPort)},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          constant.LorryHTTPPortName,
+				ContainerPort: LorryHTTPPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+	}
+}
+
+// FakeLorryUsers returns the users responded by the lorry listUsers operation
+func FakeLorryUsers(names ...string) map[string]any {
+	users := make([]any, 0, len(names))
+	for _, name := range names {
+		users = append(users, map[string]any{"userName": name, "expired": "F", "roleName": "readonly"})
+	}
+	return map[string]any{"users": users}
+}
+
+// WriteFakeKubeConfig writes a kubeconfig to the dir, the lorry exec client loads it from
+// the KUBECONFIG environment variable to build the rest config, and no request is sent to
+// the server with the FakeLorryExecutor.
+func WriteFakeKubeConfig(dir string) (string, error) {
+	file := filepath.Join(dir, "kubeconfig")
+	content := `apiVersion: v1
+kind: Config
+clusters:
+- name: fake-cluster
+  cluster:
+    server: http://localhost:8080
+contexts:
+- name: fake-context
+  context:
+    cluster: fake-cluster
+    namespace: ` + Namespace + `
+current-context: fake-context
+`
+	return file, os.WriteFile(file, []byte(content), 0600)
+}
@@ -26,4 +26,36 @@ const (
 	CfgKeyClusterDefaultCPU         = "CLUSTER_DEFAULT_CPU"
 	CfgKeyClusterDefaultMemory      = "CLUSTER_DEFAULT_MEMORY"
 	CfgKeyHelmRepoURL               = "HELM_REPO_URL"
+
+	// the defaults of the flags, which can be set by `kbcli config set`
+	CfgKeyDefaultNamespace          = "namespace"
+	CfgKeyDefaultOutput             = "output"
+	CfgKeyDefaultTerminationPolicy  = "termination-policy"
+	CfgKeyDefaultMonitoringInterval = "monitoring-interval"
+	CfgKeyEditor                    = "editor"
+	CfgKeyAuditLog                  = "audit-log"
+	CfgKeyAuditEvents               = "audit-events"
+	CfgKeyColor                     = "color"
+	CfgKeyStatusColors              = "status-colors"
+	CfgKeyAutoNamespace             = "auto-namespace"
+	CfgKeyDiscoveryCacheTTL         = "discovery-cache-ttl"
+	CfgKeyRequestTimeout            = "request-timeout"
+	CfgKeyMaxRetries                = "max-retries"
+	CfgKeyClientQPS                 = "client-qps"
+	CfgKeyClientBurst               = "client-burst"
+	CfgKeyCompletionCacheTTL        = "completion-cache-ttl"
+	CfgKeyTimezone                  = "timezone"
+	CfgKeyTimeFormat                = "time-format"
+	CfgKeyImageRegistry             = "image-registry"
+)
+
+const (
+	// CliConfigFile is the kbcli config file name under the kbcli home dir
+	CliConfigFile = "config.yaml"
+	// CliHistoryFile is the audit log file name under the kbcli home dir
+	CliHistoryFile = "history.log"
+	// CliBenchSummaryDir is the dir under the kbcli home dir storing the benchmark summaries
+	CliBenchSummaryDir = "bench"
+	// CliCapacityHistoryDir is the dir under the kbcli home dir storing the volume usage snapshots of clusters
+	CliCapacityHistoryDir = "capacity"
 )
@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package types
+
+import (
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+)
+
+// NegotiatedAPIVersions are the served versions of the KubeBlocks objects that kbcli can select, in
+// the order of preference.
+var NegotiatedAPIVersions = []string{"v1", "v1beta1", "v1alpha1"}
+
+// negotiatedAPIGroups are the API groups of the KubeBlocks objects whose versions are negotiated
+var negotiatedAPIGroups = map[string]bool{
+	AppsAPIGroup:                 true,
+	DPAPIGroup:                   true,
+	ExtensionsAPIGroup:           true,
+	StorageAPIGroup:              true,
+	workloads.GroupVersion.Group: true,
+}
+
+// gvrRegistry records the served versions of the KubeBlocks resources on the connected cluster,
+// the versions are discovered lazily when a GVR is resolved for the first time.
+type gvrRegistry struct {
+	once      sync.Once
+	discovery func() (discovery.DiscoveryInterface, error)
+	// served is the served versions of the resources
+	served map[schema.GroupResource]map[string]bool
+	// switched records the GVRs whose versions are switched from the compiled ones by the negotiation
+	switched sync.Map
+}
+
+var registry = &gvrRegistry{}
+
+// SetAPIDiscovery sets the discovery client to negotiate the API versions of the KubeBlocks objects,
+// the previously negotiated versions are reset.
+func SetAPIDiscovery(f func() (discovery.DiscoveryInterface, error)) {
+	registry = &gvrRegistry{discovery: f}
+}
+
+// NegotiateAPIVersions discovers the served versions of the KubeBlocks objects by the discovery client.
+func NegotiateAPIVersions(client discovery.DiscoveryInterface) error {
+	served, err := discoverServedVersions(client)
+	if err != nil {
+		return err
+	}
+	r := &gvrRegistry{served: served}
+	r.once.Do(func() {})
+	registry = r
+	return nil
+}
+
+// ResolveGVR returns the GVR with the version served by the connected cluster. The default version of
+// the GVR is used if it's served or the versions are not discovered, otherwise the most preferred
+// served version in NegotiatedAPIVersions is selected, so kbcli keeps working after the API is graduated.
+// The objects built by kbcli are in the compiled versions, so the switched versions are only readable, the
+// writes to them are rejected by CheckWritable.
+func ResolveGVR(gvr schema.GroupVersionResource) schema.GroupVersionResource {
+	if !negotiatedAPIGroups[gvr.Group] {
+		return gvr
+	}
+	r := registry
+	r.once.Do(func() {
+		if r.discovery == nil {
+			return
+		}
+		client, err := r.discovery()
+		if err == nil {
+			r.served, err = discoverServedVersions(client)
+		}
+		if err != nil {
+			klog.V(1).Infof("failed to negotiate the API versions, use the default versions: %v", err)
+		}
+	})
+
+	versions := r.served[gvr.GroupResource()]
+	if len(versions) == 0 || versions[gvr.Version] {
+		return gvr
+	}
+	for _, v := range NegotiatedAPIVersions {
+		if versions[v] {
+			klog.V(1).Infof("the version %s of %s is not served, use %s instead", gvr.Version, gvr.GroupResource(), v)
+			compiled := gvr.Version
+			gvr.Version = v
+			r.switched.Store(gvr, compiled)
+			return gvr
+		}
+	}
+	return gvr
+}
+
+// CheckWritable returns an error if the version of the GVR is switched from the compiled one by the
+// negotiation, the objects built by kbcli are in the compiled version and can't be written to it.
+func CheckWritable(gvr schema.GroupVersionResource) error {
+	compiled, ok := registry.switched.Load(gvr)
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf("%s is served in version %s instead of %s by the connected KubeBlocks, kbcli can only read it, "+
+		"upgrade kbcli to the version matching KubeBlocks to change it", gvr.GroupResource(), gvr.Version, compiled)
+}
+
+func discoverServedVersions(client discovery.DiscoveryInterface) (map[schema.GroupResource]map[string]bool, error) {
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	supported := map[string]bool{}
+	for _, v := range NegotiatedAPIVersions {
+		supported[v] = true
+	}
+
+	served := map[schema.GroupResource]map[string]bool{}
+	for _, group := range groups.Groups {
+		if !negotiatedAPIGroups[group.Name] {
+			continue
+		}
+		for _, version := range group.Versions {
+			if !supported[version.Version] {
+				continue
+			}
+			resources, err := client.ServerResourcesForGroupVersion(version.GroupVersion)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			for _, resource := range resources.APIResources {
+				gr := schema.GroupResource{Group: group.Name, Resource: resource.Name}
+				if served[gr] == nil {
+					served[gr] = map[string]bool{}
+				}
+				served[gr][version.Version] = true
+			}
+		}
+	}
+	return served, nil
+}
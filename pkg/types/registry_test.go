@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package types
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("gvr registry", func() {
+	newDiscovery := func(resources ...*metav1.APIResourceList) discovery.DiscoveryInterface {
+		return &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}}
+	}
+	resourceList := func(groupVersion string, names ...string) *metav1.APIResourceList {
+		list := &metav1.APIResourceList{GroupVersion: groupVersion}
+		for _, name := range names {
+			list.APIResources = append(list.APIResources, metav1.APIResource{Name: name})
+		}
+		return list
+	}
+
+	AfterEach(func() {
+		SetAPIDiscovery(nil)
+	})
+
+	It("use the default versions if not negotiated", func() {
+		Expect(ClusterGVR().Version).Should(Equal(AppsAPIVersion))
+		SetAPIDiscovery(func() (discovery.DiscoveryInterface, error) {
+			return nil, errors.New("cluster unreachable")
+		})
+		Expect(ClusterGVR().Version).Should(Equal(AppsAPIVersion))
+	})
+
+	It("select the served versions", func() {
+		Expect(NegotiateAPIVersions(newDiscovery(
+			resourceList("apps.kubeblocks.io/v1alpha1", ResourceClusters, ResourceOpsRequests),
+			resourceList("apps.kubeblocks.io/v1", ResourceClusters, ResourceClusterDefs),
+			resourceList("apps.kubeblocks.io/v1beta1", ResourceClusterDefs, ResourceComponentDefs),
+			resourceList("dataprotection.kubeblocks.io/v1alpha1", ResourceBackups),
+			resourceList("v1", ResourcePods),
+		))).Should(Succeed())
+
+		By("the default version is served")
+		Expect(ClusterGVR().Version).Should(Equal("v1alpha1"))
+		Expect(OpsGVR().Version).Should(Equal("v1alpha1"))
+		Expect(BackupGVR().Version).Should(Equal("v1alpha1"))
+
+		By("the default version is not served")
+		Expect(ClusterDefGVR().Version).Should(Equal("v1"))
+		Expect(CompDefGVR().Version).Should(Equal("v1beta1"))
+
+		By("the resource is not installed")
+		Expect(AddonGVR().Version).Should(Equal(ExtensionsAPIVersion))
+		Expect(PodGVR().Version).Should(Equal(K8sCoreAPIVersion))
+
+		By("the switched versions are not writable")
+		Expect(CheckWritable(ClusterGVR())).Should(Succeed())
+		Expect(CheckWritable(ClusterDefGVR())).Should(MatchError(ContainSubstring("instead of v1alpha1")))
+		Expect(CheckWritable(PodGVR())).Should(Succeed())
+	})
+
+	It("negotiate the versions lazily", func() {
+		calls := 0
+		SetAPIDiscovery(func() (discovery.DiscoveryInterface, error) {
+			calls++
+			return newDiscovery(resourceList("apps.kubeblocks.io/v1", ResourceClusters)), nil
+		})
+		Expect(calls).Should(BeZero())
+		Expect(PodGVR().Version).Should(Equal(K8sCoreAPIVersion))
+		Expect(calls).Should(BeZero())
+		Expect(ClusterGVR().Version).Should(Equal("v1"))
+		Expect(ClusterGVR().Version).Should(Equal("v1"))
+		Expect(calls).Should(Equal(1))
+	})
+})
@@ -26,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -105,6 +106,7 @@ const (
 	ResourceClusterDefs                 = "clusterdefinitions"
 	ResourceClusterVersions             = "clusterversions"
 	ResourceComponentDefs               = "componentdefinitions"
+	ResourceComponents                  = "components"
 	ResourceOpsRequests                 = "opsrequests"
 	ResourceConfigConstraintVersions    = "configconstraints"
 	ResourceComponentResourceConstraint = "componentresourceconstraints"
@@ -119,6 +121,7 @@ const (
 	KindBackupPolicy                    = "BackupPolicy"
 	KindOps                             = "OpsRequest"
 	KindBackupSchedule                  = "BackupSchedule"
+	KindActionSet                       = "ActionSet"
 	KindBackupPolicyTemplate            = "BackupPolicyTemplate"
 	KindStatefulSet                     = "StatefulSet"
 	KindDeployment                      = "Deployment"
@@ -150,6 +153,14 @@ const (
 	ReloadConfigMapAnnotationKey = "kubeblocks.io/reload-configmap" // mark an annotation to load configmap
 
 	KBVersionValidateAnnotationKey = "addon.kubeblocks.io/kubeblocks-version"
+	// AddonSourceAnnotationKey records the external Helm repo or OCI registry an addon is installed from
+	AddonSourceAnnotationKey = "addon.kubeblocks.io/source"
+	// AddonDependenciesAnnotationKey declares the comma separated addon names that an addon depends on
+	AddonDependenciesAnnotationKey = "addon.kubeblocks.io/dependencies"
+	// AddonLabelPrefix is the prefix of the addon labels
+	AddonLabelPrefix = "addon.kubeblocks.io"
+	// AddonCommunityProvider is the provider of the addons installed from an external source
+	AddonCommunityProvider = "community"
 )
 
 // DataProtection API group
@@ -205,6 +216,20 @@ const (
 	ResourceTpch        = "tpches"
 )
 
+// Velero API group
+const (
+	VeleroAPIGroup   = "velero.io"
+	VeleroAPIVersion = "v1"
+)
+
+// Prometheus operator API group
+const (
+	MonitoringAPIGroup   = "monitoring.coreos.com"
+	MonitoringAPIVersion = "v1"
+	ResourcePodMonitors  = "podmonitors"
+	KindPodMonitor       = "PodMonitor"
+)
+
 // Workload API group
 const (
 	ResourceRSM = "replicatedstatemachines"
@@ -266,67 +291,71 @@ func PodGVR() schema.GroupVersionResource {
 }
 
 func ClusterGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceClusters}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceClusters})
 }
 
 func ClusterDefGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceClusterDefs}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceClusterDefs})
 }
 
 func ClusterVersionGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceClusterVersions}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceClusterVersions})
 }
 
 func CompDefGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponentDefs}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponentDefs})
+}
+
+func ComponentGVR() schema.GroupVersionResource {
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponents})
 }
 
 func OpsGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceOpsRequests}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceOpsRequests})
 }
 
 func BackupGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackups}
+	return ResolveGVR(schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackups})
 }
 
 func BackupPolicyGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupPolicies}
+	return ResolveGVR(schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupPolicies})
 }
 
 func BackupPolicyTemplateGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupTemplates}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupTemplates})
 }
 
 func BackupScheduleGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupSchedules}
+	return ResolveGVR(schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupSchedules})
 }
 
 func ActionSetGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceActionSets}
+	return ResolveGVR(schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceActionSets})
 }
 
 func BackupRepoGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupRepos}
+	return ResolveGVR(schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceBackupRepos})
 }
 
 func RestoreGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceRestores}
+	return ResolveGVR(schema.GroupVersionResource{Group: DPAPIGroup, Version: DPAPIVersion, Resource: ResourceRestores})
 }
 
 func AddonGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: ExtensionsAPIGroup, Version: ExtensionsAPIVersion, Resource: ResourceAddons}
+	return ResolveGVR(schema.GroupVersionResource{Group: ExtensionsAPIGroup, Version: ExtensionsAPIVersion, Resource: ResourceAddons})
 }
 
 func StorageProviderGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: StorageAPIGroup, Version: StorageAPIVersion, Resource: ResourceStorageProviders}
+	return ResolveGVR(schema.GroupVersionResource{Group: StorageAPIGroup, Version: StorageAPIVersion, Resource: ResourceStorageProviders})
 }
 
 func ComponentResourceConstraintGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponentResourceConstraint}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponentResourceConstraint})
 }
 
 func ComponentClassDefinitionGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponentClassDefinition}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceComponentClassDefinition})
 }
 
 func CRDGVR() schema.GroupVersionResource {
@@ -350,7 +379,7 @@ func StatefulSetGVR() schema.GroupVersionResource {
 }
 
 func RSMGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: workloads.GroupVersion.Group, Version: workloads.GroupVersion.Version, Resource: ResourceRSM}
+	return ResolveGVR(schema.GroupVersionResource{Group: workloads.GroupVersion.Group, Version: workloads.GroupVersion.Version, Resource: ResourceRSM})
 }
 
 func DaemonSetGVR() schema.GroupVersionResource {
@@ -374,7 +403,7 @@ func PVGVR() schema.GroupVersionResource {
 }
 
 func ConfigConstraintGVR() schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceConfigConstraintVersions}
+	return ResolveGVR(schema.GroupVersionResource{Group: AppsAPIGroup, Version: AppsAPIVersion, Resource: ResourceConfigConstraintVersions})
 }
 
 func StorageClassGVR() schema.GroupVersionResource {
@@ -393,6 +422,14 @@ func VolumeSnapshotClassGVR() schema.GroupVersionResource {
 	}
 }
 
+func VolumeSnapshotGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "snapshot.storage.k8s.io",
+		Version:  K8sCoreAPIVersion,
+		Resource: "volumesnapshots",
+	}
+}
+
 func ValidatingWebhookConfigurationGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Group:    WebhookAPIGroup,
@@ -428,6 +465,10 @@ func ServiceAccountGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: corev1.GroupName, Version: K8sCoreAPIVersion, Resource: ServiceAccounts}
 }
 
+func NetworkPolicyGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: networkingv1.GroupName, Version: "v1", Resource: "networkpolicies"}
+}
+
 func MigrationTaskGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Group:    MigrationAPIGroup,
@@ -478,3 +519,23 @@ func TpccGVR() schema.GroupVersionResource {
 func TpchGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: KubebenchAPIGroup, Version: KubebenchAPIVersion, Resource: ResourceTpch}
 }
+
+func CertificateGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+}
+
+func VeleroScheduleGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: VeleroAPIGroup, Version: VeleroAPIVersion, Resource: "schedules"}
+}
+
+func VeleroRestoreGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: VeleroAPIGroup, Version: VeleroAPIVersion, Resource: "restores"}
+}
+
+func VeleroBackupGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: VeleroAPIGroup, Version: VeleroAPIVersion, Resource: "backups"}
+}
+
+func PodMonitorGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: MonitoringAPIGroup, Version: MonitoringAPIVersion, Resource: ResourcePodMonitors}
+}
@@ -21,12 +21,16 @@ package util
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -34,9 +38,15 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	utilcomp "k8s.io/kubectl/pkg/util/completion"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
 	"github.com/apecloud/kbcli/pkg/types"
 )
 
+// CompletionFunc is the function to complete the arguments or flag values of a command
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
 func ResourceNameCompletionFunc(f cmdutil.Factory, gvr schema.GroupVersionResource) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		comps := utilcomp.CompGetResource(f, GVRToString(gvr), toComplete)
@@ -120,10 +130,86 @@ func RegisterClusterCompletionFunc(cmd *cobra.Command, f cmdutil.Factory) {
 		return
 	}
 
-	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(
-		"cluster",
+	RegisterFlagCompletionFunc(cmd, "cluster",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return utilcomp.CompGetResource(f, GVRToString(types.ClusterGVR()), toComplete), cobra.ShellCompDirectiveNoFileComp
-		},
-	))
+		})
+}
+
+// RegisterFlagCompletionFunc registers the completion function for the flag with the completion
+// cache, it does nothing if the command has no such flag.
+func RegisterFlagCompletionFunc(cmd *cobra.Command, flag string, fn CompletionFunc) {
+	if cmd.Flag(flag) == nil {
+		return
+	}
+	cmdutil.CheckErr(cmd.RegisterFlagCompletionFunc(flag, CachedCompletionFunc(flag, fn)))
+}
+
+// ValuesCompletionFunc returns the completion function completing the fixed values.
+func ValuesCompletionFunc(values ...string) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var comps []string
+		for _, v := range values {
+			if strings.HasPrefix(v, toComplete) {
+				comps = append(comps, v)
+			}
+		}
+		return comps, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// GetClusterNameFromArgsOrFlag gets the cluster name from the "cluster" flag, or the first argument
+// if the flag is not specified.
+func GetClusterNameFromArgsOrFlag(cmd *cobra.Command, args []string) string {
+	if flag := cmd.Flags().Lookup("cluster"); flag != nil && flag.Value.String() != "" {
+		return flag.Value.String()
+	}
+	if len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+// ClusterResourceCompletionFunc returns the completion function completing the names of the resource
+// belonging to the cluster got by GetClusterNameFromArgsOrFlag, such as the backups or OpsRequests of
+// the cluster. All resources are completed if the cluster is not specified.
+func ClusterResourceCompletionFunc(f cmdutil.Factory, gvr schema.GroupVersionResource) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var labels []string
+		if clusterName := GetClusterNameFromArgsOrFlag(cmd, args); clusterName != "" {
+			labels = append(labels, fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, clusterName))
+		}
+		return CompGetResourceWithLabels(f, cmd, GVRToString(gvr), labels, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ClusterComponentCompletionFunc returns the completion function completing the component names of
+// the cluster got by GetClusterNameFromArgsOrFlag.
+func ClusterComponentCompletionFunc(f cmdutil.Factory) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var components []string
+		clusterName := GetClusterNameFromArgsOrFlag(cmd, args)
+		if clusterName == "" {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		}
+		namespace, _, _ := f.ToRawKubeConfigLoader().Namespace()
+		dynamic, err := f.DynamicClient()
+		if err != nil {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		}
+		obj, err := dynamic.Resource(types.ClusterGVR()).Namespace(namespace).Get(context.TODO(), clusterName, metav1.GetOptions{})
+		if err != nil {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		}
+		cluster := &appsv1alpha1.Cluster{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cluster); err != nil {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, comp := range cluster.Spec.ComponentSpecs {
+			if strings.HasPrefix(comp.Name, toComplete) {
+				components = append(components, comp.Name)
+			}
+		}
+		return components, cobra.ShellCompDirectiveNoFileComp
+	}
 }
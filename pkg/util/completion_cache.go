@@ -0,0 +1,224 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+const (
+	// DefaultCompletionCacheTTL is the default TTL of the cached completions
+	DefaultCompletionCacheTTL = 30 * time.Second
+
+	// CompletionRequestTimeout is the timeout of the requests to complete the resource names, so
+	// the completions do not hang if the API server is unreachable
+	CompletionRequestTimeout = "5s"
+
+	completionCacheDir = "cache/completion"
+
+	// completionProbeTimeout is the timeout to check whether the API server is reachable
+	completionProbeTimeout = 2 * time.Second
+)
+
+// completionCacheEntry is the cached completions of a flag
+type completionCacheEntry struct {
+	Timestamp   time.Time                `json:"timestamp"`
+	Completions []string                 `json:"completions"`
+	Directive   cobra.ShellCompDirective `json:"directive"`
+}
+
+// CachedCompletionFunc wraps the completion function of the flag with a short-TTL cache under the
+// kbcli home dir, so the completions listing the resources do not hit the API server on every TAB.
+// The cache is scoped by the kube context, namespace, command, arguments and the specified flags,
+// the completions are fetched without prefix and filtered by the prefix to complete, and the TTL is
+// configured by `kbcli config set completion-cache-ttl`, 0 disables the cache.
+func CachedCompletionFunc(flag string, fn CompletionFunc) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ttl := GetCompletionCacheTTL()
+		if ttl <= 0 {
+			return fn(cmd, args, toComplete)
+		}
+		path, err := completionCachePath(cmd, flag, args)
+		if err != nil {
+			klog.V(1).Infof("failed to get the completion cache path: %v", err)
+			return fn(cmd, args, toComplete)
+		}
+		if entry := readCompletionCache(path, ttl); entry != nil {
+			return filterCompletions(entry.Completions, toComplete), entry.Directive
+		}
+		comps, directive := fn(cmd, args, "")
+		// do not cache the failed or empty completions, the resources may be created soon
+		if directive&cobra.ShellCompDirectiveError == 0 && len(comps) > 0 {
+			writeCompletionCache(path, &completionCacheEntry{Timestamp: time.Now(), Completions: comps, Directive: directive})
+			return filterCompletions(comps, toComplete), directive
+		}
+		// fall back to the expired cached completions if the API server is unreachable, instead of
+		// completing nothing or the file names
+		if entry := readCompletionCache(path, 0); entry != nil && !serverReachable(cmd) {
+			return filterCompletions(entry.Completions, toComplete), entry.Directive
+		}
+		if directive&cobra.ShellCompDirectiveError != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(comps, toComplete), directive
+	}
+}
+
+// serverReachable checks whether the API server of the completions is reachable, it is a variable
+// so that it can be replaced in tests.
+var serverReachable = func(cmd *cobra.Command) bool {
+	config, err := completionClientConfig(cmd).ClientConfig()
+	if err != nil {
+		return false
+	}
+	config.Timeout = completionProbeTimeout
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return false
+	}
+	_, err = client.ServerVersion()
+	return err == nil
+}
+
+// GetCompletionCacheTTL returns the TTL of the cached completions
+func GetCompletionCacheTTL() time.Duration {
+	value := viper.GetString(types.CfgKeyCompletionCacheTTL)
+	if value == "" {
+		return DefaultCompletionCacheTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		klog.V(1).Infof("invalid completion cache TTL %s, use the default %s", value, DefaultCompletionCacheTTL)
+		return DefaultCompletionCacheTTL
+	}
+	return ttl
+}
+
+// completionCachePath returns the cache file path of the completions, which is the hash of the
+// kube context, namespace, command path, flag, arguments and the specified flags.
+func completionCachePath(cmd *cobra.Command, flag string, args []string) (string, error) {
+	cliHome, err := GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	context, namespace := completionScope(cmd)
+	keys := []string{context, namespace, cmd.CommandPath(), flag, strings.Join(args, " ")}
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name != flag {
+			flags = append(flags, f.Name+"="+f.Value.String())
+		}
+	})
+	sort.Strings(flags)
+	keys = append(keys, flags...)
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return filepath.Join(cliHome, completionCacheDir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// completionClientConfig returns the client config of the completions by the global flags
+func completionClientConfig(cmd *cobra.Command) clientcmd.ClientConfig {
+	flagValue := func(name string) string {
+		if f := cmd.Flag(name); f != nil {
+			return f.Value.String()
+		}
+		return ""
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = flagValue("kubeconfig")
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: flagValue("context")}
+	overrides.Context.Namespace = flagValue("namespace")
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// completionScope returns the kube context and namespace the completions belong to
+func completionScope(cmd *cobra.Command) (string, string) {
+	clientConfig := completionClientConfig(cmd)
+	context := ""
+	if rawConfig, err := clientConfig.RawConfig(); err == nil {
+		context = rawConfig.CurrentContext
+	}
+	if f := cmd.Flag("context"); f != nil && f.Value.String() != "" {
+		context = f.Value.String()
+	}
+	namespace, _, _ := clientConfig.Namespace()
+	return context, namespace
+}
+
+// readCompletionCache reads the cached completions not older than the ttl, the expired
+// completions are returned too if the ttl is 0.
+func readCompletionCache(path string, ttl time.Duration) *completionCacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	entry := &completionCacheEntry{}
+	if err = json.Unmarshal(data, entry); err != nil || (ttl > 0 && time.Since(entry.Timestamp) > ttl) {
+		return nil
+	}
+	return entry
+}
+
+func writeCompletionCache(path string, entry *completionCacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		klog.V(1).Infof("failed to create the completion cache dir: %v", err)
+		return
+	}
+	// write to a temp file and rename it, so the concurrent completions never read a partial file
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0640); err != nil {
+		klog.V(1).Infof("failed to write the completion cache: %v", err)
+		return
+	}
+	_ = os.Rename(tmp, path)
+}
+
+func filterCompletions(comps []string, toComplete string) []string {
+	if toComplete == "" {
+		return comps
+	}
+	var res []string
+	for _, comp := range comps {
+		if strings.HasPrefix(comp, toComplete) {
+			res = append(res, comp)
+		}
+	}
+	return res
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("completion cache", func() {
+	var (
+		calls int
+		cmd   *cobra.Command
+		fn    CompletionFunc
+	)
+
+	BeforeEach(func() {
+		Expect(os.Setenv(types.CliHomeEnv, GinkgoT().TempDir())).Should(Succeed())
+		calls = 0
+		cmd = &cobra.Command{Use: "test"}
+		cmd.Flags().String("cluster", "", "")
+		cmd.Flags().String("policy", "", "")
+		fn = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			calls++
+			return filterCompletions([]string{"policy-a", "policy-b", "other"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(types.CliHomeEnv)).Should(Succeed())
+		viper.Set(types.CfgKeyCompletionCacheTTL, "")
+	})
+
+	It("caches the completions", func() {
+		cached := CachedCompletionFunc("policy", fn)
+		comps, directive := cached(cmd, nil, "")
+		Expect(comps).Should(Equal([]string{"policy-a", "policy-b", "other"}))
+		Expect(directive).Should(Equal(cobra.ShellCompDirectiveNoFileComp))
+		Expect(calls).Should(Equal(1))
+
+		By("complete with prefix from the cache")
+		comps, _ = cached(cmd, nil, "policy-")
+		Expect(comps).Should(Equal([]string{"policy-a", "policy-b"}))
+		Expect(calls).Should(Equal(1))
+
+		By("the specified flags and args are in the cache key")
+		Expect(cmd.Flags().Set("cluster", "mycluster")).Should(Succeed())
+		_, _ = cached(cmd, nil, "")
+		Expect(calls).Should(Equal(2))
+		_, _ = cached(cmd, []string{"arg"}, "")
+		Expect(calls).Should(Equal(3))
+		_, _ = cached(cmd, []string{"arg"}, "o")
+		Expect(calls).Should(Equal(3))
+	})
+
+	It("does not cache the empty completions", func() {
+		cached := CachedCompletionFunc("policy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			calls++
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		})
+		_, _ = cached(cmd, nil, "")
+		_, _ = cached(cmd, nil, "")
+		Expect(calls).Should(Equal(2))
+	})
+
+	It("falls back to the expired completions if the API server is unreachable", func() {
+		cached := CachedCompletionFunc("policy", fn)
+		_, _ = cached(cmd, nil, "")
+		Expect(calls).Should(Equal(1))
+
+		viper.Set(types.CfgKeyCompletionCacheTTL, "1ns")
+		failed := CachedCompletionFunc("policy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			calls++
+			return nil, cobra.ShellCompDirectiveError
+		})
+		reachable := false
+		defer func(fn func(*cobra.Command) bool) { serverReachable = fn }(serverReachable)
+		serverReachable = func(*cobra.Command) bool { return reachable }
+		comps, directive := failed(cmd, nil, "policy-")
+		Expect(comps).Should(Equal([]string{"policy-a", "policy-b"}))
+		Expect(directive).Should(Equal(cobra.ShellCompDirectiveNoFileComp))
+		Expect(calls).Should(Equal(2))
+
+		By("the error is not returned to the shell if the API server is reachable")
+		reachable = true
+		comps, directive = failed(cmd, nil, "policy-")
+		Expect(comps).Should(BeEmpty())
+		Expect(directive).Should(Equal(cobra.ShellCompDirectiveNoFileComp))
+	})
+
+	It("disables the cache with zero TTL", func() {
+		viper.Set(types.CfgKeyCompletionCacheTTL, "0")
+		Expect(GetCompletionCacheTTL()).Should(BeZero())
+		cached := CachedCompletionFunc("policy", fn)
+		_, _ = cached(cmd, nil, "")
+		_, _ = cached(cmd, nil, "")
+		Expect(calls).Should(Equal(2))
+
+		viper.Set(types.CfgKeyCompletionCacheTTL, "invalid")
+		Expect(GetCompletionCacheTTL()).Should(Equal(DefaultCompletionCacheTTL))
+	})
+})
@@ -25,6 +25,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -80,4 +81,37 @@ var _ = Describe("completion", func() {
 		Expect(len(CompGetResourceWithLabels(tf, cmd, "pods", []string{}, ""))).Should(Equal(1))
 		Expect(len(CompGetResourceWithLabels(tf, cmd, "pods", []string{fmt.Sprintf("%s=%s", constant.RoleLabelKey, "leader")}, ""))).Should(Equal(1))
 	})
+
+	It("test completion helpers", func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("cluster", "", "")
+		cmd.Flags().String("policy", "", "")
+
+		By("fixed values")
+		comps, directive := ValuesCompletionFunc("Delete", "Retain")(cmd, nil, "De")
+		Expect(comps).Should(Equal([]string{"Delete"}))
+		Expect(directive).Should(Equal(cobra.ShellCompDirectiveNoFileComp))
+
+		By("cluster name from args or flag")
+		Expect(GetClusterNameFromArgsOrFlag(cmd, nil)).Should(BeEmpty())
+		Expect(GetClusterNameFromArgsOrFlag(cmd, []string{"arg-cluster"})).Should(Equal("arg-cluster"))
+		Expect(cmd.Flags().Set("cluster", clusterName)).Should(Succeed())
+		Expect(GetClusterNameFromArgsOrFlag(cmd, []string{"arg-cluster"})).Should(Equal(clusterName))
+
+		By("cluster components")
+		tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeCluster(clusterName, namespace))
+		comps, _ = ClusterComponentCompletionFunc(tf)(cmd, nil, "")
+		Expect(comps).Should(Equal([]string{testing.ComponentName, testing.ComponentName + "-1"}))
+		comps, _ = ClusterComponentCompletionFunc(tf)(cmd, nil, testing.ComponentName+"-")
+		Expect(comps).Should(Equal([]string{testing.ComponentName + "-1"}))
+		Expect(cmd.Flags().Set("cluster", "not-found")).Should(Succeed())
+		comps, _ = ClusterComponentCompletionFunc(tf)(cmd, nil, "")
+		Expect(comps).Should(BeEmpty())
+
+		By("register completion for flags")
+		RegisterFlagCompletionFunc(cmd, "policy", ValuesCompletionFunc("policy"))
+		Expect(cmd.RegisterFlagCompletionFunc("policy", ValuesCompletionFunc("policy"))).Should(MatchError(ContainSubstring("already registered")))
+		// do nothing if the flag does not exist
+		RegisterFlagCompletionFunc(cmd, "method", ValuesCompletionFunc("method"))
+	})
 })
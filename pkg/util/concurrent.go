@@ -0,0 +1,73 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultParallelism is the default number of resources that are fetched concurrently.
+const DefaultParallelism = 5
+
+// ParallelMap calls fn for every name with at most parallelism concurrent calls, and returns
+// the results in the same order as the names. If any call fails, the first error is returned.
+// A non-positive parallelism falls back to DefaultParallelism.
+func ParallelMap[T any](names []string, parallelism int, fn func(name string) (T, error)) ([]T, error) {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+	results := make([]T, len(names))
+	g := errgroup.Group{}
+	g.SetLimit(parallelism)
+	for i := range names {
+		i := i
+		g.Go(func() error {
+			res, err := fn(names[i])
+			if err != nil {
+				return err
+			}
+			results[i] = res
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetResourceNames returns the names of the resources in the namespace matching the label selector,
+// it is used by the commands that accept either resource names or a label selector.
+func GetResourceNames(dynamic dynamic.Interface, gvr schema.GroupVersionResource, namespace, selector string) ([]string, error) {
+	objs, err := dynamic.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(objs.Items))
+	for _, obj := range objs.Items {
+		names = append(names, obj.GetName())
+	}
+	return names, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("concurrent", func() {
+	It("parallel map keeps the order and bounds the parallelism", func() {
+		var running, maxRunning int32
+		names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+		results, err := ParallelMap(names, 3, func(name string) (string, error) {
+			cur := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if cur <= m || atomic.CompareAndSwapInt32(&maxRunning, m, cur) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return name + "-result", nil
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(maxRunning).Should(BeNumerically("<=", 3))
+		for i, name := range names {
+			Expect(results[i]).Should(Equal(name + "-result"))
+		}
+
+		_, err = ParallelMap(names, 0, func(name string) (string, error) {
+			if name == "c" {
+				return "", fmt.Errorf("failed to get %s", name)
+			}
+			return name, nil
+		})
+		Expect(err).Should(MatchError("failed to get c"))
+	})
+
+	It("get resource names by label selector", func() {
+		c1 := testing.FakeCluster("cluster1", testing.Namespace)
+		c1.SetLabels(map[string]string{"env": "test"})
+		c2 := testing.FakeCluster("cluster2", testing.Namespace)
+		dynamic := testing.FakeDynamicClient(c1, c2)
+		names, err := GetResourceNames(dynamic, types.ClusterGVR(), testing.Namespace, "env=test")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names).Should(Equal([]string{"cluster1"}))
+	})
+})
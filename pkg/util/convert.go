@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	goruntime "runtime"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// parallelConvertThreshold is the minimum number of items for which the conversion
+// of an unstructured list is split across multiple goroutines.
+const parallelConvertThreshold = 1000
+
+// ConvertUnstructuredList converts the unstructured items to a typed slice. Items rejected by
+// the filter are skipped before they are converted, the typed slice is allocated only once and
+// every item is decoded in place, and large lists are converted concurrently in chunks.
+// A nil filter keeps all items.
+func ConvertUnstructuredList[T any](items []unstructured.Unstructured, filter func(*unstructured.Unstructured) bool) ([]T, error) {
+	selected := make([]int, 0, len(items))
+	for i := range items {
+		if filter == nil || filter(&items[i]) {
+			selected = append(selected, i)
+		}
+	}
+
+	result := make([]T, len(selected))
+	convert := func(start, end int) error {
+		for i := start; i < end; i++ {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(items[selected[i]].Object, &result[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	workers := goruntime.GOMAXPROCS(0)
+	if len(selected) < parallelConvertThreshold || workers == 1 {
+		if err := convert(0, len(selected)); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	chunk := (len(selected) + workers - 1) / workers
+	g := errgroup.Group{}
+	for start := 0; start < len(selected); start += chunk {
+		start, end := start, start+chunk
+		if end > len(selected) {
+			end = len(selected)
+		}
+		g.Go(func() error {
+			return convert(start, end)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NameFilter returns a filter of ConvertUnstructuredList which keeps the items whose name is
+// one of the given names. If no names are given, all items are kept.
+func NameFilter(names ...string) func(*unstructured.Unstructured) bool {
+	if len(names) == 0 {
+		return nil
+	}
+	nameSet := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		nameSet[name] = struct{}{}
+	}
+	return func(obj *unstructured.Unstructured) bool {
+		_, ok := nameSet[obj.GetName()]
+		return ok
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+func newUnstructuredBackups(n int) []unstructured.Unstructured {
+	items := make([]unstructured.Unstructured, n)
+	for i := range items {
+		items[i] = unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "dataprotection.kubeblocks.io/v1alpha1",
+			"kind":       "Backup",
+			"metadata": map[string]interface{}{
+				"name":      fmt.Sprintf("backup-%d", i),
+				"namespace": "default",
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/instance": "test-cluster",
+				},
+			},
+			"spec": map[string]interface{}{
+				"backupMethod":     "xtrabackup",
+				"backupPolicyName": "test-backup-policy",
+			},
+			"status": map[string]interface{}{
+				"phase":     "Completed",
+				"totalSize": "1Gi",
+				"path":      fmt.Sprintf("/default/test-cluster/backup-%d", i),
+			},
+		}}
+	}
+	return items
+}
+
+var _ = Describe("convert unstructured list", func() {
+	It("converts all items", func() {
+		backups, err := ConvertUnstructuredList[dpv1alpha1.Backup](newUnstructuredBackups(3), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(backups).Should(HaveLen(3))
+		Expect(backups[2].Name).Should(Equal("backup-2"))
+		Expect(backups[2].Spec.BackupMethod).Should(Equal("xtrabackup"))
+		Expect(backups[2].Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseCompleted))
+	})
+
+	It("converts large lists concurrently and keeps the order", func() {
+		backups, err := ConvertUnstructuredList[dpv1alpha1.Backup](newUnstructuredBackups(parallelConvertThreshold*2+1), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(backups).Should(HaveLen(parallelConvertThreshold*2 + 1))
+		for i := range backups {
+			Expect(backups[i].Name).Should(Equal(fmt.Sprintf("backup-%d", i)))
+		}
+	})
+
+	It("filters items before converting", func() {
+		backups, err := ConvertUnstructuredList[dpv1alpha1.Backup](newUnstructuredBackups(5), NameFilter("backup-1", "backup-3"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(backups).Should(HaveLen(2))
+		Expect(backups[0].Name).Should(Equal("backup-1"))
+		Expect(backups[1].Name).Should(Equal("backup-3"))
+		Expect(NameFilter()).Should(BeNil())
+	})
+
+	It("returns the conversion error", func() {
+		items := newUnstructuredBackups(1)
+		items[0].Object["spec"] = "invalid"
+		_, err := ConvertUnstructuredList[dpv1alpha1.Backup](items, nil)
+		Expect(err).Should(HaveOccurred())
+	})
+})
+
+func BenchmarkConvertUnstructuredList(b *testing.B) {
+	items := newUnstructuredBackups(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ConvertUnstructuredList[dpv1alpha1.Backup](items, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	diskcached "k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+const (
+	// DefaultDiscoveryCacheTTL is the default TTL of the on-disk discovery cache, same as kubectl
+	DefaultDiscoveryCacheTTL = 6 * time.Hour
+
+	discoveryBurst = 300
+	discoveryQPS   = 50.0
+)
+
+var (
+	illegalFileCharacters = regexp.MustCompile(`[^(\w/.)]`)
+
+	// sharedDiscovery caches the discovery clients and REST mappers by the discovery cache dir of the
+	// API server, so all the factories in the process talking to the same API server share them.
+	sharedDiscovery = struct {
+		sync.Mutex
+		clients map[string]discovery.CachedDiscoveryInterface
+		mappers map[string]meta.RESTMapper
+	}{
+		clients: map[string]discovery.CachedDiscoveryInterface{},
+		mappers: map[string]meta.RESTMapper{},
+	}
+)
+
+// CachedConfigFlags is a RESTClientGetter sharing the discovery client and REST mapper with other factories
+// in the process, the discovery results are cached on disk with the TTL configured by `kbcli config set
+// discovery-cache-ttl`, and a zero TTL caches the discovery results in memory only.
+type CachedConfigFlags struct {
+	*genericclioptions.ConfigFlags
+}
+
+// NewCachedConfigFlags wraps the ConfigFlags with the shared discovery cache
+func NewCachedConfigFlags(configFlags *genericclioptions.ConfigFlags) *CachedConfigFlags {
+	return &CachedConfigFlags{ConfigFlags: configFlags}
+}
+
+// ToDiscoveryClient returns the shared cached discovery client of the API server
+func (f *CachedConfigFlags) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	_, client, err := f.sharedDiscoveryClient()
+	return client, err
+}
+
+// ToRESTMapper returns the shared REST mapper of the API server
+func (f *CachedConfigFlags) ToRESTMapper() (meta.RESTMapper, error) {
+	key, client, err := f.sharedDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	sharedDiscovery.Lock()
+	defer sharedDiscovery.Unlock()
+	mapper, ok := sharedDiscovery.mappers[key]
+	if !ok {
+		mapper = restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(client), client)
+		sharedDiscovery.mappers[key] = mapper
+	}
+	return mapper, nil
+}
+
+// sharedDiscoveryClient returns the discovery client of the API server and the discovery cache dir as its key
+func (f *CachedConfigFlags) sharedDiscoveryClient() (string, discovery.CachedDiscoveryInterface, error) {
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	config.Burst = discoveryBurst
+	config.QPS = discoveryQPS
+
+	cacheDir := filepath.Join(homedir.HomeDir(), ".kube", "cache")
+	if f.CacheDir != nil && *f.CacheDir != "" {
+		cacheDir = *f.CacheDir
+	}
+	host := strings.Replace(strings.Replace(config.Host, "https://", "", 1), "http://", "", 1)
+	key := filepath.Join(cacheDir, "discovery", illegalFileCharacters.ReplaceAllString(host, "_"))
+
+	sharedDiscovery.Lock()
+	defer sharedDiscovery.Unlock()
+	if client, ok := sharedDiscovery.clients[key]; ok {
+		return key, client, nil
+	}
+	var client discovery.CachedDiscoveryInterface
+	if ttl := GetDiscoveryCacheTTL(); ttl > 0 {
+		client, err = diskcached.NewCachedDiscoveryClientForConfig(config, key, filepath.Join(cacheDir, "http"), ttl)
+	} else {
+		var dc discovery.DiscoveryInterface
+		if dc, err = discovery.NewDiscoveryClientForConfig(config); err == nil {
+			client = memory.NewMemCacheClient(dc)
+		}
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	sharedDiscovery.clients[key] = client
+	return key, client, nil
+}
+
+// GetDiscoveryCacheTTL returns the TTL of the on-disk discovery cache
+func GetDiscoveryCacheTTL() time.Duration {
+	value := viper.GetString(types.CfgKeyDiscoveryCacheTTL)
+	if value == "" {
+		return DefaultDiscoveryCacheTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		klog.V(1).Infof("invalid discovery cache TTL %s, use the default %s", value, DefaultDiscoveryCacheTTL)
+		return DefaultDiscoveryCacheTTL
+	}
+	return ttl
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/utils/pointer"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("discovery cache", func() {
+	newConfigFlags := func(server string) *CachedConfigFlags {
+		cf := genericclioptions.NewConfigFlags(true)
+		cf.APIServer = pointer.String(server)
+		cf.CacheDir = pointer.String(GinkgoT().TempDir())
+		return NewCachedConfigFlags(cf)
+	}
+
+	AfterEach(func() {
+		viper.Set(types.CfgKeyDiscoveryCacheTTL, "")
+	})
+
+	It("get the discovery cache TTL", func() {
+		Expect(GetDiscoveryCacheTTL()).Should(Equal(DefaultDiscoveryCacheTTL))
+		viper.Set(types.CfgKeyDiscoveryCacheTTL, "30m")
+		Expect(GetDiscoveryCacheTTL()).Should(Equal(30 * time.Minute))
+		viper.Set(types.CfgKeyDiscoveryCacheTTL, "invalid")
+		Expect(GetDiscoveryCacheTTL()).Should(Equal(DefaultDiscoveryCacheTTL))
+	})
+
+	It("share the discovery client and REST mapper of the same API server", func() {
+		f1 := newConfigFlags("https://10.0.0.1:6443")
+		f2 := &CachedConfigFlags{ConfigFlags: genericclioptions.NewConfigFlags(true)}
+		f2.APIServer = f1.APIServer
+		f2.CacheDir = f1.CacheDir
+
+		c1, err := f1.ToDiscoveryClient()
+		Expect(err).ShouldNot(HaveOccurred())
+		c2, err := f2.ToDiscoveryClient()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(c1).Should(BeIdenticalTo(c2))
+
+		m1, err := f1.ToRESTMapper()
+		Expect(err).ShouldNot(HaveOccurred())
+		m2, err := f2.ToRESTMapper()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m1).Should(BeIdenticalTo(m2))
+
+		By("the discovery client of another API server is not shared")
+		c3, err := newConfigFlags("https://10.0.0.2:6443").ToDiscoveryClient()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(c3).ShouldNot(BeIdenticalTo(c1))
+	})
+
+	It("cache the discovery in memory if the TTL is zero", func() {
+		viper.Set(types.CfgKeyDiscoveryCacheTTL, "0")
+		c, err := newConfigFlags("https://10.0.0.3:6443").ToDiscoveryClient()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(c).Should(BeAssignableToTypeOf(memory.NewMemCacheClient(nil)))
+	})
+})
@@ -27,6 +27,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	clierrors "github.com/apecloud/kbcli/pkg/errors"
 )
 
 var (
@@ -34,6 +36,31 @@ var (
 	invalidAuthAPIVersionHint = "if you are using Amazon EKS, please update AWS CLI to the latest version and update the kubeconfig file for your cluster,\nrefer to https://docs.aws.amazon.com/eks/latest/userguide/create-kubeconfig.html"
 )
 
+// FatalHandlers are called before kbcli exits with a fatal error, such as recording the
+// failure to the audit log.
+var FatalHandlers []func(msg string, code int)
+
+// RunFatalHandlers runs the FatalHandlers with the error message and the exit code.
+func RunFatalHandlers(msg string, code int) {
+	for _, h := range FatalHandlers {
+		h(msg, code)
+	}
+}
+
+// Fatal runs the FatalHandlers, prints the message if provided and then exits, it is
+// used to replace the default fatal error handler of kubectl.
+func Fatal(msg string, code int) {
+	RunFatalHandlers(msg, code)
+	if len(msg) > 0 {
+		// add newline if needed
+		if !strings.HasSuffix(msg, "\n") {
+			msg += "\n"
+		}
+		fmt.Fprint(os.Stderr, msg)
+	}
+	os.Exit(code)
+}
+
 // CheckErr prints a user-friendly error to STDERR and exits with a non-zero exit code.
 func CheckErr(err error) {
 	// unwrap aggregates of 1
@@ -45,10 +72,18 @@ func CheckErr(err error) {
 		return
 	}
 
+	// typed errors exit with their own exit codes, so scripts can branch on the failures
+	if code := clierrors.ExitCode(err); code != clierrors.ExitCodeDefault {
+		RunFatalHandlers(err.Error(), code)
+		printErr(err)
+		os.Exit(code)
+	}
+
 	// ErrExit and other valid api errors will be checked by cmdutil.CheckErr, now
 	// we only check invalid api errors that can not be converted to StatusError.
 	if err != cmdutil.ErrExit && apierrors.IsInvalid(err) {
 		if _, ok := err.(*apierrors.StatusError); !ok {
+			RunFatalHandlers(err.Error(), cmdutil.DefaultErrorExitCode)
 			printErr(err)
 			os.Exit(cmdutil.DefaultErrorExitCode)
 		}
@@ -56,6 +91,7 @@ func CheckErr(err error) {
 
 	// check invalid authentication apiVersion and output hint message
 	if err.Error() == invalidAuthAPIVersion {
+		RunFatalHandlers(err.Error(), cmdutil.DefaultErrorExitCode)
 		printErr(err)
 		fmt.Fprintf(os.Stderr, "hint: %s\n", invalidAuthAPIVersionHint)
 		os.Exit(cmdutil.DefaultErrorExitCode)
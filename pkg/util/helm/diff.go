@@ -505,3 +505,125 @@ func getAPIInfo(api map[string]any) string {
 	res += "}"
 	return res
 }
+
+// OutputValuesDiff output the difference of the default values between different version for a chart,
+// and highlights the values overridden by user whose defaults are changed or removed in versionB,
+// these overrides may change their meaning after upgrading.
+func OutputValuesDiff(releaseA *release.Release, releaseB *release.Release, overrides map[string]any, versionA, versionB string, out io.Writer) {
+	valuesA := chartValues(releaseA)
+	valuesB := chartValues(releaseB)
+	flatA := flattenValues(valuesA)
+	flatB := flattenValues(valuesB)
+
+	tblPrinter := printer.NewTablePrinter(out)
+	tblPrinter.SetHeader("VALUE", "MODE", versionA, versionB)
+	for _, key := range sortedKeys(flatA) {
+		valueB, ok := flatB[key]
+		switch {
+		case !ok:
+			tblPrinter.AddRow(key, printer.BoldRed(Removed), formatValue(flatA[key]), "")
+		case !reflect.DeepEqual(flatA[key], valueB):
+			tblPrinter.AddRow(key, printer.BoldYellow(Modified), formatValue(flatA[key]), formatValue(valueB))
+		}
+	}
+	for _, key := range sortedKeys(flatB) {
+		if _, ok := flatA[key]; !ok {
+			tblPrinter.AddRow(key, printer.BoldGreen(Added), "", formatValue(flatB[key]))
+		}
+	}
+	if tblPrinter.Tbl.Length() != 0 {
+		fmt.Fprintf(out, "%s\n", printer.BoldYellow("Default values"))
+		tblPrinter.Print()
+		printer.PrintBlankLine(out)
+	}
+
+	overriddenPrinter := printer.NewTablePrinter(out)
+	overriddenPrinter.SetHeader("OVERRIDDEN VALUE", fmt.Sprintf("DEFAULT %s", versionA), fmt.Sprintf("DEFAULT %s", versionB), "NOTE")
+	overriddenPaths := make(map[string][]string)
+	walkValues(overrides, nil, func(path []string, _ any) {
+		overriddenPaths[strings.Join(path, ".")] = path
+	})
+	for _, key := range sortedKeys(overriddenPaths) {
+		path := overriddenPaths[key]
+		valueA, depthA := lookupValue(valuesA, path)
+		valueB, depthB := lookupValue(valuesB, path)
+		_, isMapB := valueB.(map[string]any)
+		switch {
+		case depthA == 0:
+			// not a default value of versionA, can not tell whether its meaning is changed
+			continue
+		case depthB < depthA && isMapB:
+			overriddenPrinter.AddRow(key, formatValue(valueA), "", printer.BoldRed(fmt.Sprintf("removed in %s, the override will not take effect", versionB)))
+		case reflect.TypeOf(valueA) != reflect.TypeOf(valueB):
+			overriddenPrinter.AddRow(key, formatValue(valueA), formatValue(valueB), printer.BoldRed(fmt.Sprintf("the type is changed from %T to %T", valueA, valueB)))
+		case depthA == len(path) && !reflect.DeepEqual(valueA, valueB):
+			overriddenPrinter.AddRow(key, formatValue(valueA), formatValue(valueB), printer.BoldYellow("the default is changed, the override still takes effect"))
+		}
+	}
+	if overriddenPrinter.Tbl.Length() != 0 {
+		fmt.Fprintf(out, "%s\n", printer.BoldRed("Overridden values changed"))
+		overriddenPrinter.Print()
+		printer.PrintBlankLine(out)
+	}
+}
+
+// chartValues returns the default values of the chart in the release
+func chartValues(release *release.Release) map[string]any {
+	if release == nil || release.Chart == nil {
+		return map[string]any{}
+	}
+	return release.Chart.Values
+}
+
+// walkValues calls fn for each leaf of the values, the maps which are empty, and the slices are treated as leaves
+func walkValues(values map[string]any, path []string, fn func(path []string, value any)) {
+	for key, value := range values {
+		curPath := append(path[:len(path):len(path)], key)
+		if m, ok := value.(map[string]any); ok && len(m) > 0 {
+			walkValues(m, curPath, fn)
+			continue
+		}
+		fn(curPath, value)
+	}
+}
+
+// flattenValues flattens the values to a map whose keys are the leaf paths joined by "."
+func flattenValues(values map[string]any) map[string]any {
+	res := make(map[string]any)
+	walkValues(values, nil, func(path []string, value any) {
+		res[strings.Join(path, ".")] = value
+	})
+	return res
+}
+
+// lookupValue looks up the value of the path, if the path does not exist, it returns
+// the value of the deepest existing ancestor, and the depth of the returned value
+func lookupValue(values map[string]any, path []string) (any, int) {
+	var cur any = values
+	for i, key := range path {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return cur, i
+		}
+		next, ok := m[key]
+		if !ok {
+			return cur, i
+		}
+		cur = next
+	}
+	return cur, len(path)
+}
+
+func formatValue(value any) string {
+	if value == nil {
+		return "null"
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -25,6 +25,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"go.uber.org/zap/buffer"
+	"helm.sh/helm/v3/pkg/chart"
 	helm "helm.sh/helm/v3/pkg/release"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
@@ -429,4 +430,52 @@ spec.name   true -> false   Modified
 		})
 
 	})
+	It("test OutputValuesDiff", func() {
+		var out buffer.Buffer
+		buildValuesRelease := func(values map[string]any) *helm.Release {
+			return &helm.Release{Chart: &chart.Chart{Values: values}}
+		}
+		releaseA := buildValuesRelease(map[string]any{
+			"replicaCount": 1,
+			"image":        map[string]any{"tag": "0.8.1", "pullPolicy": "IfNotPresent"},
+			"webhooks":     map[string]any{"enabled": true},
+			"nodeSelector": map[string]any{},
+			"tolerations":  []any{},
+		})
+		releaseB := buildValuesRelease(map[string]any{
+			"replicaCount": 1,
+			"image":        map[string]any{"tag": "0.9.0", "pullPolicy": "IfNotPresent"},
+			"nodeSelector": map[string]any{},
+			"tolerations":  map[string]any{"key": "value"},
+			"featureGates": map[string]any{"enabled": false},
+		})
+		overrides := map[string]any{
+			"replicaCount": 3,
+			"image":        map[string]any{"tag": "0.8.2"},
+			"webhooks":     map[string]any{"enabled": false},
+			"nodeSelector": map[string]any{"dev": "true"},
+			"tolerations":  []any{map[string]any{"key": "dev"}},
+			"unknown":      "value",
+		}
+		OutputValuesDiff(releaseA, releaseB, overrides, "0.8.1", "0.9.0", &out)
+		output := out.String()
+		Expect(output).Should(ContainSubstring("Default values"))
+		Expect(output).Should(MatchRegexp(`image.tag\s+Modified\s+0.8.1\s+0.9.0`))
+		Expect(output).Should(MatchRegexp(`webhooks.enabled\s+Removed\s+true`))
+		Expect(output).Should(MatchRegexp(`featureGates.enabled\s+Added\s+false`))
+		Expect(output).ShouldNot(MatchRegexp(`replicaCount\s+Modified`))
+
+		Expect(output).Should(ContainSubstring("Overridden values changed"))
+		Expect(output).Should(MatchRegexp(`image.tag\s+0.8.1\s+0.9.0\s+the default is changed`))
+		Expect(output).Should(MatchRegexp(`webhooks.enabled\s+true\s+removed in 0.9.0`))
+		Expect(output).Should(ContainSubstring("the type is changed from []interface {} to map[string]interface {}"))
+		Expect(output).ShouldNot(MatchRegexp(`(?m)^replicaCount`))
+		Expect(output).ShouldNot(ContainSubstring("nodeSelector.dev"))
+		Expect(output).ShouldNot(ContainSubstring("unknown"))
+
+		By("no changes")
+		out.Reset()
+		OutputValuesDiff(releaseA, releaseA, overrides, "0.8.1", "0.8.1", &out)
+		Expect(out.String()).Should(BeEmpty())
+	})
 })
@@ -41,6 +41,7 @@ import (
 	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/helmpath"
 	kubefake "helm.sh/helm/v3/pkg/kube/fake"
@@ -56,6 +57,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 	"github.com/apecloud/kbcli/pkg/util/breakingchange"
 )
 
@@ -76,6 +78,8 @@ type InstallOpts struct {
 	DisableHooks    bool
 	ForceUninstall  bool
 	Upgrader        breakingchange.Upgrader
+	// CaFile is the CA bundle to verify the certificate of the chart repository
+	CaFile string
 
 	// for helm template
 	DryRun     *bool
@@ -115,7 +119,7 @@ func AddRepo(r *repo.Entry) error {
 		}
 	}
 
-	cp, err := repo.NewChartRepository(r, getter.All(settings))
+	cp, err := repo.NewChartRepository(r, getters(settings, r.CAFile))
 	if err != nil {
 		return err
 	}
@@ -132,6 +136,56 @@ func AddRepo(r *repo.Entry) error {
 	return nil
 }
 
+// getters returns the getters of the charts, the HTTP getter uses the transport with the proxy set by
+// util.SetHTTPSProxy and the CA bundle in caFile if any of them is set, so the other requests, such as
+// the ones to the Kubernetes API server, are not sent to the proxy.
+func getters(settings *cli.EnvSettings, caFile string) getter.Providers {
+	providers := getter.All(settings)
+	if caFile == "" && !util.HTTPSProxyEnabled() {
+		return providers
+	}
+	for i := range providers {
+		if !providers[i].Provides("https") {
+			continue
+		}
+		providers[i].New = func(options ...getter.Option) (getter.Getter, error) {
+			transport, err := util.NewHTTPTransport(caFile)
+			if err != nil {
+				return nil, err
+			}
+			return getter.NewHTTPGetter(append(options, getter.WithTransport(transport))...)
+		}
+	}
+	return providers
+}
+
+// locateChart locates the chart as ChartPathOptions.LocateChart, but the chart in the repository is
+// downloaded by the getters returned by getters.
+func locateChart(c *action.ChartPathOptions, name string, settings *cli.EnvSettings) (string, error) {
+	name = strings.TrimSpace(name)
+	if _, err := os.Stat(name); err == nil || registry.IsOCI(name) || c.RepoURL != "" {
+		return c.LocateChart(name, settings)
+	}
+	dl := downloader.ChartDownloader{
+		Out:     os.Stdout,
+		Getters: getters(settings, c.CaFile),
+		Options: []getter.Option{
+			getter.WithTLSClientConfig(c.CertFile, c.KeyFile, c.CaFile),
+			getter.WithBasicAuth(c.Username, c.Password),
+		},
+		RepositoryConfig: settings.RepositoryConfig,
+		RepositoryCache:  settings.RepositoryCache,
+	}
+	if err := os.MkdirAll(settings.RepositoryCache, 0755); err != nil {
+		return "", err
+	}
+	filename, _, err := dl.DownloadTo(name, strings.TrimSpace(c.Version), settings.RepositoryCache)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(filename)
+}
+
 // RemoveRepo removes a repo
 func RemoveRepo(r *repo.Entry) error {
 	settings := cli.New()
@@ -242,12 +296,13 @@ func (i *InstallOpts) tryInstall(cfg *action.Configuration) (*release.Release, e
 		client.Timeout = defaultTimeout
 	}
 
-	cp, err := client.ChartPathOptions.LocateChart(i.Chart, settings)
+	client.ChartPathOptions.CaFile = i.CaFile
+	cp, err := locateChart(&client.ChartPathOptions, i.Chart, settings)
 	if err != nil {
 		return nil, err
 	}
 
-	p := getter.All(settings)
+	p := getters(settings, i.CaFile)
 	vals, err := i.ValueOpts.MergeValues(p)
 	if err != nil {
 		return nil, err
@@ -414,6 +469,10 @@ func NewActionConfig(cfg *Config) (*action.Configuration, error) {
 		settings.KubeContext = cfg.kubeContext
 	}
 	settings.Debug = cfg.debug
+	// impersonate the user and groups of the global flags `--as` and `--as-group`
+	impersonation := util.Impersonation()
+	settings.KubeAsUser = impersonation.UserName
+	settings.KubeAsGroups = impersonation.Groups
 
 	if actionCfg.RegistryClient, err = registry.NewClient(
 		registry.ClientOptDebug(settings.Debug),
@@ -424,10 +483,11 @@ func NewActionConfig(cfg *Config) (*action.Configuration, error) {
 		return nil, err
 	}
 
-	// do not output warnings
+	// do not output warnings, and set the User-Agent of kbcli
 	getter := settings.RESTClientGetter()
 	getter.(*genericclioptions.ConfigFlags).WrapConfigFn = func(c *rest.Config) *rest.Config {
 		c.WarningHandler = rest.NoWarnings{}
+		c.UserAgent = util.UserAgent()
 		return c
 	}
 
@@ -514,12 +574,13 @@ func (i *InstallOpts) tryUpgrade(cfg *action.Configuration) (*release.Release, e
 	// if ReuseValues set to true, helm will use old values instead of new ones, which will cause nil pointer error if new values added.
 	client.ReuseValues = false
 
-	cp, err := client.ChartPathOptions.LocateChart(i.Chart, settings)
+	client.ChartPathOptions.CaFile = i.CaFile
+	cp, err := locateChart(&client.ChartPathOptions, i.Chart, settings)
 	if err != nil {
 		return nil, err
 	}
 
-	p := getter.All(settings)
+	p := getters(settings, i.CaFile)
 	vals, err := i.ValueOpts.MergeValues(p)
 	if err != nil {
 		return nil, err
@@ -682,6 +743,15 @@ func GetValues(release string, cfg *Config) (map[string]interface{}, error) {
 	return client.Run(release)
 }
 
+// GetUserValues gives an implementation of 'helm get values' for target release, only the values supplied by user are returned
+func GetUserValues(release string, cfg *Config) (map[string]interface{}, error) {
+	actionConfig, err := NewActionConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return action.NewGetValues(actionConfig).Run(release)
+}
+
 // GetTemplateInstallOps build a helm InstallOpts with dryrun to implement helm template
 func GetTemplateInstallOps(name, chart, version, namespace string) *InstallOpts {
 	dryrun := true
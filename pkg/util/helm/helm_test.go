@@ -20,11 +20,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package helm
 
 import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
 
@@ -32,6 +39,7 @@ import (
 
 	"github.com/apecloud/kbcli/pkg/testing"
 	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
 )
 
 var _ = Describe("helm util", func() {
@@ -155,6 +163,25 @@ var _ = Describe("helm util", func() {
 		})
 	})
 
+	It("fetch the charts by the proxy and CA", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("apiVersion: v1"))
+		}))
+		defer server.Close()
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)).Should(Succeed())
+		Expect(util.SetHTTPSProxy("", "127.0.0.1")).Should(Succeed())
+		defer func() {
+			Expect(util.SetHTTPSProxy("", "")).Should(Succeed())
+		}()
+
+		g, err := getters(cli.New(), caFile).ByScheme("https")
+		Expect(err).Should(Succeed())
+		data, err := g.Get(server.URL + "/index.yaml")
+		Expect(err).Should(Succeed())
+		Expect(data.String()).Should(Equal("apiVersion: v1"))
+	})
+
 	It("get chart versions", func() {
 		versions, _ := GetChartVersions(testing.KubeBlocksChartName)
 		Expect(versions).Should(BeNil())
@@ -0,0 +1,268 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package multicluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+const (
+	// ContextsFlag is the global flag to specify the kube contexts to fan out the command
+	ContextsFlag   = "contexts"
+	contextFlag    = "context"
+	kubeConfigFlag = "kubeconfig"
+)
+
+// ExecFunc runs the command in the kube context and writes the outputs to out and errOut
+type ExecFunc func(ctx context.Context, kubeContext string, out, errOut io.Writer) error
+
+// Runner runs a read command in multiple kube contexts concurrently and merges the outputs
+type Runner struct {
+	Contexts []string
+	Exec     ExecFunc
+	// MergeTable merges the table outputs of all contexts into one table with a CONTEXT column,
+	// otherwise the outputs are printed one by one with the context as the title
+	MergeTable bool
+
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+type result struct {
+	out    bytes.Buffer
+	errOut bytes.Buffer
+	err    error
+}
+
+// NewRunner creates a runner executing the command by the exec function in the contexts
+func NewRunner(contexts []string, exec ExecFunc, out, errOut io.Writer) *Runner {
+	return &Runner{Contexts: contexts, Exec: exec, Out: out, ErrOut: errOut}
+}
+
+// Run runs the command in all the contexts concurrently, the outputs are printed in the order of the contexts
+func (r *Runner) Run() error {
+	results := make([]*result, len(r.Contexts))
+	wg := sync.WaitGroup{}
+	for i := range r.Contexts {
+		results[i] = &result{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res := results[i]
+			res.err = r.Exec(context.Background(), r.Contexts[i], &res.out, &res.errOut)
+		}(i)
+	}
+	wg.Wait()
+
+	var (
+		errs   []error
+		tables []contextTable
+	)
+	for i, res := range results {
+		kubeContext := r.Contexts[i]
+		if res.errOut.Len() > 0 {
+			for _, line := range strings.Split(strings.TrimRight(res.errOut.String(), "\n"), "\n") {
+				fmt.Fprintf(r.ErrOut, "[%s] %s\n", kubeContext, line)
+			}
+		}
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("context %s: %v", kubeContext, res.err))
+			continue
+		}
+		if !r.MergeTable {
+			fmt.Fprintf(r.Out, "Context: %s\n%s\n", kubeContext, strings.TrimRight(res.out.String(), "\n"))
+			if i < len(results)-1 {
+				fmt.Fprintln(r.Out)
+			}
+			continue
+		}
+		header, rows, ok := parseTable(res.out.String())
+		if !ok {
+			// the command outputs a message instead of a table, such as "No resources found"
+			if msg := strings.TrimSpace(res.out.String()); msg != "" {
+				fmt.Fprintf(r.ErrOut, "[%s] %s\n", kubeContext, msg)
+			}
+			continue
+		}
+		tables = append(tables, contextTable{context: kubeContext, header: header, rows: rows})
+	}
+	if len(tables) > 0 {
+		printMergedTable(r.Out, tables)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+type contextTable struct {
+	context string
+	header  []string
+	rows    [][]string
+}
+
+// printMergedTable prints the tables with a CONTEXT column, the header is taken from the first table and
+// the cells of other tables are matched by the column name.
+func printMergedTable(out io.Writer, tables []contextTable) {
+	header := tables[0].header
+	tbl := printer.NewTablePrinter(out)
+	row := []interface{}{"CONTEXT"}
+	for _, h := range header {
+		row = append(row, h)
+	}
+	tbl.SetHeader(row...)
+	for _, t := range tables {
+		index := map[string]int{}
+		for i, h := range t.header {
+			index[h] = i
+		}
+		for _, cells := range t.rows {
+			row = []interface{}{t.context}
+			for _, h := range header {
+				var cell string
+				if i, ok := index[h]; ok && i < len(cells) {
+					cell = cells[i]
+				}
+				row = append(row, cell)
+			}
+			tbl.AddRow(row...)
+		}
+	}
+	tbl.Print()
+}
+
+// parseTable parses the table printed by the table printer, the cells are split by the start columns
+// of the header because the cells may be empty or contain spaces. The columns are counted by the display
+// width as the table printer pads the cells by it, so the wide characters such as CJK are split correctly.
+func parseTable(s string) ([]string, [][]string, bool) {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	headerLine := lines[0]
+	if strings.TrimSpace(headerLine) == "" || strings.ToUpper(headerLine) != headerLine {
+		return nil, nil, false
+	}
+	var (
+		starts []int
+		header []string
+		column int
+		prev   rune = ' '
+	)
+	for _, c := range headerLine {
+		if c != ' ' && prev == ' ' {
+			starts = append(starts, column)
+		}
+		column += runewidth.RuneWidth(c)
+		prev = c
+	}
+	split := func(line string) []string {
+		cells := make([]strings.Builder, len(starts))
+		i, column := -1, 0
+		for _, c := range line {
+			for i < len(starts)-1 && column >= starts[i+1] {
+				i++
+			}
+			if i >= 0 {
+				cells[i].WriteRune(c)
+			}
+			column += runewidth.RuneWidth(c)
+		}
+		res := make([]string, len(cells))
+		for i := range cells {
+			res[i] = strings.TrimSpace(cells[i].String())
+		}
+		return res
+	}
+	header = split(headerLine)
+	var rows [][]string
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rows = append(rows, split(line))
+	}
+	return header, rows, true
+}
+
+// NewExecFunc returns the exec function running the current executable with the args in the kube context,
+// the --contexts flag is removed from the args. The kubeconfig resolved by the parent, such as the one of
+// the current kbcli context, is passed to the children explicitly if it's not empty.
+func NewExecFunc(args []string, kubeConfig string) (ExecFunc, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	args = append(StripContextsArgs(args), "--color", string(printer.ColorNever))
+	if kubeConfig != "" {
+		args = append(args, "--"+kubeConfigFlag, kubeConfig)
+	}
+	return func(ctx context.Context, kubeContext string, out, errOut io.Writer) error {
+		cmd := exec.CommandContext(ctx, executable, append(args, "--"+contextFlag, kubeContext)...)
+		cmd.Stdout = out
+		cmd.Stderr = errOut
+		return cmd.Run()
+	}, nil
+}
+
+// StripContextsArgs removes the --contexts, --context and --kubeconfig flags from the args
+func StripContextsArgs(args []string) []string {
+	var res []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "--") && (name == ContextsFlag || name == contextFlag || name == kubeConfigFlag) {
+			// skip the value of the flag in the format of --flag value
+			if !hasValue {
+				i++
+			}
+			continue
+		}
+		res = append(res, arg)
+	}
+	return res
+}
+
+// IsReadCommand checks whether the command only reads the resources and can be fanned out to multiple contexts
+func IsReadCommand(cmd *cobra.Command) bool {
+	name := cmd.Name()
+	return name == "list" || strings.HasPrefix(name, "list-") || strings.HasPrefix(name, "describe") || name == "status"
+}
+
+// IsTableOutput checks whether the command outputs a table which can be merged
+func IsTableOutput(cmd *cobra.Command) bool {
+	name := cmd.Name()
+	if name != "list" && !strings.HasPrefix(name, "list-") {
+		return false
+	}
+	f := cmd.Flags().Lookup("output")
+	if f == nil {
+		return true
+	}
+	format := printer.Format(f.Value.String())
+	return format == "" || format.IsHumanReadable()
+}
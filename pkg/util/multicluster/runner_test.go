@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package multicluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func fakeExec(outputs map[string]string) ExecFunc {
+	return func(ctx context.Context, kubeContext string, out, errOut io.Writer) error {
+		o, ok := outputs[kubeContext]
+		if !ok {
+			fmt.Fprintln(errOut, "context not found")
+			return fmt.Errorf("exit status 1")
+		}
+		fmt.Fprint(out, o)
+		return nil
+	}
+}
+
+func TestRunMergeTable(t *testing.T) {
+	outputs := map[string]string{
+		"dev": "NAME   NAMESPACE   STATUS    \nmysql  default     Running   \n",
+		"prod": "NAME       NAMESPACE   STATUS   \n" +
+			"pg         demo        Failed   \n" +
+			"redis                  Running  \n",
+		"test": "No resources found in default namespace.\n",
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	r := NewRunner([]string{"dev", "prod", "test", "missing"}, fakeExec(outputs), out, errOut)
+	r.MergeTable = true
+	err := r.Run()
+	if err == nil || !strings.Contains(err.Error(), "context missing") {
+		t.Errorf("expect the error of context missing, got %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expect 4 lines, got %q", out.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, ",") != "CONTEXT,NAME,NAMESPACE,STATUS" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if fields := strings.Fields(lines[3]); strings.Join(fields, ",") != "prod,redis,Running" {
+		t.Errorf("unexpected row %q", lines[3])
+	}
+	if !strings.Contains(errOut.String(), "[test] No resources found") || !strings.Contains(errOut.String(), "[missing] context not found") {
+		t.Errorf("unexpected error output %q", errOut.String())
+	}
+}
+
+func TestRunWithoutMerge(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	r := NewRunner([]string{"dev", "prod"}, fakeExec(map[string]string{"dev": "Name: mysql\n", "prod": "Name: pg\n"}), out, errOut)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Context: dev\nName: mysql\n\nContext: prod\nName: pg\n"
+	if out.String() != expected {
+		t.Errorf("expect %q, got %q", expected, out.String())
+	}
+}
+
+func TestParseWideTable(t *testing.T) {
+	header, rows, ok := parseTable("NAME     STATUS    AGE\n数据库   Running   1d\nmysql    Failed    2d\n")
+	if !ok || strings.Join(header, ",") != "NAME,STATUS,AGE" {
+		t.Fatalf("unexpected header %q", header)
+	}
+	if strings.Join(rows[0], ",") != "数据库,Running,1d" || strings.Join(rows[1], ",") != "mysql,Failed,2d" {
+		t.Errorf("unexpected rows %q", rows)
+	}
+}
+
+func TestStripContextsArgs(t *testing.T) {
+	args := []string{"cluster", "list", "--contexts", "a,b", "-n", "demo", "--contexts=a", "--context", "c", "-A", "--kubeconfig", "config"}
+	if res := strings.Join(StripContextsArgs(args), " "); res != "cluster list -n demo -A" {
+		t.Errorf("unexpected args %s", res)
+	}
+}
+
+func TestIsReadCommand(t *testing.T) {
+	for name, expected := range map[string]bool{"list": true, "list-ops": true, "describe-backup": true, "create": false, "delete": false} {
+		cmd := &cobra.Command{Use: name}
+		if IsReadCommand(cmd) != expected {
+			t.Errorf("IsReadCommand(%s) should be %v", name, expected)
+		}
+	}
+	cmd := &cobra.Command{Use: "list"}
+	cmd.Flags().StringP("output", "o", "table", "")
+	if !IsTableOutput(cmd) {
+		t.Errorf("table output should be merged")
+	}
+	_ = cmd.Flags().Set("output", "json")
+	if IsTableOutput(cmd) {
+		t.Errorf("json output should not be merged")
+	}
+}
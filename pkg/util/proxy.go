@@ -0,0 +1,93 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// httpsProxy is the proxy of the HTTP clients fetching the charts and checking the versions, it's
+// set by SetHTTPSProxy. The Kubernetes clients don't use it, they use the proxy of the environment
+// variables or the kubeconfig.
+var httpsProxy func(*http.Request) (*url.URL, error)
+
+// SetHTTPSProxy sets the proxy of the transports created by NewHTTPTransport, the empty proxy or
+// noProxy keeps the one of the environment variables.
+func SetHTTPSProxy(proxy, noProxy string) error {
+	if proxy == "" && noProxy == "" {
+		httpsProxy = nil
+		return nil
+	}
+	cfg := httpproxy.FromEnvironment()
+	if proxy != "" {
+		if _, err := url.Parse(proxy); err != nil {
+			return err
+		}
+		cfg.HTTPSProxy = proxy
+	}
+	if noProxy != "" {
+		cfg.NoProxy = noProxy
+	}
+	proxyFunc := cfg.ProxyFunc()
+	httpsProxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	return nil
+}
+
+// HTTPSProxyEnabled checks if the proxy is set by SetHTTPSProxy
+func HTTPSProxyEnabled() bool {
+	return httpsProxy != nil
+}
+
+// NewHTTPTransport returns the transport of the HTTP clients fetching the charts and checking the
+// versions, it uses the proxy set by SetHTTPSProxy, and trusts the CA bundle in caFile besides the
+// system ones if caFile is not empty.
+func NewHTTPTransport(caFile string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the charts are compressed files, they should not be decompressed by the transport
+	transport.DisableCompression = true
+	if httpsProxy != nil {
+		transport.Proxy = httpsProxy
+	}
+	if caFile == "" {
+		return transport, nil
+	}
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificate is found in %s", caFile)
+	}
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return transport, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("https proxy", func() {
+	AfterEach(func() {
+		Expect(SetHTTPSProxy("", "")).Should(Succeed())
+	})
+
+	It("use the proxy for the HTTP transport only", func() {
+		Expect(SetHTTPSProxy("http://proxy.example.com:3128", "charts.internal.example.com")).Should(Succeed())
+		Expect(HTTPSProxyEnabled()).Should(BeTrue())
+		Expect(os.Getenv("HTTPS_PROXY")).Should(BeEmpty())
+
+		transport, err := NewHTTPTransport("")
+		Expect(err).Should(Succeed())
+		req, _ := http.NewRequest(http.MethodGet, "https://apecloud.github.io/helm-charts/index.yaml", nil)
+		proxy, err := transport.Proxy(req)
+		Expect(err).Should(Succeed())
+		Expect(proxy.String()).Should(Equal("http://proxy.example.com:3128"))
+		req, _ = http.NewRequest(http.MethodGet, "https://charts.internal.example.com/index.yaml", nil)
+		proxy, err = transport.Proxy(req)
+		Expect(err).Should(Succeed())
+		Expect(proxy).Should(BeNil())
+
+		By("reset the proxy")
+		Expect(SetHTTPSProxy("", "")).Should(Succeed())
+		Expect(HTTPSProxyEnabled()).Should(BeFalse())
+	})
+
+	It("trust the CA bundle", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+
+		Expect(os.WriteFile(caFile, []byte("invalid"), 0644)).Should(Succeed())
+		_, err := NewHTTPTransport(caFile)
+		Expect(err).Should(MatchError(ContainSubstring("no valid certificate")))
+
+		Expect(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)).Should(Succeed())
+		transport, err := NewHTTPTransport(caFile)
+		Expect(err).Should(Succeed())
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		Expect(err).Should(Succeed())
+		Expect(resp.Body.Close()).Should(Succeed())
+	})
+})
@@ -0,0 +1,298 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/version"
+)
+
+const (
+	// DefaultClientQPS is the default QPS of the REST clients to the API server
+	DefaultClientQPS = 50
+	// DefaultClientBurst is the default burst of the REST clients to the API server
+	DefaultClientBurst = 100
+	// DefaultMaxRetries is the default max number of retries of the failed requests to the API server
+	DefaultMaxRetries = 3
+
+	// MaxRetriesFlag is the global flag of the max number of retries
+	MaxRetriesFlag = "max-retries"
+)
+
+var (
+	maxRetries = DefaultMaxRetries
+
+	// retryBackoff is the exponential backoff between the retries of the failed requests
+	retryBackoff = wait.Backoff{
+		Duration: 200 * time.Millisecond,
+		Factor:   2,
+		Jitter:   0.1,
+		Cap:      5 * time.Second,
+	}
+
+	// userAgentCommand is the subcommand running, which is a part of the User-Agent
+	userAgentCommand string
+
+	// impersonation is the user and groups to impersonate specified by the global flags
+	impersonation rest.ImpersonationConfig
+)
+
+// AddClientFlags adds the flags of the REST clients shared by all commands.
+func AddClientFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&maxRetries, MaxRetriesFlag, DefaultMaxRetries, "The max number of retries with exponential backoff of the requests to the API server failed with 429 or 5xx, 0 disables the retries")
+}
+
+// SetUserAgentCommand sets the subcommand in the User-Agent of the REST clients by the command
+// path, such as `kbcli cluster create`, so that the API server audit logs can tell which
+// subcommand sent the requests.
+func SetUserAgentCommand(commandPath string) {
+	fields := strings.Fields(commandPath)
+	if len(fields) > 0 {
+		// drop the root command
+		fields = fields[1:]
+	}
+	userAgentCommand = strings.Join(fields, ".")
+}
+
+// UserAgent returns the User-Agent of the REST clients in the format of
+// kbcli/<version>/<subcommand> (<os>/<arch>).
+func UserAgent() string {
+	ua := "kbcli/" + version.GetVersion()
+	if userAgentCommand != "" {
+		ua += "/" + userAgentCommand
+	}
+	return fmt.Sprintf("%s (%s/%s)", ua, runtime.GOOS, runtime.GOARCH)
+}
+
+// SetImpersonation records the user and groups to impersonate by the global flags `--as` and
+// `--as-group`, they are applied to the REST clients which are not created from the global flags,
+// such as the clients of the helm actions and the new config flags.
+func SetImpersonation(flags *genericclioptions.ConfigFlags) {
+	impersonation = rest.ImpersonationConfig{}
+	if flags.Impersonate != nil {
+		impersonation.UserName = *flags.Impersonate
+	}
+	if flags.ImpersonateUID != nil {
+		impersonation.UID = *flags.ImpersonateUID
+	}
+	if flags.ImpersonateGroup != nil {
+		impersonation.Groups = append([]string(nil), *flags.ImpersonateGroup...)
+	}
+}
+
+// Impersonation returns the user and groups to impersonate by the global flags
+func Impersonation() rest.ImpersonationConfig {
+	return impersonation
+}
+
+// WrapRESTConfig configures the REST config used by all commands: the warnings are disabled,
+// the QPS and burst are set from the configs `client-qps` and `client-burst` if they are not set,
+// and the requests failed with 429 or 5xx are retried with exponential backoff. The timeout of
+// each request is set by the global flag `--request-timeout`. The User-Agent is set to
+// kbcli/<version>/<subcommand>, and the impersonation of the global flags `--as` and `--as-group`
+// is applied if the config doesn't impersonate anyone. The writes to the KubeBlocks APIs whose
+// versions are switched by the negotiation are rejected.
+func WrapRESTConfig(c *rest.Config) *rest.Config {
+	c.WarningHandler = rest.NoWarnings{}
+	c.UserAgent = UserAgent()
+	if c.Impersonate.UserName == "" && len(c.Impersonate.Groups) == 0 {
+		c.Impersonate = impersonation
+	}
+	if c.QPS == 0 {
+		c.QPS = float32(getClientConfigInt(types.CfgKeyClientQPS, DefaultClientQPS))
+	}
+	if c.Burst == 0 {
+		c.Burst = getClientConfigInt(types.CfgKeyClientBurst, DefaultClientBurst)
+	}
+	if maxRetries > 0 {
+		c.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &retryRoundTripper{delegate: rt, maxRetries: maxRetries, backoff: retryBackoff}
+		})
+	}
+	c.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &writableRoundTripper{delegate: rt}
+	})
+	return c
+}
+
+func getClientConfigInt(key string, defaultValue int) int {
+	value := viper.GetString(key)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		klog.V(1).Infof("invalid %s %s, use the default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return i
+}
+
+// retryRoundTripper retries the requests failed with 429 or 5xx with exponential backoff,
+// the Retry-After header of the response is honored if it is longer than the backoff.
+type retryRoundTripper struct {
+	delegate   http.RoundTripper
+	maxRetries int
+	backoff    wait.Backoff
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := rt.delegate.RoundTrip(req)
+		if attempt >= rt.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// the body of the request can not be sent again
+		var body io.ReadCloser
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			if body, err = req.GetBody(); err != nil {
+				return resp, err
+			}
+		}
+
+		delay := rt.delay(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		klog.V(4).Infof("retry %s %s after %s, attempt %d", req.Method, req.URL, delay, attempt+1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			if body != nil {
+				body.Close()
+			}
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		req = req.Clone(req.Context())
+		if body != nil {
+			req.Body = body
+		}
+	}
+}
+
+func (rt *retryRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// delay returns the backoff before the next attempt
+func (rt *retryRoundTripper) delay(attempt int, resp *http.Response) time.Duration {
+	delay := time.Duration(float64(rt.backoff.Duration) * math.Pow(rt.backoff.Factor, float64(attempt)))
+	if rt.backoff.Cap > 0 && delay > rt.backoff.Cap {
+		delay = rt.backoff.Cap
+	}
+	if rt.backoff.Jitter > 0 {
+		delay = wait.Jitter(delay, rt.backoff.Jitter)
+	}
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second > delay {
+			delay = time.Duration(seconds) * time.Second
+		}
+	}
+	return delay
+}
+
+// writableRoundTripper rejects the writes to the resources whose versions are switched from the compiled
+// ones by the API version negotiation, see types.CheckWritable.
+type writableRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *writableRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if gvr, ok := parseGVRFromPath(req.URL.Path); ok {
+			if err := types.CheckWritable(gvr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+func (rt *writableRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// parseGVRFromPath parses the GVR from the path of the API groups, such as
+// /apis/apps.kubeblocks.io/v1alpha1/namespaces/default/clusters/mycluster.
+func parseGVRFromPath(path string) (schema.GroupVersionResource, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "apis" {
+		return schema.GroupVersionResource{}, false
+	}
+	gvr := schema.GroupVersionResource{Group: parts[1], Version: parts[2], Resource: parts[3]}
+	if parts[3] == "namespaces" && len(parts) >= 6 {
+		gvr.Resource = parts[5]
+	}
+	return gvr, true
+}
+
+// shouldRetry returns true if the request is throttled, or the idempotent request failed with
+// a server error or a connection error. The watch and streaming requests are never retried.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.URL.Query().Get("watch") == "true" || req.Header.Get("Upgrade") != "" {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/version"
+)
+
+var _ = Describe("rest client", func() {
+	var (
+		requests int32
+		failures int32
+		status   int
+		server   *httptest.Server
+		client   *http.Client
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) <= failures {
+				w.WriteHeader(status)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		client = &http.Client{Transport: &retryRoundTripper{
+			delegate:   http.DefaultTransport,
+			maxRetries: 3,
+			backoff:    wait.Backoff{Duration: time.Millisecond, Factor: 2},
+		}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("retries the get request failed with 5xx", func() {
+		failures, status = 2, http.StatusServiceUnavailable
+		resp, err := client.Get(server.URL)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+		Expect(requests).Should(Equal(int32(3)))
+	})
+
+	It("gives up after the max retries", func() {
+		failures, status = 10, http.StatusInternalServerError
+		resp, err := client.Get(server.URL)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusInternalServerError))
+		Expect(requests).Should(Equal(int32(4)))
+	})
+
+	It("retries the throttled post request with the body", func() {
+		failures, status = 1, http.StatusTooManyRequests
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+		Expect(requests).Should(Equal(int32(2)))
+	})
+
+	It("does not retry the post request failed with 5xx", func() {
+		failures, status = 1, http.StatusServiceUnavailable
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusServiceUnavailable))
+		Expect(requests).Should(Equal(int32(1)))
+	})
+
+	It("does not retry the watch request", func() {
+		failures, status = 1, http.StatusServiceUnavailable
+		resp, err := client.Get(server.URL + "?watch=true")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusServiceUnavailable))
+		Expect(requests).Should(Equal(int32(1)))
+	})
+
+	It("stops retrying if the context is done", func() {
+		failures, status = 10, http.StatusServiceUnavailable
+		client.Transport.(*retryRoundTripper).backoff = wait.Backoff{Duration: time.Minute, Factor: 1}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		_, err := client.Do(req)
+		Expect(err).Should(HaveOccurred())
+		Expect(requests).Should(Equal(int32(1)))
+	})
+
+	It("honors the Retry-After header", func() {
+		rt := &retryRoundTripper{backoff: wait.Backoff{Duration: time.Millisecond, Factor: 2}}
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+		Expect(rt.delay(0, resp)).Should(Equal(2 * time.Second))
+		Expect(rt.delay(3, nil)).Should(Equal(8 * time.Millisecond))
+	})
+
+	It("rejects the writes to the switched versions", func() {
+		failures = 0
+		Expect(types.NegotiateAPIVersions(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+			{GroupVersion: "apps.kubeblocks.io/v1", APIResources: []metav1.APIResource{{Name: types.ResourceClusterDefs}}},
+		}}})).Should(Succeed())
+		defer types.SetAPIDiscovery(nil)
+		Expect(types.ClusterDefGVR().Version).Should(Equal("v1"))
+
+		client = &http.Client{Transport: &writableRoundTripper{delegate: http.DefaultTransport}}
+		path := server.URL + "/apis/apps.kubeblocks.io/v1/clusterdefinitions"
+		_, err := client.Post(path, "application/json", strings.NewReader("{}"))
+		Expect(err).Should(MatchError(ContainSubstring("can only read it")))
+		resp, err := client.Get(path + "/test")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+		resp, err = client.Post(server.URL+"/apis/apps.kubeblocks.io/v1alpha1/namespaces/default/clusters", "application/json", strings.NewReader("{}"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+	})
+
+	It("wraps the rest config", func() {
+		c := WrapRESTConfig(&rest.Config{})
+		Expect(c.QPS).Should(Equal(float32(DefaultClientQPS)))
+		Expect(c.Burst).Should(Equal(DefaultClientBurst))
+		Expect(c.WrapTransport).ShouldNot(BeNil())
+
+		viper.Set(types.CfgKeyClientQPS, "20")
+		defer viper.Set(types.CfgKeyClientQPS, "")
+		c = WrapRESTConfig(&rest.Config{Burst: 10})
+		Expect(c.QPS).Should(Equal(float32(20)))
+		Expect(c.Burst).Should(Equal(10))
+	})
+
+	It("sets the User-Agent and impersonation", func() {
+		SetUserAgentCommand("kbcli cluster create")
+		defer SetUserAgentCommand("")
+		Expect(UserAgent()).Should(HavePrefix("kbcli/" + version.GetVersion() + "/cluster.create ("))
+
+		user := "alice"
+		groups := []string{"dba"}
+		SetImpersonation(&genericclioptions.ConfigFlags{Impersonate: &user, ImpersonateGroup: &groups})
+		defer SetImpersonation(&genericclioptions.ConfigFlags{})
+		c := WrapRESTConfig(&rest.Config{})
+		Expect(c.UserAgent).Should(Equal(UserAgent()))
+		Expect(c.Impersonate.UserName).Should(Equal(user))
+		Expect(c.Impersonate.Groups).Should(Equal(groups))
+
+		By("the impersonation of the config is kept")
+		c = WrapRESTConfig(&rest.Config{Impersonate: rest.ImpersonationConfig{UserName: "bob"}})
+		Expect(c.Impersonate.UserName).Should(Equal("bob"))
+	})
+})
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"context"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/util/storage"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// VolumeSnapshotInstallHint is the hint to install the volume snapshot CRDs and controller
+const VolumeSnapshotInstallHint = "install the snapshot controller and CRDs by 'kbcli addon enable snapshot-controller', " +
+	"or refer to https://github.com/kubernetes-csi/external-snapshotter"
+
+// singleNodeProvisioners are the provisioners of block or local storage, whose volumes can only be
+// attached to a single node
+var singleNodeProvisioners = map[string]bool{
+	"ebs.csi.aws.com":                 true,
+	"kubernetes.io/aws-ebs":           true,
+	"pd.csi.storage.gke.io":           true,
+	"kubernetes.io/gce-pd":            true,
+	"disk.csi.azure.com":              true,
+	"kubernetes.io/azure-disk":        true,
+	"diskplugin.csi.alibabacloud.com": true,
+	"rancher.io/local-path":           true,
+	"hostpath.csi.k8s.io":             true,
+	"openebs.io/local":                true,
+	"kubernetes.io/no-provisioner":    true,
+}
+
+// StorageClassAccessModes returns the access modes supported by the storage class, the volumes of block or
+// local storage only support ReadWriteOnce and ReadWriteOncePod, other storage is assumed to support all
+func StorageClassAccessModes(sc *storagev1.StorageClass) []corev1.PersistentVolumeAccessMode {
+	if singleNodeProvisioners[sc.Provisioner] {
+		return []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadWriteOncePod}
+	}
+	return []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadWriteOncePod, corev1.ReadOnlyMany, corev1.ReadWriteMany}
+}
+
+// IsDefaultStorageClass returns true if the annotations mark the storage class as the default one
+func IsDefaultStorageClass(annotations map[string]string) bool {
+	return annotations[storage.IsDefaultStorageClassAnnotation] == "true" ||
+		annotations[storage.BetaIsDefaultStorageClassAnnotation] == "true"
+}
+
+// GetDefaultStorageClass returns the default storage class, or nil if there is no default storage class
+func GetDefaultStorageClass(client kubernetes.Interface) (*storagev1.StorageClass, error) {
+	list, err := client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if IsDefaultStorageClass(list.Items[i].Annotations) {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetVolumeSnapshotClassDrivers returns the CSI drivers having a VolumeSnapshotClass, the volume snapshot of
+// a storage class is supported if its provisioner is one of the drivers. The returned installed is false if
+// the VolumeSnapshotClass CRD is not installed.
+func GetVolumeSnapshotClassDrivers(dynamic dynamic.Interface) (drivers map[string]bool, installed bool, err error) {
+	list, err := dynamic.Resource(types.VolumeSnapshotClassGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	drivers = map[string]bool{}
+	for _, item := range list.Items {
+		vsc := &snapshotv1.VolumeSnapshotClass{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, vsc); err != nil {
+			return nil, true, err
+		}
+		drivers[vsc.Driver] = true
+	}
+	return drivers, true, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// the timezone database is embedded for the systems without it, such as Windows
+	_ "time/tzdata"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// TimezoneFlag is the global flag of the timezone of the time outputs
+	TimezoneFlag = "timezone"
+	// TimeFormatFlag is the global flag of the format of the time outputs
+	TimeFormatFlag = "time-format"
+
+	// TimeFormatAbsolute prints the time such as "Jan 02,2006 15:04 UTC-0700"
+	TimeFormatAbsolute = "absolute"
+	// TimeFormatRelative prints the time relative to now, such as "3h ago"
+	TimeFormatRelative = "relative"
+)
+
+var (
+	// timeLocation is the timezone of the time outputs, the time is printed as it is if it's nil
+	timeLocation *time.Location
+	timeFormat   = TimeFormatAbsolute
+
+	// timeNow returns the current time to format the relative time, it's replaced in tests
+	timeNow = time.Now
+)
+
+// TimeFormats returns the supported formats of the time outputs
+func TimeFormats() []string {
+	return []string{TimeFormatAbsolute, TimeFormatRelative}
+}
+
+// ParseTimezone parses the timezone, it can be UTC, Local or an IANA timezone such as Asia/Shanghai
+func ParseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s, should be UTC, Local or an IANA timezone such as Asia/Shanghai", name)
+	}
+	return loc, nil
+}
+
+type timezoneValue struct {
+	name string
+}
+
+func (v *timezoneValue) String() string { return v.name }
+
+func (v *timezoneValue) Set(s string) error {
+	loc, err := ParseTimezone(s)
+	if err != nil {
+		return err
+	}
+	v.name, timeLocation = s, loc
+	return nil
+}
+
+func (v *timezoneValue) Type() string { return "string" }
+
+type timeFormatValue struct{}
+
+func (v *timeFormatValue) String() string { return timeFormat }
+
+func (v *timeFormatValue) Set(s string) error {
+	if s != TimeFormatAbsolute && s != TimeFormatRelative {
+		return fmt.Errorf("invalid time format %s, should be one of %s", s, strings.Join(TimeFormats(), "|"))
+	}
+	timeFormat = s
+	return nil
+}
+
+func (v *timeFormatValue) Type() string { return "string" }
+
+// AddTimeFlags adds the flags of the time outputs shared by all commands, they are applied to
+// all times formatted by TimeFormat and its variants.
+func AddTimeFlags(flags *pflag.FlagSet) {
+	flags.Var(&timezoneValue{}, TimezoneFlag, "The timezone of the time outputs, such as UTC, Local or Asia/Shanghai, default to the timezone of the time returned by the API server")
+	flags.Var(&timeFormatValue{}, TimeFormatFlag, fmt.Sprintf("The format of the time outputs, one of %s. The relative format prints the time such as 3h ago", strings.Join(TimeFormats(), "|")))
+}
+
+// formatTime formats the time with the layout in the timezone, or relative to now in the relative format
+func formatTime(t time.Time, layout string) string {
+	if timeFormat == TimeFormatRelative {
+		return formatRelativeTime(t, timeNow())
+	}
+	if timeLocation != nil {
+		t = t.In(timeLocation)
+	}
+	return t.Format(layout)
+}
+
+// formatRelativeTime formats the time relative to now, such as "3h ago" or "in 5m" for the future time
+func formatRelativeTime(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		return "in " + duration.HumanDuration(-d)
+	}
+	return duration.HumanDuration(d) + " ago"
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -62,7 +63,6 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	cmdget "k8s.io/kubectl/pkg/cmd/get"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -106,6 +106,15 @@ func GetCliHomeDir() (string, error) {
 	return cliHome, nil
 }
 
+// GetCliConfigFile returns kbcli config file path
+func GetCliConfigFile() (string, error) {
+	cliHome, err := GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cliHome, types.CliConfigFile), nil
+}
+
 // GetCliLogDir returns kbcli log dir
 func GetCliLogDir() (string, error) {
 	cliHome, err := GetCliHomeDir()
@@ -279,16 +288,14 @@ func NewFactory() cmdutil.Factory {
 			panic(err)
 		}
 	})
-	return cmdutil.NewFactory(configFlags)
+	return cmdutil.NewFactory(NewCachedConfigFlags(configFlags))
 }
 
-// NewConfigFlagNoWarnings returns a ConfigFlags that disables warnings.
+// NewConfigFlagNoWarnings returns a ConfigFlags that disables warnings, and configures the
+// rate limit, retries, User-Agent and impersonation of the REST clients by WrapRESTConfig.
 func NewConfigFlagNoWarnings() *genericclioptions.ConfigFlags {
 	configFlags := genericclioptions.NewConfigFlags(true)
-	configFlags.WrapConfigFn = func(c *rest.Config) *rest.Config {
-		c.WarningHandler = rest.NoWarnings{}
-		return c
-	}
+	configFlags.WrapConfigFn = WrapRESTConfig
 	return configFlags
 }
 
@@ -361,7 +368,7 @@ func TimeFormatWithDuration(t *metav1.Time, duration time.Duration) string {
 
 func TimeTimeFormat(t time.Time) string {
 	const layout = "Jan 02,2006 15:04 UTC-0700"
-	return t.Format(layout)
+	return formatTime(t, layout)
 }
 
 func timeLayout(precision time.Duration) string {
@@ -377,7 +384,7 @@ func timeLayout(precision time.Duration) string {
 
 func TimeTimeFormatWithDuration(t time.Time, precision time.Duration) string {
 	layout := timeLayout(precision)
-	return t.Format(layout)
+	return formatTime(t, layout)
 }
 
 func TimeParse(t string, precision time.Duration) (time.Time, error) {
@@ -636,7 +643,11 @@ func ValidateParametersModified2(parameters sets.Set[string], cc appsv1alpha1.Co
 }
 
 func GetIPLocation() (string, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
+	transport, err := NewHTTPTransport("")
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
 	req, err := http.NewRequest("GET", "https://ifconfig.io/country_code", nil)
 	if err != nil {
 		return "", err
@@ -1051,3 +1062,26 @@ func TrimVersionPrefix(version string) string {
 	}
 	return version
 }
+
+// RunParentPersistentPreRun runs the persistent pre-run hook of the ancestors. Cobra only runs the
+// nearest hook, so the commands defining their own hook should call it to keep the hook of the root
+// command, such as applying the current kbcli context.
+func RunParentPersistentPreRun(cmd *cobra.Command, args []string) error {
+	found := false
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.PersistentPreRunE == nil && c.PersistentPreRun == nil {
+			continue
+		}
+		// skip the nearest hook, it is the one calling this function
+		if !found {
+			found = true
+			continue
+		}
+		if c.PersistentPreRunE != nil {
+			return c.PersistentPreRunE(cmd, args)
+		}
+		c.PersistentPreRun(cmd, args)
+		return nil
+	}
+	return nil
+}
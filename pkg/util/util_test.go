@@ -30,6 +30,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +52,26 @@ import (
 )
 
 var _ = Describe("util", func() {
+	It("RunParentPersistentPreRun", func() {
+		var called []string
+		root := &cobra.Command{Use: "root", PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			called = append(called, "root:"+cmd.Name())
+			return nil
+		}}
+		parent := &cobra.Command{Use: "parent"}
+		sub := &cobra.Command{Use: "sub", PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			called = append(called, "sub:"+cmd.Name())
+			Expect(RunParentPersistentPreRun(cmd, args)).Should(Succeed())
+		}}
+		leaf := &cobra.Command{Use: "leaf", Run: func(cmd *cobra.Command, args []string) {}}
+		root.AddCommand(parent)
+		parent.AddCommand(sub)
+		sub.AddCommand(leaf)
+		root.SetArgs([]string{"parent", "sub", "leaf"})
+		Expect(root.Execute()).Should(Succeed())
+		Expect(called).Should(Equal([]string{"sub:leaf", "root:leaf"}))
+	})
+
 	It("Get home dir", func() {
 		home, err := GetCliHomeDir()
 		Expect(len(home) > 0).Should(BeTrue())
@@ -151,6 +173,28 @@ var _ = Describe("util", func() {
 		Expect(TimeFormatWithDuration(&metav1Time, time.Millisecond)).Should(Equal("Jan 04,2023 01:00:00.000 UTC+0000"))
 	})
 
+	It("TimeFormat with timezone and relative format", func() {
+		defer func(loc *time.Location, format string, now func() time.Time) {
+			timeLocation, timeFormat, timeNow = loc, format, now
+		}(timeLocation, timeFormat, timeNow)
+		t, _ := time.Parse(time.RFC3339, "2023-01-04T01:00:00.000Z")
+		metav1Time := metav1.Time{Time: t}
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddTimeFlags(flags)
+		Expect(flags.Parse([]string{"--timezone", "Invalid/Zone"})).Should(HaveOccurred())
+		Expect(flags.Parse([]string{"--time-format", "unknown"})).Should(HaveOccurred())
+		Expect(flags.Parse([]string{"--timezone", "Asia/Shanghai"})).Should(Succeed())
+		Expect(TimeFormat(&metav1Time)).Should(Equal("Jan 04,2023 09:00 UTC+0800"))
+		Expect(TimeFormatWithDuration(&metav1Time, time.Second)).Should(Equal("Jan 04,2023 09:00:00 UTC+0800"))
+
+		timeNow = func() time.Time { return t.Add(3 * time.Hour) }
+		Expect(flags.Parse([]string{"--time-format", TimeFormatRelative})).Should(Succeed())
+		Expect(TimeFormat(&metav1Time)).Should(Equal("3h ago"))
+		Expect(TimeTimeFormat(t.Add(7 * time.Hour))).Should(Equal("in 4h"))
+		Expect(TimeFormat(nil)).Should(BeEmpty())
+	})
+
 	It("CheckEmpty", func() {
 		res := ""
 		Expect(CheckEmpty(res)).Should(Equal(types.None))